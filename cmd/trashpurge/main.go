@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/collections-service/internal/trashpurge"
+)
+
+func main() {
+	lambda.Start(trashpurge.Handler())
+}
@@ -70,6 +70,10 @@ func NewConflictErrorWithCause(userMessage string, cause error) *Error {
 	return NewError(userMessage, cause, http.StatusConflict)
 }
 
+func NewPreconditionFailedError(userMessage string) *Error {
+	return NewError(userMessage, nil, http.StatusPreconditionFailed)
+}
+
 func (e *Error) Error() string {
 	if e.Cause == nil {
 		return e.UserMessage
@@ -0,0 +1,254 @@
+package citation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bibTeXUnescaper removes the escaping and grouping braces that BibTeX exporters add to DOI values.
+var bibTeXUnescaper = strings.NewReplacer(`\_`, `_`, `\%`, `%`, `\&`, `&`, `\#`, `#`, "{", "", "}", "")
+
+// extractBibTeX takes DOIs from the doi field of each entry. Entries without a doi field are returned
+// with their citation key as the Source.
+func extractBibTeX(content string) ([]Reference, error) {
+	var references []Reference
+	remaining := content
+	for {
+		at := strings.IndexByte(remaining, '@')
+		if at < 0 {
+			return references, nil
+		}
+		remaining = remaining[at+1:]
+		open := strings.IndexAny(remaining, "{(")
+		if open < 0 {
+			return references, nil
+		}
+		entryType := strings.ToLower(strings.TrimSpace(remaining[:open]))
+		if !isBibTeXIdentifier(entryType) {
+			// an '@' outside an entry, which BibTeX treats as a comment
+			continue
+		}
+		body, rest, err := bibTeXEntryBody(remaining[open:])
+		if err != nil {
+			return nil, err
+		}
+		remaining = rest
+
+		switch entryType {
+		case "comment", "string", "preamble":
+			continue
+		}
+		key, fields := parseBibTeXEntry(body)
+		doi := strings.TrimSpace(bibTeXUnescaper.Replace(fields["doi"]))
+		if len(doi) == 0 {
+			references = append(references, Reference{Source: key})
+		} else {
+			references = append(references, newReference(doi))
+		}
+	}
+}
+
+func isBibTeXIdentifier(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// bibTeXEntryBody returns the text between the delimiter at the start of s and its matching closing delimiter,
+// along with whatever follows the closing delimiter.
+func bibTeXEntryBody(s string) (body string, rest string, err error) {
+	closer := byte('}')
+	if s[0] == '(' {
+		closer = ')'
+	}
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '{':
+			depth++
+		case depth == 0 && c == closer:
+			return s[1:i], s[i+1:], nil
+		case c == '}':
+			depth--
+		}
+	}
+	return "", "", errors.New("BibTeX entry is missing its closing delimiter")
+}
+
+// parseBibTeXEntry returns the citation key and the fields of an entry body. Field names are lowercased.
+// Concatenated values are not supported; only the first part is returned.
+func parseBibTeXEntry(body string) (key string, fields map[string]string) {
+	fields = map[string]string{}
+	key, remaining, found := strings.Cut(body, ",")
+	key = strings.TrimSpace(key)
+	for found {
+		name, valueAndRest, hasValue := strings.Cut(remaining, "=")
+		if !hasValue {
+			break
+		}
+		var value string
+		value, remaining = bibTeXValue(strings.TrimSpace(valueAndRest))
+		fields[strings.ToLower(strings.TrimSpace(name))] = value
+		_, remaining, found = strings.Cut(remaining, ",")
+	}
+	return key, fields
+}
+
+// bibTeXValue returns the field value at the start of s, without its delimiters, and whatever follows it.
+func bibTeXValue(s string) (value string, rest string) {
+	if len(s) == 0 {
+		return "", ""
+	}
+	switch s[0] {
+	case '{':
+		depth := 0
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return s[1:i], s[i+1:]
+				}
+			}
+		}
+		return s[1:], ""
+	case '"':
+		depth := 0
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '"':
+				if depth == 0 {
+					return s[1:i], s[i+1:]
+				}
+			}
+		}
+		return s[1:], ""
+	default:
+		end := strings.IndexByte(s, ',')
+		if end < 0 {
+			end = len(s)
+		}
+		return strings.TrimSpace(s[:end]), s[end:]
+	}
+}
+
+// bibTeXEscaper escapes characters that are special to BibTeX. Braces are escaped as well, so values
+// are written as literal text.
+var bibTeXEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+)
+
+// bibTeXEntryTypes maps CSL types to BibTeX entry types. Anything else, including datasets, is written as @misc
+// since plain BibTeX has no dataset type.
+var bibTeXEntryTypes = map[string]string{
+	"article-journal":  "article",
+	"book":             "book",
+	"paper-conference": "inproceedings",
+	"report":           "techreport",
+	"thesis":           "phdthesis",
+}
+
+func writeBibTeX(entries []Entry) string {
+	var builder strings.Builder
+	usedKeys := map[string]int{}
+	for i, entry := range entries {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		entryType, ok := bibTeXEntryTypes[entry.itemType()]
+		if !ok {
+			entryType = "misc"
+		}
+		key := bibTeXKey(entry)
+		usedKeys[key]++
+		if n := usedKeys[key]; n > 1 {
+			key = fmt.Sprintf("%s-%d", key, n)
+		}
+		fmt.Fprintf(&builder, "@%s{%s,\n", entryType, key)
+		writeBibTeXField(&builder, "author", bibTeXAuthors(entry.Authors))
+		writeBibTeXField(&builder, "title", bibTeXEscaper.Replace(entry.Title))
+		if year := entry.year(); year > 0 {
+			writeBibTeXField(&builder, "year", strconv.Itoa(year))
+		}
+		if entry.Issued != nil {
+			writeBibTeXField(&builder, "month", strings.ToLower(entry.Issued.Month().String()[:3]))
+		}
+		writeBibTeXField(&builder, "publisher", bibTeXEscaper.Replace(entry.Publisher))
+		writeBibTeXField(&builder, "version", bibTeXEscaper.Replace(entry.Version))
+		// DOIs and URLs are not escaped; BibTeX styles typecheck these with \url or \doi
+		writeBibTeXField(&builder, "doi", entry.DOI)
+		writeBibTeXField(&builder, "url", entry.URL)
+		builder.WriteString("}\n")
+	}
+	return builder.String()
+}
+
+func writeBibTeXField(builder *strings.Builder, name, value string) {
+	if len(value) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "  %s = {%s},\n", name, value)
+}
+
+func bibTeXAuthors(authors []Name) string {
+	var names []string
+	for _, author := range authors {
+		switch {
+		case len(author.Literal) > 0:
+			// the extra braces stop BibTeX from splitting the name into parts
+			names = append(names, fmt.Sprintf("{%s}", bibTeXEscaper.Replace(author.Literal)))
+		case len(author.Given) > 0:
+			names = append(names, fmt.Sprintf("%s, %s", bibTeXEscaper.Replace(author.Family), bibTeXEscaper.Replace(author.Given)))
+		case len(author.Family) > 0:
+			names = append(names, bibTeXEscaper.Replace(author.Family))
+		}
+	}
+	return strings.Join(names, " and ")
+}
+
+// bibTeXKey returns a key of the form family name of first author followed by year, for example smith2024.
+// Only ASCII letters and digits are kept.
+func bibTeXKey(entry Entry) string {
+	var base string
+	if len(entry.Authors) > 0 {
+		base = entry.Authors[0].Family
+		if len(base) == 0 {
+			base = entry.Authors[0].Literal
+		}
+	}
+	var builder strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+		}
+	}
+	if builder.Len() == 0 {
+		builder.WriteString(DatasetType)
+	}
+	if year := entry.year(); year > 0 {
+		builder.WriteString(strconv.Itoa(year))
+	}
+	return builder.String()
+}
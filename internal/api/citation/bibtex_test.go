@@ -0,0 +1,54 @@
+package citation_test
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExtractReferences_BibTeX(t *testing.T) {
+	t.Run("entries", func(t *testing.T) {
+		content := `
+Exported by someone@example.com
+
+@comment{ignored = {10.1234/comment}}
+@string{journal = "Journal"}
+
+@article{smith_2020,
+	title = {A {Title} with {Braces}},
+	author = {Smith, Jane and Doe, John},
+	journal = journal,
+	year = 2020,
+	DOI = {10.1234/first\_part},
+}
+
+@dataset(doe_2021,
+	title = "Quoted {Title}",
+	doi = "https://doi.org/10.1234/second"
+)
+
+@book{no_doi_2019,
+	title = {No DOI},
+	url = {https://example.com}
+}
+
+@misc{bad_doi,
+	doi = {not a doi}
+}
+`
+		references, err := citation.ExtractReferences(citation.BibTeX, content)
+		require.NoError(t, err)
+		assert.Equal(t, []citation.Reference{
+			{Source: "10.1234/first_part", DOI: "10.1234/first_part"},
+			{Source: "https://doi.org/10.1234/second", DOI: "10.1234/second"},
+			{Source: "no_doi_2019"},
+			{Source: "not a doi"},
+		}, references)
+	})
+
+	t.Run("unterminated entry", func(t *testing.T) {
+		_, err := citation.ExtractReferences(citation.BibTeX, "@article{key, doi = {10.1234/abc}")
+		require.Error(t, err)
+	})
+}
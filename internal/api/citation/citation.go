@@ -0,0 +1,119 @@
+// Package citation reads and writes the reference list formats used by citation managers such as Zotero.
+package citation
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// Format is a reference list format.
+type Format string
+
+const (
+	CSV    Format = "csv"
+	BibTeX Format = "bibtex"
+	RIS    Format = "ris"
+)
+
+// importMediaTypes maps the media types accepted for each Format.
+var importMediaTypes = map[string]Format{
+	"text/csv":                            CSV,
+	"application/csv":                     CSV,
+	"application/x-bibtex":                BibTeX,
+	"text/x-bibtex":                       BibTeX,
+	"application/x-research-info-systems": RIS,
+}
+
+// FormatFromContentType returns the Format of the given Content-Type header value, ignoring any parameters.
+// Returns false if the content type is not a supported reference list format.
+func FormatFromContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	format, ok := importMediaTypes[mediaType]
+	return format, ok
+}
+
+// Reference is one entry of a reference list.
+type Reference struct {
+	// Source identifies the entry in the input. It is the DOI as written if the entry has one,
+	// otherwise something like the entry's key or title.
+	Source string
+	// DOI is the normalized DOI of the entry. Empty if the entry has no DOI or if Source is not a DOI.
+	DOI string
+}
+
+func newReference(value string) Reference {
+	reference := Reference{Source: value}
+	if doi, ok := NormalizeDOI(value); ok {
+		reference.DOI = doi
+	}
+	return reference
+}
+
+// doiURLPrefixes are the resolver prefixes that reference managers commonly put in front of DOIs.
+var doiURLPrefixes = []string{
+	"https://doi.org/",
+	"http://doi.org/",
+	"https://dx.doi.org/",
+	"http://dx.doi.org/",
+	"doi.org/",
+	"dx.doi.org/",
+}
+
+// DOIURL returns the canonical https://doi.org URL of doi.
+func DOIURL(doi string) string {
+	return doiURLPrefixes[0] + doi
+}
+
+// NormalizeDOI strips any doi.org URL or "doi:" prefix and surrounding whitespace from value.
+// Returns false if what remains does not look like a DOI.
+func NormalizeDOI(value string) (string, bool) {
+	doi := strings.TrimSpace(value)
+	if trimmed, found := cutPrefixFold(doi, "doi:"); found {
+		doi = strings.TrimSpace(trimmed)
+	} else {
+		for _, prefix := range doiURLPrefixes {
+			if trimmed, found := cutPrefixFold(doi, prefix); found {
+				// URL forms may have escaped characters
+				unescaped, err := url.PathUnescape(trimmed)
+				if err != nil {
+					return "", false
+				}
+				doi = unescaped
+				break
+			}
+		}
+	}
+	if !validate.IsDOI(doi) {
+		return "", false
+	}
+	return doi, true
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+// ExtractReferences returns the entries of content, which is in the given format, in the order they appear.
+func ExtractReferences(format Format, content string) ([]Reference, error) {
+	// Zotero and spreadsheet programs often write a byte order mark at the start of exported files
+	content = strings.TrimPrefix(content, "\ufeff")
+	switch format {
+	case CSV:
+		return extractCSV(content)
+	case BibTeX:
+		return extractBibTeX(content)
+	case RIS:
+		return extractRIS(content)
+	default:
+		return nil, fmt.Errorf("unsupported reference list format: %q", format)
+	}
+}
@@ -0,0 +1,61 @@
+package citation_test
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeDOI(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		ok       bool
+	}{
+		{"10.1234/abc.def", "10.1234/abc.def", true},
+		{"  10.1234/abc  ", "10.1234/abc", true},
+		{"doi:10.1234/abc", "10.1234/abc", true},
+		{"DOI: 10.1234/abc", "10.1234/abc", true},
+		{"https://doi.org/10.1234/abc", "10.1234/abc", true},
+		{"HTTPS://DOI.ORG/10.1234/abc", "10.1234/abc", true},
+		{"http://dx.doi.org/10.1234/abc", "10.1234/abc", true},
+		{"doi.org/10.1234/abc", "10.1234/abc", true},
+		{"https://doi.org/10.1234%2Fa%3Cb%3E", "10.1234/a<b>", true},
+		{"10.1234.5/with/slashes", "10.1234.5/with/slashes", true},
+		{"https://example.com/10.1234/abc", "", false},
+		{"Smith2020", "", false},
+		{"10.1234/", "", false},
+		{"doi:", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			doi, ok := citation.NormalizeDOI(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, doi)
+		})
+	}
+}
+
+func TestFormatFromContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    citation.Format
+		ok          bool
+	}{
+		{"text/csv", citation.CSV, true},
+		{"text/csv; charset=utf-8", citation.CSV, true},
+		{"application/x-bibtex", citation.BibTeX, true},
+		{"text/x-bibtex; charset=UTF-8", citation.BibTeX, true},
+		{"application/x-research-info-systems", citation.RIS, true},
+		{"application/json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			format, ok := citation.FormatFromContentType(tt.contentType)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// cslItem is the subset of CSL JSON that we write.
+// See https://github.com/citation-style-language/schema/blob/master/schemas/input/csl-data.json
+type cslItem struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title,omitempty"`
+	Author    []cslName `json:"author,omitempty"`
+	Issued    *cslDate  `json:"issued,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	DOI       string    `json:"DOI,omitempty"`
+	URL       string    `json:"URL,omitempty"`
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+func newCSLDate(entry Entry) *cslDate {
+	if entry.Issued != nil {
+		return &cslDate{DateParts: [][]int{{entry.Issued.Year(), int(entry.Issued.Month()), entry.Issued.Day()}}}
+	}
+	if entry.Year > 0 {
+		return &cslDate{DateParts: [][]int{{entry.Year}}}
+	}
+	return nil
+}
+
+// writeCSLJSON writes an array of CSL items. Item ids are the DOIs, since CSL processors require ids to be unique
+// and every entry in a collection has a distinct DOI.
+func writeCSLJSON(entries []Entry) (string, error) {
+	items := make([]cslItem, 0, len(entries))
+	for _, entry := range entries {
+		item := cslItem{
+			ID:        entry.DOI,
+			Type:      entry.itemType(),
+			Title:     entry.Title,
+			Issued:    newCSLDate(entry),
+			Publisher: entry.Publisher,
+			Version:   entry.Version,
+			DOI:       entry.DOI,
+			URL:       entry.URL,
+		}
+		for _, author := range entry.Authors {
+			item.Author = append(item.Author, cslName{Family: author.Family, Given: author.Given, Literal: author.Literal})
+		}
+		items = append(items, item)
+	}
+	itemsBytes, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error marshalling CSL JSON: %w", err)
+	}
+	return string(itemsBytes), nil
+}
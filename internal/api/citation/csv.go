@@ -0,0 +1,48 @@
+package citation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// csvDOIColumn is the header of the DOI column in CSV exported by Zotero and most other reference managers.
+const csvDOIColumn = "doi"
+
+// extractCSV takes DOIs from the column with header csvDOIColumn, ignoring case. If there is no such column,
+// there is assumed to be no header row and DOIs are taken from the first column.
+func extractCSV(content string) ([]Reference, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	rows := records
+	column := slices.IndexFunc(records[0], func(header string) bool {
+		return strings.EqualFold(strings.TrimSpace(header), csvDOIColumn)
+	})
+	if column >= 0 {
+		rows = records[1:]
+	} else {
+		column = 0
+	}
+
+	var references []Reference
+	for _, row := range rows {
+		if column >= len(row) {
+			continue
+		}
+		if value := strings.TrimSpace(row[column]); len(value) > 0 {
+			references = append(references, newReference(value))
+		}
+	}
+	return references, nil
+}
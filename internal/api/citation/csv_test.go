@@ -0,0 +1,42 @@
+package citation_test
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExtractReferences_CSV(t *testing.T) {
+	t.Run("DOI column", func(t *testing.T) {
+		// shape of a Zotero CSV export, including its byte order mark
+		content := "\ufeff\"Key\",\"Item Type\",\"Title\",\"DOI\"\n" +
+			"\"ABCD1234\",\"journalArticle\",\"A title, with a comma\",\"10.1234/first\"\n" +
+			"\"EFGH5678\",\"book\",\"No DOI\",\"\"\n" +
+			"\"IJKL9012\",\"dataset\",\"Linked\",\"https://doi.org/10.1234/second\"\n" +
+			"\"MNOP3456\",\"webpage\",\"Bad\",\"not a doi\"\n"
+		references, err := citation.ExtractReferences(citation.CSV, content)
+		require.NoError(t, err)
+		assert.Equal(t, []citation.Reference{
+			{Source: "10.1234/first", DOI: "10.1234/first"},
+			{Source: "https://doi.org/10.1234/second", DOI: "10.1234/second"},
+			{Source: "not a doi"},
+		}, references)
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		content := "10.1234/first\r\ndoi:10.1234/second,ignored\r\n\r\n"
+		references, err := citation.ExtractReferences(citation.CSV, content)
+		require.NoError(t, err)
+		assert.Equal(t, []citation.Reference{
+			{Source: "10.1234/first", DOI: "10.1234/first"},
+			{Source: "doi:10.1234/second", DOI: "10.1234/second"},
+		}, references)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		references, err := citation.ExtractReferences(citation.CSV, "")
+		require.NoError(t, err)
+		assert.Empty(t, references)
+	})
+}
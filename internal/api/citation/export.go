@@ -0,0 +1,99 @@
+package citation
+
+import (
+	"fmt"
+	"time"
+)
+
+// CSLJSON is only supported for export.
+const CSLJSON Format = "csljson"
+
+// exportMediaTypes are the content types of the formats we can export.
+var exportMediaTypes = map[Format]string{
+	BibTeX:  "application/x-bibtex",
+	RIS:     "application/x-research-info-systems",
+	CSLJSON: "application/vnd.citationstyles.csl+json",
+}
+
+var exportFileExtensions = map[Format]string{
+	BibTeX:  ".bib",
+	RIS:     ".ris",
+	CSLJSON: ".json",
+}
+
+// ParseExportFormat returns the Format named by value. Returns false if we cannot export that format.
+func ParseExportFormat(value string) (Format, bool) {
+	format := Format(value)
+	_, ok := exportMediaTypes[format]
+	return format, ok
+}
+
+// ExportFormats returns the formats we can export, for use in error messages.
+func ExportFormats() []Format {
+	return []Format{BibTeX, RIS, CSLJSON}
+}
+
+// MediaType returns the content type of an exported format.
+func (f Format) MediaType() string {
+	return exportMediaTypes[f]
+}
+
+// FileExtension returns the usual file extension, including the leading '.', of an exported format.
+func (f Format) FileExtension() string {
+	return exportFileExtensions[f]
+}
+
+// DatasetType is the CSL type of Pennsieve datasets, and the default for entries with no Type.
+const DatasetType = "dataset"
+
+// Entry is what we know about a work when writing a citation for it.
+type Entry struct {
+	DOI     string
+	Title   string
+	Authors []Name
+	// Type is a CSL item type such as "dataset" or "article-journal".
+	Type      string
+	Publisher string
+	// Version is empty if the work is not versioned.
+	Version string
+	URL     string
+	// Issued is the publication date if we know it. Otherwise, Year is the publication year, or zero if unknown.
+	Issued *time.Time
+	Year   int
+}
+
+func (e Entry) itemType() string {
+	if len(e.Type) == 0 {
+		return DatasetType
+	}
+	return e.Type
+}
+
+func (e Entry) year() int {
+	if e.Issued != nil {
+		return e.Issued.Year()
+	}
+	return e.Year
+}
+
+// Name is an author name. Literal is used for names that could not be split into Given and Family, such
+// as organizations.
+type Name struct {
+	Given   string
+	Family  string
+	Literal string
+}
+
+// Export writes entries in the given format, in order.
+func Export(format Format, entries []Entry) (string, error) {
+	switch format {
+	case BibTeX:
+		return writeBibTeX(entries), nil
+	case RIS:
+		return writeRIS(entries), nil
+	case CSLJSON:
+		return writeCSLJSON(entries)
+	default:
+		return "", fmt.Errorf("unsupported export format: %q", format)
+	}
+}
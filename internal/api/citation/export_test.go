@@ -0,0 +1,183 @@
+package citation_test
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func testEntries() []citation.Entry {
+	issued := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	return []citation.Entry{
+		{
+			DOI:   "10.26275/abc_123",
+			Title: "Mouse {Brain} Atlas & 100% more",
+			Authors: []citation.Name{
+				{Given: "Jane Q.", Family: "Smith"},
+				{Given: "John", Family: "Doe"},
+			},
+			Type:      citation.DatasetType,
+			Publisher: "Pennsieve Discover",
+			Version:   "2",
+			URL:       "https://doi.org/10.26275/abc_123",
+			Issued:    &issued,
+		},
+		{
+			DOI:       "10.1234/article",
+			Title:     "An Article",
+			Authors:   []citation.Name{{Literal: "The Consortium"}},
+			Type:      "article-journal",
+			Publisher: "Publisher",
+			Year:      2019,
+		},
+		{
+			// same first author and year as the first entry
+			DOI:     "10.26275/def",
+			Title:   "Second Atlas",
+			Authors: []citation.Name{{Given: "Jane", Family: "Smith"}},
+			Issued:  &issued,
+		},
+	}
+}
+
+func TestExport_BibTeX(t *testing.T) {
+	exported, err := citation.Export(citation.BibTeX, testEntries())
+	require.NoError(t, err)
+
+	assert.Equal(t, `@misc{smith2024,
+  author = {Smith, Jane Q. and Doe, John},
+  title = {Mouse \{Brain\} Atlas \& 100\% more},
+  year = {2024},
+  month = {mar},
+  publisher = {Pennsieve Discover},
+  version = {2},
+  doi = {10.26275/abc_123},
+  url = {https://doi.org/10.26275/abc_123},
+}
+
+@article{theconsortium2019,
+  author = {{The Consortium}},
+  title = {An Article},
+  year = {2019},
+  publisher = {Publisher},
+  doi = {10.1234/article},
+}
+
+@misc{smith2024-2,
+  author = {Smith, Jane},
+  title = {Second Atlas},
+  year = {2024},
+  month = {mar},
+  doi = {10.26275/def},
+}
+`, exported)
+
+	references, err := citation.ExtractReferences(citation.BibTeX, exported)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.26275/abc_123", "10.1234/article", "10.26275/def"}, referenceDOIs(references))
+}
+
+func TestExport_RIS(t *testing.T) {
+	entries := testEntries()
+	entries[1].Title = "An Article\nwith a line break"
+
+	exported, err := citation.Export(citation.RIS, entries)
+	require.NoError(t, err)
+
+	assert.Equal(t, "TY  - DATA\r\n"+
+		"AU  - Smith, Jane Q.\r\n"+
+		"AU  - Doe, John\r\n"+
+		"TI  - Mouse {Brain} Atlas & 100% more\r\n"+
+		"PY  - 2024\r\n"+
+		"DA  - 2024/03/05\r\n"+
+		"PB  - Pennsieve Discover\r\n"+
+		"ET  - 2\r\n"+
+		"DO  - 10.26275/abc_123\r\n"+
+		"UR  - https://doi.org/10.26275/abc_123\r\n"+
+		"ER  - \r\n"+
+		"TY  - JOUR\r\n"+
+		"AU  - The Consortium\r\n"+
+		"TI  - An Article with a line break\r\n"+
+		"PY  - 2019\r\n"+
+		"PB  - Publisher\r\n"+
+		"DO  - 10.1234/article\r\n"+
+		"ER  - \r\n"+
+		"TY  - DATA\r\n"+
+		"AU  - Smith, Jane\r\n"+
+		"TI  - Second Atlas\r\n"+
+		"PY  - 2024\r\n"+
+		"DA  - 2024/03/05\r\n"+
+		"DO  - 10.26275/def\r\n"+
+		"ER  - \r\n", exported)
+
+	references, err := citation.ExtractReferences(citation.RIS, exported)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.26275/abc_123", "10.1234/article", "10.26275/def"}, referenceDOIs(references))
+}
+
+func TestExport_CSLJSON(t *testing.T) {
+	exported, err := citation.Export(citation.CSLJSON, testEntries())
+	require.NoError(t, err)
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(exported), &items))
+	require.Len(t, items, 3)
+
+	assert.Equal(t, map[string]any{
+		"id":    "10.26275/abc_123",
+		"type":  "dataset",
+		"title": "Mouse {Brain} Atlas & 100% more",
+		"author": []any{
+			map[string]any{"family": "Smith", "given": "Jane Q."},
+			map[string]any{"family": "Doe", "given": "John"},
+		},
+		"issued":    map[string]any{"date-parts": []any{[]any{2024.0, 3.0, 5.0}}},
+		"publisher": "Pennsieve Discover",
+		"version":   "2",
+		"DOI":       "10.26275/abc_123",
+		"URL":       "https://doi.org/10.26275/abc_123",
+	}, items[0])
+
+	assert.Equal(t, "article-journal", items[1]["type"])
+	assert.Equal(t, []any{map[string]any{"literal": "The Consortium"}}, items[1]["author"])
+	assert.Equal(t, map[string]any{"date-parts": []any{[]any{2019.0}}}, items[1]["issued"])
+}
+
+func TestExport_Empty(t *testing.T) {
+	for _, format := range citation.ExportFormats() {
+		t.Run(string(format), func(t *testing.T) {
+			exported, err := citation.Export(format, nil)
+			require.NoError(t, err)
+			if format == citation.CSLJSON {
+				assert.Equal(t, "[]", exported)
+			} else {
+				assert.Empty(t, exported)
+			}
+		})
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for _, format := range citation.ExportFormats() {
+		parsed, ok := citation.ParseExportFormat(string(format))
+		assert.True(t, ok)
+		assert.Equal(t, format, parsed)
+		assert.NotEmpty(t, parsed.MediaType())
+		assert.NotEmpty(t, parsed.FileExtension())
+	}
+	_, ok := citation.ParseExportFormat(string(citation.CSV))
+	assert.False(t, ok)
+	_, ok = citation.ParseExportFormat("")
+	assert.False(t, ok)
+}
+
+func referenceDOIs(references []citation.Reference) []string {
+	var dois []string
+	for _, reference := range references {
+		dois = append(dois, reference.DOI)
+	}
+	return dois
+}
@@ -0,0 +1,132 @@
+package citation
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// risLine matches a tagged RIS line: a two character tag, two spaces, a hyphen, and an optional value.
+var risLine = regexp.MustCompile(`^([A-Z][A-Z0-9])  -(?: (.*))?$`)
+
+// maxRISLineLength is larger than bufio.Scanner's default so that long abstracts do not stop the scan.
+const maxRISLineLength = 1024 * 1024
+
+// extractRIS takes DOIs from the DO tag of each record. Records without a DO tag are returned with their title
+// as the Source, or their position if they have no title either.
+func extractRIS(content string) ([]Reference, error) {
+	var references []Reference
+	var inRecord bool
+	var recordCount int
+	var doi, title string
+	endRecord := func() {
+		switch {
+		case len(doi) > 0:
+			references = append(references, newReference(doi))
+		case len(title) > 0:
+			references = append(references, Reference{Source: title})
+		default:
+			references = append(references, Reference{Source: fmt.Sprintf("record %d", recordCount)})
+		}
+		inRecord = false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(nil, maxRISLineLength)
+	for scanner.Scan() {
+		match := risLine.FindStringSubmatch(strings.TrimRight(scanner.Text(), " \r"))
+		if match == nil {
+			// blank or continuation line
+			continue
+		}
+		tag, value := match[1], strings.TrimSpace(match[2])
+		switch {
+		case tag == "TY":
+			if inRecord {
+				endRecord()
+			}
+			inRecord = true
+			recordCount++
+			doi, title = "", ""
+		case !inRecord:
+			continue
+		case tag == "DO" && len(doi) == 0:
+			doi = value
+		case (tag == "TI" || tag == "T1") && len(title) == 0:
+			title = value
+		case tag == "ER":
+			endRecord()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading RIS: %w", err)
+	}
+	if inRecord {
+		// be forgiving of a missing final ER tag
+		endRecord()
+	}
+	return references, nil
+}
+
+// risTypes maps CSL types to RIS reference types. Anything else is written as GEN.
+var risTypes = map[string]string{
+	DatasetType:        "DATA",
+	"article-journal":  "JOUR",
+	"book":             "BOOK",
+	"chapter":          "CHAP",
+	"paper-conference": "CPAPER",
+	"report":           "RPRT",
+	"thesis":           "THES",
+	"software":         "COMP",
+}
+
+// writeRIS uses CRLF line endings as the RIS specification requires.
+func writeRIS(entries []Entry) string {
+	var builder strings.Builder
+	for _, entry := range entries {
+		risType, ok := risTypes[entry.itemType()]
+		if !ok {
+			risType = "GEN"
+		}
+		writeRISTag(&builder, "TY", risType)
+		for _, author := range entry.Authors {
+			writeRISTag(&builder, "AU", risAuthor(author))
+		}
+		writeRISTag(&builder, "TI", entry.Title)
+		if year := entry.year(); year > 0 {
+			writeRISTag(&builder, "PY", strconv.Itoa(year))
+		}
+		if entry.Issued != nil {
+			writeRISTag(&builder, "DA", entry.Issued.Format("2006/01/02"))
+		}
+		writeRISTag(&builder, "PB", entry.Publisher)
+		// Zotero reads ET as the version of datasets and software
+		writeRISTag(&builder, "ET", entry.Version)
+		writeRISTag(&builder, "DO", entry.DOI)
+		writeRISTag(&builder, "UR", entry.URL)
+		builder.WriteString("ER  - \r\n")
+	}
+	return builder.String()
+}
+
+// writeRISTag skips empty values. Line breaks in value are replaced with spaces since RIS values are a single line.
+func writeRISTag(builder *strings.Builder, tag, value string) {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "%s  - %s\r\n", tag, value)
+}
+
+func risAuthor(author Name) string {
+	switch {
+	case len(author.Literal) > 0:
+		return author.Literal
+	case len(author.Given) > 0:
+		return fmt.Sprintf("%s, %s", author.Family, author.Given)
+	default:
+		return author.Family
+	}
+}
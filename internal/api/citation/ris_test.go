@@ -0,0 +1,40 @@
+package citation_test
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestExtractReferences_RIS(t *testing.T) {
+	content := strings.Join([]string{
+		"TY  - JOUR",
+		"TI  - First",
+		"AU  - Smith, Jane",
+		"DO  - 10.1234/first",
+		"ER  - ",
+		"",
+		"TY  - DATA",
+		"T1  - Second",
+		"UR  - https://example.com",
+		"ER  -",
+		"",
+		"TY  - BOOK",
+		"ER  - ",
+		"TY  - JOUR",
+		"DO  - https://doi.org/10.1234/fourth",
+		"AB  - An abstract that",
+		"continues on the next line",
+	}, "\r\n")
+
+	references, err := citation.ExtractReferences(citation.RIS, content)
+	require.NoError(t, err)
+	assert.Equal(t, []citation.Reference{
+		{Source: "10.1234/first", DOI: "10.1234/first"},
+		{Source: "Second"},
+		{Source: "record 3"},
+		{Source: "https://doi.org/10.1234/fourth", DOI: "10.1234/fourth"},
+	}, references)
+}
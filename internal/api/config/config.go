@@ -3,15 +3,145 @@ package config
 import (
 	"fmt"
 	sharedconfig "github.com/pennsieve/collections-service/internal/shared/config"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"time"
 )
 
 const MaxBannersPerCollection = 4
 const EnvironmentKey = "ENV"
 
+// PublishTimeoutMinutesKey is the env var holding the number of minutes after which an InProgress publish is assumed
+// to have died without finishing. It should be longer than the timeout of the API Lambda.
+const PublishTimeoutMinutesKey = "PUBLISH_TIMEOUT_MINUTES"
+const DefaultPublishTimeoutMinutes = "30"
+const DefaultPublishTimeout = 30 * time.Minute
+
+// PublishJobMaxAttemptsKey is the env var holding the number of times the publish worker tries a publish job before
+// failing it. Attempts that fail because of the collection itself, for example because it contains unpublished DOIs,
+// are not retried.
+const PublishJobMaxAttemptsKey = "PUBLISH_JOB_MAX_ATTEMPTS"
+const DefaultPublishJobMaxAttempts = "3"
+
+// DiscoverCacheTTLMinutesKey is the env var holding the number of minutes that a dataset looked up from Discover
+// is cached. Zero, the default, turns the cache off.
+const DiscoverCacheTTLMinutesKey = "DISCOVER_CACHE_TTL_MINUTES"
+const DefaultDiscoverCacheTTLMinutes = "0"
+
+// DiscoverCacheModeKey is the env var holding the DiscoverCacheMode. Only used if the cache is on.
+const DiscoverCacheModeKey = "DISCOVER_CACHE_MODE"
+
+type DiscoverCacheMode string
+
+// CacheAsideMode means that expired datasets are looked up again in Discover, and a Discover error is returned to
+// the caller.
+const CacheAsideMode DiscoverCacheMode = "cache-aside"
+
+// RefreshMode means that expired datasets are looked up again in Discover like CacheAsideMode, but if Discover cannot
+// be reached, the expired datasets are returned instead of the error, and a warning is logged.
+const RefreshMode DiscoverCacheMode = "refresh"
+
+const DefaultDiscoverCacheMode = CacheAsideMode
+
+// Env vars holding the number of seconds each attempt of a call to a dependency can take before timing out.
+const DiscoverTimeoutSecondsKey = "DISCOVER_TIMEOUT_SECONDS"
+const DefaultDiscoverTimeoutSeconds = "5"
+
+// InternalDiscoverTimeoutSecondsKey is separate from DiscoverTimeoutSecondsKey, since unpublishing takes
+// Discover longer than looking up datasets. The API calls internal Discover while handling requests, so
+// HTTPMaxAttemptsKey attempts of this timeout should fit within the 30 second API Gateway timeout.
+const InternalDiscoverTimeoutSecondsKey = "INTERNAL_DISCOVER_TIMEOUT_SECONDS"
+const DefaultInternalDiscoverTimeoutSeconds = "8"
+
+// PublishWorkerInternalDiscoverTimeoutSecondsKey replaces InternalDiscoverTimeoutSecondsKey in the publish worker,
+// which is not bound by the API Gateway timeout, since starting a publish takes Discover much longer.
+const PublishWorkerInternalDiscoverTimeoutSecondsKey = "PUBLISH_WORKER_INTERNAL_DISCOVER_TIMEOUT_SECONDS"
+const DefaultPublishWorkerInternalDiscoverTimeoutSeconds = "60"
+const DOIServiceTimeoutSecondsKey = "DOI_SERVICE_TIMEOUT_SECONDS"
+const DefaultDOIServiceTimeoutSeconds = "5"
+const DOIResolverTimeoutSecondsKey = "DOI_RESOLVER_TIMEOUT_SECONDS"
+const DefaultDOIResolverTimeoutSeconds = "5"
+
+// HTTPMaxAttemptsKey is the env var holding the most times an idempotent call to a dependency is attempted.
+const HTTPMaxAttemptsKey = "HTTP_MAX_ATTEMPTS"
+const DefaultHTTPMaxAttempts = "3"
+
+// PublishQueueName is the name of the queue that PublishCollection sends publish jobs to.
+const PublishQueueName = "publish"
+
 type Config struct {
 	Environment     string
 	PostgresDB      sharedconfig.PostgresDBConfig
 	PennsieveConfig PennsieveConfig
+	// PublishTimeout is how long a publish can be InProgress before it is considered stale.
+	// A stale publish no longer blocks a new one from starting.
+	PublishTimeout time.Duration
+	// PublishJobMaxAttempts is how many times the publish worker tries a publish job.
+	PublishJobMaxAttempts int
+	// DiscoverCacheTTL is how long a dataset looked up from Discover is cached. Zero means no cache.
+	DiscoverCacheTTL  time.Duration
+	DiscoverCacheMode DiscoverCacheMode
+	HTTPClients       HTTPClientsConfig
+}
+
+// HTTPClientsConfig holds a util.HTTPClientConfig for each dependency called over HTTP.
+type HTTPClientsConfig struct {
+	Discover         util.HTTPClientConfig
+	InternalDiscover util.HTTPClientConfig
+	// PublishWorkerInternalDiscover is used for InternalDiscover by the publish worker.
+	PublishWorkerInternalDiscover util.HTTPClientConfig
+	DOIService                    util.HTTPClientConfig
+	DOIResolver                   util.HTTPClientConfig
+}
+
+// DefaultHTTPClientsConfig returns the HTTPClientsConfig used when none of the HTTP client env vars are set.
+func DefaultHTTPClientsConfig() HTTPClientsConfig {
+	return HTTPClientsConfig{
+		Discover:                      util.DefaultHTTPClientConfig().WithTimeout(5 * time.Second),
+		InternalDiscover:              util.DefaultHTTPClientConfig().WithTimeout(8 * time.Second),
+		PublishWorkerInternalDiscover: util.DefaultHTTPClientConfig().WithTimeout(60 * time.Second),
+		DOIService:                    util.DefaultHTTPClientConfig().WithTimeout(5 * time.Second),
+		DOIResolver:                   util.DefaultHTTPClientConfig().WithTimeout(5 * time.Second),
+	}
+}
+
+func loadHTTPClientsConfig() (HTTPClientsConfig, error) {
+	maxAttempts, err := sharedconfig.NewEnvironmentSettingWithDefault(HTTPMaxAttemptsKey, DefaultHTTPMaxAttempts).GetInt()
+	if err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	if maxAttempts <= 0 {
+		return HTTPClientsConfig{}, fmt.Errorf("'%s' must be positive: %d", HTTPMaxAttemptsKey, maxAttempts)
+	}
+	loadClientConfig := func(timeoutKey, defaultTimeoutSeconds string) (util.HTTPClientConfig, error) {
+		timeoutSeconds, err := sharedconfig.NewEnvironmentSettingWithDefault(timeoutKey, defaultTimeoutSeconds).GetInt()
+		if err != nil {
+			return util.HTTPClientConfig{}, err
+		}
+		if timeoutSeconds <= 0 {
+			return util.HTTPClientConfig{}, fmt.Errorf("'%s' must be positive: %d", timeoutKey, timeoutSeconds)
+		}
+		return util.DefaultHTTPClientConfig().
+			WithTimeout(time.Duration(timeoutSeconds) * time.Second).
+			WithMaxAttempts(maxAttempts), nil
+	}
+
+	var clientsConfig HTTPClientsConfig
+	if clientsConfig.Discover, err = loadClientConfig(DiscoverTimeoutSecondsKey, DefaultDiscoverTimeoutSeconds); err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	if clientsConfig.InternalDiscover, err = loadClientConfig(InternalDiscoverTimeoutSecondsKey, DefaultInternalDiscoverTimeoutSeconds); err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	if clientsConfig.PublishWorkerInternalDiscover, err = loadClientConfig(PublishWorkerInternalDiscoverTimeoutSecondsKey, DefaultPublishWorkerInternalDiscoverTimeoutSeconds); err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	if clientsConfig.DOIService, err = loadClientConfig(DOIServiceTimeoutSecondsKey, DefaultDOIServiceTimeoutSeconds); err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	if clientsConfig.DOIResolver, err = loadClientConfig(DOIResolverTimeoutSecondsKey, DefaultDOIResolverTimeoutSeconds); err != nil {
+		return HTTPClientsConfig{}, err
+	}
+	return clientsConfig, nil
 }
 
 func LoadConfig() (Config, error) {
@@ -27,9 +157,46 @@ func LoadConfig() (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("error loading Pennsieve config: %w", err)
 	}
+	publishTimeoutMinutes, err := sharedconfig.NewEnvironmentSettingWithDefault(PublishTimeoutMinutesKey, DefaultPublishTimeoutMinutes).GetInt()
+	if err != nil {
+		return Config{}, err
+	}
+	if publishTimeoutMinutes <= 0 {
+		return Config{}, fmt.Errorf("'%s' must be positive: %d", PublishTimeoutMinutesKey, publishTimeoutMinutes)
+	}
+	publishJobMaxAttempts, err := sharedconfig.NewEnvironmentSettingWithDefault(PublishJobMaxAttemptsKey, DefaultPublishJobMaxAttempts).GetInt()
+	if err != nil {
+		return Config{}, err
+	}
+	if publishJobMaxAttempts <= 0 {
+		return Config{}, fmt.Errorf("'%s' must be positive: %d", PublishJobMaxAttemptsKey, publishJobMaxAttempts)
+	}
+	discoverCacheTTLMinutes, err := sharedconfig.NewEnvironmentSettingWithDefault(DiscoverCacheTTLMinutesKey, DefaultDiscoverCacheTTLMinutes).GetInt()
+	if err != nil {
+		return Config{}, err
+	}
+	if discoverCacheTTLMinutes < 0 {
+		return Config{}, fmt.Errorf("'%s' cannot be negative: %d", DiscoverCacheTTLMinutesKey, discoverCacheTTLMinutes)
+	}
+	discoverCacheMode, err := sharedconfig.NewEnvironmentSettingWithDefault(DiscoverCacheModeKey, string(DefaultDiscoverCacheMode)).Get()
+	if err != nil {
+		return Config{}, err
+	}
+	if mode := DiscoverCacheMode(discoverCacheMode); mode != CacheAsideMode && mode != RefreshMode {
+		return Config{}, fmt.Errorf("'%s' must be one of %s, %s: %s", DiscoverCacheModeKey, CacheAsideMode, RefreshMode, discoverCacheMode)
+	}
+	httpClientsConfig, err := loadHTTPClientsConfig()
+	if err != nil {
+		return Config{}, err
+	}
 	return Config{
-		Environment:     environment,
-		PostgresDB:      postgresConfig,
-		PennsieveConfig: pennsieveConfig,
+		Environment:           environment,
+		PostgresDB:            postgresConfig,
+		PennsieveConfig:       pennsieveConfig,
+		PublishTimeout:        time.Duration(publishTimeoutMinutes) * time.Minute,
+		PublishJobMaxAttempts: publishJobMaxAttempts,
+		DiscoverCacheTTL:      time.Duration(discoverCacheTTLMinutes) * time.Minute,
+		DiscoverCacheMode:     DiscoverCacheMode(discoverCacheMode),
+		HTTPClients:           httpClientsConfig,
 	}, nil
 }
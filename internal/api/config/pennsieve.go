@@ -13,6 +13,8 @@ type PennsieveConfig struct {
 	CollectionsIDSpace CollectionsPublishingIDSpace
 	DOIServiceURL      string
 	PublishBucket      string
+	// DOIResolverURL is used to look up non-Pennsieve DOIs, for example https://doi.org
+	DOIResolverURL string
 }
 
 type CollectionsPublishingIDSpace struct {
@@ -42,6 +44,12 @@ func WithDOIServiceURL(url string) PennsieveOption {
 	}
 }
 
+func WithDOIResolverURL(url string) PennsieveOption {
+	return func(pennsieveConfig *PennsieveConfig) {
+		pennsieveConfig.DOIResolverURL = url
+	}
+}
+
 func WithDOIPrefix(doiPrefix string) PennsieveOption {
 	return func(pennsieveConfig *PennsieveConfig) {
 		pennsieveConfig.DOIPrefix = doiPrefix
@@ -86,6 +94,13 @@ func (c PennsieveConfig) LoadWithSettings(environmentName string, settings Penns
 		}
 		c.DOIServiceURL = ensureURL(url)
 	}
+	if len(c.DOIResolverURL) == 0 {
+		url, err := settings.DOIResolverURL.Get()
+		if err != nil {
+			return PennsieveConfig{}, err
+		}
+		c.DOIResolverURL = ensureURL(url)
+	}
 	if len(c.DOIPrefix) == 0 {
 		prefix, err := settings.DOIPrefix.Get()
 		if err != nil {
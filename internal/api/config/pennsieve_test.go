@@ -36,6 +36,7 @@ func TestPennsieveConfig_Load(t *testing.T) {
 	assert.Equal(t, expectedCollectionsIDSpaceID, actualConfig.CollectionsIDSpace.ID)
 	assert.Equal(t, expectedCollectionsIDSpaceName, actualConfig.CollectionsIDSpace.Name)
 	assert.Equal(t, expectedPublishBucket, actualConfig.PublishBucket)
+	assert.Equal(t, config.DefaultDOIResolverURL, actualConfig.DOIResolverURL)
 
 	assert.NotNil(t, actualConfig.JWTSecretKey)
 
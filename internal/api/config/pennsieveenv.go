@@ -10,6 +10,9 @@ const PublishBucketKey = "PUBLISH_BUCKET"
 
 const DOIServiceHostKey = "DOI_SERVICE_HOST"
 
+const DOIResolverURLKey = "DOI_RESOLVER_URL"
+const DefaultDOIResolverURL = "https://doi.org"
+
 const ServiceName = "collections-service"
 const JWTSecretKeySSMName = "jwt-secret-key"
 
@@ -20,6 +23,7 @@ type PennsieveSettings struct {
 	CollectionsIDSpaceName sharedconfig.EnvironmentSetting
 	PublishBucket          sharedconfig.EnvironmentSetting
 	DOIServiceHost         sharedconfig.EnvironmentSetting
+	DOIResolverURL         sharedconfig.EnvironmentSetting
 	JWTSecretKey           *sharedconfig.SSMSetting
 }
 
@@ -30,6 +34,7 @@ var DeployedPennsieveSettings = PennsieveSettings{
 	CollectionsIDSpaceName: sharedconfig.NewEnvironmentSetting(CollectionsIDSpaceNameKey),
 	PublishBucket:          sharedconfig.NewEnvironmentSetting(PublishBucketKey),
 	DOIServiceHost:         sharedconfig.NewEnvironmentSetting(DOIServiceHostKey),
+	DOIResolverURL:         sharedconfig.NewEnvironmentSettingWithDefault(DOIResolverURLKey, DefaultDOIResolverURL),
 	JWTSecretKey:           NewJWTSecretKeySetting(),
 }
 
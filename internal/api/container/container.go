@@ -7,10 +7,13 @@ import (
 	"github.com/pennsieve/collections-service/internal/api/config"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/store/discovercache"
 	"github.com/pennsieve/collections-service/internal/api/store/manifests"
 	"github.com/pennsieve/collections-service/internal/api/store/users"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
 	"github.com/pennsieve/collections-service/internal/shared/clients/ssm"
 	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/shared/util"
 	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,6 +26,10 @@ type DependencyContainer interface {
 	PostgresDB() postgres.DB
 
 	Discover() service.Discover
+	// UncachedDiscover returns a Discover that always calls the Discover service,
+	// even when Discover() is configured to cache. Publishing and the publish readiness
+	// check use it so that neither is based on stale dataset information.
+	UncachedDiscover() service.Discover
 	// InternalDiscover returns a Discover service for calling
 	// the internal endpoints. Since these require authz, the setup
 	// is a little different and requires calling SSM. So it is separated
@@ -30,11 +37,15 @@ type DependencyContainer interface {
 	// endpoints will be used.
 	InternalDiscover(ctx context.Context) (service.InternalDiscover, error)
 	DOI(ctx context.Context) (service.DOI, error)
+	ExternalDOI() service.ExternalDOI
 
 	CollectionsStore() collections.Store
 	UsersStore() users.Store
 	ManifestStore() manifests.Store
 
+	// PublishQueue returns the queue of publish jobs run by the publish worker.
+	PublishQueue() queue.Queue
+
 	Logger() *slog.Logger
 	SetLogger(logger *slog.Logger)
 	AddLoggingContext(args ...any)
@@ -43,13 +54,16 @@ type DependencyContainer interface {
 type Container struct {
 	AwsConfig        aws.Config
 	Config           config.Config
-	postgresdb       *postgres.RDSProxy
-	discover         *service.HTTPDiscover
+	postgresdb       *postgres.Pool
+	discover         service.Discover
+	uncachedDiscover *service.HTTPDiscover
 	internalDiscover *service.HTTPInternalDiscover
 	doi              *service.HTTPDOI
+	externalDOI      *service.HTTPExternalDOI
 	collectionsStore *collections.PostgresStore
 	usersStore       *users.PostgresStore
 	manifestStore    *manifests.S3Store
+	publishQueue     *queue.PostgresQueue
 	parameterStore   *ssm.AWSParameterStore
 	logger           *slog.Logger
 }
@@ -93,12 +107,16 @@ func (c *Container) AddLoggingContext(args ...any) {
 func (c *Container) PostgresDB() postgres.DB {
 	if c.postgresdb == nil {
 		pgCfg := c.Config.PostgresDB
-		c.postgresdb = postgres.NewRDSProxy(
+		c.postgresdb = postgres.NewRDSProxyPool(
 			c.AwsConfig,
 			pgCfg.Host,
 			pgCfg.Port,
 			pgCfg.User,
-		)
+		).WithLimits(postgres.PoolLimits{
+			MaxConns:        pgCfg.MaxConns,
+			MaxConnLifetime: pgCfg.MaxConnLifetime,
+			MaxConnIdleTime: pgCfg.MaxConnIdleTime,
+		})
 	}
 
 	return c.postgresdb
@@ -106,16 +124,36 @@ func (c *Container) PostgresDB() postgres.DB {
 
 func (c *Container) Discover() service.Discover {
 	if c.discover == nil {
-		c.discover = service.NewHTTPDiscover(c.Config.PennsieveConfig.DiscoverServiceURL, c.Logger())
+		if c.Config.DiscoverCacheTTL > 0 {
+			cache := discovercache.NewPostgresStore(c.PostgresDB(), c.Config.PostgresDB.CollectionsDatabase, c.Logger())
+			cachingDiscover := service.NewCachingDiscover(c.UncachedDiscover(), cache, c.Config.DiscoverCacheTTL, c.Logger())
+			if c.Config.DiscoverCacheMode == config.RefreshMode {
+				cachingDiscover.WithRefreshMode()
+			}
+			c.discover = cachingDiscover
+		} else {
+			c.discover = c.UncachedDiscover()
+		}
 	}
 	return c.discover
 }
 
+func (c *Container) UncachedDiscover() service.Discover {
+	if c.uncachedDiscover == nil {
+		c.uncachedDiscover = service.NewHTTPDiscover(
+			c.Config.PennsieveConfig.DiscoverServiceURL,
+			util.NewHTTPClient("discover", c.Config.HTTPClients.Discover, c.Logger()),
+			c.Logger())
+	}
+	return c.uncachedDiscover
+}
+
 func (c *Container) CollectionsStore() collections.Store {
 	if c.collectionsStore == nil {
 		c.collectionsStore = collections.NewPostgresStore(c.PostgresDB(),
 			c.Config.PostgresDB.CollectionsDatabase,
-			c.Logger())
+			c.Logger()).
+			WithPublishTimeout(c.Config.PublishTimeout)
 	}
 	return c.collectionsStore
 }
@@ -135,6 +173,16 @@ func (c *Container) ManifestStore() manifests.Store {
 	return c.manifestStore
 }
 
+func (c *Container) PublishQueue() queue.Queue {
+	if c.publishQueue == nil {
+		c.publishQueue = queue.NewPostgresQueue(c.PostgresDB(),
+			c.Config.PostgresDB.CollectionsDatabase,
+			config.PublishQueueName,
+			c.Logger())
+	}
+	return c.publishQueue
+}
+
 // ParameterStore is not part of the interface, since right now it is only used internally by Config.
 func (c *Container) ParameterStore() ssm.ParameterStore {
 	if c.parameterStore == nil {
@@ -163,6 +211,7 @@ func (c *Container) InternalDiscover(ctx context.Context) (service.InternalDisco
 			c.Config.PennsieveConfig.DiscoverServiceURL,
 			jwtSecretKey,
 			c.Config.PennsieveConfig.CollectionsIDSpace.ID,
+			util.NewHTTPClient("internal-discover", c.Config.HTTPClients.InternalDiscover, c.Logger()),
 			c.Logger())
 	}
 	return c.internalDiscover, nil
@@ -178,8 +227,19 @@ func (c *Container) DOI(ctx context.Context) (service.DOI, error) {
 			c.Config.PennsieveConfig.DOIServiceURL,
 			jwtSecretKey,
 			c.Config.PennsieveConfig.CollectionsIDSpace.ID,
+			util.NewHTTPClient("doi-service", c.Config.HTTPClients.DOIService, c.Logger()),
 			c.Logger(),
 		)
 	}
 	return c.doi, nil
 }
+
+func (c *Container) ExternalDOI() service.ExternalDOI {
+	if c.externalDOI == nil {
+		c.externalDOI = service.NewHTTPExternalDOI(
+			c.Config.PennsieveConfig.DOIResolverURL,
+			util.NewHTTPClient("doi-resolver", c.Config.HTTPClients.DOIResolver, c.Logger()),
+			c.Logger())
+	}
+	return c.externalDOI
+}
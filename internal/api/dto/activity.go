@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Actor is the user who changed a collection or took a snapshot of it.
+type Actor struct {
+	UserNodeID string `json:"userNodeId"`
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+}
+
+// CollectionEvent is an entry in the activity log of a collection.
+type CollectionEvent struct {
+	Type string `json:"type"`
+	// User is omitted if the user is unknown or has been deleted.
+	User *Actor `json:"user,omitempty"`
+	// Diff is an object whose keys are the names of changed fields. A field whose value changed maps to an object with
+	// "old" and "new" keys, while other keys identify what was changed, for example the DOIs added.
+	Diff      json.RawMessage `json:"diff"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// GetCollectionActivityResponse represents the response body of GET /{nodeId}/activity
+type GetCollectionActivityResponse struct {
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	TotalCount int               `json:"totalCount"`
+	Events     []CollectionEvent `json:"events"`
+}
+
+func (r GetCollectionActivityResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetCollectionActivityResponse) MarshalJSON() ([]byte, error) {
+	type alias GetCollectionActivityResponse
+	if r.Events == nil {
+		r.Events = []CollectionEvent{}
+	}
+	return json.Marshal(alias(r))
+}
@@ -26,6 +26,15 @@ type CreateCollectionRequest struct {
 // CreateCollectionResponse represents the response body of POST /
 type CreateCollectionResponse CollectionSummary
 
+// CopyCollectionRequest represents the optional request body of POST /collections/{nodeId}/copy
+type CopyCollectionRequest struct {
+	// Name of the new collection. Defaults to the name of the original with a suffix.
+	Name *string `json:"name,omitempty"`
+	// DropTombstones removes DOIs of datasets that are no longer published from the copy
+	// instead of failing the request.
+	DropTombstones bool `json:"dropTombstones"`
+}
+
 // PatchCollectionRequest represents the request body of PATCH /collections/{nodeId}
 type PatchCollectionRequest struct {
 	Name        *string    `json:"name,omitempty"`
@@ -38,6 +47,22 @@ type PatchCollectionRequest struct {
 type PatchDOIs struct {
 	Remove []string `json:"remove,omitempty"`
 	Add    []string `json:"add,omitempty"`
+	// Annotate sets the label or note of DOIs in the collection, including any in Add.
+	Annotate []DOIAnnotation `json:"annotate,omitempty"`
+}
+
+// DOIAnnotation sets the label and note of a DOI in a collection.
+// An omitted label or note is left unchanged and an empty one is removed.
+type DOIAnnotation struct {
+	DOI   string  `json:"doi"`
+	Label *string `json:"label,omitempty"`
+	Note  *string `json:"note,omitempty"`
+}
+
+// PutDOIOrderRequest represents the request body of PUT /collections/{nodeId}/dois/order
+type PutDOIOrderRequest struct {
+	// DOIs is every DOI in the collection, in the new order.
+	DOIs []string `json:"dois"`
 }
 
 func (r CreateCollectionResponse) Marshal() (string, error) {
@@ -48,18 +73,34 @@ func (r CreateCollectionResponse) MarshalJSON() ([]byte, error) {
 	return CollectionSummary(r).MarshalJSON()
 }
 
+// StaleWarning is the Warning header value of responses that include expired cached Discover info
+// because Discover could not be reached.
+const StaleWarning = `110 - "Response is Stale"`
+
 // GetCollectionsResponse represents the response body of GET /
 type GetCollectionsResponse struct {
 	Limit       int                 `json:"limit"`
 	Offset      int                 `json:"offset"`
 	TotalCount  int                 `json:"totalCount"`
 	Collections []CollectionSummary `json:"collections"`
+	// NextCursor is an opaque value that can be passed as the cursor query param to get the next page.
+	// Empty if there are no more pages, or if the request had a non-zero offset.
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Stale is true if some banners came from expired cached Discover info. It is returned as a Warning header.
+	Stale bool `json:"-"`
 }
 
 func (r GetCollectionsResponse) Marshal() (string, error) {
 	return defaultMarshalImpl(r)
 }
 
+func (r GetCollectionsResponse) Headers() map[string]string {
+	if !r.Stale {
+		return nil
+	}
+	return map[string]string{"warning": StaleWarning}
+}
+
 func (r GetCollectionsResponse) MarshalJSON() ([]byte, error) {
 	type GetCollectionsResponseAlias GetCollectionsResponse
 	if r.Collections == nil {
@@ -73,12 +114,32 @@ type GetCollectionResponse struct {
 	CollectionSummary
 	DerivedContributors []PublicContributor `json:"derivedContributors"`
 	Datasets            []Dataset           `json:"datasets"`
+	// Sections group some of Datasets. Datasets still contains every dataset in the collection.
+	Sections []CollectionSection `json:"sections"`
+	// ETag identifies the version of the collection in this response. It is returned as a header rather than in the body.
+	ETag string `json:"-"`
+	// Stale is true if some dataset info came from expired cached Discover info. It is returned as a Warning header.
+	Stale bool `json:"-"`
 }
 
 func (r GetCollectionResponse) Marshal() (string, error) {
 	return defaultMarshalImpl(r)
 }
 
+func (r GetCollectionResponse) Headers() map[string]string {
+	headers := map[string]string{}
+	if len(r.ETag) > 0 {
+		headers["etag"] = r.ETag
+	}
+	if r.Stale {
+		headers["warning"] = StaleWarning
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
 // MarshalJSON is implemented so that nil slices get marshalled as [] instead of null.
 // The subtleties of embedded structs with added fields and JSON marshalling has complicated
 // the implementation
@@ -92,15 +153,20 @@ func (r GetCollectionResponse) MarshalJSON() ([]byte, error) {
 	if r.Datasets == nil {
 		r.Datasets = []Dataset{}
 	}
+	if r.Sections == nil {
+		r.Sections = []CollectionSection{}
+	}
 	type Alias CollectionSummary
 	return json.Marshal(struct {
 		Alias
 		DerivedContributors []PublicContributor `json:"derivedContributors"`
 		Datasets            []Dataset           `json:"datasets"`
+		Sections            []CollectionSection `json:"sections"`
 	}{
 		Alias(r.CollectionSummary),
 		r.DerivedContributors,
 		r.Datasets,
+		r.Sections,
 	})
 }
 
@@ -150,7 +216,12 @@ type Dataset struct {
 	// Data is the info we got from looking up the DOI.
 	// If Source == Pennsieve AND Problem == false, then Data is a PublicDataset.
 	// If Source == Pennsieve AND Problem == true, then Data is a Tombstone.
+	// If Source == External, then Data is an ExternalDataset. If Problem == true, the DOI could not be resolved
+	// and only ExternalDataset.DOI will be populated.
 	Data json.RawMessage `json:"data"`
+	// Label and Note are the curator's annotations of the dataset within the collection.
+	Label string `json:"label,omitempty"`
+	Note  string `json:"note,omitempty"`
 }
 
 func NewPennsieveDataset(publicDataset PublicDataset) (Dataset, error) {
@@ -178,6 +249,39 @@ func NewTombstoneDataset(tombstone Tombstone) (Dataset, error) {
 	}, nil
 }
 
+func NewExternalDataset(externalDataset ExternalDataset) (Dataset, error) {
+	externalBytes, err := json.Marshal(externalDataset)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("error marshalling ExternalDataset %s: %w", externalDataset.DOI, err)
+	}
+	return Dataset{
+		Source: datasource.External,
+		Data:   externalBytes,
+	}, nil
+}
+
+// NewUnresolvedExternalDataset is for external DOIs that the DOI resolver could not find.
+func NewUnresolvedExternalDataset(doi string) (Dataset, error) {
+	dataset, err := NewExternalDataset(ExternalDataset{DOI: doi})
+	if err != nil {
+		return Dataset{}, err
+	}
+	dataset.Problem = true
+	return dataset, nil
+}
+
+// ExternalDataset is what we know about a non-Pennsieve DOI. It is taken from the
+// metadata the DOI's registration agency (DataCite, Crossref, etc.) returns to the DOI resolver.
+type ExternalDataset struct {
+	DOI             string   `json:"doi"`
+	Title           string   `json:"title,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationYear int      `json:"publicationYear,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	Creators        []string `json:"creators,omitempty"`
+}
+
 const CollectionDatasetType = "collection"
 
 // PublicDataset and it's child DTOs are taken from the Discover service so that
@@ -352,6 +456,17 @@ func (r PublishCollectionResponse) Marshal() (string, error) {
 	return defaultMarshalImpl(r)
 }
 
+type ReviseCollectionResponse struct {
+	PublishedDatasetID int           `json:"publishedDatasetId"`
+	PublishedVersion   int           `json:"publishedVersion"`
+	Revision           int           `json:"revision"`
+	Status             PublishStatus `json:"status"`
+}
+
+func (r ReviseCollectionResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
 type UnpublishCollectionResponse struct {
 	PublishedDatasetID int           `json:"publishedDatasetId"`
 	PublishedVersion   int           `json:"publishedVersion"`
@@ -27,17 +27,19 @@ func TestGetCollectionResponse_MarshalJSON(t *testing.T) {
 			dto.GetCollectionResponse{
 				CollectionSummary: apitest.NewCollectionResponse(0),
 			},
-			[]string{`"banners":[]`, `"derivedContributors":[]`, `"datasets":[]`},
-			[]string{`"banners":null`, `"derivedContributors":null`, `"datasets":null`},
+			[]string{`"banners":[]`, `"derivedContributors":[]`, `"datasets":[]`, `"sections":[]`},
+			[]string{`"banners":null`, `"derivedContributors":null`, `"datasets":null`, `"sections":null`},
 		},
 		{"collection contains contributor and dataset",
 			dto.GetCollectionResponse{
 				CollectionSummary:   apitest.NewCollectionResponse(1, *banner),
 				DerivedContributors: []dto.PublicContributor{contributor},
 				Datasets:            []dto.Dataset{{Source: datasource.External, Data: []byte(externalData)}},
+				Sections:            []dto.CollectionSection{{ID: 1, Title: "section title"}},
 			},
 			[]string{
 				fmt.Sprintf(`"banners":[%q]`, *banner),
+				`"sections":[{"id":1,"title":"section title","description":"","dois":[]}]`,
 				`"derivedContributors":[{`,
 				fmt.Sprintf(`"source":%q`, datasource.External),
 				fmt.Sprintf(`"data":%s`, externalData),
@@ -181,3 +183,14 @@ func TestPublicDataset_EmbargoReleaseDate(t *testing.T) {
 	})
 
 }
+
+func TestNewUnresolvedExternalDataset(t *testing.T) {
+	doi := apitest.NewExternalDOI().Value
+	dataset, err := dto.NewUnresolvedExternalDataset(doi)
+	require.NoError(t, err)
+
+	assert.Equal(t, datasource.External, dataset.Source)
+	assert.True(t, dataset.Problem)
+	// Only the DOI should be included
+	assert.JSONEq(t, fmt.Sprintf(`{"doi":%q}`, doi), string(dataset.Data))
+}
@@ -23,3 +23,11 @@ type NoContent struct{}
 func (d NoContent) Marshal() (string, error) {
 	return "", nil
 }
+
+// HeaderDTO is implemented by DTOs whose response headers depend on the response itself,
+// for example a response that is not JSON. The returned headers are added to, and override,
+// the Handler's headers.
+type HeaderDTO interface {
+	DTO
+	Headers() map[string]string
+}
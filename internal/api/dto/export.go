@@ -0,0 +1,22 @@
+package dto
+
+import "fmt"
+
+// CollectionExport is a collection rendered as a citation manager file. It is not JSON,
+// so it supplies its own content-type.
+type CollectionExport struct {
+	ContentType string
+	FileName    string
+	Content     string
+}
+
+func (e CollectionExport) Marshal() (string, error) {
+	return e.Content, nil
+}
+
+func (e CollectionExport) Headers() map[string]string {
+	return map[string]string{
+		"content-type":        e.ContentType,
+		"content-disposition": fmt.Sprintf("attachment; filename=%q", e.FileName),
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+)
+
+// ImportDOIStatus is the outcome of importing one reference into a collection.
+type ImportDOIStatus string
+
+const (
+	// ImportAdded means the DOI was added to the collection.
+	ImportAdded ImportDOIStatus = "added"
+	// ImportAlreadyPresent means the DOI was already in the collection.
+	ImportAlreadyPresent ImportDOIStatus = "alreadyPresent"
+	// ImportUnpublished means the DOI has a Pennsieve prefix, but is not a published Pennsieve dataset.
+	ImportUnpublished ImportDOIStatus = "unpublished"
+	// ImportCollection means the DOI belongs to a published collection. Collections cannot contain collections.
+	ImportCollection ImportDOIStatus = "collection"
+	// ImportUnresolved means the DOI is not a Pennsieve DOI and the DOI resolver does not know about it.
+	ImportUnresolved ImportDOIStatus = "unresolved"
+	// ImportUnparseable means no DOI could be found in the reference.
+	ImportUnparseable ImportDOIStatus = "unparseable"
+)
+
+// ImportDOIResult reports what happened to one reference in the request body of POST /{nodeId}/dois/import
+type ImportDOIResult struct {
+	// Input is the reference's DOI as written in the request, or if it has none, something like the reference's key or title.
+	Input string `json:"input"`
+	// DOI and Source are omitted if Status is ImportUnparseable
+	DOI    string                   `json:"doi,omitempty"`
+	Source datasource.DOIDatasource `json:"source,omitempty"`
+	Status ImportDOIStatus          `json:"status"`
+	Detail string                   `json:"detail,omitempty"`
+}
+
+// ImportDOIsResponse represents the response body of POST /{nodeId}/dois/import
+type ImportDOIsResponse struct {
+	// Results are in the order the references appear in the request, with repeated DOIs reported once.
+	Results []ImportDOIResult `json:"results"`
+}
+
+func (r ImportDOIsResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r ImportDOIsResponse) MarshalJSON() ([]byte, error) {
+	type alias ImportDOIsResponse
+	if r.Results == nil {
+		r.Results = []ImportDOIResult{}
+	}
+	return json.Marshal(alias(r))
+}
@@ -0,0 +1,80 @@
+package dto
+
+import "encoding/json"
+
+// CollectionMember is a user with a role on a collection.
+type CollectionMember struct {
+	UserNodeID string `json:"userNodeId"`
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+	Role       string `json:"role"`
+}
+
+func (m CollectionMember) Marshal() (string, error) {
+	return defaultMarshalImpl(m)
+}
+
+// GetCollectionMembersResponse represents the response body of GET /{nodeId}/members
+type GetCollectionMembersResponse struct {
+	Members []CollectionMember `json:"members"`
+}
+
+func (r GetCollectionMembersResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetCollectionMembersResponse) MarshalJSON() ([]byte, error) {
+	type alias GetCollectionMembersResponse
+	if r.Members == nil {
+		r.Members = []CollectionMember{}
+	}
+	return json.Marshal(alias(r))
+}
+
+// PutCollectionMemberRequest represents the request body of PUT /{nodeId}/members/{userNodeId}
+type PutCollectionMemberRequest struct {
+	Role string `json:"role"`
+}
+
+// TransferOwnershipRequest represents the request body of POST /{nodeId}/transfer-ownership
+type TransferOwnershipRequest struct {
+	UserNodeID string `json:"userNodeId"`
+}
+
+// CollectionGrant is a team or organization with a role on a collection.
+type CollectionGrant struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+}
+
+func (g CollectionGrant) Marshal() (string, error) {
+	return defaultMarshalImpl(g)
+}
+
+// GetCollectionGrantsResponse represents the response body of GET /{nodeId}/grants
+type GetCollectionGrantsResponse struct {
+	Teams         []CollectionGrant `json:"teams"`
+	Organizations []CollectionGrant `json:"organizations"`
+}
+
+func (r GetCollectionGrantsResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetCollectionGrantsResponse) MarshalJSON() ([]byte, error) {
+	type alias GetCollectionGrantsResponse
+	if r.Teams == nil {
+		r.Teams = []CollectionGrant{}
+	}
+	if r.Organizations == nil {
+		r.Organizations = []CollectionGrant{}
+	}
+	return json.Marshal(alias(r))
+}
+
+// PutCollectionGrantRequest represents the request body of PUT /{nodeId}/teams/{teamNodeId}
+// and PUT /{nodeId}/organizations/{organizationNodeId}
+type PutCollectionGrantRequest struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"time"
+)
+
+// PublishJob represents the response body of POST /{nodeId}/publish and GET /{nodeId}/publish/status
+type PublishJob struct {
+	JobID  string               `json:"jobId"`
+	Status publishing.JobStatus `json:"status"`
+	// Step is omitted until the publish worker starts the job.
+	Step     publishing.JobStep `json:"step,omitempty"`
+	Attempts int                `json:"attempts"`
+	// Error is the error of the most recent attempt, if it failed.
+	Error              string    `json:"error,omitempty"`
+	PublishedDatasetID *int      `json:"publishedDatasetId,omitempty"`
+	PublishedVersion   *int      `json:"publishedVersion,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+func (j PublishJob) Marshal() (string, error) {
+	return defaultMarshalImpl(j)
+}
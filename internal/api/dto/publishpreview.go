@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+)
+
+// PublishPreview represents the response body of GET /{nodeId}/publish/preview
+type PublishPreview struct {
+	// DiscoverRequest is the body of the request that would start the publish on Discover.
+	DiscoverRequest json.RawMessage `json:"discoverRequest"`
+	// Manifest is the manifest that would be written to S3. Its DOI, published dataset id, and version are
+	// assigned by Discover when the publish starts, so they are left empty.
+	Manifest publishing.ManifestV5 `json:"manifest"`
+}
+
+func (p PublishPreview) Marshal() (string, error) {
+	return defaultMarshalImpl(p)
+}
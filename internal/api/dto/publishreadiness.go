@@ -0,0 +1,50 @@
+package dto
+
+// ReadinessSeverity says whether a PublishReadinessItem stops the collection from being published.
+type ReadinessSeverity string
+
+const (
+	// ReadinessBlocking means that a publish of the collection would be rejected until the problem is fixed.
+	ReadinessBlocking ReadinessSeverity = "blocking"
+	// ReadinessWarning means that the collection can be published, but the published collection may be missing something.
+	ReadinessWarning ReadinessSeverity = "warning"
+)
+
+// ReadinessCode identifies the kind of problem reported by a PublishReadinessItem, so that clients
+// do not need to parse messages.
+type ReadinessCode string
+
+const (
+	ReadinessMissingDescription ReadinessCode = "missingDescription"
+	ReadinessInvalidLicense     ReadinessCode = "invalidLicense"
+	ReadinessInvalidTags        ReadinessCode = "invalidTags"
+	ReadinessNoDOIs             ReadinessCode = "noDOIs"
+	ReadinessUnpublishedDOIs    ReadinessCode = "unpublishedDOIs"
+	ReadinessUnresolvedDOIs     ReadinessCode = "unresolvedDOIs"
+	ReadinessCollectionDOIs     ReadinessCode = "collectionDOIs"
+	ReadinessPublishInProgress  ReadinessCode = "publishInProgress"
+	ReadinessOwnerMissingORCID  ReadinessCode = "ownerMissingORCID"
+	ReadinessNoBanners          ReadinessCode = "noBanners"
+)
+
+type PublishReadinessItem struct {
+	Code     ReadinessCode     `json:"code"`
+	Severity ReadinessSeverity `json:"severity"`
+	Message  string            `json:"message"`
+	// DOIs are the DOIs in the collection that caused the problem, if any.
+	DOIs []string `json:"dois,omitempty"`
+}
+
+// PublishReadiness represents the response body of GET /{nodeId}/publish/readiness
+type PublishReadiness struct {
+	// Ready is true if Items contains no blocking items.
+	Ready bool                   `json:"ready"`
+	Items []PublishReadinessItem `json:"items"`
+}
+
+func (r PublishReadiness) Marshal() (string, error) {
+	if r.Items == nil {
+		r.Items = []PublishReadinessItem{}
+	}
+	return defaultMarshalImpl(r)
+}
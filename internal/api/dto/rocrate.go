@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+)
+
+// ROCrateContentType is the media type of RO-Crate metadata, which is JSON-LD.
+const ROCrateContentType = "application/ld+json"
+
+// ROCrateResponse is the ro-crate-metadata.json of a collection.
+type ROCrateResponse struct {
+	publishing.ROCrate
+}
+
+func (r ROCrateResponse) Marshal() (string, error) {
+	body, err := r.ROCrate.Marshal()
+	if err != nil {
+		return "", apierrors.NewInternalServerError("error marshalling RO-Crate metadata", err)
+	}
+	return string(body), nil
+}
+
+func (r ROCrateResponse) Headers() map[string]string {
+	return map[string]string{
+		"content-type":        ROCrateContentType,
+		"content-disposition": fmt.Sprintf("attachment; filename=%q", publishing.ROCrateMetadataFileName),
+	}
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "encoding/json"
+
+// CollectionSection is a titled sub-grouping of some of the datasets in a collection.
+type CollectionSection struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// DOIs identify the datasets in the section, in collection order.
+	DOIs []string `json:"dois"`
+}
+
+func (s CollectionSection) Marshal() (string, error) {
+	return defaultMarshalImpl(s)
+}
+
+func (s CollectionSection) MarshalJSON() ([]byte, error) {
+	type alias CollectionSection
+	if s.DOIs == nil {
+		s.DOIs = []string{}
+	}
+	return json.Marshal(alias(s))
+}
+
+// GetCollectionSectionsResponse represents the response body of GET /{nodeId}/sections
+type GetCollectionSectionsResponse struct {
+	Sections []CollectionSection `json:"sections"`
+}
+
+func (r GetCollectionSectionsResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetCollectionSectionsResponse) MarshalJSON() ([]byte, error) {
+	type alias GetCollectionSectionsResponse
+	if r.Sections == nil {
+		r.Sections = []CollectionSection{}
+	}
+	return json.Marshal(alias(r))
+}
+
+// CreateCollectionSectionRequest represents the request body of POST /{nodeId}/sections
+type CreateCollectionSectionRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// DOIs must already be in the collection. They are moved out of any other section.
+	DOIs []string `json:"dois"`
+}
+
+// PatchCollectionSectionRequest represents the request body of PATCH /{nodeId}/sections/{sectionId}
+type PatchCollectionSectionRequest struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	// Position is the zero-based index the section should be moved to.
+	Position *int `json:"position,omitempty"`
+	// DOIs replaces the DOIs in the section. Omit if not being changed.
+	DOIs []string `json:"dois"`
+}
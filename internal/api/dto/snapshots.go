@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"time"
+)
+
+// CreateCollectionSnapshotRequest represents the request body of POST /{nodeId}/versions
+type CreateCollectionSnapshotRequest struct {
+	Name string `json:"name"`
+}
+
+// CollectionSnapshotSummary describes a snapshot without its saved state.
+type CollectionSnapshotSummary struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// CreatedBy is omitted if the user is unknown or has been deleted.
+	CreatedBy *Actor    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CollectionSnapshot represents the response body of POST /{nodeId}/versions and GET /{nodeId}/versions/{snapshotId}
+type CollectionSnapshot struct {
+	CollectionSnapshotSummary
+	State CollectionSnapshotState `json:"state"`
+}
+
+func (s CollectionSnapshot) Marshal() (string, error) {
+	return defaultMarshalImpl(s)
+}
+
+// CollectionSnapshotState is the draft state of a collection saved by a snapshot.
+type CollectionSnapshotState struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	License     string   `json:"license,omitempty"`
+	Tags        []string `json:"tags"`
+	// DOIs are in collection order.
+	DOIs []SnapshotDOI `json:"dois"`
+}
+
+func (s CollectionSnapshotState) MarshalJSON() ([]byte, error) {
+	type alias CollectionSnapshotState
+	if s.Tags == nil {
+		s.Tags = []string{}
+	}
+	if s.DOIs == nil {
+		s.DOIs = []SnapshotDOI{}
+	}
+	return json.Marshal(alias(s))
+}
+
+type SnapshotDOI struct {
+	DOI        string                   `json:"doi"`
+	Datasource datasource.DOIDatasource `json:"source"`
+	Label      string                   `json:"label,omitempty"`
+	Note       string                   `json:"note,omitempty"`
+}
+
+// GetCollectionSnapshotsResponse represents the response body of GET /{nodeId}/versions
+type GetCollectionSnapshotsResponse struct {
+	Limit      int                         `json:"limit"`
+	Offset     int                         `json:"offset"`
+	TotalCount int                         `json:"totalCount"`
+	Snapshots  []CollectionSnapshotSummary `json:"snapshots"`
+}
+
+func (r GetCollectionSnapshotsResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetCollectionSnapshotsResponse) MarshalJSON() ([]byte, error) {
+	type alias GetCollectionSnapshotsResponse
+	if r.Snapshots == nil {
+		r.Snapshots = []CollectionSnapshotSummary{}
+	}
+	return json.Marshal(alias(r))
+}
+
+// FieldChange is the value of a field before and after. Old or New is null if the field has no value.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// DOIAnnotationDiff is a change to the label or note of a DOI that is in both states being compared.
+type DOIAnnotationDiff struct {
+	DOI   string       `json:"doi"`
+	Label *FieldChange `json:"label,omitempty"`
+	Note  *FieldChange `json:"note,omitempty"`
+}
+
+// CollectionSnapshotDiff represents the response body of GET /{nodeId}/versions/{snapshotId}/diff.
+// It lists the changes needed to get from one state to the other. Unchanged fields are omitted.
+type CollectionSnapshotDiff struct {
+	// From is the id of the snapshot the diff starts from.
+	From int64 `json:"from"`
+	// To is the id of the snapshot the diff ends at. Omitted if the diff ends at the current state of the collection.
+	To          *int64       `json:"to,omitempty"`
+	Name        *FieldChange `json:"name,omitempty"`
+	Description *FieldChange `json:"description,omitempty"`
+	License     *FieldChange `json:"license,omitempty"`
+	Tags        *FieldChange `json:"tags,omitempty"`
+	// DOIsAdded and DOIsRemoved are in the order they appear in the To and From states respectively.
+	DOIsAdded     []string            `json:"doisAdded"`
+	DOIsRemoved   []string            `json:"doisRemoved"`
+	DOIsAnnotated []DOIAnnotationDiff `json:"doisAnnotated"`
+	// Reordered is true if the DOIs in both states are in a different order relative to each other.
+	Reordered bool `json:"reordered"`
+}
+
+func (d CollectionSnapshotDiff) Marshal() (string, error) {
+	return defaultMarshalImpl(d)
+}
+
+func (d CollectionSnapshotDiff) MarshalJSON() ([]byte, error) {
+	type alias CollectionSnapshotDiff
+	if d.DOIsAdded == nil {
+		d.DOIsAdded = []string{}
+	}
+	if d.DOIsRemoved == nil {
+		d.DOIsRemoved = []string{}
+	}
+	if d.DOIsAnnotated == nil {
+		d.DOIsAnnotated = []DOIAnnotationDiff{}
+	}
+	return json.Marshal(alias(d))
+}
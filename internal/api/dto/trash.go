@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeletedCollection is a collection in the trash.
+type DeletedCollection struct {
+	NodeID      string    `json:"nodeId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Size        int       `json:"size"`
+	UserRole    string    `json:"userRole"`
+	License     string    `json:"license,omitempty"`
+	Tags        []string  `json:"tags"`
+	DeletedAt   time.Time `json:"deletedAt"`
+}
+
+func (c DeletedCollection) Marshal() (string, error) {
+	return defaultMarshalImpl(c)
+}
+
+func (c DeletedCollection) MarshalJSON() ([]byte, error) {
+	type alias DeletedCollection
+	if c.Tags == nil {
+		c.Tags = []string{}
+	}
+	return json.Marshal(alias(c))
+}
+
+// GetTrashResponse represents the response body of GET /trash
+type GetTrashResponse struct {
+	Limit       int                 `json:"limit"`
+	Offset      int                 `json:"offset"`
+	TotalCount  int                 `json:"totalCount"`
+	Collections []DeletedCollection `json:"collections"`
+}
+
+func (r GetTrashResponse) Marshal() (string, error) {
+	return defaultMarshalImpl(r)
+}
+
+func (r GetTrashResponse) MarshalJSON() ([]byte, error) {
+	type alias GetTrashResponse
+	if r.Collections == nil {
+		r.Collections = []DeletedCollection{}
+	}
+	return json.Marshal(alias(r))
+}
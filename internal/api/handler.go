@@ -19,7 +19,8 @@ import (
 type LambdaHandler func(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error)
 
 func Handler() LambdaHandler {
-	// initializes the dependency container once per Lambda invocation
+	// initializes the dependency container once per cold start, so that warm invocations
+	// reuse its clients and Postgres connection pool
 	depContainer, err := container.NewContainer()
 	if err != nil {
 		log.Fatalf("Failed to initialize dependency container: %v", err)
@@ -79,14 +80,74 @@ func CollectionsServiceAPIHandler(
 			return routes.Handle(ctx, routes.NewGetCollectionRouteHandler(), routeParams)
 		case routes.DeleteCollectionRouteKey:
 			return routes.Handle(ctx, routes.NewDeleteCollectionRouteHandler(), routeParams)
+		case routes.GetTrashRouteKey:
+			return routes.Handle(ctx, routes.NewGetTrashRouteHandler(), routeParams)
+		case routes.RestoreCollectionRouteKey:
+			return routes.Handle(ctx, routes.NewRestoreCollectionRouteHandler(), routeParams)
+		case routes.CopyCollectionRouteKey:
+			return routes.Handle(ctx, routes.NewCopyCollectionRouteHandler(), routeParams)
 		case routes.PatchCollectionRouteKey:
 			return routes.Handle(ctx, routes.NewPatchCollectionRouteHandler(), routeParams)
+		case routes.PutDOIOrderRouteKey:
+			return routes.Handle(ctx, routes.NewPutDOIOrderRouteHandler(), routeParams)
+		case routes.ImportDOIsRouteKey:
+			return routes.Handle(ctx, routes.NewImportDOIsRouteHandler(), routeParams)
+		case routes.ExportCollectionRouteKey:
+			return routes.Handle(ctx, routes.NewExportCollectionRouteHandler(), routeParams)
+		case routes.GetROCrateRouteKey:
+			return routes.Handle(ctx, routes.NewGetROCrateRouteHandler(), routeParams)
+		case routes.GetCollectionActivityRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionActivityRouteHandler(), routeParams)
+		case routes.CreateCollectionSnapshotRouteKey:
+			return routes.Handle(ctx, routes.NewCreateCollectionSnapshotRouteHandler(), routeParams)
+		case routes.GetCollectionSnapshotsRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionSnapshotsRouteHandler(), routeParams)
+		case routes.GetCollectionSnapshotRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionSnapshotRouteHandler(), routeParams)
+		case routes.RestoreCollectionSnapshotRouteKey:
+			return routes.Handle(ctx, routes.NewRestoreCollectionSnapshotRouteHandler(), routeParams)
+		case routes.GetCollectionSnapshotDiffRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionSnapshotDiffRouteHandler(), routeParams)
+		case routes.GetCollectionSectionsRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionSectionsRouteHandler(), routeParams)
+		case routes.CreateCollectionSectionRouteKey:
+			return routes.Handle(ctx, routes.NewCreateCollectionSectionRouteHandler(), routeParams)
+		case routes.PatchCollectionSectionRouteKey:
+			return routes.Handle(ctx, routes.NewPatchCollectionSectionRouteHandler(), routeParams)
+		case routes.DeleteCollectionSectionRouteKey:
+			return routes.Handle(ctx, routes.NewDeleteCollectionSectionRouteHandler(), routeParams)
 		case routes.PublishCollectionRouteKey:
 			return routes.Handle(ctx, routes.NewPublishCollectionRouteHandler(), routeParams)
+		case routes.GetPublishStatusRouteKey:
+			return routes.Handle(ctx, routes.NewGetPublishStatusRouteHandler(), routeParams)
+		case routes.PublishPreviewRouteKey:
+			return routes.Handle(ctx, routes.NewPublishPreviewRouteHandler(), routeParams)
+		case routes.PublishReadinessRouteKey:
+			return routes.Handle(ctx, routes.NewPublishReadinessRouteHandler(), routeParams)
 		case routes.UnpublishCollectionRouteKey:
 			return routes.Handle(ctx, routes.NewUnpublishCollectionRouteHandler(), routeParams)
+		case routes.ReviseCollectionRouteKey:
+			return routes.Handle(ctx, routes.NewReviseCollectionRouteHandler(), routeParams)
 		case routes.GetDOIRouteKey:
 			return routes.Handle(ctx, routes.NewGetDOIRouteHandler(), routeParams)
+		case routes.GetCollectionMembersRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionMembersRouteHandler(), routeParams)
+		case routes.PutCollectionMemberRouteKey:
+			return routes.Handle(ctx, routes.NewPutCollectionMemberRouteHandler(), routeParams)
+		case routes.DeleteCollectionMemberRouteKey:
+			return routes.Handle(ctx, routes.NewDeleteCollectionMemberRouteHandler(), routeParams)
+		case routes.TransferOwnershipRouteKey:
+			return routes.Handle(ctx, routes.NewTransferOwnershipRouteHandler(), routeParams)
+		case routes.GetCollectionGrantsRouteKey:
+			return routes.Handle(ctx, routes.NewGetCollectionGrantsRouteHandler(), routeParams)
+		case routes.PutCollectionTeamRouteKey:
+			return routes.Handle(ctx, routes.NewPutCollectionTeamRouteHandler(), routeParams)
+		case routes.DeleteCollectionTeamRouteKey:
+			return routes.Handle(ctx, routes.NewDeleteCollectionTeamRouteHandler(), routeParams)
+		case routes.PutCollectionOrganizationRouteKey:
+			return routes.Handle(ctx, routes.NewPutCollectionOrganizationRouteHandler(), routeParams)
+		case routes.DeleteCollectionOrganizationRouteKey:
+			return routes.Handle(ctx, routes.NewDeleteCollectionOrganizationRouteHandler(), routeParams)
 		default:
 			routeNotFound := apierrors.NewError(fmt.Sprintf("route [%s] not found", routeKey), nil, http.StatusNotFound)
 			routeNotFound.LogError(logger)
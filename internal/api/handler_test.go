@@ -11,6 +11,7 @@ import (
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/api/store/manifests"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
@@ -20,9 +21,12 @@ import (
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAPILambdaHandler(t *testing.T) {
@@ -32,7 +36,8 @@ func TestAPILambdaHandler(t *testing.T) {
 	}{
 		{"default not found response", testDefaultNotFound},
 		{"no claims", testNoClaims},
-		{"create collection bad request: external DOIs", testCreateCollectionExternalDOIs},
+		{"create collection bad request: unresolved external DOIs", testCreateCollectionUnresolvedExternalDOIs},
+		{"create collection bad request: invalid DOIs", testCreateCollectionInvalidDOIs},
 		{"create collection bad request: empty name", testCreateCollectionEmptyName},
 		{"create collection bad request: name too long", testCreateCollectionNameTooLong},
 		{"create collection bad request: description too long", testCreateCollectionDescriptionTooLong},
@@ -46,7 +51,27 @@ func TestAPILambdaHandler(t *testing.T) {
 		{"update collection", testUpdateCollection},
 		{"publish collection", testPublishCollection},
 		{"unpublish collection", testUnpublishCollection},
+		{"revise collection", testReviseCollection},
 		{"get doi", testGetDOI},
+		{"get collection members", testGetCollectionMembers},
+		{"put collection member", testPutCollectionMember},
+		{"delete collection member", testDeleteCollectionMember},
+		{"transfer ownership", testTransferOwnership},
+		{"get collection grants", testGetCollectionGrants},
+		{"copy collection", testCopyCollection},
+		{"get trash", testGetTrash},
+		{"restore collection", testRestoreCollection},
+		{"put DOI order", testPutDOIOrder},
+		{"create collection section", testCreateCollectionSection},
+		{"delete collection section", testDeleteCollectionSection},
+		{"import DOIs", testImportDOIs},
+		{"export collection", testExportCollection},
+		{"get RO-Crate", testGetROCrate},
+		{"get collection activity", testGetCollectionActivity},
+		{"get publish status", testGetPublishStatus},
+		{"preview publish", testPublishPreview},
+		{"publish readiness", testPublishReadiness},
+		{"get collection snapshot", testGetCollectionSnapshot},
 	}
 	for _, tt := range tests {
 		t.Run(tt.scenario, func(t *testing.T) {
@@ -81,13 +106,51 @@ func testNoClaims(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
 }
 
-func testCreateCollectionExternalDOIs(t *testing.T) {
+func testCreateCollectionUnresolvedExternalDOIs(t *testing.T) {
+	expectedPennsieveDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedPennsieveDatasets.NewPublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	resolved := expectedExternalDatasets.NewResolved()
+	unresolved := expectedExternalDatasets.NewUnresolved()
+
+	createCollectionRequest := dto.CreateCollectionRequest{
+		Name:        uuid.NewString(),
+		Description: uuid.NewString(),
+		DOIs:        []string{resolved.DOI, published.DOI, unresolved},
+	}
+
+	handler := CollectionsServiceAPIHandler(
+		apitest.NewTestContainer().
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedPennsieveDatasets.GetDatasetsByDOIFunc(t))).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))),
+		apitest.NewConfigBuilder().
+			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+			Build())
+
+	req := apitest.NewAPIGatewayRequestBuilder(routes.CreateCollectionRouteKey).
+		WithDefaultClaims(userstest.SeedUser1).
+		WithBody(t, createCollectionRequest).
+		Build()
+
+	response, err := handler(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, response.Body, resolved.DOI)
+	assert.NotContains(t, response.Body, published.DOI)
+	assert.Contains(t, response.Body, unresolved)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func testCreateCollectionInvalidDOIs(t *testing.T) {
+	invalidDOI := uuid.NewString()
 	createCollectionRequest := dto.CreateCollectionRequest{
 		Name:        uuid.NewString(),
 		Description: uuid.NewString(),
-		DOIs:        []string{apitest.NewExternalDOI().Value, apitest.NewPennsieveDOI().Value, apitest.NewExternalDOI().Value},
+		DOIs:        []string{apitest.NewExternalDOI().Value, invalidDOI},
 	}
 
+	// No ExternalDOI in the container since we should fail before trying to resolve anything
 	handler := CollectionsServiceAPIHandler(
 		apitest.NewTestContainer(),
 		apitest.NewConfigBuilder().
@@ -102,9 +165,8 @@ func testCreateCollectionExternalDOIs(t *testing.T) {
 	response, err := handler(context.Background(), req)
 
 	assert.NoError(t, err)
-	assert.Contains(t, response.Body, createCollectionRequest.DOIs[0])
-	assert.NotContains(t, response.Body, createCollectionRequest.DOIs[1])
-	assert.Contains(t, response.Body, createCollectionRequest.DOIs[2])
+	assert.NotContains(t, response.Body, createCollectionRequest.DOIs[0])
+	assert.Contains(t, response.Body, invalidDOI)
 	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
 }
 
@@ -344,7 +406,7 @@ func testGetCollections(t *testing.T) {
 	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithPublicDatasets(expectedDataset)
 
 	mockCollectionStore := mocks.NewCollectionsStore().
-		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error) {
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, _ collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
 			require.Equal(t, callingUser.ID, userID)
 			require.Equal(t, routes.DefaultGetCollectionsLimit, limit)
 			require.Equal(t, expectedOffset, offset)
@@ -463,7 +525,7 @@ func testDeleteCollection(t *testing.T) {
 
 	mockCollectionStore := mocks.NewCollectionsStore().
 		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
-		WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64) error {
+		WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64, _ int64, _ *int64) error {
 			require.Equal(t, *expectedCollection.ID, collectionID)
 			return nil
 		})
@@ -564,46 +626,21 @@ func testPublishCollection(t *testing.T) {
 		WithRandomLicense().
 		WithNTags(2)
 
+	expectedJob := collectionstest.NewQueuedPublishJob(*expectedCollection.ID, *expectedCollection.NodeID, callingUser.ID, callingUser.NodeID)
 	mockCollectionStore := mocks.NewCollectionsStore().
 		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
-		WithUpdateCollectionFunc(expectedCollection.UpdateCollectionFunc(t)).
 		WithStartPublishFunc(expectedCollection.StartPublishFunc(t, callingUser.ID, publishing.PublicationType)).
-		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.CompletedStatus))
-
-	mockDiscover := mocks.NewDiscover().
-		WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))
-
-	mockUserStore := mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))
-
-	expectedPublishedDatasetID := 12
-	expectedPublishedVersion := 3
-	expectedDiscoverPublishStatus := dto.PublishInProgress
-	mockPublishDOICollectionResponse := service.PublishDOICollectionResponse{
-		PublishedDatasetID: expectedPublishedDatasetID,
-		PublishedVersion:   expectedPublishedVersion,
-		Status:             expectedDiscoverPublishStatus,
-	}
-	mockFinalizeDOICollectionResponse := service.FinalizeDOICollectionPublishResponse{Status: dto.PublishSucceeded}
-
-	mockInternalDiscover := mocks.NewInternalDiscover().
-		WithPublishCollectionFunc(expectedCollection.PublishCollectionFunc(t, mockPublishDOICollectionResponse)).
-		WithFinalizeCollectionPublishFunc(
-			expectedCollection.FinalizeCollectionPublishFunc(t,
-				mockFinalizeDOICollectionResponse,
-				apitest.VerifyFinalizeDOICollectionRequest(expectedPublishedDatasetID, expectedPublishedVersion)),
-		)
+		WithCreatePublishJobFunc(func(_ context.Context, _ int64, _ int64) (collections.PublishJob, error) {
+			return expectedJob, nil
+		})
 
-	mockManifestStore := mocks.NewManifestStore().WithSaveManifestFunc(func(_ context.Context, _ string, _ publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
-		return manifests.SaveManifestResponse{S3VersionID: uuid.NewString()}, nil
-	})
+	publishQueue := queue.NewMemoryQueue()
 
+	// the rest of publishing is left to the publish worker, so Discover and S3 are not needed here
 	handler := CollectionsServiceAPIHandler(
 		apitest.NewTestContainer().
 			WithCollectionsStore(mockCollectionStore).
-			WithDiscover(mockDiscover).
-			WithUsersStore(mockUserStore).
-			WithInternalDiscover(mockInternalDiscover).
-			WithManifestStore(mockManifestStore),
+			WithPublishQueue(publishQueue),
 		apitest.NewConfigBuilder().
 			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
 			Build(),
@@ -616,14 +653,14 @@ func testPublishCollection(t *testing.T) {
 	response, err := handler(context.Background(), req)
 	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
 
-	var responseDTO dto.PublishCollectionResponse
+	var responseDTO dto.PublishJob
 	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
 
-	assert.Equal(t, expectedPublishedDatasetID, responseDTO.PublishedDatasetID)
-	assert.Equal(t, expectedPublishedVersion, responseDTO.PublishedVersion)
-	assert.Equal(t, mockFinalizeDOICollectionResponse.Status, responseDTO.Status)
+	assert.Equal(t, expectedJob.ID, responseDTO.JobID)
+	assert.Equal(t, publishing.JobQueuedStatus, responseDTO.Status)
+	assert.Equal(t, 1, publishQueue.Len())
 }
 
 func testUnpublishCollection(t *testing.T) {
@@ -728,3 +765,879 @@ func testGetDOI(t *testing.T) {
 	assert.Equal(t, doiResponse, responseDTO)
 
 }
+
+func testGetCollectionMembers(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithRandomID().WithUser(callingUser.ID, pgdb.Read)
+
+	ownerFirstName, ownerLastName := uuid.NewString(), uuid.NewString()
+	owner := collections.CollectionMember{
+		UserID:     rand.Int64(),
+		UserNodeID: userstest.NewTestUser().NodeID,
+		FirstName:  &ownerFirstName,
+		LastName:   &ownerLastName,
+		Role:       role.Owner,
+	}
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetCollectionMembersFunc(func(_ context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return []collections.CollectionMember{
+						owner,
+						{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: role.Viewer},
+					}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetCollectionMembersRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionMembersResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+
+	assert.Equal(t, []dto.CollectionMember{
+		{UserNodeID: owner.UserNodeID, FirstName: *owner.FirstName, LastName: *owner.LastName, Role: role.Owner.String()},
+		{UserNodeID: callingUser.NodeID, Role: role.Viewer.String()},
+	}, responseDTO.Members)
+}
+
+func testPutCollectionMember(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	memberNodeID := userstest.NewTestUser().NodeID
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithRandomID().WithUser(callingUser.ID, pgdb.Owner)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetCollectionMembersFunc(func(_ context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return []collections.CollectionMember{{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: role.Owner}}, nil
+				}).
+				WithPutCollectionMemberFunc(func(_ context.Context, collectionID int64, _ int64, userNodeID string, memberRole role.Role) (collections.CollectionMember, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, memberNodeID, userNodeID)
+					require.Equal(t, role.Editor, memberRole)
+					return collections.CollectionMember{UserID: rand.Int64(), UserNodeID: userNodeID, Role: memberRole}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.PutCollectionMemberRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithPathParam(routes.UserNodeIDPathParamKey, memberNodeID).
+		WithDefaultClaims(callingUser).
+		WithBody(t, dto.PutCollectionMemberRequest{Role: "editor"}).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.CollectionMember
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, memberNodeID, responseDTO.UserNodeID)
+	assert.Equal(t, role.Editor.String(), responseDTO.Role)
+}
+
+func testDeleteCollectionMember(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithRandomID().WithUser(callingUser.ID, pgdb.Read)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithDeleteCollectionMemberFunc(func(_ context.Context, collectionID int64, _ int64, userNodeID string) error {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, callingUser.NodeID, userNodeID)
+					return nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.DeleteCollectionMemberRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithPathParam(routes.UserNodeIDPathParamKey, callingUser.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Empty(t, response.Body)
+}
+
+func testTransferOwnership(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	newOwner := userstest.SeedUser2
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithRandomID().WithUser(callingUser.ID, pgdb.Owner)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithTransferOwnershipFunc(func(_ context.Context, collectionID int64, currentOwnerID int64, newOwnerNodeID string) error {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, callingUser.ID, currentOwnerID)
+					require.Equal(t, newOwner.NodeID, newOwnerNodeID)
+					return nil
+				}).
+				WithGetCollectionMembersFunc(func(_ context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return []collections.CollectionMember{
+						{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: role.Manager},
+						{UserID: newOwner.ID, UserNodeID: newOwner.NodeID, Role: role.Owner},
+					}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.TransferOwnershipRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		WithBody(t, dto.TransferOwnershipRequest{UserNodeID: newOwner.NodeID}).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionMembersResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, []dto.CollectionMember{
+		{UserNodeID: callingUser.NodeID, Role: role.Manager.String()},
+		{UserNodeID: newOwner.NodeID, Role: role.Owner.String()},
+	}, responseDTO.Members)
+}
+
+func testGetCollectionGrants(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithRandomID().WithUser(callingUser.ID, pgdb.Read)
+
+	team := collections.CollectionGrant{
+		GranteeType:   collections.TeamGrantee,
+		GranteeID:     rand.Int64(),
+		GranteeNodeID: uuid.NewString(),
+		Name:          uuid.NewString(),
+		Role:          role.Editor,
+	}
+	organization := collections.CollectionGrant{
+		GranteeType:   collections.OrganizationGrantee,
+		GranteeID:     rand.Int64(),
+		GranteeNodeID: uuid.NewString(),
+		Name:          uuid.NewString(),
+		Role:          role.Viewer,
+	}
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetCollectionGrantsFunc(func(_ context.Context, collectionID int64) ([]collections.CollectionGrant, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return []collections.CollectionGrant{organization, team}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetCollectionGrantsRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionGrantsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+
+	assert.Equal(t, []dto.CollectionGrant{
+		{NodeID: team.GranteeNodeID, Name: team.Name, Role: role.Editor.String()},
+	}, responseDTO.Teams)
+	assert.Equal(t, []dto.CollectionGrant{
+		{NodeID: organization.GranteeNodeID, Name: organization.Name, Role: role.Viewer.String()},
+	}, responseDTO.Organizations)
+}
+
+func testCopyCollection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Guest).
+		WithNTags(1).
+		WithPublicDatasets(published)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithCreateCollectionsFunc(func(_ context.Context, request collections.CreateCollectionRequest) (collections.CreateCollectionResponse, error) {
+					require.NotEqual(t, *collection.NodeID, request.NodeID)
+					require.Equal(t, callingUser.ID, request.UserID)
+					require.Equal(t, collection.Name+routes.CopyNameSuffix, request.Name)
+					require.Equal(t, collection.Tags, request.Tags)
+					require.Equal(t, collection.DOIs.AsDOIs(), collections.DOIs(request.DOIs))
+					return collections.CreateCollectionResponse{ID: rand.Int64(), CreatorRole: role.Owner}, nil
+				}),
+		).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.CopyCollectionRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, response.StatusCode)
+
+	var responseDTO dto.CreateCollectionResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, collection.Name+routes.CopyNameSuffix, responseDTO.Name)
+	assert.Equal(t, []string{*published.Banner}, responseDTO.Banners)
+	assert.Equal(t, 1, responseDTO.Size)
+	assert.Equal(t, role.Owner.String(), responseDTO.UserRole)
+}
+
+func testGetTrash(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	deleted := collections.DeletedCollection{
+		CollectionBase: collections.CollectionBase{
+			ID:       rand.Int64(),
+			NodeID:   uuid.NewString(),
+			Name:     uuid.NewString(),
+			UserRole: role.Owner,
+		},
+		DeletedAt: time.Now().UTC(),
+	}
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetDeletedCollectionsFunc(func(_ context.Context, userID int64, _ role.Role, limit int, offset int) (collections.GetDeletedCollectionsResponse, error) {
+					require.Equal(t, callingUser.ID, userID)
+					return collections.GetDeletedCollectionsResponse{
+						Limit:       limit,
+						Offset:      offset,
+						Collections: []collections.DeletedCollection{deleted},
+						TotalCount:  1,
+					}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetTrashRouteKey).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetTrashResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, 1, responseDTO.TotalCount)
+	require.Len(t, responseDTO.Collections, 1)
+	assert.Equal(t, deleted.NodeID, responseDTO.Collections[0].NodeID)
+}
+
+func testRestoreCollection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	deleted := collections.DeletedCollection{
+		CollectionBase: collections.CollectionBase{
+			ID:       rand.Int64(),
+			NodeID:   uuid.NewString(),
+			UserRole: role.Owner,
+		},
+		DeletedAt: time.Now().UTC(),
+	}
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetDeletedCollectionFunc(func(_ context.Context, _ int64, nodeID string) (collections.DeletedCollection, error) {
+					require.Equal(t, deleted.NodeID, nodeID)
+					return deleted, nil
+				}).
+				WithRestoreCollectionFunc(func(_ context.Context, collectionID int64, _ int64) error {
+					require.Equal(t, deleted.ID, collectionID)
+					return nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.RestoreCollectionRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, deleted.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Empty(t, response.Body)
+}
+
+func testPutDOIOrder(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published1 := expectedDatasets.NewPublished()
+	published2 := expectedDatasets.NewPublished()
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Write).
+		WithPublicDatasets(published1, published2)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithReorderDOIsFunc(func(_ context.Context, userID int64, collectionID int64, dois []string) (collections.GetCollectionResponse, error) {
+					require.Equal(t, callingUser.ID, userID)
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, []string{published2.DOI, published1.DOI}, dois)
+					collection.SetPublicDatasets(published2, published1)
+					return collection.ToGetCollectionResponse(t, userID, nil), nil
+				}),
+		).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.PutDOIOrderRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		WithBody(t, dto.PutDOIOrderRequest{DOIs: []string{published2.DOI, published1.DOI}}).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, []string{*published2.Banner, *published1.Banner}, responseDTO.Banners)
+	require.Len(t, responseDTO.Datasets, 2)
+}
+
+func testCreateCollectionSection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Write).
+		WithNPennsieveDOIs(2)
+	sectionDOI := collection.DOIs[1].DOI
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithCreateSectionFunc(func(_ context.Context, collectionID int64, _ int64, request collections.CreateSectionRequest) (collections.Section, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, collections.CreateSectionRequest{Title: "Section", Description: "Description", DOIs: []string{sectionDOI}}, request)
+					return collections.Section{ID: 1, Title: request.Title, Description: request.Description, DOIs: request.DOIs}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.CreateCollectionSectionRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		WithBody(t, dto.CreateCollectionSectionRequest{Title: "Section", Description: "Description", DOIs: []string{sectionDOI}}).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, response.StatusCode)
+
+	var responseDTO dto.CollectionSection
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, dto.CollectionSection{ID: 1, Title: "Section", Description: "Description", DOIs: []string{sectionDOI}}, responseDTO)
+}
+
+func testDeleteCollectionSection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner)
+	sectionID := rand.Int64N(1000) + 1
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithDeleteSectionFunc(func(_ context.Context, collectionID int64, _ int64, actualSectionID int64) error {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, sectionID, actualSectionID)
+					return nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.DeleteCollectionSectionRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithPathParam(routes.SectionIDPathParamKey, strconv.FormatInt(sectionID, 10)).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Empty(t, response.Body)
+}
+
+func testImportDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Write)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithUpdateCollectionFunc(func(_ context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, []collections.DOI{{Value: published.DOI, Datasource: datasource.Pennsieve}}, update.DOIs.Add)
+					return collections.GetCollectionResponse{}, nil
+				}),
+		).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.ImportDOIsRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		WithHeader(routes.ContentTypeHeader, "text/csv").
+		WithBody(t, "doi\n"+published.DOI+"\n").
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.ImportDOIsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	require.Len(t, responseDTO.Results, 1)
+	assert.Equal(t, dto.ImportAdded, responseDTO.Results[0].Status)
+}
+
+func testExportCollection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Guest).
+		WithPublicDatasets(published)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(collection.GetCollectionFunc(t, nil))).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.ExportCollectionRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		WithQueryParam(routes.FormatQueryParamKey, "ris").
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/x-research-info-systems", response.Headers["content-type"])
+	assert.Contains(t, response.Body, "DO  - "+published.DOI)
+}
+
+func testGetROCrate(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithNPennsieveDOIs(1)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetCollectionMembersFunc(func(_ context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return []collections.CollectionMember{{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: role.Owner}}, nil
+				}),
+		).
+		WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetROCrateRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, dto.ROCrateContentType, response.Headers["content-type"])
+
+	var crate publishing.ROCrate
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &crate))
+	assert.Equal(t, publishing.ROCrateContext, crate.Context)
+	assert.NotEmpty(t, crate.Graph)
+}
+
+func testGetCollectionActivity(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetCollectionEventsFunc(func(_ context.Context, collectionID int64, limit int, offset int) (collections.GetCollectionEventsResponse, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return collections.GetCollectionEventsResponse{
+						Limit:  limit,
+						Offset: offset,
+						Events: []collections.Event{{
+							ID:        1,
+							Type:      collections.CreatedEvent,
+							User:      &collections.Actor{UserID: callingUser.ID, UserNodeID: callingUser.NodeID},
+							Diff:      json.RawMessage(`{}`),
+							CreatedAt: time.Now().UTC(),
+						}},
+						TotalCount: 1,
+					}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetCollectionActivityRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var activity dto.GetCollectionActivityResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &activity))
+	assert.Equal(t, 1, activity.TotalCount)
+	require.Len(t, activity.Events, 1)
+	assert.Equal(t, string(collections.CreatedEvent), activity.Events[0].Type)
+	require.NotNil(t, activity.Events[0].User)
+	assert.Equal(t, callingUser.NodeID, activity.Events[0].User.UserNodeID)
+}
+
+func testGetPublishStatus(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner)
+
+	expectedJob := collectionstest.NewQueuedPublishJob(*collection.ID, *collection.NodeID, callingUser.ID, callingUser.NodeID)
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetLatestPublishJobFunc(func(_ context.Context, collectionID int64) (collections.PublishJob, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					return expectedJob, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetPublishStatusRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var job dto.PublishJob
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &job))
+	assert.Equal(t, expectedJob.ID, job.JobID)
+	assert.Equal(t, publishing.JobQueuedStatus, job.Status)
+}
+
+func testPublishPreview(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished()).
+		WithRandomLicense().
+		WithNTags(2)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(collection.GetCollectionFunc(t, nil))).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+		WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.PublishPreviewRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var preview dto.PublishPreview
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &preview))
+	var discoverRequest service.PublishDOICollectionRequest
+	require.NoError(t, json.Unmarshal(preview.DiscoverRequest, &discoverRequest))
+	assert.Equal(t, collection.Name, discoverRequest.Name)
+	assert.Equal(t, collection.Name, preview.Manifest.Name)
+}
+
+func testPublishReadiness(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	// no license or tags
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished())
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(collection.GetCollectionFunc(t, nil))).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+		WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser)))
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.PublishReadinessRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var readiness dto.PublishReadiness
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &readiness))
+	assert.False(t, readiness.Ready)
+	var codes []dto.ReadinessCode
+	for _, item := range readiness.Items {
+		codes = append(codes, item.Code)
+	}
+	assert.Contains(t, codes, dto.ReadinessInvalidLicense)
+	assert.Contains(t, codes, dto.ReadinessInvalidTags)
+}
+
+func testGetCollectionSnapshot(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithRandomID().
+		WithUser(callingUser.ID, pgdb.Read)
+
+	snapshotID := int64(9)
+	savedDOI := apitest.NewPennsieveDOI()
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(
+			mocks.NewCollectionsStore().
+				WithGetCollectionFunc(collection.GetCollectionFunc(t, nil)).
+				WithGetSnapshotFunc(func(_ context.Context, collectionID int64, actualSnapshotID int64) (collections.Snapshot, error) {
+					require.Equal(t, *collection.ID, collectionID)
+					require.Equal(t, snapshotID, actualSnapshotID)
+					return collections.Snapshot{
+						SnapshotSummary: collections.SnapshotSummary{
+							ID:        snapshotID,
+							Name:      "Before review",
+							CreatedBy: &collections.Actor{UserID: callingUser.ID, UserNodeID: callingUser.NodeID},
+							CreatedAt: time.Now().UTC(),
+						},
+						State: collections.SnapshotState{Name: collection.Name, DOIs: collections.DOIs{savedDOI}},
+					}, nil
+				}),
+		)
+
+	handler := CollectionsServiceAPIHandler(container, apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build())
+	request := apitest.NewAPIGatewayRequestBuilder(routes.GetCollectionSnapshotRouteKey).
+		WithPathParam(routes.NodeIDPathParamKey, *collection.NodeID).
+		WithPathParam(routes.SnapshotIDPathParamKey, strconv.FormatInt(snapshotID, 10)).
+		WithDefaultClaims(callingUser).
+		Build()
+
+	response, err := handler(ctx, request)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var snapshot dto.CollectionSnapshot
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &snapshot))
+	assert.Equal(t, snapshotID, snapshot.ID)
+	assert.Equal(t, "Before review", snapshot.Name)
+	assert.Equal(t, collection.Name, snapshot.State.Name)
+	assert.Equal(t, []dto.SnapshotDOI{{DOI: savedDOI.Value, Datasource: savedDOI.Datasource}}, snapshot.State.DOIs)
+}
+
+func testReviseCollection(t *testing.T) {
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithNTags(1).
+		WithDOIs(apitest.NewPennsieveDOI())
+
+	expectedPublishStatus := collectionstest.NewCompletedPublishStatus(*expectedCollection.ID, callingUser.ID)
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &expectedPublishStatus)).
+		WithStartPublishFunc(expectedCollection.StartPublishFunc(t, callingUser.ID, publishing.RevisionType)).
+		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.CompletedStatus))
+
+	expectedPublishedDatasetID := 12
+	publishedManifest := apitest.NewExpectedManifest(t,
+		apitest.WithManifestPennsieveDatasetID(expectedPublishedDatasetID),
+		apitest.WithManifestVersion(1))
+
+	mockInternalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(func(_ context.Context, _ int64, _ string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+			return service.DatasetPublishStatusResponse{
+				PublishedDatasetID:    expectedPublishedDatasetID,
+				PublishedVersionCount: 1,
+				Status:                dto.PublishSucceeded,
+			}, nil
+		}).
+		WithReviseCollectionFunc(func(_ context.Context, _ int64, _ string, _ role.Role, request service.ReviseDOICollectionRequest) (service.ReviseDOICollectionResponse, error) {
+			return service.ReviseDOICollectionResponse{
+				PublishedDatasetID: expectedPublishedDatasetID,
+				PublishedVersion:   request.PublishedVersion,
+				Revision:           request.Revision,
+				Status:             dto.PublishSucceeded,
+			}, nil
+		})
+
+	mockManifestStore := mocks.NewManifestStore().
+		WithGetManifestFunc(func(_ context.Context, _ string) (publishing.ManifestV5, error) {
+			return publishedManifest, nil
+		}).
+		WithSaveManifestFunc(func(_ context.Context, _ string, _ publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
+			return manifests.SaveManifestResponse{S3VersionID: uuid.NewString()}, nil
+		})
+
+	handler := CollectionsServiceAPIHandler(
+		apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithInternalDiscover(mockInternalDiscover).
+			WithManifestStore(mockManifestStore).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		apitest.NewConfigBuilder().
+			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+			Build(),
+	)
+	req := apitest.NewAPIGatewayRequestBuilder(routes.ReviseCollectionRouteKey).
+		WithDefaultClaims(callingUser).
+		WithPathParam(routes.NodeIDPathParamKey, *expectedCollection.NodeID).
+		Build()
+
+	response, err := handler(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.ReviseCollectionResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, expectedPublishedDatasetID, responseDTO.PublishedDatasetID)
+	assert.Equal(t, 1, responseDTO.PublishedVersion)
+	assert.Equal(t, 1, responseDTO.Revision)
+	assert.Equal(t, dto.PublishSucceeded, responseDTO.Status)
+}
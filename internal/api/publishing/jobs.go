@@ -0,0 +1,33 @@
+package publishing
+
+// JobStatus is the status of a queued publish job. It is separate from Status, which is the status of the
+// publication process that the job is running.
+type JobStatus string
+
+// JobQueuedStatus means the job is waiting for the publish worker, either for its first attempt or for a retry
+const JobQueuedStatus JobStatus = "Queued"
+
+// JobRunningStatus means the publish worker is running an attempt of the job
+const JobRunningStatus JobStatus = "Running"
+
+// JobSucceededStatus means an attempt of the job finished without error. The job will not run again
+const JobSucceededStatus JobStatus = "Succeeded"
+
+// JobFailedStatus means the job failed and will not be retried
+const JobFailedStatus JobStatus = "Failed"
+
+var JobStatuses = []JobStatus{JobQueuedStatus, JobRunningStatus, JobSucceededStatus, JobFailedStatus}
+
+// JobStep is the step of publishing that a publish job is on.
+type JobStep string
+
+const (
+	CheckingDOIsStep           JobStep = "CheckingDOIs"
+	PublishingToDiscoverStep   JobStep = "PublishingToDiscover"
+	SavingManifestStep         JobStep = "SavingManifest"
+	FinalizingWithDiscoverStep JobStep = "FinalizingWithDiscover"
+	FinishingPublishStep       JobStep = "FinishingPublish"
+)
+
+// JobSteps are in the order that a publish job runs them.
+var JobSteps = []JobStep{CheckingDOIsStep, PublishingToDiscoverStep, SavingManifestStep, FinalizingWithDiscoverStep, FinishingPublishStep}
@@ -21,6 +21,22 @@ type FileManifest struct {
 
 type References struct {
 	IDs []string `json:"ids"`
+	// Annotations holds the label and note of any referenced DOI that has them.
+	Annotations []ReferenceAnnotation `json:"annotations,omitempty"`
+	// Sections groups some of IDs under a title, in the collection's section order.
+	Sections []ReferenceSection `json:"sections,omitempty"`
+}
+
+type ReferenceSection struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	IDs         []string `json:"ids"`
+}
+
+type ReferenceAnnotation struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	Note  string `json:"note,omitempty"`
 }
 
 const ManifestFileName = "manifest.json"
@@ -115,6 +131,29 @@ func NewManifestBuilder() *ManifestBuilder {
 	}}
 }
 
+// NewRevisionManifestBuilder starts a manifest for the given revision of a published version. The returned builder keeps
+// the published version's id, references, and date published, but not its name, description, creator, keywords, or license,
+// since those are what a revision changes.
+func NewRevisionManifestBuilder(published ManifestV5, revision int) *ManifestBuilder {
+	b := NewManifestBuilder().
+		WithPennsieveDatasetID(published.PennsieveDatasetID).
+		WithVersion(published.Version).
+		WithID(published.ID).
+		WithSourceOrganization(published.SourceOrganization)
+	b.m.Revision = revision
+	b.m.DatePublished = published.DatePublished
+	b.m.References = published.References
+	b.m.Collections = published.Collections
+	b.m.RelatedPublications = published.RelatedPublications
+	// Build adds the manifest's own entry back with its new size
+	for _, file := range published.Files {
+		if file.Path != ManifestFileName {
+			b.m.Files = append(b.m.Files, file)
+		}
+	}
+	return b
+}
+
 func (b *ManifestBuilder) WithPennsieveDatasetID(id int) *ManifestBuilder {
 	b.m.PennsieveDatasetID = id
 	return b
@@ -161,6 +200,31 @@ func (b *ManifestBuilder) WithReferences(dois []string) *ManifestBuilder {
 	return b
 }
 
+// WithReferenceAnnotation adds the label and note of the referenced DOI id. Does nothing if both are empty.
+func (b *ManifestBuilder) WithReferenceAnnotation(id string, label string, note string) *ManifestBuilder {
+	if len(label) > 0 || len(note) > 0 {
+		b.m.References.Annotations = append(b.m.References.Annotations, ReferenceAnnotation{
+			ID:    id,
+			Label: label,
+			Note:  note,
+		})
+	}
+	return b
+}
+
+// WithReferenceSection adds a section containing the referenced DOIs ids after any existing sections.
+func (b *ManifestBuilder) WithReferenceSection(title string, description string, ids []string) *ManifestBuilder {
+	if ids == nil {
+		ids = make([]string, 0)
+	}
+	b.m.References.Sections = append(b.m.References.Sections, ReferenceSection{
+		Title:       title,
+		Description: description,
+		IDs:         ids,
+	})
+	return b
+}
+
 func (b *ManifestBuilder) WithSourceOrganization(sourceOrg string) *ManifestBuilder {
 	b.m.SourceOrganization = sourceOrg
 	return b
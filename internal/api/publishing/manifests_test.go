@@ -89,3 +89,106 @@ func TestManifestV5_TotalSize(t *testing.T) {
 	assert.Equal(t, int64(len(manifestBytes)), manifest.TotalSize())
 
 }
+
+func TestManifestBuilder_WithReferenceAnnotation(t *testing.T) {
+	labelled := apitest.NewPennsieveDOI().Value
+	noted := apitest.NewPennsieveDOI().Value
+	unannotated := apitest.NewPennsieveDOI().Value
+
+	manifest, err := publishing.NewManifestBuilder().
+		WithReferences([]string{labelled, noted, unannotated}).
+		WithReferenceAnnotation(labelled, "primary", "").
+		WithReferenceAnnotation(noted, "", "source of the control group").
+		WithReferenceAnnotation(unannotated, "", "").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{labelled, noted, unannotated}, manifest.References.IDs)
+	assert.Equal(t, []publishing.ReferenceAnnotation{
+		{ID: labelled, Label: "primary"},
+		{ID: noted, Note: "source of the control group"},
+	}, manifest.References.Annotations)
+
+	manifestBytes, err := manifest.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(manifestBytes)), apitest.FindManifestEntry(t, manifest).Size)
+
+	// annotations are left out of the manifest entirely if there are none
+	unannotatedManifest, err := publishing.NewManifestBuilder().WithReferences([]string{unannotated}).Build()
+	require.NoError(t, err)
+	unannotatedBytes, err := unannotatedManifest.Marshal()
+	require.NoError(t, err)
+	assert.NotContains(t, string(unannotatedBytes), "annotations")
+}
+
+func TestManifestBuilder_WithReferenceSection(t *testing.T) {
+	first := apitest.NewPennsieveDOI().Value
+	second := apitest.NewPennsieveDOI().Value
+	unsectioned := apitest.NewExternalDOI().Value
+
+	manifest, err := publishing.NewManifestBuilder().
+		WithReferences([]string{first, second, unsectioned}).
+		WithReferenceSection("Imaging", "MRI datasets", []string{first, second}).
+		WithReferenceSection("Empty", "", nil).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, []publishing.ReferenceSection{
+		{Title: "Imaging", Description: "MRI datasets", IDs: []string{first, second}},
+		{Title: "Empty", IDs: []string{}},
+	}, manifest.References.Sections)
+
+	manifestBytes, err := manifest.Marshal()
+	require.NoError(t, err)
+	assert.NotContains(t, string(manifestBytes), "null")
+	assert.Equal(t, int64(len(manifestBytes)), apitest.FindManifestEntry(t, manifest).Size)
+}
+
+func TestNewRevisionManifestBuilder(t *testing.T) {
+	doi := apitest.NewPennsieveDOI().Value
+	published, err := publishing.NewManifestBuilder().
+		WithPennsieveDatasetID(301).
+		WithVersion(2).
+		WithID(apitest.NewPennsieveDOI().Value).
+		WithName("Published Name").
+		WithDescription("Published description").
+		WithKeywords([]string{"old"}).
+		WithLicense("MIT").
+		WithCreator(publishing.PublishedContributor{FirstName: "Old", LastName: "Name"}).
+		WithSourceOrganization("Collections").
+		WithReferences([]string{doi}).
+		WithReferenceAnnotation(doi, "Label", "").
+		Build()
+	require.NoError(t, err)
+
+	revisedCreator := publishing.PublishedContributor{FirstName: "New", LastName: "Name", Orcid: "0000-0001"}
+	revision, err := publishing.NewRevisionManifestBuilder(published, 3).
+		WithName("Revised Name").
+		WithDescription("Revised description").
+		WithKeywords([]string{"new", "tags"}).
+		WithLicense("Apache-2.0").
+		WithCreator(revisedCreator).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, revision.Revision)
+	assert.Equal(t, published.PennsieveDatasetID, revision.PennsieveDatasetID)
+	assert.Equal(t, published.Version, revision.Version)
+	assert.Equal(t, published.ID, revision.ID)
+	assert.Equal(t, published.SourceOrganization, revision.SourceOrganization)
+	assert.True(t, published.DatePublished.Equal(revision.DatePublished))
+	assert.Equal(t, published.References, revision.References)
+
+	assert.Equal(t, "Revised Name", revision.Name)
+	assert.Equal(t, "Revised description", revision.Description)
+	assert.Equal(t, []string{"new", "tags"}, revision.Keywords)
+	assert.Equal(t, "Apache-2.0", revision.License)
+	assert.Equal(t, revisedCreator, revision.Creator)
+	assert.Equal(t, []publishing.PublishedContributor{revisedCreator}, revision.Contributors)
+
+	// only one entry for the manifest itself, with the size of the revision
+	require.Len(t, revision.Files, 1)
+	revisionBytes, err := revision.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(revisionBytes)), apitest.FindManifestEntry(t, revision).Size)
+}
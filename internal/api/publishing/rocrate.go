@@ -0,0 +1,157 @@
+package publishing
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apijson"
+	"strings"
+	"time"
+)
+
+// RO-Crate 1.1. See https://www.researchobject.org/ro-crate/specification/1.1/
+const ROCrateMetadataFileName = "ro-crate-metadata.json"
+const ROCrateContext = "https://w3id.org/ro/crate/1.1/context"
+const ROCrateSpecification = "https://w3id.org/ro/crate/1.1"
+
+// ROCrateRootID is the @id of the root Dataset entity, which describes the collection itself.
+const ROCrateRootID = "./"
+
+const roCrateLicenseID = "#license"
+const roCratePublisherID = "#publisher"
+const roCrateSourceOrganizationID = "#source-organization"
+
+// doiURLPrefix turns DOIs into the absolute URIs that RO-Crate wants as @ids of web-based entities.
+const doiURLPrefix = "https://doi.org/"
+const orcidURLPrefix = "https://orcid.org/"
+
+// ROCrate is the content of an ro-crate-metadata.json file.
+type ROCrate struct {
+	Context string          `json:"@context"`
+	Graph   []ROCrateEntity `json:"@graph"`
+}
+
+func (c ROCrate) Marshal() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// ROCrateEntity is one node of the RO-Crate @graph. It has the union of the properties we use
+// for each entity type; unused properties are left empty.
+type ROCrateEntity struct {
+	ID                 string             `json:"@id"`
+	Type               string             `json:"@type"`
+	ConformsTo         *ROCrateReference  `json:"conformsTo,omitempty"`
+	About              *ROCrateReference  `json:"about,omitempty"`
+	Identifier         string             `json:"identifier,omitempty"`
+	Name               string             `json:"name,omitempty"`
+	AlternateName      string             `json:"alternateName,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	Version            string             `json:"version,omitempty"`
+	DatePublished      string             `json:"datePublished,omitempty"`
+	License            *ROCrateReference  `json:"license,omitempty"`
+	Keywords           []string           `json:"keywords,omitempty"`
+	Author             []ROCrateReference `json:"author,omitempty"`
+	Publisher          *ROCrateReference  `json:"publisher,omitempty"`
+	SourceOrganization *ROCrateReference  `json:"sourceOrganization,omitempty"`
+	HasPart            []ROCrateReference `json:"hasPart,omitempty"`
+	GivenName          string             `json:"givenName,omitempty"`
+	FamilyName         string             `json:"familyName,omitempty"`
+}
+
+// ROCrateReference links to another entity of the @graph.
+type ROCrateReference struct {
+	ID string `json:"@id"`
+}
+
+// NewROCrate describes the collection in manifest as an RO-Crate. The manifest's schema.org
+// fields become properties of the root Dataset, and each referenced DOI becomes a part of it.
+// The manifest's annotations become the name and description of the part they annotate.
+// datePublished should be nil unless the collection has been published, since a draft manifest
+// is dated the day it is built.
+func NewROCrate(manifest ManifestV5, datePublished *time.Time) ROCrate {
+	metadata := ROCrateEntity{
+		ID:         ROCrateMetadataFileName,
+		Type:       "CreativeWork",
+		ConformsTo: &ROCrateReference{ID: ROCrateSpecification},
+		About:      &ROCrateReference{ID: ROCrateRootID},
+	}
+	root := ROCrateEntity{
+		ID:          ROCrateRootID,
+		Type:        "Dataset",
+		Name:        manifest.Name,
+		Description: manifest.Description,
+		Keywords:    manifest.Keywords,
+		Publisher:   &ROCrateReference{ID: roCratePublisherID},
+	}
+	if datePublished != nil {
+		root.DatePublished = apijson.Date(*datePublished).String()
+	}
+	if len(manifest.ID) > 0 {
+		root.Identifier = doiURLPrefix + manifest.ID
+	}
+	if manifest.Version > 0 {
+		root.Version = fmt.Sprint(manifest.Version)
+	}
+	// contextual entities that root links to
+	var linked []ROCrateEntity
+
+	if len(manifest.License) > 0 {
+		root.License = &ROCrateReference{ID: roCrateLicenseID}
+		linked = append(linked, ROCrateEntity{ID: roCrateLicenseID, Type: "CreativeWork", Name: manifest.License})
+	}
+	linked = append(linked, ROCrateEntity{ID: roCratePublisherID, Type: "Organization", Name: manifest.Publisher})
+	if len(manifest.SourceOrganization) > 0 {
+		root.SourceOrganization = &ROCrateReference{ID: roCrateSourceOrganizationID}
+		linked = append(linked, ROCrateEntity{ID: roCrateSourceOrganizationID, Type: "Organization", Name: manifest.SourceOrganization})
+	}
+
+	for i, contributor := range manifest.Contributors {
+		person := newROCratePerson(i, contributor)
+		root.Author = append(root.Author, ROCrateReference{ID: person.ID})
+		linked = append(linked, person)
+	}
+
+	annotations := map[string]ReferenceAnnotation{}
+	for _, annotation := range manifest.References.Annotations {
+		annotations[annotation.ID] = annotation
+	}
+	for _, doi := range manifest.References.IDs {
+		part := ROCrateEntity{
+			ID:         doiURLPrefix + doi,
+			Type:       "Dataset",
+			Identifier: doi,
+		}
+		if annotation, annotated := annotations[doi]; annotated {
+			part.AlternateName = annotation.Label
+			part.Description = annotation.Note
+		}
+		root.HasPart = append(root.HasPart, ROCrateReference{ID: part.ID})
+		linked = append(linked, part)
+	}
+
+	return ROCrate{Context: ROCrateContext, Graph: append([]ROCrateEntity{metadata, root}, linked...)}
+}
+
+// newROCratePerson uses the contributor's ORCID as the @id if there is one, since RO-Crate prefers
+// resolvable ids for people. Otherwise, the @id is local to the crate.
+func newROCratePerson(index int, contributor PublishedContributor) ROCrateEntity {
+	person := ROCrateEntity{
+		Type:       "Person",
+		GivenName:  contributor.FirstName,
+		FamilyName: contributor.LastName,
+	}
+	switch {
+	case strings.HasPrefix(contributor.Orcid, orcidURLPrefix):
+		person.ID = contributor.Orcid
+	case len(contributor.Orcid) > 0:
+		person.ID = orcidURLPrefix + contributor.Orcid
+	default:
+		person.ID = fmt.Sprintf("#contributor-%d", index+1)
+	}
+	nameParts := []string{contributor.FirstName}
+	if middleInitial := strings.TrimSuffix(contributor.MiddleInitial, "."); len(middleInitial) > 0 {
+		nameParts = append(nameParts, middleInitial+".")
+	}
+	nameParts = append(nameParts, contributor.LastName)
+	person.Name = strings.Join(strings.Fields(strings.Join(nameParts, " ")), " ")
+	return person
+}
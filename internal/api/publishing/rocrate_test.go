@@ -0,0 +1,109 @@
+package publishing_test
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewROCrate(t *testing.T) {
+	manifest, err := publishing.NewManifestBuilder().
+		WithName("Collection").
+		WithDescription("A collection").
+		WithCreator(publishing.PublishedContributor{FirstName: "Jane", LastName: "Smith", MiddleInitial: "Q", Orcid: "0000-0001-2345-6789"}).
+		WithLicense("Creative Commons Attribution").
+		WithKeywords([]string{"neuro", "mouse"}).
+		WithReferences([]string{"10.26275/first", "10.1234/second"}).
+		WithReferenceAnnotation("10.1234/second", "Label", "Note").
+		WithSourceOrganization("Pennsieve").
+		Build()
+	require.NoError(t, err)
+
+	crate := publishing.NewROCrate(manifest, nil)
+	assert.Equal(t, publishing.ROCrateContext, crate.Context)
+
+	entities := map[string]publishing.ROCrateEntity{}
+	for _, entity := range crate.Graph {
+		require.NotContains(t, entities, entity.ID, "duplicate @id")
+		entities[entity.ID] = entity
+	}
+	require.Len(t, entities, 8)
+
+	metadata := entities[publishing.ROCrateMetadataFileName]
+	assert.Equal(t, "CreativeWork", metadata.Type)
+	assert.Equal(t, &publishing.ROCrateReference{ID: publishing.ROCrateSpecification}, metadata.ConformsTo)
+	assert.Equal(t, &publishing.ROCrateReference{ID: publishing.ROCrateRootID}, metadata.About)
+
+	root := entities[publishing.ROCrateRootID]
+	assert.Equal(t, "Dataset", root.Type)
+	assert.Equal(t, "Collection", root.Name)
+	assert.Equal(t, "A collection", root.Description)
+	assert.Equal(t, []string{"neuro", "mouse"}, root.Keywords)
+	// draft collections have no DOI, version, or date published
+	assert.Empty(t, root.DatePublished)
+	assert.Empty(t, root.Identifier)
+	assert.Empty(t, root.Version)
+	assert.Equal(t, []publishing.ROCrateReference{{ID: "https://doi.org/10.26275/first"}, {ID: "https://doi.org/10.1234/second"}}, root.HasPart)
+
+	require.NotNil(t, root.License)
+	assert.Equal(t, "Creative Commons Attribution", entities[root.License.ID].Name)
+	require.NotNil(t, root.Publisher)
+	assert.Equal(t, publishing.ManifestPublisher, entities[root.Publisher.ID].Name)
+	require.NotNil(t, root.SourceOrganization)
+	assert.Equal(t, "Pennsieve", entities[root.SourceOrganization.ID].Name)
+
+	require.Equal(t, []publishing.ROCrateReference{{ID: "https://orcid.org/0000-0001-2345-6789"}}, root.Author)
+	assert.Equal(t, publishing.ROCrateEntity{
+		ID:         "https://orcid.org/0000-0001-2345-6789",
+		Type:       "Person",
+		Name:       "Jane Q. Smith",
+		GivenName:  "Jane",
+		FamilyName: "Smith",
+	}, entities[root.Author[0].ID])
+
+	assert.Equal(t, publishing.ROCrateEntity{
+		ID:         "https://doi.org/10.26275/first",
+		Type:       "Dataset",
+		Identifier: "10.26275/first",
+	}, entities["https://doi.org/10.26275/first"])
+	assert.Equal(t, publishing.ROCrateEntity{
+		ID:            "https://doi.org/10.1234/second",
+		Type:          "Dataset",
+		Identifier:    "10.1234/second",
+		AlternateName: "Label",
+		Description:   "Note",
+	}, entities["https://doi.org/10.1234/second"])
+
+	asBytes, err := crate.Marshal()
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(asBytes, &decoded))
+	assert.Contains(t, decoded, "@context")
+	assert.Contains(t, decoded, "@graph")
+}
+
+func TestNewROCrate_Published(t *testing.T) {
+	manifest, err := publishing.NewManifestBuilder().
+		WithID("10.26275/collection").
+		WithVersion(2).
+		WithName("Collection").
+		WithCreator(publishing.PublishedContributor{FirstName: "John", LastName: "Doe"}).
+		Build()
+	require.NoError(t, err)
+
+	datePublished := time.Date(2025, time.March, 14, 15, 9, 26, 0, time.UTC)
+	crate := publishing.NewROCrate(manifest, &datePublished)
+	root := crate.Graph[1]
+	require.Equal(t, publishing.ROCrateRootID, root.ID)
+	assert.Equal(t, "https://doi.org/10.26275/collection", root.Identifier)
+	assert.Equal(t, "2", root.Version)
+	assert.Equal(t, "2025-03-14", root.DatePublished)
+	assert.Nil(t, root.License)
+	assert.Nil(t, root.SourceOrganization)
+	// without an ORCID the person's id is local to the crate
+	assert.Equal(t, []publishing.ROCrateReference{{ID: "#contributor-1"}}, root.Author)
+	assert.Empty(t, root.HasPart)
+}
@@ -14,6 +14,8 @@ const CompletedStatus Status = "Completed"
 // FailedStatus means that a publication process ran and finished with an error
 const FailedStatus Status = "Failed"
 
+var Statuses = []Status{DraftStatus, InProgressStatus, CompletedStatus, FailedStatus}
+
 type Type string
 
 const PublicationType Type = "Publication"
@@ -0,0 +1,148 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+var CopyCollectionRouteKey = fmt.Sprintf("POST /{%s}/copy", NodeIDPathParamKey)
+
+// CopyNameSuffix is appended to the name of the original collection to name the copy if no name is given.
+const CopyNameSuffix = " (copy)"
+
+// CopyCollection creates a new draft collection owned by the caller with the name, description, license, tags
+// and DOIs of the collection identified by the nodeId path param.
+func CopyCollection(ctx context.Context, params Params) (dto.CreateCollectionResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CreateCollectionResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	// the request body is optional
+	var copyRequest dto.CopyCollectionRequest
+	if requestBody := params.Request.Body; len(requestBody) > 0 {
+		decoder := json.NewDecoder(strings.NewReader(requestBody))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&copyRequest); err != nil {
+			return dto.CreateCollectionResponse{}, apierrors.NewRequestUnmarshallError(copyRequest, err)
+		}
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	original, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CreateCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CreateCollectionResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	minRequiredRole := role.Guest
+	if !original.UserRole.Implies(minRequiredRole) {
+		return dto.CreateCollectionResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not copied; requires user role: %s",
+				nodeID,
+				minRequiredRole),
+		)
+	}
+
+	name := CopyName(original.Name)
+	if copyRequest.Name != nil {
+		name = strings.TrimSpace(*copyRequest.Name)
+		if err := validate.CollectionName(name); err != nil {
+			return dto.CreateCollectionResponse{}, err
+		}
+	}
+
+	doisToAdd := original.DOIs
+	var banners []string
+	if pennsieveDOIs, _ := GroupByDatasource(original.DOIs); len(pennsieveDOIs) > 0 {
+		datasetResults, err := params.Container.Discover().GetDatasetsByDOI(ctx, pennsieveDOIs)
+		if err != nil {
+			return dto.CreateCollectionResponse{}, apierrors.NewInternalServerError("error looking up DOIs in Discover", err)
+		}
+		if copyRequest.DropTombstones && len(datasetResults.Unpublished) > 0 {
+			doisToAdd = nil
+			for _, doi := range original.DOIs {
+				if _, tombstoned := datasetResults.Unpublished[doi.Value]; !tombstoned {
+					doisToAdd = append(doisToAdd, doi)
+				}
+			}
+			pennsieveDOIs, _ = GroupByDatasource(doisToAdd)
+			params.Container.Logger().Info("dropping unpublished DOIs from copy",
+				slog.Int("droppedCount", len(datasetResults.Unpublished)))
+			datasetResults.Unpublished = nil
+		}
+		if err := ValidateDiscoverResponse(datasetResults); err != nil {
+			return dto.CreateCollectionResponse{}, err
+		}
+		banners = collectBanners(pennsieveDOIs, datasetResults.Published)
+	}
+
+	copyNodeID := uuid.NewString()
+	createCollection := collections.CreateCollectionRequest{
+		NodeID:      copyNodeID,
+		Name:        name,
+		Description: original.Description,
+		DOIs:        doisToAdd,
+		UserID:      userClaim.Id,
+		License:     original.License,
+		Tags:        original.Tags,
+	}
+	storeResp, err := collectionsStore.CreateCollection(ctx, createCollection)
+	if err != nil {
+		return dto.CreateCollectionResponse{}, apierrors.NewInternalServerError(fmt.Sprintf("error copying collection %s", nodeID), err)
+	}
+
+	response := dto.CreateCollectionResponse{
+		NodeID:      copyNodeID,
+		Name:        name,
+		Description: original.Description,
+		Banners:     banners,
+		Size:        len(doisToAdd),
+		Tags:        original.Tags,
+		UserRole:    storeResp.CreatorRole.String(),
+	}
+	if original.License != nil {
+		response.License = *original.License
+	}
+
+	return response, nil
+}
+
+func NewCopyCollectionRouteHandler() Handler[dto.CreateCollectionResponse] {
+	return Handler[dto.CreateCollectionResponse]{
+		HandleFunc:        CopyCollection,
+		SuccessStatusCode: http.StatusCreated,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+// CopyName returns the default name for a copy of a collection named originalName,
+// shortening originalName if needed so that the result is a valid collection name.
+func CopyName(originalName string) string {
+	maxLen := validate.MaxCollectionNameLength - len(CopyNameSuffix)
+	for len(originalName) > maxLen {
+		_, lastRuneSize := utf8.DecodeLastRuneInString(originalName)
+		originalName = originalName[:len(originalName)-lastRuneSize]
+	}
+	return originalName + CopyNameSuffix
+}
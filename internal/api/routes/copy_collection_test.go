@@ -0,0 +1,265 @@
+package routes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCopyCollection(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"guest can copy collection", testCopyCollectionAsGuest},
+		{"copy collection, dropping tombstones", testCopyCollectionDropTombstones},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newCopyCollectionParams(t *testing.T, callingUser userstest.User, collectionNodeID string, discoverURL string, copyRequest *dto.CopyCollectionRequest) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(discoverURL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithHTTPTestDiscover(discoverURL).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(CopyCollectionRouteKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, collectionNodeID)
+	if copyRequest != nil {
+		requestBuilder = requestBuilder.WithBody(t, *copyRequest)
+	}
+
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testCopyCollectionAsGuest(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	guest := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, guest)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published1 := expectedDatasets.NewPublished()
+	published2 := expectedDatasets.NewPublished()
+
+	original := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithUser(*guest.ID, pgdb.Guest).
+		WithRandomLicense().
+		WithNTags(2).
+		WithPublicDatasets(published2, published1)
+	expectationDB.CreateCollection(ctx, t, original)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	response, err := CopyCollection(ctx, newCopyCollectionParams(t, guest, *original.NodeID, mockDiscoverServer.URL, nil))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, *original.NodeID, response.NodeID)
+	assert.Equal(t, original.Name+CopyNameSuffix, response.Name)
+	assert.Equal(t, original.Description, response.Description)
+	assert.Equal(t, *original.License, response.License)
+	assert.Equal(t, original.Tags, response.Tags)
+	assert.Equal(t, 2, response.Size)
+	assert.Equal(t, []string{*published2.Banner, *published1.Banner}, response.Banners)
+	assert.Equal(t, role.Owner.String(), response.UserRole)
+
+	expectedCopy := apitest.NewExpectedCollection().
+		WithUser(*guest.ID, pgdb.Owner).
+		WithLicense(*original.License).
+		WithTags(original.Tags).
+		WithPublicDatasets(published2, published1)
+	expectedCopy.Name = original.Name + CopyNameSuffix
+	expectedCopy.Description = original.Description
+	expectationDB.RequireCollectionByNodeID(ctx, t, expectedCopy, response.NodeID)
+
+	// original is unchanged
+	expectationDB.RequireCollection(ctx, t, original, *original.ID)
+}
+
+func testCopyCollectionDropTombstones(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published1 := expectedDatasets.NewPublished()
+	unpublished := expectedDatasets.NewUnpublished()
+	published2 := expectedDatasets.NewPublished()
+
+	original := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithPublicDatasets(published1).
+		WithTombstones(unpublished).
+		WithPublicDatasets(published2)
+	expectationDB.CreateCollection(ctx, t, original)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	newName := uuid.NewString()
+	copyRequest := dto.CopyCollectionRequest{Name: &newName, DropTombstones: true}
+
+	response, err := CopyCollection(ctx, newCopyCollectionParams(t, owner, *original.NodeID, mockDiscoverServer.URL, &copyRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, newName, response.Name)
+	assert.Equal(t, 2, response.Size)
+	assert.Equal(t, []string{*published1.Banner, *published2.Banner}, response.Banners)
+
+	expectedCopy := apitest.NewExpectedCollection().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithPublicDatasets(published1, published2)
+	expectedCopy.Name = newName
+	expectedCopy.Description = original.Description
+	expectationDB.RequireCollectionByNodeID(ctx, t, expectedCopy, response.NodeID)
+}
+
+func TestHandleCopyCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"return Bad Request when collection contains tombstones", testHandleCopyCollectionTombstones},
+		{"return Not Found when collection is not visible", testHandleCopyCollectionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testHandleCopyCollectionTombstones(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+	unpublished := expectedDatasets.NewUnpublished()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Guest).
+		WithPublicDatasets(published).
+		WithTombstones(unpublished)
+
+	// no CreateCollectionFunc set, so the mock panics if the copy is created
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(CopyCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+	response, err := Handle(ctx, NewCopyCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, unpublished.DOI)
+}
+
+func testHandleCopyCollectionNotFound(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	nodeID := uuid.NewString()
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.GetCollectionResponse, error) {
+			return collections.GetCollectionResponse{}, collections.ErrCollectionNotFound
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(CopyCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, nodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewCopyCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Contains(t, response.Body, nodeID)
+}
+
+func TestCopyName(t *testing.T) {
+	assert.Equal(t, "My Collection (copy)", CopyName("My Collection"))
+
+	longName := strings.Repeat("a", validate.MaxCollectionNameLength)
+	copyName := CopyName(longName)
+	assert.Len(t, copyName, validate.MaxCollectionNameLength)
+	assert.True(t, strings.HasSuffix(copyName, CopyNameSuffix))
+
+	// multibyte runes are not split
+	longMultibyteName := strings.Repeat("é", validate.MaxCollectionNameLength/2)
+	multibyteCopyName := CopyName(longMultibyteName)
+	assert.LessOrEqual(t, len(multibyteCopyName), validate.MaxCollectionNameLength)
+	assert.True(t, utf8.ValidString(multibyteCopyName))
+	assert.NoError(t, validate.CollectionName(multibyteCopyName))
+}
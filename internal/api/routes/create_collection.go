@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
-	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/api/validate"
@@ -38,25 +37,20 @@ func CreateCollection(ctx context.Context, params Params) (dto.CreateCollectionR
 		return dto.CreateCollectionResponse{}, err
 	}
 
-	pennsieveDOIs, externalDOIs := CategorizeDOIs(ccParams.Config.PennsieveConfig.DOIPrefix, createRequest.DOIs)
-	if len(externalDOIs) > 0 {
-		// We may later allow non-Pennsieve DOIs, but for now, this is an error
-		return dto.CreateCollectionResponse{}, apierrors.NewBadRequestError(
-			fmt.Sprintf("request contains non-Pennsieve DOIs: %s", strings.Join(externalDOIs, ", ")))
-	}
+	doisToAdd := ToStoreDOIs(ccParams.Config.PennsieveConfig.DOIPrefix, createRequest.DOIs)
+	pennsieveDOIs, externalDOIs := GroupByDatasource(doisToAdd)
 
 	nodeID := uuid.NewString()
 	response := dto.CreateCollectionResponse{
 		NodeID:      nodeID,
 		Name:        createRequest.Name,
 		Description: createRequest.Description,
-		Size:        len(pennsieveDOIs),
+		Size:        len(doisToAdd),
 		Tags:        createRequest.Tags,
 	}
 	if createRequest.License != nil {
 		response.License = *createRequest.License
 	}
-	var doisToAdd []collections.DOI
 	if len(pennsieveDOIs) > 0 {
 		datasetResults, err := ccParams.Container.Discover().GetDatasetsByDOI(ctx, pennsieveDOIs)
 		if err != nil {
@@ -68,13 +62,9 @@ func CreateCollection(ctx context.Context, params Params) (dto.CreateCollectionR
 		}
 
 		response.Banners = collectBanners(pennsieveDOIs, datasetResults.Published)
-
-		for _, pennsieveDOI := range pennsieveDOIs {
-			doisToAdd = append(doisToAdd, collections.DOI{
-				Value:      pennsieveDOI,
-				Datasource: datasource.Pennsieve,
-			})
-		}
+	}
+	if _, err := ccParams.ResolveExternalDOIs(ctx, externalDOIs); err != nil {
+		return dto.CreateCollectionResponse{}, err
 	}
 	collectionsStore := ccParams.Container.CollectionsStore()
 
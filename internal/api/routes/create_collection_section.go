@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var CreateCollectionSectionRouteKey = fmt.Sprintf("POST /{%s}/sections", NodeIDPathParamKey)
+
+// CreateCollectionSection adds a section after the existing sections of the collection.
+// Any requested DOIs are moved into the new section from their current section.
+func CreateCollectionSection(ctx context.Context, params Params) (dto.CollectionSection, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionSection{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.CollectionSection{}, apierrors.NewBadRequestError("missing request body")
+	}
+	var createRequest dto.CreateCollectionSectionRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&createRequest); err != nil {
+		return dto.CollectionSection{}, apierrors.NewRequestUnmarshallError(createRequest, err)
+	}
+
+	title := strings.TrimSpace(createRequest.Title)
+	if err := validate.SectionTitle(title); err != nil {
+		return dto.CollectionSection{}, err
+	}
+	description := strings.TrimSpace(createRequest.Description)
+	if err := validate.SectionDescription(description); err != nil {
+		return dto.CollectionSection{}, err
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSection{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSection{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageSections) {
+		return dto.CollectionSection{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s section not created; requires user role: %s",
+				nodeID,
+				minRoleToManageSections),
+		)
+	}
+
+	dois, err := ValidateSectionDOIs(createRequest.DOIs, collection.DOIs)
+	if err != nil {
+		return dto.CollectionSection{}, err
+	}
+
+	section, err := collectionsStore.CreateSection(ctx, collection.ID, userClaim.Id, collections.CreateSectionRequest{
+		Title:       title,
+		Description: description,
+		DOIs:        dois,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.CollectionSection{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrSectionDOIsNotInCollection):
+			return dto.CollectionSection{}, NewSectionDOIsConflictError(nodeID, err)
+		default:
+			return dto.CollectionSection{}, apierrors.NewInternalServerError("error creating collection section", err)
+		}
+	}
+
+	return ToDTOCollectionSection(section), nil
+}
+
+func NewCreateCollectionSectionRouteHandler() Handler[dto.CollectionSection] {
+	return Handler[dto.CollectionSection]{
+		HandleFunc:        CreateCollectionSection,
+		SuccessStatusCode: http.StatusCreated,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
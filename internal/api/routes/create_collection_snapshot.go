@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var CreateCollectionSnapshotRouteKey = fmt.Sprintf("POST /{%s}/versions", NodeIDPathParamKey)
+
+// CreateCollectionSnapshot saves the current name, description, license, tags, and ordered DOIs of the collection
+// under the requested name so that they can be restored later.
+func CreateCollectionSnapshot(ctx context.Context, params Params) (dto.CollectionSnapshot, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionSnapshot{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.CollectionSnapshot{}, apierrors.NewBadRequestError("missing request body")
+	}
+	var createRequest dto.CreateCollectionSnapshotRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&createRequest); err != nil {
+		return dto.CollectionSnapshot{}, apierrors.NewRequestUnmarshallError(createRequest, err)
+	}
+
+	name := strings.TrimSpace(createRequest.Name)
+	if err := validate.SnapshotName(name); err != nil {
+		return dto.CollectionSnapshot{}, err
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSnapshot{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSnapshot{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageSnapshots) {
+		return dto.CollectionSnapshot{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s snapshot not created; requires user role: %s",
+				nodeID,
+				minRoleToManageSnapshots),
+		)
+	}
+
+	snapshot, err := collectionsStore.CreateSnapshot(ctx, collection.ID, userClaim.Id, name)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSnapshot{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSnapshot{}, apierrors.NewInternalServerError("error creating collection snapshot", err)
+	}
+
+	return ToDTOCollectionSnapshot(snapshot), nil
+}
+
+func NewCreateCollectionSnapshotRouteHandler() Handler[dto.CollectionSnapshot] {
+	return Handler[dto.CollectionSnapshot]{
+		HandleFunc:        CreateCollectionSnapshot,
+		SuccessStatusCode: http.StatusCreated,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -30,6 +30,7 @@ func TestCreateCollection(t *testing.T) {
 		"create collection; five DTOs":            testCreateCollectionFiveDTOs,
 		"create collection; some missing banners": testCreateCollectionSomeMissingBanners,
 		"create collection; remove whitespace":    testCreateCollectionRemoveWhitespace,
+		"create collection; external DOIs":        testCreateCollectionExternalDOIs,
 	} {
 		t.Run(scenario, func(t *testing.T) {
 			db := test.NewPostgresDBFromConfig(t, config)
@@ -157,6 +158,70 @@ func testCreateCollectionTwoDTOs(t *testing.T, expectationDB *fixtures.Expectati
 	expectationDB.RequireCollectionByNodeID(ctx, t, expectedCollection, response.NodeID)
 }
 
+func testCreateCollectionExternalDOIs(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	callingUser := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published1 := expectedDatasets.NewPublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	external1 := expectedExternalDatasets.NewResolved()
+	external2 := expectedExternalDatasets.NewResolved()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithUser(*callingUser.ID, pgdb.Owner).
+		WithExternalDatasets(external1).
+		WithPublicDatasets(published1).
+		WithExternalDatasets(external2)
+
+	createCollectionRequest := dto.CreateCollectionRequest{
+		Name:        expectedCollection.Name,
+		Description: expectedCollection.Description,
+		DOIs:        expectedCollection.DOIs.Strings(),
+	}
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	mockResolverServer := httptest.NewServer(mocks.NewDOIResolverMux(t).WithExternalDatasets(external1, external2))
+	defer mockResolverServer.Close()
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	config := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(mockDiscoverServer.URL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, config.PostgresDB)).
+		WithHTTPTestDiscover(mockDiscoverServer.URL).
+		WithHTTPTestExternalDOI(mockResolverServer.URL).
+		WithCollectionsStoreFromPostgresDB(config.PostgresDB.CollectionsDatabase)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(CreateCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, createCollectionRequest).
+			Build(),
+		Container: container,
+		Config:    config,
+		Claims:    &claims,
+	}
+
+	response, err := CreateCollection(ctx, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(createCollectionRequest.DOIs), response.Size)
+	// only Pennsieve datasets have banners
+	assert.Equal(t, []string{*published1.Banner}, response.Banners)
+
+	expectationDB.RequireCollectionByNodeID(ctx, t, expectedCollection, response.NodeID)
+}
+
 func testCreateCollectionFiveDTOs(t *testing.T, expectationDB *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
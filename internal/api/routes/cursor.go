@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"strings"
+	"time"
+)
+
+// cursorJSON is the serialized form of collections.Cursor. Clients should treat the encoded
+// value as opaque.
+type cursorJSON struct {
+	SortBy        collections.SortField     `json:"s"`
+	SortDirection collections.SortDirection `json:"d"`
+	Key           any                       `json:"k"`
+	ID            int64                     `json:"id"`
+}
+
+// EncodeCursor returns an opaque, URL-safe string for the given cursor.
+func EncodeCursor(cursor collections.Cursor) (string, error) {
+	cursorBytes, err := json.Marshal(cursorJSON(cursor))
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorBytes), nil
+}
+
+// DecodeCursor is the inverse of EncodeCursor.
+func DecodeCursor(encoded string) (collections.Cursor, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return collections.Cursor{}, fmt.Errorf("error decoding cursor: %w", err)
+	}
+	var decoded cursorJSON
+	decoder := json.NewDecoder(strings.NewReader(string(cursorBytes)))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&decoded); err != nil {
+		return collections.Cursor{}, fmt.Errorf("error unmarshalling cursor: %w", err)
+	}
+
+	cursor := collections.Cursor{SortBy: decoded.SortBy, SortDirection: decoded.SortDirection, ID: decoded.ID}
+	switch decoded.SortDirection {
+	case collections.Ascending, collections.Descending:
+	default:
+		return collections.Cursor{}, fmt.Errorf("unknown cursor sort direction: %q", decoded.SortDirection)
+	}
+
+	// JSON loses the type of the key, so restore it based on the sort field
+	switch decoded.SortBy {
+	case collections.SortByName:
+		key, ok := decoded.Key.(string)
+		if !ok {
+			return collections.Cursor{}, errors.New("cursor key is not a string")
+		}
+		cursor.Key = key
+	case collections.SortByCreatedAt, collections.SortByUpdatedAt:
+		keyString, ok := decoded.Key.(string)
+		if !ok {
+			return collections.Cursor{}, errors.New("cursor key is not a timestamp")
+		}
+		key, err := time.Parse(time.RFC3339Nano, keyString)
+		if err != nil {
+			return collections.Cursor{}, fmt.Errorf("cursor key is not a timestamp: %w", err)
+		}
+		cursor.Key = key
+	case collections.SortBySize, collections.SortByID:
+		keyNumber, ok := decoded.Key.(json.Number)
+		if !ok {
+			return collections.Cursor{}, errors.New("cursor key is not an integer")
+		}
+		key, err := keyNumber.Int64()
+		if err != nil {
+			return collections.Cursor{}, fmt.Errorf("cursor key is not an integer: %w", err)
+		}
+		cursor.Key = key
+	default:
+		return collections.Cursor{}, fmt.Errorf("unknown cursor sort field: %q", decoded.SortBy)
+	}
+	return cursor, nil
+}
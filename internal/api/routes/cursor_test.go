@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"encoding/base64"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	for _, tt := range []struct {
+		scenario string
+		cursor   collections.Cursor
+	}{
+		{"id", collections.Cursor{SortBy: collections.SortByID, SortDirection: collections.Ascending, Key: int64(42), ID: 42}},
+		{"name", collections.Cursor{SortBy: collections.SortByName, SortDirection: collections.Descending, Key: "my collection", ID: 7}},
+		{"createdAt", collections.Cursor{SortBy: collections.SortByCreatedAt, SortDirection: collections.Ascending, Key: time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.UTC), ID: 8}},
+		{"updatedAt", collections.Cursor{SortBy: collections.SortByUpdatedAt, SortDirection: collections.Descending, Key: time.Now().UTC().Truncate(time.Microsecond), ID: 9}},
+		{"size", collections.Cursor{SortBy: collections.SortBySize, SortDirection: collections.Ascending, Key: int64(0), ID: 10}},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			encoded, err := EncodeCursor(tt.cursor)
+			require.NoError(t, err)
+
+			decoded, err := DecodeCursor(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tt.cursor, decoded)
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		scenario string
+		encoded  string
+	}{
+		{"not base64", "!!!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("cursor"))},
+		{"unknown sort", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"nodeId","d":"asc","k":"x","id":1}`))},
+		{"unknown direction", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"name","d":"up","k":"x","id":1}`))},
+		{"wrong key type", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"size","d":"asc","k":"x","id":1}`))},
+		{"bad timestamp", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"createdAt","d":"asc","k":"yesterday","id":1}`))},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			_, err := DecodeCursor(tt.encoded)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -15,6 +15,9 @@ import (
 
 var DeleteCollectionRouteKey = fmt.Sprintf("DELETE /{%s}", NodeIDPathParamKey)
 
+// minDeleteRole is the role needed to move a collection to the trash and to see or restore it once there.
+const minDeleteRole = role.Manager
+
 func DeleteCollection(ctx context.Context, params Params) (dto.NoContent, error) {
 	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
 	if len(nodeID) == 0 {
@@ -34,20 +37,36 @@ func DeleteCollection(ctx context.Context, params Params) (dto.NoContent, error)
 			"error querying store for collection to delete",
 			err)
 	}
-	if !storeResp.UserRole.Implies(role.Manager) {
+	if !storeResp.UserRole.Implies(minDeleteRole) {
 		return dto.NoContent{}, apierrors.NewForbiddenError(
 			fmt.Sprintf("collection %s not deleted; requires user role: %s",
 				nodeID,
-				role.Manager),
+				minDeleteRole),
 		)
 	}
 
+	conditional, err := CheckIfMatch(params.Request.Headers, nodeID, storeResp.Version)
+	if err != nil {
+		return dto.NoContent{}, err
+	}
+	var expectedVersion *int64
+	if conditional {
+		expectedVersion = &storeResp.Version
+	}
+
 	if err := validatePublishStatusForDelete(storeResp.Publication); err != nil {
 		return dto.NoContent{}, err
 	}
 
-	if err := params.Container.CollectionsStore().DeleteCollection(ctx, storeResp.ID); err != nil {
-		return dto.NoContent{}, apierrors.NewInternalServerError("error deleting collection", err)
+	if err := params.Container.CollectionsStore().DeleteCollection(ctx, storeResp.ID, userClaim.Id, expectedVersion); err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrCollectionVersionMismatch):
+			return dto.NoContent{}, NewCollectionChangedError(nodeID)
+		default:
+			return dto.NoContent{}, apierrors.NewInternalServerError("error deleting collection", err)
+		}
 	}
 	return dto.NoContent{}, nil
 }
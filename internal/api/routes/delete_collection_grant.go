@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var DeleteCollectionTeamRouteKey = fmt.Sprintf("DELETE /{%s}/teams/{%s}", NodeIDPathParamKey, TeamNodeIDPathParamKey)
+
+var DeleteCollectionOrganizationRouteKey = fmt.Sprintf("DELETE /{%s}/organizations/{%s}", NodeIDPathParamKey, OrganizationNodeIDPathParamKey)
+
+// DeleteCollectionTeam removes the role of the given team on the collection.
+func DeleteCollectionTeam(ctx context.Context, params Params) (dto.NoContent, error) {
+	return deleteCollectionGrant(ctx, params, collections.TeamGrantee)
+}
+
+// DeleteCollectionOrganization removes the role of the given organization on the collection.
+func DeleteCollectionOrganization(ctx context.Context, params Params) (dto.NoContent, error) {
+	return deleteCollectionGrant(ctx, params, collections.OrganizationGrantee)
+}
+
+// deleteCollectionGrant removes the role of a team or organization on the collection.
+// The caller must be at least a Manager.
+func deleteCollectionGrant(ctx context.Context, params Params, granteeType collections.GranteeType) (dto.NoContent, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	granteeParamKey := granteePathParamKey(granteeType)
+	granteeNodeID := params.Request.PathParameters[granteeParamKey]
+	if len(granteeNodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, granteeParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.String(granteeParamKey, granteeNodeID))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageMembers) {
+		return dto.NoContent{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s %s roles not updated; requires user role: %s",
+				nodeID,
+				granteeType,
+				minRoleToManageMembers),
+		)
+	}
+
+	if err := params.Container.CollectionsStore().DeleteCollectionGrant(ctx, collection.ID, userClaim.Id, granteeType, granteeNodeID); err != nil {
+		if errors.Is(err, collections.ErrCollectionGrantNotFound) {
+			return dto.NoContent{}, NewCollectionGrantNotFoundError(nodeID, granteeType, granteeNodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError(fmt.Sprintf("error removing collection %s role", granteeType), err)
+	}
+	return dto.NoContent{}, nil
+}
+
+func NewDeleteCollectionTeamRouteHandler() Handler[dto.NoContent] {
+	return Handler[dto.NoContent]{
+		HandleFunc:        DeleteCollectionTeam,
+		SuccessStatusCode: http.StatusNoContent,
+	}
+}
+
+func NewDeleteCollectionOrganizationRouteHandler() Handler[dto.NoContent] {
+	return Handler[dto.NoContent]{
+		HandleFunc:        DeleteCollectionOrganization,
+		SuccessStatusCode: http.StatusNoContent,
+	}
+}
@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteCollectionGrant(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"remove team from collection", testDeleteCollectionTeam},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func testDeleteCollectionTeam(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	teamMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, teamMember)
+	teamID, teamNodeID := expectationDB.CreateTestTeam(ctx, t, *teamMember.ID)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.AddCollectionTeam(ctx, t, collectionID, teamID, pgdb.Read)
+
+	claims := apitest.DefaultClaims(owner)
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(DeleteCollectionTeamRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *collection.NodeID).
+			WithPathParam(TeamNodeIDPathParamKey, teamNodeID).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+
+	_, err := DeleteCollectionTeam(ctx, params)
+	require.NoError(t, err)
+
+	_, err = container.CollectionsStore().GetCollection(ctx, *teamMember.ID, *collection.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
+func TestHandleDeleteCollectionGrant(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+	granteeNodeID := uuid.NewString()
+
+	for _, tt := range []struct {
+		scenario       string
+		granteeType    collections.GranteeType
+		callerPerm     pgdb.DbPermission
+		storeErr       error
+		expectedStatus int
+	}{
+		{"editor cannot remove team", collections.TeamGrantee, pgdb.Delete, nil, http.StatusForbidden},
+		{"manager can remove team", collections.TeamGrantee, pgdb.Administer, nil, http.StatusNoContent},
+		{"team without grant", collections.TeamGrantee, pgdb.Owner, collections.ErrCollectionGrantNotFound, http.StatusNotFound},
+		{"viewer cannot remove organization", collections.OrganizationGrantee, pgdb.Read, nil, http.StatusForbidden},
+		{"owner can remove organization", collections.OrganizationGrantee, pgdb.Owner, nil, http.StatusNoContent},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tt.callerPerm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithDeleteCollectionGrantFunc(func(ctx context.Context, collectionID int64, _ int64, granteeType collections.GranteeType, nodeID string) error {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, tt.granteeType, granteeType)
+					require.Equal(t, granteeNodeID, nodeID)
+					return tt.storeErr
+				})
+
+			routeKey, handler := DeleteCollectionTeamRouteKey, NewDeleteCollectionTeamRouteHandler()
+			if tt.granteeType == collections.OrganizationGrantee {
+				routeKey, handler = DeleteCollectionOrganizationRouteKey, NewDeleteCollectionOrganizationRouteHandler()
+			}
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(routeKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithPathParam(granteePathParamKey(tt.granteeType), granteeNodeID).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, handler, params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
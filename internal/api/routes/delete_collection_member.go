@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var DeleteCollectionMemberRouteKey = fmt.Sprintf("DELETE /{%s}/members/{%s}", NodeIDPathParamKey, UserNodeIDPathParamKey)
+
+// DeleteCollectionMember removes the given user from the collection.
+// Any member may remove themselves. Otherwise, the caller must be at least a Manager and cannot remove
+// a member whose role is higher than their own.
+func DeleteCollectionMember(ctx context.Context, params Params) (dto.NoContent, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	memberNodeID := params.Request.PathParameters[UserNodeIDPathParamKey]
+	if len(memberNodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, UserNodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.String("memberNodeId", memberNodeID))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if memberNodeID != userClaim.NodeId {
+		if !collection.UserRole.Implies(minRoleToManageMembers) {
+			return dto.NoContent{}, apierrors.NewForbiddenError(
+				fmt.Sprintf("collection %s members not updated; requires user role: %s",
+					nodeID,
+					minRoleToManageMembers),
+			)
+		}
+		existingMember, err := findCollectionMember(ctx, params, collection.ID, memberNodeID)
+		if err != nil {
+			return dto.NoContent{}, err
+		}
+		if existingMember == nil {
+			return dto.NoContent{}, NewCollectionMemberNotFoundError(nodeID, memberNodeID)
+		}
+		if !collection.UserRole.Implies(existingMember.Role) {
+			return dto.NoContent{}, apierrors.NewForbiddenError(
+				fmt.Sprintf("cannot remove member with role %s; user role is %s",
+					existingMember.Role,
+					collection.UserRole),
+			)
+		}
+	}
+
+	if err := params.Container.CollectionsStore().DeleteCollectionMember(ctx, collection.ID, userClaim.Id, memberNodeID); err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionMemberNotFound):
+			return dto.NoContent{}, NewCollectionMemberNotFoundError(nodeID, memberNodeID)
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrLastOwner):
+			return dto.NoContent{}, apierrors.NewConflictErrorWithCause(
+				fmt.Sprintf("cannot remove user %s; collection %s must have an owner", memberNodeID, nodeID),
+				err)
+		default:
+			return dto.NoContent{}, apierrors.NewInternalServerError("error removing collection member", err)
+		}
+	}
+	return dto.NoContent{}, nil
+}
+
+func NewDeleteCollectionMemberRouteHandler() Handler[dto.NoContent] {
+	return Handler[dto.NoContent]{
+		HandleFunc:        DeleteCollectionMember,
+		SuccessStatusCode: http.StatusNoContent,
+	}
+}
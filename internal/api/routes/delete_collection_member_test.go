@@ -0,0 +1,225 @@
+package routes
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteCollectionMember(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"remove collection member", testDeleteCollectionMemberRemove},
+		{"member removes themselves", testDeleteCollectionMemberSelf},
+		{"remove non-member", testDeleteCollectionMemberNonMember},
+		{"remove only owner", testDeleteCollectionMemberLastOwner},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newDeleteCollectionMemberParams(t *testing.T, callingUser userstest.User, collectionNodeID, memberNodeID string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(DeleteCollectionMemberRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			WithPathParam(UserNodeIDPathParamKey, memberNodeID).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testDeleteCollectionMemberRemove(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	editor := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, editor)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*editor.ID, pgdb.Write)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := DeleteCollectionMember(ctx, newDeleteCollectionMemberParams(t, owner, *collection.NodeID, editor.NodeID))
+	require.NoError(t, err)
+
+	collection.RemoveUser(*editor.ID)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMemberSelf(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	guest := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, guest)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*guest.ID, pgdb.Guest)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := DeleteCollectionMember(ctx, newDeleteCollectionMemberParams(t, guest, *collection.NodeID, guest.NodeID))
+	require.NoError(t, err)
+
+	collection.RemoveUser(*guest.ID)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMemberNonMember(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := DeleteCollectionMember(ctx, newDeleteCollectionMemberParams(t, owner, *collection.NodeID, nonMember.NodeID))
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Contains(t, apiErr.UserMessage, nonMember.NodeID)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMemberLastOwner(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := DeleteCollectionMember(ctx, newDeleteCollectionMemberParams(t, owner, *collection.NodeID, owner.NodeID))
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+// TestHandleDeleteCollectionMember tests that run the Handle wrapper around DeleteCollectionMember
+func TestHandleDeleteCollectionMember(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"delete collection member, authorization", testDeleteCollectionMemberAuthz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testDeleteCollectionMemberAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+	otherNodeID := userstest.NewTestUser().NodeID
+
+	for _, tt := range []struct {
+		scenario       string
+		callerPerm     pgdb.DbPermission
+		memberNodeID   string
+		memberRole     role.Role
+		expectedStatus int
+	}{
+		{"guest can remove themselves", pgdb.Guest, callingUser.NodeID, role.Guest, http.StatusNoContent},
+		{"editor cannot remove others", pgdb.Delete, otherNodeID, role.Guest, http.StatusForbidden},
+		{"manager can remove viewer", pgdb.Administer, otherNodeID, role.Viewer, http.StatusNoContent},
+		{"manager can remove manager", pgdb.Administer, otherNodeID, role.Manager, http.StatusNoContent},
+		{"manager cannot remove owner", pgdb.Administer, otherNodeID, role.Owner, http.StatusForbidden},
+		{"owner can remove owner", pgdb.Owner, otherNodeID, role.Owner, http.StatusNoContent},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tt.callerPerm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithGetCollectionMembersFunc(func(ctx context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					return []collections.CollectionMember{
+						{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: tt.callerPerm.ToRole()},
+						{UserID: 100, UserNodeID: otherNodeID, Role: tt.memberRole},
+					}, nil
+				}).
+				WithDeleteCollectionMemberFunc(func(ctx context.Context, collectionID int64, actorID int64, userNodeID string) error {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, callingUser.ID, actorID)
+					require.Equal(t, tt.memberNodeID, userNodeID)
+					return nil
+				})
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(DeleteCollectionMemberRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithPathParam(UserNodeIDPathParamKey, tt.memberNodeID).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewDeleteCollectionMemberRouteHandler(), params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Empty(t, resp.Body)
+			} else {
+				assert.Contains(t, resp.Body, "errorId")
+			}
+		})
+	}
+}
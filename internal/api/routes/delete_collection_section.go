@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var DeleteCollectionSectionRouteKey = fmt.Sprintf("DELETE /{%s}/sections/{%s}", NodeIDPathParamKey, SectionIDPathParamKey)
+
+// DeleteCollectionSection removes a section from the collection. The datasets in the section stay in the collection.
+func DeleteCollectionSection(ctx context.Context, params Params) (dto.NoContent, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	sectionID, err := sectionIDPathParam(params)
+	if err != nil {
+		return dto.NoContent{}, err
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.Int64(SectionIDPathParamKey, sectionID))
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageSections) {
+		return dto.NoContent{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s section not deleted; requires user role: %s",
+				nodeID,
+				minRoleToManageSections),
+		)
+	}
+
+	if err := collectionsStore.DeleteSection(ctx, collection.ID, userClaim.Id, sectionID); err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrSectionNotFound):
+			return dto.NoContent{}, NewSectionNotFoundError(nodeID, sectionID)
+		default:
+			return dto.NoContent{}, apierrors.NewInternalServerError("error deleting collection section", err)
+		}
+	}
+	return dto.NoContent{}, nil
+}
+
+func NewDeleteCollectionSectionRouteHandler() Handler[dto.NoContent] {
+	return Handler[dto.NoContent]{
+		HandleFunc:        DeleteCollectionSection,
+		SuccessStatusCode: http.StatusNoContent,
+	}
+}
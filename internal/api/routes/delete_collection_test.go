@@ -5,6 +5,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
@@ -121,7 +122,7 @@ func testDeleteCollection(t *testing.T, expectationDB *fixtures.ExpectationDB) {
 
 	require.NoError(t, err)
 
-	expectationDB.RequireNoCollection(ctx, t, idToDelete)
+	expectationDB.RequireDeletedCollection(ctx, t, user1CollectionDelete, idToDelete)
 	expectationDB.RequireCollection(ctx, t, user1CollectionKeep, keepResp.ID)
 	expectationDB.RequireCollection(ctx, t, user2Collection, user2Resp.ID)
 }
@@ -191,7 +192,7 @@ func testDeleteCollectionWithPublishStatus(t *testing.T, expectationDB *fixtures
 
 			if tt.allowed {
 				require.NoError(t, err)
-				expectationDB.RequireNoCollection(ctx, t, idToDelete)
+				expectationDB.RequireDeletedCollection(ctx, t, collection, idToDelete)
 			} else {
 				require.Error(t, err)
 				var apiErr *apierrors.Error
@@ -213,6 +214,8 @@ func TestHandleDeleteCollection(t *testing.T) {
 		tstFunc  func(t *testing.T)
 	}{
 		{"delete collection, authorization", testDeleteAuthz},
+		{"delete collection, If-Match", testDeleteIfMatch},
+		{"delete collection, changed after If-Match check", testDeleteIfMatchChanged},
 	}
 
 	for _, tt := range tests {
@@ -266,7 +269,7 @@ func testDeleteAuthz(t *testing.T) {
 
 			mockCollectionStore := mocks.NewCollectionsStore().
 				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
-				WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64) error {
+				WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64, _ int64, _ *int64) error {
 					require.Equal(t, mockCollectionID, collectionID)
 					return nil
 				})
@@ -291,3 +294,84 @@ func testDeleteAuthz(t *testing.T) {
 	}
 
 }
+
+func testDeleteIfMatch(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	version := int64(5)
+	tests := []struct {
+		ifMatch         string
+		expectedStatus  int
+		expectedVersion *int64
+	}{
+		{`"5"`, http.StatusNoContent, &version},
+		{"*", http.StatusNoContent, nil},
+		{`"4"`, http.StatusPreconditionFailed, nil},
+		{`W/"5"`, http.StatusPreconditionFailed, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ifMatch, func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithVersion(5)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64, _ int64, expectedVersion *int64) error {
+					require.Equal(t, http.StatusNoContent, tt.expectedStatus, "unexpected call to DeleteCollection")
+					assert.Equal(t, tt.expectedVersion, expectedVersion)
+					return nil
+				})
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(DeleteCollectionRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithHeader(IfMatchHeader, tt.ifMatch).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewDeleteCollectionRouteHandler(), params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func testDeleteIfMatchChanged(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithVersion(5)
+
+	// someone else updates the collection between GetCollection and DeleteCollection
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithDeleteCollectionFunc(func(ctx context.Context, collectionID int64, _ int64, expectedVersion *int64) error {
+			require.NotNil(t, expectedVersion)
+			assert.Equal(t, int64(5), *expectedVersion)
+			return collections.ErrCollectionVersionMismatch
+		})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(DeleteCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(IfMatchHeader, ETag(5)).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewDeleteCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.Contains(t, resp.Body, "has changed")
+}
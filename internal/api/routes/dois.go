@@ -1,34 +1,43 @@
 package routes
 
 import (
+	"context"
 	"fmt"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
 	"strings"
 )
 
 // CategorizeDOIs splits the given dois into either Pennsieve or non-Pennsieve, based on the prefix.
 // Also de-duplicates the DOIs and trims any leading or trailing whitespace.
 func CategorizeDOIs(pennsieveDOIPrefix string, dois []string) (pennsieveDOIs []string, externalDOIs []string) {
+	return GroupByDatasource(ToStoreDOIs(pennsieveDOIPrefix, dois))
+}
+
+// ToStoreDOIs is like CategorizeDOIs, but keeps the DOIs in a single slice in their original order, with
+// the Datasource of each set based on the prefix.
+func ToStoreDOIs(pennsieveDOIPrefix string, dois []string) []collections.DOI {
 	pennsievePrefixAndSlash := fmt.Sprintf("%s/", pennsieveDOIPrefix)
 	seenDOIs := map[string]bool{}
+	var storeDOIs []collections.DOI
 	// Maybe overly complicated, but trying to maintain order of the dois so that
 	// if there are dups, we take the first one
 	for _, doi := range dois {
 		doi = strings.TrimSpace(doi)
 		if _, seen := seenDOIs[doi]; !seen {
 			seenDOIs[doi] = true
+			storeDOI := collections.DOI{Value: doi, Datasource: datasource.External}
 			if strings.HasPrefix(doi, pennsievePrefixAndSlash) {
-				pennsieveDOIs = append(pennsieveDOIs, doi)
-			} else {
-				externalDOIs = append(externalDOIs, doi)
+				storeDOI.Datasource = datasource.Pennsieve
 			}
+			storeDOIs = append(storeDOIs, storeDOI)
 		}
 	}
-	return
+	return storeDOIs
 }
 
 func GroupByDatasource(dois []collections.DOI) (pennsieveDOIs []string, externalDOIs []string) {
@@ -42,6 +51,26 @@ func GroupByDatasource(dois []collections.DOI) (pennsieveDOIs []string, external
 	return
 }
 
+// ResolveExternalDOIs checks that externalDOIs are well-formed and known to the DOI resolver. Returns a Bad Request *apierrors.Error
+// if any are not. Does not call the resolver if externalDOIs is empty.
+func (p Params) ResolveExternalDOIs(ctx context.Context, externalDOIs []string) (service.ResolveDOIsResponse, error) {
+	if len(externalDOIs) == 0 {
+		return service.ResolveDOIsResponse{}, nil
+	}
+	if err := validate.DOIs(externalDOIs); err != nil {
+		return service.ResolveDOIsResponse{}, err
+	}
+	resolveResponse, err := p.Container.ExternalDOI().ResolveDOIs(ctx, externalDOIs)
+	if err != nil {
+		return service.ResolveDOIsResponse{}, apierrors.NewInternalServerError("error looking up non-Pennsieve DOIs", err)
+	}
+	if len(resolveResponse.Unresolved) > 0 {
+		return service.ResolveDOIsResponse{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("request contains DOIs that could not be resolved: %s", strings.Join(resolveResponse.Unresolved, ", ")))
+	}
+	return resolveResponse, nil
+}
+
 // ValidateDiscoverResponse returns a Bad Request *apierrors.Error if datasetResults
 // contains unpublished datasets or published collection datasets (a collection cannot contain a collection).
 func ValidateDiscoverResponse(datasetResults service.DatasetsByDOIResponse) error {
@@ -55,7 +84,7 @@ func ValidateDiscoverResponse(datasetResults service.DatasetsByDOIResponse) erro
 
 	var collectionDetails []string
 	for publishedDOI, published := range datasetResults.Published {
-		if published.DatasetType != nil && *published.DatasetType == dto.CollectionDatasetType {
+		if isCollectionDataset(published) {
 			collectionDetails = append(collectionDetails, publishedDOI)
 		}
 	}
@@ -64,3 +93,8 @@ func ValidateDiscoverResponse(datasetResults service.DatasetsByDOIResponse) erro
 	}
 	return nil
 }
+
+// isCollectionDataset returns true if published is itself a collection. A collection cannot contain a collection.
+func isCollectionDataset(published dto.PublicDataset) bool {
+	return published.DatasetType != nil && *published.DatasetType == dto.CollectionDatasetType
+}
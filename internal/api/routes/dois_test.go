@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -47,3 +48,23 @@ func TestCategorizeDOIs(t *testing.T) {
 	}
 
 }
+
+func TestToStoreDOIs(t *testing.T) {
+	pennsieveDOI1 := apitest.NewPennsieveDOI()
+	pennsieveDOI2 := apitest.NewPennsieveDOI()
+
+	externalDOI1 := apitest.NewExternalDOI()
+	externalDOI2 := apitest.NewExternalDOI()
+
+	assert.Nil(t, ToStoreDOIs(apitest.PennsieveDOIPrefix, nil))
+
+	actual := ToStoreDOIs(apitest.PennsieveDOIPrefix, []string{
+		externalDOI1.Value,
+		pennsieveDOI1.Value,
+		" " + externalDOI2.Value,
+		externalDOI1.Value,
+		pennsieveDOI2.Value + "\t",
+		pennsieveDOI1.Value,
+	})
+	assert.Equal(t, []collections.DOI{externalDOI1, pennsieveDOI1, externalDOI2, pennsieveDOI2}, actual)
+}
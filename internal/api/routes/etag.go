@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"strconv"
+	"strings"
+)
+
+// IfMatchHeader is lowercase because that is how API Gateway delivers request headers.
+const IfMatchHeader = "if-match"
+
+// ETag returns the strong entity tag for the given collection version.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// CheckIfMatch returns a Precondition Failed *apierrors.Error if the request has an If-Match header
+// and none of its entity tags match currentVersion. Weak tags never match, since If-Match uses strong comparison.
+// The returned bool is true if the request is conditional on currentVersion, that is, it has an If-Match header
+// other than "*", so that callers can make sure the version does not change before their update is applied.
+func CheckIfMatch(headers map[string]string, nodeID string, currentVersion int64) (bool, error) {
+	ifMatch := strings.TrimSpace(headers[IfMatchHeader])
+	if len(ifMatch) == 0 || ifMatch == "*" {
+		return false, nil
+	}
+	current := ETag(currentVersion)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == current {
+			return true, nil
+		}
+	}
+	return true, NewCollectionChangedError(nodeID)
+}
+
+func NewCollectionChangedError(nodeID string) *apierrors.Error {
+	return apierrors.NewPreconditionFailedError(
+		fmt.Sprintf("collection %s has changed; get the latest version and try again", nodeID))
+}
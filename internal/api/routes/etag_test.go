@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"1"`, ETag(1))
+	assert.Equal(t, `"42"`, ETag(42))
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name                string
+		headers             map[string]string
+		expectedConditional bool
+		expectedMatch       bool
+	}{
+		{"no header", map[string]string{}, false, true},
+		{"empty header", map[string]string{IfMatchHeader: ""}, false, true},
+		{"wildcard", map[string]string{IfMatchHeader: "*"}, false, true},
+		{"matching tag", map[string]string{IfMatchHeader: `"3"`}, true, true},
+		{"matching tag in list", map[string]string{IfMatchHeader: `"1", "3"`}, true, true},
+		{"non-matching tag", map[string]string{IfMatchHeader: `"2"`}, true, false},
+		{"weak tag", map[string]string{IfMatchHeader: `W/"3"`}, true, false},
+		{"unquoted tag", map[string]string{IfMatchHeader: `3`}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditional, err := CheckIfMatch(tt.headers, "some-node-id", 3)
+			assert.Equal(t, tt.expectedConditional, conditional)
+			if tt.expectedMatch {
+				assert.NoError(t, err)
+			} else {
+				var apiErr *apierrors.Error
+				require.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, http.StatusPreconditionFailed, apiErr.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const FormatQueryParamKey = "format"
+
+var ExportCollectionRouteKey = fmt.Sprintf("GET /{%s}/export", NodeIDPathParamKey)
+
+// ExportCollection renders the datasets of a collection as citations in the format given by the format query param.
+// Any user who can see the collection can export it.
+func ExportCollection(ctx context.Context, params Params) (dto.CollectionExport, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionExport{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	format, ok := citation.ParseExportFormat(params.Request.QueryStringParameters[FormatQueryParamKey])
+	if !ok {
+		return dto.CollectionExport{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("value of [%s] must be one of %v", FormatQueryParamKey, citation.ExportFormats()))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.String(FormatQueryParamKey, string(format)))
+
+	// GetCollection only returns the collection if the given user has >= Guest permission,
+	// so no further authz is required for this route.
+	storeResp, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionExport{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionExport{}, apierrors.NewInternalServerError(
+			"error querying store for collection to export",
+			err)
+	}
+
+	collection, err := params.StoreToDTOCollection(ctx, storeResp, nil)
+	if err != nil {
+		return dto.CollectionExport{}, err
+	}
+
+	entries, err := ToCitationEntries(collection.Datasets)
+	if err != nil {
+		return dto.CollectionExport{}, apierrors.NewInternalServerError("error reading datasets to export", err)
+	}
+
+	content, err := citation.Export(format, entries)
+	if err != nil {
+		return dto.CollectionExport{}, apierrors.NewInternalServerError(fmt.Sprintf("error exporting collection as %s", format), err)
+	}
+
+	return dto.CollectionExport{
+		ContentType: format.MediaType(),
+		FileName:    exportFileName(storeResp.Name, format),
+		Content:     content,
+	}, nil
+}
+
+// NewExportCollectionRouteHandler has no Headers since the content-type comes from the response.
+func NewExportCollectionRouteHandler() Handler[dto.CollectionExport] {
+	return Handler[dto.CollectionExport]{
+		HandleFunc:        ExportCollection,
+		SuccessStatusCode: http.StatusOK,
+	}
+}
+
+// ToCitationEntries returns a citation entry for each published Pennsieve dataset and resolved external dataset, in order.
+// Unpublished and unresolved datasets are left out since there is nothing to cite.
+func ToCitationEntries(datasets []dto.Dataset) ([]citation.Entry, error) {
+	var entries []citation.Entry
+	for _, dataset := range datasets {
+		if dataset.Problem {
+			continue
+		}
+		switch dataset.Source {
+		case datasource.Pennsieve:
+			var published dto.PublicDataset
+			if err := json.Unmarshal(dataset.Data, &published); err != nil {
+				return nil, fmt.Errorf("error unmarshalling Pennsieve dataset: %w", err)
+			}
+			entries = append(entries, PennsieveCitationEntry(published))
+		case datasource.External:
+			var external dto.ExternalDataset
+			if err := json.Unmarshal(dataset.Data, &external); err != nil {
+				return nil, fmt.Errorf("error unmarshalling external dataset: %w", err)
+			}
+			entries = append(entries, ExternalCitationEntry(external))
+		default:
+			return nil, fmt.Errorf("unknown dataset source: %q", dataset.Source)
+		}
+	}
+	return entries, nil
+}
+
+// PennsieveCitationEntry cites the version of published that Discover returned. The publisher is the
+// same one we give DataCite.
+func PennsieveCitationEntry(published dto.PublicDataset) citation.Entry {
+	entry := citation.Entry{
+		DOI:       published.DOI,
+		Title:     published.Name,
+		Type:      citation.DatasetType,
+		Publisher: publishing.ManifestPublisher,
+		Version:   strconv.Itoa(published.Version),
+		URL:       citation.DOIURL(published.DOI),
+		Issued:    published.FirstPublishedAt,
+	}
+	if entry.Issued == nil && !published.CreatedAt.IsZero() {
+		entry.Issued = &published.CreatedAt
+	}
+	for _, contributor := range published.Contributors {
+		given := contributor.FirstName
+		if middleInitial := strings.TrimSuffix(util.SafeDeref(contributor.MiddleInitial), "."); len(middleInitial) > 0 {
+			given = fmt.Sprintf("%s %s.", given, middleInitial)
+		}
+		entry.Authors = append(entry.Authors, citation.Name{Given: given, Family: contributor.LastName})
+	}
+	return entry
+}
+
+// ExternalCitationEntry uses creator names as given since the resolver has already flattened them to strings.
+func ExternalCitationEntry(external dto.ExternalDataset) citation.Entry {
+	entry := citation.Entry{
+		DOI:       external.DOI,
+		Title:     external.Title,
+		Type:      external.Type,
+		Publisher: external.Publisher,
+		URL:       external.URL,
+		Year:      external.PublicationYear,
+	}
+	if len(entry.URL) == 0 {
+		entry.URL = citation.DOIURL(external.DOI)
+	}
+	for _, creator := range external.Creators {
+		entry.Authors = append(entry.Authors, citation.Name{Literal: creator})
+	}
+	return entry
+}
+
+// exportFileName replaces everything but ASCII letters, digits, '-', and '_' in the collection name
+// so that the name is safe in a content-disposition header.
+func exportFileName(collectionName string, format citation.Format) string {
+	var builder strings.Builder
+	lastWasDash := false
+	for _, r := range strings.TrimSpace(collectionName) {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			builder.WriteRune(r)
+			lastWasDash = false
+		} else if !lastWasDash {
+			builder.WriteRune('-')
+			lastWasDash = true
+		}
+	}
+	name := strings.Trim(builder.String(), "-")
+	if len(name) == 0 {
+		name = "collection"
+	}
+	return name + format.FileExtension()
+}
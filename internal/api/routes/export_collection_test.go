@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandleExportCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"export should cite published and resolved datasets in order", testHandleExportCollectionCSLJSON},
+		{"export should set content-type and file name for format", testHandleExportCollectionHeaders},
+		{"export should return Bad Request for unknown format", testHandleExportCollectionUnknownFormat},
+		{"export should return Not Found when collection is not found", testHandleExportCollectionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleExportCollectionParams(nodeID string, format string, container *apitest.TestContainer) Params {
+	claims := apitest.DefaultClaims(userstest.SeedUser1)
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(ExportCollectionRouteKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, nodeID)
+	if len(format) > 0 {
+		requestBuilder = requestBuilder.WithQueryParam(FormatQueryParamKey, format)
+	}
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: container,
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandleExportCollectionCSLJSON(t *testing.T) {
+	ctx := context.Background()
+
+	firstPublishedAt := time.Date(2023, time.June, 7, 0, 0, 0, 0, time.UTC)
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublishedWithOptions(func(publicDataset *dto.PublicDataset) {
+		publicDataset.Name = "Published Dataset"
+		publicDataset.Version = 3
+		publicDataset.FirstPublishedAt = &firstPublishedAt
+		middleInitial := "Q"
+		publicDataset.Contributors = []dto.PublicContributor{{FirstName: "Jane", MiddleInitial: &middleInitial, LastName: "Smith"}}
+	})
+	unpublished := expectedDatasets.NewUnpublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	external := expectedExternalDatasets.NewResolved()
+	unresolved := expectedExternalDatasets.NewUnresolved()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Guest).
+		WithExternalDatasets(external).
+		WithTombstones(unpublished).
+		WithDOIs(collections.DOI{Value: unresolved, Datasource: datasource.External}).
+		WithPublicDatasets(published)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+		WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t)))
+
+	response, err := Handle(ctx, NewExportCollectionRouteHandler(), newHandleExportCollectionParams(*expectedCollection.NodeID, string(citation.CSLJSON), container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, citation.CSLJSON.MediaType(), response.Headers["content-type"])
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &items))
+	require.Len(t, items, 2)
+
+	assert.Equal(t, external.DOI, items[0]["DOI"])
+	assert.Equal(t, external.Title, items[0]["title"])
+	assert.Equal(t, external.URL, items[0]["URL"])
+	assert.Equal(t, []any{
+		map[string]any{"literal": external.Creators[0]},
+		map[string]any{"literal": external.Creators[1]},
+	}, items[0]["author"])
+
+	assert.Equal(t, map[string]any{
+		"id":        published.DOI,
+		"type":      citation.DatasetType,
+		"title":     "Published Dataset",
+		"author":    []any{map[string]any{"family": "Smith", "given": "Jane Q."}},
+		"issued":    map[string]any{"date-parts": []any{[]any{2023.0, 6.0, 7.0}}},
+		"publisher": publishing.ManifestPublisher,
+		"version":   "3",
+		"DOI":       published.DOI,
+		"URL":       citation.DOIURL(published.DOI),
+	}, items[1])
+}
+
+func testHandleExportCollectionHeaders(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithPublicDatasets(published)
+	expectedCollection.Name = "  My Collection: 2024/25 "
+
+	for _, format := range []citation.Format{citation.BibTeX, citation.RIS} {
+		t.Run(string(format), func(t *testing.T) {
+			container := apitest.NewTestContainer().
+				WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+				WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+			response, err := Handle(ctx, NewExportCollectionRouteHandler(), newHandleExportCollectionParams(*expectedCollection.NodeID, string(format), container))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, response.StatusCode)
+
+			assert.Equal(t, format.MediaType(), response.Headers["content-type"])
+			assert.Equal(t, `attachment; filename="My-Collection-2024-25`+format.FileExtension()+`"`, response.Headers["content-disposition"])
+
+			references, err := citation.ExtractReferences(format, response.Body)
+			require.NoError(t, err)
+			require.Len(t, references, 1)
+			assert.Equal(t, published.DOI, references[0].DOI)
+		})
+	}
+}
+
+func testHandleExportCollectionUnknownFormat(t *testing.T) {
+	ctx := context.Background()
+
+	for _, format := range []string{"", "csv", "BIBTEX"} {
+		t.Run(format, func(t *testing.T) {
+			// no store set, so the container panics if the store is called
+			response, err := Handle(ctx, NewExportCollectionRouteHandler(), newHandleExportCollectionParams(*apitest.NewExpectedCollection().WithNodeID().NodeID, format, apitest.NewTestContainer()))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+			assert.Contains(t, response.Body, FormatQueryParamKey)
+		})
+	}
+}
+
+func testHandleExportCollectionNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	nodeID := *apitest.NewExpectedCollection().WithNodeID().NodeID
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.GetCollectionResponse, error) {
+			return collections.GetCollectionResponse{}, collections.ErrCollectionNotFound
+		}))
+
+	response, err := Handle(ctx, NewExportCollectionRouteHandler(), newHandleExportCollectionParams(nodeID, string(citation.RIS), container))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
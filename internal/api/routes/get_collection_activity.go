@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionActivityRouteKey = fmt.Sprintf("GET /{%s}/activity", NodeIDPathParamKey)
+
+// minActivityRole is the role needed to see who changed what in a collection.
+const minActivityRole = role.Owner
+
+// GetCollectionActivity returns the activity log of a collection, most recent first.
+func GetCollectionActivity(ctx context.Context, params Params) (dto.GetCollectionActivityResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionActivityResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	limit, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "limit", 0, DefaultGetCollectionsLimit)
+	if apiErr != nil {
+		return dto.GetCollectionActivityResponse{}, apiErr
+	}
+	offset, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "offset", 0, DefaultGetCollectionsOffset)
+	if apiErr != nil {
+		return dto.GetCollectionActivityResponse{}, apiErr
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionActivityResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionActivityResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+	if !collection.UserRole.Implies(minActivityRole) {
+		return dto.GetCollectionActivityResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("activity of collection %s not available; requires user role: %s",
+				nodeID,
+				minActivityRole),
+		)
+	}
+
+	storeResp, err := params.Container.CollectionsStore().GetCollectionEvents(ctx, collection.ID, limit, offset)
+	if err != nil {
+		return dto.GetCollectionActivityResponse{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up activity of collection %s", nodeID),
+			err)
+	}
+
+	response := dto.GetCollectionActivityResponse{
+		Limit:      limit,
+		Offset:     offset,
+		TotalCount: storeResp.TotalCount,
+	}
+	for _, event := range storeResp.Events {
+		response.Events = append(response.Events, ToDTOCollectionEvent(event))
+	}
+	return response, nil
+}
+
+func NewGetCollectionActivityRouteHandler() Handler[dto.GetCollectionActivityResponse] {
+	return Handler[dto.GetCollectionActivityResponse]{
+		HandleFunc:        GetCollectionActivity,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+func ToDTOCollectionEvent(event collections.Event) dto.CollectionEvent {
+	return dto.CollectionEvent{
+		Type:      string(event.Type),
+		User:      ToDTOActor(event.User),
+		Diff:      event.Diff,
+		CreatedAt: event.CreatedAt,
+	}
+}
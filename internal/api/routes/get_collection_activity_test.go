@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestHandleGetCollectionActivity tests that run the Handle wrapper around GetCollectionActivity
+func TestHandleGetCollectionActivity(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"get collection activity", testHandleGetCollectionActivity},
+		{"get collection activity, no events", testHandleGetCollectionActivityEmpty},
+		{"get collection activity, authorization", testHandleGetCollectionActivityAuthz},
+		{"get collection activity, bad limit", testHandleGetCollectionActivityBadLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testHandleGetCollectionActivity(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner)
+
+	firstName := uuid.NewString()
+	renamedAt := time.Now().UTC().Truncate(time.Second)
+	storeEvents := []collections.Event{
+		{
+			ID:        2,
+			Type:      collections.RenamedEvent,
+			User:      &collections.Actor{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, FirstName: &firstName},
+			Diff:      json.RawMessage(`{"name":{"old":"before","new":"after"}}`),
+			CreatedAt: renamedAt,
+		},
+		{
+			ID:        1,
+			Type:      collections.CreatedEvent,
+			Diff:      json.RawMessage(`{"name":{"old":null,"new":"before"}}`),
+			CreatedAt: renamedAt.Add(-time.Hour),
+		},
+	}
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetCollectionEventsFunc(func(_ context.Context, collectionID int64, limit int, offset int) (collections.GetCollectionEventsResponse, error) {
+			require.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, 2, limit)
+			assert.Equal(t, 4, offset)
+			return collections.GetCollectionEventsResponse{Limit: limit, Offset: offset, Events: storeEvents, TotalCount: 6}, nil
+		})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionActivityRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithIntQueryParam("limit", 2).
+			WithIntQueryParam("offset", 4).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewGetCollectionActivityRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response dto.GetCollectionActivityResponse
+	require.NoError(t, json.Unmarshal([]byte(resp.Body), &response))
+	assert.Equal(t, 2, response.Limit)
+	assert.Equal(t, 4, response.Offset)
+	assert.Equal(t, 6, response.TotalCount)
+	require.Len(t, response.Events, 2)
+
+	renamed := response.Events[0]
+	assert.Equal(t, string(collections.RenamedEvent), renamed.Type)
+	require.NotNil(t, renamed.User)
+	assert.Equal(t, callingUser.NodeID, renamed.User.UserNodeID)
+	assert.Equal(t, firstName, renamed.User.FirstName)
+	assert.Empty(t, renamed.User.LastName)
+	assert.JSONEq(t, `{"name":{"old":"before","new":"after"}}`, string(renamed.Diff))
+	assert.True(t, renamedAt.Equal(renamed.CreatedAt))
+
+	created := response.Events[1]
+	assert.Equal(t, string(collections.CreatedEvent), created.Type)
+	assert.Nil(t, created.User)
+	assert.NotContains(t, resp.Body, `"user":null`)
+}
+
+func testHandleGetCollectionActivityEmpty(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetCollectionEventsFunc(func(_ context.Context, _ int64, limit int, offset int) (collections.GetCollectionEventsResponse, error) {
+			assert.Equal(t, DefaultGetCollectionsLimit, limit)
+			assert.Equal(t, DefaultGetCollectionsOffset, offset)
+			return collections.GetCollectionEventsResponse{Limit: limit, Offset: offset}, nil
+		})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionActivityRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewGetCollectionActivityRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Body, `"events":[]`)
+}
+
+func testHandleGetCollectionActivityAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, tooLowPerm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer} {
+		t.Run(tooLowPerm.String(), func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tooLowPerm)
+
+			// No GetCollectionEventsFunc since we should fail before looking up events
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionActivityRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewGetCollectionActivityRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
+
+func testHandleGetCollectionActivityBadLimit(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionActivityRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, uuid.NewString()).
+			WithIntQueryParam("limit", -1).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mocks.NewCollectionsStore()),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewGetCollectionActivityRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
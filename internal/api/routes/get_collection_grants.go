@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionGrantsRouteKey = fmt.Sprintf("GET /{%s}/grants", NodeIDPathParamKey)
+
+// GetCollectionGrants returns the teams and organizations with a role on the collection.
+// Any user with a role on the collection can see them.
+func GetCollectionGrants(ctx context.Context, params Params) (dto.GetCollectionGrantsResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionGrantsResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionGrantsResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionGrantsResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	grants, err := params.Container.CollectionsStore().GetCollectionGrants(ctx, collection.ID)
+	if err != nil {
+		return dto.GetCollectionGrantsResponse{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up grants on collection %s", nodeID),
+			err)
+	}
+
+	return ToDTOCollectionGrants(grants), nil
+}
+
+func NewGetCollectionGrantsRouteHandler() Handler[dto.GetCollectionGrantsResponse] {
+	return Handler[dto.GetCollectionGrantsResponse]{
+		HandleFunc:        GetCollectionGrants,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionMembersRouteKey = fmt.Sprintf("GET /{%s}/members", NodeIDPathParamKey)
+
+// GetCollectionMembers returns the members of the collection. Any user with a role on the collection can see its members.
+func GetCollectionMembers(ctx context.Context, params Params) (dto.GetCollectionMembersResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionMembersResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionMembersResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	members, err := params.Container.CollectionsStore().GetCollectionMembers(ctx, collection.ID)
+	if err != nil {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up members of collection %s", nodeID),
+			err)
+	}
+
+	return ToDTOCollectionMembers(members), nil
+}
+
+func NewGetCollectionMembersRouteHandler() Handler[dto.GetCollectionMembersResponse] {
+	return Handler[dto.GetCollectionMembersResponse]{
+		HandleFunc:        GetCollectionMembers,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestGetCollectionMembers(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"get collection members", testGetCollectionMembers},
+		{"get collection members, non-member", testGetCollectionMembersNonMember},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newGetCollectionMembersParams(t *testing.T, callingUser userstest.User, collectionNodeID string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionMembersRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testGetCollectionMembers(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, owner)
+	guest := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, guest)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*guest.ID, pgdb.Guest)
+	expectationDB.CreateCollection(ctx, t, collection)
+
+	// even a guest can see the members
+	response, err := GetCollectionMembers(ctx, newGetCollectionMembersParams(t, guest, *collection.NodeID))
+	require.NoError(t, err)
+
+	require.Len(t, response.Members, 2)
+
+	assert.Equal(t, owner.NodeID, response.Members[0].UserNodeID)
+	assert.Equal(t, owner.GetFirstName(), response.Members[0].FirstName)
+	assert.Equal(t, owner.GetLastName(), response.Members[0].LastName)
+	assert.Equal(t, role.Owner.String(), response.Members[0].Role)
+
+	assert.Equal(t, guest.NodeID, response.Members[1].UserNodeID)
+	assert.Equal(t, guest.GetFirstName(), response.Members[1].FirstName)
+	assert.Equal(t, guest.GetLastName(), response.Members[1].LastName)
+	assert.Equal(t, role.Guest.String(), response.Members[1].Role)
+}
+
+func testGetCollectionMembersNonMember(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, collection)
+
+	_, err := GetCollectionMembers(ctx, newGetCollectionMembersParams(t, nonMember, *collection.NodeID))
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionSectionsRouteKey = fmt.Sprintf("GET /{%s}/sections", NodeIDPathParamKey)
+
+// GetCollectionSections returns the sections of the collection in order. Any user with a role on the collection can see its sections.
+func GetCollectionSections(ctx context.Context, params Params) (dto.GetCollectionSectionsResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionSectionsResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionSectionsResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionSectionsResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	return dto.GetCollectionSectionsResponse{Sections: ToDTOCollectionSections(collection.Sections)}, nil
+}
+
+func NewGetCollectionSectionsRouteHandler() Handler[dto.GetCollectionSectionsResponse] {
+	return Handler[dto.GetCollectionSectionsResponse]{
+		HandleFunc:        GetCollectionSections,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
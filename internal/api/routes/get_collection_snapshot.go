@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionSnapshotRouteKey = fmt.Sprintf("GET /{%s}/versions/{%s}", NodeIDPathParamKey, SnapshotIDPathParamKey)
+
+// GetCollectionSnapshot returns a snapshot of the collection, including its saved state.
+// Any user with a role on the collection can see its snapshots.
+func GetCollectionSnapshot(ctx context.Context, params Params) (dto.CollectionSnapshot, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionSnapshot{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	snapshotID, err := snapshotIDPathParam(params)
+	if err != nil {
+		return dto.CollectionSnapshot{}, err
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.Int64(SnapshotIDPathParamKey, snapshotID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSnapshot{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSnapshot{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	snapshot, err := collectionsStore.GetSnapshot(ctx, collection.ID, snapshotID)
+	if err != nil {
+		if errors.Is(err, collections.ErrSnapshotNotFound) {
+			return dto.CollectionSnapshot{}, NewSnapshotNotFoundError(nodeID, snapshotID)
+		}
+		return dto.CollectionSnapshot{}, apierrors.NewInternalServerError("error querying store for snapshot", err)
+	}
+
+	return ToDTOCollectionSnapshot(snapshot), nil
+}
+
+func NewGetCollectionSnapshotRouteHandler() Handler[dto.CollectionSnapshot] {
+	return Handler[dto.CollectionSnapshot]{
+		HandleFunc:        GetCollectionSnapshot,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+var GetCollectionSnapshotDiffRouteKey = fmt.Sprintf("GET /{%s}/versions/{%s}/diff", NodeIDPathParamKey, SnapshotIDPathParamKey)
+
+// DiffToQueryParamKey is the optional query param naming the snapshot to compare against.
+// If it is missing, the snapshot is compared against the current state of the collection.
+const DiffToQueryParamKey = "to"
+
+// GetCollectionSnapshotDiff returns the changes from a snapshot to either another snapshot or the current state of the collection.
+// Any user with a role on the collection can see its snapshots.
+func GetCollectionSnapshotDiff(ctx context.Context, params Params) (dto.CollectionSnapshotDiff, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionSnapshotDiff{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	fromID, err := snapshotIDPathParam(params)
+	if err != nil {
+		return dto.CollectionSnapshotDiff{}, err
+	}
+	var toID *int64
+	if value, present := params.Request.QueryStringParameters[DiffToQueryParamKey]; present {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return dto.CollectionSnapshotDiff{}, apierrors.NewBadRequestErrorWithCause(fmt.Sprintf("value of [%s] must be an integer", DiffToQueryParamKey), err)
+		}
+		toID = &id
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.Int64(SnapshotIDPathParamKey, fromID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSnapshotDiff{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSnapshotDiff{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	getState := func(snapshotID int64) (collections.SnapshotState, error) {
+		snapshot, err := collectionsStore.GetSnapshot(ctx, collection.ID, snapshotID)
+		if err != nil {
+			if errors.Is(err, collections.ErrSnapshotNotFound) {
+				return collections.SnapshotState{}, NewSnapshotNotFoundError(nodeID, snapshotID)
+			}
+			return collections.SnapshotState{}, apierrors.NewInternalServerError("error querying store for snapshot", err)
+		}
+		return snapshot.State, nil
+	}
+
+	fromState, err := getState(fromID)
+	if err != nil {
+		return dto.CollectionSnapshotDiff{}, err
+	}
+	toState := collection.SnapshotState()
+	if toID != nil {
+		if toState, err = getState(*toID); err != nil {
+			return dto.CollectionSnapshotDiff{}, err
+		}
+	}
+
+	diff := DiffSnapshotStates(fromState, toState)
+	diff.From = fromID
+	diff.To = toID
+	return diff, nil
+}
+
+func NewGetCollectionSnapshotDiffRouteHandler() Handler[dto.CollectionSnapshotDiff] {
+	return Handler[dto.CollectionSnapshotDiff]{
+		HandleFunc:        GetCollectionSnapshotDiff,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetCollectionSnapshotsRouteKey = fmt.Sprintf("GET /{%s}/versions", NodeIDPathParamKey)
+
+// GetCollectionSnapshots returns the snapshots of the collection, most recent first. Any user with a role on the collection can see its snapshots.
+func GetCollectionSnapshots(ctx context.Context, params Params) (dto.GetCollectionSnapshotsResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionSnapshotsResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	limit, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "limit", 0, DefaultGetCollectionsLimit)
+	if apiErr != nil {
+		return dto.GetCollectionSnapshotsResponse{}, apiErr
+	}
+	offset, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "offset", 0, DefaultGetCollectionsOffset)
+	if apiErr != nil {
+		return dto.GetCollectionSnapshotsResponse{}, apiErr
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionSnapshotsResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionSnapshotsResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	storeResp, err := params.Container.CollectionsStore().GetSnapshots(ctx, collection.ID, limit, offset)
+	if err != nil {
+		return dto.GetCollectionSnapshotsResponse{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up snapshots of collection %s", nodeID),
+			err)
+	}
+
+	response := dto.GetCollectionSnapshotsResponse{
+		Limit:      limit,
+		Offset:     offset,
+		TotalCount: storeResp.TotalCount,
+	}
+	for _, summary := range storeResp.Snapshots {
+		response.Snapshots = append(response.Snapshots, ToDTOCollectionSnapshotSummary(summary))
+	}
+	return response, nil
+}
+
+func NewGetCollectionSnapshotsRouteHandler() Handler[dto.GetCollectionSnapshotsResponse] {
+	return Handler[dto.GetCollectionSnapshotsResponse]{
+		HandleFunc:        GetCollectionSnapshots,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/dto"
@@ -31,6 +32,7 @@ func TestGetCollection(t *testing.T) {
 		{"get collection, none", testGetCollectionNone},
 		{"get collection", testGetCollection},
 		{"get collection with tombstone", testGetCollectionTombstone},
+		{"get collection with external datasets", testGetCollectionExternalDatasets},
 		{"get collection should return Publication if a publish status exists", testGetCollectionPublishStatus},
 		{"get collection on draft collection should return correct Publication field if includePublishedDataset=true", testGetCollectionIncludePublishedDatasetDraft},
 		{"get collection on published collection should return correct Publication field if includePublishedDataset=true", testGetCollectionIncludePublishedDatasetPublished},
@@ -264,6 +266,75 @@ func testGetCollectionTombstone(t *testing.T, expectationDB *fixtures.Expectatio
 
 }
 
+func testGetCollectionExternalDatasets(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	callingUser := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, callingUser)
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	expectedPublicDataset := expectedDatasets.NewPublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	expectedExternalDataset := expectedExternalDatasets.NewResolved()
+	// resolved when added to the collection, but no longer
+	noLongerResolvedDOI := apitest.NewExternalDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*callingUser.ID, pgdb.Owner).
+		WithExternalDatasets(expectedExternalDataset).
+		WithPublicDatasets(expectedPublicDataset).
+		WithDOIs(noLongerResolvedDOI)
+	expectationDB.CreateCollection(ctx, t, expectedCollection)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	mockResolverServer := httptest.NewServer(mocks.NewDOIResolverMux(t).WithExternalDatasets(expectedExternalDataset))
+	defer mockResolverServer.Close()
+
+	userClaims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(mockDiscoverServer.URL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
+		WithHTTPTestDiscover(mockDiscoverServer.URL).
+		WithHTTPTestExternalDOI(mockResolverServer.URL)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionRouteKey).
+			WithClaims(userClaims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &userClaims,
+	}
+	resp, err := GetCollection(ctx, params)
+	require.NoError(t, err)
+
+	assertEqualExpectedCollectionSummary(t, expectedCollection, resp.CollectionSummary, expectedDatasets)
+
+	require.Len(t, resp.Datasets, 3)
+	// should be in same order that the DOIs were added to the ExpectedCollection
+	var actualExternalDataset dto.ExternalDataset
+	assert.False(t, resp.Datasets[0].Problem)
+	apitest.RequireAsExternalDataset(t, resp.Datasets[0], &actualExternalDataset)
+	assert.Equal(t, expectedExternalDataset, actualExternalDataset)
+
+	var actualPublicDataset dto.PublicDataset
+	apitest.RequireAsPennsieveDataset(t, resp.Datasets[1], &actualPublicDataset)
+	assert.Equal(t, expectedPublicDataset, actualPublicDataset)
+
+	var actualUnresolved dto.ExternalDataset
+	assert.True(t, resp.Datasets[2].Problem)
+	apitest.RequireAsExternalDataset(t, resp.Datasets[2], &actualUnresolved)
+	assert.Equal(t, dto.ExternalDataset{DOI: noLongerResolvedDOI.Value}, actualUnresolved)
+}
+
 func testGetCollectionPublishStatus(t *testing.T, expectationDB *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
@@ -458,6 +529,18 @@ func TestHandleGetCollection(t *testing.T) {
 			"return empty arrays in PublicDatasets instead of nulls",
 			testHandleGetCollectionEmptyArraysInPublicDataset,
 		},
+		{
+			"return ETag header",
+			testHandleGetCollectionETag,
+		},
+		{
+			"return Warning header if Discover info is stale",
+			testHandleGetCollectionStale,
+		},
+		{
+			"return external datasets as unresolved if the resolver fails",
+			testHandleGetCollectionResolverError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -538,3 +621,104 @@ func testHandleGetCollectionEmptyArraysInPublicDataset(t *testing.T) {
 	assert.Contains(t, response.Body, `"modelCount":[]`)
 
 }
+
+func testHandleGetCollectionETag(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Read).WithVersion(7)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewGetCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"7"`, response.Headers["etag"])
+	assert.Equal(t, "application/json", response.Headers["content-type"])
+	assert.NotContains(t, response.Headers, "warning")
+}
+
+func testHandleGetCollectionStale(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDOI := apitest.NewPennsieveDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Read).WithVersion(3).WithDOIs(expectedDOI)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	mockDiscover := mocks.NewDiscover().WithGetDatasetsByDOIFunc(func(ctx context.Context, dois []string) (service.DatasetsByDOIResponse, error) {
+		return service.DatasetsByDOIResponse{
+			Published: map[string]dto.PublicDataset{
+				expectedDOI.Value: apitest.NewPublicDataset(expectedDOI.Value, apitest.NewBanner()),
+			},
+			Stale: []string{expectedDOI.Value},
+		}, nil
+	})
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore).WithDiscover(mockDiscover),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewGetCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, dto.StaleWarning, response.Headers["warning"])
+	assert.Equal(t, `"3"`, response.Headers["etag"])
+}
+
+func testHandleGetCollectionResolverError(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	externalDOI := apitest.NewExternalDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Read).WithDOIs(externalDOI)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	mockExternalDOI := mocks.NewExternalDOI().WithResolveDOIsFunc(func(_ context.Context, dois []string) (service.ResolveDOIsResponse, error) {
+		assert.Equal(t, []string{externalDOI.Value}, dois)
+		return service.ResolveDOIsResponse{}, errors.New("doi.org is down")
+	})
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore).WithExternalDOI(mockExternalDOI),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	resp, err := GetCollection(ctx, params)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Datasets, 1)
+	assert.True(t, resp.Datasets[0].Problem)
+	var actualUnresolved dto.ExternalDataset
+	apitest.RequireAsExternalDataset(t, resp.Datasets[0], &actualUnresolved)
+	assert.Equal(t, dto.ExternalDataset{DOI: externalDOI.Value}, actualUnresolved)
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
 	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -18,6 +23,17 @@ const GetCollectionsRouteKey = "GET /"
 const DefaultGetCollectionsLimit = 10
 const DefaultGetCollectionsOffset = 0
 
+const (
+	TextSearchQueryParamKey        = "q"
+	TagQueryParamKey               = "tag"
+	LicenseQueryParamKey           = "license"
+	PublicationStatusQueryParamKey = "publicationStatus"
+	RoleQueryParamKey              = "role"
+	SortQueryParamKey              = "sort"
+	SortOrderQueryParamKey         = "order"
+	CursorQueryParamKey            = "cursor"
+)
+
 func GetCollections(ctx context.Context, params Params) (dto.GetCollectionsResponse, error) {
 	// any errors returned should be *apierrors.Error for correct status codes and better logging
 	limit, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "limit", 0, DefaultGetCollectionsLimit)
@@ -32,6 +48,14 @@ func GetCollections(ctx context.Context, params Params) (dto.GetCollectionsRespo
 	if apiErr != nil {
 		return dto.GetCollectionsResponse{}, apiErr
 	}
+	storeQuery, apiErr := GetCollectionsQueryFromParams(params.Request.QueryStringParameters)
+	if apiErr != nil {
+		return dto.GetCollectionsResponse{}, apiErr
+	}
+	if storeQuery.After != nil && offset != 0 {
+		return dto.GetCollectionsResponse{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("[%s] and [%s] cannot be used together", CursorQueryParamKey, "offset"))
+	}
 	response := dto.GetCollectionsResponse{
 		Limit:  limit,
 		Offset: offset,
@@ -43,12 +67,20 @@ func GetCollections(ctx context.Context, params Params) (dto.GetCollectionsRespo
 
 	// GetCollections only returns collections where the given user has >= Guest permission,
 	// so no further authz is required for this route.
-	storeResp, err := collectionsStore.GetCollections(ctx, userClaim.Id, limit, offset)
+	storeResp, err := collectionsStore.GetCollections(ctx, userClaim.Id, limit, offset, storeQuery)
 	if err != nil {
 		return dto.GetCollectionsResponse{}, apierrors.NewInternalServerError(fmt.Sprintf("error getting collections for user %s", userClaim.NodeId), err)
 	}
 
 	response.TotalCount = storeResp.TotalCount
+	// A non-zero offset means the caller is paging by offset, so there is no cursor to continue from
+	if storeResp.NextCursor != nil && offset == 0 {
+		nextCursor, err := EncodeCursor(*storeResp.NextCursor)
+		if err != nil {
+			return dto.GetCollectionsResponse{}, apierrors.NewInternalServerError("error creating next cursor", err)
+		}
+		response.NextCursor = nextCursor
+	}
 
 	// Gather all the banner DOIs to eventually look up banners in Discover
 	var dois []string
@@ -62,7 +94,7 @@ func GetCollections(ctx context.Context, params Params) (dto.GetCollectionsRespo
 	var doiToPublicDataset map[string]dto.PublicDataset
 	pennsieveDOIs, _ := CategorizeDOIs(params.Config.PennsieveConfig.DOIPrefix, dois)
 	if len(pennsieveDOIs) > 0 {
-		doiToPublicDataset, err = fetchPennsieveDatasets(ctx, params.Container.Discover(), pennsieveDOIs)
+		doiToPublicDataset, response.Stale, err = fetchPennsieveDatasets(ctx, params.Container.Discover(), pennsieveDOIs)
 		if err != nil {
 			return dto.GetCollectionsResponse{}, err
 		}
@@ -104,6 +136,82 @@ func GetCollections(ctx context.Context, params Params) (dto.GetCollectionsRespo
 	return response, nil
 }
 
+// GetCollectionsQueryFromParams builds the store query for the search, filter, and sort query params of GET /.
+func GetCollectionsQueryFromParams(queryParams map[string]string) (collections.GetCollectionsQuery, error) {
+	query := collections.GetCollectionsQuery{
+		Text: strings.TrimSpace(queryParams[TextSearchQueryParamKey]),
+		Tag:  queryParams[TagQueryParamKey],
+	}
+
+	if license, present := queryParams[LicenseQueryParamKey]; present {
+		if err := validate.License(&license, true); err != nil {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestErrorWithCause(fmt.Sprintf("invalid value of [%s]", LicenseQueryParamKey), err)
+		}
+		query.License = license
+	}
+
+	if statusValue, present := queryParams[PublicationStatusQueryParamKey]; present {
+		idx := slices.IndexFunc(publishing.Statuses, func(status publishing.Status) bool {
+			return strings.EqualFold(string(status), statusValue)
+		})
+		if idx < 0 {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestError(
+				fmt.Sprintf("value of [%s] must be one of %v: %q", PublicationStatusQueryParamKey, publishing.Statuses, statusValue))
+		}
+		status := publishing.Statuses[idx]
+		query.PublicationStatus = &status
+	}
+
+	if roleValue, present := queryParams[RoleQueryParamKey]; present {
+		queryRole, ok := role.RoleFromString(roleValue)
+		if !ok || queryRole == role.None {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestError(
+				fmt.Sprintf("value of [%s] must be a collection role: %q", RoleQueryParamKey, roleValue))
+		}
+		query.Role = &queryRole
+	}
+
+	if sortValue, present := queryParams[SortQueryParamKey]; present {
+		idx := slices.Index(collections.SortFields, collections.SortField(sortValue))
+		if idx < 0 {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestError(
+				fmt.Sprintf("value of [%s] must be one of %v: %q", SortQueryParamKey, collections.SortFields, sortValue))
+		}
+		query.SortBy = collections.SortFields[idx]
+	}
+
+	if orderValue, present := queryParams[SortOrderQueryParamKey]; present {
+		switch direction := collections.SortDirection(strings.ToLower(orderValue)); direction {
+		case collections.Ascending, collections.Descending:
+			query.SortDirection = direction
+		default:
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestError(
+				fmt.Sprintf("value of [%s] must be one of [%s %s]: %q", SortOrderQueryParamKey, collections.Ascending, collections.Descending, orderValue))
+		}
+	}
+
+	// The cursor records the sort it was created with, so sort and order may be left off when paging.
+	if encodedCursor, present := queryParams[CursorQueryParamKey]; present {
+		cursor, err := DecodeCursor(encodedCursor)
+		if err != nil {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestErrorWithCause(fmt.Sprintf("invalid value of [%s]", CursorQueryParamKey), err)
+		}
+		if _, present := queryParams[SortQueryParamKey]; !present {
+			query.SortBy = cursor.SortBy
+		}
+		if _, present := queryParams[SortOrderQueryParamKey]; !present {
+			query.SortDirection = cursor.SortDirection
+		}
+		if sortBy, sortDirection := query.EffectiveSort(); sortBy != cursor.SortBy || sortDirection != cursor.SortDirection {
+			return collections.GetCollectionsQuery{}, apierrors.NewBadRequestError(
+				fmt.Sprintf("[%s] was created for a different [%s] or [%s]", CursorQueryParamKey, SortQueryParamKey, SortOrderQueryParamKey))
+		}
+		query.After = &cursor
+	}
+
+	return query, nil
+}
+
 func NewGetCollectionsRouteHandler() Handler[dto.GetCollectionsResponse] {
 	return Handler[dto.GetCollectionsResponse]{
 		HandleFunc:        GetCollections,
@@ -112,26 +220,29 @@ func NewGetCollectionsRouteHandler() Handler[dto.GetCollectionsResponse] {
 	}
 }
 
-func fetchPennsieveDatasets(ctx context.Context, discoverService service.Discover, pennsieveDOIs []string) (map[string]dto.PublicDataset, error) {
-	const (
-		batchSize  = 80 // how many DOIs per request. >= 90 leads to URL-too-long errors. See discover_benchmark_test.go
-		numWorkers = 3  // how many concurrent requests
-	)
+// discoverDOIBatchSize is how many DOIs to send in one Discover request. >= 90 leads to URL-too-long errors.
+// See discover_benchmark_test.go
+const discoverDOIBatchSize = 80
+
+// fetchPennsieveDatasets returns the published datasets of the given DOIs, and whether any of them came from
+// expired cache entries.
+func fetchPennsieveDatasets(ctx context.Context, discoverService service.Discover, pennsieveDOIs []string) (map[string]dto.PublicDataset, bool, error) {
+	const numWorkers = 3 // how many concurrent requests
 
 	// In reality, len(pennsieveDOIs) <= 40 == FE page size * 4 banner DOIs per collection
 	// Testing in discover_benchmark_test.go showed not much point in doing concurrent batches in this case.
-	if len(pennsieveDOIs) <= batchSize {
+	if len(pennsieveDOIs) <= discoverDOIBatchSize {
 		discoverResp, err := discoverService.GetDatasetsByDOI(ctx, pennsieveDOIs)
 		if err != nil {
-			return nil, apierrors.NewInternalServerError("error looking up Datasets in Discover by DOI", err)
+			return nil, false, apierrors.NewInternalServerError("error looking up Datasets in Discover by DOI", err)
 		}
-		return discoverResp.Published, nil
+		return discoverResp.Published, len(discoverResp.Stale) > 0, nil
 	}
 
 	// But we do get URL-to-long errors if we request 90 or more DOIs at a time. So
 	// to keep things working if someone scripts calls with larger page sizes, we'll
 	// batch things here.
-	return fetchPennsieveDatasetsInBatches(ctx, discoverService, pennsieveDOIs, batchSize, numWorkers)
+	return fetchPennsieveDatasetsInBatches(ctx, discoverService, pennsieveDOIs, discoverDOIBatchSize, numWorkers)
 }
 
 // fetchPennsieveDatasetsInBatches fetches datasets by DOI in concurrent batches.
@@ -143,11 +254,12 @@ func fetchPennsieveDatasetsInBatches(
 	dois []string,
 	batchSize int,
 	numWorkers int,
-) (map[string]dto.PublicDataset, error) {
+) (map[string]dto.PublicDataset, bool, error) {
 
 	type batchResult struct {
-		data map[string]dto.PublicDataset
-		err  error
+		data  map[string]dto.PublicDataset
+		stale bool
+		err   error
 	}
 
 	jobs := make(chan []string, numWorkers)
@@ -174,7 +286,7 @@ func fetchPennsieveDatasetsInBatches(
 					results <- batchResult{err: fmt.Errorf("fetch batch failed: %w", err)}
 					continue
 				}
-				results <- batchResult{data: resp.Published}
+				results <- batchResult{data: resp.Published, stale: len(resp.Stale) > 0}
 			}
 		}()
 	}
@@ -196,17 +308,19 @@ func fetchPennsieveDatasetsInBatches(
 	}()
 
 	doiToDataset := make(map[string]dto.PublicDataset)
+	stale := false
 	for res := range results {
 		if res.err != nil {
 			cancel()
-			return nil, apierrors.NewInternalServerError("error fetching datasets from Discover by DOI", res.err)
+			return nil, false, apierrors.NewInternalServerError("error fetching datasets from Discover by DOI", res.err)
 		}
 		for k, v := range res.data {
 			doiToDataset[k] = v
 		}
+		stale = stale || res.stale
 	}
 
-	return doiToDataset, nil
+	return doiToDataset, stale, nil
 }
 
 func fetchCollectionPublishStatuses(ctx context.Context, internalDiscover service.InternalDiscover, summaries []collections.CollectionSummary, numWorkers int) (map[string]service.DatasetPublishStatusResponse, error) {
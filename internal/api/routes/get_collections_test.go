@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/config"
 	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/service/jwtdiscover"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
@@ -423,6 +424,26 @@ func TestHandleGetCollections(t *testing.T) {
 			"handle batched Discover GetDatasetsByDOIs calls correctly",
 			testHandleGetCollectionsLargePageSize,
 		},
+		{
+			"pass search, filter, and sort params to the store",
+			testHandleGetCollectionsQuery,
+		},
+		{
+			"reject invalid search, filter, and sort params",
+			testHandleGetCollectionsBadQuery,
+		},
+		{
+			"pass cursor to store and return next cursor",
+			testHandleGetCollectionsCursor,
+		},
+		{
+			"not return next cursor when paging by offset",
+			testHandleGetCollectionsOffsetNoCursor,
+		},
+		{
+			"reject cursor with offset or a different sort",
+			testHandleGetCollectionsBadCursor,
+		},
 	}
 
 	for _, tt := range tests {
@@ -437,7 +458,7 @@ func testHandleGetCollectionsEmptyCollectionsArray(t *testing.T) {
 	callingUser := userstest.SeedUser1
 
 	mockCollectionStore := mocks.NewCollectionsStore().
-		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error) {
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, _ collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
 			return collections.GetCollectionsResponse{
 				Limit:  DefaultGetCollectionsLimit,
 				Offset: DefaultGetCollectionsOffset,
@@ -471,7 +492,7 @@ func testHandleGetCollectionsEmptyBannersArray(t *testing.T) {
 	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(callingUser.ID, pgdb.Owner)
 
 	mockCollectionStore := mocks.NewCollectionsStore().
-		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error) {
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, _ collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
 			return collections.GetCollectionsResponse{
 				Limit:      DefaultGetCollectionsLimit,
 				Offset:     DefaultGetCollectionsOffset,
@@ -537,7 +558,7 @@ func testHandleGetCollectionsLargePageSize(t *testing.T) {
 	}
 
 	mockCollectionStore := mocks.NewCollectionsStore().
-		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error) {
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, _ collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
 			return collections.GetCollectionsResponse{
 				Limit:       largePageSize,
 				Offset:      DefaultGetCollectionsOffset,
@@ -582,3 +603,197 @@ func testHandleGetCollectionsLargePageSize(t *testing.T) {
 	}
 
 }
+
+func testHandleGetCollectionsQuery(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedStatus := publishing.CompletedStatus
+	expectedRole := role.Manager
+	expectedQuery := collections.GetCollectionsQuery{
+		Text:              "heart",
+		Tag:               "cardiac",
+		License:           "MIT",
+		PublicationStatus: &expectedStatus,
+		Role:              &expectedRole,
+		SortBy:            collections.SortByUpdatedAt,
+		SortDirection:     collections.Descending,
+	}
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, query collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
+			assert.Equal(t, expectedQuery, query)
+			return collections.GetCollectionsResponse{Limit: limit, Offset: offset}, nil
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionsRouteKey).
+			WithClaims(claims).
+			WithQueryParam(TextSearchQueryParamKey, " heart ").
+			WithQueryParam(TagQueryParamKey, "cardiac").
+			WithQueryParam(LicenseQueryParamKey, "MIT").
+			WithQueryParam(PublicationStatusQueryParamKey, "completed").
+			WithQueryParam(RoleQueryParamKey, "manager").
+			WithQueryParam(SortQueryParamKey, "updatedAt").
+			WithQueryParam(SortOrderQueryParamKey, "DESC").
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewGetCollectionsRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func testHandleGetCollectionsBadQuery(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, tt := range []struct {
+		key   string
+		value string
+	}{
+		{LicenseQueryParamKey, "not a license"},
+		{PublicationStatusQueryParamKey, "Published"},
+		{RoleQueryParamKey, "none"},
+		{RoleQueryParamKey, "admin"},
+		{SortQueryParamKey, "id"},
+		{SortQueryParamKey, "nodeId"},
+		{SortOrderQueryParamKey, "up"},
+	} {
+		t.Run(fmt.Sprintf("%s=%s", tt.key, tt.value), func(t *testing.T) {
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionsRouteKey).
+					WithClaims(claims).
+					WithQueryParam(tt.key, tt.value).
+					Build(),
+				// no mock functions set; store should not be called
+				Container: apitest.NewTestContainer().WithCollectionsStore(mocks.NewCollectionsStore()),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+			response, err := Handle(ctx, NewGetCollectionsRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+			assert.Contains(t, response.Body, tt.key)
+		})
+	}
+}
+
+func testHandleGetCollectionsCursor(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	cursor := collections.Cursor{SortBy: collections.SortByName, SortDirection: collections.Descending, Key: "m", ID: 12}
+	encodedCursor, err := EncodeCursor(cursor)
+	require.NoError(t, err)
+
+	nextCursor := collections.Cursor{SortBy: collections.SortByName, SortDirection: collections.Descending, Key: "f", ID: 3}
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, query collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
+			// sort and order are taken from the cursor
+			assert.Equal(t, collections.SortByName, query.SortBy)
+			assert.Equal(t, collections.Descending, query.SortDirection)
+			if assert.NotNil(t, query.After) {
+				assert.Equal(t, cursor, *query.After)
+			}
+			assert.Zero(t, offset)
+			return collections.GetCollectionsResponse{Limit: limit, Offset: offset, TotalCount: 20, NextCursor: &nextCursor}, nil
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionsRouteKey).
+			WithClaims(claims).
+			WithQueryParam(CursorQueryParamKey, encodedCursor).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewGetCollectionsRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	actualNextCursor, err := DecodeCursor(responseDTO.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, nextCursor, actualNextCursor)
+}
+
+func testHandleGetCollectionsOffsetNoCursor(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	nextCursor := collections.Cursor{SortBy: collections.SortByName, SortDirection: collections.Ascending, Key: "f", ID: 3}
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionsFunc(func(ctx context.Context, userID int64, limit int, offset int, query collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
+			assert.Nil(t, query.After)
+			assert.Equal(t, 10, offset)
+			return collections.GetCollectionsResponse{Limit: limit, Offset: offset, TotalCount: 30, NextCursor: &nextCursor}, nil
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetCollectionsRouteKey).
+			WithClaims(claims).
+			WithQueryParam("offset", "10").
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewGetCollectionsRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, 10, responseDTO.Offset)
+	assert.Empty(t, responseDTO.NextCursor)
+}
+
+func testHandleGetCollectionsBadCursor(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	encodedCursor, err := EncodeCursor(collections.Cursor{SortBy: collections.SortByName, SortDirection: collections.Ascending, Key: "m", ID: 12})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		scenario string
+		params   map[string]string
+	}{
+		{"cursor and offset", map[string]string{CursorQueryParamKey: encodedCursor, "offset": "10"}},
+		{"different sort", map[string]string{CursorQueryParamKey: encodedCursor, SortQueryParamKey: "size"}},
+		{"different order", map[string]string{CursorQueryParamKey: encodedCursor, SortOrderQueryParamKey: "desc"}},
+		{"garbage", map[string]string{CursorQueryParamKey: "garbage"}},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			requestBuilder := apitest.NewAPIGatewayRequestBuilder(GetCollectionsRouteKey).WithClaims(claims)
+			for key, value := range tt.params {
+				requestBuilder = requestBuilder.WithQueryParam(key, value)
+			}
+			params := Params{
+				Request: requestBuilder.Build(),
+				// no mock functions set; store should not be called
+				Container: apitest.NewTestContainer().WithCollectionsStore(mocks.NewCollectionsStore()),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+			response, err := Handle(ctx, NewGetCollectionsRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var GetPublishStatusRouteKey = fmt.Sprintf("GET /{%s}/publish/status", NodeIDPathParamKey)
+
+// GetPublishStatus returns the progress of the most recent publish job of the collection.
+func GetPublishStatus(ctx context.Context, params Params) (dto.PublishJob, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.PublishJob{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.PublishJob{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.PublishJob{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+	if !collection.UserRole.Implies(minPublishRole) {
+		return dto.PublishJob{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("publish status of collection %s not available; requires user role: %s",
+				nodeID,
+				minPublishRole),
+		)
+	}
+
+	job, err := params.Container.CollectionsStore().GetLatestPublishJob(ctx, collection.ID)
+	if err != nil {
+		if errors.Is(err, collections.ErrPublishJobNotFound) {
+			return dto.PublishJob{}, apierrors.NewError(fmt.Sprintf("no publish job found for collection %s", nodeID), nil, http.StatusNotFound)
+		}
+		return dto.PublishJob{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up publish job of collection %s", nodeID),
+			err)
+	}
+	return ToDTOPublishJob(job), nil
+}
+
+func NewGetPublishStatusRouteHandler() Handler[dto.PublishJob] {
+	return Handler[dto.PublishJob]{
+		HandleFunc:        GetPublishStatus,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestHandleGetPublishStatus tests that run the Handle wrapper around GetPublishStatus
+func TestHandleGetPublishStatus(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"get publish status", testHandleGetPublishStatus},
+		{"get publish status, no publish job", testHandleGetPublishStatusNoJob},
+		{"get publish status, authorization", testHandleGetPublishStatusAuthz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testHandleGetPublishStatus(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner)
+
+	step := publishing.SavingManifestStep
+	jobError := "error publishing manifest"
+	storeJob := collectionstest.NewRunningPublishJob(
+		collectionstest.NewQueuedPublishJob(*expectedCollection.ID, *expectedCollection.NodeID, callingUser.ID, callingUser.NodeID))
+	storeJob.Attempts = 2
+	storeJob.Step = &step
+	storeJob.Error = &jobError
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetLatestPublishJobFunc(func(_ context.Context, collectionID int64) (collections.PublishJob, error) {
+			require.Equal(t, *expectedCollection.ID, collectionID)
+			return storeJob, nil
+		})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetPublishStatusRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewGetPublishStatusRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response dto.PublishJob
+	require.NoError(t, json.Unmarshal([]byte(resp.Body), &response))
+	assert.Equal(t, storeJob.ID, response.JobID)
+	assert.Equal(t, publishing.JobRunningStatus, response.Status)
+	assert.Equal(t, step, response.Step)
+	assert.Equal(t, 2, response.Attempts)
+	assert.Equal(t, jobError, response.Error)
+	assert.Nil(t, response.PublishedDatasetID)
+	assert.NotContains(t, resp.Body, "publishedDatasetId")
+	assert.True(t, storeJob.CreatedAt.Equal(response.CreatedAt))
+}
+
+func testHandleGetPublishStatusNoJob(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetLatestPublishJobFunc(func(_ context.Context, _ int64) (collections.PublishJob, error) {
+			return collections.PublishJob{}, collections.ErrPublishJobNotFound
+		})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetPublishStatusRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewGetPublishStatusRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Contains(t, resp.Body, "no publish job found")
+}
+
+func testHandleGetPublishStatusAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, tooLowPerm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer} {
+		t.Run(tooLowPerm.String(), func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tooLowPerm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(GetPublishStatusRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewGetPublishStatusRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			assert.Equal(t, DefaultErrorResponseHeaders(), resp.Headers)
+		})
+	}
+}
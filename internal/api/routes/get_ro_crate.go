@@ -0,0 +1,116 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+var GetROCrateRouteKey = fmt.Sprintf("GET /{%s}/ro-crate", NodeIDPathParamKey)
+
+// GetROCrate describes a collection as RO-Crate metadata. The description is built the same way
+// as the manifest written when the collection is published, with the collection owner as creator.
+// If the collection has been published, the crate includes its latest DOI and the date it was last published.
+func GetROCrate(ctx context.Context, params Params) (dto.ROCrateResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.ROCrateResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	// GetCollection only returns the collection if the given user has >= Guest permission,
+	// so no further authz is required for this route.
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.ROCrateResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.ROCrateResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	owner, err := params.getCollectionOwner(ctx, collection.ID)
+	if err != nil {
+		return dto.ROCrateResponse{}, err
+	}
+	ownerResp, err := params.Container.UsersStore().GetUser(ctx, owner.UserID)
+	if err != nil {
+		return dto.ROCrateResponse{}, apierrors.NewInternalServerError("error getting collection owner information", err)
+	}
+
+	manifestBuilder := params.newCollectionManifestBuilder(collection, creator(ownerResp))
+	var datePublished *time.Time
+	if publication := collection.Publication; publication != nil && publication.Status == publishing.CompletedStatus {
+		latestDOI, err := params.getLatestDOI(ctx, collection)
+		if err != nil {
+			return dto.ROCrateResponse{}, err
+		}
+		if latestDOI != nil {
+			manifestBuilder.WithID(latestDOI.DOI)
+		}
+		publishStatus, err := params.getDatasetPublishStatus(ctx, collection.ID, collection.NodeID, collection.UserRole)
+		if err != nil {
+			return dto.ROCrateResponse{}, err
+		}
+		datePublished = publishStatus.LastPublishedDate
+	}
+	manifest, err := manifestBuilder.Build()
+	if err != nil {
+		return dto.ROCrateResponse{}, apierrors.NewInternalServerError("error creating manifest for RO-Crate", err)
+	}
+
+	return dto.ROCrateResponse{ROCrate: publishing.NewROCrate(manifest, datePublished)}, nil
+}
+
+// NewGetROCrateRouteHandler has no Headers since the content-type comes from the response.
+func NewGetROCrateRouteHandler() Handler[dto.ROCrateResponse] {
+	return Handler[dto.ROCrateResponse]{
+		HandleFunc:        GetROCrate,
+		SuccessStatusCode: http.StatusOK,
+	}
+}
+
+func (p Params) getCollectionOwner(ctx context.Context, collectionID int64) (collections.CollectionMember, error) {
+	members, err := p.Container.CollectionsStore().GetCollectionMembers(ctx, collectionID)
+	if err != nil {
+		return collections.CollectionMember{}, apierrors.NewInternalServerError("error getting collection members", err)
+	}
+	for _, member := range members {
+		if member.Role == role.Owner {
+			return member, nil
+		}
+	}
+	return collections.CollectionMember{}, apierrors.NewInternalServerError(
+		fmt.Sprintf("collection %d has no owner", collectionID), nil)
+}
+
+// getLatestDOI returns nil if the DOI service has no DOI for the collection.
+func (p Params) getLatestDOI(ctx context.Context, collection collections.GetCollectionResponse) (*dto.GetLatestDOIResponse, error) {
+	doiService, err := p.Container.DOI(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalServerError("error getting DOI service", err)
+	}
+	latestDOI, err := doiService.GetLatestDOI(ctx, collection.ID, collection.NodeID, collection.UserRole)
+	if err != nil {
+		var notFoundErr service.LatestDOINotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+		return nil, apierrors.NewInternalServerError("error calling DOI service", err)
+	}
+	return &latestDOI, nil
+}
@@ -0,0 +1,164 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandleGetROCrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"get RO-Crate should describe a draft collection", testHandleGetROCrateDraft},
+		{"get RO-Crate should include DOI of a published collection", testHandleGetROCratePublished},
+		{"get RO-Crate should return Not Found when collection is not found", testHandleGetROCrateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleGetROCrateParams(callingUser userstest.User, nodeID string, container *apitest.TestContainer) Params {
+	claims := apitest.DefaultClaims(callingUser)
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(GetROCrateRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, nodeID).
+			Build(),
+		Container: container,
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func ownerMembersFunc(t *testing.T, expectedCollection *apitest.ExpectedCollection, owner userstest.User, others ...userstest.User) mocks.GetCollectionMembersFunc {
+	return func(_ context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+		require.Equal(t, *expectedCollection.ID, collectionID)
+		members := []collections.CollectionMember{{UserID: owner.GetID(), UserNodeID: owner.GetNodeID(), Role: role.Owner}}
+		for _, other := range others {
+			members = append(members, collections.CollectionMember{UserID: other.GetID(), UserNodeID: other.GetNodeID(), Role: role.Viewer})
+		}
+		return members, nil
+	}
+}
+
+func unmarshalROCrateRoot(t *testing.T, body string) (publishing.ROCrate, publishing.ROCrateEntity) {
+	var crate publishing.ROCrate
+	require.NoError(t, json.Unmarshal([]byte(body), &crate))
+	for _, entity := range crate.Graph {
+		if entity.ID == publishing.ROCrateRootID {
+			return crate, entity
+		}
+	}
+	require.FailNow(t, "RO-Crate has no root entity")
+	return crate, publishing.ROCrateEntity{}
+}
+
+func testHandleGetROCrateDraft(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	owner := userstest.SeedUser2
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(callingUser.ID, pgdb.Read).
+		WithRandomLicense().
+		WithNTags(2).
+		WithNPennsieveDOIs(2)
+
+	// no DOI service set since draft collections have no DOI to look up
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().
+			WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+			WithGetCollectionMembersFunc(ownerMembersFunc(t, expectedCollection, owner, callingUser))).
+		WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, owner)))
+
+	response, err := Handle(ctx, NewGetROCrateRouteHandler(), newHandleGetROCrateParams(callingUser, *expectedCollection.NodeID, container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, dto.ROCrateContentType, response.Headers["content-type"])
+
+	crate, root := unmarshalROCrateRoot(t, response.Body)
+	assert.Equal(t, publishing.ROCrateContext, crate.Context)
+	assert.Equal(t, expectedCollection.Name, root.Name)
+	assert.Equal(t, expectedCollection.Description, root.Description)
+	assert.Equal(t, expectedCollection.Tags, root.Keywords)
+	assert.Empty(t, root.Identifier)
+	assert.Empty(t, root.DatePublished)
+	assert.Equal(t, []publishing.ROCrateReference{
+		{ID: "https://doi.org/" + expectedCollection.DOIs[0].DOI},
+		{ID: "https://doi.org/" + expectedCollection.DOIs[1].DOI},
+	}, root.HasPart)
+
+	entities := map[string]publishing.ROCrateEntity{}
+	for _, entity := range crate.Graph {
+		entities[entity.ID] = entity
+	}
+	require.NotNil(t, root.License)
+	assert.Equal(t, *expectedCollection.License, entities[root.License.ID].Name)
+	require.Len(t, root.Author, 1)
+	author := entities[root.Author[0].ID]
+	assert.Equal(t, owner.GetFirstName(), author.GivenName)
+	assert.Equal(t, owner.GetLastName(), author.FamilyName)
+}
+
+func testHandleGetROCratePublished(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithNPennsieveDOIs(1)
+	publishStatus := collections.PublishStatus{Status: publishing.CompletedStatus, Type: publishing.PublicationType}
+	collectionDOI := apitest.NewPennsieveDOI().Value
+	lastPublishedDate := time.Now().UTC().AddDate(0, -1, 0)
+	datasetPublishStatus := expectedCollection.DatasetPublishStatusResponse(t)
+	datasetPublishStatus.Status = dto.PublishSucceeded
+	datasetPublishStatus.LastPublishedDate = &lastPublishedDate
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().
+			WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)).
+			WithGetCollectionMembersFunc(ownerMembersFunc(t, expectedCollection, callingUser))).
+		WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))).
+		WithDOI(mocks.NewDOI().WithGetLatestDOIFunc(expectedCollection.GetLatestDOIFunc(t, dto.GetLatestDOIResponse{DOI: collectionDOI}))).
+		WithInternalDiscover(mocks.NewInternalDiscover().WithGetCollectionPublishStatusFunc(expectedCollection.GetCollectionPublishStatusFunc(t, datasetPublishStatus)))
+
+	response, err := Handle(ctx, NewGetROCrateRouteHandler(), newHandleGetROCrateParams(callingUser, *expectedCollection.NodeID, container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	_, root := unmarshalROCrateRoot(t, response.Body)
+	assert.Equal(t, "https://doi.org/"+collectionDOI, root.Identifier)
+	assert.Equal(t, lastPublishedDate.Format(time.DateOnly), root.DatePublished)
+}
+
+func testHandleGetROCrateNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.GetCollectionResponse, error) {
+			return collections.GetCollectionResponse{}, collections.ErrCollectionNotFound
+		}))
+
+	response, err := Handle(ctx, NewGetROCrateRouteHandler(), newHandleGetROCrateParams(userstest.SeedUser1, *apitest.NewExpectedCollection().WithNodeID().NodeID, container))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"log/slog"
+	"net/http"
+)
+
+const GetTrashRouteKey = "GET /trash"
+
+// GetTrash returns the deleted collections that the caller could restore, most recently deleted first.
+func GetTrash(ctx context.Context, params Params) (dto.GetTrashResponse, error) {
+	limit, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "limit", 0, DefaultGetCollectionsLimit)
+	if apiErr != nil {
+		return dto.GetTrashResponse{}, apiErr
+	}
+	offset, apiErr := GetIntQueryParam(params.Request.QueryStringParameters, "offset", 0, DefaultGetCollectionsOffset)
+	if apiErr != nil {
+		return dto.GetTrashResponse{}, apiErr
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String("userNodeId", userClaim.NodeId))
+
+	// GetDeletedCollections only returns collections where the given user has >= minDeleteRole,
+	// so no further authz is required for this route.
+	storeResp, err := params.Container.CollectionsStore().GetDeletedCollections(ctx, userClaim.Id, minDeleteRole, limit, offset)
+	if err != nil {
+		return dto.GetTrashResponse{}, apierrors.NewInternalServerError(fmt.Sprintf("error getting deleted collections for user %s", userClaim.NodeId), err)
+	}
+
+	response := dto.GetTrashResponse{
+		Limit:      limit,
+		Offset:     offset,
+		TotalCount: storeResp.TotalCount,
+	}
+	for _, storeCollection := range storeResp.Collections {
+		response.Collections = append(response.Collections, ToDTODeletedCollection(storeCollection))
+	}
+	return response, nil
+}
+
+func NewGetTrashRouteHandler() Handler[dto.GetTrashResponse] {
+	return Handler[dto.GetTrashResponse]{
+		HandleFunc:        GetTrash,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+func ToDTODeletedCollection(storeCollection collections.DeletedCollection) dto.DeletedCollection {
+	return dto.DeletedCollection{
+		NodeID:      storeCollection.NodeID,
+		Name:        storeCollection.Name,
+		Description: storeCollection.Description,
+		Size:        storeCollection.Size,
+		UserRole:    storeCollection.UserRole.String(),
+		License:     util.SafeDeref(storeCollection.License),
+		Tags:        storeCollection.Tags,
+		DeletedAt:   storeCollection.DeletedAt,
+	}
+}
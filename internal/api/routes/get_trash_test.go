@@ -0,0 +1,120 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestHandleGetTrash(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"return deleted collections", testHandleGetTrash},
+		{"return empty arrays instead of null", testHandleGetTrashEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newGetTrashParams(callingUser userstest.SeedUser, queryParams map[string]string, store *mocks.CollectionsStore) Params {
+	claims := apitest.DefaultClaims(callingUser)
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(GetTrashRouteKey).
+		WithClaims(claims)
+	for key, value := range queryParams {
+		requestBuilder = requestBuilder.WithQueryParam(key, value)
+	}
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(store),
+		Config:    apitest.NewConfigBuilder().Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandleGetTrash(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	license := "MIT"
+	deleted1 := newDeletedCollection(uuid.NewString(), role.Owner)
+	deleted1.License = &license
+	deleted1.Tags = []string{uuid.NewString()}
+	deleted1.Size = 3
+	deleted2 := newDeletedCollection(uuid.NewString(), role.Manager)
+
+	store := mocks.NewCollectionsStore().
+		WithGetDeletedCollectionsFunc(func(_ context.Context, userID int64, minRole role.Role, limit int, offset int) (collections.GetDeletedCollectionsResponse, error) {
+			require.Equal(t, callingUser.ID, userID)
+			require.Equal(t, role.Manager, minRole)
+			require.Equal(t, 5, limit)
+			require.Equal(t, 2, offset)
+			return collections.GetDeletedCollectionsResponse{
+				Limit:       limit,
+				Offset:      offset,
+				Collections: []collections.DeletedCollection{deleted1, deleted2},
+				TotalCount:  4,
+			}, nil
+		})
+
+	response, err := Handle(ctx, NewGetTrashRouteHandler(), newGetTrashParams(callingUser, map[string]string{"limit": "5", "offset": "2"}, store))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetTrashResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, 5, responseDTO.Limit)
+	assert.Equal(t, 2, responseDTO.Offset)
+	assert.Equal(t, 4, responseDTO.TotalCount)
+	require.Len(t, responseDTO.Collections, 2)
+
+	actual1 := responseDTO.Collections[0]
+	assert.Equal(t, deleted1.NodeID, actual1.NodeID)
+	assert.Equal(t, deleted1.Name, actual1.Name)
+	assert.Equal(t, license, actual1.License)
+	assert.Equal(t, deleted1.Tags, actual1.Tags)
+	assert.Equal(t, 3, actual1.Size)
+	assert.Equal(t, role.Owner.String(), actual1.UserRole)
+	assert.True(t, deleted1.DeletedAt.Equal(actual1.DeletedAt))
+
+	assert.Equal(t, deleted2.NodeID, responseDTO.Collections[1].NodeID)
+	assert.Equal(t, role.Manager.String(), responseDTO.Collections[1].UserRole)
+}
+
+func testHandleGetTrashEmpty(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	deleted := newDeletedCollection(uuid.NewString(), role.Owner)
+	store := mocks.NewCollectionsStore().
+		WithGetDeletedCollectionsFunc(func(_ context.Context, _ int64, _ role.Role, limit int, offset int) (collections.GetDeletedCollectionsResponse, error) {
+			return collections.GetDeletedCollectionsResponse{
+				Limit:       limit,
+				Offset:      offset,
+				Collections: []collections.DeletedCollection{deleted},
+				TotalCount:  1,
+			}, nil
+		})
+
+	response, err := Handle(ctx, NewGetTrashRouteHandler(), newGetTrashParams(callingUser, nil, store))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	assert.NotContains(t, response.Body, "null")
+	assert.Contains(t, response.Body, `"tags":[]`)
+}
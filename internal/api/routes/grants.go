@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"net/http"
+)
+
+const TeamNodeIDPathParamKey = "teamNodeId"
+
+const OrganizationNodeIDPathParamKey = "organizationNodeId"
+
+func ToDTOCollectionGrant(grant collections.CollectionGrant) dto.CollectionGrant {
+	return dto.CollectionGrant{
+		NodeID: grant.GranteeNodeID,
+		Name:   grant.Name,
+		Role:   grant.Role.String(),
+	}
+}
+
+func ToDTOCollectionGrants(grants []collections.CollectionGrant) dto.GetCollectionGrantsResponse {
+	response := dto.GetCollectionGrantsResponse{}
+	for _, grant := range grants {
+		switch grant.GranteeType {
+		case collections.TeamGrantee:
+			response.Teams = append(response.Teams, ToDTOCollectionGrant(grant))
+		case collections.OrganizationGrantee:
+			response.Organizations = append(response.Organizations, ToDTOCollectionGrant(grant))
+		}
+	}
+	return response
+}
+
+// granteePathParamKey returns the path parameter holding the node id of a grantee of the given type.
+func granteePathParamKey(granteeType collections.GranteeType) string {
+	if granteeType == collections.OrganizationGrantee {
+		return OrganizationNodeIDPathParamKey
+	}
+	return TeamNodeIDPathParamKey
+}
+
+func NewGranteeNotFoundError(granteeType collections.GranteeType, granteeNodeID string) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("%s %s not found", granteeType, granteeNodeID), nil, http.StatusNotFound)
+}
+
+func NewCollectionGrantNotFoundError(collectionNodeID string, granteeType collections.GranteeType, granteeNodeID string) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("%s %s has no role on collection %s", granteeType, granteeNodeID, collectionNodeID), nil, http.StatusNotFound)
+}
@@ -0,0 +1,227 @@
+package routes
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/citation"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+var ImportDOIsRouteKey = fmt.Sprintf("POST /{%s}/dois/import", NodeIDPathParamKey)
+
+// MaxImportReferences is the most references a single import request may contain.
+const MaxImportReferences = 1000
+
+// MaxImportExternalDOIs is the most new non-Pennsieve DOIs a single import request may contain. Each one
+// is looked up with its own resolver request, so this keeps the import within the API Gateway timeout.
+const MaxImportExternalDOIs = 25
+
+// ContentTypeHeader is lowercase since API Gateway lowercases request header names.
+const ContentTypeHeader = "content-type"
+
+// ImportDOIs adds the DOIs found in a CSV, BibTeX, or RIS reference list to a collection. The format is
+// given by the Content-Type header. References that cannot be added do not fail the request. Instead, the
+// response reports what happened to each reference. All the DOIs that can be added are added together.
+func ImportDOIs(ctx context.Context, params Params) (dto.ImportDOIsResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.ImportDOIsResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	contentType := params.Request.Headers[ContentTypeHeader]
+	format, ok := citation.FormatFromContentType(contentType)
+	if !ok {
+		return dto.ImportDOIsResponse{}, apierrors.NewError(
+			fmt.Sprintf("unsupported content type %q; use text/csv, application/x-bibtex, or application/x-research-info-systems", contentType),
+			nil,
+			http.StatusUnsupportedMediaType)
+	}
+
+	requestBody, err := decodedRequestBody(params)
+	if err != nil {
+		return dto.ImportDOIsResponse{}, err
+	}
+	if len(requestBody) == 0 {
+		return dto.ImportDOIsResponse{}, apierrors.NewBadRequestError("missing request body")
+	}
+
+	references, err := citation.ExtractReferences(format, requestBody)
+	if err != nil {
+		return dto.ImportDOIsResponse{}, apierrors.NewBadRequestErrorWithCause(fmt.Sprintf("error reading %s request body", format), err)
+	}
+	if len(references) == 0 {
+		return dto.ImportDOIsResponse{}, apierrors.NewBadRequestError(fmt.Sprintf("no references found in %s request body", format))
+	}
+	if len(references) > MaxImportReferences {
+		return dto.ImportDOIsResponse{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("request body contains %d references; cannot import more than %d at once", len(references), MaxImportReferences))
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	currentState, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.ImportDOIsResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.ImportDOIsResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection to import into",
+			err)
+	}
+
+	minRequiredRole := role.Editor
+	if !currentState.UserRole.Implies(minRequiredRole) {
+		return dto.ImportDOIsResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not updated; requires user role: %s",
+				nodeID,
+				minRequiredRole),
+		)
+	}
+
+	results, err := params.importResults(ctx, references, currentState.DOIs)
+	if err != nil {
+		return dto.ImportDOIsResponse{}, err
+	}
+
+	var toAdd []collections.DOI
+	for _, result := range results {
+		if result.Status == dto.ImportAdded {
+			toAdd = append(toAdd, collections.DOI{Value: result.DOI, Datasource: result.Source})
+		}
+	}
+	if len(toAdd) > 0 {
+		if _, err := collectionsStore.UpdateCollection(ctx, userClaim.Id, currentState.ID, collections.UpdateCollectionRequest{
+			DOIs: collections.DOIUpdate{Add: toAdd},
+		}); err != nil {
+			if errors.Is(err, collections.ErrCollectionNotFound) {
+				return dto.ImportDOIsResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+			}
+			return dto.ImportDOIsResponse{}, apierrors.NewInternalServerError(
+				"error adding imported DOIs to collection",
+				err)
+		}
+	}
+
+	return dto.ImportDOIsResponse{Results: results}, nil
+}
+
+func NewImportDOIsRouteHandler() Handler[dto.ImportDOIsResponse] {
+	return Handler[dto.ImportDOIsResponse]{
+		HandleFunc:        ImportDOIs,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+// importResults decides what should happen to each reference. A result with status dto.ImportAdded
+// has not been added yet; that is up to the caller.
+func (p Params) importResults(ctx context.Context, references []citation.Reference, currentDOIs collections.DOIs) ([]dto.ImportDOIResult, error) {
+	existing := map[string]collections.DOI{}
+	for _, doi := range currentDOIs {
+		existing[doi.Value] = doi
+	}
+
+	var results []dto.ImportDOIResult
+	// resultIndex maps a DOI to its result so that repeated DOIs are only reported once
+	resultIndex := map[string]int{}
+	var candidates []string
+	for _, reference := range references {
+		if len(reference.DOI) == 0 {
+			results = append(results, dto.ImportDOIResult{Input: reference.Source, Status: dto.ImportUnparseable})
+			continue
+		}
+		if _, seen := resultIndex[reference.DOI]; seen {
+			continue
+		}
+		resultIndex[reference.DOI] = len(results)
+		result := dto.ImportDOIResult{Input: reference.Source, DOI: reference.DOI}
+		if existingDOI, present := existing[reference.DOI]; present {
+			result.Source = existingDOI.Datasource
+			result.Status = dto.ImportAlreadyPresent
+		} else {
+			candidates = append(candidates, reference.DOI)
+		}
+		results = append(results, result)
+	}
+
+	pennsieveDOIs, externalDOIs := CategorizeDOIs(p.Config.PennsieveConfig.DOIPrefix, candidates)
+	if len(externalDOIs) > MaxImportExternalDOIs {
+		return nil, apierrors.NewBadRequestError(
+			fmt.Sprintf("request body contains %d new non-Pennsieve DOIs; cannot import more than %d at once", len(externalDOIs), MaxImportExternalDOIs))
+	}
+	if len(pennsieveDOIs) > 0 {
+		discoverResp := service.DatasetsByDOIResponse{
+			Published:   map[string]dto.PublicDataset{},
+			Unpublished: map[string]dto.Tombstone{},
+		}
+		for batch := range slices.Chunk(pennsieveDOIs, discoverDOIBatchSize) {
+			batchResp, err := p.Container.Discover().GetDatasetsByDOI(ctx, batch)
+			if err != nil {
+				return nil, apierrors.NewInternalServerError("error querying Discover for DOIs to import", err)
+			}
+			maps.Copy(discoverResp.Published, batchResp.Published)
+			maps.Copy(discoverResp.Unpublished, batchResp.Unpublished)
+		}
+		for _, doi := range pennsieveDOIs {
+			result := &results[resultIndex[doi]]
+			result.Source = datasource.Pennsieve
+			if published, isPublished := discoverResp.Published[doi]; isPublished {
+				if isCollectionDataset(published) {
+					result.Status = dto.ImportCollection
+				} else {
+					result.Status = dto.ImportAdded
+				}
+			} else if tombstone, isUnpublished := discoverResp.Unpublished[doi]; isUnpublished {
+				result.Status = dto.ImportUnpublished
+				result.Detail = fmt.Sprintf("status is %s", tombstone.Status)
+			} else {
+				result.Status = dto.ImportUnpublished
+				result.Detail = "dataset not found"
+			}
+		}
+	}
+	if len(externalDOIs) > 0 {
+		resolveResp, err := p.Container.ExternalDOI().ResolveDOIs(ctx, externalDOIs)
+		if err != nil {
+			return nil, apierrors.NewInternalServerError("error looking up non-Pennsieve DOIs to import", err)
+		}
+		for _, doi := range externalDOIs {
+			result := &results[resultIndex[doi]]
+			result.Source = datasource.External
+			if _, resolved := resolveResp.Resolved[doi]; resolved {
+				result.Status = dto.ImportAdded
+			} else {
+				result.Status = dto.ImportUnresolved
+			}
+		}
+	}
+	return results, nil
+}
+
+// decodedRequestBody returns the request body, decoding it first if API Gateway base64 encoded it.
+// API Gateway does this for content types it does not consider text.
+func decodedRequestBody(params Params) (string, error) {
+	if !params.Request.IsBase64Encoded {
+		return params.Request.Body, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(params.Request.Body)
+	if err != nil {
+		return "", apierrors.NewBadRequestErrorWithCause("error decoding base64 encoded request body", err)
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,350 @@
+package routes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHandleImportDOIs(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"import should report on every reference and add DOIs in one update", testHandleImportDOIsReport},
+		{"import should read BibTeX", testHandleImportDOIsBibTeX},
+		{"import should read base64 encoded RIS", testHandleImportDOIsBase64RIS},
+		{"import should not update collection when nothing can be added", testHandleImportDOIsNothingToAdd},
+		{"import should return Unsupported Media Type for unknown content type", testHandleImportDOIsUnsupportedMediaType},
+		{"import should return Bad Request when body has no references", testHandleImportDOIsNoReferences},
+		{"import should return Forbidden when user is not an editor", testHandleImportDOIsForbidden},
+		{"import should look up Pennsieve DOIs in batches", testHandleImportDOIsBatches},
+		{"import should return Bad Request when there are too many external DOIs", testHandleImportDOIsTooManyExternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleImportDOIsParams(t *testing.T, expectedCollection *apitest.ExpectedCollection, contentType string, body string, container *apitest.TestContainer) Params {
+	claims := apitest.DefaultClaims(userstest.SeedUser1)
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(ImportDOIsRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(ContentTypeHeader, contentType).
+			WithBody(t, body).
+			Build(),
+		Container: container,
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandleImportDOIsReport(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+	unpublished := expectedDatasets.NewUnpublished()
+	collectionDataset := expectedDatasets.NewPublishedWithOptions(apitest.WithDatasetType(dto.CollectionDatasetType))
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	external := expectedExternalDatasets.NewResolved()
+	unresolved := expectedExternalDatasets.NewUnresolved()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Write).
+		WithNPennsieveDOIs(1)
+	present := expectedCollection.DOIs[0].DOI
+
+	updateCalls := 0
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			updateCalls++
+			assert.Equal(t, userstest.SeedUser1.ID, userID)
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Nil(t, update.Name)
+			assert.Empty(t, update.DOIs.Remove)
+			assert.Equal(t, []collections.DOI{
+				{Value: published.DOI, Datasource: datasource.Pennsieve},
+				{Value: external.DOI, Datasource: datasource.External},
+			}, update.DOIs.Add)
+			return collections.GetCollectionResponse{}, nil
+		})
+
+	csvBody := strings.Join([]string{
+		"title,doi",
+		fmt.Sprintf("Published,https://doi.org/%s", published.DOI),
+		fmt.Sprintf("Duplicate,doi:%s", published.DOI),
+		fmt.Sprintf("Present,%s", present),
+		fmt.Sprintf("Unpublished,%s", unpublished.DOI),
+		fmt.Sprintf("Collection,%s", collectionDataset.DOI),
+		fmt.Sprintf("External,%s", external.DOI),
+		fmt.Sprintf("Unresolved,%s", unresolved),
+		"Garbage,not a doi",
+	}, "\n")
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mockCollectionStore).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+		WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t)))
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "text/csv; charset=utf-8", csvBody, container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 1, updateCalls)
+
+	var responseDTO dto.ImportDOIsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	require.Len(t, responseDTO.Results, 7)
+
+	assert.Equal(t, dto.ImportDOIResult{
+		Input:  fmt.Sprintf("https://doi.org/%s", published.DOI),
+		DOI:    published.DOI,
+		Source: datasource.Pennsieve,
+		Status: dto.ImportAdded,
+	}, responseDTO.Results[0])
+
+	assert.Equal(t, present, responseDTO.Results[1].DOI)
+	assert.Equal(t, dto.ImportAlreadyPresent, responseDTO.Results[1].Status)
+
+	assert.Equal(t, unpublished.DOI, responseDTO.Results[2].DOI)
+	assert.Equal(t, dto.ImportUnpublished, responseDTO.Results[2].Status)
+	assert.Contains(t, responseDTO.Results[2].Detail, unpublished.Status)
+
+	assert.Equal(t, collectionDataset.DOI, responseDTO.Results[3].DOI)
+	assert.Equal(t, dto.ImportCollection, responseDTO.Results[3].Status)
+
+	assert.Equal(t, dto.ImportDOIResult{
+		Input:  external.DOI,
+		DOI:    external.DOI,
+		Source: datasource.External,
+		Status: dto.ImportAdded,
+	}, responseDTO.Results[4])
+
+	assert.Equal(t, unresolved, responseDTO.Results[5].DOI)
+	assert.Equal(t, datasource.External, responseDTO.Results[5].Source)
+	assert.Equal(t, dto.ImportUnresolved, responseDTO.Results[5].Status)
+
+	assert.Equal(t, dto.ImportDOIResult{
+		Input:  "not a doi",
+		Status: dto.ImportUnparseable,
+	}, responseDTO.Results[6])
+}
+
+func testHandleImportDOIsBibTeX(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	var added []collections.DOI
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			added = update.DOIs.Add
+			return collections.GetCollectionResponse{}, nil
+		})
+
+	bibtexBody := fmt.Sprintf(`@article{smith2024,
+  title = {A Dataset},
+  doi = {%s}
+}
+
+@misc{nodoi2023,
+  title = {No DOI Here}
+}
+`, published.DOI)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mockCollectionStore).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "application/x-bibtex", bibtexBody, container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []collections.DOI{{Value: published.DOI, Datasource: datasource.Pennsieve}}, added)
+
+	var responseDTO dto.ImportDOIsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	require.Len(t, responseDTO.Results, 2)
+	assert.Equal(t, dto.ImportAdded, responseDTO.Results[0].Status)
+	assert.Equal(t, dto.ImportDOIResult{Input: "nodoi2023", Status: dto.ImportUnparseable}, responseDTO.Results[1])
+}
+
+func testHandleImportDOIsBase64RIS(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	var added []collections.DOI
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			added = update.DOIs.Add
+			return collections.GetCollectionResponse{}, nil
+		})
+
+	risBody := fmt.Sprintf("TY  - DATA\r\nTI  - A Dataset\r\nDO  - %s\r\nER  - \r\n", published.DOI)
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mockCollectionStore).
+		WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t)))
+
+	params := newHandleImportDOIsParams(t, expectedCollection, "application/x-research-info-systems", base64.StdEncoding.EncodeToString([]byte(risBody)), container)
+	params.Request.IsBase64Encoded = true
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []collections.DOI{{Value: published.DOI, Datasource: datasource.Pennsieve}}, added)
+}
+
+func testHandleImportDOIsNothingToAdd(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithNPennsieveDOIs(1)
+
+	// no UpdateCollectionFunc or Discover set, so the mocks panic if called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	csvBody := fmt.Sprintf("%s\nnonsense\n", expectedCollection.DOIs[0].DOI)
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "text/csv", csvBody, apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore)))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.ImportDOIsResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	require.Len(t, responseDTO.Results, 2)
+	assert.Equal(t, dto.ImportAlreadyPresent, responseDTO.Results[0].Status)
+	assert.Equal(t, dto.ImportUnparseable, responseDTO.Results[1].Status)
+}
+
+func testHandleImportDOIsUnsupportedMediaType(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "application/json", `{"dois": []}`, apitest.NewTestContainer()))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.StatusCode)
+	assert.Contains(t, response.Body, "application/json")
+}
+
+func testHandleImportDOIsNoReferences(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "application/x-bibtex", "% just a comment\n", apitest.NewTestContainer()))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, "no references")
+}
+
+func testHandleImportDOIsForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Read)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	csvBody := apitest.NewPennsieveDOI().Value
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "text/csv", csvBody, apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore)))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func testHandleImportDOIsBatches(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	var dois []string
+	for range 2*discoverDOIBatchSize + 10 {
+		dois = append(dois, expectedDatasets.NewPublished().DOI)
+	}
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	var added []collections.DOI
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			added = update.DOIs.Add
+			return collections.GetCollectionResponse{}, nil
+		})
+
+	var batchSizes []int
+	getDatasetsByDOI := expectedDatasets.GetDatasetsByDOIFunc(t)
+	mockDiscover := mocks.NewDiscover().WithGetDatasetsByDOIFunc(func(ctx context.Context, dois []string) (service.DatasetsByDOIResponse, error) {
+		batchSizes = append(batchSizes, len(dois))
+		return getDatasetsByDOI(ctx, dois)
+	})
+
+	container := apitest.NewTestContainer().
+		WithCollectionsStore(mockCollectionStore).
+		WithDiscover(mockDiscover)
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "text/csv", strings.Join(dois, "\n"), container))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []int{discoverDOIBatchSize, discoverDOIBatchSize, 10}, batchSizes)
+	assert.Len(t, added, len(dois))
+}
+
+func testHandleImportDOIsTooManyExternal(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	var dois []string
+	for range MaxImportExternalDOIs + 1 {
+		dois = append(dois, apitest.NewExternalDOI().Value)
+	}
+
+	// no ExternalDOI set, so the test container panics if it is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	response, err := Handle(ctx, NewImportDOIsRouteHandler(), newHandleImportDOIsParams(t, expectedCollection, "text/csv", strings.Join(dois, "\n"), apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore)))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, "non-Pennsieve DOIs")
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/shared/util"
+	"log/slog"
 )
 
 func ToDTOPublication(storePublication *collections.Publication, fromDiscover *service.DatasetPublishStatusResponse) *dto.Publication {
@@ -45,6 +47,9 @@ func (p Params) StoreToDTOCollection(ctx context.Context, storeCollection collec
 			Publication: ToDTOPublication(storeCollection.Publication, datasetPublishStatus),
 		},
 	}
+	if storeCollection.Version > 0 {
+		response.ETag = ETag(storeCollection.Version)
+	}
 	if publication := storeCollection.Publication; publication != nil {
 		response.Publication.Status = publication.Status
 		response.Publication.Type = publication.Type
@@ -54,9 +59,11 @@ func (p Params) StoreToDTOCollection(ctx context.Context, storeCollection collec
 
 	mergedContributors := MergedContributors{}
 
-	pennsieveDOIs, _ := GroupByDatasource(storeCollection.DOIs)
+	pennsieveDOIs, externalDOIs := GroupByDatasource(storeCollection.DOIs)
+	var discoverResp service.DatasetsByDOIResponse
 	if len(pennsieveDOIs) > 0 {
-		discoverResp, err := p.Container.Discover().GetDatasetsByDOI(ctx, pennsieveDOIs)
+		var err error
+		discoverResp, err = p.Container.Discover().GetDatasetsByDOI(ctx, pennsieveDOIs)
 		if err != nil {
 			return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
 				"error querying Discover for datasets in collection",
@@ -64,9 +71,26 @@ func (p Params) StoreToDTOCollection(ctx context.Context, storeCollection collec
 		}
 
 		response.Banners = collectBanners(pennsieveDOIs, discoverResp.Published)
+		response.Stale = len(discoverResp.Stale) > 0
+	}
+	var resolveResp service.ResolveDOIsResponse
+	if len(externalDOIs) > 0 {
+		var err error
+		resolveResp, err = p.Container.ExternalDOI().ResolveDOIs(ctx, externalDOIs)
+		if err != nil {
+			// don't fail the whole response because doi.org is having trouble. The DOIs are mapped as unresolved below.
+			p.Container.Logger().Warn("error resolving non-Pennsieve datasets in collection; returning them as unresolved",
+				slog.Any("dois", externalDOIs),
+				slog.Any("error", err))
+			resolveResp = service.ResolveDOIsResponse{}
+		}
+	}
 
-		for _, doi := range pennsieveDOIs {
-			var datasetDTO dto.Dataset
+	for _, storeDOI := range storeCollection.DOIs {
+		doi := storeDOI.Value
+		var datasetDTO dto.Dataset
+		var err error
+		if storeDOI.Datasource == datasource.Pennsieve {
 			if published, foundPub := discoverResp.Published[doi]; foundPub {
 				datasetDTO, err = dto.NewPennsieveDataset(published)
 				if err != nil {
@@ -94,10 +118,23 @@ func (p Params) StoreToDTOCollection(ctx context.Context, storeCollection collec
 						fmt.Sprintf("error marshalling Discover Tombstone for missing dataset %s", doi), err)
 				}
 			}
-			response.Datasets = append(response.Datasets, datasetDTO)
-
+		} else {
+			if externalDataset, resolved := resolveResp.Resolved[doi]; resolved {
+				datasetDTO, err = dto.NewExternalDataset(externalDataset)
+			} else {
+				// the DOI resolved when it was added, but not now.
+				datasetDTO, err = dto.NewUnresolvedExternalDataset(doi)
+			}
+			if err != nil {
+				return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+					fmt.Sprintf("error marshalling external dataset %s", doi), err)
+			}
 		}
+		datasetDTO.Label = storeDOI.Label
+		datasetDTO.Note = storeDOI.Note
+		response.Datasets = append(response.Datasets, datasetDTO)
 	}
 	response.DerivedContributors = mergedContributors.Deduplicated()
+	response.Sections = ToDTOCollectionSections(storeCollection.Sections)
 	return response, nil
 }
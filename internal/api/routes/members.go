@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"net/http"
+	"slices"
+)
+
+const UserNodeIDPathParamKey = "userNodeId"
+
+// minRoleToManageMembers is the role required to add, change, or remove any collection member other than oneself.
+const minRoleToManageMembers = role.Manager
+
+func ToDTOCollectionMember(member collections.CollectionMember) dto.CollectionMember {
+	return dto.CollectionMember{
+		UserNodeID: member.UserNodeID,
+		FirstName:  util.SafeDeref(member.FirstName),
+		LastName:   util.SafeDeref(member.LastName),
+		Role:       member.Role.String(),
+	}
+}
+
+func ToDTOCollectionMembers(members []collections.CollectionMember) dto.GetCollectionMembersResponse {
+	response := dto.GetCollectionMembersResponse{}
+	for _, member := range members {
+		response.Members = append(response.Members, ToDTOCollectionMember(member))
+	}
+	return response
+}
+
+// findCollectionMember returns the member of the given collection with the given user node id or nil if
+// the user is not a member.
+func findCollectionMember(ctx context.Context, params Params, collectionID int64, userNodeID string) (*collections.CollectionMember, error) {
+	members, err := params.Container.CollectionsStore().GetCollectionMembers(ctx, collectionID)
+	if err != nil {
+		return nil, apierrors.NewInternalServerError(fmt.Sprintf("error looking up members of collection %d", collectionID), err)
+	}
+	idx := slices.IndexFunc(members, func(member collections.CollectionMember) bool {
+		return member.UserNodeID == userNodeID
+	})
+	if idx < 0 {
+		return nil, nil
+	}
+	return &members[idx], nil
+}
+
+func NewUserNotFoundError(userNodeID string) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("user %s not found", userNodeID), nil, http.StatusNotFound)
+}
+
+func NewCollectionMemberNotFoundError(collectionNodeID, userNodeID string) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("user %s is not a member of collection %s", userNodeID, collectionNodeID), nil, http.StatusNotFound)
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
-	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/api/validate"
@@ -68,15 +67,23 @@ func PatchCollection(ctx context.Context, params Params) (dto.GetCollectionRespo
 		)
 	}
 
+	conditional, err := CheckIfMatch(params.Request.Headers, nodeID, currentState.Version)
+	if err != nil {
+		return dto.GetCollectionResponse{}, err
+	}
+
 	updateCollectionRequest, err := GetUpdateRequest(params.Config.PennsieveConfig.DOIPrefix, patchRequest, currentState)
 	if err != nil {
 		return dto.GetCollectionResponse{}, err
 	}
+	if conditional {
+		// The checks below take a while, so make sure no one else updates the collection in the meantime.
+		updateCollectionRequest.ExpectedVersion = &currentState.Version
+	}
 
-	// Check that we haven't been asked to add unpublished DOIs.
-	// For now, no external DOIs, so we ignore that part of the return value
-	// GetUpdateRequest will have failed if there were any external DOIs
-	if pennsieveToAdd, _ := GroupByDatasource(updateCollectionRequest.DOIs.Add); len(pennsieveToAdd) > 0 {
+	// Check that we haven't been asked to add unpublished or unknown DOIs.
+	pennsieveToAdd, externalToAdd := GroupByDatasource(updateCollectionRequest.DOIs.Add)
+	if len(pennsieveToAdd) > 0 {
 		discoverResp, err := params.Container.Discover().GetDatasetsByDOI(ctx, pennsieveToAdd)
 		if err != nil {
 			return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
@@ -87,15 +94,22 @@ func PatchCollection(ctx context.Context, params Params) (dto.GetCollectionRespo
 			return dto.GetCollectionResponse{}, err
 		}
 	}
+	if _, err := params.ResolveExternalDOIs(ctx, externalToAdd); err != nil {
+		return dto.GetCollectionResponse{}, err
+	}
 
 	updateCollectionResponse, err := params.Container.CollectionsStore().UpdateCollection(ctx, userClaim.Id, currentState.ID, updateCollectionRequest)
 	if err != nil {
-		if errors.Is(err, collections.ErrCollectionNotFound) {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
 			return dto.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrCollectionVersionMismatch):
+			return dto.GetCollectionResponse{}, NewCollectionChangedError(nodeID)
+		default:
+			return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+				"error updating collection",
+				err)
 		}
-		return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
-			"error updating collection",
-			err)
 	}
 	return params.StoreToDTOCollection(ctx, updateCollectionResponse, nil)
 }
@@ -136,11 +150,31 @@ func ValidatePatchRequest(request *dto.PatchCollectionRequest) error {
 			return apierrors.NewBadRequestError(err.Error())
 		}
 	}
+	if request.DOIs != nil {
+		for i := range request.DOIs.Annotate {
+			annotation := &request.DOIs.Annotate[i]
+			annotation.DOI = strings.TrimSpace(annotation.DOI)
+			if annotation.Label != nil {
+				trimmedLabel := strings.TrimSpace(*annotation.Label)
+				annotation.Label = &trimmedLabel
+				if err := validate.DOILabel(trimmedLabel); err != nil {
+					return err
+				}
+			}
+			if annotation.Note != nil {
+				trimmedNote := strings.TrimSpace(*annotation.Note)
+				annotation.Note = &trimmedNote
+				if err := validate.DOINote(trimmedNote); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 
 }
 
-// GetUpdateRequest constructs the update request for the Store. It returns an error if any DOIs are not Pennsieve, and removes any
+// GetUpdateRequest constructs the update request for the Store. It sets the Datasource of any DOIs to add based on pennsieveDOIPrefix, and removes any
 // duplicates as well as any "adds" that already exist in the collection and any "removes" that do not exist in the collection.
 func GetUpdateRequest(pennsieveDOIPrefix string, patchRequest dto.PatchCollectionRequest, currentState collections.GetCollectionResponse) (collections.UpdateCollectionRequest, error) {
 	storeRequest := collections.UpdateCollectionRequest{}
@@ -172,21 +206,70 @@ func GetUpdateRequest(pennsieveDOIPrefix string, patchRequest dto.PatchCollectio
 		}
 	}
 
-	pennsieveDOIs, externalDOIs := CategorizeDOIs(pennsieveDOIPrefix, patchRequest.DOIs.Add)
-	if len(externalDOIs) > 0 {
-		// We may later allow non-Pennsieve DOIs, but for now, this is an error
-		return collections.UpdateCollectionRequest{}, apierrors.NewBadRequestError(
-			fmt.Sprintf("request contains non-Pennsieve DOIs: %s", strings.Join(externalDOIs, ", ")))
-	}
-
 	// Iterate over all the DOIs to Add to maintain the same order
-	for _, toAdd := range pennsieveDOIs {
-		if _, exists := existingDOIs[toAdd]; !exists {
-			storeRequest.DOIs.Add = append(storeRequest.DOIs.Add, collections.DOI{
-				Value:      toAdd,
-				Datasource: datasource.Pennsieve,
-			})
+	for _, toAdd := range ToStoreDOIs(pennsieveDOIPrefix, patchRequest.DOIs.Add) {
+		if _, exists := existingDOIs[toAdd.Value]; !exists {
+			storeRequest.DOIs.Add = append(storeRequest.DOIs.Add, toAdd)
 		}
 	}
+
+	annotations, err := getDOIAnnotations(patchRequest.DOIs.Annotate, currentState.DOIs, storeRequest.DOIs)
+	if err != nil {
+		return collections.UpdateCollectionRequest{}, err
+	}
+	storeRequest.DOIs.Annotate = annotations
 	return storeRequest, nil
 }
+
+// getDOIAnnotations returns a Bad Request *apierrors.Error if any annotation is for a DOI that will not be in the collection
+// after doiUpdate is applied, or if a DOI is annotated more than once. Annotations that do not change anything are dropped.
+func getDOIAnnotations(annotations []dto.DOIAnnotation, currentDOIs collections.DOIs, doiUpdate collections.DOIUpdate) ([]collections.DOIAnnotation, error) {
+	if len(annotations) == 0 {
+		return nil, nil
+	}
+	current := make(map[string]collections.DOI, len(currentDOIs))
+	for _, doi := range currentDOIs {
+		current[doi.Value] = doi
+	}
+	for _, toRemove := range doiUpdate.Remove {
+		delete(current, toRemove)
+	}
+	for _, toAdd := range doiUpdate.Add {
+		current[toAdd.Value] = toAdd
+	}
+
+	var storeAnnotations []collections.DOIAnnotation
+	var notInCollection, duplicates []string
+	seen := map[string]bool{}
+	for _, annotation := range annotations {
+		if seen[annotation.DOI] {
+			duplicates = append(duplicates, annotation.DOI)
+			continue
+		}
+		seen[annotation.DOI] = true
+		doi, inCollection := current[annotation.DOI]
+		if !inCollection {
+			notInCollection = append(notInCollection, annotation.DOI)
+			continue
+		}
+		storeAnnotation := collections.DOIAnnotation{DOI: annotation.DOI}
+		if annotation.Label != nil && *annotation.Label != doi.Label {
+			storeAnnotation.Label = annotation.Label
+		}
+		if annotation.Note != nil && *annotation.Note != doi.Note {
+			storeAnnotation.Note = annotation.Note
+		}
+		if storeAnnotation.Label != nil || storeAnnotation.Note != nil {
+			storeAnnotations = append(storeAnnotations, storeAnnotation)
+		}
+	}
+	if len(notInCollection) > 0 {
+		return nil, apierrors.NewBadRequestError(
+			fmt.Sprintf("cannot annotate DOIs not in collection: %s", strings.Join(notInCollection, ", ")))
+	}
+	if len(duplicates) > 0 {
+		return nil, apierrors.NewBadRequestError(
+			fmt.Sprintf("DOIs annotated more than once: %s", strings.Join(duplicates, ", ")))
+	}
+	return storeAnnotations, nil
+}
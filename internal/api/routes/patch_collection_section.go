@@ -0,0 +1,116 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var PatchCollectionSectionRouteKey = fmt.Sprintf("PATCH /{%s}/sections/{%s}", NodeIDPathParamKey, SectionIDPathParamKey)
+
+// PatchCollectionSection changes the title, description, position, or DOIs of a section.
+func PatchCollectionSection(ctx context.Context, params Params) (dto.CollectionSection, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionSection{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	sectionID, err := sectionIDPathParam(params)
+	if err != nil {
+		return dto.CollectionSection{}, err
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.Int64(SectionIDPathParamKey, sectionID))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.CollectionSection{}, apierrors.NewBadRequestError("missing request body")
+	}
+	var patchRequest dto.PatchCollectionSectionRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patchRequest); err != nil {
+		return dto.CollectionSection{}, apierrors.NewRequestUnmarshallError(patchRequest, err)
+	}
+
+	var update collections.UpdateSectionRequest
+	if patchRequest.Title != nil {
+		title := strings.TrimSpace(*patchRequest.Title)
+		if err := validate.SectionTitle(title); err != nil {
+			return dto.CollectionSection{}, err
+		}
+		update.Title = &title
+	}
+	if patchRequest.Description != nil {
+		description := strings.TrimSpace(*patchRequest.Description)
+		if err := validate.SectionDescription(description); err != nil {
+			return dto.CollectionSection{}, err
+		}
+		update.Description = &description
+	}
+	if patchRequest.Position != nil {
+		if *patchRequest.Position < 0 {
+			return dto.CollectionSection{}, apierrors.NewBadRequestError(fmt.Sprintf("section position cannot be negative: %d", *patchRequest.Position))
+		}
+		update.Position = patchRequest.Position
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	collection, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionSection{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionSection{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageSections) {
+		return dto.CollectionSection{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s section not updated; requires user role: %s",
+				nodeID,
+				minRoleToManageSections),
+		)
+	}
+
+	if patchRequest.DOIs != nil {
+		if update.DOIs, err = ValidateSectionDOIs(patchRequest.DOIs, collection.DOIs); err != nil {
+			return dto.CollectionSection{}, err
+		}
+	}
+
+	section, err := collectionsStore.UpdateSection(ctx, collection.ID, userClaim.Id, sectionID, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.CollectionSection{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrSectionNotFound):
+			return dto.CollectionSection{}, NewSectionNotFoundError(nodeID, sectionID)
+		case errors.Is(err, collections.ErrSectionDOIsNotInCollection):
+			return dto.CollectionSection{}, NewSectionDOIsConflictError(nodeID, err)
+		default:
+			return dto.CollectionSection{}, apierrors.NewInternalServerError("error updating collection section", err)
+		}
+	}
+
+	return ToDTOCollectionSection(section), nil
+}
+
+func NewPatchCollectionSectionRouteHandler() Handler[dto.CollectionSection] {
+	return Handler[dto.CollectionSection]{
+		HandleFunc:        PatchCollectionSection,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -2,11 +2,14 @@ package routes
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
@@ -1040,6 +1043,109 @@ func TestGetUpdateRequestAddDOIs(t *testing.T) {
 
 }
 
+func TestGetUpdateRequestAddExternalDOIs(t *testing.T) {
+	doi1 := apitest.NewPennsieveDOI()
+	externalDOI1 := apitest.NewExternalDOI()
+
+	externalToAdd := apitest.NewExternalDOI()
+	pennsieveToAdd := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithDOIs(doi1, externalDOI1)
+
+	patchCollectionRequest := dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{
+		Add:    []string{externalToAdd.Value, externalDOI1.Value, pennsieveToAdd.Value},
+		Remove: []string{externalDOI1.Value},
+	}}
+
+	updateRequest, err := GetUpdateRequest(apitest.PennsieveDOIPrefix, patchCollectionRequest, expectedCollection.ToGetCollectionResponse(t, userstest.SeedUser1.ID, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{externalDOI1.Value}, updateRequest.DOIs.Remove)
+	// externalDOI1 is already in the collection, so not added
+	assert.Equal(t, []collections.DOI{externalToAdd, pennsieveToAdd}, updateRequest.DOIs.Add)
+}
+
+func TestGetUpdateRequestAnnotateDOIs(t *testing.T) {
+	doi1 := apitest.NewPennsieveDOI()
+	doi1.Label = "primary"
+	doi1.Note = uuid.NewString()
+	doi2 := apitest.NewPennsieveDOI()
+	doiToAdd := apitest.NewPennsieveDOI()
+	doiToRemove := apitest.NewPennsieveDOI()
+
+	currentState := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithDOIs(doi1, doi2, doiToRemove).
+		ToGetCollectionResponse(t, userstest.SeedUser1.ID, nil)
+
+	unchangedLabel := doi1.Label
+	emptyNote := ""
+	newLabel := "secondary"
+	newNote := uuid.NewString()
+
+	t.Run("annotate existing and added DOIs", func(t *testing.T) {
+		patchRequest := dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{
+			Add:    []string{doiToAdd.Value},
+			Remove: []string{doiToRemove.Value},
+			Annotate: []dto.DOIAnnotation{
+				{DOI: doi1.Value, Label: &unchangedLabel, Note: &emptyNote},
+				{DOI: doi2.Value, Label: &unchangedLabel},
+				{DOI: doiToAdd.Value, Label: &newLabel, Note: &newNote},
+			},
+		}}
+
+		updateRequest, err := GetUpdateRequest(apitest.PennsieveDOIPrefix, patchRequest, currentState)
+		require.NoError(t, err)
+
+		assert.Equal(t, []collections.DOIAnnotation{
+			// unchanged label is dropped
+			{DOI: doi1.Value, Note: &emptyNote},
+			{DOI: doi2.Value, Label: &unchangedLabel},
+			{DOI: doiToAdd.Value, Label: &newLabel, Note: &newNote},
+		}, updateRequest.DOIs.Annotate)
+	})
+
+	t.Run("no-op annotations are dropped", func(t *testing.T) {
+		patchRequest := dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{
+			Annotate: []dto.DOIAnnotation{{DOI: doi1.Value, Label: &unchangedLabel}, {DOI: doi2.Value}},
+		}}
+
+		updateRequest, err := GetUpdateRequest(apitest.PennsieveDOIPrefix, patchRequest, currentState)
+		require.NoError(t, err)
+		assert.Empty(t, updateRequest.DOIs.Annotate)
+	})
+
+	t.Run("DOI not in collection", func(t *testing.T) {
+		unknown := apitest.NewPennsieveDOI()
+		patchRequest := dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{
+			Remove: []string{doiToRemove.Value},
+			Annotate: []dto.DOIAnnotation{
+				{DOI: unknown.Value, Label: &newLabel},
+				{DOI: doiToRemove.Value, Label: &newLabel},
+			},
+		}}
+
+		_, err := GetUpdateRequest(apitest.PennsieveDOIPrefix, patchRequest, currentState)
+		var badRequest *apierrors.Error
+		require.ErrorAs(t, err, &badRequest)
+		assert.Equal(t, http.StatusBadRequest, badRequest.StatusCode)
+		assert.Contains(t, badRequest.UserMessage, unknown.Value)
+		assert.Contains(t, badRequest.UserMessage, doiToRemove.Value)
+	})
+
+	t.Run("DOI annotated twice", func(t *testing.T) {
+		patchRequest := dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{
+			Annotate: []dto.DOIAnnotation{{DOI: doi2.Value, Label: &newLabel}, {DOI: doi2.Value, Note: &newNote}},
+		}}
+
+		_, err := GetUpdateRequest(apitest.PennsieveDOIPrefix, patchRequest, currentState)
+		var badRequest *apierrors.Error
+		require.ErrorAs(t, err, &badRequest)
+		assert.Equal(t, http.StatusBadRequest, badRequest.StatusCode)
+		assert.Contains(t, badRequest.UserMessage, doi2.Value)
+	})
+}
+
 // TestHandlePatchCollection tests that run the Handle wrapper around PatchCollection
 func TestHandlePatchCollection(t *testing.T) {
 	tests := []struct {
@@ -1074,6 +1180,14 @@ func TestHandlePatchCollection(t *testing.T) {
 			"return Bad Request when given an invalid license",
 			testHandlePatchCollectionInvalidLicense,
 		},
+		{
+			"return Bad Request when given a DOI note that is too long",
+			testHandlePatchCollectionDOINoteTooLong,
+		},
+		{
+			"return DOI annotations with datasets",
+			testHandlePatchCollectionAnnotateDOIs,
+		},
 		{
 			"return Not Found when given a non-existent collection",
 			testHandlePatchCollectionNotFound,
@@ -1086,6 +1200,26 @@ func TestHandlePatchCollection(t *testing.T) {
 			"return Bad Request when given a collection DOI to add",
 			testRejectAddingCollectionDOI,
 		},
+		{
+			"add external DOIs",
+			testHandlePatchCollectionAddExternalDOIs,
+		},
+		{
+			"return Bad Request when adding unresolvable external DOIs",
+			testHandlePatchCollectionUnresolvedExternalDOIs,
+		},
+		{
+			"return ETag header for updated collection",
+			testHandlePatchCollectionETag,
+		},
+		{
+			"return Precondition Failed when If-Match does not match current version",
+			testHandlePatchCollectionIfMatchMismatch,
+		},
+		{
+			"return Precondition Failed when collection changes during update",
+			testHandlePatchCollectionConcurrentUpdate,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1293,6 +1427,182 @@ func testHandlePatchCollectionDescriptionTooLong(t *testing.T) {
 
 }
 
+func testHandlePatchCollectionDOINoteTooLong(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	mockCollectionStore := mocks.NewCollectionsStore()
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	tooLongString := strings.Repeat("b", validate.MaxDOINoteLength+1)
+	patchRequest := dto.PatchCollectionRequest{
+		DOIs: &dto.PatchDOIs{Annotate: []dto.DOIAnnotation{{DOI: apitest.NewPennsieveDOI().Value, Note: &tooLongString}}},
+	}
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, patchRequest).
+			WithPathParam(NodeIDPathParamKey, uuid.NewString()).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	assert.Contains(t, response.Body, fmt.Sprintf("DOI note cannot have more than %d characters", validate.MaxDOINoteLength))
+}
+
+func testHandlePatchCollectionAnnotateDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	dataset1 := expectedDatasets.NewPublished()
+	dataset2 := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(dataset1, dataset2)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(expectedCollection.UpdateCollectionFunc(t))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	label := " primary "
+	note := uuid.NewString()
+	patchRequest := dto.PatchCollectionRequest{
+		DOIs: &dto.PatchDOIs{Annotate: []dto.DOIAnnotation{{DOI: dataset2.DOI, Label: &label, Note: &note}}},
+	}
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, patchRequest).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	require.Len(t, responseDTO.Datasets, 2)
+	assert.Empty(t, responseDTO.Datasets[0].Label)
+	assert.Empty(t, responseDTO.Datasets[0].Note)
+	assert.Equal(t, "primary", responseDTO.Datasets[1].Label)
+	assert.Equal(t, note, responseDTO.Datasets[1].Note)
+}
+
+func testHandlePatchCollectionAddExternalDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	dataset1 := expectedDatasets.NewPublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	externalDataset := expectedExternalDatasets.NewResolved()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(dataset1)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(expectedCollection.UpdateCollectionFunc(t))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	patchRequest := dto.PatchCollectionRequest{
+		DOIs: &dto.PatchDOIs{Add: []string{externalDataset.DOI}},
+	}
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, patchRequest).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var responseDTO dto.GetCollectionResponse
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &responseDTO))
+	assert.Equal(t, 2, responseDTO.Size)
+	require.Len(t, responseDTO.Datasets, 2)
+
+	var actualPublicDataset dto.PublicDataset
+	apitest.RequireAsPennsieveDataset(t, responseDTO.Datasets[0], &actualPublicDataset)
+	assert.Equal(t, dataset1.DOI, actualPublicDataset.DOI)
+
+	var actualExternalDataset dto.ExternalDataset
+	apitest.RequireAsExternalDataset(t, responseDTO.Datasets[1], &actualExternalDataset)
+	assert.Equal(t, externalDataset, actualExternalDataset)
+}
+
+func testHandlePatchCollectionUnresolvedExternalDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	externalDataset := expectedExternalDatasets.NewResolved()
+	unresolvedDOI := expectedExternalDatasets.NewUnresolved()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner)
+
+	// No UpdateCollectionFunc since we should fail before that
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	patchRequest := dto.PatchCollectionRequest{
+		DOIs: &dto.PatchDOIs{Add: []string{externalDataset.DOI, unresolvedDOI}},
+	}
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, patchRequest).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, unresolvedDOI)
+	assert.NotContains(t, response.Body, externalDataset.DOI)
+}
+
 func testHandlePatchCollectionInvalidLicense(t *testing.T) {
 	ctx := context.Background()
 	callingUser := userstest.SeedUser1
@@ -1474,3 +1784,107 @@ func testRejectAddingCollectionDOI(t *testing.T) {
 
 	assert.Contains(t, response.Body, collectionDataset.DOI)
 }
+
+func testHandlePatchCollectionETag(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithVersion(3)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			require.NotNil(t, update.ExpectedVersion)
+			assert.Equal(t, int64(3), *update.ExpectedVersion)
+			return expectedCollection.UpdateCollectionFunc(t)(ctx, userID, collectionID, update)
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	newName := uuid.NewString()
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, dto.PatchCollectionRequest{Name: &newName}).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(IfMatchHeader, `"2", "3"`).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `"4"`, response.Headers["etag"])
+	assert.Contains(t, response.Body, newName)
+}
+
+func testHandlePatchCollectionIfMatchMismatch(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithVersion(3)
+
+	// No UpdateCollectionFunc since we should fail before trying to update
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	newName := uuid.NewString()
+	for _, ifMatch := range []string{`"2"`, `W/"3"`, "3"} {
+		t.Run(ifMatch, func(t *testing.T) {
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+					WithClaims(claims).
+					WithBody(t, dto.PatchCollectionRequest{Name: &newName}).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithHeader(IfMatchHeader, ifMatch).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+			response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+			assert.Contains(t, response.Body, "has changed")
+		})
+	}
+}
+
+func testHandlePatchCollectionConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, pgdb.Owner).WithVersion(3)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateCollectionFunc(func(_ context.Context, _ int64, _ int64, _ collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			return collections.GetCollectionResponse{}, fmt.Errorf("error updating collection %d: %w", *expectedCollection.ID, collections.ErrCollectionVersionMismatch)
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	newName := uuid.NewString()
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+			WithClaims(claims).
+			WithBody(t, dto.PatchCollectionRequest{Name: &newName}).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(IfMatchHeader, ETag(expectedCollection.Version)).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewPatchCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+	assert.Contains(t, response.Body, "has changed")
+}
@@ -15,19 +15,23 @@ import (
 	"github.com/pennsieve/collections-service/internal/shared/util"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"log/slog"
-	"maps"
 	"net/http"
-	"slices"
 	"strings"
 )
 
 var PublishCollectionRouteKey = fmt.Sprintf("POST /{%s}/publish", NodeIDPathParamKey)
 
-func PublishCollection(ctx context.Context, params Params) (dto.PublishCollectionResponse, error) {
+// minPublishRole is the role needed to publish a collection and to follow the progress of its publish jobs.
+const minPublishRole = role.Owner
+
+// PublishCollection starts a publish of the collection and queues a job for the publish worker to run it. Publishing
+// makes several calls to Discover and writes the manifest to S3, which can take longer than API Gateway allows a request
+// to take, so only the checks that need no other service are done here. Callers follow the job with GET /{nodeId}/publish/status.
+func PublishCollection(ctx context.Context, params Params) (dto.PublishJob, error) {
 	// Get all the inputs items
 	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
 	if len(nodeID) == 0 {
-		return dto.PublishCollectionResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+		return dto.PublishJob{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
 	}
 
 	userClaim := params.Claims.UserClaim
@@ -35,34 +39,44 @@ func PublishCollection(ctx context.Context, params Params) (dto.PublishCollectio
 		slog.String(NodeIDPathParamKey, nodeID),
 		slog.String("userNodeId", userClaim.NodeId))
 
-	// Lookup info for the initial publish request to Discover
 	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
 	if err != nil {
 		if errors.Is(err, collections.ErrCollectionNotFound) {
-			return dto.PublishCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+			return dto.PublishJob{}, apierrors.NewCollectionNotFoundError(nodeID)
 		}
-		return dto.PublishCollectionResponse{}, apierrors.NewInternalServerError(
+		return dto.PublishJob{}, apierrors.NewInternalServerError(
 			"error querying store for collection to publish",
 			err)
 	}
 
 	// Check permissions
-	minRequiredRole := role.Owner
-	if !collection.UserRole.Implies(minRequiredRole) {
-		return dto.PublishCollectionResponse{}, apierrors.NewForbiddenError(
+	if !collection.UserRole.Implies(minPublishRole) {
+		return dto.PublishJob{}, apierrors.NewForbiddenError(
 			fmt.Sprintf("collection %s not published; requires user role: %s",
 				nodeID,
-				minRequiredRole),
+				minPublishRole),
 		)
 	}
 
+	conditional, err := CheckIfMatch(params.Request.Headers, nodeID, collection.Version)
+	if err != nil {
+		return dto.PublishJob{}, err
+	}
+	var expectedVersion *int64
+	if conditional {
+		expectedVersion = &collection.Version
+	}
+
 	// Make sure there is no in-progress publish for this collection
-	if err := params.Container.CollectionsStore().StartPublish(ctx, collection.ID, userClaim.Id, publishing.PublicationType); err != nil {
+	if err := params.Container.CollectionsStore().StartPublish(ctx, collection.ID, userClaim.Id, publishing.PublicationType, expectedVersion); err != nil {
+		// deliberately leave publish status alone for these, i.e., no cleanupStatus
 		if errors.Is(err, collections.ErrPublishInProgress) {
-			// deliberately leave publish status alone, i.e., no cleanupStatus
-			return dto.PublishCollectionResponse{}, apierrors.NewConflictError(err.Error())
+			return dto.PublishJob{}, apierrors.NewConflictError(err.Error())
 		}
-		return dto.PublishCollectionResponse{},
+		if errors.Is(err, collections.ErrCollectionVersionMismatch) {
+			return dto.PublishJob{}, NewCollectionChangedError(nodeID)
+		}
+		return dto.PublishJob{},
 			cleanupOnError(ctx, params.Container.Logger(),
 				apierrors.NewInternalServerError("error registering start of publish", err),
 				cleanupStatusIfExists(params.Container.CollectionsStore(), collection.ID),
@@ -70,7 +84,7 @@ func PublishCollection(ctx context.Context, params Params) (dto.PublishCollectio
 	}
 
 	if err := validateCollection(collection); err != nil {
-		return dto.PublishCollectionResponse{}, cleanupOnError(
+		return dto.PublishJob{}, cleanupOnError(
 			ctx,
 			params.Container.Logger(),
 			err,
@@ -78,177 +92,40 @@ func PublishCollection(ctx context.Context, params Params) (dto.PublishCollectio
 		)
 	}
 
-	pennsieveDOIs, _ := GroupByDatasource(collection.DOIs)
-
-	// this is really a check on whether the collection contains any DOIs, not Pennsieve specific.
-	// It just so happens that there are only pennsieve DOIs now. I guess should really be
-	// len(pennsieveDOIs) + len(externalDOIs) == 0 if we ever get there.
-	if len(pennsieveDOIs) == 0 {
-		return dto.PublishCollectionResponse{}, cleanupOnError(ctx, params.Container.Logger(),
+	if len(collection.DOIs) == 0 {
+		return dto.PublishJob{}, cleanupOnError(ctx, params.Container.Logger(),
 			apierrors.NewConflictError("published collection must contain DOIs"),
 			cleanupStatus(params.Container.CollectionsStore(), collection.ID),
 		)
 	}
 
-	banners := make([]string, 0)
-	discoverDOIRes, err := params.Container.Discover().GetDatasetsByDOI(ctx, pennsieveDOIs)
-	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error getting DOI info from Discover", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-			)
-	}
-	if len(discoverDOIRes.Unpublished) > 0 {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewConflictError(fmt.Sprintf("collection contains unpublished DOIs: %s", strings.Join(slices.Collect(maps.Keys(discoverDOIRes.Unpublished)), ", "))),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-			)
-	}
-
-	banners = collectBanners(pennsieveDOIs, discoverDOIRes.Published)
-
-	userResp, err := params.Container.UsersStore().GetUser(ctx, userClaim.Id)
-	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error getting user information", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-			)
-	}
-
-	discoverPubReq := service.PublishDOICollectionRequest{
-		Name:             collection.Name,
-		Description:      collection.Description,
-		Banners:          banners,
-		DOIs:             pennsieveDOIs,
-		License:          *collection.License,
-		Tags:             collection.Tags,
-		OwnerID:          userClaim.Id,
-		OwnerNodeID:      userClaim.NodeId,
-		OwnerFirstName:   util.SafeDeref(userResp.FirstName),
-		OwnerLastName:    util.SafeDeref(userResp.LastName),
-		OwnerORCID:       util.SafeDeref(userResp.ORCID),
-		CollectionNodeID: collection.NodeID,
-		Contributors:     []service.InternalContributor{toInternalContributor(userClaim.Id, userResp)},
-	}
-
-	// Initiate publish to Discover
-	internalDiscover, err := params.Container.InternalDiscover(ctx)
-	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error getting internal Discover dependency", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-			)
-	}
-	discoverPubResp, err := internalDiscover.PublishCollection(ctx, collection.ID, collection.UserRole, discoverPubReq)
-	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error publishing to Discover", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-			)
-	}
-	params.Container.Logger().Info("publish started on Discover",
-		slog.Int("publishedDatasetId", discoverPubResp.PublishedDatasetID),
-		slog.Int("publishedVersion", discoverPubResp.PublishedVersion),
-		slog.Any("status", discoverPubResp.Status),
-		slog.String("ownerFirstName", discoverPubReq.OwnerFirstName),
-		slog.String("ownerLastName", discoverPubReq.OwnerLastName),
-		slog.String("ownerOrcid", discoverPubReq.OwnerORCID),
-	)
-
-	// Create manifest and copy to S3
-	manifest, err := publishing.NewManifestBuilder().
-		WithID(discoverPubResp.PublicID).
-		WithPennsieveDatasetID(discoverPubResp.PublishedDatasetID).
-		WithVersion(discoverPubResp.PublishedVersion).
-		WithName(collection.Name).
-		WithDescription(collection.Description).
-		WithCreator(creator(userResp)).
-		WithLicense(*collection.License).
-		WithKeywords(collection.Tags).
-		WithReferences(pennsieveDOIs).
-		WithSourceOrganization(params.Config.PennsieveConfig.CollectionsIDSpace.Name).
-		Build()
-	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx,
-				params.Container.Logger(),
-				apierrors.NewInternalServerError("error creating manifest", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
-			)
-	}
-
-	manifestKey := manifest.S3Key()
-	saveManifestResp, err := params.Container.ManifestStore().SaveManifest(ctx, manifestKey, manifest)
+	job, err := params.Container.CollectionsStore().CreatePublishJob(ctx, collection.ID, userClaim.Id)
 	if err != nil {
-		return dto.PublishCollectionResponse{},
-			// assuming if this failed then there is nothing to clean up in S3
-			cleanupOnError(ctx,
-				params.Container.Logger(),
-				apierrors.NewInternalServerError("error publishing manifest", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
-			)
+		return dto.PublishJob{}, cleanupOnError(ctx, params.Container.Logger(),
+			apierrors.NewInternalServerError("error creating publish job", err),
+			cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+		)
 	}
-	manifestS3VersionID := saveManifestResp.S3VersionID
-
-	params.Container.Logger().Info("wrote manifest to S3",
-		slog.String("key", manifestKey),
-		slog.String("s3VersionId", manifestS3VersionID))
 
-	discoverFinalizeReq := service.FinalizeDOICollectionPublishRequest{
-		PublishedDatasetID: discoverPubResp.PublishedDatasetID,
-		PublishedVersion:   discoverPubResp.PublishedVersion,
-		PublishSuccess:     true,
-		FileCount:          len(manifest.Files),
-		TotalSize:          manifest.TotalSize(),
-		ManifestKey:        manifestKey,
-		ManifestVersionID:  manifestS3VersionID,
-	}
-	discoverFinalizeResp, err := internalDiscover.FinalizeCollectionPublish(ctx, collection.ID, collection.NodeID, collection.UserRole, discoverFinalizeReq)
+	messageID, err := params.Container.PublishQueue().Send(ctx, job.ID)
 	if err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error finalizing publish with Discover", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
-				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
-			)
-	}
-	collectionsServiceStatus := discoverFinalizeResp.Status.ToPublishingStatus()
-	params.Container.Logger().Info("publish finalized on Discover",
-		slog.Any("discoverServiceStatus", discoverFinalizeResp.Status),
-		slog.Any("collectionsServiceStatus", collectionsServiceStatus),
-	)
-
-	// Mark publish as finished
-	if err := params.Container.CollectionsStore().FinishPublish(ctx, collection.ID, collectionsServiceStatus, true); err != nil {
-		return dto.PublishCollectionResponse{},
-			cleanupOnError(ctx, params.Container.Logger(),
-				apierrors.NewInternalServerError("error marking publish as complete", err),
-				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
-				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
-				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
-			)
+		return dto.PublishJob{}, cleanupOnError(ctx, params.Container.Logger(),
+			apierrors.NewInternalServerError("error queueing publish job", err),
+			cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			cleanupPublishJob(params.Container.CollectionsStore(), job.ID, "publish job could not be queued"),
+		)
 	}
+	params.Container.Logger().Info("queued publish job",
+		slog.String("jobId", job.ID),
+		slog.String("messageId", messageID))
 
-	publishResponse := dto.PublishCollectionResponse{
-		PublishedDatasetID: discoverPubResp.PublishedDatasetID,
-		PublishedVersion:   discoverPubResp.PublishedVersion,
-		Status:             discoverFinalizeResp.Status,
-	}
-	return publishResponse, nil
+	return ToDTOPublishJob(job), nil
 }
 
-func NewPublishCollectionRouteHandler() Handler[dto.PublishCollectionResponse] {
-	return Handler[dto.PublishCollectionResponse]{
+func NewPublishCollectionRouteHandler() Handler[dto.PublishJob] {
+	return Handler[dto.PublishJob]{
 		HandleFunc:        PublishCollection,
-		SuccessStatusCode: http.StatusOK,
+		SuccessStatusCode: http.StatusAccepted,
 		Headers:           DefaultResponseHeaders(),
 	}
 }
@@ -266,6 +143,26 @@ func validateCollection(collection collections.GetCollectionResponse) error {
 	return nil
 }
 
+// newCollectionManifestBuilder starts a manifest with the fields that come from the collection itself.
+// Callers add anything that only exists once the collection is published.
+func (p Params) newCollectionManifestBuilder(collection collections.GetCollectionResponse, collectionCreator publishing.PublishedContributor) *publishing.ManifestBuilder {
+	manifestBuilder := publishing.NewManifestBuilder().
+		WithName(collection.Name).
+		WithDescription(collection.Description).
+		WithCreator(collectionCreator).
+		WithLicense(util.SafeDeref(collection.License)).
+		WithKeywords(collection.Tags).
+		WithReferences(collection.DOIs.Strings()).
+		WithSourceOrganization(p.Config.PennsieveConfig.CollectionsIDSpace.Name)
+	for _, doi := range collection.DOIs {
+		manifestBuilder.WithReferenceAnnotation(doi.Value, doi.Label, doi.Note)
+	}
+	for _, section := range collection.Sections {
+		manifestBuilder.WithReferenceSection(section.Title, section.Description, section.DOIs)
+	}
+	return manifestBuilder
+}
+
 func creator(user users.GetUserResponse) publishing.PublishedContributor {
 	return publishing.PublishedContributor{
 		FirstName:     util.SafeDeref(user.FirstName),
@@ -319,6 +216,19 @@ func cleanupManifest(manifestStore manifests.Store, key string, s3VersionID stri
 	}
 }
 
+// cleanupPublishJob fails the given publish job, so that it is not left Queued when it will never run.
+func cleanupPublishJob(collectionsStore collections.Store, jobID string, reason string) cleanupFunc {
+	return func(ctx context.Context, logger *slog.Logger) error {
+		err := collectionsStore.FinishPublishJobAttempt(ctx, jobID, publishing.JobFailedStatus, collections.PublishJobResult{Error: &reason})
+		// Error is taken care of by cleanupOnError. Here we just want to log that the
+		// cleanup ran successfully
+		if err == nil {
+			logger.Info("cleanup set publish job status to failed", slog.String("jobId", jobID))
+		}
+		return err
+	}
+}
+
 func finalizeDiscoverFailure(discover service.InternalDiscover, publishedDatasetID, publishedVersion int, collection collections.GetCollectionResponse) cleanupFunc {
 	return func(ctx context.Context, logger *slog.Logger) error {
 		request := service.FinalizeDOICollectionPublishRequest{
@@ -2,18 +2,20 @@ package routes
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/apijson"
 	"github.com/pennsieve/collections-service/internal/api/config"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/service"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
 	"github.com/pennsieve/collections-service/internal/api/store/manifests"
-	"github.com/pennsieve/collections-service/internal/api/store/users"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
@@ -40,6 +42,7 @@ func TestPublishCollection(t *testing.T) {
 		{"publish collection with existing publish status", testPublishWithPublishStatus},
 		{"should return Conflict if description is empty", testPublishNoDescription},
 		{"should return Conflict if collection contains unpublished datasets", testPublishContainsTombstones},
+		{"should return Conflict if collection contains unresolvable external DOIs", testPublishContainsUnresolvedExternalDOIs},
 		{"should return Conflict if collection contains no DOIs", testPublishContainsNoDOIs},
 		{"should clean up publish status and Discover if SaveManifest fails", testPublishSaveManifestFails},
 		{"should clean up S3, publish status, and Discover if Discover finalize fails", testPublishFinalizeFails},
@@ -81,9 +84,10 @@ func testPublishWithoutPublishStatus(t *testing.T, expectationDB *fixtures.Expec
 
 	claims := apitest.DefaultClaims(callingUser)
 
-	// The dataset that will be in the collection
+	// The datasets that will be in the collection
 	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
 	dataset := expectedDatasets.NewPublished()
+	externalDataset := apitest.NewExpectedExternalDatasets().NewResolved()
 
 	// The collection
 	expectedCollection := apitest.NewExpectedCollection().
@@ -92,7 +96,11 @@ func testPublishWithoutPublishStatus(t *testing.T, expectationDB *fixtures.Expec
 		WithUser(*callingUser.ID, pgdb.Owner).
 		WithRandomLicense().
 		WithNTags(2).
-		WithPublicDatasets(dataset)
+		WithPublicDatasets(dataset).
+		WithExternalDatasets(externalDataset)
+	expectedCollection.DOIs[0].Label = uuid.NewString()
+	expectedCollection.DOIs[0].Note = uuid.NewString()
+	expectedCollection.DOIs[1].Label = uuid.NewString()
 	createCollectionResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
 
 	pennsieveConfig := apitest.PennsieveConfigWithOptions(config.WithPublishBucket(publishBucket))
@@ -139,6 +147,9 @@ func testPublishWithoutPublishStatus(t *testing.T, expectationDB *fixtures.Expec
 
 	pennsieveConfig.DiscoverServiceURL = mockDiscoverServer.URL
 
+	mockResolverServer := httptest.NewServer(mocks.NewDOIResolverMux(t).WithExternalDatasets(externalDataset))
+	defer mockResolverServer.Close()
+
 	apiConfig := apitest.NewConfigBuilder().
 		WithPostgresDBConfig(test.PostgresDBConfig(t)).
 		WithPennsieveConfig(pennsieveConfig).
@@ -154,24 +165,37 @@ func testPublishWithoutPublishStatus(t *testing.T, expectationDB *fixtures.Expec
 			WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
 			WithUsersStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
 			WithHTTPTestDiscover(mockDiscoverServer.URL).
+			WithHTTPTestExternalDOI(mockResolverServer.URL).
 			WithHTTPTestInternalDiscover(pennsieveConfig).
-			WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket),
+			WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket).
+			WithPublishQueue(queue.NewMemoryQueue()),
 		Config: apiConfig,
 		Claims: &claims,
 	}
 
-	resp, err := PublishCollection(ctx, params)
+	_, err := params.Container.CollectionsStore().CreateSection(ctx, createCollectionResp.ID, claims.UserClaim.Id, collections.CreateSectionRequest{
+		Title:       "External",
+		Description: "datasets published elsewhere",
+		DOIs:        []string{externalDataset.DOI},
+	})
+	require.NoError(t, err)
+
+	job, err := publishAndRunJob(ctx, t, params)
 	require.NoError(t, err)
 
-	assert.Equal(t, expectedPublishedDatasetID, resp.PublishedDatasetID)
-	assert.Equal(t, expectedPublishedVersion, resp.PublishedVersion)
-	assert.Equal(t, mockFinalizeDOICollectionResponse.Status, resp.Status)
+	assert.Equal(t, publishing.JobSucceededStatus, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Nil(t, job.Error)
+	require.NotNil(t, job.PublishedDatasetID)
+	assert.Equal(t, expectedPublishedDatasetID, *job.PublishedDatasetID)
+	require.NotNil(t, job.PublishedVersion)
+	assert.Equal(t, expectedPublishedVersion, *job.PublishedVersion)
 
 	expectedPublishStatus := collectionstest.NewExpectedCompletedPublishStatus(createCollectionResp.ID, *callingUser.ID)
 
 	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, nil)
 
-	manifestKey := publishing.ManifestS3Key(resp.PublishedDatasetID)
+	manifestKey := publishing.ManifestS3Key(*job.PublishedDatasetID)
 	headManifest := minio.RequireObjectExists(ctx, t, pennsieveConfig.PublishBucket, manifestKey)
 	var actualManifest publishing.ManifestV5
 	minio.GetObject(ctx, t, pennsieveConfig.PublishBucket, manifestKey, headManifest.VersionId).As(t, &actualManifest)
@@ -204,6 +228,24 @@ func testPublishWithoutPublishStatus(t *testing.T, expectationDB *fixtures.Expec
 	assert.Equal(t, publishing.ManifestPennsieveSchemaVersion, actualManifest.PennsieveSchemaVersion)
 
 	assert.Equal(t, expectedCollection.DOIs.Strings(), actualManifest.References.IDs)
+	assert.Equal(t, []publishing.ReferenceAnnotation{
+		{
+			ID:    dataset.DOI,
+			Label: expectedCollection.DOIs[0].Label,
+			Note:  expectedCollection.DOIs[0].Note,
+		},
+		{
+			ID:    externalDataset.DOI,
+			Label: expectedCollection.DOIs[1].Label,
+		},
+	}, actualManifest.References.Annotations)
+	assert.Equal(t, []publishing.ReferenceSection{
+		{
+			Title:       "External",
+			Description: "datasets published elsewhere",
+			IDs:         []string{externalDataset.DOI},
+		},
+	}, actualManifest.References.Sections)
 
 	expectedFileManifest := publishing.FileManifest{
 		Name:     publishing.ManifestFileName,
@@ -225,6 +267,8 @@ func testPublishWithPublishStatus(t *testing.T, expectationDB *fixtures.Expectat
 
 	startedAt := time.Now().UTC().AddDate(0, -1, 2)
 	finishedAt := startedAt.Add(time.Minute)
+	// recent enough that an in progress publish is not stale
+	inProgressStartedAt := time.Now().UTC().Add(-time.Minute)
 
 	tests := []struct {
 		scenario   string
@@ -234,13 +278,16 @@ func testPublishWithPublishStatus(t *testing.T, expectationDB *fixtures.Expectat
 		finishedAt *time.Time
 		allowed    bool
 	}{
-		{"in progress publication should not be allowed", publishing.PublicationType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress publication should not be allowed", publishing.PublicationType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress publication should be allowed", publishing.PublicationType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed publication should be allowed", publishing.PublicationType, publishing.CompletedStatus, startedAt, &finishedAt, true},
 		{"failed publication should be allowed", publishing.PublicationType, publishing.FailedStatus, startedAt, &finishedAt, true},
-		{"in progress revision should not be allowed", publishing.RevisionType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress revision should not be allowed", publishing.RevisionType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress revision should be allowed", publishing.RevisionType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed revision should be allowed", publishing.RevisionType, publishing.CompletedStatus, startedAt, &finishedAt, true},
 		{"failed revision should be allowed", publishing.RevisionType, publishing.FailedStatus, startedAt, &finishedAt, true},
-		{"in progress removal should not be allowed", publishing.RemovalType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress removal should not be allowed", publishing.RemovalType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress removal should be allowed", publishing.RemovalType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed removal should be allowed", publishing.RemovalType, publishing.CompletedStatus, startedAt, &finishedAt, true},
 		{"failed removal should be allowed", publishing.RemovalType, publishing.FailedStatus, startedAt, &finishedAt, true},
 	}
@@ -336,19 +383,22 @@ func testPublishWithPublishStatus(t *testing.T, expectationDB *fixtures.Expectat
 					WithUsersStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
 					WithHTTPTestDiscover(mockDiscoverServer.URL).
 					WithHTTPTestInternalDiscover(pennsieveConfig).
-					WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket),
+					WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket).
+					WithPublishQueue(queue.NewMemoryQueue()),
 				Config: apiConfig,
 				Claims: &claims,
 			}
 
-			resp, err := PublishCollection(ctx, params)
+			job, err := publishAndRunJob(ctx, t, params)
 
 			if tt.allowed {
 				require.NoError(t, err)
 
-				assert.Equal(t, expectedPublishedDatasetID, resp.PublishedDatasetID)
-				assert.Equal(t, expectedPublishedVersion, resp.PublishedVersion)
-				assert.Equal(t, mockFinalizeDOICollectionResponse.Status, resp.Status)
+				assert.Equal(t, publishing.JobSucceededStatus, job.Status)
+				require.NotNil(t, job.PublishedDatasetID)
+				assert.Equal(t, expectedPublishedDatasetID, *job.PublishedDatasetID)
+				require.NotNil(t, job.PublishedVersion)
+				assert.Equal(t, expectedPublishedVersion, *job.PublishedVersion)
 
 				expectedPublishStatus := collectionstest.NewExpectedCompletedPublishStatus(createCollectionResp.ID, *callingUser.ID)
 
@@ -456,18 +506,80 @@ func testPublishContainsTombstones(t *testing.T, expectationDB *fixtures.Expecta
 		Container: apitest.NewTestContainer().
 			WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
 			WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
-			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithPublishQueue(queue.NewMemoryQueue()),
 		Config: apiConfig,
 		Claims: &claims,
 	}
 
-	_, err := PublishCollection(ctx, params)
-	var apiError *apierrors.Error
-	require.ErrorAs(t, err, &apiError)
+	// the DOIs are checked by the publish worker, so the publish is accepted
+	job, err := publishAndRunJob(ctx, t, params)
+	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusConflict, apiError.StatusCode)
-	assert.Contains(t, apiError.UserMessage, "unpublished")
-	assert.Contains(t, apiError.UserMessage, tombstone.DOI)
+	// and the job fails without being retried
+	assert.Equal(t, publishing.JobFailedStatus, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	require.NotNil(t, job.Error)
+	assert.Contains(t, *job.Error, "unpublished")
+	assert.Contains(t, *job.Error, tombstone.DOI)
+
+	expectedPublishStatus := collectionstest.NewExpectedFailedPublishStatus(createCollectionResp.ID, *callingUser.ID)
+	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, nil)
+
+}
+
+func testPublishContainsUnresolvedExternalDOIs(t *testing.T, expectationDB *fixtures.ExpectationDB, _ *fixtures.MinIO) {
+	ctx := context.Background()
+
+	callingUser := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, callingUser)
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	// The datasets that will be in the collection
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	externalDataset := expectedExternalDatasets.NewResolved()
+	unresolvedDOI := expectedExternalDatasets.NewUnresolved()
+
+	// The collection
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(*callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithNTags(3).
+		WithExternalDatasets(externalDataset).
+		WithDOIs(collections.DOI{Value: unresolvedDOI, Datasource: datasource.External})
+	createCollectionResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+		Build()
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+			WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))).
+			WithPublishQueue(queue.NewMemoryQueue()),
+		Config: apiConfig,
+		Claims: &claims,
+	}
+
+	job, err := publishAndRunJob(ctx, t, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, publishing.JobFailedStatus, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	require.NotNil(t, job.Error)
+	assert.Contains(t, *job.Error, "could not be resolved")
+	assert.Contains(t, *job.Error, unresolvedDOI)
+	assert.NotContains(t, *job.Error, externalDataset.DOI)
 
 	expectedPublishStatus := collectionstest.NewExpectedFailedPublishStatus(createCollectionResp.ID, *callingUser.ID)
 	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, nil)
@@ -597,6 +709,7 @@ func testPublishSaveManifestFails(t *testing.T, expectationDB *fixtures.Expectat
 	apiConfig := apitest.NewConfigBuilder().
 		WithPostgresDBConfig(test.PostgresDBConfig(t)).
 		WithPennsieveConfig(pennsieveConfig).
+		WithPublishJobMaxAttempts(2).
 		Build()
 
 	params := Params{
@@ -610,17 +723,26 @@ func testPublishSaveManifestFails(t *testing.T, expectationDB *fixtures.Expectat
 			WithUsersStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
 			WithHTTPTestDiscover(mockDiscoverServer.URL).
 			WithHTTPTestInternalDiscover(pennsieveConfig).
-			WithManifestStore(mockManifestStore),
+			WithManifestStore(mockManifestStore).
+			WithPublishQueue(queue.NewMemoryQueue()),
 		Config: apiConfig,
 		Claims: &claims,
 	}
 
-	_, err := PublishCollection(ctx, params)
+	job, err := publishAndRunJob(ctx, t, params)
+	require.NoError(t, err)
 
-	var apiError *apierrors.Error
-	require.ErrorAs(t, err, &apiError)
+	// the first failure is retried, so the publish is still in progress
+	assert.Equal(t, publishing.JobQueuedStatus, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	require.NotNil(t, job.Error)
+	assert.Contains(t, *job.Error, "error publishing manifest")
+	expectationDB.RequirePublishStatus(ctx, t, collectionstest.NewExpectedInProgressPublishStatus(createCollectionResp.ID, *callingUser.ID), nil)
 
-	assert.Equal(t, http.StatusInternalServerError, apiError.StatusCode)
+	// the second is the last attempt
+	job = runNextPublishJob(ctx, t, params)
+	assert.Equal(t, publishing.JobFailedStatus, job.Status)
+	assert.Equal(t, 2, job.Attempts)
 
 	expectedPublishStatus := collectionstest.NewExpectedFailedPublishStatus(createCollectionResp.ID, *callingUser.ID)
 
@@ -693,9 +815,11 @@ func testPublishFinalizeFails(t *testing.T, expectationDB *fixtures.ExpectationD
 
 	pennsieveConfig.DiscoverServiceURL = mockDiscoverServer.URL
 
+	// no retries, so that there is one attempt to check
 	apiConfig := apitest.NewConfigBuilder().
 		WithPostgresDBConfig(test.PostgresDBConfig(t)).
 		WithPennsieveConfig(pennsieveConfig).
+		WithPublishJobMaxAttempts(1).
 		Build()
 
 	params := Params{
@@ -709,16 +833,20 @@ func testPublishFinalizeFails(t *testing.T, expectationDB *fixtures.ExpectationD
 			WithUsersStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
 			WithHTTPTestDiscover(mockDiscoverServer.URL).
 			WithHTTPTestInternalDiscover(pennsieveConfig).
-			WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket),
+			WithMinIOManifestStore(ctx, t, apiConfig.PennsieveConfig.PublishBucket).
+			WithPublishQueue(queue.NewMemoryQueue()),
 		Config: apiConfig,
 		Claims: &claims,
 	}
 
-	_, err := PublishCollection(ctx, params)
-	var apiError *apierrors.Error
-	require.ErrorAs(t, err, &apiError)
+	job, err := publishAndRunJob(ctx, t, params)
+	require.NoError(t, err)
 
-	assert.Equal(t, http.StatusInternalServerError, apiError.StatusCode)
+	assert.Equal(t, publishing.JobFailedStatus, job.Status)
+	require.NotNil(t, job.Error)
+	assert.Contains(t, *job.Error, "error finalizing publish with Discover")
+	require.NotNil(t, job.Step)
+	assert.Equal(t, publishing.FinalizingWithDiscoverStep, *job.Step)
 
 	expectedPublishStatus := collectionstest.NewExpectedFailedPublishStatus(createCollectionResp.ID, *callingUser.ID)
 
@@ -766,6 +894,14 @@ func TestHandlePublishCollection(t *testing.T) {
 			"return Conflict when a publish is already in progress",
 			testHandlePublishCollectionPublishAlreadyInProgress,
 		},
+		{
+			"return Precondition Failed when If-Match does not match current version",
+			testHandlePublishCollectionIfMatchMismatch,
+		},
+		{
+			"collection changes after If-Match check",
+			testHandlePublishCollectionIfMatchChanged,
+		},
 	}
 
 	for _, tt := range tests {
@@ -992,8 +1128,6 @@ func testHandlePublishCollectionAuthz(t *testing.T) {
 			expectedDatasets := apitest.NewExpectedPennsieveDatasets()
 			dataset := expectedDatasets.NewPublished()
 
-			mockDiscover := mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))
-
 			expectedCollection := apitest.NewExpectedCollection().
 				WithRandomID().
 				WithNodeID().
@@ -1002,61 +1136,17 @@ func testHandlePublishCollectionAuthz(t *testing.T) {
 				WithRandomLicense().
 				WithNTags(2)
 
+			expectedJob := collectionstest.NewQueuedPublishJob(*expectedCollection.ID, *expectedCollection.NodeID, callingUser.ID, callingUser.NodeID)
 			mockCollectionStore := mocks.NewCollectionsStore().
 				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
 				WithStartPublishFunc(expectedCollection.StartPublishFunc(t, callingUser.ID, publishing.PublicationType)).
-				WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.CompletedStatus))
-
-			expectedPublishedID := 14
-			expectedPublishedVersion := 1
-			mockPublishDOICollectionResponse := service.PublishDOICollectionResponse{
-				PublishedDatasetID: expectedPublishedID,
-				PublishedVersion:   expectedPublishedVersion,
-				Status:             dto.PublishInProgress,
-			}
-
-			mockFinalizeDOICollectionResponse := service.FinalizeDOICollectionPublishResponse{Status: dto.PublishSucceeded}
-			expectedManifestS3VersionID := uuid.NewString()
-
-			var capturedManifestTotalSize int64
-			mockManifestStore := mocks.NewManifestStore().WithSaveManifestFunc(func(ctx context.Context, key string, manifest publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
-				require.Equal(t, publishing.ManifestS3Key(expectedPublishedID), key)
-				require.Equal(t, expectedPublishedID, manifest.PennsieveDatasetID)
-				require.Equal(t, expectedCollection.Name, manifest.Name)
-				require.Equal(t, callingUser.LastName, manifest.Creator.LastName)
-				capturedManifestTotalSize = manifest.TotalSize()
-				return manifests.SaveManifestResponse{
-					S3VersionID: expectedManifestS3VersionID,
-				}, nil
-			})
-
-			mockInternalDiscover := mocks.NewInternalDiscover().
-				WithPublishCollectionFunc(
-					expectedCollection.PublishCollectionFunc(t, mockPublishDOICollectionResponse,
-						apitest.VerifyPublishingUser(callingUser),
-						apitest.VerifyInternalContributors(apitest.InternalContributor(callingUser)),
-					),
-				).
-				WithFinalizeCollectionPublishFunc(
-					expectedCollection.FinalizeCollectionPublishFunc(t, mockFinalizeDOICollectionResponse,
-						apitest.VerifyFinalizeDOICollectionRequest(expectedPublishedID, expectedPublishedVersion),
-						apitest.VerifyFinalizeDOICollectionRequestS3VersionID(expectedManifestS3VersionID),
-						apitest.VerifyFinalizeDOICollectionRequestTotalSize(func() int64 {
-							return capturedManifestTotalSize
-						}),
-					),
-				)
-
-			mockUsersStore := mocks.NewUsersStore().WithGetUserFunc(func(ctx context.Context, userID int64) (users.GetUserResponse, error) {
-				t.Helper()
-				require.Equal(t, callingUser.ID, userID)
-				return users.GetUserResponse{
-					FirstName: &callingUser.FirstName,
-					LastName:  &callingUser.LastName,
-				}, nil
-			})
+				WithCreatePublishJobFunc(func(_ context.Context, collectionID int64, userID int64) (collections.PublishJob, error) {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, callingUser.ID, userID)
+					return expectedJob, nil
+				})
 
-			pennsieveConfig := apitest.PennsieveConfigWithFakeURL()
+			publishQueue := queue.NewMemoryQueue()
 
 			params := Params{
 				Request: apitest.NewAPIGatewayRequestBuilder(PublishCollectionRouteKey).
@@ -1065,18 +1155,24 @@ func testHandlePublishCollectionAuthz(t *testing.T) {
 					Build(),
 				Container: apitest.NewTestContainer().
 					WithCollectionsStore(mockCollectionStore).
-					WithDiscover(mockDiscover).
-					WithInternalDiscover(mockInternalDiscover).
-					WithUsersStore(mockUsersStore).
-					WithManifestStore(mockManifestStore),
-				Config: apitest.NewConfigBuilder().WithPennsieveConfig(pennsieveConfig).Build(),
+					WithPublishQueue(publishQueue),
+				Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
 				Claims: &claims,
 			}
 
 			resp, err := Handle(ctx, NewPublishCollectionRouteHandler(), params)
 			require.NoError(t, err)
 
-			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+			var respJob dto.PublishJob
+			require.NoError(t, json.Unmarshal([]byte(resp.Body), &respJob))
+			assert.Equal(t, expectedJob.ID, respJob.JobID)
+			assert.Equal(t, publishing.JobQueuedStatus, respJob.Status)
+
+			messages, err := publishQueue.Receive(ctx, 10, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, expectedJob.ID, messages[0].Body)
 		})
 	}
 
@@ -1094,7 +1190,7 @@ func testHandlePublishCollectionPublishAlreadyInProgress(t *testing.T) {
 
 	mockCollectionStore := mocks.NewCollectionsStore().
 		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
-		WithStartPublishFunc(func(_ context.Context, _ int64, _ int64, _ publishing.Type) error {
+		WithStartPublishFunc(func(_ context.Context, _ int64, _ int64, _ publishing.Type, _ *int64) error {
 			return collections.ErrPublishInProgress
 		})
 
@@ -1116,3 +1212,110 @@ func testHandlePublishCollectionPublishAlreadyInProgress(t *testing.T) {
 
 	assert.Contains(t, response.Body, "in progress")
 }
+
+func testHandlePublishCollectionIfMatchMismatch(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithDOIs(apitest.NewPennsieveDOI()).
+		WithVersion(2)
+
+	// No StartPublishFunc since we should fail before publish status is touched
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(IfMatchHeader, ETag(1)).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewPublishCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+	assert.Contains(t, response.Body, "has changed")
+}
+
+func testHandlePublishCollectionIfMatchChanged(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithDOIs(apitest.NewPennsieveDOI()).
+		WithVersion(2)
+
+	// someone else updates the collection between GetCollection and StartPublish.
+	// No FinishPublishFunc since the publish status should be left alone.
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithStartPublishFunc(func(_ context.Context, _ int64, _ int64, _ publishing.Type, expectedVersion *int64) error {
+			require.NotNil(t, expectedVersion)
+			assert.Equal(t, int64(2), *expectedVersion)
+			return collections.ErrCollectionVersionMismatch
+		})
+
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithHeader(IfMatchHeader, ETag(2)).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+	response, err := Handle(ctx, NewPublishCollectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+	assert.Contains(t, response.Body, "has changed")
+}
+
+// publishAndRunJob calls PublishCollection and, if it queues a job, runs the job the way the publish worker does.
+// Returns the error of PublishCollection, or the job as it is after the run.
+func publishAndRunJob(ctx context.Context, t *testing.T, params Params) (collections.PublishJob, error) {
+	t.Helper()
+	queued, err := PublishCollection(ctx, params)
+	if err != nil {
+		return collections.PublishJob{}, err
+	}
+	require.Equal(t, publishing.JobQueuedStatus, queued.Status)
+	job := runNextPublishJob(ctx, t, params)
+	require.Equal(t, queued.JobID, job.ID)
+	return job, nil
+}
+
+// runNextPublishJob runs the next job in the publish queue the way the publish worker does and returns the job as it is after the run.
+// The message is received with no visibility timeout, so a job that is not finished can be run again right away.
+func runNextPublishJob(ctx context.Context, t *testing.T, params Params) collections.PublishJob {
+	t.Helper()
+	messages, err := params.Container.PublishQueue().Receive(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	finished, err := RunPublishJob(ctx, params, messages[0].Body)
+	require.NoError(t, err)
+	if finished {
+		require.NoError(t, params.Container.PublishQueue().Delete(ctx, messages[0].ReceiptHandle))
+	}
+
+	job, err := params.Container.CollectionsStore().GetPublishJob(ctx, messages[0].Body)
+	require.NoError(t, err)
+	return job
+}
@@ -0,0 +1,354 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/store/users"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RunPublishJob runs one attempt of the publish job with the given id, doing the steps of publishing that
+// PublishCollection leaves to the publish worker. Only params.Container and params.Config are used.
+//
+// Failures caused by the collection, for example unpublished DOIs, fail the job and the publish right away. Other failures
+// put the job back in the queue with the publish still InProgress, until the job has been tried params.Config.PublishJobMaxAttempts times.
+//
+// Returns true if the job is finished, successfully or not, so that its queue message can be deleted.
+// A non-nil error means the outcome of the attempt could not be recorded.
+func RunPublishJob(ctx context.Context, params Params, jobID string) (bool, error) {
+	collectionsStore := params.Container.CollectionsStore()
+	job, err := collectionsStore.StartPublishJobAttempt(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, collections.ErrPublishJobNotFound) || errors.Is(err, collections.ErrPublishJobFinished) {
+			params.Container.Logger().Warn("not running publish job",
+				slog.String("jobId", jobID),
+				slog.String("reason", err.Error()))
+			return true, nil
+		}
+		return false, fmt.Errorf("error starting attempt of publish job %s: %w", jobID, err)
+	}
+	params.Container.AddLoggingContext(
+		slog.String("jobId", job.ID),
+		slog.String(NodeIDPathParamKey, job.CollectionNodeID),
+		slog.Int("attempt", job.Attempts))
+
+	collection, err := getPublishJobCollection(ctx, params, job)
+	if err != nil {
+		if isRetryablePublishError(err) && job.Attempts < params.Config.PublishJobMaxAttempts {
+			params.Container.Logger().Warn("error getting collection of publish job; will retry",
+				slog.Int("maxAttempts", params.Config.PublishJobMaxAttempts),
+				slog.Any("error", err))
+			return false, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobQueuedStatus, collections.PublishJobResult{
+				Error: publishJobError(err),
+			})
+		}
+		// The publish status belongs to someone else, or to nobody we can act for. If it is still InProgress
+		// the publish reconciler will finish it once it is stale.
+		params.Container.Logger().Error("publish job cannot run", slog.Any("error", err))
+		return true, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobFailedStatus, collections.PublishJobResult{
+			Error: publishJobError(err),
+		})
+	}
+
+	publishResponse, err := runPublishSteps(ctx, params, job, collection)
+	if err == nil {
+		return true, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobSucceededStatus, collections.PublishJobResult{
+			PublishedDatasetID: &publishResponse.PublishedDatasetID,
+			PublishedVersion:   &publishResponse.PublishedVersion,
+		})
+	}
+
+	if isRetryablePublishError(err) && job.Attempts < params.Config.PublishJobMaxAttempts {
+		params.Container.Logger().Warn("publish job attempt failed; will retry",
+			slog.Int("maxAttempts", params.Config.PublishJobMaxAttempts),
+			slog.Any("error", err))
+		return false, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobQueuedStatus, collections.PublishJobResult{
+			Error: publishJobError(err),
+		})
+	}
+
+	err = cleanupOnError(ctx, params.Container.Logger(), err, cleanupStatus(collectionsStore, collection.ID))
+	params.Container.Logger().Error("publish job failed", slog.Any("error", err))
+	return true, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobFailedStatus, collections.PublishJobResult{
+		Error: publishJobError(err),
+	})
+}
+
+// getPublishJobCollection returns the collection of the job as seen by the user who started the job. Returns an error
+// if the collection is gone or if its publish status is no longer the InProgress publication that the job is running.
+func getPublishJobCollection(ctx context.Context, params Params, job collections.PublishJob) (collections.GetCollectionResponse, error) {
+	if job.UserID == nil {
+		return collections.GetCollectionResponse{}, apierrors.NewConflictError("user who started the publish has been deleted")
+	}
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, *job.UserID, job.CollectionNodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return collections.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(job.CollectionNodeID)
+		}
+		return collections.GetCollectionResponse{}, apierrors.NewInternalServerError("error querying store for collection to publish", err)
+	}
+	publication := collection.Publication
+	if publication == nil || publication.Status != publishing.InProgressStatus || publication.Type != publishing.PublicationType {
+		return collections.GetCollectionResponse{}, apierrors.NewConflictError("publish is no longer in progress")
+	}
+	return collection, nil
+}
+
+// runPublishSteps publishes the collection to Discover and writes its manifest to S3. On error, whatever was done
+// in Discover and S3 is undone, but the publish status is left InProgress for RunPublishJob to decide whether to retry.
+func runPublishSteps(ctx context.Context, params Params, job collections.PublishJob, collection collections.GetCollectionResponse) (dto.PublishCollectionResponse, error) {
+	collectionsStore := params.Container.CollectionsStore()
+	startStep := func(step publishing.JobStep) error {
+		if err := collectionsStore.UpdatePublishJobStep(ctx, job.ID, step); err != nil {
+			return apierrors.NewInternalServerError("error updating publish job step", err)
+		}
+		return nil
+	}
+
+	if !collection.UserRole.Implies(minPublishRole) {
+		return dto.PublishCollectionResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not published; requires user role: %s",
+				collection.NodeID,
+				minPublishRole),
+		)
+	}
+	userID := *job.UserID
+	userNodeID := util.SafeDeref(job.UserNodeID)
+
+	if err := startStep(publishing.CheckingDOIsStep); err != nil {
+		return dto.PublishCollectionResponse{}, err
+	}
+	banners, err := checkPublishDOIs(ctx, params, collection)
+	if err != nil {
+		return dto.PublishCollectionResponse{}, err
+	}
+
+	if err := startStep(publishing.PublishingToDiscoverStep); err != nil {
+		return dto.PublishCollectionResponse{}, err
+	}
+	userResp, err := params.Container.UsersStore().GetUser(ctx, userID)
+	if err != nil {
+		return dto.PublishCollectionResponse{}, apierrors.NewInternalServerError("error getting user information", err)
+	}
+	discoverPubReq := newPublishDOICollectionRequest(collection, userID, userNodeID, userResp, banners)
+
+	// Initiate publish to Discover
+	internalDiscover, err := params.Container.InternalDiscover(ctx)
+	if err != nil {
+		return dto.PublishCollectionResponse{}, apierrors.NewInternalServerError("error getting internal Discover dependency", err)
+	}
+	discoverPubResp, err := internalDiscover.PublishCollection(ctx, collection.ID, collection.UserRole, discoverPubReq)
+	if err != nil {
+		return dto.PublishCollectionResponse{}, apierrors.NewInternalServerError("error publishing to Discover", err)
+	}
+	params.Container.Logger().Info("publish started on Discover",
+		slog.Int("publishedDatasetId", discoverPubResp.PublishedDatasetID),
+		slog.Int("publishedVersion", discoverPubResp.PublishedVersion),
+		slog.Any("status", discoverPubResp.Status),
+		slog.String("ownerFirstName", discoverPubReq.OwnerFirstName),
+		slog.String("ownerLastName", discoverPubReq.OwnerLastName),
+		slog.String("ownerOrcid", discoverPubReq.OwnerORCID),
+	)
+
+	// Create manifest and copy to S3
+	if err := startStep(publishing.SavingManifestStep); err != nil {
+		return dto.PublishCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				err,
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+	manifest, err := params.newCollectionManifestBuilder(collection, creator(userResp)).
+		WithID(discoverPubResp.PublicID).
+		WithPennsieveDatasetID(discoverPubResp.PublishedDatasetID).
+		WithVersion(discoverPubResp.PublishedVersion).
+		Build()
+	if err != nil {
+		return dto.PublishCollectionResponse{},
+			cleanupOnError(ctx,
+				params.Container.Logger(),
+				apierrors.NewInternalServerError("error creating manifest", err),
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+
+	manifestKey := manifest.S3Key()
+	saveManifestResp, err := params.Container.ManifestStore().SaveManifest(ctx, manifestKey, manifest)
+	if err != nil {
+		return dto.PublishCollectionResponse{},
+			// assuming if this failed then there is nothing to clean up in S3
+			cleanupOnError(ctx,
+				params.Container.Logger(),
+				apierrors.NewInternalServerError("error publishing manifest", err),
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+	manifestS3VersionID := saveManifestResp.S3VersionID
+
+	params.Container.Logger().Info("wrote manifest to S3",
+		slog.String("key", manifestKey),
+		slog.String("s3VersionId", manifestS3VersionID))
+
+	if err := startStep(publishing.FinalizingWithDiscoverStep); err != nil {
+		return dto.PublishCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				err,
+				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+	discoverFinalizeReq := service.FinalizeDOICollectionPublishRequest{
+		PublishedDatasetID: discoverPubResp.PublishedDatasetID,
+		PublishedVersion:   discoverPubResp.PublishedVersion,
+		PublishSuccess:     true,
+		FileCount:          len(manifest.Files),
+		TotalSize:          manifest.TotalSize(),
+		ManifestKey:        manifestKey,
+		ManifestVersionID:  manifestS3VersionID,
+	}
+	discoverFinalizeResp, err := internalDiscover.FinalizeCollectionPublish(ctx, collection.ID, collection.NodeID, collection.UserRole, discoverFinalizeReq)
+	if err != nil {
+		return dto.PublishCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error finalizing publish with Discover", err),
+				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+	collectionsServiceStatus := discoverFinalizeResp.Status.ToPublishingStatus()
+	params.Container.Logger().Info("publish finalized on Discover",
+		slog.Any("discoverServiceStatus", discoverFinalizeResp.Status),
+		slog.Any("collectionsServiceStatus", collectionsServiceStatus),
+	)
+
+	// Mark publish as finished. If the step cannot be recorded, finishing the publish is still the right thing to do.
+	if err := collectionsStore.UpdatePublishJobStep(ctx, job.ID, publishing.FinishingPublishStep); err != nil {
+		params.Container.Logger().Warn("error updating publish job step", slog.Any("error", err))
+	}
+	if err := collectionsStore.FinishPublish(ctx, collection.ID, collectionsServiceStatus, true); err != nil {
+		return dto.PublishCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error marking publish as complete", err),
+				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
+				finalizeDiscoverFailure(internalDiscover, discoverPubResp.PublishedDatasetID, discoverPubResp.PublishedVersion, collection),
+			)
+	}
+
+	return dto.PublishCollectionResponse{
+		PublishedDatasetID: discoverPubResp.PublishedDatasetID,
+		PublishedVersion:   discoverPubResp.PublishedVersion,
+		Status:             discoverFinalizeResp.Status,
+	}, nil
+}
+
+// checkPublishDOIs returns a Conflict error if any DOI of the collection is unpublished, is another collection, or cannot be resolved.
+// Otherwise returns the banners of the Pennsieve datasets, for the Discover publish request.
+func checkPublishDOIs(ctx context.Context, params Params, collection collections.GetCollectionResponse) ([]string, error) {
+	pennsieveDOIs, externalDOIs := GroupByDatasource(collection.DOIs)
+
+	banners := make([]string, 0)
+	if len(pennsieveDOIs) > 0 {
+		// not cached: a collection should not be published because of stale dataset info
+		discoverDOIRes, err := params.Container.UncachedDiscover().GetDatasetsByDOI(ctx, pennsieveDOIs)
+		if err != nil {
+			return nil, apierrors.NewInternalServerError("error getting DOI info from Discover", err)
+		}
+		if len(discoverDOIRes.Unpublished) > 0 {
+			return nil,
+				apierrors.NewConflictError(fmt.Sprintf("collection contains unpublished DOIs: %s", strings.Join(slices.Collect(maps.Keys(discoverDOIRes.Unpublished)), ", ")))
+		}
+		var collectionDOIs []string
+		for _, doi := range pennsieveDOIs {
+			if published, isPublished := discoverDOIRes.Published[doi]; isPublished && isCollectionDataset(published) {
+				collectionDOIs = append(collectionDOIs, doi)
+			}
+		}
+		if len(collectionDOIs) > 0 {
+			return nil,
+				apierrors.NewConflictError(fmt.Sprintf("collection contains DOIs of other collections: %s", strings.Join(collectionDOIs, ", ")))
+		}
+
+		banners = collectBanners(pennsieveDOIs, discoverDOIRes.Published)
+	}
+
+	if len(externalDOIs) > 0 {
+		resolveRes, err := params.Container.ExternalDOI().ResolveDOIs(ctx, externalDOIs)
+		if err != nil {
+			return nil, apierrors.NewInternalServerError("error resolving non-Pennsieve DOIs", err)
+		}
+		if len(resolveRes.Unresolved) > 0 {
+			return nil,
+				apierrors.NewConflictError(fmt.Sprintf("collection contains DOIs that could not be resolved: %s", strings.Join(resolveRes.Unresolved, ", ")))
+		}
+	}
+	return banners, nil
+}
+
+// newPublishDOICollectionRequest returns the request that publishes the collection to Discover on behalf of the given user.
+func newPublishDOICollectionRequest(collection collections.GetCollectionResponse, userID int64, userNodeID string, userResp users.GetUserResponse, banners []string) service.PublishDOICollectionRequest {
+	return service.PublishDOICollectionRequest{
+		Name:             collection.Name,
+		Description:      collection.Description,
+		Banners:          banners,
+		DOIs:             collection.DOIs.Strings(),
+		License:          util.SafeDeref(collection.License),
+		Tags:             collection.Tags,
+		OwnerID:          userID,
+		OwnerNodeID:      userNodeID,
+		OwnerFirstName:   util.SafeDeref(userResp.FirstName),
+		OwnerLastName:    util.SafeDeref(userResp.LastName),
+		OwnerORCID:       util.SafeDeref(userResp.ORCID),
+		CollectionNodeID: collection.NodeID,
+		Contributors:     []service.InternalContributor{toInternalContributor(userID, userResp)},
+	}
+}
+
+// isRetryablePublishError returns false for errors that another attempt would only repeat, that is, those caused by
+// the collection or the user rather than by a failing dependency.
+func isRetryablePublishError(err error) bool {
+	var apiErr *apierrors.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// publishJobError is the error recorded on a publish job. Callers of GET /{nodeId}/publish/status see it, so it is the
+// user message of the error rather than its cause.
+func publishJobError(err error) *string {
+	var apiErr *apierrors.Error
+	if errors.As(err, &apiErr) {
+		return &apiErr.UserMessage
+	}
+	message := err.Error()
+	return &message
+}
+
+func ToDTOPublishJob(job collections.PublishJob) dto.PublishJob {
+	var step publishing.JobStep
+	if job.Step != nil {
+		step = *job.Step
+	}
+	return dto.PublishJob{
+		JobID:              job.ID,
+		Status:             job.Status,
+		Step:               step,
+		Attempts:           job.Attempts,
+		Error:              util.SafeDeref(job.Error),
+		PublishedDatasetID: job.PublishedDatasetID,
+		PublishedVersion:   job.PublishedVersion,
+		CreatedAt:          job.CreatedAt,
+		UpdatedAt:          job.UpdatedAt,
+	}
+}
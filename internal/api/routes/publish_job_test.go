@@ -0,0 +1,460 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/store/manifests"
+	"github.com/pennsieve/collections-service/internal/api/store/users"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestRunPublishJob tests RunPublishJob against mocked dependencies. The tests of PublishCollection run
+// publish jobs against real ones.
+func TestRunPublishJob(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"run publish job", testRunPublishJob},
+		{"run publish job, Discover fails, retried", testRunPublishJobRetried},
+		{"run publish job, Discover fails on last attempt", testRunPublishJobLastAttempt},
+		{"run publish job, unpublished DOIs are not retried", testRunPublishJobUnpublishedDOIs},
+		{"run publish job, collection DOIs are not retried", testRunPublishJobCollectionDOIs},
+		{"run publish job, finished job is skipped", testRunPublishJobFinished},
+		{"run publish job, publish no longer in progress", testRunPublishJobNotInProgress},
+		{"run publish job, attempt cannot be started", testRunPublishJobStartError},
+		{"run publish job, store error getting collection is retried", testRunPublishJobGetCollectionError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+// publishJobRecorder backs the publish job methods of a mock collections store with a single job.
+type publishJobRecorder struct {
+	job    collections.PublishJob
+	steps  []publishing.JobStep
+	result *collections.PublishJobResult
+}
+
+func newPublishJobRecorder(expectedCollection *apitest.ExpectedCollection, user userstest.SeedUser) *publishJobRecorder {
+	return &publishJobRecorder{
+		job: collectionstest.NewQueuedPublishJob(*expectedCollection.ID, *expectedCollection.NodeID, user.ID, user.NodeID),
+	}
+}
+
+func (r *publishJobRecorder) withAttempts(attempts int) *publishJobRecorder {
+	r.job.Attempts = attempts
+	return r
+}
+
+func (r *publishJobRecorder) mockStore(t *testing.T, store *mocks.CollectionsStore) *mocks.CollectionsStore {
+	return store.
+		WithStartPublishJobAttemptFunc(func(_ context.Context, jobID string) (collections.PublishJob, error) {
+			require.Equal(t, r.job.ID, jobID)
+			r.job = collectionstest.NewRunningPublishJob(r.job)
+			return r.job, nil
+		}).
+		WithUpdatePublishJobStepFunc(func(_ context.Context, jobID string, step publishing.JobStep) error {
+			require.Equal(t, r.job.ID, jobID)
+			r.steps = append(r.steps, step)
+			return nil
+		}).
+		WithFinishPublishJobAttemptFunc(func(_ context.Context, jobID string, status publishing.JobStatus, result collections.PublishJobResult) error {
+			require.Equal(t, r.job.ID, jobID)
+			r.job.Status = status
+			r.result = &result
+			return nil
+		})
+}
+
+func publishJobUsersStore(t *testing.T, user userstest.SeedUser) *mocks.UsersStore {
+	return mocks.NewUsersStore().WithGetUserFunc(func(_ context.Context, userID int64) (users.GetUserResponse, error) {
+		require.Equal(t, user.ID, userID)
+		return users.GetUserResponse{
+			FirstName: &user.FirstName,
+			LastName:  &user.LastName,
+		}, nil
+	})
+}
+
+func testRunPublishJob(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	dataset := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(dataset).
+		WithRandomLicense().
+		WithNTags(2)
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser)
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)).
+		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.CompletedStatus)))
+
+	expectedPublishedID := 14
+	expectedPublishedVersion := 1
+	mockPublishDOICollectionResponse := service.PublishDOICollectionResponse{
+		PublishedDatasetID: expectedPublishedID,
+		PublishedVersion:   expectedPublishedVersion,
+		Status:             dto.PublishInProgress,
+	}
+	mockFinalizeDOICollectionResponse := service.FinalizeDOICollectionPublishResponse{Status: dto.PublishSucceeded}
+	expectedManifestS3VersionID := uuid.NewString()
+
+	var capturedManifestTotalSize int64
+	mockManifestStore := mocks.NewManifestStore().WithSaveManifestFunc(func(_ context.Context, key string, manifest publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
+		require.Equal(t, publishing.ManifestS3Key(expectedPublishedID), key)
+		require.Equal(t, expectedPublishedID, manifest.PennsieveDatasetID)
+		require.Equal(t, expectedCollection.Name, manifest.Name)
+		require.Equal(t, callingUser.LastName, manifest.Creator.LastName)
+		capturedManifestTotalSize = manifest.TotalSize()
+		return manifests.SaveManifestResponse{S3VersionID: expectedManifestS3VersionID}, nil
+	})
+
+	mockInternalDiscover := mocks.NewInternalDiscover().
+		WithPublishCollectionFunc(
+			expectedCollection.PublishCollectionFunc(t, mockPublishDOICollectionResponse,
+				apitest.VerifyPublishingUser(callingUser),
+				apitest.VerifyInternalContributors(apitest.InternalContributor(callingUser)),
+			),
+		).
+		WithFinalizeCollectionPublishFunc(
+			expectedCollection.FinalizeCollectionPublishFunc(t, mockFinalizeDOICollectionResponse,
+				apitest.VerifyFinalizeDOICollectionRequest(expectedPublishedID, expectedPublishedVersion),
+				apitest.VerifyFinalizeDOICollectionRequestS3VersionID(expectedManifestS3VersionID),
+				apitest.VerifyFinalizeDOICollectionRequestTotalSize(func() int64 {
+					return capturedManifestTotalSize
+				}),
+			),
+		)
+
+	params := Params{
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithInternalDiscover(mockInternalDiscover).
+			WithUsersStore(publishJobUsersStore(t, callingUser)).
+			WithManifestStore(mockManifestStore),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+	}
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	assert.Equal(t, publishing.JobSucceededStatus, recorder.job.Status)
+	assert.Equal(t, 1, recorder.job.Attempts)
+	assert.Equal(t, publishing.JobSteps, recorder.steps)
+	require.NotNil(t, recorder.result)
+	assert.Nil(t, recorder.result.Error)
+	require.NotNil(t, recorder.result.PublishedDatasetID)
+	assert.Equal(t, expectedPublishedID, *recorder.result.PublishedDatasetID)
+	require.NotNil(t, recorder.result.PublishedVersion)
+	assert.Equal(t, expectedPublishedVersion, *recorder.result.PublishedVersion)
+}
+
+// publishFailingParams returns Params where publishing to Discover fails, so there is nothing in Discover or S3 to clean up.
+func publishFailingParams(t *testing.T, expectedCollection *apitest.ExpectedCollection, callingUser userstest.SeedUser, collectionsStore *mocks.CollectionsStore, maxAttempts int) Params {
+	mockInternalDiscover := mocks.NewInternalDiscover().
+		WithPublishCollectionFunc(func(_ context.Context, collectionID int64, _ role.Role, _ service.PublishDOICollectionRequest) (service.PublishDOICollectionResponse, error) {
+			require.Equal(t, *expectedCollection.ID, collectionID)
+			return service.PublishDOICollectionResponse{}, errors.New("discover is down")
+		})
+	return Params{
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(collectionsStore).
+			WithInternalDiscover(mockInternalDiscover).
+			WithUsersStore(publishJobUsersStore(t, callingUser)),
+		Config: apitest.NewConfigBuilder().
+			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+			WithPublishJobMaxAttempts(maxAttempts).
+			Build(),
+	}
+}
+
+func testRunPublishJobRetried(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	// no DOIs, so that Discover is only called to publish
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense()
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser)
+	// no FinishPublishFunc, since the publish status should be left InProgress
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)))
+
+	params := publishFailingParams(t, expectedCollection, callingUser, mockCollectionStore, 2)
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.False(t, finished)
+
+	assert.Equal(t, publishing.JobQueuedStatus, recorder.job.Status)
+	assert.Equal(t, []publishing.JobStep{publishing.CheckingDOIsStep, publishing.PublishingToDiscoverStep}, recorder.steps)
+	require.NotNil(t, recorder.result)
+	require.NotNil(t, recorder.result.Error)
+	assert.Equal(t, "error publishing to Discover", *recorder.result.Error)
+	assert.Nil(t, recorder.result.PublishedDatasetID)
+}
+
+func testRunPublishJobLastAttempt(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense()
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser).withAttempts(1)
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)).
+		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.FailedStatus)))
+
+	params := publishFailingParams(t, expectedCollection, callingUser, mockCollectionStore, 2)
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	assert.Equal(t, publishing.JobFailedStatus, recorder.job.Status)
+	assert.Equal(t, 2, recorder.job.Attempts)
+	require.NotNil(t, recorder.result)
+	require.NotNil(t, recorder.result.Error)
+	assert.Equal(t, "error publishing to Discover", *recorder.result.Error)
+}
+
+func testRunPublishJobUnpublishedDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	tombstone := expectedDatasets.NewUnpublished()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithTombstones(tombstone)
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser)
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)).
+		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.FailedStatus)))
+
+	// plenty of attempts left, but another one would find the same DOIs
+	params := Params{
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+		Config: apitest.NewConfigBuilder().
+			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+			WithPublishJobMaxAttempts(3).
+			Build(),
+	}
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	assert.Equal(t, publishing.JobFailedStatus, recorder.job.Status)
+	assert.Equal(t, 1, recorder.job.Attempts)
+	require.NotNil(t, recorder.result)
+	require.NotNil(t, recorder.result.Error)
+	assert.Contains(t, *recorder.result.Error, "unpublished")
+	assert.Contains(t, *recorder.result.Error, tombstone.DOI)
+}
+
+func testRunPublishJobCollectionDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	collectionDataset := expectedDatasets.NewPublishedWithOptions(apitest.WithDatasetType(dto.CollectionDatasetType))
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithPublicDatasets(expectedDatasets.NewPublished(), collectionDataset)
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser)
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)).
+		WithFinishPublishFunc(expectedCollection.FinishPublishFunc(t, publishing.FailedStatus)))
+
+	params := Params{
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mockCollectionStore).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+		Config: apitest.NewConfigBuilder().
+			WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).
+			WithPublishJobMaxAttempts(3).
+			Build(),
+	}
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	assert.Equal(t, publishing.JobFailedStatus, recorder.job.Status)
+	assert.Equal(t, 1, recorder.job.Attempts)
+	require.NotNil(t, recorder.result)
+	require.NotNil(t, recorder.result.Error)
+	assert.Contains(t, *recorder.result.Error, "other collections")
+	assert.Contains(t, *recorder.result.Error, collectionDataset.DOI)
+}
+
+func testRunPublishJobFinished(t *testing.T) {
+	ctx := context.Background()
+
+	for _, storeErr := range []error{collections.ErrPublishJobFinished, collections.ErrPublishJobNotFound} {
+		t.Run(storeErr.Error(), func(t *testing.T) {
+			jobID := uuid.NewString()
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithStartPublishJobAttemptFunc(func(_ context.Context, actualJobID string) (collections.PublishJob, error) {
+					require.Equal(t, jobID, actualJobID)
+					return collections.PublishJob{}, storeErr
+				})
+
+			params := Params{
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().Build(),
+			}
+
+			finished, err := RunPublishJob(ctx, params, jobID)
+			require.NoError(t, err)
+			assert.True(t, finished)
+		})
+	}
+}
+
+func testRunPublishJobNotInProgress(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense()
+	// for example, the reconciler failed the publish while the job was waiting
+	publishStatus := collectionstest.NewFailedPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	recorder := newPublishJobRecorder(expectedCollection, callingUser)
+	// no FinishPublishFunc, since the publish status is not the job's to change
+	mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)))
+
+	params := Params{
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().Build(),
+	}
+
+	finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	assert.Equal(t, publishing.JobFailedStatus, recorder.job.Status)
+	assert.Empty(t, recorder.steps)
+	require.NotNil(t, recorder.result)
+	require.NotNil(t, recorder.result.Error)
+	assert.Contains(t, *recorder.result.Error, "no longer in progress")
+}
+
+func testRunPublishJobGetCollectionError(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner)
+
+	config := apitest.NewConfigBuilder().WithPublishJobMaxAttempts(2).Build()
+	for _, tt := range []struct {
+		attempts         int
+		expectedFinished bool
+		expectedStatus   publishing.JobStatus
+	}{
+		{0, false, publishing.JobQueuedStatus},
+		{1, true, publishing.JobFailedStatus},
+	} {
+		recorder := newPublishJobRecorder(expectedCollection, callingUser).withAttempts(tt.attempts)
+		// no FinishPublishFunc, since the publish status should be left InProgress
+		mockCollectionStore := recorder.mockStore(t, mocks.NewCollectionsStore().
+			WithGetCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.GetCollectionResponse, error) {
+				return collections.GetCollectionResponse{}, errors.New("database is down")
+			}))
+
+		params := Params{
+			Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+			Config:    config,
+		}
+
+		finished, err := RunPublishJob(ctx, params, recorder.job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expectedFinished, finished)
+
+		assert.Equal(t, tt.expectedStatus, recorder.job.Status)
+		assert.Empty(t, recorder.steps)
+		require.NotNil(t, recorder.result)
+		require.NotNil(t, recorder.result.Error)
+		assert.Equal(t, "error querying store for collection to publish", *recorder.result.Error)
+	}
+}
+
+func testRunPublishJobStartError(t *testing.T) {
+	ctx := context.Background()
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithStartPublishJobAttemptFunc(func(_ context.Context, _ string) (collections.PublishJob, error) {
+			return collections.PublishJob{}, errors.New("database is down")
+		})
+
+	params := Params{
+		Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+		Config:    apitest.NewConfigBuilder().Build(),
+	}
+
+	finished, err := RunPublishJob(ctx, params, uuid.NewString())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database is down")
+	assert.False(t, finished)
+}
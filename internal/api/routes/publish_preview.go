@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var PublishPreviewRouteKey = fmt.Sprintf("GET /{%s}/publish/preview", NodeIDPathParamKey)
+
+// PublishPreview does the checks of publishing the collection and returns the Discover request and manifest that a
+// publish would send, so that owners can review what will become public. Nothing is published, and the publish
+// status of the collection is left alone.
+func PublishPreview(ctx context.Context, params Params) (dto.PublishPreview, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.PublishPreview{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.PublishPreview{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.PublishPreview{}, apierrors.NewInternalServerError(
+			"error querying store for collection to preview",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minPublishRole) {
+		return dto.PublishPreview{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("publish of collection %s not previewed; requires user role: %s",
+				nodeID,
+				minPublishRole),
+		)
+	}
+
+	if err := validateCollection(collection); err != nil {
+		return dto.PublishPreview{}, err
+	}
+	if len(collection.DOIs) == 0 {
+		return dto.PublishPreview{}, apierrors.NewConflictError("published collection must contain DOIs")
+	}
+
+	banners, err := checkPublishDOIs(ctx, params, collection)
+	if err != nil {
+		return dto.PublishPreview{}, err
+	}
+
+	userResp, err := params.Container.UsersStore().GetUser(ctx, userClaim.Id)
+	if err != nil {
+		return dto.PublishPreview{}, apierrors.NewInternalServerError("error getting user information", err)
+	}
+
+	discoverPubReq := newPublishDOICollectionRequest(collection, userClaim.Id, userClaim.NodeId, userResp, banners)
+	discoverPubReqJSON, err := json.Marshal(discoverPubReq)
+	if err != nil {
+		return dto.PublishPreview{}, apierrors.NewInternalServerError("error marshalling Discover publish request", err)
+	}
+
+	manifest, err := params.newCollectionManifestBuilder(collection, creator(userResp)).Build()
+	if err != nil {
+		return dto.PublishPreview{}, apierrors.NewInternalServerError("error creating manifest", err)
+	}
+
+	return dto.PublishPreview{
+		DiscoverRequest: discoverPubReqJSON,
+		Manifest:        manifest,
+	}, nil
+}
+
+func NewPublishPreviewRouteHandler() Handler[dto.PublishPreview] {
+	return Handler[dto.PublishPreview]{
+		HandleFunc:        PublishPreview,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
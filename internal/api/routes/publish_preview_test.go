@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestHandlePublishPreview tests that run the Handle wrapper around PublishPreview
+func TestHandlePublishPreview(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"preview publish", testHandlePublishPreview},
+		{"preview publish, unpublished DOIs", testHandlePublishPreviewUnpublishedDOIs},
+		{"preview publish, invalid collection", testHandlePublishPreviewInvalid},
+		{"preview publish, authorization", testHandlePublishPreviewAuthz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testHandlePublishPreview(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	dataset := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(dataset).
+		WithRandomLicense().
+		WithNTags(2)
+
+	// Only GetCollectionFunc is set, and no InternalDiscover or ManifestStore, so anything that would
+	// start a publish will panic
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishPreviewRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishPreviewRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var preview struct {
+		DiscoverRequest service.PublishDOICollectionRequest `json:"discoverRequest"`
+		Manifest        publishing.ManifestV5               `json:"manifest"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(resp.Body), &preview))
+
+	discoverRequest := preview.DiscoverRequest
+	assert.Equal(t, expectedCollection.Name, discoverRequest.Name)
+	assert.Equal(t, expectedCollection.Description, discoverRequest.Description)
+	assert.Equal(t, expectedCollection.DOIs.Strings(), discoverRequest.DOIs)
+	assert.Equal(t, []string{*dataset.Banner}, discoverRequest.Banners)
+	assert.Equal(t, *expectedCollection.License, discoverRequest.License)
+	assert.Equal(t, expectedCollection.Tags, discoverRequest.Tags)
+	assert.Equal(t, callingUser.ID, discoverRequest.OwnerID)
+	assert.Equal(t, callingUser.NodeID, discoverRequest.OwnerNodeID)
+	assert.Equal(t, callingUser.LastName, discoverRequest.OwnerLastName)
+	assert.Equal(t, *expectedCollection.NodeID, discoverRequest.CollectionNodeID)
+	require.Len(t, discoverRequest.Contributors, 1)
+
+	manifest := preview.Manifest
+	assert.Equal(t, expectedCollection.Name, manifest.Name)
+	assert.Equal(t, callingUser.LastName, manifest.Creator.LastName)
+	assert.Equal(t, expectedCollection.DOIs.Strings(), manifest.References.IDs)
+	// assigned by Discover
+	assert.Empty(t, manifest.ID)
+	assert.Zero(t, manifest.PennsieveDatasetID)
+	assert.Zero(t, manifest.Version)
+	assert.NotZero(t, manifest.TotalSize())
+}
+
+func testHandlePublishPreviewUnpublishedDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	tombstone := expectedDatasets.NewUnpublished()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished()).
+		WithTombstones(tombstone).
+		WithRandomLicense().
+		WithNTags(2)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishPreviewRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishPreviewRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Body, "unpublished")
+	assert.Contains(t, resp.Body, tombstone.DOI)
+}
+
+func testHandlePublishPreviewInvalid(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	// no license or tags
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithDOIs(apitest.NewPennsieveDOI())
+
+	// no Discover set, since the collection should be rejected before any DOIs are checked
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishPreviewRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishPreviewRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Contains(t, resp.Body, "license")
+}
+
+func testHandlePublishPreviewAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, tooLowPerm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer} {
+		t.Run(tooLowPerm.String(), func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().
+				WithRandomID().
+				WithNodeID().
+				WithUser(callingUser.ID, tooLowPerm).
+				WithRandomLicense().
+				WithNTags(2)
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(PublishPreviewRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					Build(),
+				Container: apitest.NewTestContainer().
+					WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))),
+				Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims: &claims,
+			}
+
+			resp, err := Handle(ctx, NewPublishPreviewRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			assert.Equal(t, DefaultErrorResponseHeaders(), resp.Headers)
+		})
+	}
+}
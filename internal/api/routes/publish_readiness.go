@@ -0,0 +1,199 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/validate"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var PublishReadinessRouteKey = fmt.Sprintf("GET /{%s}/publish/readiness", NodeIDPathParamKey)
+
+// PublishReadiness runs every check that a publish of the collection would run, but instead of stopping at the
+// first problem, it returns all of them, along with warnings about things that would not stop a publish.
+func PublishReadiness(ctx context.Context, params Params) (dto.PublishReadiness, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.PublishReadiness{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.PublishReadiness{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.PublishReadiness{}, apierrors.NewInternalServerError(
+			"error querying store for collection to check",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minPublishRole) {
+		return dto.PublishReadiness{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("publish readiness of collection %s not checked; requires user role: %s",
+				nodeID,
+				minPublishRole),
+		)
+	}
+
+	readiness := readinessBuilder{}
+	collectionReadiness(&readiness, collection, params.Config.PublishTimeout)
+
+	if err := doiReadiness(ctx, params, &readiness, collection); err != nil {
+		return dto.PublishReadiness{}, err
+	}
+
+	userResp, err := params.Container.UsersStore().GetUser(ctx, userClaim.Id)
+	if err != nil {
+		return dto.PublishReadiness{}, apierrors.NewInternalServerError("error getting user information", err)
+	}
+	if orcid := userResp.ORCID; orcid == nil || len(*orcid) == 0 {
+		readiness.warning(dto.ReadinessOwnerMissingORCID,
+			"owner has no linked ORCID, so the published collection's creator will not have one")
+	}
+
+	return readiness.build(), nil
+}
+
+func NewPublishReadinessRouteHandler() Handler[dto.PublishReadiness] {
+	return Handler[dto.PublishReadiness]{
+		HandleFunc:        PublishReadiness,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+// collectionReadiness adds the problems that validateCollection would find, and any publish already in progress
+// that is not yet stale enough for StartPublish to replace.
+func collectionReadiness(readiness *readinessBuilder, collection collections.GetCollectionResponse, publishTimeout time.Duration) {
+	if len(collection.Description) == 0 {
+		readiness.blocking(dto.ReadinessMissingDescription, "published description cannot be empty")
+	}
+	if err := validate.License(collection.License, true); err != nil {
+		readiness.blocking(dto.ReadinessInvalidLicense, err.Error())
+	}
+	if err := validate.Tags(collection.Tags, true); err != nil {
+		readiness.blocking(dto.ReadinessInvalidTags, err.Error())
+	}
+	if publication := collection.Publication; publication != nil && publication.Status == publishing.InProgressStatus &&
+		!publication.IsStale(time.Now(), publishTimeout) {
+		readiness.blocking(dto.ReadinessPublishInProgress, "a publish of this collection is already in progress")
+	}
+}
+
+// doiReadiness adds the problems that checkPublishDOIs would find, as well as members that are themselves collections
+// and a missing banner. Unlike checkPublishDOIs, a dependency error is the only thing that stops it early.
+func doiReadiness(ctx context.Context, params Params, readiness *readinessBuilder, collection collections.GetCollectionResponse) error {
+	if len(collection.DOIs) == 0 {
+		readiness.blocking(dto.ReadinessNoDOIs, "published collection must contain DOIs")
+		return nil
+	}
+
+	pennsieveDOIs, externalDOIs := GroupByDatasource(collection.DOIs)
+
+	var banners []string
+	if len(pennsieveDOIs) > 0 {
+		discoverDOIRes, err := params.Container.UncachedDiscover().GetDatasetsByDOI(ctx, pennsieveDOIs)
+		if err != nil {
+			return apierrors.NewInternalServerError("error getting DOI info from Discover", err)
+		}
+
+		// loop over pennsieveDOIs rather than the response maps to keep the collection's order
+		var unpublished, unpublishedDetails, collectionDOIs []string
+		for _, doi := range pennsieveDOIs {
+			if tombstone, isTombstone := discoverDOIRes.Unpublished[doi]; isTombstone {
+				unpublished = append(unpublished, doi)
+				unpublishedDetails = append(unpublishedDetails, fmt.Sprintf("%s status is %s", doi, tombstone.Status))
+			} else if published, isPublished := discoverDOIRes.Published[doi]; isPublished && isCollectionDataset(published) {
+				collectionDOIs = append(collectionDOIs, doi)
+			}
+		}
+		if len(unpublished) > 0 {
+			readiness.blocking(dto.ReadinessUnpublishedDOIs,
+				fmt.Sprintf("collection contains unpublished DOIs: %s", strings.Join(unpublishedDetails, ", ")),
+				unpublished...)
+		}
+		if len(collectionDOIs) > 0 {
+			readiness.blocking(dto.ReadinessCollectionDOIs,
+				"collection contains DOIs of other collections; a collection cannot contain a collection",
+				collectionDOIs...)
+		}
+
+		banners = collectBanners(pennsieveDOIs, discoverDOIRes.Published)
+	}
+
+	if len(externalDOIs) > 0 {
+		resolveRes, err := params.Container.ExternalDOI().ResolveDOIs(ctx, externalDOIs)
+		if err != nil {
+			return apierrors.NewInternalServerError("error resolving non-Pennsieve DOIs", err)
+		}
+		if len(resolveRes.Unresolved) > 0 {
+			readiness.blocking(dto.ReadinessUnresolvedDOIs,
+				"collection contains DOIs that could not be resolved",
+				resolveRes.Unresolved...)
+		}
+	}
+
+	if !containsBanner(banners) {
+		readiness.warning(dto.ReadinessNoBanners,
+			"no DOI in the collection has a banner image, so the published collection will only have default banners")
+	}
+	return nil
+}
+
+// containsBanner returns true if banners has at least one non-empty banner URL.
+func containsBanner(banners []string) bool {
+	for _, banner := range banners {
+		if len(banner) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// readinessBuilder collects the items of a dto.PublishReadiness in the order they are found.
+type readinessBuilder struct {
+	items []dto.PublishReadinessItem
+}
+
+func (b *readinessBuilder) blocking(code dto.ReadinessCode, message string, dois ...string) {
+	b.add(code, dto.ReadinessBlocking, message, dois)
+}
+
+func (b *readinessBuilder) warning(code dto.ReadinessCode, message string, dois ...string) {
+	b.add(code, dto.ReadinessWarning, message, dois)
+}
+
+func (b *readinessBuilder) add(code dto.ReadinessCode, severity dto.ReadinessSeverity, message string, dois []string) {
+	b.items = append(b.items, dto.PublishReadinessItem{
+		Code:     code,
+		Severity: severity,
+		Message:  message,
+		DOIs:     dois,
+	})
+}
+
+func (b *readinessBuilder) build() dto.PublishReadiness {
+	ready := true
+	for _, item := range b.items {
+		if item.Severity == dto.ReadinessBlocking {
+			ready = false
+		}
+	}
+	return dto.PublishReadiness{
+		Ready: ready,
+		Items: b.items,
+	}
+}
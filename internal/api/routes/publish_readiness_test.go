@@ -0,0 +1,321 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+// TestHandlePublishReadiness tests that run the Handle wrapper around PublishReadiness
+func TestHandlePublishReadiness(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"publish readiness, ready", testHandlePublishReadinessReady},
+		{"publish readiness, every problem reported", testHandlePublishReadinessAllProblems},
+		{"publish readiness, no DOIs", testHandlePublishReadinessNoDOIs},
+		{"publish readiness, publish in progress", testHandlePublishReadinessInProgress},
+		{"publish readiness, stale publish in progress", testHandlePublishReadinessStaleInProgress},
+		{"publish readiness, authorization", testHandlePublishReadinessAuthz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func unmarshalReadiness(t *testing.T, body string) dto.PublishReadiness {
+	t.Helper()
+	var readiness dto.PublishReadiness
+	require.NoError(t, json.Unmarshal([]byte(body), &readiness))
+	return readiness
+}
+
+func readinessItem(t *testing.T, readiness dto.PublishReadiness, code dto.ReadinessCode) dto.PublishReadinessItem {
+	t.Helper()
+	for _, item := range readiness.Items {
+		if item.Code == code {
+			return item
+		}
+	}
+	require.FailNow(t, "readiness item not found", "no item with code %s in %+v", code, readiness.Items)
+	return dto.PublishReadinessItem{}
+}
+
+func testHandlePublishReadinessReady(t *testing.T) {
+	ctx := context.Background()
+	id := int64(5001)
+	callingUser := userstest.NewTestUser(
+		userstest.WithID(id),
+		userstest.WithNodeID(uuid.NewString()),
+		userstest.WithFirstName(uuid.NewString()),
+		userstest.WithLastName(uuid.NewString()),
+		userstest.WithORCID(uuid.NewString()),
+	)
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.GetID(), pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished()).
+		WithExternalDatasets(expectedExternalDatasets.NewResolved()).
+		WithRandomLicense().
+		WithNTags(2)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Body, `"items":[]`)
+
+	readiness := unmarshalReadiness(t, resp.Body)
+	assert.True(t, readiness.Ready)
+	assert.Empty(t, readiness.Items)
+}
+
+func testHandlePublishReadinessAllProblems(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	noBanner := expectedDatasets.NewPublishedWithNilBanner()
+	collectionDataset := expectedDatasets.NewPublishedWithOptions(apitest.WithDatasetType(dto.CollectionDatasetType), apitest.WithNilBanner())
+	tombstone := expectedDatasets.NewUnpublished()
+
+	expectedExternalDatasets := apitest.NewExpectedExternalDatasets()
+	unresolved := expectedExternalDatasets.NewUnresolved()
+
+	// no description, license, or tags
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithDescription("").
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(noBanner, collectionDataset).
+		WithTombstones(tombstone).
+		WithExternalDatasets(dto.ExternalDataset{DOI: unresolved})
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithExternalDOI(mocks.NewExternalDOI().WithResolveDOIsFunc(expectedExternalDatasets.ResolveDOIsFunc(t))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	readiness := unmarshalReadiness(t, resp.Body)
+	assert.False(t, readiness.Ready)
+	assert.Len(t, readiness.Items, 8)
+
+	for _, code := range []dto.ReadinessCode{dto.ReadinessMissingDescription, dto.ReadinessInvalidLicense, dto.ReadinessInvalidTags} {
+		item := readinessItem(t, readiness, code)
+		assert.Equal(t, dto.ReadinessBlocking, item.Severity)
+		assert.NotEmpty(t, item.Message)
+		assert.Empty(t, item.DOIs)
+	}
+
+	unpublishedItem := readinessItem(t, readiness, dto.ReadinessUnpublishedDOIs)
+	assert.Equal(t, dto.ReadinessBlocking, unpublishedItem.Severity)
+	assert.Equal(t, []string{tombstone.DOI}, unpublishedItem.DOIs)
+	assert.Contains(t, unpublishedItem.Message, tombstone.Status)
+
+	collectionItem := readinessItem(t, readiness, dto.ReadinessCollectionDOIs)
+	assert.Equal(t, dto.ReadinessBlocking, collectionItem.Severity)
+	assert.Equal(t, []string{collectionDataset.DOI}, collectionItem.DOIs)
+
+	unresolvedItem := readinessItem(t, readiness, dto.ReadinessUnresolvedDOIs)
+	assert.Equal(t, dto.ReadinessBlocking, unresolvedItem.Severity)
+	assert.Equal(t, []string{unresolved}, unresolvedItem.DOIs)
+
+	assert.Equal(t, dto.ReadinessWarning, readinessItem(t, readiness, dto.ReadinessOwnerMissingORCID).Severity)
+	assert.Equal(t, dto.ReadinessWarning, readinessItem(t, readiness, dto.ReadinessNoBanners).Severity)
+}
+
+func testHandlePublishReadinessNoDOIs(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithNTags(2)
+
+	// no Discover or ExternalDOI set, since there are no DOIs to look up
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	readiness := unmarshalReadiness(t, resp.Body)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, dto.ReadinessBlocking, readinessItem(t, readiness, dto.ReadinessNoDOIs).Severity)
+	// banners are not reported without DOIs, since there is already a blocking item about them
+	for _, item := range readiness.Items {
+		assert.NotEqual(t, dto.ReadinessNoBanners, item.Code)
+	}
+}
+
+func testHandlePublishReadinessInProgress(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished()).
+		WithRandomLicense().
+		WithNTags(2)
+	publishStatus := collectionstest.NewInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	readiness := unmarshalReadiness(t, resp.Body)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, dto.ReadinessBlocking, readinessItem(t, readiness, dto.ReadinessPublishInProgress).Severity)
+}
+
+func testHandlePublishReadinessStaleInProgress(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithPublicDatasets(expectedDatasets.NewPublished()).
+		WithRandomLicense().
+		WithNTags(2)
+	publishStatus := collectionstest.NewStaleInProgressPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus))).
+			WithDiscover(mocks.NewDiscover().WithGetDatasetsByDOIFunc(expectedDatasets.GetDatasetsByDOIFunc(t))).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+
+	resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// StartPublish would replace a stale publish, so it should not block
+	readiness := unmarshalReadiness(t, resp.Body)
+	assert.True(t, readiness.Ready)
+	for _, item := range readiness.Items {
+		assert.NotEqual(t, dto.ReadinessPublishInProgress, item.Code)
+	}
+}
+
+func testHandlePublishReadinessAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, tooLowPerm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer} {
+		t.Run(tooLowPerm.String(), func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().
+				WithRandomID().
+				WithNodeID().
+				WithUser(callingUser.ID, tooLowPerm)
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(PublishReadinessRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					Build(),
+				Container: apitest.NewTestContainer().
+					WithCollectionsStore(mocks.NewCollectionsStore().WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))),
+				Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims: &claims,
+			}
+
+			resp, err := Handle(ctx, NewPublishReadinessRouteHandler(), params)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			assert.Equal(t, DefaultErrorResponseHeaders(), resp.Headers)
+		})
+	}
+}
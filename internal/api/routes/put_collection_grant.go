@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var PutCollectionTeamRouteKey = fmt.Sprintf("PUT /{%s}/teams/{%s}", NodeIDPathParamKey, TeamNodeIDPathParamKey)
+
+var PutCollectionOrganizationRouteKey = fmt.Sprintf("PUT /{%s}/organizations/{%s}", NodeIDPathParamKey, OrganizationNodeIDPathParamKey)
+
+// PutCollectionTeam gives every member of the given team the requested role on the collection.
+func PutCollectionTeam(ctx context.Context, params Params) (dto.CollectionGrant, error) {
+	return putCollectionGrant(ctx, params, collections.TeamGrantee)
+}
+
+// PutCollectionOrganization gives every member of the given organization the requested role on the collection.
+func PutCollectionOrganization(ctx context.Context, params Params) (dto.CollectionGrant, error) {
+	return putCollectionGrant(ctx, params, collections.OrganizationGrantee)
+}
+
+// putCollectionGrant adds or changes the role of a team or organization on the collection.
+// The caller must be at least a Manager and the role can be at most collections.MaxGrantRole.
+func putCollectionGrant(ctx context.Context, params Params, granteeType collections.GranteeType) (dto.CollectionGrant, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	granteeParamKey := granteePathParamKey(granteeType)
+	granteeNodeID := params.Request.PathParameters[granteeParamKey]
+	if len(granteeNodeID) == 0 {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, granteeParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.String(granteeParamKey, granteeNodeID))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError("missing request body")
+	}
+
+	var putRequest dto.PutCollectionGrantRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&putRequest); err != nil {
+		return dto.CollectionGrant{}, apierrors.NewRequestUnmarshallError(putRequest, err)
+	}
+
+	requestedRole, ok := role.RoleFromString(putRequest.Role)
+	if !ok {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError(fmt.Sprintf("unknown role: %q", putRequest.Role))
+	}
+	if requestedRole == role.None {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("cannot grant role %s; use DELETE to remove a %s", requestedRole, granteeType))
+	}
+	if requestedRole > collections.MaxGrantRole {
+		return dto.CollectionGrant{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("cannot grant role %s to a %s; maximum role is %s", requestedRole, granteeType, collections.MaxGrantRole))
+	}
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionGrant{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionGrant{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageMembers) {
+		return dto.CollectionGrant{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s %s roles not updated; requires user role: %s",
+				nodeID,
+				granteeType,
+				minRoleToManageMembers),
+		)
+	}
+
+	grant, err := params.Container.CollectionsStore().PutCollectionGrant(ctx, collection.ID, userClaim.Id, granteeType, granteeNodeID, requestedRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, collections.ErrGranteeNotFound):
+			return dto.CollectionGrant{}, NewGranteeNotFoundError(granteeType, granteeNodeID)
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.CollectionGrant{}, apierrors.NewCollectionNotFoundError(nodeID)
+		default:
+			return dto.CollectionGrant{}, apierrors.NewInternalServerError(fmt.Sprintf("error updating collection %s role", granteeType), err)
+		}
+	}
+
+	return ToDTOCollectionGrant(grant), nil
+}
+
+func NewPutCollectionTeamRouteHandler() Handler[dto.CollectionGrant] {
+	return Handler[dto.CollectionGrant]{
+		HandleFunc:        PutCollectionTeam,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+func NewPutCollectionOrganizationRouteHandler() Handler[dto.CollectionGrant] {
+	return Handler[dto.CollectionGrant]{
+		HandleFunc:        PutCollectionOrganization,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
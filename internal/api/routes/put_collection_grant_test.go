@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestPutCollectionGrant(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"add team to collection", testPutCollectionTeam},
+		{"add organization to collection", testPutCollectionOrganization},
+		{"add non-existent team", testPutCollectionTeamUnknownTeam},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newPutCollectionGrantParams(t *testing.T, callingUser userstest.User, routeKey string, collectionNodeID, granteeParamKey, granteeNodeID string, grantRole string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(routeKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			WithPathParam(granteeParamKey, granteeNodeID).
+			WithBody(t, dto.PutCollectionGrantRequest{Role: grantRole}).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testPutCollectionTeam(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	teamMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, teamMember)
+	_, teamNodeID := expectationDB.CreateTestTeam(ctx, t, *teamMember.ID)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, collection)
+
+	params := newPutCollectionGrantParams(t, owner, PutCollectionTeamRouteKey, *collection.NodeID, TeamNodeIDPathParamKey, teamNodeID, "editor")
+
+	response, err := PutCollectionTeam(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, teamNodeID, response.NodeID)
+	assert.NotEmpty(t, response.Name)
+	assert.Equal(t, role.Editor.String(), response.Role)
+
+	// team member should now see the collection
+	teamMemberCollection, err := params.Container.CollectionsStore().GetCollection(ctx, *teamMember.ID, *collection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Editor, teamMemberCollection.UserRole)
+}
+
+func testPutCollectionOrganization(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	organizationMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, organizationMember)
+	_, organizationNodeID := expectationDB.CreateTestOrganization(ctx, t, map[int64]pgdb.DbPermission{*organizationMember.ID: pgdb.Delete})
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, collection)
+
+	params := newPutCollectionGrantParams(t, owner, PutCollectionOrganizationRouteKey, *collection.NodeID, OrganizationNodeIDPathParamKey, organizationNodeID, "viewer")
+
+	response, err := PutCollectionOrganization(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, organizationNodeID, response.NodeID)
+	assert.Equal(t, role.Viewer.String(), response.Role)
+
+	organizationMemberCollection, err := params.Container.CollectionsStore().GetCollection(ctx, *organizationMember.ID, *collection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Viewer, organizationMemberCollection.UserRole)
+}
+
+func testPutCollectionTeamUnknownTeam(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, collection)
+
+	params := newPutCollectionGrantParams(t, owner, PutCollectionTeamRouteKey, *collection.NodeID, TeamNodeIDPathParamKey, uuid.NewString(), "viewer")
+
+	_, err := PutCollectionTeam(ctx, params)
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestHandlePutCollectionGrant(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+	granteeNodeID := uuid.NewString()
+
+	for _, tt := range []struct {
+		scenario       string
+		granteeType    collections.GranteeType
+		callerPerm     pgdb.DbPermission
+		requestedRole  string
+		expectedStatus int
+	}{
+		{"editor cannot add team", collections.TeamGrantee, pgdb.Delete, "viewer", http.StatusForbidden},
+		{"manager can add team", collections.TeamGrantee, pgdb.Administer, "manager", http.StatusOK},
+		{"owner cannot make team owner", collections.TeamGrantee, pgdb.Owner, "owner", http.StatusBadRequest},
+		{"unknown role", collections.TeamGrantee, pgdb.Owner, "superuser", http.StatusBadRequest},
+		{"none role", collections.TeamGrantee, pgdb.Owner, "none", http.StatusBadRequest},
+		{"viewer cannot add organization", collections.OrganizationGrantee, pgdb.Read, "guest", http.StatusForbidden},
+		{"owner can add organization", collections.OrganizationGrantee, pgdb.Owner, "editor", http.StatusOK},
+		{"owner cannot make organization owner", collections.OrganizationGrantee, pgdb.Owner, "owner", http.StatusBadRequest},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tt.callerPerm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithPutCollectionGrantFunc(func(ctx context.Context, collectionID int64, _ int64, granteeType collections.GranteeType, nodeID string, grantRole role.Role) (collections.CollectionGrant, error) {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, tt.granteeType, granteeType)
+					require.Equal(t, granteeNodeID, nodeID)
+					return collections.CollectionGrant{GranteeType: granteeType, GranteeID: 100, GranteeNodeID: nodeID, Name: "grantee", Role: grantRole}, nil
+				})
+
+			routeKey, handler := PutCollectionTeamRouteKey, NewPutCollectionTeamRouteHandler()
+			if tt.granteeType == collections.OrganizationGrantee {
+				routeKey, handler = PutCollectionOrganizationRouteKey, NewPutCollectionOrganizationRouteHandler()
+			}
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(routeKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithPathParam(granteePathParamKey(tt.granteeType), granteeNodeID).
+					WithBody(t, dto.PutCollectionGrantRequest{Role: tt.requestedRole}).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, handler, params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				var grant dto.CollectionGrant
+				require.NoError(t, json.Unmarshal([]byte(resp.Body), &grant))
+				assert.Equal(t, granteeNodeID, grant.NodeID)
+				expectedRole, _ := role.RoleFromString(tt.requestedRole)
+				assert.Equal(t, expectedRole.String(), grant.Role)
+			} else {
+				assert.Contains(t, resp.Body, "errorId")
+				assert.Contains(t, resp.Body, "message")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var PutCollectionMemberRouteKey = fmt.Sprintf("PUT /{%s}/members/{%s}", NodeIDPathParamKey, UserNodeIDPathParamKey)
+
+// PutCollectionMember adds the given user to the collection with the requested role or changes the role of
+// an existing member.
+// The caller must be at least a Manager and can neither grant a role higher than their own nor change the
+// role of a member whose role is higher than their own.
+func PutCollectionMember(ctx context.Context, params Params) (dto.CollectionMember, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.CollectionMember{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	memberNodeID := params.Request.PathParameters[UserNodeIDPathParamKey]
+	if len(memberNodeID) == 0 {
+		return dto.CollectionMember{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, UserNodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId),
+		slog.String("memberNodeId", memberNodeID))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.CollectionMember{}, apierrors.NewBadRequestError("missing request body")
+	}
+
+	var putRequest dto.PutCollectionMemberRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&putRequest); err != nil {
+		return dto.CollectionMember{}, apierrors.NewRequestUnmarshallError(putRequest, err)
+	}
+
+	requestedRole, ok := role.RoleFromString(putRequest.Role)
+	if !ok {
+		return dto.CollectionMember{}, apierrors.NewBadRequestError(fmt.Sprintf("unknown role: %q", putRequest.Role))
+	}
+	if requestedRole == role.None {
+		return dto.CollectionMember{}, apierrors.NewBadRequestError(
+			fmt.Sprintf("cannot grant role %s; use DELETE to remove a member", requestedRole))
+	}
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.CollectionMember{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.CollectionMember{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	if !collection.UserRole.Implies(minRoleToManageMembers) {
+		return dto.CollectionMember{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s members not updated; requires user role: %s",
+				nodeID,
+				minRoleToManageMembers),
+		)
+	}
+	if !collection.UserRole.Implies(requestedRole) {
+		return dto.CollectionMember{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("cannot grant role %s; user role is %s",
+				requestedRole,
+				collection.UserRole),
+		)
+	}
+
+	existingMember, err := findCollectionMember(ctx, params, collection.ID, memberNodeID)
+	if err != nil {
+		return dto.CollectionMember{}, err
+	}
+	if existingMember != nil && !collection.UserRole.Implies(existingMember.Role) {
+		return dto.CollectionMember{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("cannot change role of member with role %s; user role is %s",
+				existingMember.Role,
+				collection.UserRole),
+		)
+	}
+
+	member, err := params.Container.CollectionsStore().PutCollectionMember(ctx, collection.ID, userClaim.Id, memberNodeID, requestedRole)
+	if err != nil {
+		switch {
+		case errors.Is(err, collections.ErrUserNotFound):
+			return dto.CollectionMember{}, NewUserNotFoundError(memberNodeID)
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.CollectionMember{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrLastOwner):
+			return dto.CollectionMember{}, apierrors.NewConflictErrorWithCause(
+				fmt.Sprintf("cannot change role of user %s; collection %s must have an owner", memberNodeID, nodeID),
+				err)
+		default:
+			return dto.CollectionMember{}, apierrors.NewInternalServerError("error updating collection member", err)
+		}
+	}
+
+	return ToDTOCollectionMember(member), nil
+}
+
+func NewPutCollectionMemberRouteHandler() Handler[dto.CollectionMember] {
+	return Handler[dto.CollectionMember]{
+		HandleFunc:        PutCollectionMember,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,282 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestPutCollectionMember(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"add collection member", testPutCollectionMemberAdd},
+		{"change role of collection member", testPutCollectionMemberChangeRole},
+		{"add non-existent user", testPutCollectionMemberUnknownUser},
+		{"demote only owner", testPutCollectionMemberLastOwner},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newPutCollectionMemberParams(t *testing.T, callingUser userstest.User, collectionNodeID, memberNodeID string, memberRole string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PutCollectionMemberRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			WithPathParam(UserNodeIDPathParamKey, memberNodeID).
+			WithBody(t, dto.PutCollectionMemberRequest{Role: memberRole}).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testPutCollectionMemberAdd(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	newMember := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, newMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	params := newPutCollectionMemberParams(t, manager, *collection.NodeID, newMember.NodeID, "editor")
+
+	response, err := PutCollectionMember(ctx, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, newMember.NodeID, response.UserNodeID)
+	assert.Equal(t, newMember.GetFirstName(), response.FirstName)
+	assert.Equal(t, newMember.GetLastName(), response.LastName)
+	assert.Equal(t, role.Editor.String(), response.Role)
+
+	collection.WithUser(*newMember.ID, pgdb.Delete)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberChangeRole(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	member := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, member)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*member.ID, pgdb.Guest)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	params := newPutCollectionMemberParams(t, owner, *collection.NodeID, member.NodeID, role.Owner.String())
+
+	response, err := PutCollectionMember(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, role.Owner.String(), response.Role)
+
+	collection.SetUser(*member.ID, pgdb.Owner)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberUnknownUser(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	unknownUserNodeID := userstest.NewTestUser().NodeID
+	params := newPutCollectionMemberParams(t, owner, *collection.NodeID, unknownUserNodeID, role.Viewer.String())
+
+	_, err := PutCollectionMember(ctx, params)
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Contains(t, apiErr.UserMessage, unknownUserNodeID)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberLastOwner(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	params := newPutCollectionMemberParams(t, owner, *collection.NodeID, owner.NodeID, role.Manager.String())
+
+	_, err := PutCollectionMember(ctx, params)
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+// TestHandlePutCollectionMember tests that run the Handle wrapper around PutCollectionMember
+func TestHandlePutCollectionMember(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"put collection member, authorization", testPutCollectionMemberAuthz},
+		{"put collection member, bad role", testPutCollectionMemberBadRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testPutCollectionMemberAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+	memberNodeID := userstest.NewTestUser().NodeID
+	existingOwner, existingManager := role.Owner, role.Manager
+
+	for _, tt := range []struct {
+		scenario       string
+		callerPerm     pgdb.DbPermission
+		existingRole   *role.Role
+		requestedRole  role.Role
+		expectedStatus int
+	}{
+		{"guest cannot add members", pgdb.Guest, nil, role.Guest, http.StatusForbidden},
+		{"viewer cannot add members", pgdb.Read, nil, role.Guest, http.StatusForbidden},
+		{"editor cannot add members", pgdb.Delete, nil, role.Viewer, http.StatusForbidden},
+		{"manager can add editor", pgdb.Administer, nil, role.Editor, http.StatusOK},
+		{"manager can add manager", pgdb.Administer, nil, role.Manager, http.StatusOK},
+		{"manager cannot add owner", pgdb.Administer, nil, role.Owner, http.StatusForbidden},
+		{"manager cannot demote owner", pgdb.Administer, &existingOwner, role.Viewer, http.StatusForbidden},
+		{"manager can demote manager", pgdb.Administer, &existingManager, role.Viewer, http.StatusOK},
+		{"owner can add owner", pgdb.Owner, nil, role.Owner, http.StatusOK},
+		{"owner can demote owner", pgdb.Owner, &existingOwner, role.Manager, http.StatusOK},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, tt.callerPerm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithGetCollectionMembersFunc(func(ctx context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					members := []collections.CollectionMember{{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: tt.callerPerm.ToRole()}}
+					if tt.existingRole != nil {
+						members = append(members, collections.CollectionMember{UserID: 100, UserNodeID: memberNodeID, Role: *tt.existingRole})
+					}
+					return members, nil
+				}).
+				WithPutCollectionMemberFunc(func(ctx context.Context, collectionID int64, actorID int64, userNodeID string, memberRole role.Role) (collections.CollectionMember, error) {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, callingUser.ID, actorID)
+					require.Equal(t, memberNodeID, userNodeID)
+					require.Equal(t, tt.requestedRole, memberRole)
+					return collections.CollectionMember{UserID: 100, UserNodeID: userNodeID, Role: memberRole}, nil
+				})
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(PutCollectionMemberRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithPathParam(UserNodeIDPathParamKey, memberNodeID).
+					WithBody(t, dto.PutCollectionMemberRequest{Role: tt.requestedRole.String()}).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewPutCollectionMemberRouteHandler(), params)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				var member dto.CollectionMember
+				require.NoError(t, json.Unmarshal([]byte(resp.Body), &member))
+				assert.Equal(t, memberNodeID, member.UserNodeID)
+				assert.Equal(t, tt.requestedRole.String(), member.Role)
+			} else {
+				assert.Contains(t, resp.Body, "errorId")
+				assert.Contains(t, resp.Body, "message")
+			}
+		})
+	}
+}
+
+func testPutCollectionMemberBadRole(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	for _, badRole := range []string{"", "none", "administrator"} {
+		t.Run(badRole, func(t *testing.T) {
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(PutCollectionMemberRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, uuid.NewString()).
+					WithPathParam(UserNodeIDPathParamKey, userstest.NewTestUser().NodeID).
+					WithBody(t, dto.PutCollectionMemberRequest{Role: badRole}).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mocks.NewCollectionsStore()),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewPutCollectionMemberRouteHandler(), params)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var PutDOIOrderRouteKey = fmt.Sprintf("PUT /{%s}/dois/order", NodeIDPathParamKey)
+
+// PutDOIOrder sets the order of the datasets in a collection. The order determines which datasets
+// supply the collection's banners and the order of the datasets in GET /{nodeId}.
+func PutDOIOrder(ctx context.Context, params Params) (dto.GetCollectionResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.GetCollectionResponse{}, apierrors.NewBadRequestError("missing request body")
+	}
+	var orderRequest dto.PutDOIOrderRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&orderRequest); err != nil {
+		return dto.GetCollectionResponse{}, apierrors.NewRequestUnmarshallError(orderRequest, err)
+	}
+
+	collectionsStore := params.Container.CollectionsStore()
+	currentState, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection to reorder",
+			err)
+	}
+
+	minRequiredRole := role.Editor
+	if !currentState.UserRole.Implies(minRequiredRole) {
+		return dto.GetCollectionResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not reordered; requires user role: %s",
+				nodeID,
+				minRequiredRole),
+		)
+	}
+
+	dois, err := ValidateDOIOrder(orderRequest.DOIs, currentState.DOIs)
+	if err != nil {
+		return dto.GetCollectionResponse{}, err
+	}
+
+	// nothing to do if the order is unchanged
+	if slices.Equal(dois, currentState.DOIs.Strings()) {
+		return params.StoreToDTOCollection(ctx, currentState, nil)
+	}
+
+	updatedCollection, err := collectionsStore.ReorderDOIs(ctx, userClaim.Id, currentState.ID, dois)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		if errors.Is(err, collections.ErrDOIOrderMismatch) {
+			return dto.GetCollectionResponse{}, apierrors.NewConflictErrorWithCause(
+				fmt.Sprintf("datasets in collection %s changed while reordering; retry with the current datasets", nodeID),
+				err)
+		}
+		return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+			"error reordering collection datasets",
+			err)
+	}
+	return params.StoreToDTOCollection(ctx, updatedCollection, nil)
+}
+
+func NewPutDOIOrderRouteHandler() Handler[dto.GetCollectionResponse] {
+	return Handler[dto.GetCollectionResponse]{
+		HandleFunc:        PutDOIOrder,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+// ValidateDOIOrder returns the requested DOIs with any leading or trailing whitespace trimmed. Returns a Bad Request *apierrors.Error
+// unless the requested DOIs contain each of the current DOIs exactly once and nothing else.
+func ValidateDOIOrder(requestedDOIs []string, currentDOIs collections.DOIs) ([]string, error) {
+	current := make(map[string]bool, len(currentDOIs))
+	for _, doi := range currentDOIs {
+		current[doi.Value] = true
+	}
+
+	var dois, duplicates, unknown []string
+	seen := make(map[string]bool, len(requestedDOIs))
+	for _, doi := range requestedDOIs {
+		doi = strings.TrimSpace(doi)
+		if seen[doi] {
+			duplicates = append(duplicates, doi)
+			continue
+		}
+		seen[doi] = true
+		if !current[doi] {
+			unknown = append(unknown, doi)
+			continue
+		}
+		dois = append(dois, doi)
+	}
+
+	var missing []string
+	for _, doi := range currentDOIs {
+		if !seen[doi.Value] {
+			missing = append(missing, doi.Value)
+		}
+	}
+
+	var details []string
+	if len(duplicates) > 0 {
+		details = append(details, fmt.Sprintf("duplicate DOIs: %s", strings.Join(duplicates, ", ")))
+	}
+	if len(unknown) > 0 {
+		details = append(details, fmt.Sprintf("DOIs not in collection: %s", strings.Join(unknown, ", ")))
+	}
+	if len(missing) > 0 {
+		details = append(details, fmt.Sprintf("missing DOIs: %s", strings.Join(missing, ", ")))
+	}
+	if len(details) > 0 {
+		return nil, apierrors.NewBadRequestError(fmt.Sprintf("order must contain every DOI in the collection exactly once; %s", strings.Join(details, "; ")))
+	}
+	return dois, nil
+}
@@ -0,0 +1,264 @@
+package routes
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPutDOIOrder(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"reorder DOIs", testPutDOIOrder},
+		{"DOIs added after a reorder go last", testPutDOIOrderThenAdd},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newPutDOIOrderParams(t *testing.T, callingUser userstest.User, collectionNodeID string, discoverURL string, orderRequest dto.PutDOIOrderRequest) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(discoverURL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
+		WithHTTPTestDiscover(discoverURL)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PutDOIOrderRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			WithBody(t, orderRequest).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testPutDOIOrder(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	dataset1 := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+	dataset2 := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+	dataset3 := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Write).
+		WithPublicDatasets(dataset1, dataset2, dataset3)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	orderRequest := dto.PutDOIOrderRequest{DOIs: []string{dataset3.DOI, dataset1.DOI, dataset2.DOI}}
+	updatedCollection, err := PutDOIOrder(ctx, newPutDOIOrderParams(t, user, *expectedCollection.NodeID, mockDiscoverServer.URL, orderRequest))
+	require.NoError(t, err)
+
+	expectedCollection.SetPublicDatasets(dataset3, dataset1, dataset2)
+	assertEqualExpectedGetCollectionResponse(t, expectedCollection, updatedCollection, expectedDatasets)
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, createResp.ID)
+}
+
+func testPutDOIOrderThenAdd(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	dataset1 := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+	dataset2 := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+	datasetToAdd := expectedDatasets.NewPublished(apitest.NewPublicContributor())
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).
+		WithPublicDatasets(dataset1, dataset2)
+	expectationDB.CreateCollection(ctx, t, expectedCollection)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	orderRequest := dto.PutDOIOrderRequest{DOIs: []string{dataset2.DOI, dataset1.DOI}}
+	orderParams := newPutDOIOrderParams(t, user, *expectedCollection.NodeID, mockDiscoverServer.URL, orderRequest)
+	_, err := PutDOIOrder(ctx, orderParams)
+	require.NoError(t, err)
+
+	patchParams := orderParams
+	patchParams.Request = apitest.NewAPIGatewayRequestBuilder(PatchCollectionRouteKey).
+		WithClaims(*patchParams.Claims).
+		WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+		WithBody(t, dto.PatchCollectionRequest{DOIs: &dto.PatchDOIs{Add: []string{datasetToAdd.DOI}}}).
+		Build()
+	updatedCollection, err := PatchCollection(ctx, patchParams)
+	require.NoError(t, err)
+
+	expectedCollection.SetPublicDatasets(dataset2, dataset1, datasetToAdd)
+	assertEqualExpectedGetCollectionResponse(t, expectedCollection, updatedCollection, expectedDatasets)
+}
+
+func TestHandlePutDOIOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"return Bad Request when order is missing a DOI", testHandlePutDOIOrderMissingDOI},
+		{"return Forbidden when user is not an editor", testHandlePutDOIOrderForbidden},
+		{"return Conflict when DOIs change during reorder", testHandlePutDOIOrderConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandlePutDOIOrderParams(t *testing.T, expectedCollection *apitest.ExpectedCollection, store *mocks.CollectionsStore, orderRequest dto.PutDOIOrderRequest) Params {
+	claims := apitest.DefaultClaims(userstest.SeedUser1)
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(PutDOIOrderRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+			WithBody(t, orderRequest).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(store),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandlePutDOIOrderMissingDOI(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithNPennsieveDOIs(3)
+
+	// no ReorderDOIsFunc set, so the mock panics if the store is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	orderRequest := dto.PutDOIOrderRequest{DOIs: []string{expectedCollection.DOIs[2].DOI, expectedCollection.DOIs[0].DOI}}
+	response, err := Handle(ctx, NewPutDOIOrderRouteHandler(), newHandlePutDOIOrderParams(t, expectedCollection, mockCollectionStore, orderRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, expectedCollection.DOIs[1].DOI)
+}
+
+func testHandlePutDOIOrderForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Read).
+		WithNPennsieveDOIs(2)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	orderRequest := dto.PutDOIOrderRequest{DOIs: []string{expectedCollection.DOIs[1].DOI, expectedCollection.DOIs[0].DOI}}
+	response, err := Handle(ctx, NewPutDOIOrderRouteHandler(), newHandlePutDOIOrderParams(t, expectedCollection, mockCollectionStore, orderRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func testHandlePutDOIOrderConflict(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Write).
+		WithNPennsieveDOIs(2)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithReorderDOIsFunc(func(_ context.Context, _ int64, collectionID int64, dois []string) (collections.GetCollectionResponse, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, []string{expectedCollection.DOIs[1].DOI, expectedCollection.DOIs[0].DOI}, dois)
+			return collections.GetCollectionResponse{}, collections.ErrDOIOrderMismatch
+		})
+
+	orderRequest := dto.PutDOIOrderRequest{DOIs: []string{expectedCollection.DOIs[1].DOI, " " + expectedCollection.DOIs[0].DOI + " "}}
+	response, err := Handle(ctx, NewPutDOIOrderRouteHandler(), newHandlePutDOIOrderParams(t, expectedCollection, mockCollectionStore, orderRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestValidateDOIOrder(t *testing.T) {
+	current := collections.DOIs{
+		apitest.NewPennsieveDOI(),
+		apitest.NewPennsieveDOI(),
+		apitest.NewPennsieveDOI(),
+	}
+	doi0, doi1, doi2 := current[0].Value, current[1].Value, current[2].Value
+
+	t.Run("valid", func(t *testing.T) {
+		dois, err := ValidateDOIOrder([]string{doi2, " " + doi0, doi1}, current)
+		require.NoError(t, err)
+		assert.Equal(t, []string{doi2, doi0, doi1}, dois)
+	})
+
+	t.Run("empty collection", func(t *testing.T) {
+		dois, err := ValidateDOIOrder(nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, dois)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := ValidateDOIOrder([]string{doi2, doi0, doi1, doi0}, current)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate DOIs: "+doi0)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		unknown := apitest.NewPennsieveDOI().Value
+		_, err := ValidateDOIOrder([]string{doi2, doi0, doi1, unknown}, current)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DOIs not in collection: "+unknown)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := ValidateDOIOrder([]string{doi2, doi0}, current)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing DOIs: "+doi1)
+	})
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var RestoreCollectionRouteKey = fmt.Sprintf("POST /{%s}/restore", NodeIDPathParamKey)
+
+// RestoreCollection takes a deleted collection out of the trash.
+func RestoreCollection(ctx context.Context, params Params) (dto.NoContent, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.NoContent{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collectionsStore := params.Container.CollectionsStore()
+	deleted, err := collectionsStore.GetDeletedCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError(
+			"error querying store for collection to restore",
+			err)
+	}
+	if !deleted.UserRole.Implies(minDeleteRole) {
+		return dto.NoContent{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not restored; requires user role: %s",
+				nodeID,
+				minDeleteRole),
+		)
+	}
+
+	if err := collectionsStore.RestoreCollection(ctx, deleted.ID, userClaim.Id); err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			// restored or purged after we looked it up above
+			return dto.NoContent{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.NoContent{}, apierrors.NewInternalServerError("error restoring collection", err)
+	}
+	return dto.NoContent{}, nil
+}
+
+func NewRestoreCollectionRouteHandler() Handler[dto.NoContent] {
+	return Handler[dto.NoContent]{
+		HandleFunc:        RestoreCollection,
+		SuccessStatusCode: http.StatusNoContent,
+	}
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"log/slog"
+	"net/http"
+)
+
+var RestoreCollectionSnapshotRouteKey = fmt.Sprintf("POST /{%s}/versions/{%s}/restore", NodeIDPathParamKey, SnapshotIDPathParamKey)
+
+// RestoreCollectionSnapshot changes the name, description, license, tags, and DOIs of the collection back to those saved
+// in a snapshot. The changes are made with a single update, so they are recorded in the collection's activity like any other update.
+// DOIs are restored without checking that they are still published, since they were checked when they were first added.
+func RestoreCollectionSnapshot(ctx context.Context, params Params) (dto.GetCollectionResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	snapshotID, err := snapshotIDPathParam(params)
+	if err != nil {
+		return dto.GetCollectionResponse{}, err
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.Int64(SnapshotIDPathParamKey, snapshotID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collectionsStore := params.Container.CollectionsStore()
+	currentState, err := collectionsStore.GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection to restore",
+			err)
+	}
+
+	if !currentState.UserRole.Implies(minRoleToManageSnapshots) {
+		return dto.GetCollectionResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not restored; requires user role: %s",
+				nodeID,
+				minRoleToManageSnapshots),
+		)
+	}
+
+	conditional, err := CheckIfMatch(params.Request.Headers, nodeID, currentState.Version)
+	if err != nil {
+		return dto.GetCollectionResponse{}, err
+	}
+
+	snapshot, err := collectionsStore.GetSnapshot(ctx, currentState.ID, snapshotID)
+	if err != nil {
+		if errors.Is(err, collections.ErrSnapshotNotFound) {
+			return dto.GetCollectionResponse{}, NewSnapshotNotFoundError(nodeID, snapshotID)
+		}
+		return dto.GetCollectionResponse{}, apierrors.NewInternalServerError("error querying store for snapshot", err)
+	}
+
+	restoreRequest := GetRestoreRequest(currentState, snapshot.State)
+	restored, err := collectionsStore.UpdateCollection(ctx, userClaim.Id, currentState.ID, restoreRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.GetCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case conditional && errors.Is(err, collections.ErrCollectionVersionMismatch):
+			return dto.GetCollectionResponse{}, NewCollectionChangedError(nodeID)
+		case errors.Is(err, collections.ErrCollectionVersionMismatch), errors.Is(err, collections.ErrDOIOrderMismatch):
+			return dto.GetCollectionResponse{}, apierrors.NewConflictErrorWithCause(
+				fmt.Sprintf("collection %s changed while restoring snapshot %d; try again", nodeID, snapshotID),
+				err)
+		default:
+			return dto.GetCollectionResponse{}, apierrors.NewInternalServerError(
+				"error restoring collection",
+				err)
+		}
+	}
+
+	return params.StoreToDTOCollection(ctx, restored, nil)
+}
+
+func NewRestoreCollectionSnapshotRouteHandler() Handler[dto.GetCollectionResponse] {
+	return Handler[dto.GetCollectionResponse]{
+		HandleFunc:        RestoreCollectionSnapshot,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,214 @@
+package routes
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand/v2"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRestoreCollection(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"restore deleted collection", testRestoreCollection},
+		{"restore deleted collection requires Manager role", testRestoreCollectionForbidden},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newRestoreCollectionParams(t *testing.T, callingUser userstest.User, collectionNodeID string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(RestoreCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testRestoreCollection(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer).WithNPennsieveDOIs(2)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.SetCollectionDeletedAt(ctx, t, collectionID, time.Now())
+
+	_, err := RestoreCollection(ctx, newRestoreCollectionParams(t, manager, *collection.NodeID))
+	require.NoError(t, err)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+
+	// no longer in the trash
+	_, err = RestoreCollection(ctx, newRestoreCollectionParams(t, manager, *collection.NodeID))
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func testRestoreCollectionForbidden(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	editor := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, editor)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*editor.ID, pgdb.Write)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.SetCollectionDeletedAt(ctx, t, collectionID, time.Now())
+
+	_, err := RestoreCollection(ctx, newRestoreCollectionParams(t, editor, *collection.NodeID))
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	expectationDB.RequireDeletedCollection(ctx, t, collection, collectionID)
+}
+
+func TestHandleRestoreCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"return No Content when collection is restored", testHandleRestoreCollection},
+		{"return Not Found when collection is not in the trash", testHandleRestoreCollectionNotFound},
+		{"return Forbidden when user is not a Manager", testHandleRestoreCollectionForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleRestoreCollectionParams(callingUser userstest.SeedUser, nodeID string, store *mocks.CollectionsStore) Params {
+	claims := apitest.DefaultClaims(callingUser)
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(RestoreCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, nodeID).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(store),
+		Config:    apitest.NewConfigBuilder().Build(),
+		Claims:    &claims,
+	}
+}
+
+func newDeletedCollection(nodeID string, userRole role.Role) collections.DeletedCollection {
+	return collections.DeletedCollection{
+		CollectionBase: collections.CollectionBase{
+			ID:       rand.Int64(),
+			NodeID:   nodeID,
+			Name:     uuid.NewString(),
+			UserRole: userRole,
+		},
+		DeletedAt: time.Now().UTC(),
+	}
+}
+
+func testHandleRestoreCollection(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	deleted := newDeletedCollection(uuid.NewString(), role.Manager)
+	var restoredID int64
+	store := mocks.NewCollectionsStore().
+		WithGetDeletedCollectionFunc(func(_ context.Context, userID int64, nodeID string) (collections.DeletedCollection, error) {
+			require.Equal(t, callingUser.ID, userID)
+			require.Equal(t, deleted.NodeID, nodeID)
+			return deleted, nil
+		}).
+		WithRestoreCollectionFunc(func(_ context.Context, collectionID int64, _ int64) error {
+			restoredID = collectionID
+			return nil
+		})
+
+	response, err := Handle(ctx, NewRestoreCollectionRouteHandler(), newHandleRestoreCollectionParams(callingUser, deleted.NodeID, store))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Empty(t, response.Body)
+	assert.Equal(t, deleted.ID, restoredID)
+}
+
+func testHandleRestoreCollectionNotFound(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	nodeID := uuid.NewString()
+	store := mocks.NewCollectionsStore().
+		WithGetDeletedCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.DeletedCollection, error) {
+			return collections.DeletedCollection{}, collections.ErrCollectionNotFound
+		})
+
+	response, err := Handle(ctx, NewRestoreCollectionRouteHandler(), newHandleRestoreCollectionParams(callingUser, nodeID, store))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Contains(t, response.Body, nodeID)
+}
+
+func testHandleRestoreCollectionForbidden(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+
+	deleted := newDeletedCollection(uuid.NewString(), role.Editor)
+	// no RestoreCollectionFunc set, so the mock panics if the collection is restored
+	store := mocks.NewCollectionsStore().
+		WithGetDeletedCollectionFunc(func(_ context.Context, _ int64, _ string) (collections.DeletedCollection, error) {
+			return deleted, nil
+		})
+
+	response, err := Handle(ctx, NewRestoreCollectionRouteHandler(), newHandleRestoreCollectionParams(callingUser, deleted.NodeID, store))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
@@ -0,0 +1,232 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+)
+
+var ReviseCollectionRouteKey = fmt.Sprintf("POST /{%s}/revise", NodeIDPathParamKey)
+
+// ReviseCollection pushes the collection's current name, description, license, tags, and contributor to the latest
+// published version as a new revision. Unlike PublishCollection, the published DOIs and sections are left alone, so
+// they are taken from the published manifest rather than the collection.
+func ReviseCollection(ctx context.Context, params Params) (dto.ReviseCollectionResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.ReviseCollectionResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.ReviseCollectionResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.ReviseCollectionResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection to revise",
+			err)
+	}
+
+	// Check permissions
+	minRequiredRole := role.Owner
+	if !collection.UserRole.Implies(minRequiredRole) {
+		return dto.ReviseCollectionResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s not revised; requires user role: %s",
+				nodeID,
+				minRequiredRole),
+		)
+	}
+
+	conditional, err := CheckIfMatch(params.Request.Headers, nodeID, collection.Version)
+	if err != nil {
+		return dto.ReviseCollectionResponse{}, err
+	}
+	var expectedVersion *int64
+	if conditional {
+		expectedVersion = &collection.Version
+	}
+
+	if err := validatePublishStatusForRevise(collection.Publication); err != nil {
+		return dto.ReviseCollectionResponse{}, err
+	}
+	if err := validateCollection(collection); err != nil {
+		return dto.ReviseCollectionResponse{}, err
+	}
+
+	// Set revision in progress
+	if err := params.Container.CollectionsStore().StartPublish(ctx, collection.ID, userClaim.Id, publishing.RevisionType, expectedVersion); err != nil {
+		// deliberately leave publish status alone for these, i.e., no cleanup
+		if errors.Is(err, collections.ErrPublishInProgress) {
+			return dto.ReviseCollectionResponse{}, apierrors.NewConflictError(err.Error())
+		}
+		if errors.Is(err, collections.ErrCollectionVersionMismatch) {
+			return dto.ReviseCollectionResponse{}, NewCollectionChangedError(nodeID)
+		}
+		return dto.ReviseCollectionResponse{}, cleanupOnError(ctx,
+			params.Container.Logger(),
+			apierrors.NewInternalServerError("error registering start of revision", err),
+			cleanupStatusIfExists(params.Container.CollectionsStore(), collection.ID))
+	}
+
+	internalDiscover, err := params.Container.InternalDiscover(ctx)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error getting internal Discover dependency", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	// Our publish status only records the most recent process, so ask Discover whether there is a published version to revise
+	discoverStatus, err := internalDiscover.GetCollectionPublishStatus(ctx, collection.ID, collection.NodeID, collection.UserRole)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error getting publish status from Discover", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+	if discoverStatus.Status != dto.PublishSucceeded {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewConflictError(fmt.Sprintf("error revising: Discover reports collection publish status %s", discoverStatus.Status)),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	manifestKey := publishing.ManifestS3Key(discoverStatus.PublishedDatasetID)
+	publishedManifest, err := params.Container.ManifestStore().GetManifest(ctx, manifestKey)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error reading published manifest", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	userResp, err := params.Container.UsersStore().GetUser(ctx, userClaim.Id)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error getting user information", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	revision := publishedManifest.Revision + 1
+	manifest, err := publishing.NewRevisionManifestBuilder(publishedManifest, revision).
+		WithName(collection.Name).
+		WithDescription(collection.Description).
+		WithCreator(creator(userResp)).
+		WithLicense(util.SafeDeref(collection.License)).
+		WithKeywords(collection.Tags).
+		Build()
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error creating revision manifest", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	saveManifestResp, err := params.Container.ManifestStore().SaveManifest(ctx, manifestKey, manifest)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			// assuming if this failed then there is nothing to clean up in S3
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error publishing revision manifest", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+	manifestS3VersionID := saveManifestResp.S3VersionID
+
+	params.Container.Logger().Info("wrote revision manifest to S3",
+		slog.String("key", manifestKey),
+		slog.String("s3VersionId", manifestS3VersionID),
+		slog.Int("revision", revision))
+
+	discoverReviseReq := service.ReviseDOICollectionRequest{
+		Name:              collection.Name,
+		Description:       collection.Description,
+		License:           util.SafeDeref(collection.License),
+		Tags:              collection.Tags,
+		Contributors:      []service.InternalContributor{toInternalContributor(userClaim.Id, userResp)},
+		OwnerID:           userClaim.Id,
+		OwnerNodeID:       userClaim.NodeId,
+		OwnerFirstName:    util.SafeDeref(userResp.FirstName),
+		OwnerLastName:     util.SafeDeref(userResp.LastName),
+		OwnerORCID:        util.SafeDeref(userResp.ORCID),
+		CollectionNodeID:  collection.NodeID,
+		PublishedVersion:  publishedManifest.Version,
+		Revision:          revision,
+		ManifestKey:       manifestKey,
+		ManifestVersionID: manifestS3VersionID,
+	}
+	discoverReviseResp, err := internalDiscover.ReviseCollection(ctx, collection.ID, collection.NodeID, collection.UserRole, discoverReviseReq)
+	if err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error revising with Discover", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+				// deleting the new S3 version makes the published manifest the latest again
+				cleanupManifest(params.Container.ManifestStore(), manifestKey, manifestS3VersionID),
+			)
+	}
+	collectionsServiceStatus := discoverReviseResp.Status.ToPublishingStatus()
+	params.Container.Logger().Info("revised on Discover",
+		slog.Int("publishedDatasetId", discoverReviseResp.PublishedDatasetID),
+		slog.Int("publishedVersion", discoverReviseResp.PublishedVersion),
+		slog.Int("revision", discoverReviseResp.Revision),
+		slog.Any("discoverServiceStatus", discoverReviseResp.Status),
+		slog.Any("collectionsServiceStatus", collectionsServiceStatus),
+	)
+
+	// Mark revision as finished. Discover now refers to the new manifest version, so it stays in S3 even if this fails.
+	if err := params.Container.CollectionsStore().FinishPublish(ctx, collection.ID, collectionsServiceStatus, true); err != nil {
+		return dto.ReviseCollectionResponse{},
+			cleanupOnError(ctx, params.Container.Logger(),
+				apierrors.NewInternalServerError("error marking revision as complete", err),
+				cleanupStatus(params.Container.CollectionsStore(), collection.ID),
+			)
+	}
+
+	return dto.ReviseCollectionResponse{
+		PublishedDatasetID: discoverReviseResp.PublishedDatasetID,
+		PublishedVersion:   discoverReviseResp.PublishedVersion,
+		Revision:           discoverReviseResp.Revision,
+		Status:             discoverReviseResp.Status,
+	}, nil
+}
+
+func NewReviseCollectionRouteHandler() Handler[dto.ReviseCollectionResponse] {
+	return Handler[dto.ReviseCollectionResponse]{
+		HandleFunc:        ReviseCollection,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
+
+// validatePublishStatusForRevise leaves an InProgress publication to StartPublish, which knows whether it is stale.
+func validatePublishStatusForRevise(publication *collections.Publication) error {
+	if publication == nil {
+		return apierrors.NewConflictError("error revising: collection has not been published")
+	}
+	if publication.Type == publishing.RemovalType && publication.Status == publishing.CompletedStatus {
+		return apierrors.NewConflictError("error revising: collection has been unpublished")
+	}
+	return nil
+}
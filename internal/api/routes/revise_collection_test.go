@@ -0,0 +1,278 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/store/manifests"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/apitest/builders/stores/collectionstest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestHandleReviseCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"revise should write a manifest revision and tell Discover", testHandleReviseCollection},
+		{"return Conflict when collection has not been published", testHandleReviseCollectionNotPublished},
+		{"return Conflict when collection has been unpublished", testHandleReviseCollectionUnpublished},
+		{"return Conflict and clean up status when Discover has no published version", testHandleReviseCollectionDiscoverNotPublished},
+		{"clean up status and manifest when Discover revise fails", testHandleReviseCollectionDiscoverFails},
+		{"forbid revise from users without the proper role on the collection", testHandleReviseCollectionAuthz},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+// reviseTestFixture holds a published collection and the mocks needed to revise it.
+// Tests change the mocks before calling params.
+type reviseTestFixture struct {
+	callingUser        userstest.SeedUser
+	expectedCollection *apitest.ExpectedCollection
+	publishStatus      collections.PublishStatus
+	publishedManifest  publishing.ManifestV5
+	collectionsStore   *mocks.CollectionsStore
+	internalDiscover   *mocks.InternalDiscover
+	manifestStore      *mocks.ManifestStore
+}
+
+func newReviseTestFixture(t *testing.T) *reviseTestFixture {
+	callingUser := userstest.SeedUser1
+	expectedCollection := apitest.NewExpectedCollection().
+		WithRandomID().
+		WithNodeID().
+		WithUser(callingUser.ID, pgdb.Owner).
+		WithRandomLicense().
+		WithNTags(2).
+		WithDOIs(apitest.NewPennsieveDOI())
+	publishStatus := collectionstest.NewCompletedPublishStatus(*expectedCollection.ID, callingUser.ID)
+
+	publishedDatasetID := 301
+	publishedManifest := apitest.NewExpectedManifest(t,
+		apitest.WithManifestPennsieveDatasetID(publishedDatasetID),
+		apitest.WithManifestVersion(2),
+	)
+	publishedManifest.Revision = 1
+
+	return &reviseTestFixture{
+		callingUser:        callingUser,
+		expectedCollection: expectedCollection,
+		publishStatus:      publishStatus,
+		publishedManifest:  publishedManifest,
+		collectionsStore: mocks.NewCollectionsStore().
+			WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, &publishStatus)),
+		internalDiscover: mocks.NewInternalDiscover().
+			WithGetCollectionPublishStatusFunc(func(_ context.Context, collectionID int64, collectionNodeID string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+				require.Equal(t, *expectedCollection.ID, collectionID)
+				require.Equal(t, *expectedCollection.NodeID, collectionNodeID)
+				return service.DatasetPublishStatusResponse{
+					PublishedDatasetID:    publishedDatasetID,
+					PublishedVersionCount: publishedManifest.Version,
+					Status:                dto.PublishSucceeded,
+				}, nil
+			}),
+		manifestStore: mocks.NewManifestStore().
+			WithGetManifestFunc(func(_ context.Context, key string) (publishing.ManifestV5, error) {
+				require.Equal(t, publishing.ManifestS3Key(publishedDatasetID), key)
+				return publishedManifest, nil
+			}),
+	}
+}
+
+func (f *reviseTestFixture) params(t *testing.T) Params {
+	claims := apitest.DefaultClaims(f.callingUser)
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(ReviseCollectionRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, *f.expectedCollection.NodeID).
+			Build(),
+		Container: apitest.NewTestContainer().
+			WithCollectionsStore(f.collectionsStore).
+			WithInternalDiscover(f.internalDiscover).
+			WithManifestStore(f.manifestStore).
+			WithUsersStore(mocks.NewUsersStore().WithGetUserFunc(mocks.NewGetUserFunc(t, f.callingUser))),
+		Config: apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims: &claims,
+	}
+}
+
+// expectStatus sets the mock StartPublish to expect a revision and the mock FinishPublish to expect the given final status.
+func (f *reviseTestFixture) expectStatus(t *testing.T, expectedFinalStatus publishing.Status) *bool {
+	finished := false
+	f.collectionsStore.
+		WithStartPublishFunc(func(_ context.Context, collectionID int64, userID int64, publishingType publishing.Type, _ *int64) error {
+			require.Equal(t, *f.expectedCollection.ID, collectionID)
+			require.Equal(t, f.callingUser.ID, userID)
+			require.Equal(t, publishing.RevisionType, publishingType)
+			return nil
+		}).
+		WithFinishPublishFunc(func(_ context.Context, collectionID int64, publishingStatus publishing.Status, strict bool) error {
+			require.Equal(t, *f.expectedCollection.ID, collectionID)
+			require.Equal(t, expectedFinalStatus, publishingStatus)
+			require.True(t, strict)
+			finished = true
+			return nil
+		})
+	return &finished
+}
+
+func testHandleReviseCollection(t *testing.T) {
+	ctx := context.Background()
+	f := newReviseTestFixture(t)
+	finished := f.expectStatus(t, publishing.CompletedStatus)
+
+	manifestKey := publishing.ManifestS3Key(f.publishedManifest.PennsieveDatasetID)
+	s3VersionID := uuid.NewString()
+	var savedManifest publishing.ManifestV5
+	f.manifestStore.WithSaveManifestFunc(func(_ context.Context, key string, manifest publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
+		require.Equal(t, manifestKey, key)
+		savedManifest = manifest
+		return manifests.SaveManifestResponse{S3VersionID: s3VersionID}, nil
+	})
+	f.internalDiscover.WithReviseCollectionFunc(func(_ context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request service.ReviseDOICollectionRequest) (service.ReviseDOICollectionResponse, error) {
+		require.Equal(t, *f.expectedCollection.ID, collectionID)
+		require.Equal(t, *f.expectedCollection.NodeID, collectionNodeID)
+		require.Equal(t, role.Owner, userRole)
+		assert.Equal(t, f.expectedCollection.Name, request.Name)
+		assert.Equal(t, f.expectedCollection.Description, request.Description)
+		assert.Equal(t, *f.expectedCollection.License, request.License)
+		assert.Equal(t, f.expectedCollection.Tags, request.Tags)
+		assert.Equal(t, []service.InternalContributor{apitest.InternalContributor(f.callingUser)}, request.Contributors)
+		assert.Equal(t, f.publishedManifest.Version, request.PublishedVersion)
+		assert.Equal(t, 2, request.Revision)
+		assert.Equal(t, manifestKey, request.ManifestKey)
+		assert.Equal(t, s3VersionID, request.ManifestVersionID)
+		return service.ReviseDOICollectionResponse{
+			PublishedDatasetID: f.publishedManifest.PennsieveDatasetID,
+			PublishedVersion:   request.PublishedVersion,
+			Revision:           request.Revision,
+			Status:             dto.PublishSucceeded,
+		}, nil
+	})
+
+	resp, err := ReviseCollection(ctx, f.params(t))
+	require.NoError(t, err)
+	assert.Equal(t, dto.ReviseCollectionResponse{
+		PublishedDatasetID: f.publishedManifest.PennsieveDatasetID,
+		PublishedVersion:   f.publishedManifest.Version,
+		Revision:           2,
+		Status:             dto.PublishSucceeded,
+	}, resp)
+	assert.True(t, *finished)
+
+	assert.Equal(t, 2, savedManifest.Revision)
+	assert.Equal(t, f.publishedManifest.Version, savedManifest.Version)
+	assert.Equal(t, f.publishedManifest.ID, savedManifest.ID)
+	assert.Equal(t, f.publishedManifest.References, savedManifest.References)
+	assert.Equal(t, f.expectedCollection.Name, savedManifest.Name)
+	assert.Equal(t, f.expectedCollection.Description, savedManifest.Description)
+	assert.Equal(t, *f.expectedCollection.License, savedManifest.License)
+	assert.Equal(t, f.expectedCollection.Tags, savedManifest.Keywords)
+	assert.Equal(t, apitest.ToPublishedContributor(f.callingUser), savedManifest.Creator)
+}
+
+func testHandleReviseCollectionNotPublished(t *testing.T) {
+	ctx := context.Background()
+	f := newReviseTestFixture(t)
+	// no StartPublishFunc, since we should fail before publish status is touched
+	f.collectionsStore.WithGetCollectionFunc(f.expectedCollection.GetCollectionFunc(t, nil))
+
+	response, err := Handle(ctx, NewReviseCollectionRouteHandler(), f.params(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Contains(t, response.Body, "has not been published")
+}
+
+func testHandleReviseCollectionUnpublished(t *testing.T) {
+	ctx := context.Background()
+	f := newReviseTestFixture(t)
+	unpublished := collectionstest.NewTerminalPublishStatusBuilder(*f.expectedCollection.ID, publishing.RemovalType, publishing.CompletedStatus).Build()
+	f.collectionsStore.WithGetCollectionFunc(f.expectedCollection.GetCollectionFunc(t, &unpublished))
+
+	response, err := Handle(ctx, NewReviseCollectionRouteHandler(), f.params(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Contains(t, response.Body, "unpublished")
+}
+
+func testHandleReviseCollectionDiscoverNotPublished(t *testing.T) {
+	ctx := context.Background()
+	f := newReviseTestFixture(t)
+	finished := f.expectStatus(t, publishing.FailedStatus)
+	f.internalDiscover.WithGetCollectionPublishStatusFunc(func(_ context.Context, _ int64, _ string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+		return service.DatasetPublishStatusResponse{Status: dto.PublishFailed}, nil
+	})
+
+	response, err := Handle(ctx, NewReviseCollectionRouteHandler(), f.params(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Contains(t, response.Body, string(dto.PublishFailed))
+	assert.True(t, *finished)
+}
+
+func testHandleReviseCollectionDiscoverFails(t *testing.T) {
+	ctx := context.Background()
+	f := newReviseTestFixture(t)
+	finished := f.expectStatus(t, publishing.FailedStatus)
+
+	s3VersionID := uuid.NewString()
+	manifestDeleted := false
+	f.manifestStore.
+		WithSaveManifestFunc(func(_ context.Context, _ string, _ publishing.ManifestV5) (manifests.SaveManifestResponse, error) {
+			return manifests.SaveManifestResponse{S3VersionID: s3VersionID}, nil
+		}).
+		WithDeleteManifestVersionFunc(func(_ context.Context, key string, actualS3VersionID string) error {
+			require.Equal(t, publishing.ManifestS3Key(f.publishedManifest.PennsieveDatasetID), key)
+			require.Equal(t, s3VersionID, actualS3VersionID)
+			manifestDeleted = true
+			return nil
+		})
+	f.internalDiscover.WithReviseCollectionFunc(func(_ context.Context, _ int64, _ string, _ role.Role, _ service.ReviseDOICollectionRequest) (service.ReviseDOICollectionResponse, error) {
+		return service.ReviseDOICollectionResponse{}, errors.New("discover unavailable")
+	})
+
+	response, err := Handle(ctx, NewReviseCollectionRouteHandler(), f.params(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	assert.True(t, *finished)
+	assert.True(t, manifestDeleted)
+}
+
+func testHandleReviseCollectionAuthz(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tooLowPerm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer} {
+		t.Run(tooLowPerm.String(), func(t *testing.T) {
+			f := newReviseTestFixture(t)
+			f.expectedCollection.Users = nil
+			f.expectedCollection.WithUser(f.callingUser.ID, tooLowPerm)
+			f.collectionsStore.WithGetCollectionFunc(f.expectedCollection.GetCollectionFunc(t, &f.publishStatus))
+
+			response, err := Handle(ctx, NewReviseCollectionRouteHandler(), f.params(t))
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		})
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/pennsieve/collections-service/internal/api/container"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/validate"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
 	"github.com/pennsieve/collections-service/internal/shared/util"
 	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
 	"log/slog"
+	"maps"
 	"strconv"
 )
 
@@ -37,7 +39,15 @@ type Handler[T dto.DTO] struct {
 }
 
 func Handle[T dto.DTO](ctx context.Context, handler Handler[T], params Params) (events.APIGatewayV2HTTPResponse, error) {
-	response, err := handler.HandleFunc(ctx, params)
+	// all the store calls made while handling the request share one connection per database
+	var response T
+	var err error
+	if scopeErr := postgres.WithConnection(ctx, func(ctx context.Context) error {
+		response, err = handler.HandleFunc(ctx, params)
+		return err
+	}); scopeErr != nil && err == nil {
+		params.Container.Logger().Warn("error releasing shared database connection", slog.Any("error", scopeErr))
+	}
 	if err != nil {
 		return handleError(err, params.Container.Logger())
 	}
@@ -45,9 +55,17 @@ func Handle[T dto.DTO](ctx context.Context, handler Handler[T], params Params) (
 	if err != nil {
 		return handleError(err, params.Container.Logger())
 	}
+	headers := handler.Headers
+	if headerDTO, ok := any(response).(dto.HeaderDTO); ok {
+		headers = maps.Clone(handler.Headers)
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		maps.Copy(headers, headerDTO.Headers())
+	}
 	return events.APIGatewayV2HTTPResponse{
 		StatusCode: handler.SuccessStatusCode,
-		Headers:    handler.Headers,
+		Headers:    headers,
 		Body:       body,
 	}, nil
 }
@@ -95,6 +113,19 @@ func GetIntQueryParam(queryParams map[string]string, key string, requiredMin int
 	return defaultValue, nil
 }
 
+// int64PathParam returns the value of the given path param or a Bad Request *apierrors.Error if it is missing or not an integer.
+func int64PathParam(params Params, key string) (int64, error) {
+	value := params.Request.PathParameters[key]
+	if len(value) == 0 {
+		return 0, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, key))
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, apierrors.NewBadRequestError(fmt.Sprintf(`invalid %q path parameter: %q`, key, value))
+	}
+	return id, nil
+}
+
 func GetBoolQueryParam(queryParams map[string]string, key string, defaultValue bool) (bool, error) {
 	if strVal, present := queryParams[key]; present {
 		value, err := strconv.ParseBool(strVal)
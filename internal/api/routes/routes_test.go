@@ -2,6 +2,7 @@ package routes
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
@@ -144,3 +145,26 @@ func TestHandleError(t *testing.T) {
 	})
 
 }
+
+func TestHandleHeaderDTO(t *testing.T) {
+	export := dto.CollectionExport{ContentType: "application/x-bibtex", FileName: "collection.bib", Content: "@misc{key}"}
+	handler := Handler[dto.CollectionExport]{
+		HandleFunc: func(_ context.Context, _ Params) (dto.CollectionExport, error) {
+			return export, nil
+		},
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+
+	resp, err := Handle(context.Background(), handler, Params{})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, export.Content, resp.Body)
+	assert.Equal(t, map[string]string{
+		"content-type":        "application/x-bibtex",
+		"content-disposition": `attachment; filename="collection.bib"`,
+	}, resp.Headers)
+	// the handler's own headers are left alone
+	assert.Equal(t, DefaultResponseHeaders(), handler.Headers)
+}
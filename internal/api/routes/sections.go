@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const SectionIDPathParamKey = "sectionId"
+
+// minRoleToManageSections is the role required to create, change, or delete the sections of a collection.
+const minRoleToManageSections = role.Editor
+
+func ToDTOCollectionSection(section collections.Section) dto.CollectionSection {
+	return dto.CollectionSection{
+		ID:          section.ID,
+		Title:       section.Title,
+		Description: section.Description,
+		DOIs:        section.DOIs,
+	}
+}
+
+func ToDTOCollectionSections(sections []collections.Section) []dto.CollectionSection {
+	var dtos []dto.CollectionSection
+	for _, section := range sections {
+		dtos = append(dtos, ToDTOCollectionSection(section))
+	}
+	return dtos
+}
+
+// sectionIDPathParam returns the value of the section id path param or a Bad Request *apierrors.Error if it is missing or not an integer.
+func sectionIDPathParam(params Params) (int64, error) {
+	return int64PathParam(params, SectionIDPathParamKey)
+}
+
+// ValidateSectionDOIs trims and de-duplicates requested and returns a Bad Request *apierrors.Error if any
+// of them are not in the collection.
+func ValidateSectionDOIs(requested []string, current collections.DOIs) ([]string, error) {
+	dois := make([]string, 0, len(requested))
+	var missing []string
+	for _, doi := range requested {
+		trimmed := strings.TrimSpace(doi)
+		if slices.Contains(dois, trimmed) {
+			continue
+		}
+		dois = append(dois, trimmed)
+		if !slices.ContainsFunc(current, func(d collections.DOI) bool { return d.Value == trimmed }) {
+			missing = append(missing, trimmed)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apierrors.NewBadRequestError(fmt.Sprintf("section DOIs must already be in collection: %s", strings.Join(missing, ", ")))
+	}
+	return dois, nil
+}
+
+func NewSectionNotFoundError(collectionNodeID string, sectionID int64) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("section %d not found in collection %s", sectionID, collectionNodeID), nil, http.StatusNotFound)
+}
+
+// NewSectionDOIsConflictError is for the case where the collection's DOIs change between validation and the section update.
+func NewSectionDOIsConflictError(collectionNodeID string, cause error) *apierrors.Error {
+	return apierrors.NewConflictErrorWithCause(
+		fmt.Sprintf("datasets in collection %s changed while updating section; retry with the current datasets", collectionNodeID),
+		cause)
+}
@@ -0,0 +1,419 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestCollectionSections(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"create, update, and delete sections", testCollectionSections},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+// sectionsTestParams builds Params for the section routes, all of which share a container talking to the test database.
+type sectionsTestParams struct {
+	t                *testing.T
+	callingUser      userstest.User
+	collectionNodeID string
+	discoverURL      string
+}
+
+func (p sectionsTestParams) build(routeKey string, sectionID *int64, body any) Params {
+	claims := apitest.DefaultClaims(p.callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(p.t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(p.discoverURL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(p.t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
+		WithHTTPTestDiscover(p.discoverURL)
+
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(routeKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, p.collectionNodeID)
+	if sectionID != nil {
+		requestBuilder = requestBuilder.WithPathParam(SectionIDPathParamKey, strconv.FormatInt(*sectionID, 10))
+	}
+	if body != nil {
+		requestBuilder = requestBuilder.WithBody(p.t, body)
+	}
+
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testCollectionSections(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	dataset1 := expectedDatasets.NewPublished()
+	dataset2 := expectedDatasets.NewPublished()
+	dataset3 := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Write).
+		WithPublicDatasets(dataset1, dataset2, dataset3)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	params := sectionsTestParams{t: t, callingUser: user, collectionNodeID: *expectedCollection.NodeID, discoverURL: mockDiscoverServer.URL}
+
+	imaging, err := CreateCollectionSection(ctx, params.build(CreateCollectionSectionRouteKey, nil, dto.CreateCollectionSectionRequest{
+		Title:       " Imaging ",
+		Description: "MRI datasets",
+		DOIs:        []string{dataset3.DOI, dataset1.DOI, dataset3.DOI},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Imaging", imaging.Title)
+	assert.Equal(t, "MRI datasets", imaging.Description)
+	assert.Equal(t, []string{dataset1.DOI, dataset3.DOI}, imaging.DOIs)
+
+	other, err := CreateCollectionSection(ctx, params.build(CreateCollectionSectionRouteKey, nil, dto.CreateCollectionSectionRequest{
+		Title: "Other",
+		DOIs:  []string{dataset2.DOI},
+	}))
+	require.NoError(t, err)
+
+	firstPosition := 0
+	newTitle := "Other Datasets"
+	updatedOther, err := PatchCollectionSection(ctx, params.build(PatchCollectionSectionRouteKey, &other.ID, dto.PatchCollectionSectionRequest{
+		Title:    &newTitle,
+		Position: &firstPosition,
+		DOIs:     []string{dataset2.DOI, dataset3.DOI},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, dto.CollectionSection{ID: other.ID, Title: newTitle, DOIs: []string{dataset2.DOI, dataset3.DOI}}, updatedOther)
+
+	expectedSections := []dto.CollectionSection{
+		updatedOther,
+		{ID: imaging.ID, Title: imaging.Title, Description: imaging.Description, DOIs: []string{dataset1.DOI}},
+	}
+
+	sectionsResponse, err := GetCollectionSections(ctx, params.build(GetCollectionSectionsRouteKey, nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, expectedSections, sectionsResponse.Sections)
+
+	collectionResponse, err := GetCollection(ctx, params.build(GetCollectionRouteKey, nil, nil))
+	require.NoError(t, err)
+	assertEqualExpectedGetCollectionResponse(t, expectedCollection, collectionResponse, expectedDatasets)
+	assert.Equal(t, expectedSections, collectionResponse.Sections)
+
+	_, err = DeleteCollectionSection(ctx, params.build(DeleteCollectionSectionRouteKey, &other.ID, nil))
+	require.NoError(t, err)
+
+	sectionsResponse, err = GetCollectionSections(ctx, params.build(GetCollectionSectionsRouteKey, nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, expectedSections[1:], sectionsResponse.Sections)
+
+	// deleting a section leaves its datasets in the collection
+	expectationDB.RequireCollection(ctx, t, expectedCollection, createResp.ID)
+}
+
+func TestHandleCollectionSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"create should return Forbidden when user is not an editor", testHandleCreateCollectionSectionForbidden},
+		{"create should return Bad Request when title is empty", testHandleCreateCollectionSectionEmptyTitle},
+		{"create should return Bad Request when DOIs are not in collection", testHandleCreateCollectionSectionDOIsNotInCollection},
+		{"create should return Conflict when DOIs are removed during create", testHandleCreateCollectionSectionConflict},
+		{"patch should return Bad Request when section id is not an integer", testHandlePatchCollectionSectionInvalidID},
+		{"patch should return Bad Request when position is negative", testHandlePatchCollectionSectionNegativePosition},
+		{"patch should return Not Found when section is not in collection", testHandlePatchCollectionSectionNotFound},
+		{"patch should leave DOIs alone when they are omitted", testHandlePatchCollectionSectionOmittedDOIs},
+		{"delete should return Not Found when section is not in collection", testHandleDeleteCollectionSectionNotFound},
+		{"get should return empty sections array", testHandleGetCollectionSectionsNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleSectionParams(t *testing.T, routeKey string, expectedCollection *apitest.ExpectedCollection, sectionID string, store *mocks.CollectionsStore, body any) Params {
+	claims := apitest.DefaultClaims(userstest.SeedUser1)
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(routeKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID)
+	if len(sectionID) > 0 {
+		requestBuilder = requestBuilder.WithPathParam(SectionIDPathParamKey, sectionID)
+	}
+	if body != nil {
+		requestBuilder = requestBuilder.WithBody(t, body)
+	}
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(store),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandleCreateCollectionSectionForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Read).
+		WithNPennsieveDOIs(1)
+
+	// no CreateSectionFunc set, so the mock panics if the store is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	request := dto.CreateCollectionSectionRequest{Title: "Section"}
+	response, err := Handle(ctx, NewCreateCollectionSectionRouteHandler(), newHandleSectionParams(t, CreateCollectionSectionRouteKey, expectedCollection, "", mockCollectionStore, request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func testHandleCreateCollectionSectionEmptyTitle(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	request := dto.CreateCollectionSectionRequest{Title: "  "}
+	response, err := Handle(ctx, NewCreateCollectionSectionRouteHandler(), newHandleSectionParams(t, CreateCollectionSectionRouteKey, expectedCollection, "", mocks.NewCollectionsStore(), request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, "section title cannot be empty")
+}
+
+func testHandleCreateCollectionSectionDOIsNotInCollection(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner).
+		WithNPennsieveDOIs(1)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	unknownDOI := apitest.NewPennsieveDOI().Value
+	request := dto.CreateCollectionSectionRequest{Title: "Section", DOIs: []string{expectedCollection.DOIs[0].DOI, unknownDOI}}
+	response, err := Handle(ctx, NewCreateCollectionSectionRouteHandler(), newHandleSectionParams(t, CreateCollectionSectionRouteKey, expectedCollection, "", mockCollectionStore, request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, unknownDOI)
+	assert.NotContains(t, response.Body, expectedCollection.DOIs[0].DOI)
+}
+
+func testHandleCreateCollectionSectionConflict(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Write).
+		WithNPennsieveDOIs(1)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithCreateSectionFunc(func(_ context.Context, collectionID int64, _ int64, request collections.CreateSectionRequest) (collections.Section, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, collections.CreateSectionRequest{Title: "Section", DOIs: []string{expectedCollection.DOIs[0].DOI}}, request)
+			return collections.Section{}, collections.ErrSectionDOIsNotInCollection
+		})
+
+	request := dto.CreateCollectionSectionRequest{Title: "Section", DOIs: []string{" " + expectedCollection.DOIs[0].DOI}}
+	response, err := Handle(ctx, NewCreateCollectionSectionRouteHandler(), newHandleSectionParams(t, CreateCollectionSectionRouteKey, expectedCollection, "", mockCollectionStore, request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func testHandlePatchCollectionSectionInvalidID(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	newTitle := "New Title"
+	request := dto.PatchCollectionSectionRequest{Title: &newTitle}
+	response, err := Handle(ctx, NewPatchCollectionSectionRouteHandler(), newHandleSectionParams(t, PatchCollectionSectionRouteKey, expectedCollection, "first", mocks.NewCollectionsStore(), request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, SectionIDPathParamKey)
+}
+
+func testHandlePatchCollectionSectionNegativePosition(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	position := -1
+	request := dto.PatchCollectionSectionRequest{Position: &position}
+	response, err := Handle(ctx, NewPatchCollectionSectionRouteHandler(), newHandleSectionParams(t, PatchCollectionSectionRouteKey, expectedCollection, "1", mocks.NewCollectionsStore(), request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func testHandlePatchCollectionSectionNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	sectionID := int64(42)
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateSectionFunc(func(_ context.Context, _ int64, _ int64, actualSectionID int64, _ collections.UpdateSectionRequest) (collections.Section, error) {
+			assert.Equal(t, sectionID, actualSectionID)
+			return collections.Section{}, fmt.Errorf("wrapped: %w", collections.ErrSectionNotFound)
+		})
+
+	newTitle := "New Title"
+	request := dto.PatchCollectionSectionRequest{Title: &newTitle}
+	response, err := Handle(ctx, NewPatchCollectionSectionRouteHandler(), newHandleSectionParams(t, PatchCollectionSectionRouteKey, expectedCollection, strconv.FormatInt(sectionID, 10), mockCollectionStore, request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Contains(t, response.Body, fmt.Sprintf("section %d not found", sectionID))
+}
+
+func testHandlePatchCollectionSectionOmittedDOIs(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Write).
+		WithNPennsieveDOIs(1)
+
+	sectionID := int64(7)
+	sectionDOIs := []string{expectedCollection.DOIs[0].DOI}
+	newDescription := "new description"
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithUpdateSectionFunc(func(_ context.Context, collectionID int64, _ int64, actualSectionID int64, update collections.UpdateSectionRequest) (collections.Section, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, sectionID, actualSectionID)
+			assert.Nil(t, update.DOIs)
+			assert.Nil(t, update.Title)
+			assert.Nil(t, update.Position)
+			require.NotNil(t, update.Description)
+			assert.Equal(t, newDescription, *update.Description)
+			return collections.Section{ID: sectionID, Title: "Title", Description: newDescription, DOIs: sectionDOIs}, nil
+		})
+
+	params := newHandleSectionParams(t, PatchCollectionSectionRouteKey, expectedCollection, strconv.FormatInt(sectionID, 10), mockCollectionStore, nil)
+	params.Request.Body = fmt.Sprintf(`{"description": %q}`, newDescription)
+	response, err := Handle(ctx, NewPatchCollectionSectionRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Body, fmt.Sprintf(`"dois":[%q]`, sectionDOIs[0]))
+}
+
+func testHandleDeleteCollectionSectionNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Write)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithDeleteSectionFunc(func(_ context.Context, collectionID int64, _ int64, _ int64) error {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			return collections.ErrSectionNotFound
+		})
+
+	response, err := Handle(ctx, NewDeleteCollectionSectionRouteHandler(), newHandleSectionParams(t, DeleteCollectionSectionRouteKey, expectedCollection, "3", mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func testHandleGetCollectionSectionsNone(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Guest)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	response, err := Handle(ctx, NewGetCollectionSectionsRouteHandler(), newHandleSectionParams(t, GetCollectionSectionsRouteKey, expectedCollection, "", mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"sections":[]}`, response.Body)
+}
+
+func TestValidateSectionDOIs(t *testing.T) {
+	current := collections.DOIs{
+		apitest.NewPennsieveDOI(),
+		apitest.NewExternalDOI(),
+	}
+	doi0, doi1 := current[0].Value, current[1].Value
+
+	t.Run("valid", func(t *testing.T) {
+		dois, err := ValidateSectionDOIs([]string{doi1, " " + doi0, doi1}, current)
+		require.NoError(t, err)
+		assert.Equal(t, []string{doi1, doi0}, dois)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		dois, err := ValidateSectionDOIs(nil, current)
+		require.NoError(t, err)
+		assert.Empty(t, dois)
+	})
+
+	t.Run("not in collection", func(t *testing.T) {
+		unknown := apitest.NewPennsieveDOI().Value
+		_, err := ValidateSectionDOIs([]string{doi0, unknown}, current)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "section DOIs must already be in collection: "+unknown)
+	})
+}
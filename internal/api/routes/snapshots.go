@@ -0,0 +1,193 @@
+package routes
+
+import (
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"net/http"
+	"slices"
+)
+
+const SnapshotIDPathParamKey = "snapshotId"
+
+// minRoleToManageSnapshots is the role required to take a snapshot of a collection or restore one.
+// It is the same as the role required to update the collection.
+const minRoleToManageSnapshots = role.Editor
+
+// snapshotIDPathParam returns the value of the snapshot id path param or a Bad Request *apierrors.Error if it is missing or not an integer.
+func snapshotIDPathParam(params Params) (int64, error) {
+	return int64PathParam(params, SnapshotIDPathParamKey)
+}
+
+func NewSnapshotNotFoundError(collectionNodeID string, snapshotID int64) *apierrors.Error {
+	return apierrors.NewError(fmt.Sprintf("snapshot %d not found in collection %s", snapshotID, collectionNodeID), nil, http.StatusNotFound)
+}
+
+func ToDTOActor(actor *collections.Actor) *dto.Actor {
+	if actor == nil {
+		return nil
+	}
+	return &dto.Actor{
+		UserNodeID: actor.UserNodeID,
+		FirstName:  util.SafeDeref(actor.FirstName),
+		LastName:   util.SafeDeref(actor.LastName),
+	}
+}
+
+func ToDTOCollectionSnapshotSummary(summary collections.SnapshotSummary) dto.CollectionSnapshotSummary {
+	return dto.CollectionSnapshotSummary{
+		ID:        summary.ID,
+		Name:      summary.Name,
+		CreatedBy: ToDTOActor(summary.CreatedBy),
+		CreatedAt: summary.CreatedAt,
+	}
+}
+
+func ToDTOCollectionSnapshot(snapshot collections.Snapshot) dto.CollectionSnapshot {
+	state := dto.CollectionSnapshotState{
+		Name:        snapshot.State.Name,
+		Description: snapshot.State.Description,
+		License:     util.SafeDeref(snapshot.State.License),
+		Tags:        snapshot.State.Tags,
+	}
+	for _, doi := range snapshot.State.DOIs {
+		state.DOIs = append(state.DOIs, dto.SnapshotDOI{
+			DOI:        doi.Value,
+			Datasource: doi.Datasource,
+			Label:      doi.Label,
+			Note:       doi.Note,
+		})
+	}
+	return dto.CollectionSnapshot{
+		CollectionSnapshotSummary: ToDTOCollectionSnapshotSummary(snapshot.SnapshotSummary),
+		State:                     state,
+	}
+}
+
+// GetRestoreRequest returns the update that changes current into the saved state. The update is conditional on the
+// current version, since it is only correct if the collection has not changed since current was read.
+func GetRestoreRequest(current collections.GetCollectionResponse, saved collections.SnapshotState) collections.UpdateCollectionRequest {
+	update := collections.UpdateCollectionRequest{ExpectedVersion: &current.Version}
+	if saved.Name != current.Name {
+		update.Name = &saved.Name
+	}
+	if saved.Description != current.Description {
+		update.Description = &saved.Description
+	}
+	if savedLicense := util.SafeDeref(saved.License); savedLicense != util.SafeDeref(current.License) {
+		// an empty license removes the current one
+		update.License = &savedLicense
+	}
+	if !slices.Equal(saved.Tags, current.Tags) {
+		update.Tags = append([]string{}, saved.Tags...)
+	}
+
+	savedDOIs := doisByValue(saved.DOIs)
+	currentDOIs := doisByValue(current.DOIs)
+	var afterUpdate []string
+	for _, doi := range current.DOIs {
+		if savedDOI, inSaved := savedDOIs[doi.Value]; !inSaved {
+			update.DOIs.Remove = append(update.DOIs.Remove, doi.Value)
+		} else {
+			afterUpdate = append(afterUpdate, doi.Value)
+			if annotation, changed := restoreAnnotation(doi, savedDOI); changed {
+				update.DOIs.Annotate = append(update.DOIs.Annotate, annotation)
+			}
+		}
+	}
+	for _, doi := range saved.DOIs {
+		if _, inCurrent := currentDOIs[doi.Value]; !inCurrent {
+			update.DOIs.Add = append(update.DOIs.Add, doi)
+			afterUpdate = append(afterUpdate, doi.Value)
+		}
+	}
+	// new DOIs go after existing ones, so we only need to set the order if that is not where they were saved
+	if savedOrder := saved.DOIs.Strings(); !slices.Equal(afterUpdate, savedOrder) {
+		update.DOIs.Order = savedOrder
+	}
+	return update
+}
+
+func restoreAnnotation(current, saved collections.DOI) (collections.DOIAnnotation, bool) {
+	annotation := collections.DOIAnnotation{DOI: current.Value}
+	if saved.Label != current.Label {
+		annotation.Label = &saved.Label
+	}
+	if saved.Note != current.Note {
+		annotation.Note = &saved.Note
+	}
+	return annotation, annotation.Label != nil || annotation.Note != nil
+}
+
+// DiffSnapshotStates returns the changes from one state to another. The caller sets the From and To ids of the returned diff.
+func DiffSnapshotStates(from, to collections.SnapshotState) dto.CollectionSnapshotDiff {
+	var diff dto.CollectionSnapshotDiff
+	if from.Name != to.Name {
+		diff.Name = &dto.FieldChange{Old: from.Name, New: to.Name}
+	}
+	if from.Description != to.Description {
+		diff.Description = &dto.FieldChange{Old: from.Description, New: to.Description}
+	}
+	if fromLicense, toLicense := util.SafeDeref(from.License), util.SafeDeref(to.License); fromLicense != toLicense {
+		diff.License = &dto.FieldChange{Old: emptyAsNil(fromLicense), New: emptyAsNil(toLicense)}
+	}
+	if !slices.Equal(from.Tags, to.Tags) {
+		diff.Tags = &dto.FieldChange{Old: nonNilTags(from.Tags), New: nonNilTags(to.Tags)}
+	}
+
+	fromDOIs := doisByValue(from.DOIs)
+	toDOIs := doisByValue(to.DOIs)
+	var fromCommon, toCommon []string
+	for _, doi := range from.DOIs {
+		if _, inTo := toDOIs[doi.Value]; inTo {
+			fromCommon = append(fromCommon, doi.Value)
+		} else {
+			diff.DOIsRemoved = append(diff.DOIsRemoved, doi.Value)
+		}
+	}
+	for _, doi := range to.DOIs {
+		fromDOI, inFrom := fromDOIs[doi.Value]
+		if !inFrom {
+			diff.DOIsAdded = append(diff.DOIsAdded, doi.Value)
+			continue
+		}
+		toCommon = append(toCommon, doi.Value)
+		annotationDiff := dto.DOIAnnotationDiff{DOI: doi.Value}
+		if fromDOI.Label != doi.Label {
+			annotationDiff.Label = &dto.FieldChange{Old: emptyAsNil(fromDOI.Label), New: emptyAsNil(doi.Label)}
+		}
+		if fromDOI.Note != doi.Note {
+			annotationDiff.Note = &dto.FieldChange{Old: emptyAsNil(fromDOI.Note), New: emptyAsNil(doi.Note)}
+		}
+		if annotationDiff.Label != nil || annotationDiff.Note != nil {
+			diff.DOIsAnnotated = append(diff.DOIsAnnotated, annotationDiff)
+		}
+	}
+	diff.Reordered = !slices.Equal(fromCommon, toCommon)
+	return diff
+}
+
+func doisByValue(dois collections.DOIs) map[string]collections.DOI {
+	byValue := make(map[string]collections.DOI, len(dois))
+	for _, doi := range dois {
+		byValue[doi.Value] = doi
+	}
+	return byValue
+}
+
+func emptyAsNil(s string) any {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,503 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCollectionSnapshots(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"create, list, diff, and restore snapshots", testCollectionSnapshots},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+// snapshotsTestParams builds Params for the snapshot routes, all of which share a container talking to the test database.
+type snapshotsTestParams struct {
+	t                *testing.T
+	callingUser      userstest.User
+	collectionNodeID string
+	discoverURL      string
+}
+
+func (p snapshotsTestParams) build(routeKey string, snapshotID *int64, body any) Params {
+	claims := apitest.DefaultClaims(p.callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(p.t)).
+		WithPennsieveConfig(apitest.PennsieveConfig(p.discoverURL)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(p.t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase).
+		WithHTTPTestDiscover(p.discoverURL)
+
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(routeKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, p.collectionNodeID)
+	if snapshotID != nil {
+		requestBuilder = requestBuilder.WithPathParam(SnapshotIDPathParamKey, strconv.FormatInt(*snapshotID, 10))
+	}
+	if body != nil {
+		requestBuilder = requestBuilder.WithBody(p.t, body)
+	}
+
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testCollectionSnapshots(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	dataset1 := expectedDatasets.NewPublished()
+	dataset2 := expectedDatasets.NewPublished()
+	dataset3 := expectedDatasets.NewPublished()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Write).
+		WithPublicDatasets(dataset1, dataset2, dataset3)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+	originalName := expectedCollection.Name
+
+	mockDiscoverServer := httptest.NewServer(mocks.ToDiscoverHandlerFunc(ctx, t, expectedDatasets.GetDatasetsByDOIFunc(t)))
+	defer mockDiscoverServer.Close()
+
+	params := snapshotsTestParams{t: t, callingUser: user, collectionNodeID: *expectedCollection.NodeID, discoverURL: mockDiscoverServer.URL}
+
+	original, err := CreateCollectionSnapshot(ctx, params.build(CreateCollectionSnapshotRouteKey, nil, dto.CreateCollectionSnapshotRequest{Name: " Original "}))
+	require.NoError(t, err)
+	assert.Equal(t, "Original", original.Name)
+	require.NotNil(t, original.CreatedBy)
+	assert.Equal(t, user.NodeID, original.CreatedBy.UserNodeID)
+	assert.Equal(t, originalName, original.State.Name)
+	require.Len(t, original.State.DOIs, 3)
+	assert.Equal(t, dto.SnapshotDOI{DOI: dataset1.DOI, Datasource: datasource.Pennsieve}, original.State.DOIs[0])
+
+	// rename, remove a dataset, label another, and reorder
+	newName := "Renamed"
+	label := "Primary"
+	_, err = PatchCollection(ctx, params.build(PatchCollectionRouteKey, nil, dto.PatchCollectionRequest{
+		Name: &newName,
+		DOIs: &dto.PatchDOIs{
+			Remove:   []string{dataset1.DOI},
+			Annotate: []dto.DOIAnnotation{{DOI: dataset2.DOI, Label: &label}},
+		},
+	}))
+	require.NoError(t, err)
+	_, err = PutDOIOrder(ctx, params.build(PutDOIOrderRouteKey, nil, dto.PutDOIOrderRequest{DOIs: []string{dataset3.DOI, dataset2.DOI}}))
+	require.NoError(t, err)
+
+	renamed, err := CreateCollectionSnapshot(ctx, params.build(CreateCollectionSnapshotRouteKey, nil, dto.CreateCollectionSnapshotRequest{Name: "Renamed"}))
+	require.NoError(t, err)
+
+	snapshots, err := GetCollectionSnapshots(ctx, params.build(GetCollectionSnapshotsRouteKey, nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 2, snapshots.TotalCount)
+	assert.Equal(t, []dto.CollectionSnapshotSummary{renamed.CollectionSnapshotSummary, original.CollectionSnapshotSummary}, snapshots.Snapshots)
+
+	got, err := GetCollectionSnapshot(ctx, params.build(GetCollectionSnapshotRouteKey, &original.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, original.State, got.State)
+
+	expectedDiff := dto.CollectionSnapshotDiff{
+		From:          original.ID,
+		Name:          &dto.FieldChange{Old: originalName, New: newName},
+		DOIsRemoved:   []string{dataset1.DOI},
+		DOIsAnnotated: []dto.DOIAnnotationDiff{{DOI: dataset2.DOI, Label: &dto.FieldChange{Old: nil, New: label}}},
+		Reordered:     true,
+	}
+	diffToCurrent, err := GetCollectionSnapshotDiff(ctx, params.build(GetCollectionSnapshotDiffRouteKey, &original.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, expectedDiff, diffToCurrent)
+
+	diffParams := params.build(GetCollectionSnapshotDiffRouteKey, &original.ID, nil)
+	diffParams.Request.QueryStringParameters = map[string]string{DiffToQueryParamKey: strconv.FormatInt(renamed.ID, 10)}
+	diffToRenamed, err := GetCollectionSnapshotDiff(ctx, diffParams)
+	require.NoError(t, err)
+	expectedDiff.To = &renamed.ID
+	assert.Equal(t, expectedDiff, diffToRenamed)
+
+	restored, err := RestoreCollectionSnapshot(ctx, params.build(RestoreCollectionSnapshotRouteKey, &original.ID, nil))
+	require.NoError(t, err)
+	assertEqualExpectedGetCollectionResponse(t, expectedCollection, restored, expectedDatasets)
+	expectationDB.RequireCollection(ctx, t, expectedCollection, createResp.ID)
+
+	// nothing left to restore
+	diffAfterRestore, err := GetCollectionSnapshotDiff(ctx, params.build(GetCollectionSnapshotDiffRouteKey, &original.ID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, dto.CollectionSnapshotDiff{From: original.ID}, diffAfterRestore)
+}
+
+func TestHandleCollectionSnapshots(t *testing.T) {
+	tests := []struct {
+		name    string
+		tstFunc func(t *testing.T)
+	}{
+		{"create should return Forbidden when user is not an editor", testHandleCreateCollectionSnapshotForbidden},
+		{"create should return Bad Request when name is empty", testHandleCreateCollectionSnapshotEmptyName},
+		{"get should return Not Found when snapshot is not in collection", testHandleGetCollectionSnapshotNotFound},
+		{"list should return empty snapshots array", testHandleGetCollectionSnapshotsNone},
+		{"diff should return Bad Request when to is not an integer", testHandleGetCollectionSnapshotDiffInvalidTo},
+		{"restore should apply the snapshot with a conditional update", testHandleRestoreCollectionSnapshot},
+		{"restore should return Forbidden when user is not an editor", testHandleRestoreCollectionSnapshotForbidden},
+		{"restore should return Conflict when collection changes during restore", testHandleRestoreCollectionSnapshotConflict},
+		{"restore should return Precondition Failed when If-Match does not match", testHandleRestoreCollectionSnapshotIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newHandleSnapshotParams(t *testing.T, routeKey string, expectedCollection *apitest.ExpectedCollection, snapshotID string, store *mocks.CollectionsStore, body any) Params {
+	claims := apitest.DefaultClaims(userstest.SeedUser1)
+	requestBuilder := apitest.NewAPIGatewayRequestBuilder(routeKey).
+		WithClaims(claims).
+		WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID)
+	if len(snapshotID) > 0 {
+		requestBuilder = requestBuilder.WithPathParam(SnapshotIDPathParamKey, snapshotID)
+	}
+	if body != nil {
+		requestBuilder = requestBuilder.WithBody(t, body)
+	}
+	return Params{
+		Request:   requestBuilder.Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(store),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+}
+
+func testHandleCreateCollectionSnapshotForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Read)
+
+	// no CreateSnapshotFunc set, so the mock panics if the store is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	request := dto.CreateCollectionSnapshotRequest{Name: "Snapshot"}
+	response, err := Handle(ctx, NewCreateCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, CreateCollectionSnapshotRouteKey, expectedCollection, "", mockCollectionStore, request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func testHandleCreateCollectionSnapshotEmptyName(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	request := dto.CreateCollectionSnapshotRequest{Name: "  "}
+	response, err := Handle(ctx, NewCreateCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, CreateCollectionSnapshotRouteKey, expectedCollection, "", mocks.NewCollectionsStore(), request))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, "snapshot name cannot be empty")
+}
+
+func testHandleGetCollectionSnapshotNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Guest)
+
+	snapshotID := int64(12)
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetSnapshotFunc(func(_ context.Context, collectionID, actualSnapshotID int64) (collections.Snapshot, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, snapshotID, actualSnapshotID)
+			return collections.Snapshot{}, collections.ErrSnapshotNotFound
+		})
+
+	response, err := Handle(ctx, NewGetCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, GetCollectionSnapshotRouteKey, expectedCollection, strconv.FormatInt(snapshotID, 10), mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Contains(t, response.Body, fmt.Sprintf("snapshot %d not found", snapshotID))
+}
+
+func testHandleGetCollectionSnapshotsNone(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Guest)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetSnapshotsFunc(func(_ context.Context, collectionID int64, limit int, offset int) (collections.GetSnapshotsResponse, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			return collections.GetSnapshotsResponse{Limit: limit, Offset: offset}, nil
+		})
+
+	response, err := Handle(ctx, NewGetCollectionSnapshotsRouteHandler(), newHandleSnapshotParams(t, GetCollectionSnapshotsRouteKey, expectedCollection, "", mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, fmt.Sprintf(`{"limit":%d,"offset":%d,"totalCount":0,"snapshots":[]}`, DefaultGetCollectionsLimit, DefaultGetCollectionsOffset), response.Body)
+}
+
+func testHandleGetCollectionSnapshotDiffInvalidTo(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	params := newHandleSnapshotParams(t, GetCollectionSnapshotDiffRouteKey, expectedCollection, "1", mocks.NewCollectionsStore(), nil)
+	params.Request.QueryStringParameters = map[string]string{DiffToQueryParamKey: "current"}
+	response, err := Handle(ctx, NewGetCollectionSnapshotDiffRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, DiffToQueryParamKey)
+}
+
+func testHandleRestoreCollectionSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithVersion(4).
+		WithUser(userstest.SeedUser1.ID, pgdb.Write)
+
+	snapshotID := int64(3)
+	savedName := "Saved Name"
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetSnapshotFunc(func(_ context.Context, collectionID, actualSnapshotID int64) (collections.Snapshot, error) {
+			assert.Equal(t, *expectedCollection.ID, collectionID)
+			assert.Equal(t, snapshotID, actualSnapshotID)
+			return collections.Snapshot{
+				SnapshotSummary: collections.SnapshotSummary{ID: snapshotID, Name: "Before", CreatedAt: time.Now()},
+				State:           collections.SnapshotState{Name: savedName, Description: expectedCollection.Description},
+			}, nil
+		}).
+		WithUpdateCollectionFunc(func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			require.NotNil(t, update.ExpectedVersion)
+			assert.Equal(t, expectedCollection.Version, *update.ExpectedVersion)
+			require.NotNil(t, update.Name)
+			assert.Equal(t, savedName, *update.Name)
+			assert.Nil(t, update.Description)
+			return expectedCollection.UpdateCollectionFunc(t)(ctx, userID, collectionID, update)
+		})
+
+	response, err := Handle(ctx, NewRestoreCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, RestoreCollectionSnapshotRouteKey, expectedCollection, strconv.FormatInt(snapshotID, 10), mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Body, fmt.Sprintf(`"name":%q`, savedName))
+	assert.Equal(t, ETag(expectedCollection.Version+1), response.Headers["etag"])
+}
+
+func testHandleRestoreCollectionSnapshotForbidden(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().
+		WithUser(userstest.SeedUser1.ID, pgdb.Read)
+
+	// no GetSnapshotFunc or UpdateCollectionFunc set, so the mock panics if the store is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	response, err := Handle(ctx, NewRestoreCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, RestoreCollectionSnapshotRouteKey, expectedCollection, "1", mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func testHandleRestoreCollectionSnapshotConflict(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithVersion(2).
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+		WithGetSnapshotFunc(func(_ context.Context, _, snapshotID int64) (collections.Snapshot, error) {
+			return collections.Snapshot{
+				SnapshotSummary: collections.SnapshotSummary{ID: snapshotID},
+				State:           collections.SnapshotState{Name: "Saved Name"},
+			}, nil
+		}).
+		WithUpdateCollectionFunc(func(_ context.Context, _ int64, _ int64, _ collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
+			return collections.GetCollectionResponse{}, fmt.Errorf("wrapped: %w", collections.ErrCollectionVersionMismatch)
+		})
+
+	response, err := Handle(ctx, NewRestoreCollectionSnapshotRouteHandler(), newHandleSnapshotParams(t, RestoreCollectionSnapshotRouteKey, expectedCollection, "1", mockCollectionStore, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func testHandleRestoreCollectionSnapshotIfMatch(t *testing.T) {
+	ctx := context.Background()
+
+	expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithVersion(5).
+		WithUser(userstest.SeedUser1.ID, pgdb.Owner)
+
+	// no GetSnapshotFunc or UpdateCollectionFunc set, so the mock panics if the store is called
+	mockCollectionStore := mocks.NewCollectionsStore().
+		WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil))
+
+	params := newHandleSnapshotParams(t, RestoreCollectionSnapshotRouteKey, expectedCollection, "1", mockCollectionStore, nil)
+	params.Request.Headers = map[string]string{IfMatchHeader: ETag(4)}
+	response, err := Handle(ctx, NewRestoreCollectionSnapshotRouteHandler(), params)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+}
+
+func TestGetRestoreRequest(t *testing.T) {
+	license := "MIT"
+	doi1, doi2, doi3 := apitest.NewPennsieveDOI(), apitest.NewPennsieveDOI(), apitest.NewExternalDOI()
+
+	current := collections.GetCollectionResponse{
+		CollectionBase: collections.CollectionBase{
+			Name:        "Current",
+			Description: "Same",
+			License:     &license,
+			Tags:        []string{"a"},
+			Version:     7,
+		},
+		DOIs: collections.DOIs{doi1, doi2},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		update := GetRestoreRequest(current, current.SnapshotState())
+		require.NotNil(t, update.ExpectedVersion)
+		assert.Equal(t, int64(7), *update.ExpectedVersion)
+		update.ExpectedVersion = nil
+		assert.Equal(t, collections.UpdateCollectionRequest{}, update)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		saved := collections.SnapshotState{Name: "Saved", Description: "Same", DOIs: collections.DOIs{doi1, doi2}}
+		update := GetRestoreRequest(current, saved)
+		require.NotNil(t, update.Name)
+		assert.Equal(t, "Saved", *update.Name)
+		assert.Nil(t, update.Description)
+		require.NotNil(t, update.License)
+		assert.Empty(t, *update.License)
+		assert.NotNil(t, update.Tags)
+		assert.Empty(t, update.Tags)
+		assert.Equal(t, collections.DOIUpdate{}, update.DOIs)
+	})
+
+	t.Run("DOIs", func(t *testing.T) {
+		labeledDOI1 := doi1
+		labeledDOI1.Label = "Primary"
+		labeledDOI3 := doi3
+		labeledDOI3.Note = "From elsewhere"
+		saved := current.SnapshotState()
+		saved.DOIs = collections.DOIs{labeledDOI3, labeledDOI1}
+
+		update := GetRestoreRequest(current, saved)
+		assert.Equal(t, []string{doi2.Value}, update.DOIs.Remove)
+		assert.Equal(t, []collections.DOI{labeledDOI3}, update.DOIs.Add)
+		require.Len(t, update.DOIs.Annotate, 1)
+		assert.Equal(t, doi1.Value, update.DOIs.Annotate[0].DOI)
+		require.NotNil(t, update.DOIs.Annotate[0].Label)
+		assert.Equal(t, "Primary", *update.DOIs.Annotate[0].Label)
+		assert.Nil(t, update.DOIs.Annotate[0].Note)
+		// added DOIs would otherwise go last
+		assert.Equal(t, []string{doi3.Value, doi1.Value}, update.DOIs.Order)
+	})
+
+	t.Run("added DOIs already last", func(t *testing.T) {
+		saved := current.SnapshotState()
+		saved.DOIs = collections.DOIs{doi1, doi2, doi3}
+
+		update := GetRestoreRequest(current, saved)
+		assert.Equal(t, []collections.DOI{doi3}, update.DOIs.Add)
+		assert.Nil(t, update.DOIs.Order)
+	})
+}
+
+func TestDiffSnapshotStates(t *testing.T) {
+	license := "MIT"
+	doi1, doi2, doi3, doi4 := apitest.NewPennsieveDOI(), apitest.NewPennsieveDOI(), apitest.NewPennsieveDOI(), apitest.NewExternalDOI()
+
+	from := collections.SnapshotState{
+		Name:        "From",
+		Description: "Same",
+		Tags:        []string{"a", "b"},
+		DOIs:        collections.DOIs{doi1, doi2, doi3},
+	}
+
+	t.Run("same", func(t *testing.T) {
+		assert.Equal(t, dto.CollectionSnapshotDiff{}, DiffSnapshotStates(from, from))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		notedDOI3 := doi3
+		notedDOI3.Note = "A note"
+		to := collections.SnapshotState{
+			Name:        "To",
+			Description: "Same",
+			License:     &license,
+			DOIs:        collections.DOIs{notedDOI3, doi4, doi2},
+		}
+		assert.Equal(t, dto.CollectionSnapshotDiff{
+			Name:          &dto.FieldChange{Old: "From", New: "To"},
+			License:       &dto.FieldChange{Old: nil, New: license},
+			Tags:          &dto.FieldChange{Old: []string{"a", "b"}, New: []string{}},
+			DOIsAdded:     []string{doi4.Value},
+			DOIsRemoved:   []string{doi1.Value},
+			DOIsAnnotated: []dto.DOIAnnotationDiff{{DOI: doi3.Value, Note: &dto.FieldChange{Old: nil, New: "A note"}}},
+			Reordered:     true,
+		}, DiffSnapshotStates(from, to))
+	})
+
+	t.Run("removing a DOI is not a reorder", func(t *testing.T) {
+		to := from
+		to.DOIs = collections.DOIs{doi1, doi3}
+		assert.Equal(t, dto.CollectionSnapshotDiff{DOIsRemoved: []string{doi2.Value}}, DiffSnapshotStates(from, to))
+	})
+}
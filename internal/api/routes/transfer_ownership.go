@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var TransferOwnershipRouteKey = fmt.Sprintf("POST /{%s}/transfer-ownership", NodeIDPathParamKey)
+
+// TransferOwnership makes another member of the collection an Owner and demotes the calling owner to Manager.
+// Returns the updated list of members.
+func TransferOwnership(ctx context.Context, params Params) (dto.GetCollectionMembersResponse, error) {
+	nodeID := params.Request.PathParameters[NodeIDPathParamKey]
+	if len(nodeID) == 0 {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewBadRequestError(fmt.Sprintf(`missing %q path parameter`, NodeIDPathParamKey))
+	}
+	userClaim := params.Claims.UserClaim
+	params.Container.AddLoggingContext(
+		slog.String(NodeIDPathParamKey, nodeID),
+		slog.String("userNodeId", userClaim.NodeId))
+
+	requestBody := params.Request.Body
+	if len(requestBody) == 0 {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewBadRequestError("missing request body")
+	}
+
+	var transferRequest dto.TransferOwnershipRequest
+	decoder := json.NewDecoder(strings.NewReader(requestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&transferRequest); err != nil {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewRequestUnmarshallError(transferRequest, err)
+	}
+	newOwnerNodeID := transferRequest.UserNodeID
+	if len(newOwnerNodeID) == 0 {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewBadRequestError("missing userNodeId of new owner")
+	}
+	if newOwnerNodeID == userClaim.NodeId {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewBadRequestError("cannot transfer ownership to oneself")
+	}
+	params.Container.AddLoggingContext(slog.String("newOwnerNodeId", newOwnerNodeID))
+
+	collection, err := params.Container.CollectionsStore().GetCollection(ctx, userClaim.Id, nodeID)
+	if err != nil {
+		if errors.Is(err, collections.ErrCollectionNotFound) {
+			return dto.GetCollectionMembersResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		}
+		return dto.GetCollectionMembersResponse{}, apierrors.NewInternalServerError(
+			"error querying store for collection",
+			err)
+	}
+
+	minRequiredRole := role.Owner
+	if !collection.UserRole.Implies(minRequiredRole) {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewForbiddenError(
+			fmt.Sprintf("collection %s ownership not transferred; requires user role: %s",
+				nodeID,
+				minRequiredRole),
+		)
+	}
+
+	if err := params.Container.CollectionsStore().TransferOwnership(ctx, collection.ID, userClaim.Id, newOwnerNodeID); err != nil {
+		switch {
+		case errors.Is(err, collections.ErrCollectionMemberNotFound):
+			return dto.GetCollectionMembersResponse{}, NewCollectionMemberNotFoundError(nodeID, newOwnerNodeID)
+		case errors.Is(err, collections.ErrCollectionNotFound):
+			return dto.GetCollectionMembersResponse{}, apierrors.NewCollectionNotFoundError(nodeID)
+		case errors.Is(err, collections.ErrNotOwner):
+			// caller was demoted after we looked up the collection above
+			return dto.GetCollectionMembersResponse{}, apierrors.NewConflictErrorWithCause(
+				fmt.Sprintf("collection %s ownership not transferred; user is no longer an owner", nodeID),
+				err)
+		default:
+			return dto.GetCollectionMembersResponse{}, apierrors.NewInternalServerError("error transferring collection ownership", err)
+		}
+	}
+
+	members, err := params.Container.CollectionsStore().GetCollectionMembers(ctx, collection.ID)
+	if err != nil {
+		return dto.GetCollectionMembersResponse{}, apierrors.NewInternalServerError(
+			fmt.Sprintf("error looking up members of collection %s", nodeID),
+			err)
+	}
+
+	return ToDTOCollectionMembers(members), nil
+}
+
+func NewTransferOwnershipRouteHandler() Handler[dto.GetCollectionMembersResponse] {
+	return Handler[dto.GetCollectionMembersResponse]{
+		HandleFunc:        TransferOwnership,
+		SuccessStatusCode: http.StatusOK,
+		Headers:           DefaultResponseHeaders(),
+	}
+}
@@ -0,0 +1,205 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/apierrors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/collections-service/internal/test/userstest"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestTransferOwnership(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T, expectationDB *fixtures.ExpectationDB)
+	}{
+		{"transfer ownership", testTransferOwnership},
+		{"transfer ownership to non-member", testTransferOwnershipNonMember},
+	}
+
+	ctx := context.Background()
+	postgresDBConfig := test.PostgresDBConfig(t)
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, postgresDBConfig)
+			expectationDB := fixtures.NewExpectationDB(db, postgresDBConfig.CollectionsDatabase)
+
+			t.Cleanup(func() {
+				expectationDB.CleanUp(ctx, t)
+			})
+
+			tt.tstFunc(t, expectationDB)
+		})
+	}
+}
+
+func newTransferOwnershipParams(t *testing.T, callingUser userstest.User, collectionNodeID, newOwnerNodeID string) Params {
+	claims := apitest.DefaultClaims(callingUser)
+
+	apiConfig := apitest.NewConfigBuilder().
+		WithPostgresDBConfig(test.PostgresDBConfig(t)).
+		Build()
+
+	container := apitest.NewTestContainer().
+		WithPostgresDB(test.NewPostgresDBFromConfig(t, apiConfig.PostgresDB)).
+		WithCollectionsStoreFromPostgresDB(apiConfig.PostgresDB.CollectionsDatabase)
+
+	return Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(TransferOwnershipRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, collectionNodeID).
+			WithBody(t, dto.TransferOwnershipRequest{UserNodeID: newOwnerNodeID}).
+			Build(),
+		Container: container,
+		Config:    apiConfig,
+		Claims:    &claims,
+	}
+}
+
+func testTransferOwnership(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	newOwner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, newOwner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*newOwner.ID, pgdb.Read)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	response, err := TransferOwnership(ctx, newTransferOwnershipParams(t, owner, *collection.NodeID, newOwner.NodeID))
+	require.NoError(t, err)
+
+	require.Len(t, response.Members, 2)
+	assert.Equal(t, owner.NodeID, response.Members[0].UserNodeID)
+	assert.Equal(t, role.Manager.String(), response.Members[0].Role)
+	assert.Equal(t, newOwner.NodeID, response.Members[1].UserNodeID)
+	assert.Equal(t, role.Owner.String(), response.Members[1].Role)
+
+	collection.SetUser(*owner.ID, pgdb.Administer).SetUser(*newOwner.ID, pgdb.Owner)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testTransferOwnershipNonMember(t *testing.T, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := TransferOwnership(ctx, newTransferOwnershipParams(t, owner, *collection.NodeID, nonMember.NodeID))
+
+	var apiErr *apierrors.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Contains(t, apiErr.UserMessage, nonMember.NodeID)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+// TestHandleTransferOwnership tests that run the Handle wrapper around TransferOwnership
+func TestHandleTransferOwnership(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"transfer ownership, authorization", testTransferOwnershipAuthz},
+		{"transfer ownership to self", testTransferOwnershipToSelf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testTransferOwnershipAuthz(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+	newOwnerNodeID := userstest.SeedUser2.NodeID
+
+	for _, perm := range []pgdb.DbPermission{pgdb.Guest, pgdb.Read, pgdb.Write, pgdb.Delete, pgdb.Administer, pgdb.Owner} {
+		t.Run(perm.String(), func(t *testing.T) {
+			expectedCollection := apitest.NewExpectedCollection().WithRandomID().WithNodeID().WithUser(callingUser.ID, perm)
+
+			mockCollectionStore := mocks.NewCollectionsStore().
+				WithGetCollectionFunc(expectedCollection.GetCollectionFunc(t, nil)).
+				WithTransferOwnershipFunc(func(ctx context.Context, collectionID int64, currentOwnerID int64, userNodeID string) error {
+					require.Equal(t, *expectedCollection.ID, collectionID)
+					require.Equal(t, callingUser.ID, currentOwnerID)
+					require.Equal(t, newOwnerNodeID, userNodeID)
+					return nil
+				}).
+				WithGetCollectionMembersFunc(func(ctx context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+					return []collections.CollectionMember{
+						{UserID: callingUser.ID, UserNodeID: callingUser.NodeID, Role: role.Manager},
+						{UserID: userstest.SeedUser2.ID, UserNodeID: newOwnerNodeID, Role: role.Owner},
+					}, nil
+				})
+
+			params := Params{
+				Request: apitest.NewAPIGatewayRequestBuilder(TransferOwnershipRouteKey).
+					WithClaims(claims).
+					WithPathParam(NodeIDPathParamKey, *expectedCollection.NodeID).
+					WithBody(t, dto.TransferOwnershipRequest{UserNodeID: newOwnerNodeID}).
+					Build(),
+				Container: apitest.NewTestContainer().WithCollectionsStore(mockCollectionStore),
+				Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+				Claims:    &claims,
+			}
+
+			resp, err := Handle(ctx, NewTransferOwnershipRouteHandler(), params)
+			require.NoError(t, err)
+
+			if perm == pgdb.Owner {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				var members dto.GetCollectionMembersResponse
+				require.NoError(t, json.Unmarshal([]byte(resp.Body), &members))
+				assert.Len(t, members.Members, 2)
+			} else {
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				assert.Contains(t, resp.Body, "errorId")
+			}
+		})
+	}
+}
+
+func testTransferOwnershipToSelf(t *testing.T) {
+	ctx := context.Background()
+	callingUser := userstest.SeedUser1
+	claims := apitest.DefaultClaims(callingUser)
+
+	params := Params{
+		Request: apitest.NewAPIGatewayRequestBuilder(TransferOwnershipRouteKey).
+			WithClaims(claims).
+			WithPathParam(NodeIDPathParamKey, uuid.NewString()).
+			WithBody(t, dto.TransferOwnershipRequest{UserNodeID: callingUser.NodeID}).
+			Build(),
+		Container: apitest.NewTestContainer().WithCollectionsStore(mocks.NewCollectionsStore()),
+		Config:    apitest.NewConfigBuilder().WithPennsieveConfig(apitest.PennsieveConfigWithFakeURL()).Build(),
+		Claims:    &claims,
+	}
+
+	resp, err := Handle(ctx, NewTransferOwnershipRouteHandler(), params)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
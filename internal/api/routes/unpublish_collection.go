@@ -55,7 +55,7 @@ func UnpublishCollection(ctx context.Context, params Params) (dto.UnpublishColle
 	}
 
 	// Set unpublish in progress
-	if err := params.Container.CollectionsStore().StartPublish(ctx, collection.ID, userClaim.Id, publishing.RemovalType); err != nil {
+	if err := params.Container.CollectionsStore().StartPublish(ctx, collection.ID, userClaim.Id, publishing.RemovalType, nil); err != nil {
 		if errors.Is(err, collections.ErrPublishInProgress) {
 			// deliberately leave publish status alone, i.e., no cleanup
 			return dto.UnpublishCollectionResponse{}, apierrors.NewConflictError(err.Error())
@@ -130,13 +130,11 @@ func NewUnpublishCollectionRouteHandler() Handler[dto.UnpublishCollectionRespons
 	}
 }
 
+// validatePublishStatusForUnpublish leaves an InProgress publication to StartPublish, which knows whether it is stale.
 func validatePublishStatusForUnpublish(publication *collections.Publication) error {
 	if publication == nil {
 		return apierrors.NewConflictError("error unpublishing: collection has not been published")
 	}
-	if publication.Status == publishing.InProgressStatus {
-		return apierrors.NewConflictError(fmt.Sprintf("error unpublishing: another publication process is already in progress: %s", publication.Type))
-	}
 	if publication.Type == publishing.RemovalType && publication.Status == publishing.CompletedStatus {
 		return apierrors.NewConflictError("error unpublishing: collection already unpublished")
 	}
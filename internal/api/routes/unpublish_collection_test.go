@@ -108,6 +108,8 @@ func testUnpublish(t *testing.T, expectationDB *fixtures.ExpectationDB) {
 
 	startedAt := time.Now().UTC().AddDate(0, -1, 2)
 	finishedAt := startedAt.Add(time.Minute)
+	// recent enough that an in progress publish is not stale
+	inProgressStartedAt := time.Now().UTC().Add(-time.Minute)
 
 	tests := []struct {
 		scenario   string
@@ -117,13 +119,16 @@ func testUnpublish(t *testing.T, expectationDB *fixtures.ExpectationDB) {
 		finishedAt *time.Time
 		allowed    bool
 	}{
-		{"in progress publication should not be allowed", publishing.PublicationType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress publication should not be allowed", publishing.PublicationType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress publication should be allowed", publishing.PublicationType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed publication should be allowed", publishing.PublicationType, publishing.CompletedStatus, startedAt, &finishedAt, true},
 		{"failed publication should be allowed", publishing.PublicationType, publishing.FailedStatus, startedAt, &finishedAt, true},
-		{"in progress revision should not be allowed", publishing.RevisionType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress revision should not be allowed", publishing.RevisionType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress revision should be allowed", publishing.RevisionType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed revision should be allowed", publishing.RevisionType, publishing.CompletedStatus, startedAt, &finishedAt, true},
 		{"failed revision should be allowed", publishing.RevisionType, publishing.FailedStatus, startedAt, &finishedAt, true},
-		{"in progress removal should not be allowed", publishing.RemovalType, publishing.InProgressStatus, startedAt, nil, false},
+		{"in progress removal should not be allowed", publishing.RemovalType, publishing.InProgressStatus, inProgressStartedAt, nil, false},
+		{"stale in progress removal should be allowed", publishing.RemovalType, publishing.InProgressStatus, startedAt, nil, true},
 		{"completed removal should not be allowed", publishing.RemovalType, publishing.CompletedStatus, startedAt, &finishedAt, false},
 		{"failed removal should be allowed", publishing.RemovalType, publishing.FailedStatus, startedAt, &finishedAt, true},
 	}
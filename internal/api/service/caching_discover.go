@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/discovercache"
+	"log/slog"
+	"time"
+)
+
+// CachingDiscover is a Discover that keeps the datasets it looks up in a discovercache.Store, so that DOIs
+// looked up within the TTL do not need a call to the wrapped Discover.
+// The cache is best effort: an error reading or writing it is logged and the wrapped Discover is used instead.
+type CachingDiscover struct {
+	discover    Discover
+	cache       discovercache.Store
+	ttl         time.Duration
+	refreshMode bool
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+func NewCachingDiscover(discover Discover, cache discovercache.Store, ttl time.Duration, logger *slog.Logger) *CachingDiscover {
+	return &CachingDiscover{
+		discover: discover,
+		cache:    cache,
+		ttl:      ttl,
+		now:      time.Now,
+		logger:   logger.With(slog.String("type", "service.CachingDiscover")),
+	}
+}
+
+// WithRefreshMode makes GetDatasetsByDOI return expired entries, listed in the response's Stale field,
+// if the wrapped Discover returns an error while refreshing them.
+func (d *CachingDiscover) WithRefreshMode() *CachingDiscover {
+	d.refreshMode = true
+	return d
+}
+
+// WithClock replaces time.Now when deciding if an entry has expired. For tests.
+func (d *CachingDiscover) WithClock(now func() time.Time) *CachingDiscover {
+	d.now = now
+	return d
+}
+
+func (d *CachingDiscover) GetDatasetsByDOI(ctx context.Context, dois []string) (DatasetsByDOIResponse, error) {
+	cached, err := d.cache.Get(ctx, dois)
+	if err != nil {
+		d.logger.Warn("error reading Discover cache; using Discover", slog.Any("error", err))
+		cached = nil
+	}
+
+	response := DatasetsByDOIResponse{
+		Published:   map[string]dto.PublicDataset{},
+		Unpublished: map[string]dto.Tombstone{},
+	}
+	var expiredOrMissing []string
+	now := d.now()
+	for _, doi := range dois {
+		if entry, found := cached[doi]; found && now.Sub(entry.FetchedAt) < d.ttl {
+			response.add(entry)
+		} else {
+			expiredOrMissing = append(expiredOrMissing, doi)
+		}
+	}
+	if len(expiredOrMissing) == 0 {
+		return response, nil
+	}
+
+	fetched, err := d.discover.GetDatasetsByDOI(ctx, expiredOrMissing)
+	if err != nil {
+		if d.refreshMode {
+			if addStale(response, cached, expiredOrMissing) {
+				d.logger.Warn("error refreshing datasets from Discover; returning expired cache entries",
+					slog.Any("staleDOIs", expiredOrMissing),
+					slog.Any("error", err))
+				response.Stale = expiredOrMissing
+				return response, nil
+			}
+		}
+		return DatasetsByDOIResponse{}, err
+	}
+
+	if err := d.cache.Put(ctx, fetched.Published, fetched.Unpublished); err != nil {
+		d.logger.Warn("error writing Discover cache", slog.Any("error", err))
+	}
+	for doi, published := range fetched.Published {
+		response.Published[doi] = published
+	}
+	for doi, tombstone := range fetched.Unpublished {
+		response.Unpublished[doi] = tombstone
+	}
+	return response, nil
+}
+
+// addStale adds the cached entries of the given DOIs to response. Returns false, and leaves response alone,
+// if any of the DOIs has never been cached, since then there is nothing to return for it.
+func addStale(response DatasetsByDOIResponse, cached map[string]discovercache.Entry, dois []string) bool {
+	for _, doi := range dois {
+		if _, found := cached[doi]; !found {
+			return false
+		}
+	}
+	for _, doi := range dois {
+		response.add(cached[doi])
+	}
+	return true
+}
+
+func (r DatasetsByDOIResponse) add(entry discovercache.Entry) {
+	if entry.Published != nil {
+		r.Published[entry.DOI] = *entry.Published
+	} else if entry.Tombstone != nil {
+		r.Unpublished[entry.DOI] = *entry.Tombstone
+	}
+}
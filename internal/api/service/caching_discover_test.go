@@ -0,0 +1,230 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/discovercache"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+const testCacheTTL = 10 * time.Minute
+
+func TestCachingDiscover(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"unexpired entries should not be looked up in Discover", testCachingDiscoverHit},
+		{"missing and expired entries should be looked up in Discover and cached", testCachingDiscoverMissAndExpired},
+		{"Discover errors should be returned without refresh mode", testCachingDiscoverErrorNoRefresh},
+		{"expired entries should be returned in refresh mode if Discover errors", testCachingDiscoverErrorRefresh},
+		{"Discover errors should be returned in refresh mode if a DOI was never cached", testCachingDiscoverErrorRefreshMissing},
+		{"cache errors should fall back to Discover", testCachingDiscoverCacheErrors},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+// testCache is a map-backed discovercache.Store whose entries are fetched at the given time.
+type testCache struct {
+	entries map[string]discovercache.Entry
+	now     time.Time
+}
+
+func newTestCache(now time.Time) *testCache {
+	return &testCache{entries: map[string]discovercache.Entry{}, now: now}
+}
+
+func (c *testCache) withPublished(dataset dto.PublicDataset, fetchedAt time.Time) *testCache {
+	c.entries[dataset.DOI] = discovercache.Entry{DOI: dataset.DOI, Published: &dataset, FetchedAt: fetchedAt}
+	return c
+}
+
+func (c *testCache) withTombstone(tombstone dto.Tombstone, fetchedAt time.Time) *testCache {
+	c.entries[tombstone.DOI] = discovercache.Entry{DOI: tombstone.DOI, Tombstone: &tombstone, FetchedAt: fetchedAt}
+	return c
+}
+
+func (c *testCache) mock() *mocks.DiscoverCache {
+	return mocks.NewDiscoverCache().
+		WithGetFunc(func(_ context.Context, dois []string) (map[string]discovercache.Entry, error) {
+			found := map[string]discovercache.Entry{}
+			for _, doi := range dois {
+				if entry, ok := c.entries[doi]; ok {
+					found[doi] = entry
+				}
+			}
+			return found, nil
+		}).
+		WithPutFunc(func(_ context.Context, published map[string]dto.PublicDataset, unpublished map[string]dto.Tombstone) error {
+			for _, dataset := range published {
+				c.withPublished(dataset, c.now)
+			}
+			for _, tombstone := range unpublished {
+				c.withTombstone(tombstone, c.now)
+			}
+			return nil
+		})
+}
+
+// recordingDiscover returns a mock Discover that answers from expectedDatasets and records the DOIs it is asked for.
+func recordingDiscover(t *testing.T, expectedDatasets *apitest.ExpectedPennsieveDatasets, requested *[]string) *mocks.Discover {
+	getDatasetsByDOI := expectedDatasets.GetDatasetsByDOIFunc(t)
+	return mocks.NewDiscover().WithGetDatasetsByDOIFunc(func(ctx context.Context, dois []string) (service.DatasetsByDOIResponse, error) {
+		*requested = append(*requested, dois...)
+		return getDatasetsByDOI(ctx, dois)
+	})
+}
+
+func failingDiscover() *mocks.Discover {
+	return mocks.NewDiscover().WithGetDatasetsByDOIFunc(func(_ context.Context, _ []string) (service.DatasetsByDOIResponse, error) {
+		return service.DatasetsByDOIResponse{}, errors.New("discover is down")
+	})
+}
+
+func testCachingDiscoverHit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	published := apitest.NewPublicDataset(apitest.NewPennsieveDOI().Value, apitest.NewBanner())
+	tombstone := apitest.NewTombstone(apitest.NewPennsieveDOI().Value, "Unpublished")
+	cache := newTestCache(now).
+		withPublished(published, now.Add(-testCacheTTL+time.Second)).
+		withTombstone(tombstone, now)
+
+	// no GetDatasetsByDOIFunc set, so the mock will panic if called
+	discover := service.NewCachingDiscover(mocks.NewDiscover(), cache.mock(), testCacheTTL, logging.Default).
+		WithClock(func() time.Time { return now })
+
+	response, err := discover.GetDatasetsByDOI(ctx, []string{published.DOI, tombstone.DOI})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]dto.PublicDataset{published.DOI: published}, response.Published)
+	assert.Equal(t, map[string]dto.Tombstone{tombstone.DOI: tombstone}, response.Unpublished)
+	assert.Empty(t, response.Stale)
+}
+
+func testCachingDiscoverMissAndExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	cached := expectedDatasets.NewPublished()
+	expired := expectedDatasets.NewPublished()
+	missing := expectedDatasets.NewPublished()
+	missingTombstone := expectedDatasets.NewUnpublished()
+
+	// what Discover returns for expired has changed since it was cached
+	staleExpired := expired
+	staleExpired.Version = expired.Version + 1
+
+	cache := newTestCache(now).
+		withPublished(cached, now).
+		withPublished(staleExpired, now.Add(-testCacheTTL))
+
+	var requested []string
+	discover := service.NewCachingDiscover(recordingDiscover(t, expectedDatasets, &requested), cache.mock(), testCacheTTL, logging.Default).
+		WithClock(func() time.Time { return now })
+
+	response, err := discover.GetDatasetsByDOI(ctx, []string{cached.DOI, expired.DOI, missing.DOI, missingTombstone.DOI})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{expired.DOI, missing.DOI, missingTombstone.DOI}, requested)
+	assert.Equal(t, map[string]dto.PublicDataset{cached.DOI: cached, expired.DOI: expired, missing.DOI: missing}, response.Published)
+	assert.Equal(t, map[string]dto.Tombstone{missingTombstone.DOI: missingTombstone}, response.Unpublished)
+
+	// the looked-up datasets should now be cached
+	for _, doi := range []string{expired.DOI, missing.DOI, missingTombstone.DOI} {
+		assert.Equal(t, now, cache.entries[doi].FetchedAt)
+	}
+	assert.Equal(t, expired, *cache.entries[expired.DOI].Published)
+
+	requested = nil
+	_, err = discover.GetDatasetsByDOI(ctx, []string{cached.DOI, expired.DOI, missing.DOI, missingTombstone.DOI})
+	require.NoError(t, err)
+	assert.Empty(t, requested)
+}
+
+func testCachingDiscoverErrorNoRefresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	expired := apitest.NewPublicDataset(apitest.NewPennsieveDOI().Value, apitest.NewBanner())
+	cache := newTestCache(now).withPublished(expired, now.Add(-2*testCacheTTL))
+
+	discover := service.NewCachingDiscover(failingDiscover(), cache.mock(), testCacheTTL, logging.Default).
+		WithClock(func() time.Time { return now })
+
+	_, err := discover.GetDatasetsByDOI(ctx, []string{expired.DOI})
+	assert.ErrorContains(t, err, "discover is down")
+}
+
+func testCachingDiscoverErrorRefresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	fresh := apitest.NewPublicDataset(apitest.NewPennsieveDOI().Value, apitest.NewBanner())
+	expired := apitest.NewPublicDataset(apitest.NewPennsieveDOI().Value, apitest.NewBanner())
+	expiredTombstone := apitest.NewTombstone(apitest.NewPennsieveDOI().Value, "Unpublished")
+	cache := newTestCache(now).
+		withPublished(fresh, now).
+		withPublished(expired, now.Add(-2*testCacheTTL)).
+		withTombstone(expiredTombstone, now.Add(-2*testCacheTTL))
+
+	discover := service.NewCachingDiscover(failingDiscover(), cache.mock(), testCacheTTL, logging.Default).
+		WithRefreshMode().
+		WithClock(func() time.Time { return now })
+
+	response, err := discover.GetDatasetsByDOI(ctx, []string{fresh.DOI, expired.DOI, expiredTombstone.DOI})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]dto.PublicDataset{fresh.DOI: fresh, expired.DOI: expired}, response.Published)
+	assert.Equal(t, map[string]dto.Tombstone{expiredTombstone.DOI: expiredTombstone}, response.Unpublished)
+	assert.ElementsMatch(t, []string{expired.DOI, expiredTombstone.DOI}, response.Stale)
+}
+
+func testCachingDiscoverErrorRefreshMissing(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	expired := apitest.NewPublicDataset(apitest.NewPennsieveDOI().Value, apitest.NewBanner())
+	cache := newTestCache(now).withPublished(expired, now.Add(-2*testCacheTTL))
+
+	discover := service.NewCachingDiscover(failingDiscover(), cache.mock(), testCacheTTL, logging.Default).
+		WithRefreshMode().
+		WithClock(func() time.Time { return now })
+
+	_, err := discover.GetDatasetsByDOI(ctx, []string{expired.DOI, apitest.NewPennsieveDOI().Value})
+	assert.ErrorContains(t, err, "discover is down")
+}
+
+func testCachingDiscoverCacheErrors(t *testing.T) {
+	ctx := context.Background()
+
+	expectedDatasets := apitest.NewExpectedPennsieveDatasets()
+	published := expectedDatasets.NewPublished()
+
+	cache := mocks.NewDiscoverCache().
+		WithGetFunc(func(_ context.Context, _ []string) (map[string]discovercache.Entry, error) {
+			return nil, errors.New("cache read error")
+		}).
+		WithPutFunc(func(_ context.Context, _ map[string]dto.PublicDataset, _ map[string]dto.Tombstone) error {
+			return errors.New("cache write error")
+		})
+
+	var requested []string
+	discover := service.NewCachingDiscover(recordingDiscover(t, expectedDatasets, &requested), cache, testCacheTTL, logging.Default)
+
+	response, err := discover.GetDatasetsByDOI(ctx, []string{published.DOI})
+	require.NoError(t, err)
+	assert.Equal(t, []string{published.DOI}, requested)
+	assert.Equal(t, map[string]dto.PublicDataset{published.DOI: published}, response.Published)
+}
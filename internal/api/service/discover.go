@@ -19,11 +19,12 @@ type Discover interface {
 
 type HTTPDiscover struct {
 	url    string
+	client util.HTTPInvoker
 	logger *slog.Logger
 }
 
-func NewHTTPDiscover(discoverURL string, logger *slog.Logger) *HTTPDiscover {
-	return &HTTPDiscover{url: discoverURL, logger: logger}
+func NewHTTPDiscover(discoverURL string, client util.HTTPInvoker, logger *slog.Logger) *HTTPDiscover {
+	return &HTTPDiscover{url: discoverURL, client: client, logger: logger}
 }
 
 func (d *HTTPDiscover) GetDatasetsByDOI(ctx context.Context, dois []string) (DatasetsByDOIResponse, error) {
@@ -56,7 +57,7 @@ func (d *HTTPDiscover) InvokePennsieve(ctx context.Context, requestParams reques
 	if err != nil {
 		return nil, fmt.Errorf("error creating %s request: %w", requestParams, err)
 	}
-	return util.Invoke(req, d.logger)
+	return d.client.Invoke(req)
 }
 
 type requestParameters struct {
@@ -98,4 +99,7 @@ func makeJSONBody(structBody any) (io.Reader, error) {
 type DatasetsByDOIResponse struct {
 	Published   map[string]dto.PublicDataset `json:"published"`
 	Unpublished map[string]dto.Tombstone     `json:"unpublished"`
+	// Stale lists the DOIs whose info came from expired cache entries because Discover could not be reached.
+	// Only CachingDiscover in refresh mode sets it.
+	Stale []string `json:"-"`
 }
@@ -67,7 +67,7 @@ func lookupDOIs(ctx context.Context, t *testing.T, discoverService *HTTPDiscover
 func TestBatchSize(t *testing.T) {
 	t.Skip("just meant to be run manually to see how many DOIs we should request in a batch")
 	ctx := context.Background()
-	discover := NewHTTPDiscover("https://api.pennsieve.io/discover", logging.Default)
+	discover := NewHTTPDiscover("https://api.pennsieve.io/discover", util.NewHTTPClient("discover", util.DefaultHTTPClientConfig(), logging.Default), logging.Default)
 
 	// Try these batch sizes. When more than 80 we start to get URL-too-long errors.
 	batchSizes := []int{1, 5, 10, 25, 50, 60, 70, 80}
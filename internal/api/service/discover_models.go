@@ -91,6 +91,46 @@ type FinalizeDOICollectionPublishResponse struct {
 	Status dto.PublishStatus `json:"status"`
 }
 
+// ReviseDOICollectionRequest updates the metadata of the latest published version of a collection
+// without changing its DOIs. The manifest of the revision must already be in S3.
+type ReviseDOICollectionRequest struct {
+	// All Values Required
+
+	Name              string                `json:"name"`
+	Description       string                `json:"description"`
+	License           string                `json:"license"`
+	Tags              []string              `json:"tags"`
+	Contributors      []InternalContributor `json:"contributors"`
+	OwnerID           int64                 `json:"ownerId"`
+	OwnerNodeID       string                `json:"ownerNodeId"`
+	OwnerFirstName    string                `json:"ownerFirstName"`
+	OwnerLastName     string                `json:"ownerLastName"`
+	OwnerORCID        string                `json:"ownerOrcid"`
+	CollectionNodeID  string                `json:"collectionNodeId"`
+	PublishedVersion  int                   `json:"publishedVersion"`
+	Revision          int                   `json:"revision"`
+	ManifestKey       string                `json:"manifestKey"`
+	ManifestVersionID string                `json:"manifestVersionId"`
+}
+
+func (r ReviseDOICollectionRequest) MarshalJSON() ([]byte, error) {
+	if r.Contributors == nil {
+		r.Contributors = []InternalContributor{}
+	}
+	if r.Tags == nil {
+		r.Tags = []string{}
+	}
+	type alias ReviseDOICollectionRequest
+	return json.Marshal(alias(r))
+}
+
+type ReviseDOICollectionResponse struct {
+	PublishedDatasetID int               `json:"publishedDatasetId"`
+	PublishedVersion   int               `json:"publishedVersion"`
+	Revision           int               `json:"revision"`
+	Status             dto.PublishStatus `json:"status"`
+}
+
 type InternalContributorBuilder struct {
 	c    *InternalContributor
 	hash hash.Hash32
@@ -26,7 +26,7 @@ func TestHTTPDiscover_GetDatasetsByDOI(t *testing.T) {
 	}))
 	defer discoverServer.Close()
 
-	discover := service.NewHTTPDiscover(discoverServer.URL, logging.Default)
+	discover := service.NewHTTPDiscover(discoverServer.URL, apitest.NewHTTPClient(logging.Default), logging.Default)
 
 	response, err := discover.GetDatasetsByDOI(ctx, []string{publishedDOI.Value})
 	require.NoError(t, err)
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ExternalDOI looks up non-Pennsieve DOIs.
+type ExternalDOI interface {
+	ResolveDOIs(ctx context.Context, dois []string) (ResolveDOIsResponse, error)
+}
+
+type ResolveDOIsResponse struct {
+	Resolved map[string]dto.ExternalDataset
+	// Unresolved contains the requested DOIs that the resolver does not know about, in request order.
+	Unresolved []string
+}
+
+// CSLJSONContentType is requested from the resolver so that we get the same metadata format
+// regardless of the DOI's registration agency.
+const CSLJSONContentType = "application/vnd.citationstyles.csl+json"
+
+// maxConcurrentResolves limits the number of simultaneous requests to the resolver for a single ResolveDOIs call.
+const maxConcurrentResolves = 5
+
+// HTTPExternalDOI resolves DOIs using content negotiation against a doi.org-style resolver.
+// See https://citation.crosscite.org/docs.html
+type HTTPExternalDOI struct {
+	url    string
+	client util.HTTPInvoker
+	logger *slog.Logger
+}
+
+func NewHTTPExternalDOI(resolverURL string, client util.HTTPInvoker, logger *slog.Logger) *HTTPExternalDOI {
+	return &HTTPExternalDOI{url: strings.TrimSuffix(resolverURL, "/"), client: client, logger: logger}
+}
+
+func (e *HTTPExternalDOI) ResolveDOIs(ctx context.Context, dois []string) (ResolveDOIsResponse, error) {
+	type result struct {
+		dataset *dto.ExternalDataset
+		err     error
+	}
+	results := make([]result, len(dois))
+
+	semaphore := make(chan struct{}, maxConcurrentResolves)
+	var wg sync.WaitGroup
+	for i, doi := range dois {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			dataset, err := e.resolveDOI(ctx, doi)
+			results[i] = result{dataset: dataset, err: err}
+		}()
+	}
+	wg.Wait()
+
+	response := ResolveDOIsResponse{Resolved: map[string]dto.ExternalDataset{}}
+	var errs []error
+	for i, r := range results {
+		switch {
+		case r.err != nil:
+			errs = append(errs, r.err)
+		case r.dataset == nil:
+			response.Unresolved = append(response.Unresolved, dois[i])
+		default:
+			response.Resolved[dois[i]] = *r.dataset
+		}
+	}
+	if len(errs) > 0 {
+		return ResolveDOIsResponse{}, errors.Join(errs...)
+	}
+	return response, nil
+}
+
+// resolveDOI returns nil, nil if the resolver returns a 404 for doi.
+func (e *HTTPExternalDOI) resolveDOI(ctx context.Context, doi string) (*dto.ExternalDataset, error) {
+	requestParams := requestParameters{
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/%s", e.url, escapeDOI(doi)),
+	}
+	request, err := http.NewRequestWithContext(ctx, requestParams.method, requestParams.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s request: %w", requestParams, err)
+	}
+	request.Header.Add("accept", CSLJSONContentType)
+
+	response, err := e.client.Invoke(request)
+	if err != nil {
+		var httpError *util.HTTPError
+		if errors.As(err, &httpError) && httpError.StatusCode() == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer util.CloseAndWarn(response, e.logger)
+
+	var item cslItem
+	if err := util.UnmarshallResponse(response, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshalling response to %s: %w", requestParams, err)
+	}
+	dataset := item.toExternalDataset(doi)
+	return &dataset, nil
+}
+
+// escapeDOI escapes each segment of the DOI, leaving the prefix/suffix separator(s) alone
+// since resolvers expect them unescaped.
+func escapeDOI(doi string) string {
+	segments := strings.Split(doi, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// cslItem is the subset of CSL JSON that we use.
+// See https://github.com/citation-style-language/schema/blob/master/schemas/input/csl-data.json
+type cslItem struct {
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	URL       string    `json:"URL"`
+	Publisher string    `json:"publisher"`
+	Author    []cslName `json:"author"`
+	Issued    cslDate   `json:"issued"`
+	Published cslDate   `json:"published"`
+}
+
+type cslName struct {
+	Family  string `json:"family"`
+	Given   string `json:"given"`
+	Literal string `json:"literal"`
+}
+
+func (n cslName) String() string {
+	if len(n.Literal) > 0 {
+		return n.Literal
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", n.Given, n.Family))
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+func (d cslDate) year() int {
+	if len(d.DateParts) == 0 || len(d.DateParts[0]) == 0 {
+		return 0
+	}
+	return d.DateParts[0][0]
+}
+
+// toExternalDataset uses requestedDOI as the DOI of the returned dataset so that callers can match up
+// responses with requests. Registration agencies sometimes return the DOI in a different case.
+func (i cslItem) toExternalDataset(requestedDOI string) dto.ExternalDataset {
+	dataset := dto.ExternalDataset{
+		DOI:             requestedDOI,
+		Title:           i.Title,
+		URL:             i.URL,
+		Publisher:       i.Publisher,
+		PublicationYear: i.Issued.year(),
+		Type:            i.Type,
+	}
+	if dataset.PublicationYear == 0 {
+		dataset.PublicationYear = i.Published.year()
+	}
+	for _, author := range i.Author {
+		if name := author.String(); len(name) > 0 {
+			dataset.Creators = append(dataset.Creators, name)
+		}
+	}
+	return dataset
+}
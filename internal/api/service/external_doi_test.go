@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPExternalDOI_ResolveDOIs(t *testing.T) {
+	expectedDatasets := apitest.NewExpectedExternalDatasets()
+	resolved1 := expectedDatasets.NewResolved()
+	unresolved1 := expectedDatasets.NewUnresolved()
+	resolved2 := expectedDatasets.NewResolved()
+	unresolved2 := expectedDatasets.NewUnresolved()
+
+	mockServer := httptest.NewServer(mocks.NewDOIResolverMux(t).WithExternalDatasets(resolved1, resolved2))
+	defer mockServer.Close()
+
+	externalDOIService := service.NewHTTPExternalDOI(mockServer.URL, apitest.NewHTTPClient(logging.Default), logging.Default)
+
+	response, err := externalDOIService.ResolveDOIs(context.Background(), []string{resolved1.DOI, unresolved1, resolved2.DOI, unresolved2})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]dto.ExternalDataset{resolved1.DOI: resolved1, resolved2.DOI: resolved2}, response.Resolved)
+	assert.Equal(t, []string{unresolved1, unresolved2}, response.Unresolved)
+}
+
+func TestHTTPExternalDOI_ResolveDOIs_CSLJSON(t *testing.T) {
+	doi := apitest.NewExternalDOI().Value
+	// Trimmed down version of what DataCite returns for a dataset DOI
+	cslJSON := map[string]any{
+		"type": "dataset",
+		"id":   "https://doi.org/" + doi,
+		"author": []map[string]any{
+			{"family": "Lovelace", "given": "Ada"},
+			{"literal": "The Analytical Engine Consortium"},
+			{"family": "Babbage"},
+		},
+		"published": map[string]any{"date-parts": [][]int{{1843, 10}}},
+		"DOI":       doi,
+		"publisher": "Royal Society",
+		"title":     "Notes on the Analytical Engine",
+		"URL":       "https://example.com/notes",
+	}
+
+	mockServer := httptest.NewServer(mocks.NewDOIResolverMux(t).WithCSLJSON(doi, cslJSON))
+	defer mockServer.Close()
+
+	externalDOIService := service.NewHTTPExternalDOI(mockServer.URL, apitest.NewHTTPClient(logging.Default), logging.Default)
+
+	response, err := externalDOIService.ResolveDOIs(context.Background(), []string{doi})
+	require.NoError(t, err)
+
+	assert.Empty(t, response.Unresolved)
+	assert.Equal(t, map[string]dto.ExternalDataset{doi: {
+		DOI:             doi,
+		Title:           "Notes on the Analytical Engine",
+		URL:             "https://example.com/notes",
+		Publisher:       "Royal Society",
+		PublicationYear: 1843,
+		Type:            "dataset",
+		Creators:        []string{"Ada Lovelace", "The Analytical Engine Consortium", "Babbage"},
+	}}, response.Resolved)
+}
+
+func TestHTTPExternalDOI_ResolveDOIs_Error(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	defer mockServer.Close()
+
+	externalDOIService := service.NewHTTPExternalDOI(mockServer.URL, apitest.NewHTTPClient(logging.Default), logging.Default)
+
+	_, err := externalDOIService.ResolveDOIs(context.Background(), []string{apitest.NewExternalDOI().Value})
+	var httpError *util.HTTPError
+	require.ErrorAs(t, err, &httpError)
+	assert.Equal(t, http.StatusBadGateway, httpError.StatusCode())
+}
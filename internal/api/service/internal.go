@@ -9,13 +9,13 @@ import (
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/organization"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
-	"log/slog"
 	"net/http"
 	"time"
 )
 
 type InternalService struct {
 	jwtSecretKey string
+	client       util.HTTPInvoker
 }
 
 type InternalClaims struct {
@@ -41,7 +41,7 @@ func (c InternalClaims) ServiceClaim(duration time.Duration) *jwtdiscover.Servic
 	return jwtdiscover.GenerateServiceClaim(duration).WithOrganizationClaim(c.organizationClaim).WithDatasetClaim(c.datasetClaim)
 }
 
-func (d *InternalService) InvokePennsieve(ctx context.Context, logger *slog.Logger, internalClaims InternalClaims, requestParams requestParameters) (*http.Response, error) {
+func (d *InternalService) InvokePennsieve(ctx context.Context, internalClaims InternalClaims, requestParams requestParameters) (*http.Response, error) {
 	req, err := newPennsieveRequest(ctx, requestParams)
 	if err != nil {
 		return nil, fmt.Errorf("error creating %s request: %w", requestParams, err)
@@ -49,7 +49,7 @@ func (d *InternalService) InvokePennsieve(ctx context.Context, logger *slog.Logg
 	if err := d.addAuth(internalClaims, req); err != nil {
 		return nil, err
 	}
-	return util.Invoke(req, logger)
+	return d.client.Invoke(req)
 }
 
 func (d *InternalService) addAuth(internalClaims InternalClaims, request *http.Request) error {
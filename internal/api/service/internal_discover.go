@@ -16,6 +16,7 @@ import (
 type InternalDiscover interface {
 	PublishCollection(ctx context.Context, collectionID int64, userRole role.Role, request PublishDOICollectionRequest) (PublishDOICollectionResponse, error)
 	FinalizeCollectionPublish(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request FinalizeDOICollectionPublishRequest) (FinalizeDOICollectionPublishResponse, error)
+	ReviseCollection(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request ReviseDOICollectionRequest) (ReviseDOICollectionResponse, error)
 	UnpublishCollection(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (DatasetPublishStatusResponse, error)
 	GetCollectionPublishStatus(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (DatasetPublishStatusResponse, error)
 }
@@ -27,9 +28,9 @@ type HTTPInternalDiscover struct {
 	logger                *slog.Logger
 }
 
-func NewHTTPInternalDiscover(internalDiscoverURL, jwtSecretKey string, collectionNamespaceID int64, logger *slog.Logger) *HTTPInternalDiscover {
+func NewHTTPInternalDiscover(internalDiscoverURL, jwtSecretKey string, collectionNamespaceID int64, client util.HTTPInvoker, logger *slog.Logger) *HTTPInternalDiscover {
 	return &HTTPInternalDiscover{
-		InternalService:       InternalService{jwtSecretKey: jwtSecretKey},
+		InternalService:       InternalService{jwtSecretKey: jwtSecretKey, client: client},
 		url:                   internalDiscoverURL,
 		collectionNamespaceID: collectionNamespaceID,
 		logger:                logger,
@@ -43,7 +44,7 @@ func (d *HTTPInternalDiscover) PublishCollection(ctx context.Context, collection
 		url:    fmt.Sprintf("%s/collection/%d/publish", d.url, collectionID),
 		body:   request,
 	}
-	response, err := d.InvokePennsieve(ctx, d.logger, internalClaims, requestParams)
+	response, err := d.InvokePennsieve(ctx, internalClaims, requestParams)
 	if err != nil {
 		return PublishDOICollectionResponse{}, err
 	}
@@ -69,7 +70,7 @@ func (d *HTTPInternalDiscover) FinalizeCollectionPublish(ctx context.Context, co
 
 	internalClaims := NewInternalClaims(d.collectionNamespaceID, collectionNodeID, collectionID, userRole)
 
-	response, err := d.InvokePennsieve(ctx, d.logger, internalClaims, requestParams)
+	response, err := d.InvokePennsieve(ctx, internalClaims, requestParams)
 	if err != nil {
 		return FinalizeDOICollectionPublishResponse{}, err
 	}
@@ -85,12 +86,37 @@ func (d *HTTPInternalDiscover) FinalizeCollectionPublish(ctx context.Context, co
 	return responseDTO, nil
 }
 
+func (d *HTTPInternalDiscover) ReviseCollection(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request ReviseDOICollectionRequest) (ReviseDOICollectionResponse, error) {
+	requestParams := requestParameters{
+		method: http.MethodPost,
+		url:    fmt.Sprintf("%s/collection/%d/revise", d.url, collectionID),
+		body:   request,
+	}
+
+	internalClaims := NewInternalClaims(d.collectionNamespaceID, collectionNodeID, collectionID, userRole)
+
+	response, err := d.InvokePennsieve(ctx, internalClaims, requestParams)
+	if err != nil {
+		return ReviseDOICollectionResponse{}, err
+	}
+	defer util.CloseAndWarn(response, d.logger)
+
+	var responseDTO ReviseDOICollectionResponse
+	if err := util.UnmarshallResponse(response, &responseDTO); err != nil {
+		return ReviseDOICollectionResponse{}, fmt.Errorf(
+			"error unmarshalling response to %s: %w",
+			requestParams,
+			err)
+	}
+	return responseDTO, nil
+}
+
 func (d *HTTPInternalDiscover) UnpublishCollection(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (DatasetPublishStatusResponse, error) {
 	requestParams := requestParameters{method: http.MethodPost, url: fmt.Sprintf("%s/collection/%d/unpublish", d.url, collectionID)}
 
 	internalClaims := NewInternalClaims(d.collectionNamespaceID, collectionNodeID, collectionID, userRole)
 
-	response, err := d.InvokePennsieve(ctx, d.logger, internalClaims, requestParams)
+	response, err := d.InvokePennsieve(ctx, internalClaims, requestParams)
 	if err != nil {
 		return DatasetPublishStatusResponse{}, err
 	}
@@ -126,7 +152,7 @@ func (d *HTTPInternalDiscover) GetCollectionPublishStatus(ctx context.Context, c
 
 	internalClaims := NewInternalClaims(d.collectionNamespaceID, collectionNodeID, collectionID, userRole)
 
-	response, err := d.InvokePennsieve(ctx, d.logger, internalClaims, requestParams)
+	response, err := d.InvokePennsieve(ctx, internalClaims, requestParams)
 	if err != nil {
 		return DatasetPublishStatusResponse{}, err
 	}
@@ -30,7 +30,7 @@ func TestUnpublishCollectionNoContent(t *testing.T) {
 	}))
 	defer mockServer.Close()
 
-	discover := service.NewHTTPInternalDiscover(mockServer.URL, uuid.NewString(), apitest.CollectionsIDSpaceID, logging.Default)
+	discover := service.NewHTTPInternalDiscover(mockServer.URL, uuid.NewString(), apitest.CollectionsIDSpaceID, apitest.NewHTTPClient(logging.Default), logging.Default)
 
 	_, err := discover.UnpublishCollection(context.Background(), collectionID, collectionNodeID, role.Owner)
 	var neverPublishedErr service.CollectionNeverPublishedError
@@ -67,7 +67,7 @@ func TestUnpublishCollectionContent(t *testing.T) {
 	}))
 	defer mockServer.Close()
 
-	discover := service.NewHTTPInternalDiscover(mockServer.URL, uuid.NewString(), apitest.CollectionsIDSpaceID, logging.Default)
+	discover := service.NewHTTPInternalDiscover(mockServer.URL, uuid.NewString(), apitest.CollectionsIDSpaceID, apitest.NewHTTPClient(logging.Default), logging.Default)
 
 	resp, err := discover.UnpublishCollection(context.Background(), int64(discoverResp.SourceDatasetID), collectionNodeID, role.Owner)
 
@@ -75,3 +75,51 @@ func TestUnpublishCollectionContent(t *testing.T) {
 
 	assert.Equal(t, discoverResp, resp)
 }
+
+func TestReviseCollection(t *testing.T) {
+	collectionID := int64(7)
+	collectionNodeID := uuid.NewString()
+
+	request := service.ReviseDOICollectionRequest{
+		Name:              uuid.NewString(),
+		Description:       uuid.NewString(),
+		License:           "MIT",
+		CollectionNodeID:  collectionNodeID,
+		PublishedVersion:  2,
+		Revision:          3,
+		ManifestKey:       "301/manifest.json",
+		ManifestVersionID: uuid.NewString(),
+	}
+	discoverResp := service.ReviseDOICollectionResponse{
+		PublishedDatasetID: 301,
+		PublishedVersion:   request.PublishedVersion,
+		Revision:           request.Revision,
+		Status:             dto.PublishSucceeded,
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/collection/%d/revise", collectionID), request.RequestURI)
+		assert.Equal(t, http.MethodPost, request.Method)
+
+		var actualRequest map[string]any
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&actualRequest))
+		// nil slices should be sent as empty arrays
+		assert.Equal(t, []any{}, actualRequest["tags"])
+		assert.Equal(t, []any{}, actualRequest["contributors"])
+		assert.Equal(t, float64(3), actualRequest["revision"])
+
+		writer.WriteHeader(http.StatusOK)
+		respBytes, err := json.Marshal(discoverResp)
+		require.NoError(t, err)
+		_, err = writer.Write(respBytes)
+		require.NoError(t, err)
+	}))
+	defer mockServer.Close()
+
+	discover := service.NewHTTPInternalDiscover(mockServer.URL, uuid.NewString(), apitest.CollectionsIDSpaceID, apitest.NewHTTPClient(logging.Default), logging.Default)
+
+	resp, err := discover.ReviseCollection(context.Background(), collectionID, collectionNodeID, role.Owner, request)
+	require.NoError(t, err)
+
+	assert.Equal(t, discoverResp, resp)
+}
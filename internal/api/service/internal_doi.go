@@ -22,9 +22,9 @@ type HTTPDOI struct {
 	logger                *slog.Logger
 }
 
-func NewHTTPDOI(doiServiceURL, jwtSecretKey string, collectionNamespaceID int64, logger *slog.Logger) *HTTPDOI {
+func NewHTTPDOI(doiServiceURL, jwtSecretKey string, collectionNamespaceID int64, client util.HTTPInvoker, logger *slog.Logger) *HTTPDOI {
 	return &HTTPDOI{
-		InternalService:       InternalService{jwtSecretKey: jwtSecretKey},
+		InternalService:       InternalService{jwtSecretKey: jwtSecretKey, client: client},
 		url:                   doiServiceURL,
 		collectionNamespaceID: collectionNamespaceID,
 		logger:                logger,
@@ -37,7 +37,7 @@ func (h *HTTPDOI) GetLatestDOI(ctx context.Context, collectionID int64, collecti
 		method: http.MethodGet,
 		url:    fmt.Sprintf("%s/organizations/%d/datasets/%d/doi", h.url, h.collectionNamespaceID, collectionID),
 	}
-	response, err := h.InvokePennsieve(ctx, h.logger, internalClaims, requestParams)
+	response, err := h.InvokePennsieve(ctx, internalClaims, requestParams)
 	if err != nil {
 		var e *util.HTTPError
 		switch {
@@ -49,7 +49,7 @@ func TestHTTPDOI_GetLatestDOI_OK(t *testing.T) {
 	mockServer := httptest.NewServer(doiMux)
 	defer mockServer.Close()
 
-	doiService := service.NewHTTPDOI(mockServer.URL, jwtSecreteKey, apitest.CollectionsIDSpaceID, logging.Default)
+	doiService := service.NewHTTPDOI(mockServer.URL, jwtSecreteKey, apitest.CollectionsIDSpaceID, apitest.NewHTTPClient(logging.Default), logging.Default)
 
 	response, err := doiService.GetLatestDOI(context.Background(), expectedCollectionID, expectedCollectionNodeID, expectedRole)
 	require.NoError(t, err)
@@ -80,7 +80,7 @@ func TestHTTPDOI_GetLatestDOI_NotFound(t *testing.T) {
 	mockServer := httptest.NewServer(doiMux)
 	defer mockServer.Close()
 
-	doiService := service.NewHTTPDOI(mockServer.URL, jwtSecretKey, apitest.CollectionsIDSpaceID, logging.Default)
+	doiService := service.NewHTTPDOI(mockServer.URL, jwtSecretKey, apitest.CollectionsIDSpaceID, apitest.NewHTTPClient(logging.Default), logging.Default)
 
 	_, err := doiService.GetLatestDOI(context.Background(), expectedCollectionID, expectedCollectionNodeID, expectedRole)
 
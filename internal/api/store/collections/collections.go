@@ -2,6 +2,7 @@ package collections
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
@@ -9,8 +10,11 @@ import (
 	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/shared/util"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 )
@@ -19,36 +23,159 @@ const MaxBannerDOIsPerCollection = config.MaxBannersPerCollection
 
 type Store interface {
 	CreateCollection(ctx context.Context, request CreateCollectionRequest) (CreateCollectionResponse, error)
-	// GetCollections returns a paginated list of collection summaries that the given user has at least guest permission on.
-	GetCollections(ctx context.Context, userID int64, limit int, offset int) (GetCollectionsResponse, error)
+	// GetCollections returns a paginated list of collection summaries that the given user has at least guest permission on,
+	// either directly or through a team or organization.
+	// The results are filtered and ordered as described by query.
+	GetCollections(ctx context.Context, userID int64, limit int, offset int, query GetCollectionsQuery) (GetCollectionsResponse, error)
 	// GetCollection returns the given collection if it exists and if the given user has at least guest permission on it.
 	GetCollection(ctx context.Context, userID int64, nodeID string) (GetCollectionResponse, error)
-	DeleteCollection(ctx context.Context, collectionID int64) error
+	// DeleteCollection moves the given collection to the trash. Collections in the trash are hidden from every other method
+	// until they are restored by RestoreCollection or permanently deleted by PurgeDeletedCollections.
+	// actorID is the user making the change. If expectedVersion is not nil, returns ErrCollectionVersionMismatch
+	// unless the collection's current version is *expectedVersion.
+	DeleteCollection(ctx context.Context, collectionID int64, actorID int64, expectedVersion *int64) error
+	// GetDeletedCollections returns a paginated list of the collections in the trash on which the given user has at least minRole,
+	// either directly or through a team or organization. Most recently deleted collections are first.
+	GetDeletedCollections(ctx context.Context, userID int64, minRole role.Role, limit int, offset int) (GetDeletedCollectionsResponse, error)
+	// GetDeletedCollection returns the given collection if it is in the trash and if the given user has at least guest permission on it.
+	GetDeletedCollection(ctx context.Context, userID int64, nodeID string) (DeletedCollection, error)
+	// RestoreCollection takes the given collection out of the trash.
+	// Returns ErrCollectionNotFound if the collection is not in the trash. actorID is the user making the change.
+	RestoreCollection(ctx context.Context, collectionID int64, actorID int64) error
+	// PurgeDeletedCollections permanently deletes the collections moved to the trash before deletedBefore
+	// and returns the number deleted.
+	PurgeDeletedCollections(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// UpdateCollection applies update and returns the updated collection as seen by the given user.
+	// Returns ErrCollectionVersionMismatch if update.ExpectedVersion is set and the collection's version is different.
 	UpdateCollection(ctx context.Context, userID, collectionID int64, update UpdateCollectionRequest) (GetCollectionResponse, error)
+	// ReorderDOIs sets the order of the DOIs in the given collection to the order of dois and returns the updated collection as seen by the given user.
+	// Returns ErrDOIOrderMismatch if dois is not exactly the DOIs in the collection.
+	ReorderDOIs(ctx context.Context, userID, collectionID int64, dois []string) (GetCollectionResponse, error)
 	// StartPublish returns a collections.ErrPublishInProgress error if the status of the given collection is InProgress
-	StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type) error
+	// and the publish is not stale, that is, it started less than the store's publish timeout ago.
+	// If expectedVersion is not nil, returns ErrCollectionVersionMismatch unless the collection's current version is *expectedVersion.
+	StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type, expectedVersion *int64) error
 	// FinishPublish updates the existing publish status of collection with the given status.
 	// If strict is true, will return an error if no status is found
 	// otherwise, no error for this situation
 	FinishPublish(ctx context.Context, collectionID int64, publishingStatus publishing.Status, strict bool) error
+	// GetStalePublishes returns the publishes that have been InProgress since before startedBefore, oldest first.
+	GetStalePublishes(ctx context.Context, startedBefore time.Time) ([]StalePublish, error)
+	// FinishStalePublish is like FinishPublish, but only updates the status if stale is still the collection's current publish.
+	// Returns false if that publish has since finished or been replaced by a new one.
+	FinishStalePublish(ctx context.Context, stale StalePublish, publishingStatus publishing.Status) (bool, error)
+	// CreatePublishJob queues a publish job for the given collection, started by the given user.
+	// Earlier unfinished jobs of the collection are failed, since the publish they were running has been replaced.
+	CreatePublishJob(ctx context.Context, collectionID int64, userID int64) (PublishJob, error)
+	// GetPublishJob returns ErrPublishJobNotFound if there is no such job.
+	GetPublishJob(ctx context.Context, jobID string) (PublishJob, error)
+	// GetLatestPublishJob returns the most recently created publish job of the given collection.
+	// Returns ErrPublishJobNotFound if the collection has none.
+	GetLatestPublishJob(ctx context.Context, collectionID int64) (PublishJob, error)
+	// StartPublishJobAttempt marks the given job Running and counts the attempt. A job that is already Running can be started
+	// again, since that means its previous attempt died without finishing.
+	// Returns ErrPublishJobNotFound if there is no such job and ErrPublishJobFinished if it has already succeeded or failed.
+	StartPublishJobAttempt(ctx context.Context, jobID string) (PublishJob, error)
+	// UpdatePublishJobStep records the step that the given job is on.
+	UpdatePublishJobStep(ctx context.Context, jobID string, step publishing.JobStep) error
+	// FinishPublishJobAttempt records the outcome of the current attempt of the given job. status is Queued if the job will be retried.
+	FinishPublishJobAttempt(ctx context.Context, jobID string, status publishing.JobStatus, result PublishJobResult) error
+	// GetCollectionMembers returns the users with a role on the given collection, ordered by user id.
+	GetCollectionMembers(ctx context.Context, collectionID int64) ([]CollectionMember, error)
+	// PutCollectionMember sets the role of the user with the given node id on the given collection, adding the user as a member if necessary.
+	// Returns ErrUserNotFound if there is no such user and ErrLastOwner if the change would leave the collection without an owner.
+	// actorID is the user making the change.
+	PutCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string, memberRole role.Role) (CollectionMember, error)
+	// DeleteCollectionMember removes the user with the given node id from the given collection.
+	// Returns ErrCollectionMemberNotFound if the user is not a member and ErrLastOwner if the user is the only owner.
+	// actorID is the user making the change.
+	DeleteCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string) error
+	// TransferOwnership makes the member with the given node id an Owner of the given collection and demotes the given current owner to Manager.
+	// Returns ErrCollectionMemberNotFound if the new owner is not already a member and ErrNotOwner if currentOwnerID is not an owner.
+	TransferOwnership(ctx context.Context, collectionID int64, currentOwnerID int64, newOwnerNodeID string) error
+	// GetCollectionGrants returns the teams and organizations with a role on the given collection, ordered by grantee type and id.
+	GetCollectionGrants(ctx context.Context, collectionID int64) ([]CollectionGrant, error)
+	// PutCollectionGrant gives every member of the team or organization with the given node id the given role on the given collection.
+	// Returns ErrGranteeNotFound if there is no such team or organization.
+	// actorID is the user making the change.
+	PutCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType GranteeType, granteeNodeID string, grantRole role.Role) (CollectionGrant, error)
+	// DeleteCollectionGrant removes the role of the team or organization with the given node id on the given collection.
+	// Returns ErrCollectionGrantNotFound if the team or organization has no role on the collection.
+	// actorID is the user making the change.
+	DeleteCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType GranteeType, granteeNodeID string) error
+	// CreateSection adds a section after the existing sections of the given collection.
+	// Returns ErrSectionDOIsNotInCollection if any of the request's DOIs are not in the collection.
+	// actorID is the user making the change.
+	CreateSection(ctx context.Context, collectionID int64, actorID int64, request CreateSectionRequest) (Section, error)
+	// UpdateSection returns ErrSectionNotFound if the section is not in the given collection and
+	// ErrSectionDOIsNotInCollection if any of the update's DOIs are not in the collection.
+	// actorID is the user making the change.
+	UpdateSection(ctx context.Context, collectionID, actorID, sectionID int64, update UpdateSectionRequest) (Section, error)
+	// DeleteSection removes the given section from the given collection. The section's DOIs stay in the collection.
+	// Returns ErrSectionNotFound if the section is not in the collection. actorID is the user making the change.
+	DeleteSection(ctx context.Context, collectionID, actorID, sectionID int64) error
+	// GetCollectionEvents returns a paginated list of the events in the activity log of the given collection, most recent first.
+	GetCollectionEvents(ctx context.Context, collectionID int64, limit int, offset int) (GetCollectionEventsResponse, error)
+	// CreateSnapshot saves the current name, description, license, tags, and ordered DOIs of the given collection under the given name.
+	// userID is the user taking the snapshot.
+	CreateSnapshot(ctx context.Context, collectionID int64, userID int64, name string) (Snapshot, error)
+	// GetSnapshots returns a paginated list of the snapshots of the given collection, most recent first.
+	GetSnapshots(ctx context.Context, collectionID int64, limit int, offset int) (GetSnapshotsResponse, error)
+	// GetSnapshot returns ErrSnapshotNotFound if the snapshot is not in the given collection.
+	GetSnapshot(ctx context.Context, collectionID, snapshotID int64) (Snapshot, error)
 }
 
+// minOrganizationPermission is the lowest organization permission a user needs for
+// a role granted to the organization to apply to them. Excludes organization guests.
+const minOrganizationPermission = pgdb.Read
+
+// effectiveUserRoleSQL selects one row (collection_id, permission_bit, role) per collection on which
+// the user @user_id has at least @min_perm. A user may hold a role on a collection directly, through a team, or through an
+// organization, and the highest of these wins.
+const effectiveUserRoleSQL = `SELECT DISTINCT ON (g.collection_id) g.collection_id, g.permission_bit, g.role
+	FROM (
+		SELECT cu.collection_id, cu.permission_bit, cu.role
+		FROM collections.collection_user cu
+		WHERE cu.user_id = @user_id
+		UNION ALL
+		SELECT ct.collection_id, ct.permission_bit, ct.role
+		FROM collections.collection_team ct
+			JOIN pennsieve.team_user tu ON ct.team_id = tu.team_id
+		WHERE tu.user_id = @user_id
+		UNION ALL
+		SELECT co.collection_id, co.permission_bit, co.role
+		FROM collections.collection_organization co
+			JOIN pennsieve.organization_user ou ON co.organization_id = ou.organization_id
+		WHERE ou.user_id = @user_id AND ou.permission_bit >= @min_org_perm
+	) g
+	WHERE g.permission_bit >= @min_perm
+	ORDER BY g.collection_id, g.permission_bit DESC`
+
 type PostgresStore struct {
-	db           postgres.DB
-	databaseName string
-	logger       *slog.Logger
+	db             postgres.DB
+	databaseName   string
+	publishTimeout time.Duration
+	logger         *slog.Logger
 }
 
 func NewPostgresStore(db postgres.DB, collectionsDatabaseName string, logger *slog.Logger) *PostgresStore {
 	return &PostgresStore{
-		db:           db,
-		databaseName: collectionsDatabaseName,
-		logger:       logger.With(slog.String("type", "collections.PostgresStore")),
+		db:             db,
+		databaseName:   collectionsDatabaseName,
+		publishTimeout: config.DefaultPublishTimeout,
+		logger:         logger.With(slog.String("type", "collections.PostgresStore")),
 	}
 }
 
+// WithPublishTimeout sets how long a publish can be InProgress before StartPublish treats it as stale
+// and lets a new publish replace it. The default is config.DefaultPublishTimeout.
+func (s *PostgresStore) WithPublishTimeout(publishTimeout time.Duration) *PostgresStore {
+	s.publishTimeout = publishTimeout
+	return s
+}
+
 func (s *PostgresStore) CreateCollection(ctx context.Context, request CreateCollectionRequest) (CreateCollectionResponse, error) {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return CreateCollectionResponse{}, fmt.Errorf("CreateCollection error connecting to database %s: %w", s.databaseName, err)
 	}
@@ -80,20 +207,38 @@ func (s *PostgresStore) CreateCollection(ctx context.Context, request CreateColl
 		for i, doi := range request.DOIs {
 			doiKey := fmt.Sprintf("doi_%d", i)
 			datasourceKey := fmt.Sprintf("datasource_%d", i)
-			values = append(values, fmt.Sprintf("(@%s, @%s)", doiKey, datasourceKey))
+			positionKey := fmt.Sprintf("position_%d", i)
+			labelKey := fmt.Sprintf("label_%d", i)
+			noteKey := fmt.Sprintf("note_%d", i)
+			values = append(values, fmt.Sprintf("(@%s, @%s, @%s::integer, @%s, @%s)", doiKey, datasourceKey, positionKey, labelKey, noteKey))
 			insertCollectionArgs[doiKey] = doi.Value
 			insertCollectionArgs[datasourceKey] = doi.Datasource
+			insertCollectionArgs[positionKey] = i
+			insertCollectionArgs[labelKey] = doi.Label
+			insertCollectionArgs[noteKey] = doi.Note
 		}
 		insertDOISQLFormat := `, t AS (
-                          INSERT INTO collections.dois (collection_id, doi, datasource)
-                          SELECT new_collection.id, doi, datasource
-                          FROM new_collection, (VALUES %s) AS new_dois(doi, datasource)
+                          INSERT INTO collections.dois (collection_id, doi, datasource, position, label, note)
+                          SELECT new_collection.id, doi, datasource, position, NULLIF(label, ''), NULLIF(note, '')
+                          FROM new_collection, (VALUES %s) AS new_dois(doi, datasource, position, label, note)
                        )`
 		insertDOISQL = fmt.Sprintf(insertDOISQLFormat, strings.Join(values, ", "))
 	}
 	insertCollectionSQL := fmt.Sprintf(insertCollectionSQLFormat, insertDOISQL)
 	var collectionID int64
-	if err := conn.QueryRow(ctx, insertCollectionSQL, insertCollectionArgs).Scan(&collectionID); err != nil {
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, insertCollectionSQL, insertCollectionArgs).Scan(&collectionID); err != nil {
+			return err
+		}
+		diff := EventDiff{
+			"name":        Change{New: request.Name},
+			"description": Change{New: request.Description},
+			"license":     Change{New: request.License},
+			"tags":        Change{New: request.Tags},
+			"dois":        DOIs(request.DOIs).Strings(),
+		}
+		return insertEvents(ctx, tx, collectionID, &request.UserID, newEvent(CreatedEvent, diff))
+	}); err != nil {
 		return CreateCollectionResponse{}, fmt.Errorf("error inserting new collection %s: %w", request.Name, err)
 	}
 	s.logger.Debug("inserted new collection",
@@ -105,7 +250,7 @@ func (s *PostgresStore) CreateCollection(ctx context.Context, request CreateColl
 	}, nil
 }
 
-func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit int, offset int) (GetCollectionsResponse, error) {
+func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit int, offset int, query GetCollectionsQuery) (GetCollectionsResponse, error) {
 	if limit < 0 {
 		return GetCollectionsResponse{}, fmt.Errorf("limit cannot be negative: %d", limit)
 	}
@@ -113,22 +258,56 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 		return GetCollectionsResponse{}, fmt.Errorf("offset cannot be negative: %d", offset)
 
 	}
+	sortBy, sortDirection := query.EffectiveSort()
+	sortKey, err := sortKeySQL(sortBy)
+	if err != nil {
+		return GetCollectionsResponse{}, fmt.Errorf("GetCollections: %w", err)
+	}
+	var comparison, direction string
+	switch sortDirection {
+	case Ascending:
+		comparison, direction = ">", "asc"
+	case Descending:
+		comparison, direction = "<", "desc"
+	default:
+		return GetCollectionsResponse{}, fmt.Errorf("GetCollections: unknown sort direction: %q", sortDirection)
+	}
 	getCollectionsArgs := pgx.NamedArgs{
-		"user_id":  userID,
-		"limit":    limit,
-		"offset":   offset,
-		"min_perm": pgdb.Guest,
+		"user_id":      userID,
+		"limit":        limit,
+		"offset":       offset,
+		"min_perm":     pgdb.Guest,
+		"min_org_perm": minOrganizationPermission,
+	}
+	fromSQL := `FROM collections.collections c
+         			JOIN (` + effectiveUserRoleSQL + `) u ON c.id = u.collection_id
+				    LEFT JOIN collections.publish_status s ON c.id = s.collection_id` +
+		query.whereSQL(getCollectionsArgs)
+
+	var cursorCondition string
+	if after := query.After; after != nil {
+		if after.SortBy != sortBy || after.SortDirection != sortDirection {
+			return GetCollectionsResponse{}, fmt.Errorf("GetCollections: cursor sort %s %s does not match query sort %s %s",
+				after.SortBy, after.SortDirection, sortBy, sortDirection)
+		}
+		if offset != 0 {
+			return GetCollectionsResponse{}, fmt.Errorf("GetCollections: offset must be zero with a cursor: %d", offset)
+		}
+		getCollectionsArgs["cursor_key"] = after.Key
+		getCollectionsArgs["cursor_id"] = after.ID
+		cursorCondition = fmt.Sprintf("\n\t\t\tWHERE (x.sort_key, x.id) %s (@cursor_key, @cursor_id)", comparison)
 	}
-	// using ORDER BY c.id asc as a proxy for getting in order of creation, oldest first
-	getCollectionsSQL := `SELECT c.id, c.name, c.description, c.node_id, c.license, c.tags, u.role, s.type, s.status, count(*) OVER () AS total_count
-			FROM collections.collections c
-         			JOIN collections.collection_user u ON c.id = u.collection_id
-				    LEFT JOIN collections.publish_status s ON c.id = s.collection_id
-			WHERE u.user_id = @user_id AND u.permission_bit >= @min_perm
-			ORDER BY c.id asc
-			LIMIT @limit OFFSET @offset`
 
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	// total_count is computed before the cursor condition is applied so that it counts every collection matching the filters.
+	// One extra row is requested to find out if there is a next page.
+	getCollectionsSQL := `SELECT x.id, x.name, x.description, x.node_id, x.license, x.tags, x.role, x.type, x.status, x.started_at, x.total_count, x.sort_key
+			FROM (SELECT c.id, c.name, c.description, c.node_id, c.license, c.tags, u.role, s.type, s.status, s.started_at,
+			             count(*) OVER () AS total_count, ` + sortKey + ` AS sort_key
+			      ` + fromSQL + `) x` + cursorCondition + `
+			ORDER BY x.sort_key ` + direction + `, x.id ` + direction + `
+			LIMIT @limit + 1 OFFSET @offset`
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return GetCollectionsResponse{}, fmt.Errorf("GetCollections error connecting to database %s: %w", s.databaseName, err)
 	}
@@ -141,6 +320,7 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 
 	// limit may be zero
 	collectionIDs := make([]int64, 0, limit+1)
+	sortKeys := make([]any, 0, limit+1)
 	collections, err := pgx.CollectRows(collectionUserJoinRows, func(row pgx.CollectableRow) (CollectionSummary, error) {
 		var id int64
 		var name, description, nodeID string
@@ -149,8 +329,10 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 		var role PgxRole
 		var pubTypeOpt *publishing.Type
 		var pubStatusOpt *publishing.Status
+		var pubStartedAtOpt *time.Time
 		var totalCount int
-		err := row.Scan(&id, &name, &description, &nodeID, &license, &tags, &role, &pubTypeOpt, &pubStatusOpt, &totalCount)
+		var sortKeyValue any
+		err := row.Scan(&id, &name, &description, &nodeID, &license, &tags, &role, &pubTypeOpt, &pubStatusOpt, &pubStartedAtOpt, &totalCount, &sortKeyValue)
 		if err != nil {
 			return CollectionSummary{}, err
 		}
@@ -158,6 +340,7 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 		response.TotalCount = totalCount
 
 		collectionIDs = append(collectionIDs, id)
+		sortKeys = append(sortKeys, sortKeyValue)
 
 		return CollectionSummary{
 			CollectionBase: CollectionBase{
@@ -168,7 +351,7 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 				License:     license,
 				Tags:        tags,
 				UserRole:    role.AsRole(),
-				Publication: newPublication(pubStatusOpt, pubTypeOpt),
+				Publication: newPublication(pubStatusOpt, pubTypeOpt, pubStartedAtOpt),
 			}}, nil
 
 	})
@@ -176,14 +359,25 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 		return GetCollectionsResponse{}, fmt.Errorf("GetCollections: error querying for collections: %w", err)
 	}
 
-	// We may have gotten no collections because limit == 0 or offset it too large,
-	// but we still want to return a correct total count, so recount with no limit or offset.
+	// drop the extra row, if any, and use the last collection of this page as the cursor for the next.
+	if len(collections) > limit {
+		collections, collectionIDs = collections[:limit], collectionIDs[:limit]
+		if limit > 0 {
+			last := collections[limit-1]
+			response.NextCursor = &Cursor{
+				SortBy:        sortBy,
+				SortDirection: sortDirection,
+				Key:           sortKeys[limit-1],
+				ID:            last.ID,
+			}
+		}
+	}
+
+	// We may have gotten no collections because limit == 0 or offset or cursor is too large,
+	// but we still want to return a correct total count, so recount with no limit, offset, or cursor.
 	if len(collections) == 0 {
 		var totalCount int
-		if err := conn.QueryRow(ctx, `SELECT count(*)
-	                                FROM collections.collections c
-	         			            	JOIN collections.collection_user u ON c.id = u.collection_id
-				                    WHERE u.user_id = @user_id AND u.permission_bit >= @min_perm`, getCollectionsArgs).Scan(&totalCount); err != nil {
+		if err := conn.QueryRow(ctx, `SELECT count(*) `+fromSQL, getCollectionsArgs).Scan(&totalCount); err != nil {
 			return GetCollectionsResponse{}, fmt.Errorf("GetCollections: error counting total collections: %w", err)
 		}
 		response.TotalCount = totalCount
@@ -203,7 +397,7 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 	               	SELECT doi, count(*) OVER () AS total_count
 			        FROM collections.dois
 				    WHERE collection_id = c.id
-	                ORDER BY id asc
+	                ORDER BY position asc, id asc
 				    LIMIT @limit
 	               ) d ON true
 	               WHERE c.id = ANY(@collection_ids)
@@ -227,32 +421,87 @@ func (s *PostgresStore) GetCollections(ctx context.Context, userID int64, limit
 	return response, nil
 }
 
-func newPublication(pubStatusOpt *publishing.Status, pubTypeOpt *publishing.Type) *Publication {
+// whereSQL returns a WHERE clause for the filters in q and adds any needed arguments to args. Collections in the trash are always excluded.
+// Expects collections.collections to be aliased as c, the user's role as u, and collections.publish_status as s.
+func (q GetCollectionsQuery) whereSQL(args pgx.NamedArgs) string {
+	conditions := []string{"c.deleted_at IS NULL"}
+	if len(q.Text) > 0 {
+		args["text_pattern"] = "%" + likeEscaper.Replace(q.Text) + "%"
+		conditions = append(conditions, "(c.name ILIKE @text_pattern OR c.description ILIKE @text_pattern)")
+	}
+	if len(q.Tag) > 0 {
+		args["tag"] = q.Tag
+		conditions = append(conditions, "@tag = ANY(c.tags)")
+	}
+	if len(q.License) > 0 {
+		args["license"] = q.License
+		conditions = append(conditions, "c.license = @license")
+	}
+	if q.PublicationStatus != nil {
+		args["publication_status"] = *q.PublicationStatus
+		if *q.PublicationStatus == publishing.DraftStatus {
+			conditions = append(conditions, "(s.status IS NULL OR s.status = @publication_status)")
+		} else {
+			conditions = append(conditions, "s.status = @publication_status")
+		}
+	}
+	if q.Role != nil {
+		args["role"] = strings.ToLower(q.Role.String())
+		conditions = append(conditions, "lower(u.role) = @role")
+	}
+	return "\n\t\t\tWHERE " + strings.Join(conditions, " AND ")
+}
+
+// sortKeySQL returns the expression by which collections are ordered for the given sort field.
+// Expects collections.collections to be aliased as c.
+func sortKeySQL(sortBy SortField) (string, error) {
+	switch sortBy {
+	case SortByID:
+		return "c.id", nil
+	case SortByName:
+		return "lower(c.name)", nil
+	case SortByCreatedAt:
+		return "c.created_at", nil
+	case SortByUpdatedAt:
+		return "c.updated_at", nil
+	case SortBySize:
+		return "(SELECT count(*) FROM collections.dois d WHERE d.collection_id = c.id)", nil
+	default:
+		return "", fmt.Errorf("unknown sort field: %q", sortBy)
+	}
+}
+
+// likeEscaper escapes the LIKE wildcards in user supplied search text.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func newPublication(pubStatusOpt *publishing.Status, pubTypeOpt *publishing.Type, startedAtOpt *time.Time) *Publication {
 	var publication *Publication
 	if pubStatusOpt != nil && pubTypeOpt != nil {
 		publication = &Publication{
 			Status: *pubStatusOpt,
 			Type:   *pubTypeOpt,
 		}
+		if startedAtOpt != nil {
+			publication.StartedAt = *startedAtOpt
+		}
 	}
 	return publication
 }
 
 // getCollectionByIDColumn returns the error ErrCollectionNotFound if no collection with the given idValue exists for the given user id.
 // idColumn should be either "id" or "node_id"
-func getCollectionByIDColumn(ctx context.Context, conn *pgx.Conn, userID int64, idColumn string, idValue any) (GetCollectionResponse, error) {
-	args := pgx.NamedArgs{"user_id": userID, idColumn: idValue, "min_perm": pgdb.Guest}
+func getCollectionByIDColumn(ctx context.Context, conn postgres.Querier, userID int64, idColumn string, idValue any) (GetCollectionResponse, error) {
+	args := pgx.NamedArgs{"user_id": userID, idColumn: idValue, "min_perm": pgdb.Guest, "min_org_perm": minOrganizationPermission}
 
 	idCondition := fmt.Sprintf("c.%s = @%s", idColumn, idColumn)
 
-	sql := fmt.Sprintf(`SELECT c.id, c.node_id, c.name, c.description, c.license, c.tags, u.role, d.doi, d.datasource, s.type, s.status
+	sql := fmt.Sprintf(`SELECT c.id, c.node_id, c.name, c.description, c.license, c.tags, c.version, u.role, d.doi, d.datasource, d.label, d.note, s.type, s.status, s.started_at
 			FROM collections.collections c
-         		JOIN collections.collection_user u ON c.id = u.collection_id
+         		JOIN (%s) u ON c.id = u.collection_id
          		LEFT JOIN collections.dois d ON c.id = d.collection_id
 			    LEFT JOIN collections.publish_status s ON c.id = s.collection_id
-			WHERE u.user_id = @user_id AND u.permission_bit >= @min_perm
-  			  AND %s
-			ORDER BY d.id asc`, idCondition)
+			WHERE %s AND c.deleted_at IS NULL
+			ORDER BY d.position asc, d.id asc`, effectiveUserRoleSQL, idCondition)
 
 	rows, _ := conn.Query(ctx, sql, args)
 
@@ -262,12 +511,15 @@ func getCollectionByIDColumn(ctx context.Context, conn *pgx.Conn, userID int64,
 	var name, description string
 	var license *string
 	var tags []string
+	var version int64
 	var pgxRole PgxRole
 	var doiOpt *string
 	var datasourceOpt *datasource.DOIDatasource
+	var labelOpt, noteOpt *string
 	var publishTypeOpt *publishing.Type
 	var publishStatusOpt *publishing.Status
-	_, err := pgx.ForEachRow(rows, []any{&id, &nodeID, &name, &description, &license, &tags, &pgxRole, &doiOpt, &datasourceOpt, &publishTypeOpt, &publishStatusOpt}, func() error {
+	var publishStartedAtOpt *time.Time
+	_, err := pgx.ForEachRow(rows, []any{&id, &nodeID, &name, &description, &license, &tags, &version, &pgxRole, &doiOpt, &datasourceOpt, &labelOpt, &noteOpt, &publishTypeOpt, &publishStatusOpt, &publishStartedAtOpt}, func() error {
 		if response == nil {
 			response = &GetCollectionResponse{
 				CollectionBase: CollectionBase{
@@ -278,7 +530,8 @@ func getCollectionByIDColumn(ctx context.Context, conn *pgx.Conn, userID int64,
 					License:     license,
 					Tags:        tags,
 					UserRole:    pgxRole.AsRole(),
-					Publication: newPublication(publishStatusOpt, publishTypeOpt),
+					Publication: newPublication(publishStatusOpt, publishTypeOpt, publishStartedAtOpt),
+					Version:     version,
 				},
 			}
 		}
@@ -286,6 +539,8 @@ func getCollectionByIDColumn(ctx context.Context, conn *pgx.Conn, userID int64,
 			response.DOIs = append(response.DOIs, DOI{
 				Value:      *doiOpt,
 				Datasource: *datasourceOpt,
+				Label:      util.SafeDeref(labelOpt),
+				Note:       util.SafeDeref(noteOpt),
 			})
 		}
 		return nil
@@ -298,20 +553,23 @@ func getCollectionByIDColumn(ctx context.Context, conn *pgx.Conn, userID int64,
 	}
 
 	response.Size = len(response.DOIs)
+	if response.Sections, err = getSections(ctx, conn, response.ID); err != nil {
+		return GetCollectionResponse{}, err
+	}
 	return *response, nil
 }
 
-func getCollectionByNodeID(ctx context.Context, conn *pgx.Conn, userID int64, nodeID string) (GetCollectionResponse, error) {
+func getCollectionByNodeID(ctx context.Context, conn postgres.Querier, userID int64, nodeID string) (GetCollectionResponse, error) {
 	return getCollectionByIDColumn(ctx, conn, userID, "node_id", nodeID)
 }
 
-func getCollectionByID(ctx context.Context, conn *pgx.Conn, userID int64, collectionID int64) (GetCollectionResponse, error) {
+func getCollectionByID(ctx context.Context, conn postgres.Querier, userID int64, collectionID int64) (GetCollectionResponse, error) {
 	return getCollectionByIDColumn(ctx, conn, userID, "id", collectionID)
 }
 
 // GetCollection returns the error ErrCollectionNotFound if no collection with the given node id exists for the given user id.
 func (s *PostgresStore) GetCollection(ctx context.Context, userID int64, nodeID string) (GetCollectionResponse, error) {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return GetCollectionResponse{}, fmt.Errorf("GetCollection error connecting to database %s: %w", s.databaseName, err)
 	}
@@ -319,25 +577,196 @@ func (s *PostgresStore) GetCollection(ctx context.Context, userID int64, nodeID
 	return getCollectionByNodeID(ctx, conn, userID, nodeID)
 }
 
-func (s *PostgresStore) DeleteCollection(ctx context.Context, collectionID int64) error {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+func (s *PostgresStore) DeleteCollection(ctx context.Context, collectionID int64, actorID int64, expectedVersion *int64) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return fmt.Errorf("DeleteCollection error connecting to database %s: %w", s.databaseName, err)
 	}
 	defer s.closeConn(ctx, conn)
 
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if expectedVersion != nil {
+			if err := lockCollectionVersion(ctx, tx, collectionID, *expectedVersion); err != nil {
+				return err
+			}
+		}
+		commandTag, err := tx.Exec(
+			ctx,
+			"UPDATE collections.collections SET deleted_at = @deleted_at WHERE id = @collection_id AND deleted_at IS NULL",
+			pgx.NamedArgs{"collection_id": collectionID, "deleted_at": time.Now().UTC()},
+		)
+		if err != nil {
+			return fmt.Errorf("error deleting collection %d: %w", collectionID, err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return ErrCollectionNotFound
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID, newEvent(TrashedEvent, EventDiff{}))
+	}); err != nil {
+		if errors.Is(err, ErrCollectionNotFound) || errors.Is(err, ErrCollectionVersionMismatch) {
+			return err
+		}
+		return fmt.Errorf("DeleteCollection error deleting collection %d: %w", collectionID, err)
+	}
+	return nil
+}
+
+// deletedCollectionsSQL selects the collections in the trash on which the user @user_id has at least @min_perm.
+// Callers can add further conditions and an ORDER BY.
+const deletedCollectionsSQL = `SELECT c.id, c.node_id, c.name, c.description, c.license, c.tags, u.role, c.deleted_at,
+			(SELECT count(*) FROM collections.dois d WHERE d.collection_id = c.id) AS size,
+			count(*) OVER () AS total_count
+		FROM collections.collections c
+			JOIN (` + effectiveUserRoleSQL + `) u ON c.id = u.collection_id
+		WHERE c.deleted_at IS NOT NULL`
+
+func (s *PostgresStore) GetDeletedCollections(ctx context.Context, userID int64, minRole role.Role, limit int, offset int) (GetDeletedCollectionsResponse, error) {
+	if limit < 0 {
+		return GetDeletedCollectionsResponse{}, fmt.Errorf("limit cannot be negative: %d", limit)
+	}
+	if offset < 0 {
+		return GetDeletedCollectionsResponse{}, fmt.Errorf("offset cannot be negative: %d", offset)
+	}
+	minPermission := pgdb.FromRole(minRole.String())
+	if minPermission == pgdb.NoPermission {
+		minPermission = pgdb.Guest
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return GetDeletedCollectionsResponse{}, fmt.Errorf("GetDeletedCollections error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	args := pgx.NamedArgs{
+		"user_id":      userID,
+		"limit":        limit,
+		"offset":       offset,
+		"min_perm":     minPermission,
+		"min_org_perm": minOrganizationPermission,
+	}
+	query := deletedCollectionsSQL + `
+		ORDER BY c.deleted_at desc, c.id desc
+		LIMIT @limit OFFSET @offset`
+
+	response := GetDeletedCollectionsResponse{Limit: limit, Offset: offset}
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	response.Collections, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (DeletedCollection, error) {
+		collection, totalCount, err := scanDeletedCollection(row)
+		//redundant after the first
+		response.TotalCount = totalCount
+		return collection, err
+	})
+	if err != nil {
+		return GetDeletedCollectionsResponse{}, fmt.Errorf("GetDeletedCollections: error querying for collections: %w", err)
+	}
+
+	// as in GetCollections, recount if limit or offset left us with nothing
+	if len(response.Collections) == 0 {
+		if err := conn.QueryRow(ctx, `SELECT count(*) FROM (`+deletedCollectionsSQL+`) x`, args).Scan(&response.TotalCount); err != nil {
+			return GetDeletedCollectionsResponse{}, fmt.Errorf("GetDeletedCollections: error counting total collections: %w", err)
+		}
+	}
+	return response, nil
+}
+
+func (s *PostgresStore) GetDeletedCollection(ctx context.Context, userID int64, nodeID string) (DeletedCollection, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return DeletedCollection{}, fmt.Errorf("GetDeletedCollection error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	args := pgx.NamedArgs{
+		"user_id":      userID,
+		"node_id":      nodeID,
+		"min_perm":     pgdb.Guest,
+		"min_org_perm": minOrganizationPermission,
+	}
+	rows, _ := conn.Query(ctx, deletedCollectionsSQL+` AND c.node_id = @node_id`, args)
+	collection, err := pgx.CollectExactlyOneRow(rows, func(row pgx.CollectableRow) (DeletedCollection, error) {
+		collection, _, err := scanDeletedCollection(row)
+		return collection, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DeletedCollection{}, ErrCollectionNotFound
+		}
+		return DeletedCollection{}, fmt.Errorf("GetDeletedCollection error querying for collection %s: %w", nodeID, err)
+	}
+	return collection, nil
+}
+
+// scanDeletedCollection scans a row selected by deletedCollectionsSQL.
+func scanDeletedCollection(row pgx.CollectableRow) (DeletedCollection, int, error) {
+	var collection DeletedCollection
+	var pgxRole PgxRole
+	var totalCount int
+	if err := row.Scan(
+		&collection.ID,
+		&collection.NodeID,
+		&collection.Name,
+		&collection.Description,
+		&collection.License,
+		&collection.Tags,
+		&pgxRole,
+		&collection.DeletedAt,
+		&collection.Size,
+		&totalCount,
+	); err != nil {
+		return DeletedCollection{}, 0, err
+	}
+	collection.UserRole = pgxRole.AsRole()
+	return collection, totalCount, nil
+}
+
+func (s *PostgresStore) RestoreCollection(ctx context.Context, collectionID int64, actorID int64) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("RestoreCollection error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		commandTag, err := tx.Exec(
+			ctx,
+			"UPDATE collections.collections SET deleted_at = NULL WHERE id = @collection_id AND deleted_at IS NOT NULL",
+			pgx.NamedArgs{"collection_id": collectionID},
+		)
+		if err != nil {
+			return fmt.Errorf("error restoring collection %d: %w", collectionID, err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return ErrCollectionNotFound
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID, newEvent(RestoredEvent, EventDiff{}))
+	}); err != nil {
+		if errors.Is(err, ErrCollectionNotFound) {
+			return err
+		}
+		return fmt.Errorf("RestoreCollection error restoring collection %d: %w", collectionID, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) PurgeDeletedCollections(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return 0, fmt.Errorf("PurgeDeletedCollections error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	// DOIs, members, grants, and publish status cascade
 	commandTag, err := conn.Exec(
 		ctx,
-		"DELETE FROM collections.collections WHERE id = @collection_id",
-		pgx.NamedArgs{"collection_id": collectionID},
+		"DELETE FROM collections.collections WHERE deleted_at < @deleted_before",
+		pgx.NamedArgs{"deleted_before": deletedBefore.UTC()},
 	)
 	if err != nil {
-		return fmt.Errorf("DeleteCollection error deleting collection %d: %w", collectionID, err)
-	}
-	if commandTag.RowsAffected() == 0 {
-		return ErrCollectionNotFound
+		return 0, fmt.Errorf("PurgeDeletedCollections error deleting collections deleted before %s: %w", deletedBefore, err)
 	}
-	return nil
+	return commandTag.RowsAffected(), nil
 }
 
 func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collectionID int64, update UpdateCollectionRequest) (GetCollectionResponse, error) {
@@ -362,13 +791,6 @@ func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collection
 		setExpressions = append(setExpressions, "tags = @tags")
 		collectionUpdateArgs["tags"] = update.Tags
 	}
-	if len(setExpressions) > 0 {
-		collectionUpdateArgs["collection_id"] = collectionID
-		collectionUpdateSQL = fmt.Sprintf(`UPDATE collections.collections
-                               SET %s
-                               WHERE id = @collection_id`,
-			strings.Join(setExpressions, ","))
-	}
 
 	// Create SQL for DOI deletes if necessary
 	var doiDeleteSQL string
@@ -381,7 +803,7 @@ func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collection
 			doiDeleteArgs[doiVar] = doi
 		}
 		doiDeleteArgs["collection_id"] = collectionID
-		doiDeleteSQL = fmt.Sprintf(`DELETE FROM collections.dois WHERE %s`, strings.Join(wheres, " OR "))
+		doiDeleteSQL = fmt.Sprintf(`DELETE FROM collections.dois WHERE %s RETURNING doi`, strings.Join(wheres, " OR "))
 	}
 
 	// Create SQL for DOI adds if necessary
@@ -392,15 +814,59 @@ func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collection
 		for i, doi := range update.DOIs.Add {
 			doiVar := fmt.Sprintf("doi_%d", i)
 			datasourceVar := fmt.Sprintf("datasource_%d", i)
-			values = append(values, fmt.Sprintf("(@collection_id, @%s, @%s)", doiVar, datasourceVar))
+			offsetVar := fmt.Sprintf("offset_%d", i)
+			labelVar := fmt.Sprintf("label_%d", i)
+			noteVar := fmt.Sprintf("note_%d", i)
+			values = append(values, fmt.Sprintf("(@%s, @%s, @%s::integer, @%s, @%s)", doiVar, datasourceVar, offsetVar, labelVar, noteVar))
 			doiAddArgs[doiVar] = doi.Value
 			doiAddArgs[datasourceVar] = doi.Datasource
+			doiAddArgs[offsetVar] = i
+			doiAddArgs[labelVar] = doi.Label
+			doiAddArgs[noteVar] = doi.Note
 		}
 		doiAddArgs["collection_id"] = collectionID
-		doiAddSQL = fmt.Sprintf(`INSERT INTO collections.dois (collection_id, doi, datasource) VALUES %s ON CONFLICT (collection_id, doi) DO NOTHING`, strings.Join(values, ", "))
+		// New DOIs go after any existing DOIs, in the order given
+		doiAddSQL = fmt.Sprintf(`INSERT INTO collections.dois (collection_id, doi, datasource, position, label, note)
+                                 SELECT @collection_id, new_dois.doi, new_dois.datasource, next_position.position + new_dois.position_offset,
+                                        NULLIF(new_dois.label, ''), NULLIF(new_dois.note, '')
+                                 FROM (VALUES %s) AS new_dois(doi, datasource, position_offset, label, note),
+                                      (SELECT COALESCE(max(position) + 1, 0) AS position FROM collections.dois WHERE collection_id = @collection_id) AS next_position
+                                 ON CONFLICT (collection_id, doi) DO NOTHING
+                                 RETURNING doi`, strings.Join(values, ", "))
+	}
+
+	// Create SQL for DOI annotations if necessary. One statement per DOI since each may set different columns.
+	var doiAnnotateSQLs []string
+	var doiAnnotateArgs []pgx.NamedArgs
+	for _, annotation := range update.DOIs.Annotate {
+		args := pgx.NamedArgs{"collection_id": collectionID, "doi": annotation.DOI}
+		var annotationSetExpressions []string
+		if annotation.Label != nil {
+			annotationSetExpressions = append(annotationSetExpressions, "label = NULLIF(@label, '')")
+			args["label"] = *annotation.Label
+		}
+		if annotation.Note != nil {
+			annotationSetExpressions = append(annotationSetExpressions, "note = NULLIF(@note, '')")
+			args["note"] = *annotation.Note
+		}
+		if len(annotationSetExpressions) > 0 {
+			doiAnnotateSQLs = append(doiAnnotateSQLs, fmt.Sprintf(`UPDATE collections.dois SET %s WHERE collection_id = @collection_id AND doi = @doi`,
+				strings.Join(annotationSetExpressions, ", ")))
+			doiAnnotateArgs = append(doiAnnotateArgs, args)
+		}
+	}
+
+	// Any change, including to DOIs only, gets a new version
+	if len(setExpressions) > 0 || len(doiDeleteSQL) > 0 || len(doiAddSQL) > 0 || len(doiAnnotateSQLs) > 0 || update.DOIs.Order != nil {
+		setExpressions = append(setExpressions, "version = version + 1")
+		collectionUpdateArgs["collection_id"] = collectionID
+		collectionUpdateSQL = fmt.Sprintf(`UPDATE collections.collections
+                               SET %s
+                               WHERE id = @collection_id AND deleted_at IS NULL`,
+			strings.Join(setExpressions, ","))
 	}
 
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return GetCollectionResponse{}, fmt.Errorf("UpdateCollection error connecting to database %s: %w", s.databaseName, err)
 	}
@@ -408,30 +874,71 @@ func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collection
 
 	// Run any updates in a transaction
 	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
-		if len(collectionUpdateSQL) > 0 {
-			commandTag, err := tx.Exec(ctx, collectionUpdateSQL, collectionUpdateArgs)
-			if err != nil {
-				return fmt.Errorf("error updating collection %d name/description: %w", collectionID, err)
-			}
-			if commandTag.RowsAffected() == 0 {
-				return ErrCollectionNotFound
-			}
+		if len(collectionUpdateSQL) == 0 && update.ExpectedVersion == nil {
+			return nil
+		}
+		current, err := selectCollectionForUpdate(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+		if update.ExpectedVersion != nil && current.Version != *update.ExpectedVersion {
+			return ErrCollectionVersionMismatch
+		}
+		if len(collectionUpdateSQL) == 0 {
+			return nil
+		}
+		events := current.changeEvents(update)
+
+		commandTag, err := tx.Exec(ctx, collectionUpdateSQL, collectionUpdateArgs)
+		if err != nil {
+			return fmt.Errorf("error updating collection %d: %w", collectionID, err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return ErrCollectionNotFound
 		}
-		// We can't really detect CollectionNotFound with the DOI queries, but we will catch it below when looking up
-		// the updated collection to return. Plus the caller should have already tried to look up the collection for
-		// authz purposes.
 		if len(doiDeleteSQL) > 0 {
-			if _, err := tx.Exec(ctx, doiDeleteSQL, doiDeleteArgs); err != nil {
+			rows, _ := tx.Query(ctx, doiDeleteSQL, doiDeleteArgs)
+			removed, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
 				return fmt.Errorf("error deleting collection %d DOIs: %w", collectionID, err)
 			}
+			if len(removed) > 0 {
+				events = append(events, newEvent(DOIsRemovedEvent, EventDiff{"dois": removed}))
+			}
 		}
 
 		if len(doiAddSQL) > 0 {
-			if _, err := tx.Exec(ctx, doiAddSQL, doiAddArgs); err != nil {
+			rows, _ := tx.Query(ctx, doiAddSQL, doiAddArgs)
+			added, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
 				return fmt.Errorf("error adding collection %d DOIs: %w", collectionID, err)
 			}
+			if len(added) > 0 {
+				events = append(events, newEvent(DOIsAddedEvent, EventDiff{"dois": added}))
+			}
 		}
-		return nil
+
+		if update.DOIs.Order != nil {
+			if err := setDOIOrder(ctx, tx, collectionID, update.DOIs.Order); err != nil {
+				return err
+			}
+		}
+
+		if len(update.DOIs.Annotate) > 0 {
+			annotateEvent, err := doiAnnotationEvent(ctx, tx, collectionID, update.DOIs.Annotate)
+			if err != nil {
+				return err
+			}
+			if annotateEvent != nil {
+				events = append(events, *annotateEvent)
+			}
+		}
+		for i, doiAnnotateSQL := range doiAnnotateSQLs {
+			if _, err := tx.Exec(ctx, doiAnnotateSQL, doiAnnotateArgs[i]); err != nil {
+				return fmt.Errorf("error annotating collection %d DOIs: %w", collectionID, err)
+			}
+		}
+		return insertEvents(ctx, tx, collectionID, &userID, events...)
 	}); err != nil {
 		return GetCollectionResponse{}, fmt.Errorf("UpdateCollection error updating collection %d: %w", collectionID, err)
 	}
@@ -443,8 +950,54 @@ func (s *PostgresStore) UpdateCollection(ctx context.Context, userID, collection
 	return updatedCollection, nil
 }
 
-func (s *PostgresStore) StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type) error {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+func (s *PostgresStore) ReorderDOIs(ctx context.Context, userID, collectionID int64, dois []string) (GetCollectionResponse, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return GetCollectionResponse{}, fmt.Errorf("ReorderDOIs error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// the lock keeps concurrent updates from adding or removing DOIs between reading the current order and
+		// the update and count below
+		if err := lockCollection(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		rows, _ := tx.Query(ctx,
+			`SELECT doi FROM collections.dois WHERE collection_id = @collection_id ORDER BY position, id`,
+			pgx.NamedArgs{"collection_id": collectionID})
+		previousOrder, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("error getting current order of collection %d DOIs: %w", collectionID, err)
+		}
+		// the same order is a no-op, so the version is left alone to keep clients' ETags valid
+		if slices.Equal(previousOrder, dois) {
+			return nil
+		}
+		if err := incrementVersion(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		if err := setDOIOrder(ctx, tx, collectionID, dois); err != nil {
+			return err
+		}
+		return insertEvents(ctx, tx, collectionID, &userID,
+			newEvent(DOIsReorderedEvent, EventDiff{"dois": Change{Old: previousOrder, New: dois}}))
+	}); err != nil {
+		if errors.Is(err, ErrCollectionNotFound) || errors.Is(err, ErrDOIOrderMismatch) {
+			return GetCollectionResponse{}, err
+		}
+		return GetCollectionResponse{}, fmt.Errorf("ReorderDOIs error updating collection %d: %w", collectionID, err)
+	}
+
+	updatedCollection, err := getCollectionByID(ctx, conn, userID, collectionID)
+	if err != nil {
+		return GetCollectionResponse{}, fmt.Errorf("ReorderDOIs error getting updated collection %d: %w", collectionID, err)
+	}
+	return updatedCollection, nil
+}
+
+func (s *PostgresStore) StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type, expectedVersion *int64) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return fmt.Errorf("StartPublish error connecting to database %s: %w", s.databaseName, err)
 	}
@@ -458,65 +1011,1439 @@ func (s *PostgresStore) StartPublish(ctx context.Context, collectionID int64, us
                     user_id = EXCLUDED.user_id,
                     started_at = EXCLUDED.started_at,
                     finished_at = NULL
-                WHERE collections.publish_status.status != @in_progress`
+                WHERE collections.publish_status.status != @in_progress
+                   OR collections.publish_status.started_at < @stale_before`
 
+	startedAt := time.Now().UTC()
 	args := pgx.NamedArgs{
 		"collection_id": collectionID,
 		"status":        publishing.InProgressStatus,
 		"type":          publishingType,
 		"user_id":       userID,
-		"started_at":    time.Now().UTC(),
+		"started_at":    startedAt,
 		"in_progress":   publishing.InProgressStatus,
+		"stale_before":  startedAt.Add(-s.publishTimeout),
 	}
 
-	tag, err := conn.Exec(ctx, query, args)
-	if err != nil {
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if expectedVersion != nil {
+			// holds the lock until the publish status is written, so the collection cannot change in between
+			if err := lockCollectionVersion(ctx, tx, collectionID, *expectedVersion); err != nil {
+				return err
+			}
+		}
+		tag, err := tx.Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == int64(0) {
+			return ErrPublishInProgress
+		}
+		return insertEvents(ctx, tx, collectionID, &userID,
+			newEvent(publishStartedEventType(publishingType), EventDiff{"type": publishingType}))
+	}); err != nil {
+		if errors.Is(err, ErrPublishInProgress) || errors.Is(err, ErrCollectionVersionMismatch) || errors.Is(err, ErrCollectionNotFound) {
+			return err
+		}
 		return fmt.Errorf("error starting publish of collection %d for user %d: %w",
 			collectionID,
 			userID,
 			err)
 	}
-	if tag.RowsAffected() == int64(0) {
-		return ErrPublishInProgress
-	}
 
 	return nil
 
 }
 
 func (s *PostgresStore) FinishPublish(ctx context.Context, collectionID int64, publishingStatus publishing.Status, strict bool) error {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
 		return fmt.Errorf("FinishPublish error connecting to database %s: %w", s.databaseName, err)
 	}
 	defer s.closeConn(ctx, conn)
 
-	query := `UPDATE collections.publish_status
+	found, err := finishPublish(ctx, conn, collectionID, publishingStatus, nil)
+	if err != nil {
+		return fmt.Errorf("error finishing publish of collection %d: %w",
+			collectionID,
+			err)
+	}
+	if strict && !found {
+		return errors.New("no publish status found for collection")
+	}
+
+	return nil
+
+}
+
+func (s *PostgresStore) GetStalePublishes(ctx context.Context, startedBefore time.Time) ([]StalePublish, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("GetStalePublishes error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	// Collections in the trash are included since a stale publish is just as stuck there
+	query := `SELECT ps.collection_id, c.node_id, ps.type, ps.user_id, ps.started_at
+              FROM collections.publish_status ps
+                JOIN collections.collections c ON ps.collection_id = c.id
+              WHERE ps.status = @in_progress AND ps.started_at < @started_before
+              ORDER BY ps.started_at, ps.collection_id`
+	args := pgx.NamedArgs{
+		"in_progress":    publishing.InProgressStatus,
+		"started_before": startedBefore,
+	}
+
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	stalePublishes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (StalePublish, error) {
+		var stale StalePublish
+		err := row.Scan(&stale.CollectionID, &stale.CollectionNodeID, &stale.Type, &stale.UserID, &stale.StartedAt)
+		return stale, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetStalePublishes error querying for publishes started before %s: %w", startedBefore, err)
+	}
+	return stalePublishes, nil
+}
+
+func (s *PostgresStore) FinishStalePublish(ctx context.Context, stale StalePublish, publishingStatus publishing.Status) (bool, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return false, fmt.Errorf("FinishStalePublish error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	found, err := finishPublish(ctx, conn, stale.CollectionID, publishingStatus, &stale.StartedAt)
+	if err != nil {
+		return false, fmt.Errorf("error finishing stale publish of collection %d: %w",
+			stale.CollectionID,
+			err)
+	}
+	return found, nil
+}
+
+// finishPublish sets the publish status of the given collection and records the change in its activity log.
+// If inProgressSince is not nil, the status is only changed if it is InProgress and started at *inProgressSince.
+// Returns false if no status was changed.
+func finishPublish(ctx context.Context, conn postgres.Querier, collectionID int64, publishingStatus publishing.Status, inProgressSince *time.Time) (bool, error) {
+	// The finish is recorded as done by the user who started the publish
+	query := `UPDATE collections.publish_status ps
               SET status = @status,
                   finished_at = @finished_at
-              WHERE collection_id = @collection_id`
+              FROM (SELECT collection_id, status FROM collections.publish_status WHERE collection_id = @collection_id FOR UPDATE) previous
+              WHERE ps.collection_id = previous.collection_id`
 
 	args := pgx.NamedArgs{
 		"collection_id": collectionID,
 		"status":        publishingStatus,
 		"finished_at":   time.Now().UTC(),
 	}
+	if inProgressSince != nil {
+		query += ` AND previous.status = @in_progress AND ps.started_at = @started_at`
+		args["in_progress"] = publishing.InProgressStatus
+		args["started_at"] = *inProgressSince
+	}
+	query += ` RETURNING previous.status, ps.type, ps.user_id`
 
-	tag, err := conn.Exec(ctx, query, args)
+	var found bool
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		var oldStatus publishing.Status
+		var publishingType publishing.Type
+		var userID *int64
+		if err := tx.QueryRow(ctx, query, args).Scan(&oldStatus, &publishingType, &userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		found = true
+		diff := EventDiff{"type": publishingType, "status": Change{Old: oldStatus, New: publishingStatus}}
+		return insertEvents(ctx, tx, collectionID, userID, newEvent(publishFinishedEventType(publishingType), diff))
+	}); err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// publishJobSQL selects the columns scanned by scanPublishJob. It expects the jobs to be named j, so callers can
+// select from collections.collection_publish_jobs j or from a CTE j that returns the updated rows.
+const publishJobSQL = `SELECT j.id, j.collection_id, c.node_id, j.user_id, u.node_id, j.status, j.step, j.attempts, j.error,
+                              j.published_dataset_id, j.published_version, j.created_at, j.updated_at
+                       FROM %s
+                         JOIN collections.collections c ON j.collection_id = c.id
+                         LEFT JOIN pennsieve.users u ON j.user_id = u.id`
+
+func scanPublishJob(row pgx.Row) (PublishJob, error) {
+	var job PublishJob
+	err := row.Scan(&job.ID,
+		&job.CollectionID,
+		&job.CollectionNodeID,
+		&job.UserID,
+		&job.UserNodeID,
+		&job.Status,
+		&job.Step,
+		&job.Attempts,
+		&job.Error,
+		&job.PublishedDatasetID,
+		&job.PublishedVersion,
+		&job.CreatedAt,
+		&job.UpdatedAt)
+	return job, err
+}
+
+func (s *PostgresStore) CreatePublishJob(ctx context.Context, collectionID int64, userID int64) (PublishJob, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
-		return fmt.Errorf("error finishing publish of collection %d: %w",
-			collectionID,
-			err)
+		return PublishJob{}, fmt.Errorf("CreatePublishJob error connecting to database %s: %w", s.databaseName, err)
 	}
-	if strict && tag.RowsAffected() == int64(0) {
-		return errors.New("no publish status found for collection")
+	defer s.closeConn(ctx, conn)
+
+	supersedeQuery := `UPDATE collections.collection_publish_jobs
+                       SET status = @failed, error = @error, updated_at = @now
+                       WHERE collection_id = @collection_id AND status IN (@queued, @running)`
+	insertQuery := fmt.Sprintf(`WITH j AS (INSERT INTO collections.collection_publish_jobs (collection_id, user_id, status, created_at, updated_at)
+                                           VALUES (@collection_id, @user_id, @queued, @now, @now)
+                                           RETURNING *)
+                                %s`, fmt.Sprintf(publishJobSQL, "j"))
+	args := pgx.NamedArgs{
+		"collection_id": collectionID,
+		"user_id":       userID,
+		"queued":        publishing.JobQueuedStatus,
+		"running":       publishing.JobRunningStatus,
+		"failed":        publishing.JobFailedStatus,
+		"error":         "replaced by a newer publish",
+		"now":           time.Now().UTC(),
 	}
 
-	return nil
+	var job PublishJob
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, supersedeQuery, args); err != nil {
+			return err
+		}
+		job, err = scanPublishJob(tx.QueryRow(ctx, insertQuery, args))
+		return err
+	}); err != nil {
+		return PublishJob{}, fmt.Errorf("error creating publish job for collection %d: %w", collectionID, err)
+	}
+	return job, nil
+}
+
+func (s *PostgresStore) GetPublishJob(ctx context.Context, jobID string) (PublishJob, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return PublishJob{}, fmt.Errorf("GetPublishJob error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
 
+	query := fmt.Sprintf(publishJobSQL, "collections.collection_publish_jobs j") + ` WHERE j.id = @id`
+	job, err := scanPublishJob(conn.QueryRow(ctx, query, pgx.NamedArgs{"id": jobID}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublishJob{}, ErrPublishJobNotFound
+		}
+		return PublishJob{}, fmt.Errorf("error getting publish job %s: %w", jobID, err)
+	}
+	return job, nil
+}
+
+func (s *PostgresStore) GetLatestPublishJob(ctx context.Context, collectionID int64) (PublishJob, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return PublishJob{}, fmt.Errorf("GetLatestPublishJob error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := fmt.Sprintf(publishJobSQL, "collections.collection_publish_jobs j") +
+		` WHERE j.collection_id = @collection_id
+          ORDER BY j.created_at DESC, j.id
+          LIMIT 1`
+	job, err := scanPublishJob(conn.QueryRow(ctx, query, pgx.NamedArgs{"collection_id": collectionID}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublishJob{}, ErrPublishJobNotFound
+		}
+		return PublishJob{}, fmt.Errorf("error getting latest publish job of collection %d: %w", collectionID, err)
+	}
+	return job, nil
+}
+
+func (s *PostgresStore) StartPublishJobAttempt(ctx context.Context, jobID string) (PublishJob, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return PublishJob{}, fmt.Errorf("StartPublishJobAttempt error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	// the error of the previous attempt is kept until this one finishes
+	query := fmt.Sprintf(`WITH j AS (UPDATE collections.collection_publish_jobs
+                                     SET status = @running, attempts = attempts + 1, updated_at = @now
+                                     WHERE id = @id AND status IN (@queued, @running)
+                                     RETURNING *)
+                          %s`, fmt.Sprintf(publishJobSQL, "j"))
+	args := pgx.NamedArgs{
+		"id":      jobID,
+		"queued":  publishing.JobQueuedStatus,
+		"running": publishing.JobRunningStatus,
+		"now":     time.Now().UTC(),
+	}
+	job, err := scanPublishJob(conn.QueryRow(ctx, query, args))
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return PublishJob{}, fmt.Errorf("error starting attempt of publish job %s: %w", jobID, err)
+	}
+	var exists bool
+	if err := conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM collections.collection_publish_jobs WHERE id = @id)`,
+		args).Scan(&exists); err != nil {
+		return PublishJob{}, fmt.Errorf("error checking existence of publish job %s: %w", jobID, err)
+	}
+	if exists {
+		return PublishJob{}, ErrPublishJobFinished
+	}
+	return PublishJob{}, ErrPublishJobNotFound
+}
+
+func (s *PostgresStore) UpdatePublishJobStep(ctx context.Context, jobID string, step publishing.JobStep) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("UpdatePublishJobStep error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `UPDATE collections.collection_publish_jobs
+              SET step = @step, updated_at = @now
+              WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":   jobID,
+		"step": step,
+		"now":  time.Now().UTC(),
+	}
+	tag, err := conn.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("error updating step of publish job %s to %s: %w", jobID, step, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPublishJobNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) FinishPublishJobAttempt(ctx context.Context, jobID string, status publishing.JobStatus, result PublishJobResult) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("FinishPublishJobAttempt error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `UPDATE collections.collection_publish_jobs
+              SET status = @status,
+                  error = @error,
+                  published_dataset_id = COALESCE(@published_dataset_id, published_dataset_id),
+                  published_version = COALESCE(@published_version, published_version),
+                  updated_at = @now
+              WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":                   jobID,
+		"status":               status,
+		"error":                result.Error,
+		"published_dataset_id": result.PublishedDatasetID,
+		"published_version":    result.PublishedVersion,
+		"now":                  time.Now().UTC(),
+	}
+	tag, err := conn.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("error finishing attempt of publish job %s with status %s: %w", jobID, status, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPublishJobNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCollectionMembers(ctx context.Context, collectionID int64) ([]CollectionMember, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("GetCollectionMembers error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `SELECT u.id, u.node_id, u.first_name, u.last_name, cu.role
+              FROM collections.collection_user cu
+                JOIN pennsieve.users u ON cu.user_id = u.id
+              WHERE cu.collection_id = @collection_id AND cu.permission_bit >= @min_perm
+              ORDER BY u.id asc`
+	args := pgx.NamedArgs{"collection_id": collectionID, "min_perm": pgdb.Guest}
+
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (CollectionMember, error) {
+		var member CollectionMember
+		var memberRole PgxRole
+		if err := row.Scan(&member.UserID, &member.UserNodeID, &member.FirstName, &member.LastName, &memberRole); err != nil {
+			return CollectionMember{}, err
+		}
+		member.Role = memberRole.AsRole()
+		return member, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetCollectionMembers error querying for members of collection %d: %w", collectionID, err)
+	}
+	return members, nil
+}
+
+func (s *PostgresStore) PutCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string, memberRole role.Role) (CollectionMember, error) {
+	permission := pgdb.FromRole(memberRole.String())
+	if permission == pgdb.NoPermission {
+		return CollectionMember{}, fmt.Errorf("PutCollectionMember: cannot grant role %s", memberRole)
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return CollectionMember{}, fmt.Errorf("PutCollectionMember error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	member := CollectionMember{Role: permission.ToRole()}
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := lockCollection(ctx, tx, collectionID); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx,
+			`SELECT id, node_id, first_name, last_name FROM pennsieve.users WHERE node_id = @user_node_id`,
+			pgx.NamedArgs{"user_node_id": userNodeID},
+		).Scan(&member.UserID, &member.UserNodeID, &member.FirstName, &member.LastName); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("error looking up user %s: %w", userNodeID, err)
+		}
+
+		var previousRole *PgxRole
+		if err := tx.QueryRow(ctx,
+			`SELECT role FROM collections.collection_user WHERE collection_id = @collection_id AND user_id = @user_id`,
+			pgx.NamedArgs{"collection_id": collectionID, "user_id": member.UserID},
+		).Scan(&previousRole); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("error looking up current role of user %s: %w", userNodeID, err)
+		}
+
+		upsertSQL := `INSERT INTO collections.collection_user (collection_id, user_id, permission_bit, role)
+                      VALUES (@collection_id, @user_id, @permission_bit, @role)
+                      ON CONFLICT (collection_id, user_id) DO UPDATE
+                        SET permission_bit = EXCLUDED.permission_bit,
+                            role = EXCLUDED.role`
+		upsertArgs := pgx.NamedArgs{
+			"collection_id":  collectionID,
+			"user_id":        member.UserID,
+			"permission_bit": permission,
+			"role":           PgxRole(member.Role),
+		}
+		if _, err := tx.Exec(ctx, upsertSQL, upsertArgs); err != nil {
+			return fmt.Errorf("error setting role of user %s: %w", userNodeID, err)
+		}
+
+		if err := requireOwner(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		switch {
+		case previousRole == nil:
+			return insertEvents(ctx, tx, collectionID, &actorID,
+				newEvent(MemberAddedEvent, EventDiff{"member": userNodeID, "role": Change{New: member.Role.String()}}))
+		case previousRole.AsRole() != member.Role:
+			return insertEvents(ctx, tx, collectionID, &actorID,
+				newEvent(MemberRoleChangedEvent, EventDiff{"member": userNodeID, "role": Change{Old: previousRole.AsRole().String(), New: member.Role.String()}}))
+		default:
+			return nil
+		}
+	}); err != nil {
+		return CollectionMember{}, fmt.Errorf("PutCollectionMember error updating member %s of collection %d: %w", userNodeID, collectionID, err)
+	}
+	return member, nil
+}
+
+func (s *PostgresStore) DeleteCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("DeleteCollectionMember error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := lockCollection(ctx, tx, collectionID); err != nil {
+			return err
+		}
+
+		deleteSQL := `DELETE FROM collections.collection_user cu
+                      USING pennsieve.users u
+                      WHERE cu.user_id = u.id
+                        AND cu.collection_id = @collection_id
+                        AND u.node_id = @user_node_id
+                      RETURNING cu.role`
+		var removedRole PgxRole
+		if err := tx.QueryRow(ctx, deleteSQL, pgx.NamedArgs{"collection_id": collectionID, "user_node_id": userNodeID}).Scan(&removedRole); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCollectionMemberNotFound
+			}
+			return fmt.Errorf("error removing user %s: %w", userNodeID, err)
+		}
+
+		if err := requireOwner(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID,
+			newEvent(MemberRemovedEvent, EventDiff{"member": userNodeID, "role": Change{Old: removedRole.AsRole().String()}}))
+	}); err != nil {
+		return fmt.Errorf("DeleteCollectionMember error removing member %s of collection %d: %w", userNodeID, collectionID, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) TransferOwnership(ctx context.Context, collectionID int64, currentOwnerID int64, newOwnerNodeID string) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("TransferOwnership error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := lockCollection(ctx, tx, collectionID); err != nil {
+			return err
+		}
+
+		promoteSQL := `UPDATE collections.collection_user cu
+                       SET permission_bit = @owner_permission, role = @owner_role
+                       FROM pennsieve.users u
+                       WHERE cu.user_id = u.id
+                         AND cu.collection_id = @collection_id
+                         AND u.node_id = @user_node_id
+                         AND cu.user_id != @current_owner_id`
+		promoteArgs := pgx.NamedArgs{
+			"collection_id":    collectionID,
+			"user_node_id":     newOwnerNodeID,
+			"current_owner_id": currentOwnerID,
+			"owner_permission": pgdb.Owner,
+			"owner_role":       PgxRole(pgdb.Owner.ToRole()),
+		}
+		promoteTag, err := tx.Exec(ctx, promoteSQL, promoteArgs)
+		if err != nil {
+			return fmt.Errorf("error promoting user %s to owner: %w", newOwnerNodeID, err)
+		}
+		if promoteTag.RowsAffected() == 0 {
+			return ErrCollectionMemberNotFound
+		}
+
+		demoteSQL := `UPDATE collections.collection_user cu
+                      SET permission_bit = @manager_permission, role = @manager_role
+                      FROM pennsieve.users u
+                      WHERE cu.user_id = u.id
+                        AND cu.collection_id = @collection_id
+                        AND cu.user_id = @current_owner_id
+                        AND cu.permission_bit = @owner_permission
+                      RETURNING u.node_id`
+		demoteArgs := pgx.NamedArgs{
+			"collection_id":      collectionID,
+			"current_owner_id":   currentOwnerID,
+			"owner_permission":   pgdb.Owner,
+			"manager_permission": pgdb.Administer,
+			"manager_role":       PgxRole(pgdb.Administer.ToRole()),
+		}
+		var currentOwnerNodeID string
+		if err := tx.QueryRow(ctx, demoteSQL, demoteArgs).Scan(&currentOwnerNodeID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotOwner
+			}
+			return fmt.Errorf("error demoting user %d to manager: %w", currentOwnerID, err)
+		}
+		return insertEvents(ctx, tx, collectionID, &currentOwnerID,
+			newEvent(OwnershipTransferredEvent, EventDiff{"owner": Change{Old: currentOwnerNodeID, New: newOwnerNodeID}}))
+	}); err != nil {
+		return fmt.Errorf("TransferOwnership error transferring collection %d from user %d to %s: %w",
+			collectionID,
+			currentOwnerID,
+			newOwnerNodeID,
+			err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCollectionGrants(ctx context.Context, collectionID int64) ([]CollectionGrant, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("GetCollectionGrants error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `SELECT g.grantee_type, g.grantee_id, g.grantee_node_id, g.name, g.role
+              FROM (
+                SELECT 'team' AS grantee_type, t.id AS grantee_id, t.node_id AS grantee_node_id, t.name, ct.role
+                FROM collections.collection_team ct
+                  JOIN pennsieve.teams t ON ct.team_id = t.id
+                WHERE ct.collection_id = @collection_id AND ct.permission_bit >= @min_perm
+                UNION ALL
+                SELECT 'organization', o.id, o.node_id, o.name, co.role
+                FROM collections.collection_organization co
+                  JOIN pennsieve.organizations o ON co.organization_id = o.id
+                WHERE co.collection_id = @collection_id AND co.permission_bit >= @min_perm
+              ) g
+              ORDER BY g.grantee_type, g.grantee_id`
+	args := pgx.NamedArgs{"collection_id": collectionID, "min_perm": pgdb.Guest}
+
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	grants, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (CollectionGrant, error) {
+		var grant CollectionGrant
+		var granteeType string
+		var grantRole PgxRole
+		if err := row.Scan(&granteeType, &grant.GranteeID, &grant.GranteeNodeID, &grant.Name, &grantRole); err != nil {
+			return CollectionGrant{}, err
+		}
+		grant.GranteeType = GranteeType(granteeType)
+		grant.Role = grantRole.AsRole()
+		return grant, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetCollectionGrants error querying for grants on collection %d: %w", collectionID, err)
+	}
+	return grants, nil
+}
+
+func (s *PostgresStore) PutCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType GranteeType, granteeNodeID string, grantRole role.Role) (CollectionGrant, error) {
+	tables, err := granteeType.tables()
+	if err != nil {
+		return CollectionGrant{}, fmt.Errorf("PutCollectionGrant: %w", err)
+	}
+	permission := pgdb.FromRole(grantRole.String())
+	if permission == pgdb.NoPermission || grantRole > MaxGrantRole {
+		return CollectionGrant{}, fmt.Errorf("PutCollectionGrant: cannot grant role %s to a %s", grantRole, granteeType)
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return CollectionGrant{}, fmt.Errorf("PutCollectionGrant error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	grant := CollectionGrant{GranteeType: granteeType, Role: permission.ToRole()}
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := lockCollection(ctx, tx, collectionID); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx,
+			fmt.Sprintf(`SELECT id, node_id, name FROM %s WHERE node_id = @grantee_node_id`, tables.granteeTable),
+			pgx.NamedArgs{"grantee_node_id": granteeNodeID},
+		).Scan(&grant.GranteeID, &grant.GranteeNodeID, &grant.Name); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrGranteeNotFound
+			}
+			return fmt.Errorf("error looking up %s %s: %w", granteeType, granteeNodeID, err)
+		}
+
+		var previousRole *PgxRole
+		if err := tx.QueryRow(ctx,
+			fmt.Sprintf(`SELECT role FROM %s WHERE collection_id = @collection_id AND %s = @grantee_id`, tables.grantTable, tables.granteeIDColumn),
+			pgx.NamedArgs{"collection_id": collectionID, "grantee_id": grant.GranteeID},
+		).Scan(&previousRole); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("error looking up current role of %s %s: %w", granteeType, granteeNodeID, err)
+		}
+
+		upsertSQL := fmt.Sprintf(`INSERT INTO %[1]s (collection_id, %[2]s, permission_bit, role)
+                      VALUES (@collection_id, @grantee_id, @permission_bit, @role)
+                      ON CONFLICT (collection_id, %[2]s) DO UPDATE
+                        SET permission_bit = EXCLUDED.permission_bit,
+                            role = EXCLUDED.role`, tables.grantTable, tables.granteeIDColumn)
+		upsertArgs := pgx.NamedArgs{
+			"collection_id":  collectionID,
+			"grantee_id":     grant.GranteeID,
+			"permission_bit": permission,
+			"role":           PgxRole(grant.Role),
+		}
+		if _, err := tx.Exec(ctx, upsertSQL, upsertArgs); err != nil {
+			return fmt.Errorf("error setting role of %s %s: %w", granteeType, granteeNodeID, err)
+		}
+
+		switch {
+		case previousRole == nil:
+			return insertEvents(ctx, tx, collectionID, &actorID,
+				newEvent(GrantAddedEvent, grantEventDiff(granteeType, granteeNodeID, Change{New: grant.Role.String()})))
+		case previousRole.AsRole() != grant.Role:
+			return insertEvents(ctx, tx, collectionID, &actorID,
+				newEvent(GrantRoleChangedEvent, grantEventDiff(granteeType, granteeNodeID, Change{Old: previousRole.AsRole().String(), New: grant.Role.String()})))
+		default:
+			return nil
+		}
+	}); err != nil {
+		return CollectionGrant{}, fmt.Errorf("PutCollectionGrant error updating %s %s on collection %d: %w", granteeType, granteeNodeID, collectionID, err)
+	}
+	return grant, nil
+}
+
+func (s *PostgresStore) DeleteCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType GranteeType, granteeNodeID string) error {
+	tables, err := granteeType.tables()
+	if err != nil {
+		return fmt.Errorf("DeleteCollectionGrant: %w", err)
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("DeleteCollectionGrant error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	deleteSQL := fmt.Sprintf(`DELETE FROM %s g
+                      USING %s p
+                      WHERE g.%s = p.id
+                        AND g.collection_id = @collection_id
+                        AND p.node_id = @grantee_node_id
+                      RETURNING g.role`, tables.grantTable, tables.granteeTable, tables.granteeIDColumn)
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		var removedRole PgxRole
+		if err := tx.QueryRow(ctx, deleteSQL, pgx.NamedArgs{"collection_id": collectionID, "grantee_node_id": granteeNodeID}).Scan(&removedRole); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCollectionGrantNotFound
+			}
+			return fmt.Errorf("error removing %s %s: %w", granteeType, granteeNodeID, err)
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID,
+			newEvent(GrantRemovedEvent, grantEventDiff(granteeType, granteeNodeID, Change{Old: removedRole.AsRole().String()})))
+	}); err != nil {
+		if errors.Is(err, ErrCollectionGrantNotFound) {
+			return err
+		}
+		return fmt.Errorf("DeleteCollectionGrant error removing %s %s from collection %d: %w", granteeType, granteeNodeID, collectionID, err)
+	}
+	return nil
+}
+
+// grantEventDiff is the diff of an event that changed the role of the given team or organization.
+func grantEventDiff(granteeType GranteeType, granteeNodeID string, roleChange Change) EventDiff {
+	return EventDiff{"grantee": granteeNodeID, "granteeType": string(granteeType), "role": roleChange}
+}
+
+// granteeTables names the tables and columns used to store grants for a GranteeType.
+type granteeTables struct {
+	grantTable      string
+	granteeIDColumn string
+	granteeTable    string
+}
+
+func (t GranteeType) tables() (granteeTables, error) {
+	switch t {
+	case TeamGrantee:
+		return granteeTables{
+			grantTable:      "collections.collection_team",
+			granteeIDColumn: "team_id",
+			granteeTable:    "pennsieve.teams",
+		}, nil
+	case OrganizationGrantee:
+		return granteeTables{
+			grantTable:      "collections.collection_organization",
+			granteeIDColumn: "organization_id",
+			granteeTable:    "pennsieve.organizations",
+		}, nil
+	default:
+		return granteeTables{}, fmt.Errorf("unknown grantee type: %q", t)
+	}
+}
+
+func (s *PostgresStore) CreateSection(ctx context.Context, collectionID int64, actorID int64, request CreateSectionRequest) (Section, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return Section{}, fmt.Errorf("CreateSection error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	var section Section
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := incrementVersion(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		var sectionID int64
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO collections.collection_sections (collection_id, title, description, position)
+             SELECT @collection_id, @title, @description, COALESCE(max(position) + 1, 0)
+             FROM collections.collection_sections
+             WHERE collection_id = @collection_id
+             RETURNING id`,
+			pgx.NamedArgs{"collection_id": collectionID, "title": request.Title, "description": request.Description},
+		).Scan(&sectionID); err != nil {
+			return fmt.Errorf("error inserting section: %w", err)
+		}
+		if err := setSectionDOIs(ctx, tx, collectionID, sectionID, request.DOIs); err != nil {
+			return err
+		}
+		section, err = getSection(ctx, tx, collectionID, sectionID)
+		if err != nil {
+			return err
+		}
+		diff := EventDiff{"section": section.ID, "title": Change{New: section.Title}}
+		if len(section.DOIs) > 0 {
+			diff["dois"] = Change{New: section.DOIs}
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID, newEvent(SectionCreatedEvent, diff))
+	}); err != nil {
+		return Section{}, fmt.Errorf("CreateSection error creating section in collection %d: %w", collectionID, err)
+	}
+	return section, nil
+}
+
+func (s *PostgresStore) UpdateSection(ctx context.Context, collectionID, actorID, sectionID int64, update UpdateSectionRequest) (Section, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return Section{}, fmt.Errorf("UpdateSection error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	var section Section
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := incrementVersion(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		before, err := getSections(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+		args := pgx.NamedArgs{"collection_id": collectionID, "section_id": sectionID}
+		var sets []string
+		if update.Title != nil {
+			sets = append(sets, "title = @title")
+			args["title"] = *update.Title
+		}
+		if update.Description != nil {
+			sets = append(sets, "description = @description")
+			args["description"] = *update.Description
+		}
+		// always run the update, even with only the updated_at trigger to fire, so that we find out if the section exists
+		if len(sets) == 0 {
+			sets = append(sets, "title = title")
+		}
+		tag, err := tx.Exec(ctx,
+			fmt.Sprintf(`UPDATE collections.collection_sections SET %s WHERE id = @section_id AND collection_id = @collection_id`, strings.Join(sets, ", ")),
+			args)
+		if err != nil {
+			return fmt.Errorf("error updating section %d: %w", sectionID, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrSectionNotFound
+		}
+		if update.Position != nil {
+			if err := moveSection(ctx, tx, collectionID, sectionID, *update.Position); err != nil {
+				return err
+			}
+		}
+		if update.DOIs != nil {
+			if err := setSectionDOIs(ctx, tx, collectionID, sectionID, update.DOIs); err != nil {
+				return err
+			}
+		}
+		after, err := getSections(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+		var diff EventDiff
+		section, diff = sectionEventDiff(before, after, sectionID)
+		if len(diff) == 1 {
+			// only the section id, so nothing changed
+			return nil
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID, newEvent(SectionUpdatedEvent, diff))
+	}); err != nil {
+		return Section{}, fmt.Errorf("UpdateSection error updating section %d in collection %d: %w", sectionID, collectionID, err)
+	}
+	return section, nil
+}
+
+func (s *PostgresStore) DeleteSection(ctx context.Context, collectionID, actorID, sectionID int64) error {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("DeleteSection error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := incrementVersion(ctx, tx, collectionID); err != nil {
+			return err
+		}
+		var title string
+		if err := tx.QueryRow(ctx,
+			`DELETE FROM collections.collection_sections WHERE id = @section_id AND collection_id = @collection_id RETURNING title`,
+			pgx.NamedArgs{"collection_id": collectionID, "section_id": sectionID}).Scan(&title); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSectionNotFound
+			}
+			return err
+		}
+		return insertEvents(ctx, tx, collectionID, &actorID,
+			newEvent(SectionDeletedEvent, EventDiff{"section": sectionID, "title": Change{Old: title}}))
+	}); err != nil {
+		return fmt.Errorf("DeleteSection error deleting section %d in collection %d: %w", sectionID, collectionID, err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCollectionEvents(ctx context.Context, collectionID int64, limit int, offset int) (GetCollectionEventsResponse, error) {
+	if limit < 0 {
+		return GetCollectionEventsResponse{}, fmt.Errorf("limit cannot be negative: %d", limit)
+	}
+	if offset < 0 {
+		return GetCollectionEventsResponse{}, fmt.Errorf("offset cannot be negative: %d", offset)
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return GetCollectionEventsResponse{}, fmt.Errorf("GetCollectionEvents error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	args := pgx.NamedArgs{
+		"collection_id": collectionID,
+		"limit":         limit,
+		"offset":        offset,
+	}
+	query := `SELECT e.id, e.type, e.diff, e.created_at, u.id, u.node_id, u.first_name, u.last_name, count(*) OVER () AS total_count
+              FROM collections.collection_events e
+                LEFT JOIN pennsieve.users u ON e.user_id = u.id
+              WHERE e.collection_id = @collection_id
+              ORDER BY e.id desc
+              LIMIT @limit OFFSET @offset`
+
+	response := GetCollectionEventsResponse{Limit: limit, Offset: offset}
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	response.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (Event, error) {
+		var event Event
+		var userID *int64
+		var userNodeID, firstName, lastName *string
+		if err := row.Scan(&event.ID, &event.Type, &event.Diff, &event.CreatedAt, &userID, &userNodeID, &firstName, &lastName, &response.TotalCount); err != nil {
+			return Event{}, err
+		}
+		event.User = newActor(userID, userNodeID, firstName, lastName)
+		return event, nil
+	})
+	if err != nil {
+		return GetCollectionEventsResponse{}, fmt.Errorf("GetCollectionEvents error querying for events of collection %d: %w", collectionID, err)
+	}
+
+	// as in GetCollections, recount if limit or offset left us with nothing
+	if len(response.Events) == 0 {
+		if err := conn.QueryRow(ctx, `SELECT count(*) FROM collections.collection_events WHERE collection_id = @collection_id`, args).Scan(&response.TotalCount); err != nil {
+			return GetCollectionEventsResponse{}, fmt.Errorf("GetCollectionEvents error counting events of collection %d: %w", collectionID, err)
+		}
+	}
+	return response, nil
+}
+
+func (s *PostgresStore) CreateSnapshot(ctx context.Context, collectionID int64, userID int64, name string) (Snapshot, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("CreateSnapshot error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	snapshot := Snapshot{SnapshotSummary: SnapshotSummary{Name: name}}
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		// locking the collection keeps its DOIs from changing while we read them
+		current, err := selectCollectionForUpdate(ctx, tx, collectionID)
+		if err != nil {
+			return err
+		}
+		snapshot.State = SnapshotState{
+			Name:        current.Name,
+			Description: current.Description,
+			License:     current.License,
+			Tags:        current.Tags,
+		}
+		rows, _ := tx.Query(ctx,
+			`SELECT doi, datasource, COALESCE(label, ''), COALESCE(note, '')
+             FROM collections.dois
+             WHERE collection_id = @collection_id
+             ORDER BY position, id`,
+			pgx.NamedArgs{"collection_id": collectionID})
+		var doi DOI
+		if _, err := pgx.ForEachRow(rows, []any{&doi.Value, &doi.Datasource, &doi.Label, &doi.Note}, func() error {
+			snapshot.State.DOIs = append(snapshot.State.DOIs, doi)
+			return nil
+		}); err != nil {
+			return fmt.Errorf("error getting DOIs of collection %d: %w", collectionID, err)
+		}
+		state, err := json.Marshal(snapshot.State)
+		if err != nil {
+			return fmt.Errorf("error marshalling snapshot of collection %d: %w", collectionID, err)
+		}
+		var userNodeID string
+		var firstName, lastName *string
+		if err := tx.QueryRow(ctx,
+			`WITH snapshot AS (
+                INSERT INTO collections.collection_snapshots (collection_id, name, user_id, state)
+                VALUES (@collection_id, @name, @user_id, @state)
+                RETURNING id, created_at, user_id
+             )
+             SELECT snapshot.id, snapshot.created_at, u.node_id, u.first_name, u.last_name
+             FROM snapshot JOIN pennsieve.users u ON snapshot.user_id = u.id`,
+			pgx.NamedArgs{"collection_id": collectionID, "name": name, "user_id": userID, "state": state},
+		).Scan(&snapshot.ID, &snapshot.CreatedAt, &userNodeID, &firstName, &lastName); err != nil {
+			return fmt.Errorf("error inserting snapshot of collection %d: %w", collectionID, err)
+		}
+		snapshot.CreatedBy = &Actor{UserID: userID, UserNodeID: userNodeID, FirstName: firstName, LastName: lastName}
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrCollectionNotFound) {
+			return Snapshot{}, err
+		}
+		return Snapshot{}, fmt.Errorf("CreateSnapshot error creating snapshot of collection %d: %w", collectionID, err)
+	}
+	return snapshot, nil
+}
+
+func (s *PostgresStore) GetSnapshots(ctx context.Context, collectionID int64, limit int, offset int) (GetSnapshotsResponse, error) {
+	if limit < 0 {
+		return GetSnapshotsResponse{}, fmt.Errorf("limit cannot be negative: %d", limit)
+	}
+	if offset < 0 {
+		return GetSnapshotsResponse{}, fmt.Errorf("offset cannot be negative: %d", offset)
+	}
+
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return GetSnapshotsResponse{}, fmt.Errorf("GetSnapshots error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	args := pgx.NamedArgs{
+		"collection_id": collectionID,
+		"limit":         limit,
+		"offset":        offset,
+	}
+	query := `SELECT s.id, s.name, s.created_at, u.id, u.node_id, u.first_name, u.last_name, count(*) OVER () AS total_count
+              FROM collections.collection_snapshots s
+                LEFT JOIN pennsieve.users u ON s.user_id = u.id
+              WHERE s.collection_id = @collection_id
+              ORDER BY s.id desc
+              LIMIT @limit OFFSET @offset`
+
+	response := GetSnapshotsResponse{Limit: limit, Offset: offset}
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	response.Snapshots, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (SnapshotSummary, error) {
+		var summary SnapshotSummary
+		var userID *int64
+		var userNodeID, firstName, lastName *string
+		if err := row.Scan(&summary.ID, &summary.Name, &summary.CreatedAt, &userID, &userNodeID, &firstName, &lastName, &response.TotalCount); err != nil {
+			return SnapshotSummary{}, err
+		}
+		summary.CreatedBy = newActor(userID, userNodeID, firstName, lastName)
+		return summary, nil
+	})
+	if err != nil {
+		return GetSnapshotsResponse{}, fmt.Errorf("GetSnapshots error querying for snapshots of collection %d: %w", collectionID, err)
+	}
+
+	// as in GetCollections, recount if limit or offset left us with nothing
+	if len(response.Snapshots) == 0 {
+		if err := conn.QueryRow(ctx, `SELECT count(*) FROM collections.collection_snapshots WHERE collection_id = @collection_id`, args).Scan(&response.TotalCount); err != nil {
+			return GetSnapshotsResponse{}, fmt.Errorf("GetSnapshots error counting snapshots of collection %d: %w", collectionID, err)
+		}
+	}
+	return response, nil
+}
+
+func (s *PostgresStore) GetSnapshot(ctx context.Context, collectionID, snapshotID int64) (Snapshot, error) {
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("GetSnapshot error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	var snapshot Snapshot
+	var state json.RawMessage
+	var userID *int64
+	var userNodeID, firstName, lastName *string
+	if err := conn.QueryRow(ctx,
+		`SELECT s.id, s.name, s.created_at, s.state, u.id, u.node_id, u.first_name, u.last_name
+         FROM collections.collection_snapshots s
+           LEFT JOIN pennsieve.users u ON s.user_id = u.id
+         WHERE s.id = @snapshot_id AND s.collection_id = @collection_id`,
+		pgx.NamedArgs{"collection_id": collectionID, "snapshot_id": snapshotID},
+	).Scan(&snapshot.ID, &snapshot.Name, &snapshot.CreatedAt, &state, &userID, &userNodeID, &firstName, &lastName); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Snapshot{}, ErrSnapshotNotFound
+		}
+		return Snapshot{}, fmt.Errorf("GetSnapshot error querying for snapshot %d of collection %d: %w", snapshotID, collectionID, err)
+	}
+	if err := json.Unmarshal(state, &snapshot.State); err != nil {
+		return Snapshot{}, fmt.Errorf("GetSnapshot error unmarshalling snapshot %d of collection %d: %w", snapshotID, collectionID, err)
+	}
+	snapshot.CreatedBy = newActor(userID, userNodeID, firstName, lastName)
+	return snapshot, nil
+}
+
+// getSectionsSQLFormat selects the sections of @collection_id in section order. The format parameter is an
+// additional condition on the sections table s.
+const getSectionsSQLFormat = `SELECT s.id, s.title, s.description,
+       COALESCE(array_agg(d.doi ORDER BY d.position, d.id) FILTER (WHERE d.doi IS NOT NULL), '{}') AS dois
+FROM collections.collection_sections s
+         LEFT JOIN collections.dois d ON d.section_id = s.id
+WHERE s.collection_id = @collection_id %s
+GROUP BY s.id
+ORDER BY s.position, s.id`
+
+// getSections returns nil if the given collection has no sections.
+func getSections(ctx context.Context, q postgres.Querier, collectionID int64) ([]Section, error) {
+	rows, _ := q.Query(ctx, fmt.Sprintf(getSectionsSQLFormat, ""), pgx.NamedArgs{"collection_id": collectionID})
+	sections, err := pgx.CollectRows(rows, scanSection)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sections of collection %d: %w", collectionID, err)
+	}
+	if len(sections) == 0 {
+		return nil, nil
+	}
+	return sections, nil
+}
+
+// getSection returns ErrSectionNotFound if the given section does not exist in the given collection.
+func getSection(ctx context.Context, q postgres.Querier, collectionID, sectionID int64) (Section, error) {
+	rows, _ := q.Query(ctx, fmt.Sprintf(getSectionsSQLFormat, "AND s.id = @section_id"), pgx.NamedArgs{"collection_id": collectionID, "section_id": sectionID})
+	section, err := pgx.CollectExactlyOneRow(rows, scanSection)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Section{}, ErrSectionNotFound
+		}
+		return Section{}, fmt.Errorf("error getting section %d: %w", sectionID, err)
+	}
+	return section, nil
+}
+
+// sectionEventDiff returns the given section as it is in after, along with a diff of its title, description, DOIs,
+// and position between before and after. The diff always identifies the section.
+// before and after are the sections of a collection in order, as returned by getSections.
+func sectionEventDiff(before, after []Section, sectionID int64) (Section, EventDiff) {
+	oldPosition := slices.IndexFunc(before, func(s Section) bool { return s.ID == sectionID })
+	newPosition := slices.IndexFunc(after, func(s Section) bool { return s.ID == sectionID })
+	diff := EventDiff{"section": sectionID}
+	if oldPosition < 0 || newPosition < 0 {
+		return Section{}, diff
+	}
+	old, updated := before[oldPosition], after[newPosition]
+	if old.Title != updated.Title {
+		diff["title"] = Change{Old: old.Title, New: updated.Title}
+	}
+	if old.Description != updated.Description {
+		diff["description"] = Change{Old: emptyAsNil(old.Description), New: emptyAsNil(updated.Description)}
+	}
+	if !slices.Equal(old.DOIs, updated.DOIs) {
+		diff["dois"] = Change{Old: old.DOIs, New: updated.DOIs}
+	}
+	if oldPosition != newPosition {
+		diff["position"] = Change{Old: oldPosition, New: newPosition}
+	}
+	return updated, diff
+}
+
+func scanSection(row pgx.CollectableRow) (Section, error) {
+	var section Section
+	err := row.Scan(&section.ID, &section.Title, &section.Description, &section.DOIs)
+	return section, err
+}
+
+// setSectionDOIs replaces the DOIs in the given section with dois, moving them out of any other section.
+// Returns ErrSectionDOIsNotInCollection if any of dois are not in the given collection. dois should not contain duplicates.
+func setSectionDOIs(ctx context.Context, tx pgx.Tx, collectionID, sectionID int64, dois []string) error {
+	args := pgx.NamedArgs{"collection_id": collectionID, "section_id": sectionID, "dois": dois}
+	if _, err := tx.Exec(ctx,
+		`UPDATE collections.dois SET section_id = NULL WHERE section_id = @section_id`,
+		args); err != nil {
+		return fmt.Errorf("error clearing DOIs of section %d: %w", sectionID, err)
+	}
+	if len(dois) == 0 {
+		return nil
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE collections.dois SET section_id = @section_id WHERE collection_id = @collection_id AND doi = ANY (@dois::text[])`,
+		args)
+	if err != nil {
+		return fmt.Errorf("error setting DOIs of section %d: %w", sectionID, err)
+	}
+	if tag.RowsAffected() != int64(len(dois)) {
+		return ErrSectionDOIsNotInCollection
+	}
+	return nil
+}
+
+// moveSection moves the given section to the given zero-based position among the sections of the given collection
+// and renumbers all the sections so that positions are contiguous.
+func moveSection(ctx context.Context, tx pgx.Tx, collectionID, sectionID int64, position int) error {
+	args := pgx.NamedArgs{"collection_id": collectionID}
+	rows, _ := tx.Query(ctx,
+		`SELECT id FROM collections.collection_sections WHERE collection_id = @collection_id ORDER BY position, id`,
+		args)
+	sectionIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("error getting section order of collection %d: %w", collectionID, err)
+	}
+	sectionIDs = slices.DeleteFunc(sectionIDs, func(id int64) bool { return id == sectionID })
+	position = min(max(position, 0), len(sectionIDs))
+	sectionIDs = slices.Insert(sectionIDs, position, sectionID)
+
+	args["section_ids"] = sectionIDs
+	if _, err := tx.Exec(ctx,
+		`UPDATE collections.collection_sections s
+         SET position = ordered.ordinality - 1
+         FROM unnest(@section_ids::int[]) WITH ORDINALITY AS ordered(id, ordinality)
+         WHERE s.collection_id = @collection_id AND s.id = ordered.id`,
+		args); err != nil {
+		return fmt.Errorf("error reordering sections of collection %d: %w", collectionID, err)
+	}
+	return nil
+}
+
+// lockCollection locks the given collection's row until the end of tx so that concurrent
+// membership changes are serialized. Otherwise, two transactions could each remove a different
+// owner and together leave the collection with none.
+// Returns ErrCollectionNotFound if the collection does not exist or is in the trash.
+func lockCollection(ctx context.Context, tx pgx.Tx, collectionID int64) error {
+	var id int64
+	if err := tx.QueryRow(ctx,
+		`SELECT id FROM collections.collections WHERE id = @collection_id AND deleted_at IS NULL FOR NO KEY UPDATE`,
+		pgx.NamedArgs{"collection_id": collectionID},
+	).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		return fmt.Errorf("error locking collection %d: %w", collectionID, err)
+	}
+	return nil
+}
+
+// lockCollectionVersion is like lockCollection, but only locks the collection if its version is expectedVersion.
+// Returns ErrCollectionVersionMismatch if the collection has a different version.
+func lockCollectionVersion(ctx context.Context, tx pgx.Tx, collectionID int64, expectedVersion int64) error {
+	var id int64
+	err := tx.QueryRow(ctx,
+		`SELECT id FROM collections.collections
+		 WHERE id = @collection_id AND version = @expected_version AND deleted_at IS NULL
+		 FOR NO KEY UPDATE`,
+		pgx.NamedArgs{"collection_id": collectionID, "expected_version": expectedVersion},
+	).Scan(&id)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("error locking collection %d at version %d: %w", collectionID, expectedVersion, err)
+	}
+	// no row means either no such collection or a different version
+	if err := lockCollection(ctx, tx, collectionID); err != nil {
+		return err
+	}
+	return ErrCollectionVersionMismatch
+}
+
+// collectionFields are the parts of a collection that UpdateCollection can change, along with its version.
+type collectionFields struct {
+	Name        string
+	Description string
+	License     *string
+	Tags        []string
+	Version     int64
+}
+
+// selectCollectionForUpdate returns the current fields of the given collection. Like lockCollection, it locks the
+// collection's row until the end of tx.
+func selectCollectionForUpdate(ctx context.Context, tx pgx.Tx, collectionID int64) (collectionFields, error) {
+	var fields collectionFields
+	if err := tx.QueryRow(ctx,
+		`SELECT name, description, license, tags, version FROM collections.collections WHERE id = @collection_id AND deleted_at IS NULL FOR NO KEY UPDATE`,
+		pgx.NamedArgs{"collection_id": collectionID},
+	).Scan(&fields.Name, &fields.Description, &fields.License, &fields.Tags, &fields.Version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return collectionFields{}, ErrCollectionNotFound
+		}
+		return collectionFields{}, fmt.Errorf("error selecting collection %d for update: %w", collectionID, err)
+	}
+	return fields, nil
+}
+
+// changeEvents returns the events for the changes update makes to f, ignoring DOI changes.
+func (f collectionFields) changeEvents(update UpdateCollectionRequest) []eventToInsert {
+	var events []eventToInsert
+	if update.Name != nil && *update.Name != f.Name {
+		events = append(events, newEvent(RenamedEvent, EventDiff{"name": Change{Old: f.Name, New: *update.Name}}))
+	}
+	if update.Description != nil && *update.Description != f.Description {
+		events = append(events, newEvent(DescriptionChangedEvent, EventDiff{"description": Change{Old: f.Description, New: *update.Description}}))
+	}
+	if update.License != nil && *update.License != util.SafeDeref(f.License) {
+		change := Change{Old: f.License}
+		if len(*update.License) > 0 {
+			change.New = *update.License
+		}
+		events = append(events, newEvent(LicenseChangedEvent, EventDiff{"license": change}))
+	}
+	if update.Tags != nil && !slices.Equal(update.Tags, f.Tags) {
+		events = append(events, newEvent(TagsChangedEvent, EventDiff{"tags": Change{Old: f.Tags, New: update.Tags}}))
+	}
+	return events
+}
+
+// doiAnnotationEvent returns the event for the given annotations, or nil if they do not change anything.
+// It must be called before the annotations are applied.
+func doiAnnotationEvent(ctx context.Context, tx pgx.Tx, collectionID int64, annotations []DOIAnnotation) (*eventToInsert, error) {
+	var annotatedDOIs []string
+	for _, annotation := range annotations {
+		annotatedDOIs = append(annotatedDOIs, annotation.DOI)
+	}
+	rows, _ := tx.Query(ctx,
+		`SELECT doi, COALESCE(label, ''), COALESCE(note, '') FROM collections.dois WHERE collection_id = @collection_id AND doi = ANY(@dois)`,
+		pgx.NamedArgs{"collection_id": collectionID, "dois": annotatedDOIs})
+	current := map[string]DOI{}
+	var doi DOI
+	if _, err := pgx.ForEachRow(rows, []any{&doi.Value, &doi.Label, &doi.Note}, func() error {
+		current[doi.Value] = doi
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error getting current annotations of collection %d DOIs: %w", collectionID, err)
+	}
+
+	changes := map[string]EventDiff{}
+	for _, annotation := range annotations {
+		currentDOI := current[annotation.DOI]
+		doiChanges := EventDiff{}
+		if annotation.Label != nil && *annotation.Label != currentDOI.Label {
+			doiChanges["label"] = Change{Old: emptyAsNil(currentDOI.Label), New: emptyAsNil(*annotation.Label)}
+		}
+		if annotation.Note != nil && *annotation.Note != currentDOI.Note {
+			doiChanges["note"] = Change{Old: emptyAsNil(currentDOI.Note), New: emptyAsNil(*annotation.Note)}
+		}
+		if len(doiChanges) > 0 {
+			changes[annotation.DOI] = doiChanges
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	event := newEvent(DOIsAnnotatedEvent, EventDiff{"dois": changes})
+	return &event, nil
+}
+
+func emptyAsNil(s string) any {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// newActor returns nil if userID is nil, which is the case when the user has been deleted.
+func newActor(userID *int64, userNodeID, firstName, lastName *string) *Actor {
+	if userID == nil {
+		return nil
+	}
+	return &Actor{
+		UserID:     *userID,
+		UserNodeID: util.SafeDeref(userNodeID),
+		FirstName:  firstName,
+		LastName:   lastName,
+	}
+}
+
+// setDOIOrder sets the position of each of the collection's DOIs to its index in dois. Returns ErrDOIOrderMismatch
+// unless dois contains exactly the DOIs in the collection. The collection should be locked by tx.
+func setDOIOrder(ctx context.Context, tx pgx.Tx, collectionID int64, dois []string) error {
+	args := pgx.NamedArgs{"collection_id": collectionID, "dois": dois}
+	commandTag, err := tx.Exec(ctx,
+		`UPDATE collections.dois d
+         SET position = ordered.ordinality - 1
+         FROM unnest(@dois::text[]) WITH ORDINALITY AS ordered(doi, ordinality)
+         WHERE d.collection_id = @collection_id AND d.doi = ordered.doi`,
+		args)
+	if err != nil {
+		return fmt.Errorf("error reordering collection %d DOIs: %w", collectionID, err)
+	}
+	var doiCount int64
+	if err := tx.QueryRow(ctx,
+		`SELECT count(*) FROM collections.dois WHERE collection_id = @collection_id`,
+		args).Scan(&doiCount); err != nil {
+		return fmt.Errorf("error counting collection %d DOIs: %w", collectionID, err)
+	}
+	if commandTag.RowsAffected() != int64(len(dois)) || doiCount != int64(len(dois)) {
+		return ErrDOIOrderMismatch
+	}
+	return nil
+}
+
+// incrementVersion records a change to the given collection that does not otherwise touch its row,
+// for example a change to its DOIs or sections. Like lockCollection, it locks the row until the end of tx.
+func incrementVersion(ctx context.Context, tx pgx.Tx, collectionID int64) error {
+	commandTag, err := tx.Exec(ctx,
+		`UPDATE collections.collections SET version = version + 1 WHERE id = @collection_id AND deleted_at IS NULL`,
+		pgx.NamedArgs{"collection_id": collectionID})
+	if err != nil {
+		return fmt.Errorf("error incrementing version of collection %d: %w", collectionID, err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+func publishStartedEventType(publishingType publishing.Type) EventType {
+	if publishingType == publishing.RemovalType {
+		return UnpublishStartedEvent
+	}
+	return PublishStartedEvent
+}
+
+func publishFinishedEventType(publishingType publishing.Type) EventType {
+	if publishingType == publishing.RemovalType {
+		return UnpublishFinishedEvent
+	}
+	return PublishFinishedEvent
+}
+
+type eventToInsert struct {
+	eventType EventType
+	diff      EventDiff
+}
+
+func newEvent(eventType EventType, diff EventDiff) eventToInsert {
+	return eventToInsert{eventType: eventType, diff: diff}
+}
+
+// insertEvents adds events to the activity log of the given collection as part of tx. userID is the user that made the changes,
+// or nil if unknown.
+func insertEvents(ctx context.Context, tx pgx.Tx, collectionID int64, userID *int64, events ...eventToInsert) error {
+	for _, event := range events {
+		diff, err := json.Marshal(event.diff)
+		if err != nil {
+			return fmt.Errorf("error marshalling %s event diff for collection %d: %w", event.eventType, collectionID, err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO collections.collection_events (collection_id, user_id, type, diff) VALUES (@collection_id, @user_id, @type, @diff)`,
+			pgx.NamedArgs{"collection_id": collectionID, "user_id": userID, "type": event.eventType, "diff": diff},
+		); err != nil {
+			return fmt.Errorf("error recording %s event for collection %d: %w", event.eventType, collectionID, err)
+		}
+	}
+	return nil
+}
+
+// requireOwner returns ErrLastOwner if the given collection has no owner as seen by tx.
+func requireOwner(ctx context.Context, tx pgx.Tx, collectionID int64) error {
+	var ownerCount int
+	if err := tx.QueryRow(ctx,
+		`SELECT count(*) FROM collections.collection_user WHERE collection_id = @collection_id AND permission_bit = @owner`,
+		pgx.NamedArgs{"collection_id": collectionID, "owner": pgdb.Owner},
+	).Scan(&ownerCount); err != nil {
+		return fmt.Errorf("error counting owners of collection %d: %w", collectionID, err)
+	}
+	if ownerCount == 0 {
+		return ErrLastOwner
+	}
+	return nil
 }
 
-func (s *PostgresStore) closeConn(ctx context.Context, conn *pgx.Conn) {
+func (s *PostgresStore) closeConn(ctx context.Context, conn postgres.Conn) {
 	if err := conn.Close(ctx); err != nil {
 		s.logger.Warn("error closing collections.PostgresStore DB connection", slog.Any("error", err))
 	}
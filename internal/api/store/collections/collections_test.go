@@ -2,6 +2,8 @@ package collections_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/pennsieve/collections-service/internal/api/dto"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
@@ -16,7 +18,9 @@ import (
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestStore(t *testing.T) {
@@ -37,6 +41,10 @@ func TestStore(t *testing.T) {
 		{"get collections", testGetCollections},
 		{"get collections, user with no permission on the collection should not see it", testGetCollectionsNoPerms},
 		{"get collections, limit and offset", testGetCollectionsLimitOffset},
+		{"get collections, filters", testGetCollectionsFilters},
+		{"get collections, sort", testGetCollectionsSort},
+		{"get collections, cursor", testGetCollectionsCursor},
+		{"get collections, cursor is stable when collections are created while paging", testGetCollectionsCursorConcurrentCreate},
 		{"get collection, none", testGetCollectionNone},
 		{"get collection", testGetCollection},
 		{"get collection should return publish status if one exists", testGetCollectionPublishStatus},
@@ -44,6 +52,7 @@ func TestStore(t *testing.T) {
 
 		{"delete collection", testDeleteCollection},
 		{"delete non-existent collection", testDeleteCollectionNonExistent},
+		{"delete collection should only delete the expected version", testDeleteCollectionExpectedVersion},
 		{"update collection name", testUpdateCollectionName},
 		{"update collection description", testUpdateCollectionDescription},
 		{"update collection name and description", testUpdateCollectionNameAndDescription},
@@ -53,6 +62,7 @@ func TestStore(t *testing.T) {
 		{"remove DOIs from collection", testUpdateCollectionRemoveDOIs},
 		{"add DOI to collection", testUpdateCollectionAddDOI},
 		{"add DOIs to collection", testUpdateCollectionAddDOIs},
+		{"update should annotate new and existing DOIs", testUpdateCollectionAnnotateDOIs},
 		{"update collection", testUpdateCollection},
 		{"update collection should return publish status if one exists", testUpdateCollectionPublishStatus},
 		{"update asking to remove a non-existent DOI should succeed", testUpdateCollectionRemoveNonExistentDOI},
@@ -63,8 +73,74 @@ func TestStore(t *testing.T) {
 		{"StartPublish should return InProgress error and do no updates if publish already in progress", testStartPublishExistingInProgress},
 		{"StartPublish should update an existing complete publish status", testStartPublishExistingComplete},
 		{"StartPublish should update an existing failed publish status", testStartPublishExistingFailed},
+		{"StartPublish should replace a stale in progress publish status", testStartPublishExistingStaleInProgress},
+		{"StartPublish should use the publish timeout of the store", testStartPublishWithPublishTimeout},
+		{"StartPublish should only start a publish of the expected version", testStartPublishExpectedVersion},
 		{"FinishPublish should update the publish status of a collection", testFinishPublish},
 		{"FinishPublish should return an error if no publish status exists", testFinishPublishNoExistingStatus},
+		{"GetStalePublishes should only return in progress publishes started before the given time", testGetStalePublishes},
+		{"FinishStalePublish should finish the stale publish", testFinishStalePublish},
+		{"FinishStalePublish should not change a publish that replaced the stale one", testFinishStalePublishReplaced},
+		{"GetCollectionMembers should return all members", testGetCollectionMembers},
+		{"PutCollectionMember should add a new member", testPutCollectionMemberAdd},
+		{"PutCollectionMember should change the role of an existing member", testPutCollectionMemberChangeRole},
+		{"PutCollectionMember should return ErrUserNotFound for an unknown user", testPutCollectionMemberUserNotFound},
+		{"PutCollectionMember should return ErrCollectionNotFound for a non-existent collection", testPutCollectionMemberCollectionNotFound},
+		{"PutCollectionMember should return ErrLastOwner and make no changes when demoting the only owner", testPutCollectionMemberLastOwner},
+		{"PutCollectionMember should allow demoting an owner if another owner exists", testPutCollectionMemberDemoteOneOfTwoOwners},
+		{"DeleteCollectionMember should remove a member", testDeleteCollectionMember},
+		{"DeleteCollectionMember should return ErrCollectionMemberNotFound for a non-member", testDeleteCollectionMemberNotMember},
+		{"DeleteCollectionMember should return ErrLastOwner and make no changes when removing the only owner", testDeleteCollectionMemberLastOwner},
+		{"TransferOwnership should promote the new owner and demote the current owner", testTransferOwnership},
+		{"TransferOwnership should return ErrCollectionMemberNotFound and make no changes if new owner is not a member", testTransferOwnershipNotMember},
+		{"TransferOwnership should return ErrNotOwner and make no changes if current owner is not an owner", testTransferOwnershipNotOwner},
+		{"get collections should include collections shared with a user's teams and organizations", testGetCollectionsTeamsAndOrganizations},
+		{"get collection should return the highest of a user's direct, team, and organization roles", testGetCollectionEffectiveRole},
+		{"get collection should not apply organization roles to organization guests", testGetCollectionOrganizationGuest},
+		{"GetCollectionGrants should return all team and organization grants", testGetCollectionGrants},
+		{"PutCollectionGrant should add and change team and organization grants", testPutCollectionGrant},
+		{"PutCollectionGrant should return ErrGranteeNotFound for an unknown team", testPutCollectionGrantNotFound},
+		{"PutCollectionGrant should not grant a role above Manager", testPutCollectionGrantAboveMax},
+		{"DeleteCollectionGrant should remove a grant", testDeleteCollectionGrant},
+		{"DeleteCollectionGrant should return ErrCollectionGrantNotFound if there is no grant", testDeleteCollectionGrantNotFound},
+		{"deleted collections should be hidden", testDeletedCollectionHidden},
+		{"GetDeletedCollections should return the trash of the user", testGetDeletedCollections},
+		{"GetDeletedCollection should only return collections in the trash", testGetDeletedCollection},
+		{"RestoreCollection should take a collection out of the trash", testRestoreCollection},
+		{"PurgeDeletedCollections should only delete collections past retention", testPurgeDeletedCollections},
+		{"ReorderDOIs should change the order of DOIs and banners", testReorderDOIs},
+		{"ReorderDOIs should return ErrDOIOrderMismatch and make no changes if DOIs do not match", testReorderDOIsMismatch},
+		{"ReorderDOIs on non-existent collection should return ErrCollectionNotFound", testReorderDOIsNonExistent},
+		{"CreateSection should add sections in order and move DOIs between them", testCreateSection},
+		{"CreateSection should return ErrSectionDOIsNotInCollection and make no changes if DOIs are not in collection", testCreateSectionDOIsNotInCollection},
+		{"UpdateSection should change title, description, position, and DOIs", testUpdateSection},
+		{"UpdateSection should return ErrSectionNotFound for a section in another collection", testUpdateSectionNotFound},
+		{"DeleteSection should remove the section and keep its DOIs in the collection", testDeleteSection},
+		{"updates should increment the collection version", testCollectionVersion},
+		{"UpdateCollection should return ErrCollectionVersionMismatch and make no changes if version has changed", testUpdateCollectionVersionMismatch},
+		{"create and update should record collection events", testCollectionEventsUpdate},
+		{"publishing should record collection events", testCollectionEventsPublish},
+		{"member changes should record collection events", testCollectionEventsMembers},
+		{"failed updates should not record collection events", testCollectionEventsFailedUpdate},
+		{"trash and restore should record collection events", testCollectionEventsTrash},
+		{"reordering DOIs should record collection events", testCollectionEventsReorder},
+		{"grant changes should record collection events", testCollectionEventsGrants},
+		{"section changes should record collection events", testCollectionEventsSections},
+		{"GetCollectionEvents should return events most recent first with users", testGetCollectionEvents},
+		{"GetCollectionEvents, limit and offset", testGetCollectionEventsLimitOffset},
+		{"UpdateCollection should set DOI order after adds and removes", testUpdateCollectionDOIOrder},
+		{"UpdateCollection should return ErrDOIOrderMismatch and make no changes if order does not match DOIs", testUpdateCollectionDOIOrderMismatch},
+		{"CreateSnapshot should save current state and GetSnapshot should return it", testCreateSnapshot},
+		{"CreateSnapshot on non-existent collection should return ErrCollectionNotFound", testCreateSnapshotNonExistent},
+		{"GetSnapshot should return ErrSnapshotNotFound for a snapshot in another collection", testGetSnapshotNotFound},
+		{"GetSnapshots should return snapshots most recent first, limit and offset", testGetSnapshots},
+		{"CreatePublishJob should create a queued job and GetPublishJob should return it", testCreatePublishJob},
+		{"CreatePublishJob should fail unfinished jobs of the collection", testCreatePublishJobSupersedes},
+		{"GetPublishJob should return ErrPublishJobNotFound for an unknown job", testGetPublishJobNotFound},
+		{"GetLatestPublishJob should return the most recent job of the collection", testGetLatestPublishJob},
+		{"StartPublishJobAttempt should mark the job running and count the attempt", testStartPublishJobAttempt},
+		{"StartPublishJobAttempt should return ErrPublishJobFinished or ErrPublishJobNotFound", testStartPublishJobAttemptFinished},
+		{"FinishPublishJobAttempt should record the outcome of the attempt", testFinishPublishJobAttempt},
 	} {
 
 		t.Run(tt.scenario, func(t *testing.T) {
@@ -177,7 +253,7 @@ func testGetCollectionsNone(t *testing.T, store *collections.PostgresStore, _ *f
 	// Test with store
 	limit, offset := 10, 0
 	// use a user with no collections
-	response, err := store.GetCollections(ctx, userstest.SeedUser1.ID, limit, offset)
+	response, err := store.GetCollections(ctx, userstest.SeedUser1.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, response.Limit)
@@ -212,7 +288,7 @@ func testGetCollections(t *testing.T, store *collections.PostgresStore, expectat
 
 	// Test with store
 	limit, offset := 10, 0
-	response, err := store.GetCollections(ctx, *user1.ID, limit, offset)
+	response, err := store.GetCollections(ctx, *user1.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, response.Limit)
@@ -235,7 +311,7 @@ func testGetCollections(t *testing.T, store *collections.PostgresStore, expectat
 	assert.Nil(t, actualCollection3.Publication)
 
 	// try user2's collections
-	user2CollectionResp, err := store.GetCollections(ctx, *user2.ID, limit, offset)
+	user2CollectionResp, err := store.GetCollections(ctx, *user2.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, user2CollectionResp.Limit)
@@ -283,7 +359,7 @@ func testGetCollectionsNoPerms(t *testing.T, store *collections.PostgresStore, e
 
 	// Test with store
 	limit, offset := 10, 0
-	response, err := store.GetCollections(ctx, *user1.ID, limit, offset)
+	response, err := store.GetCollections(ctx, *user1.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, response.Limit)
@@ -303,7 +379,7 @@ func testGetCollectionsNoPerms(t *testing.T, store *collections.PostgresStore, e
 	assertExpectedEqualCollectionSummary(t, user1CollectionFiveDOI, actualCollection3)
 
 	// try user2's collections
-	user2CollectionResp, err := store.GetCollections(ctx, *user2.ID, limit, offset)
+	user2CollectionResp, err := store.GetCollections(ctx, *user2.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, user2CollectionResp.Limit)
@@ -336,7 +412,7 @@ func testGetCollectionsLimitOffset(t *testing.T, store *collections.PostgresStor
 	offset := 0
 
 	for ; offset < totalCollections; offset += limit {
-		resp, err := store.GetCollections(ctx, *user1.ID, limit, offset)
+		resp, err := store.GetCollections(ctx, *user1.ID, limit, offset, collections.GetCollectionsQuery{})
 		require.NoError(t, err)
 
 		assert.Equal(t, limit, resp.Limit)
@@ -354,7 +430,7 @@ func testGetCollectionsLimitOffset(t *testing.T, store *collections.PostgresStor
 	// now offset >= totalCollections, so the response should have no collections
 	// but still have the correct TotalCount.
 
-	emptyResp, err := store.GetCollections(ctx, *user1.ID, limit, offset)
+	emptyResp, err := store.GetCollections(ctx, *user1.ID, limit, offset, collections.GetCollectionsQuery{})
 	require.NoError(t, err)
 
 	assert.Equal(t, limit, emptyResp.Limit)
@@ -364,6 +440,221 @@ func testGetCollectionsLimitOffset(t *testing.T, store *collections.PostgresStor
 
 }
 
+func testGetCollectionsFilters(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+	otherUser := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, otherUser)
+
+	searchTerm := uuid.NewString()
+	tag := uuid.NewString()
+
+	nameMatch := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).
+		WithLicense("MIT").
+		WithTags([]string{tag, uuid.NewString()})
+	nameMatch.Name = fmt.Sprintf("The %s Collection", strings.ToUpper(searchTerm))
+	expectationDB.CreateCollection(ctx, t, nameMatch)
+	expectationDB.CreatePublishStatus(ctx, t, collectionstest.NewCompletedPublishStatus(*nameMatch.ID, *user.ID))
+
+	descriptionMatch := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).
+		WithLicense("Apache 2.0").
+		WithDescription(fmt.Sprintf("about %s", searchTerm))
+	expectationDB.CreateCollection(ctx, t, descriptionMatch)
+
+	viewerCollection := apitest.NewExpectedCollection().WithNodeID().
+		WithUser(*otherUser.ID, pgdb.Owner).
+		WithUser(*user.ID, pgdb.Read).
+		WithTags([]string{tag})
+	expectationDB.CreateCollection(ctx, t, viewerCollection)
+
+	// should never be returned since user has no role on it
+	otherUserCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*otherUser.ID, pgdb.Owner).
+		WithDescription(searchTerm).
+		WithTags([]string{tag})
+	expectationDB.CreateCollection(ctx, t, otherUserCollection)
+
+	completed := publishing.CompletedStatus
+	draft := publishing.DraftStatus
+	viewer := role.Viewer
+	owner := role.Owner
+
+	for _, tt := range []struct {
+		scenario string
+		query    collections.GetCollectionsQuery
+		expected []*apitest.ExpectedCollection
+	}{
+		{"text", collections.GetCollectionsQuery{Text: searchTerm}, []*apitest.ExpectedCollection{nameMatch, descriptionMatch}},
+		{"text wildcards are literal", collections.GetCollectionsQuery{Text: "%"}, nil},
+		{"tag", collections.GetCollectionsQuery{Tag: tag}, []*apitest.ExpectedCollection{nameMatch, viewerCollection}},
+		{"license", collections.GetCollectionsQuery{License: "Apache 2.0"}, []*apitest.ExpectedCollection{descriptionMatch}},
+		{"completed", collections.GetCollectionsQuery{PublicationStatus: &completed}, []*apitest.ExpectedCollection{nameMatch}},
+		{"draft", collections.GetCollectionsQuery{PublicationStatus: &draft}, []*apitest.ExpectedCollection{descriptionMatch, viewerCollection}},
+		{"viewer role", collections.GetCollectionsQuery{Role: &viewer}, []*apitest.ExpectedCollection{viewerCollection}},
+		{"owner role and tag", collections.GetCollectionsQuery{Role: &owner, Tag: tag}, []*apitest.ExpectedCollection{nameMatch}},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			response, err := store.GetCollections(ctx, *user.ID, 10, 0, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), response.TotalCount)
+			var actualNodeIDs, expectedNodeIDs []string
+			for _, actual := range response.Collections {
+				actualNodeIDs = append(actualNodeIDs, actual.NodeID)
+			}
+			for _, expected := range tt.expected {
+				expectedNodeIDs = append(expectedNodeIDs, *expected.NodeID)
+			}
+			assert.Equal(t, expectedNodeIDs, actualNodeIDs)
+
+			// total count should still reflect filter when page is empty
+			emptyResponse, err := store.GetCollections(ctx, *user.ID, 10, 10, tt.query)
+			require.NoError(t, err)
+			assert.Empty(t, emptyResponse.Collections)
+			assert.Equal(t, len(tt.expected), emptyResponse.TotalCount)
+		})
+	}
+}
+
+func testGetCollectionsSort(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	// created in this order, so this is also createdAt and updatedAt order
+	b := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(2)
+	b.Name = "b"
+	expectationDB.CreateCollection(ctx, t, b)
+	a := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	a.Name = "A"
+	expectationDB.CreateCollection(ctx, t, a)
+	c := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(1)
+	c.Name = "c"
+	expectationDB.CreateCollection(ctx, t, c)
+
+	for _, tt := range []struct {
+		sortBy    collections.SortField
+		direction collections.SortDirection
+		expected  []*apitest.ExpectedCollection
+	}{
+		{"", "", []*apitest.ExpectedCollection{b, a, c}},
+		{collections.SortByName, "", []*apitest.ExpectedCollection{a, b, c}},
+		{collections.SortByName, collections.Descending, []*apitest.ExpectedCollection{c, b, a}},
+		{collections.SortByCreatedAt, collections.Descending, []*apitest.ExpectedCollection{c, a, b}},
+		{collections.SortByUpdatedAt, collections.Ascending, []*apitest.ExpectedCollection{b, a, c}},
+		{collections.SortBySize, collections.Ascending, []*apitest.ExpectedCollection{a, c, b}},
+		{collections.SortBySize, collections.Descending, []*apitest.ExpectedCollection{b, c, a}},
+	} {
+		t.Run(fmt.Sprintf("%s %s", tt.sortBy, tt.direction), func(t *testing.T) {
+			response, err := store.GetCollections(ctx, *user.ID, 10, 0, collections.GetCollectionsQuery{SortBy: tt.sortBy, SortDirection: tt.direction})
+			require.NoError(t, err)
+			require.Len(t, response.Collections, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.Equal(t, *expected.NodeID, response.Collections[i].NodeID)
+			}
+		})
+	}
+
+	_, err := store.GetCollections(ctx, *user.ID, 10, 0, collections.GetCollectionsQuery{SortBy: "nodeId"})
+	assert.Error(t, err)
+}
+
+func testGetCollectionsCursor(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	// two collections per size so that ties are broken by id
+	totalCollections := 7
+	var expectedNodeIDs []string
+	for i := 0; i < totalCollections; i++ {
+		expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(i / 2)
+		expectationDB.CreateCollection(ctx, t, expectedCollection)
+		expectedNodeIDs = append(expectedNodeIDs, *expectedCollection.NodeID)
+	}
+
+	for _, query := range []collections.GetCollectionsQuery{
+		{},
+		{SortBy: collections.SortBySize},
+		{SortBy: collections.SortByCreatedAt},
+		{SortBy: collections.SortByName},
+		{SortBy: collections.SortByUpdatedAt},
+	} {
+		for _, direction := range []collections.SortDirection{collections.Ascending, collections.Descending} {
+			query.SortDirection = direction
+			t.Run(fmt.Sprintf("%s %s", query.SortBy, query.SortDirection), func(t *testing.T) {
+				allResponse, err := store.GetCollections(ctx, *user.ID, totalCollections, 0, query)
+				require.NoError(t, err)
+				require.Len(t, allResponse.Collections, totalCollections)
+				assert.Nil(t, allResponse.NextCursor)
+
+				limit := 3
+				var pagedNodeIDs []string
+				pages := 0
+				for {
+					resp, err := store.GetCollections(ctx, *user.ID, limit, 0, query)
+					require.NoError(t, err)
+					assert.Equal(t, totalCollections, resp.TotalCount)
+					for _, c := range resp.Collections {
+						pagedNodeIDs = append(pagedNodeIDs, c.NodeID)
+					}
+					pages++
+					if resp.NextCursor == nil {
+						break
+					}
+					require.Len(t, resp.Collections, limit)
+					query.After = resp.NextCursor
+				}
+				query.After = nil
+				assert.Equal(t, 3, pages)
+
+				var allNodeIDs []string
+				for _, c := range allResponse.Collections {
+					allNodeIDs = append(allNodeIDs, c.NodeID)
+				}
+				assert.Equal(t, allNodeIDs, pagedNodeIDs)
+				assert.ElementsMatch(t, expectedNodeIDs, pagedNodeIDs)
+			})
+		}
+	}
+}
+
+func testGetCollectionsCursorConcurrentCreate(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	var expectedNodeIDs []string
+	for i := 0; i < 4; i++ {
+		expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+		expectationDB.CreateCollection(ctx, t, expectedCollection)
+		expectedNodeIDs = append(expectedNodeIDs, *expectedCollection.NodeID)
+	}
+
+	// newest first, so new collections would shift offsets
+	query := collections.GetCollectionsQuery{SortBy: collections.SortByCreatedAt, SortDirection: collections.Descending}
+	firstPage, err := store.GetCollections(ctx, *user.ID, 2, 0, query)
+	require.NoError(t, err)
+	require.Len(t, firstPage.Collections, 2)
+	require.NotNil(t, firstPage.NextCursor)
+
+	newCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, newCollection)
+
+	query.After = firstPage.NextCursor
+	secondPage, err := store.GetCollections(ctx, *user.ID, 2, 0, query)
+	require.NoError(t, err)
+	require.Len(t, secondPage.Collections, 2)
+	assert.Nil(t, secondPage.NextCursor)
+	assert.Equal(t, 5, secondPage.TotalCount)
+
+	assert.Equal(t, expectedNodeIDs[1], secondPage.Collections[0].NodeID)
+	assert.Equal(t, expectedNodeIDs[0], secondPage.Collections[1].NodeID)
+}
+
 func testGetCollectionNone(t *testing.T, collectionsStore *collections.PostgresStore, _ *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
@@ -512,16 +803,36 @@ func testDeleteCollection(t *testing.T, store *collections.PostgresStore, expect
 	user2Collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user2.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI(), apitest.NewPennsieveDOI())
 	user2Resp := expectationDB.CreateCollection(ctx, t, user2Collection)
 
-	require.NoError(t, store.DeleteCollection(ctx, idToDelete))
+	require.NoError(t, store.DeleteCollection(ctx, idToDelete, *user1.ID, nil))
 
-	expectationDB.RequireNoCollection(ctx, t, idToDelete)
+	expectationDB.RequireDeletedCollection(ctx, t, user1CollectionDelete, idToDelete)
 	expectationDB.RequireCollection(ctx, t, user1CollectionKeep, keepResp.ID)
 	expectationDB.RequireCollection(ctx, t, user2Collection, user2Resp.ID)
 }
 
+func testDeleteCollectionExpectedVersion(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	current, err := store.GetCollection(ctx, *user.ID, *collection.NodeID)
+	require.NoError(t, err)
+
+	staleVersion := current.Version - 1
+	require.ErrorIs(t, store.DeleteCollection(ctx, collectionID, *user.ID, &staleVersion), collections.ErrCollectionVersionMismatch)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+
+	require.NoError(t, store.DeleteCollection(ctx, collectionID, *user.ID, &current.Version))
+	expectationDB.RequireDeletedCollection(ctx, t, collection, collectionID)
+}
+
 func testDeleteCollectionNonExistent(t *testing.T, collectionsStore *collections.PostgresStore, _ *fixtures.ExpectationDB) {
 	nonExistentCollectionID := int64(99999)
-	err := collectionsStore.DeleteCollection(context.Background(), nonExistentCollectionID)
+	err := collectionsStore.DeleteCollection(context.Background(), nonExistentCollectionID, userstest.SeedUser1.ID, nil)
 	require.ErrorIs(t, err, collections.ErrCollectionNotFound)
 }
 
@@ -753,6 +1064,48 @@ func testUpdateCollectionAddDOI(t *testing.T, collectionsStore *collections.Post
 
 }
 
+func testUpdateCollectionAnnotateDOIs(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi1.Label = "Primary"
+	doi1.Note = "Used in figure 1"
+	doi2 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+	collectionID := createResp.ID
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+
+	doiToAdd := apitest.NewPennsieveDOI()
+	doiToAdd.Label = "Control"
+
+	emptyNote := ""
+	doi2Label := "Secondary"
+	update := collections.UpdateCollectionRequest{
+		DOIs: collections.DOIUpdate{
+			Add: []collections.DOI{doiToAdd},
+			Annotate: []collections.DOIAnnotation{
+				{DOI: doi1.Value, Note: &emptyNote},
+				{DOI: doi2.Value, Label: &doi2Label},
+			},
+		},
+	}
+	updatedCollection, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, update)
+	require.NoError(t, err)
+
+	doi1.Note = ""
+	doi2.Label = doi2Label
+	expectedCollection.SetDOIs(doi1, doi2, doiToAdd)
+	assertExpectedEqualCollectionBase(t, expectedCollection, updatedCollection.CollectionBase)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), updatedCollection.DOIs)
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+}
+
 func testUpdateCollectionAddDOIs(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
@@ -947,6 +1300,75 @@ func testUpdateCollectionNonExistentDOIUpdateOnly(t *testing.T, collectionsStore
 	require.ErrorIs(t, err, collections.ErrCollectionNotFound)
 }
 
+func testReorderDOIs(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	doi3 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2, doi3)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+	collectionID := createResp.ID
+
+	updatedCollection, err := collectionsStore.ReorderDOIs(ctx, *user.ID, collectionID, []string{doi3.Value, doi1.Value, doi2.Value})
+	require.NoError(t, err)
+
+	expectedCollection.SetDOIs(doi3, doi1, doi2)
+	assertExpectedEqualCollectionBase(t, expectedCollection, updatedCollection.CollectionBase)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), updatedCollection.DOIs)
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), collection.DOIs)
+
+	summaries, err := collectionsStore.GetCollections(ctx, *user.ID, 10, 0, collections.GetCollectionsQuery{})
+	require.NoError(t, err)
+	require.Len(t, summaries.Collections, 1)
+	assert.Equal(t, expectedCollection.DOIs.Strings(), summaries.Collections[0].BannerDOIs)
+}
+
+func testReorderDOIsMismatch(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	doi3 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2, doi3)
+	createResp := expectationDB.CreateCollection(ctx, t, expectedCollection)
+	collectionID := createResp.ID
+
+	for name, dois := range map[string][]string{
+		"missing":   {doi3.Value, doi1.Value},
+		"unknown":   {doi3.Value, doi1.Value, doi2.Value, apitest.NewPennsieveDOI().Value},
+		"duplicate": {doi3.Value, doi1.Value, doi3.Value},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := collectionsStore.ReorderDOIs(ctx, *user.ID, collectionID, dois)
+			require.ErrorIs(t, err, collections.ErrDOIOrderMismatch)
+
+			collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+			require.NoError(t, err)
+			assert.Equal(t, expectedCollection.DOIs.AsDOIs(), collection.DOIs)
+		})
+	}
+}
+
+func testReorderDOIsNonExistent(t *testing.T, collectionsStore *collections.PostgresStore, _ *fixtures.ExpectationDB) {
+	nonExistentCollectionID := int64(99999)
+	_, err := collectionsStore.ReorderDOIs(context.Background(), userstest.SeedUser1.ID, nonExistentCollectionID, []string{apitest.NewPennsieveDOI().Value})
+	require.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
 func testStartPublish(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
@@ -956,7 +1378,7 @@ func testStartPublish(t *testing.T, collectionsStore *collections.PostgresStore,
 	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
 	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
 
-	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType))
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
 
 	expectedPublishStatus := collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID)
 	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, nil)
@@ -973,7 +1395,7 @@ func testStartPublishExistingInProgress(t *testing.T, collectionsStore *collecti
 	existingPublishStatus := collectionstest.NewInProgressPublishStatus(collectionID, *user.ID)
 	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
 
-	err := collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType)
+	err := collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil)
 	require.ErrorIs(t, err, collections.ErrPublishInProgress)
 
 	expectedPublishStatus := collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID)
@@ -996,7 +1418,7 @@ func testStartPublishExistingComplete(t *testing.T, collectionsStore *collection
 
 	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
 
-	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType))
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
 
 	expectedPublishStatus := collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID)
 	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, &existingPublishStatus)
@@ -1017,12 +1439,78 @@ func testStartPublishExistingFailed(t *testing.T, collectionsStore *collections.
 
 	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
 
-	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType))
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
 
 	expectedPublishStatus := collectionstest.NewInProgressPublishStatus(collectionID, *user.ID)
 	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, &existingPublishStatus)
 }
 
+func testStartPublishExistingStaleInProgress(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	oldUser := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, oldUser)
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	existingPublishStatus := collectionstest.NewStaleInProgressPublishStatus(collectionID, *oldUser.ID)
+	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
+
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.RevisionType, nil))
+
+	expectedPublishStatus := collectionstest.NewPublishStatusBuilder(collectionID, publishing.RevisionType, publishing.InProgressStatus).
+		WithUserID(user.ID).
+		Build()
+	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, &existingPublishStatus)
+}
+
+func testStartPublishExpectedVersion(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	current, err := collectionsStore.GetCollection(ctx, *user.ID, *collection.NodeID)
+	require.NoError(t, err)
+
+	staleVersion := current.Version - 1
+	require.ErrorIs(t,
+		collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, &staleVersion),
+		collections.ErrCollectionVersionMismatch)
+	expectationDB.RequireNoPublishStatus(ctx, t, collectionID)
+
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, &current.Version))
+	expectationDB.RequirePublishStatus(ctx, t, collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID), nil)
+}
+
+func testStartPublishWithPublishTimeout(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	// started a minute ago, so not stale with the default timeout
+	existingPublishStatus := collectionstest.NewInProgressPublishStatus(collectionID, *user.ID)
+	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
+
+	require.ErrorIs(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil), collections.ErrPublishInProgress)
+
+	require.NoError(t, collectionsStore.WithPublishTimeout(30*time.Second).StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
+
+	expectedPublishStatus := collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID)
+	actual := expectationDB.GetPublishStatus(ctx, t, collectionID)
+	assert.Equal(t, expectedPublishStatus.Status, actual.Status)
+	assert.True(t, actual.StartedAt.After(existingPublishStatus.StartedAt.Add(time.Second)))
+}
+
 func testFinishPublish(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
 	ctx := context.Background()
 
@@ -1051,28 +1539,1899 @@ func testFinishPublishNoExistingStatus(t *testing.T, collectionsStore *collectio
 	expectationDB.RequireNoPublishStatus(ctx, t, collectionID)
 }
 
-func assertExpectedEqualCollectionBase(t *testing.T, expected *apitest.ExpectedCollection, actual collections.CollectionBase) {
-	t.Helper()
-	assert.Equal(t, *expected.NodeID, actual.NodeID)
-	assert.Equal(t, expected.Name, actual.Name)
-	assert.Equal(t, expected.Description, actual.Description)
-	assert.Equal(t, expected.License, actual.License)
-	assert.Equal(t, expected.Tags, actual.Tags)
-	assert.Equal(t, expected.Users[0].PermissionBit.ToRole(), actual.UserRole)
-	assert.Equal(t, len(expected.DOIs), actual.Size)
+func testGetStalePublishes(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	newCollectionID := func() int64 {
+		collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+		return expectationDB.CreateCollection(ctx, t, collection).ID
+	}
+
+	oldestStaleCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	oldestStaleCollectionID := expectationDB.CreateCollection(ctx, t, oldestStaleCollection).ID
+	oldestStale := collectionstest.NewPublishStatusBuilder(oldestStaleCollectionID, publishing.RemovalType, publishing.InProgressStatus).
+		WithStartedAt(time.Now().UTC().AddDate(0, -2, 0)).
+		WithUserID(user.ID).
+		Build()
+	expectationDB.CreatePublishStatus(ctx, t, oldestStale)
+
+	stale := collectionstest.NewStaleInProgressPublishStatus(newCollectionID(), *user.ID)
+	expectationDB.CreatePublishStatus(ctx, t, stale)
+
+	// not stale
+	expectationDB.CreatePublishStatus(ctx, t, collectionstest.NewInProgressPublishStatus(newCollectionID(), *user.ID))
+	// not in progress
+	expectationDB.CreatePublishStatus(ctx, t, collectionstest.NewFailedPublishStatus(newCollectionID(), *user.ID))
+
+	stalePublishes, err := collectionsStore.GetStalePublishes(ctx, time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stalePublishes, 2)
+
+	// oldest first
+	assert.Equal(t, oldestStaleCollectionID, stalePublishes[0].CollectionID)
+	assert.Equal(t, *oldestStaleCollection.NodeID, stalePublishes[0].CollectionNodeID)
+	assert.Equal(t, publishing.RemovalType, stalePublishes[0].Type)
+	assert.Equal(t, user.ID, stalePublishes[0].UserID)
+	assert.WithinDuration(t, oldestStale.StartedAt, stalePublishes[0].StartedAt, time.Second)
+
+	assert.Equal(t, stale.CollectionID, stalePublishes[1].CollectionID)
+	assert.Equal(t, publishing.PublicationType, stalePublishes[1].Type)
 }
 
-func assertExpectedPublishStatusEqual(t *testing.T, expected collections.PublishStatus, actual collections.CollectionBase) {
-	t.Helper()
-	require.NotNil(t, actual.Publication)
-	assert.Equal(t, expected.Type, actual.Publication.Type)
-	assert.Equal(t, expected.Status, actual.Publication.Status)
+func testFinishStalePublish(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	existingPublishStatus := collectionstest.NewStaleInProgressPublishStatus(collectionID, *user.ID)
+	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
+
+	stalePublishes, err := collectionsStore.GetStalePublishes(ctx, time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stalePublishes, 1)
+
+	finished, err := collectionsStore.FinishStalePublish(ctx, stalePublishes[0], publishing.FailedStatus)
+	require.NoError(t, err)
+	assert.True(t, finished)
+
+	expectedPublishStatus := collectionstest.NewExpectedFailedPublishStatus(collectionID, *user.ID)
+	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, &existingPublishStatus)
+	actual := expectationDB.GetPublishStatus(ctx, t, collectionID)
+	assert.WithinDuration(t, existingPublishStatus.StartedAt, actual.StartedAt, time.Second)
+
+	// already finished
+	finished, err = collectionsStore.FinishStalePublish(ctx, stalePublishes[0], publishing.CompletedStatus)
+	require.NoError(t, err)
+	assert.False(t, finished)
+	expectationDB.RequirePublishStatus(ctx, t, expectedPublishStatus, &existingPublishStatus)
 }
 
-func assertExpectedEqualCollectionSummary(t *testing.T, expected *apitest.ExpectedCollection, actual collections.CollectionSummary) {
-	t.Helper()
-	assertExpectedEqualCollectionBase(t, expected, actual.CollectionBase)
-	bannerLen := min(collections.MaxBannerDOIsPerCollection, len(expected.DOIs))
-	// Sometimes the order of DOI ids (used to return banners and dois) does not match the order they were inserted unfortunately
-	assert.ElementsMatch(t, expected.DOIs.Strings()[:bannerLen], actual.BannerDOIs)
+func testFinishStalePublishReplaced(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	existingPublishStatus := collectionstest.NewStaleInProgressPublishStatus(collectionID, *user.ID)
+	expectationDB.CreatePublishStatus(ctx, t, existingPublishStatus)
+
+	stalePublishes, err := collectionsStore.GetStalePublishes(ctx, time.Now().UTC().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stalePublishes, 1)
+
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
+	replacement := expectationDB.GetPublishStatus(ctx, t, collectionID)
+
+	finished, err := collectionsStore.FinishStalePublish(ctx, stalePublishes[0], publishing.FailedStatus)
+	require.NoError(t, err)
+	assert.False(t, finished)
+
+	expectationDB.RequirePublishStatus(ctx, t, collectionstest.NewExpectedInProgressPublishStatus(collectionID, *user.ID), &replacement)
+}
+
+func testGetCollectionMembers(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, owner)
+	editor := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, editor)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*editor.ID, pgdb.Delete)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	otherCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*nonMember.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, otherCollection)
+
+	members, err := collectionsStore.GetCollectionMembers(ctx, collectionID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	// ordered by user id
+	ownerMember := members[0]
+	assert.Equal(t, *owner.ID, ownerMember.UserID)
+	assert.Equal(t, owner.NodeID, ownerMember.UserNodeID)
+	assert.Equal(t, owner.FirstName, ownerMember.FirstName)
+	assert.Equal(t, owner.LastName, ownerMember.LastName)
+	assert.Equal(t, role.Owner, ownerMember.Role)
+
+	editorMember := members[1]
+	assert.Equal(t, *editor.ID, editorMember.UserID)
+	assert.Equal(t, editor.NodeID, editorMember.UserNodeID)
+	assert.Equal(t, editor.FirstName, editorMember.FirstName)
+	assert.Nil(t, editorMember.LastName)
+	assert.Equal(t, role.Editor, editorMember.Role)
+}
+
+func testPutCollectionMemberAdd(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	newMember := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, newMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	member, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, newMember.NodeID, role.Viewer)
+	require.NoError(t, err)
+	assert.Equal(t, *newMember.ID, member.UserID)
+	assert.Equal(t, newMember.NodeID, member.UserNodeID)
+	assert.Equal(t, newMember.FirstName, member.FirstName)
+	assert.Equal(t, newMember.LastName, member.LastName)
+	assert.Equal(t, role.Viewer, member.Role)
+
+	collection.WithUser(*newMember.ID, pgdb.Read)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberChangeRole(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	existingMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, existingMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*existingMember.ID, pgdb.Delete)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	member, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, existingMember.NodeID, role.Manager)
+	require.NoError(t, err)
+	assert.Equal(t, *existingMember.ID, member.UserID)
+	assert.Equal(t, role.Manager, member.Role)
+
+	collection.SetUser(*existingMember.ID, pgdb.Administer)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberUserNotFound(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, userstest.NewTestUser().NodeID, role.Viewer)
+	require.ErrorIs(t, err, collections.ErrUserNotFound)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberCollectionNotFound(t *testing.T, collectionsStore *collections.PostgresStore, _ *fixtures.ExpectationDB) {
+	nonExistentCollectionID := int64(99999)
+	_, err := collectionsStore.PutCollectionMember(context.Background(), nonExistentCollectionID, userstest.SeedUser1.ID, userstest.SeedUser2.NodeID, role.Viewer)
+	require.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
+func testPutCollectionMemberLastOwner(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, owner.NodeID, role.Manager)
+	require.ErrorIs(t, err, collections.ErrLastOwner)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionMemberDemoteOneOfTwoOwners(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	otherOwner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, otherOwner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*otherOwner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	member, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, owner.NodeID, role.Editor)
+	require.NoError(t, err)
+	assert.Equal(t, role.Editor, member.Role)
+
+	collection.SetUser(*owner.ID, pgdb.Delete)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMember(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	viewer := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, viewer)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*viewer.ID, pgdb.Read)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	require.NoError(t, collectionsStore.DeleteCollectionMember(ctx, collectionID, *owner.ID, viewer.NodeID))
+
+	collection.RemoveUser(*viewer.ID)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMemberNotMember(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	err := collectionsStore.DeleteCollectionMember(ctx, collectionID, *owner.ID, nonMember.NodeID)
+	require.ErrorIs(t, err, collections.ErrCollectionMemberNotFound)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testDeleteCollectionMemberLastOwner(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	err := collectionsStore.DeleteCollectionMember(ctx, collectionID, *owner.ID, owner.NodeID)
+	require.ErrorIs(t, err, collections.ErrLastOwner)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testTransferOwnership(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	newOwner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, newOwner)
+	viewer := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, viewer)
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithUser(*newOwner.ID, pgdb.Delete).
+		WithUser(*viewer.ID, pgdb.Read).
+		WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	require.NoError(t, collectionsStore.TransferOwnership(ctx, collectionID, *owner.ID, newOwner.NodeID))
+
+	collection.SetUser(*owner.ID, pgdb.Administer).SetUser(*newOwner.ID, pgdb.Owner)
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testTransferOwnershipNotMember(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	err := collectionsStore.TransferOwnership(ctx, collectionID, *owner.ID, nonMember.NodeID)
+	require.ErrorIs(t, err, collections.ErrCollectionMemberNotFound)
+
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testTransferOwnershipNotOwner(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+	editor := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, editor)
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithUser(*manager.ID, pgdb.Administer).
+		WithUser(*editor.ID, pgdb.Delete)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	err := collectionsStore.TransferOwnership(ctx, collectionID, *manager.ID, editor.NodeID)
+	require.ErrorIs(t, err, collections.ErrNotOwner)
+
+	// the promotion of editor should have been rolled back
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testGetCollectionsTeamsAndOrganizations(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	teamID, _ := expectationDB.CreateTestTeam(ctx, t, *user.ID)
+	organizationID, _ := expectationDB.CreateTestOrganization(ctx, t, map[int64]pgdb.DbPermission{*user.ID: pgdb.Read})
+
+	teamCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	teamCollectionID := expectationDB.CreateCollection(ctx, t, teamCollection).ID
+	expectationDB.AddCollectionTeam(ctx, t, teamCollectionID, teamID, pgdb.Delete)
+
+	organizationCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	organizationCollectionID := expectationDB.CreateCollection(ctx, t, organizationCollection).ID
+	expectationDB.AddCollectionOrganization(ctx, t, organizationCollectionID, organizationID, pgdb.Read)
+
+	// shared with both the team and the organization, so should only be returned once
+	bothCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	bothCollectionID := expectationDB.CreateCollection(ctx, t, bothCollection).ID
+	expectationDB.AddCollectionTeam(ctx, t, bothCollectionID, teamID, pgdb.Guest)
+	expectationDB.AddCollectionOrganization(ctx, t, bothCollectionID, organizationID, pgdb.Administer)
+
+	unsharedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, unsharedCollection)
+
+	response, err := collectionsStore.GetCollections(ctx, *user.ID, 10, 0, collections.GetCollectionsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.TotalCount)
+	require.Len(t, response.Collections, 3)
+
+	assert.Equal(t, *teamCollection.NodeID, response.Collections[0].NodeID)
+	assert.Equal(t, role.Editor, response.Collections[0].UserRole)
+	assert.Equal(t, 1, response.Collections[0].Size)
+
+	assert.Equal(t, *organizationCollection.NodeID, response.Collections[1].NodeID)
+	assert.Equal(t, role.Viewer, response.Collections[1].UserRole)
+
+	assert.Equal(t, *bothCollection.NodeID, response.Collections[2].NodeID)
+	assert.Equal(t, role.Manager, response.Collections[2].UserRole)
+
+	// recount path
+	response, err = collectionsStore.GetCollections(ctx, *user.ID, 10, 3, collections.GetCollectionsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.TotalCount)
+	assert.Empty(t, response.Collections)
+}
+
+func testGetCollectionEffectiveRole(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	teamID, _ := expectationDB.CreateTestTeam(ctx, t, *user.ID)
+	organizationID, _ := expectationDB.CreateTestOrganization(ctx, t, map[int64]pgdb.DbPermission{*user.ID: pgdb.Administer})
+
+	collection := apitest.NewExpectedCollection().
+		WithNodeID().
+		WithUser(*owner.ID, pgdb.Owner).
+		WithUser(*user.ID, pgdb.Guest).
+		WithDOIs(apitest.NewPennsieveDOI(), apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	actual, err := collectionsStore.GetCollection(ctx, *user.ID, *collection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Guest, actual.UserRole)
+
+	expectationDB.AddCollectionOrganization(ctx, t, collectionID, organizationID, pgdb.Read)
+	actual, err = collectionsStore.GetCollection(ctx, *user.ID, *collection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Viewer, actual.UserRole)
+
+	expectationDB.AddCollectionTeam(ctx, t, collectionID, teamID, pgdb.Delete)
+	actual, err = collectionsStore.GetCollection(ctx, *user.ID, *collection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, role.Editor, actual.UserRole)
+	// each DOI should only be returned once no matter how many grants apply
+	assert.Len(t, actual.DOIs, 2)
+	assert.Equal(t, 2, actual.Size)
+}
+
+func testGetCollectionOrganizationGuest(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	guest := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, guest)
+
+	organizationID, _ := expectationDB.CreateTestOrganization(ctx, t, map[int64]pgdb.DbPermission{*guest.ID: pgdb.Guest})
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.AddCollectionOrganization(ctx, t, collectionID, organizationID, pgdb.Read)
+
+	_, err := collectionsStore.GetCollection(ctx, *guest.ID, *collection.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+
+	response, err := collectionsStore.GetCollections(ctx, *guest.ID, 10, 0, collections.GetCollectionsQuery{})
+	require.NoError(t, err)
+	assert.Zero(t, response.TotalCount)
+}
+
+func testGetCollectionGrants(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	teamID, teamNodeID := expectationDB.CreateTestTeam(ctx, t)
+	organizationID, organizationNodeID := expectationDB.CreateTestOrganization(ctx, t, nil)
+	otherTeamID, _ := expectationDB.CreateTestTeam(ctx, t)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.AddCollectionTeam(ctx, t, collectionID, teamID, pgdb.Delete)
+	expectationDB.AddCollectionOrganization(ctx, t, collectionID, organizationID, pgdb.Read)
+
+	otherCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	otherCollectionID := expectationDB.CreateCollection(ctx, t, otherCollection).ID
+	expectationDB.AddCollectionTeam(ctx, t, otherCollectionID, otherTeamID, pgdb.Read)
+
+	grants, err := collectionsStore.GetCollectionGrants(ctx, collectionID)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+
+	// ordered by grantee type
+	organizationGrant := grants[0]
+	assert.Equal(t, collections.OrganizationGrantee, organizationGrant.GranteeType)
+	assert.Equal(t, organizationID, organizationGrant.GranteeID)
+	assert.Equal(t, organizationNodeID, organizationGrant.GranteeNodeID)
+	assert.NotEmpty(t, organizationGrant.Name)
+	assert.Equal(t, role.Viewer, organizationGrant.Role)
+
+	teamGrant := grants[1]
+	assert.Equal(t, collections.TeamGrantee, teamGrant.GranteeType)
+	assert.Equal(t, teamID, teamGrant.GranteeID)
+	assert.Equal(t, teamNodeID, teamGrant.GranteeNodeID)
+	assert.NotEmpty(t, teamGrant.Name)
+	assert.Equal(t, role.Editor, teamGrant.Role)
+}
+
+func testPutCollectionGrant(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	teamID, teamNodeID := expectationDB.CreateTestTeam(ctx, t)
+	organizationID, organizationNodeID := expectationDB.CreateTestOrganization(ctx, t, nil)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	teamGrant, err := collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Viewer)
+	require.NoError(t, err)
+	assert.Equal(t, teamID, teamGrant.GranteeID)
+	assert.Equal(t, teamNodeID, teamGrant.GranteeNodeID)
+	assert.Equal(t, role.Viewer, teamGrant.Role)
+
+	organizationGrant, err := collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.OrganizationGrantee, organizationNodeID, role.Guest)
+	require.NoError(t, err)
+	assert.Equal(t, organizationID, organizationGrant.GranteeID)
+	assert.Equal(t, role.Guest, organizationGrant.Role)
+
+	_, err = collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Manager)
+	require.NoError(t, err)
+
+	grants, err := collectionsStore.GetCollectionGrants(ctx, collectionID)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+	assert.Equal(t, role.Guest, grants[0].Role)
+	assert.Equal(t, role.Manager, grants[1].Role)
+
+	// direct members are unaffected
+	expectationDB.RequireCollection(ctx, t, collection, collectionID)
+}
+
+func testPutCollectionGrantNotFound(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, uuid.NewString(), role.Viewer)
+	require.ErrorIs(t, err, collections.ErrGranteeNotFound)
+}
+
+func testPutCollectionGrantAboveMax(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	_, teamNodeID := expectationDB.CreateTestTeam(ctx, t)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Owner)
+	require.Error(t, err)
+
+	grants, err := collectionsStore.GetCollectionGrants(ctx, collectionID)
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func testDeleteCollectionGrant(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	teamID, teamNodeID := expectationDB.CreateTestTeam(ctx, t, *user.ID)
+	organizationID, _ := expectationDB.CreateTestOrganization(ctx, t, nil)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+	expectationDB.AddCollectionTeam(ctx, t, collectionID, teamID, pgdb.Read)
+	expectationDB.AddCollectionOrganization(ctx, t, collectionID, organizationID, pgdb.Read)
+
+	require.NoError(t, collectionsStore.DeleteCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID))
+
+	grants, err := collectionsStore.GetCollectionGrants(ctx, collectionID)
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, collections.OrganizationGrantee, grants[0].GranteeType)
+
+	_, err = collectionsStore.GetCollection(ctx, *user.ID, *collection.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
+func testDeleteCollectionGrantNotFound(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	_, teamNodeID := expectationDB.CreateTestTeam(ctx, t)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	err := collectionsStore.DeleteCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID)
+	require.ErrorIs(t, err, collections.ErrCollectionGrantNotFound)
+}
+
+func assertExpectedEqualCollectionBase(t *testing.T, expected *apitest.ExpectedCollection, actual collections.CollectionBase) {
+	t.Helper()
+	assert.Equal(t, *expected.NodeID, actual.NodeID)
+	assert.Equal(t, expected.Name, actual.Name)
+	assert.Equal(t, expected.Description, actual.Description)
+	assert.Equal(t, expected.License, actual.License)
+	assert.Equal(t, expected.Tags, actual.Tags)
+	assert.Equal(t, expected.Users[0].PermissionBit.ToRole(), actual.UserRole)
+	assert.Equal(t, len(expected.DOIs), actual.Size)
+}
+
+func assertExpectedPublishStatusEqual(t *testing.T, expected collections.PublishStatus, actual collections.CollectionBase) {
+	t.Helper()
+	require.NotNil(t, actual.Publication)
+	assert.Equal(t, expected.Type, actual.Publication.Type)
+	assert.Equal(t, expected.Status, actual.Publication.Status)
+	assert.False(t, actual.Publication.StartedAt.IsZero())
+}
+
+func assertExpectedEqualCollectionSummary(t *testing.T, expected *apitest.ExpectedCollection, actual collections.CollectionSummary) {
+	t.Helper()
+	assertExpectedEqualCollectionBase(t, expected, actual.CollectionBase)
+	bannerLen := min(collections.MaxBannerDOIsPerCollection, len(expected.DOIs))
+	// Sometimes the order of DOI ids (used to return banners and dois) does not match the order they were inserted unfortunately
+	assert.ElementsMatch(t, expected.DOIs.Strings()[:bannerLen], actual.BannerDOIs)
+}
+
+func testDeletedCollectionHidden(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+	member := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, member)
+
+	deleted := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(2)
+	deletedID := expectationDB.CreateCollection(ctx, t, deleted).ID
+	kept := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, kept)
+
+	require.NoError(t, store.DeleteCollection(ctx, deletedID, *user.ID, nil))
+
+	getCollectionsResp, err := store.GetCollections(ctx, *user.ID, 10, 0, collections.GetCollectionsQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, getCollectionsResp.TotalCount)
+	require.Len(t, getCollectionsResp.Collections, 1)
+	assert.Equal(t, *kept.NodeID, getCollectionsResp.Collections[0].NodeID)
+
+	_, err = store.GetCollection(ctx, *user.ID, *deleted.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+
+	newName := uuid.NewString()
+	_, err = store.UpdateCollection(ctx, *user.ID, deletedID, collections.UpdateCollectionRequest{Name: &newName})
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+
+	_, err = store.PutCollectionMember(ctx, deletedID, *user.ID, member.NodeID, role.Viewer)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+
+	// deleting again is an error
+	assert.ErrorIs(t, store.DeleteCollection(ctx, deletedID, *user.ID, nil), collections.ErrCollectionNotFound)
+
+	expectationDB.RequireDeletedCollection(ctx, t, deleted, deletedID)
+}
+
+func testGetDeletedCollections(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	member := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, member)
+
+	managed := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*member.ID, pgdb.Administer).WithNPennsieveDOIs(3).WithRandomLicense().WithNTags(2)
+	managedID := expectationDB.CreateCollection(ctx, t, managed).ID
+	viewed := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*member.ID, pgdb.Read)
+	viewedID := expectationDB.CreateCollection(ctx, t, viewed).ID
+	live := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, live)
+
+	managedDeletedAt := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Microsecond)
+	expectationDB.SetCollectionDeletedAt(ctx, t, managedID, managedDeletedAt)
+	expectationDB.SetCollectionDeletedAt(ctx, t, viewedID, time.Now().UTC().Add(-time.Hour))
+
+	ownerTrash, err := store.GetDeletedCollections(ctx, *owner.ID, role.Manager, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ownerTrash.TotalCount)
+	require.Len(t, ownerTrash.Collections, 2)
+	// most recently deleted first
+	assert.Equal(t, *viewed.NodeID, ownerTrash.Collections[0].NodeID)
+	assert.Equal(t, *managed.NodeID, ownerTrash.Collections[1].NodeID)
+
+	memberTrash, err := store.GetDeletedCollections(ctx, *member.ID, role.Manager, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, memberTrash.TotalCount)
+	require.Len(t, memberTrash.Collections, 1)
+	actual := memberTrash.Collections[0]
+	assert.Equal(t, managedID, actual.ID)
+	assert.Equal(t, *managed.NodeID, actual.NodeID)
+	assert.Equal(t, managed.Name, actual.Name)
+	assert.Equal(t, managed.Description, actual.Description)
+	assert.Equal(t, managed.License, actual.License)
+	assert.Equal(t, managed.Tags, actual.Tags)
+	assert.Equal(t, role.Manager, actual.UserRole)
+	assert.Equal(t, 3, actual.Size)
+	assert.True(t, managedDeletedAt.Equal(actual.DeletedAt), "expected deletedAt %s, got %s", managedDeletedAt, actual.DeletedAt)
+
+	memberTrashAsViewer, err := store.GetDeletedCollections(ctx, *member.ID, role.Viewer, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, memberTrashAsViewer.TotalCount)
+
+	// offset past the end still counts
+	pastEnd, err := store.GetDeletedCollections(ctx, *owner.ID, role.Manager, 10, 5)
+	require.NoError(t, err)
+	assert.Empty(t, pastEnd.Collections)
+	assert.Equal(t, 2, pastEnd.TotalCount)
+}
+
+func testGetDeletedCollection(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	guest := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, guest)
+	nonMember := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, nonMember)
+
+	deleted := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*guest.ID, pgdb.Guest)
+	deletedID := expectationDB.CreateCollection(ctx, t, deleted).ID
+	live := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	expectationDB.CreateCollection(ctx, t, live)
+
+	require.NoError(t, store.DeleteCollection(ctx, deletedID, *owner.ID, nil))
+
+	actual, err := store.GetDeletedCollection(ctx, *guest.ID, *deleted.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, deletedID, actual.ID)
+	assert.Equal(t, role.Guest, actual.UserRole)
+	assert.NotZero(t, actual.DeletedAt)
+
+	_, err = store.GetDeletedCollection(ctx, *nonMember.ID, *deleted.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+
+	_, err = store.GetDeletedCollection(ctx, *owner.ID, *live.NodeID)
+	assert.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
+func testRestoreCollection(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(2).WithNTags(1)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	// not in the trash
+	assert.ErrorIs(t, store.RestoreCollection(ctx, collectionID, *user.ID), collections.ErrCollectionNotFound)
+
+	require.NoError(t, store.DeleteCollection(ctx, collectionID, *user.ID, nil))
+	require.NoError(t, store.RestoreCollection(ctx, collectionID, *user.ID))
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+
+	restored, err := store.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), restored.DOIs)
+}
+
+func testPurgeDeletedCollections(t *testing.T, store *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	live := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(1)
+	liveID := expectationDB.CreateCollection(ctx, t, live).ID
+	expired := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(1)
+	expiredID := expectationDB.CreateCollection(ctx, t, expired).ID
+	recent := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithNPennsieveDOIs(1)
+	recentID := expectationDB.CreateCollection(ctx, t, recent).ID
+
+	now := time.Now().UTC()
+	expectationDB.SetCollectionDeletedAt(ctx, t, expiredID, now.AddDate(0, 0, -40))
+	expectationDB.SetCollectionDeletedAt(ctx, t, recentID, now.AddDate(0, 0, -1))
+
+	purged, err := store.PurgeDeletedCollections(ctx, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	// other tests may be using the same database, so only require that ours was purged
+	assert.GreaterOrEqual(t, purged, int64(1))
+
+	expectationDB.RequireNoCollection(ctx, t, expiredID)
+	expectationDB.RequireDeletedCollection(ctx, t, recent, recentID)
+	expectationDB.RequireCollection(ctx, t, live, liveID)
+}
+
+func testCreateSection(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	doi3 := apitest.NewExternalDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2, doi3)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	imaging, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{
+		Title:       "Imaging",
+		Description: "MRI and CT",
+		DOIs:        []string{doi3.Value, doi1.Value},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, imaging.ID)
+	assert.Equal(t, "Imaging", imaging.Title)
+	assert.Equal(t, "MRI and CT", imaging.Description)
+	// section DOIs are in collection order, not request order
+	assert.Equal(t, []string{doi1.Value, doi3.Value}, imaging.DOIs)
+
+	// doi1 should move out of imaging
+	other, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{
+		Title: "Other",
+		DOIs:  []string{doi1.Value},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{doi1.Value}, other.DOIs)
+
+	empty, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{Title: "Empty"})
+	require.NoError(t, err)
+	assert.Empty(t, empty.DOIs)
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), collection.DOIs)
+	assert.Equal(t, []collections.Section{
+		{ID: imaging.ID, Title: "Imaging", Description: "MRI and CT", DOIs: []string{doi3.Value}},
+		{ID: other.ID, Title: "Other", DOIs: []string{doi1.Value}},
+		{ID: empty.ID, Title: "Empty", DOIs: []string{}},
+	}, collection.Sections)
+}
+
+func testCreateSectionDOIsNotInCollection(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi := apitest.NewPennsieveDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	_, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{
+		Title: "Section",
+		DOIs:  []string{doi.Value, apitest.NewPennsieveDOI().Value},
+	})
+	require.ErrorIs(t, err, collections.ErrSectionDOIsNotInCollection)
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Nil(t, collection.Sections)
+}
+
+func testUpdateSection(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	doi3 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2, doi3)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	var sectionIDs []int64
+	for _, title := range []string{"A", "B", "C"} {
+		section, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{Title: title})
+		require.NoError(t, err)
+		sectionIDs = append(sectionIDs, section.ID)
+	}
+	a, b, c := sectionIDs[0], sectionIDs[1], sectionIDs[2]
+
+	newTitle := "A2"
+	newDescription := "new description"
+	updated, err := collectionsStore.UpdateSection(ctx, collectionID, *user.ID, a, collections.UpdateSectionRequest{
+		Title:       &newTitle,
+		Description: &newDescription,
+		DOIs:        []string{doi2.Value, doi1.Value},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, collections.Section{ID: a, Title: newTitle, Description: newDescription, DOIs: []string{doi1.Value, doi2.Value}}, updated)
+
+	// nil DOIs leave the section's DOIs alone
+	lastPosition := 2
+	updated, err = collectionsStore.UpdateSection(ctx, collectionID, *user.ID, a, collections.UpdateSectionRequest{Position: &lastPosition})
+	require.NoError(t, err)
+	assert.Equal(t, []string{doi1.Value, doi2.Value}, updated.DOIs)
+
+	firstPosition := 0
+	_, err = collectionsStore.UpdateSection(ctx, collectionID, *user.ID, c, collections.UpdateSectionRequest{
+		Position: &firstPosition,
+		DOIs:     []string{doi2.Value, doi3.Value},
+	})
+	require.NoError(t, err)
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, []collections.Section{
+		{ID: c, Title: "C", DOIs: []string{doi2.Value, doi3.Value}},
+		{ID: b, Title: "B", DOIs: []string{}},
+		{ID: a, Title: newTitle, Description: newDescription, DOIs: []string{doi1.Value}},
+	}, collection.Sections)
+
+	// empty DOIs clear the section and a position past the end moves to the end
+	pastEnd := 10
+	updated, err = collectionsStore.UpdateSection(ctx, collectionID, *user.ID, c, collections.UpdateSectionRequest{
+		Position: &pastEnd,
+		DOIs:     []string{},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, updated.DOIs)
+
+	collection, err = collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	require.Len(t, collection.Sections, 3)
+	assert.Equal(t, []int64{b, a, c}, []int64{collection.Sections[0].ID, collection.Sections[1].ID, collection.Sections[2].ID})
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), collection.DOIs)
+}
+
+func testUpdateSectionNotFound(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection1 := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collection1ID := expectationDB.CreateCollection(ctx, t, collection1).ID
+	collection2 := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collection2ID := expectationDB.CreateCollection(ctx, t, collection2).ID
+
+	section, err := collectionsStore.CreateSection(ctx, collection1ID, *user.ID, collections.CreateSectionRequest{Title: "Section"})
+	require.NoError(t, err)
+
+	newTitle := "New Title"
+	_, err = collectionsStore.UpdateSection(ctx, collection2ID, *user.ID, section.ID, collections.UpdateSectionRequest{Title: &newTitle})
+	require.ErrorIs(t, err, collections.ErrSectionNotFound)
+
+	_, err = collectionsStore.UpdateSection(ctx, collection1ID, *user.ID, section.ID, collections.UpdateSectionRequest{DOIs: []string{apitest.NewPennsieveDOI().Value}})
+	require.ErrorIs(t, err, collections.ErrSectionDOIsNotInCollection)
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *collection1.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, []collections.Section{{ID: section.ID, Title: "Section", DOIs: []string{}}}, collection.Sections)
+}
+
+func testDeleteSection(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	section, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{
+		Title: "Section",
+		DOIs:  []string{doi1.Value, doi2.Value},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, collectionsStore.DeleteSection(ctx, collectionID, *user.ID, section.ID))
+
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Nil(t, collection.Sections)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), collection.DOIs)
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+
+	require.ErrorIs(t, collectionsStore.DeleteSection(ctx, collectionID, *user.ID, section.ID), collections.ErrSectionNotFound)
+}
+
+func testCollectionVersion(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	requireVersion := func(expectedVersion int64) {
+		t.Helper()
+		collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+		require.NoError(t, err)
+		require.Equal(t, expectedVersion, collection.Version)
+	}
+	requireVersion(1)
+
+	newName := uuid.NewString()
+	updated, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{Name: &newName})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// no changes, no new version
+	updated, err = collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	reordered, err := collectionsStore.ReorderDOIs(ctx, *user.ID, collectionID, []string{doi2.Value, doi1.Value})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), reordered.Version)
+
+	// same order, no new version or event
+	reordered, err = collectionsStore.ReorderDOIs(ctx, *user.ID, collectionID, []string{doi2.Value, doi1.Value})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), reordered.Version)
+	events, err := collectionsStore.GetCollectionEvents(ctx, collectionID, 100, 0)
+	require.NoError(t, err)
+	reorderedEvents := 0
+	for _, event := range events.Events {
+		if event.Type == collections.DOIsReorderedEvent {
+			reorderedEvents++
+		}
+	}
+	assert.Equal(t, 1, reorderedEvents)
+
+	section, err := collectionsStore.CreateSection(ctx, collectionID, *user.ID, collections.CreateSectionRequest{
+		Title: "Section",
+		DOIs:  []string{doi1.Value},
+	})
+	require.NoError(t, err)
+	requireVersion(4)
+
+	newTitle := uuid.NewString()
+	_, err = collectionsStore.UpdateSection(ctx, collectionID, *user.ID, section.ID, collections.UpdateSectionRequest{Title: &newTitle})
+	require.NoError(t, err)
+	requireVersion(5)
+
+	require.NoError(t, collectionsStore.DeleteSection(ctx, collectionID, *user.ID, section.ID))
+	requireVersion(6)
+}
+
+func testUpdateCollectionVersionMismatch(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	staleVersion := int64(1)
+	newName := uuid.NewString()
+	updated, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{
+		Name:            &newName,
+		ExpectedVersion: &staleVersion,
+	})
+	require.NoError(t, err)
+	expectedCollection.Name = newName
+	assert.Equal(t, int64(2), updated.Version)
+
+	newDescription := uuid.NewString()
+	_, err = collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{
+		Description:     &newDescription,
+		DOIs:            collections.DOIUpdate{Add: []collections.DOI{apitest.NewPennsieveDOI()}},
+		ExpectedVersion: &staleVersion,
+	})
+	require.ErrorIs(t, err, collections.ErrCollectionVersionMismatch)
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+}
+
+func requireEventDiff(t *testing.T, event collections.CollectionEvent) map[string]any {
+	t.Helper()
+	var diff map[string]any
+	require.NoError(t, json.Unmarshal(event.Diff, &diff))
+	return diff
+}
+
+func testCollectionEventsUpdate(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 1)
+	created := events[0]
+	assert.Equal(t, collections.CreatedEvent, created.Type)
+	require.NotNil(t, created.UserID)
+	assert.Equal(t, *user.ID, *created.UserID)
+	createdDiff := requireEventDiff(t, created)
+	assert.Equal(t, map[string]any{"old": nil, "new": expectedCollection.Name}, createdDiff["name"])
+	assert.Equal(t, []any{doi1.Value, doi2.Value}, createdDiff["dois"])
+
+	// a no-op update should not be recorded
+	sameName := expectedCollection.Name
+	_, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{Name: &sameName})
+	require.NoError(t, err)
+	require.Len(t, expectationDB.GetCollectionEvents(ctx, t, collectionID), 1)
+
+	newName := uuid.NewString()
+	newLicense := apitest.NewExpectedCollection().WithRandomLicense().License
+	newTags := []string{uuid.NewString(), uuid.NewString()}
+	doiToAdd := apitest.NewPennsieveDOI()
+	label := "Primary"
+	_, err = collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{
+		Name:    &newName,
+		License: newLicense,
+		Tags:    newTags,
+		DOIs: collections.DOIUpdate{
+			Add:      []collections.DOI{doiToAdd},
+			Remove:   []string{doi2.Value},
+			Annotate: []collections.DOIAnnotation{{DOI: doi1.Value, Label: &label}},
+		},
+	})
+	require.NoError(t, err)
+
+	events = expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	typeToDiff := map[collections.EventType]map[string]any{}
+	for _, event := range events[1:] {
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, *user.ID, *event.UserID)
+		typeToDiff[event.Type] = requireEventDiff(t, event)
+	}
+	assert.Len(t, typeToDiff, 6)
+	assert.Equal(t, map[string]any{"name": map[string]any{"old": expectedCollection.Name, "new": newName}}, typeToDiff[collections.RenamedEvent])
+	assert.Equal(t, map[string]any{"license": map[string]any{"old": nil, "new": *newLicense}}, typeToDiff[collections.LicenseChangedEvent])
+	assert.Equal(t, []any{newTags[0], newTags[1]}, typeToDiff[collections.TagsChangedEvent]["tags"].(map[string]any)["new"])
+	assert.Equal(t, map[string]any{"dois": []any{doiToAdd.Value}}, typeToDiff[collections.DOIsAddedEvent])
+	assert.Equal(t, map[string]any{"dois": []any{doi2.Value}}, typeToDiff[collections.DOIsRemovedEvent])
+	assert.Equal(t,
+		map[string]any{"dois": map[string]any{doi1.Value: map[string]any{"label": map[string]any{"old": nil, "new": label}}}},
+		typeToDiff[collections.DOIsAnnotatedEvent])
+}
+
+func testCollectionEventsPublish(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
+	require.NoError(t, collectionsStore.FinishPublish(ctx, collectionID, publishing.CompletedStatus, true))
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.RemovalType, nil))
+	require.NoError(t, collectionsStore.FinishPublish(ctx, collectionID, publishing.FailedStatus, true))
+
+	// publish already in progress is not recorded
+	require.NoError(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil))
+	require.ErrorIs(t, collectionsStore.StartPublish(ctx, collectionID, *user.ID, publishing.PublicationType, nil), collections.ErrPublishInProgress)
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 6)
+	var eventTypes []collections.EventType
+	for _, event := range events {
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, *user.ID, *event.UserID)
+		eventTypes = append(eventTypes, event.Type)
+	}
+	assert.Equal(t, []collections.EventType{
+		collections.CreatedEvent,
+		collections.PublishStartedEvent,
+		collections.PublishFinishedEvent,
+		collections.UnpublishStartedEvent,
+		collections.UnpublishFinishedEvent,
+		collections.PublishStartedEvent,
+	}, eventTypes)
+
+	assert.Equal(t, map[string]any{
+		"type":   string(publishing.PublicationType),
+		"status": map[string]any{"old": string(publishing.InProgressStatus), "new": string(publishing.CompletedStatus)},
+	}, requireEventDiff(t, events[2]))
+	assert.Equal(t, map[string]any{
+		"type":   string(publishing.RemovalType),
+		"status": map[string]any{"old": string(publishing.InProgressStatus), "new": string(publishing.FailedStatus)},
+	}, requireEventDiff(t, events[4]))
+}
+
+func testCollectionEventsMembers(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	member := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, member)
+	newOwner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, newOwner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*newOwner.ID, pgdb.Delete)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, member.NodeID, role.Viewer)
+	require.NoError(t, err)
+	// same role, no event
+	_, err = collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, member.NodeID, role.Viewer)
+	require.NoError(t, err)
+	_, err = collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, member.NodeID, role.Editor)
+	require.NoError(t, err)
+	require.NoError(t, collectionsStore.DeleteCollectionMember(ctx, collectionID, *owner.ID, member.NodeID))
+	require.NoError(t, collectionsStore.TransferOwnership(ctx, collectionID, *owner.ID, newOwner.NodeID))
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 5)
+	for _, event := range events {
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, *owner.ID, *event.UserID)
+	}
+
+	assert.Equal(t, collections.MemberAddedEvent, events[1].Type)
+	assert.Equal(t, map[string]any{
+		"member": member.NodeID,
+		"role":   map[string]any{"old": nil, "new": role.Viewer.String()},
+	}, requireEventDiff(t, events[1]))
+
+	assert.Equal(t, collections.MemberRoleChangedEvent, events[2].Type)
+	assert.Equal(t, map[string]any{
+		"member": member.NodeID,
+		"role":   map[string]any{"old": role.Viewer.String(), "new": role.Editor.String()},
+	}, requireEventDiff(t, events[2]))
+
+	assert.Equal(t, collections.MemberRemovedEvent, events[3].Type)
+	assert.Equal(t, map[string]any{
+		"member": member.NodeID,
+		"role":   map[string]any{"old": role.Editor.String(), "new": nil},
+	}, requireEventDiff(t, events[3]))
+
+	assert.Equal(t, collections.OwnershipTransferredEvent, events[4].Type)
+	assert.Equal(t, map[string]any{
+		"owner": map[string]any{"old": owner.NodeID, "new": newOwner.NodeID},
+	}, requireEventDiff(t, events[4]))
+}
+
+func testCollectionEventsTrash(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	manager := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, manager)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithUser(*manager.ID, pgdb.Administer)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	require.NoError(t, collectionsStore.DeleteCollection(ctx, collectionID, *owner.ID, nil))
+	require.NoError(t, collectionsStore.RestoreCollection(ctx, collectionID, *manager.ID))
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, collections.TrashedEvent, events[1].Type)
+	require.NotNil(t, events[1].UserID)
+	assert.Equal(t, *owner.ID, *events[1].UserID)
+
+	assert.Equal(t, collections.RestoredEvent, events[2].Type)
+	require.NotNil(t, events[2].UserID)
+	assert.Equal(t, *manager.ID, *events[2].UserID)
+}
+
+func testCollectionEventsReorder(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.ReorderDOIs(ctx, *owner.ID, collectionID, []string{doi2.Value, doi1.Value})
+	require.NoError(t, err)
+	// same order, no event
+	_, err = collectionsStore.ReorderDOIs(ctx, *owner.ID, collectionID, []string{doi2.Value, doi1.Value})
+	require.NoError(t, err)
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, collections.DOIsReorderedEvent, events[1].Type)
+	require.NotNil(t, events[1].UserID)
+	assert.Equal(t, *owner.ID, *events[1].UserID)
+	assert.Equal(t, map[string]any{
+		"dois": map[string]any{"old": []any{doi1.Value, doi2.Value}, "new": []any{doi2.Value, doi1.Value}},
+	}, requireEventDiff(t, events[1]))
+}
+
+func testCollectionEventsGrants(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+	_, teamNodeID := expectationDB.CreateTestTeam(ctx, t)
+	_, organizationNodeID := expectationDB.CreateTestOrganization(ctx, t, nil)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Viewer)
+	require.NoError(t, err)
+	// same role, no event
+	_, err = collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Viewer)
+	require.NoError(t, err)
+	_, err = collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID, role.Editor)
+	require.NoError(t, err)
+	_, err = collectionsStore.PutCollectionGrant(ctx, collectionID, *owner.ID, collections.OrganizationGrantee, organizationNodeID, role.Guest)
+	require.NoError(t, err)
+	require.NoError(t, collectionsStore.DeleteCollectionGrant(ctx, collectionID, *owner.ID, collections.TeamGrantee, teamNodeID))
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 5)
+	for _, event := range events[1:] {
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, *owner.ID, *event.UserID)
+	}
+
+	assert.Equal(t, collections.GrantAddedEvent, events[1].Type)
+	assert.Equal(t, map[string]any{
+		"grantee":     teamNodeID,
+		"granteeType": string(collections.TeamGrantee),
+		"role":        map[string]any{"old": nil, "new": role.Viewer.String()},
+	}, requireEventDiff(t, events[1]))
+
+	assert.Equal(t, collections.GrantRoleChangedEvent, events[2].Type)
+	assert.Equal(t, map[string]any{
+		"grantee":     teamNodeID,
+		"granteeType": string(collections.TeamGrantee),
+		"role":        map[string]any{"old": role.Viewer.String(), "new": role.Editor.String()},
+	}, requireEventDiff(t, events[2]))
+
+	assert.Equal(t, collections.GrantAddedEvent, events[3].Type)
+	assert.Equal(t, map[string]any{
+		"grantee":     organizationNodeID,
+		"granteeType": string(collections.OrganizationGrantee),
+		"role":        map[string]any{"old": nil, "new": role.Guest.String()},
+	}, requireEventDiff(t, events[3]))
+
+	assert.Equal(t, collections.GrantRemovedEvent, events[4].Type)
+	assert.Equal(t, map[string]any{
+		"grantee":     teamNodeID,
+		"granteeType": string(collections.TeamGrantee),
+		"role":        map[string]any{"old": role.Editor.String(), "new": nil},
+	}, requireEventDiff(t, events[4]))
+}
+
+func testCollectionEventsSections(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	first, err := collectionsStore.CreateSection(ctx, collectionID, *owner.ID, collections.CreateSectionRequest{
+		Title: "First",
+		DOIs:  []string{doi1.Value},
+	})
+	require.NoError(t, err)
+	second, err := collectionsStore.CreateSection(ctx, collectionID, *owner.ID, collections.CreateSectionRequest{Title: "Second"})
+	require.NoError(t, err)
+
+	newTitle := "Renamed"
+	newPosition := 0
+	_, err = collectionsStore.UpdateSection(ctx, collectionID, *owner.ID, second.ID, collections.UpdateSectionRequest{
+		Title:    &newTitle,
+		Position: &newPosition,
+		DOIs:     []string{doi2.Value},
+	})
+	require.NoError(t, err)
+	// no changes, no event
+	_, err = collectionsStore.UpdateSection(ctx, collectionID, *owner.ID, second.ID, collections.UpdateSectionRequest{Title: &newTitle})
+	require.NoError(t, err)
+	require.NoError(t, collectionsStore.DeleteSection(ctx, collectionID, *owner.ID, first.ID))
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 5)
+	for _, event := range events[1:] {
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, *owner.ID, *event.UserID)
+	}
+
+	assert.Equal(t, collections.SectionCreatedEvent, events[1].Type)
+	assert.Equal(t, map[string]any{
+		"section": float64(first.ID),
+		"title":   map[string]any{"old": nil, "new": "First"},
+		"dois":    map[string]any{"old": nil, "new": []any{doi1.Value}},
+	}, requireEventDiff(t, events[1]))
+
+	assert.Equal(t, collections.SectionCreatedEvent, events[2].Type)
+	assert.Equal(t, map[string]any{
+		"section": float64(second.ID),
+		"title":   map[string]any{"old": nil, "new": "Second"},
+	}, requireEventDiff(t, events[2]))
+
+	assert.Equal(t, collections.SectionUpdatedEvent, events[3].Type)
+	assert.Equal(t, map[string]any{
+		"section":  float64(second.ID),
+		"title":    map[string]any{"old": "Second", "new": newTitle},
+		"dois":     map[string]any{"old": []any{}, "new": []any{doi2.Value}},
+		"position": map[string]any{"old": float64(1), "new": float64(0)},
+	}, requireEventDiff(t, events[3]))
+
+	assert.Equal(t, collections.SectionDeletedEvent, events[4].Type)
+	assert.Equal(t, map[string]any{
+		"section": float64(first.ID),
+		"title":   map[string]any{"old": "First", "new": nil},
+	}, requireEventDiff(t, events[4]))
+}
+
+func testCollectionEventsFailedUpdate(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	owner := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, owner)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*owner.ID, pgdb.Owner).WithDOIs(apitest.NewPennsieveDOI())
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	staleVersion := int64(0)
+	newName := uuid.NewString()
+	_, err := collectionsStore.UpdateCollection(ctx, *owner.ID, collectionID, collections.UpdateCollectionRequest{
+		Name:            &newName,
+		ExpectedVersion: &staleVersion,
+	})
+	require.ErrorIs(t, err, collections.ErrCollectionVersionMismatch)
+
+	_, err = collectionsStore.PutCollectionMember(ctx, collectionID, *owner.ID, owner.NodeID, role.Manager)
+	require.ErrorIs(t, err, collections.ErrLastOwner)
+
+	events := expectationDB.GetCollectionEvents(ctx, t, collectionID)
+	require.Len(t, events, 1)
+	assert.Equal(t, collections.CreatedEvent, events[0].Type)
+}
+
+func testGetCollectionEvents(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser(userstest.WithFirstName(uuid.NewString()), userstest.WithLastName(uuid.NewString()))
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	newName := uuid.NewString()
+	_, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{Name: &newName})
+	require.NoError(t, err)
+
+	response, err := collectionsStore.GetCollectionEvents(ctx, collectionID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 10, response.Limit)
+	assert.Equal(t, 0, response.Offset)
+	assert.Equal(t, 2, response.TotalCount)
+	require.Len(t, response.Events, 2)
+
+	renamed := response.Events[0]
+	assert.Equal(t, collections.RenamedEvent, renamed.Type)
+	assert.JSONEq(t, fmt.Sprintf(`{"name":{"old":%q,"new":%q}}`, collection.Name, newName), string(renamed.Diff))
+	require.NotNil(t, renamed.User)
+	assert.Equal(t, *user.ID, renamed.User.UserID)
+	assert.Equal(t, user.NodeID, renamed.User.UserNodeID)
+	assert.Equal(t, user.FirstName, renamed.User.FirstName)
+	assert.Equal(t, user.LastName, renamed.User.LastName)
+	assert.False(t, renamed.CreatedAt.IsZero())
+
+	assert.Equal(t, collections.CreatedEvent, response.Events[1].Type)
+	assert.Greater(t, renamed.ID, response.Events[1].ID)
+}
+
+func testGetCollectionEventsLimitOffset(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	var names []string
+	for i := 0; i < 4; i++ {
+		name := uuid.NewString()
+		_, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{Name: &name})
+		require.NoError(t, err)
+		names = append(names, name)
+	}
+	// 1 created + 4 renamed events
+	expectedTotal := 5
+
+	page, err := collectionsStore.GetCollectionEvents(ctx, collectionID, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTotal, page.TotalCount)
+	require.Len(t, page.Events, 2)
+	assert.Contains(t, string(page.Events[0].Diff), names[2])
+	assert.Contains(t, string(page.Events[1].Diff), names[1])
+
+	lastPage, err := collectionsStore.GetCollectionEvents(ctx, collectionID, 2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTotal, lastPage.TotalCount)
+	require.Len(t, lastPage.Events, 1)
+	assert.Equal(t, collections.CreatedEvent, lastPage.Events[0].Type)
+
+	pastEnd, err := collectionsStore.GetCollectionEvents(ctx, collectionID, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTotal, pastEnd.TotalCount)
+	assert.Empty(t, pastEnd.Events)
+}
+
+func testUpdateCollectionDOIOrder(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+	doi3 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	updated, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{
+		DOIs: collections.DOIUpdate{
+			Add:    []collections.DOI{doi3},
+			Remove: []string{doi1.Value},
+			Order:  []string{doi3.Value, doi2.Value},
+		},
+	})
+	require.NoError(t, err)
+
+	expectedCollection.SetDOIs(doi3, doi2)
+	assert.Equal(t, expectedCollection.DOIs.AsDOIs(), updated.DOIs)
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+}
+
+func testUpdateCollectionDOIOrderMismatch(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi2 := apitest.NewPennsieveDOI()
+
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	newName := uuid.NewString()
+	_, err := collectionsStore.UpdateCollection(ctx, *user.ID, collectionID, collections.UpdateCollectionRequest{
+		Name: &newName,
+		DOIs: collections.DOIUpdate{
+			Remove: []string{doi1.Value},
+			Order:  []string{doi2.Value, doi1.Value},
+		},
+	})
+	require.ErrorIs(t, err, collections.ErrDOIOrderMismatch)
+
+	expectationDB.RequireCollection(ctx, t, expectedCollection, collectionID)
+}
+
+func testCreateSnapshot(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	doi1 := apitest.NewPennsieveDOI()
+	doi1.Label = uuid.NewString()
+	doi2 := apitest.NewExternalDOI()
+	doi2.Note = uuid.NewString()
+
+	license := "Apache-2.0"
+	expectedCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner).
+		WithLicense(license).WithTags([]string{"a", "b"}).WithDOIs(doi1, doi2)
+	collectionID := expectationDB.CreateCollection(ctx, t, expectedCollection).ID
+
+	// reorder so that the snapshot has to follow position rather than insertion order
+	_, err := collectionsStore.ReorderDOIs(ctx, *user.ID, collectionID, []string{doi2.Value, doi1.Value})
+	require.NoError(t, err)
+
+	name := uuid.NewString()
+	snapshot, err := collectionsStore.CreateSnapshot(ctx, collectionID, *user.ID, name)
+	require.NoError(t, err)
+	assert.Positive(t, snapshot.ID)
+	assert.Equal(t, name, snapshot.Name)
+	assert.False(t, snapshot.CreatedAt.IsZero())
+	require.NotNil(t, snapshot.CreatedBy)
+	assert.Equal(t, *user.ID, snapshot.CreatedBy.UserID)
+	assert.Equal(t, user.NodeID, snapshot.CreatedBy.UserNodeID)
+
+	expectedState := collections.SnapshotState{
+		Name:        expectedCollection.Name,
+		Description: expectedCollection.Description,
+		License:     &license,
+		Tags:        []string{"a", "b"},
+		DOIs:        collections.DOIs{doi2, doi1},
+	}
+	assert.Equal(t, expectedState, snapshot.State)
+
+	got, err := collectionsStore.GetSnapshot(ctx, collectionID, snapshot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.ID, got.ID)
+	assert.Equal(t, snapshot.CreatedBy, got.CreatedBy)
+	assert.Equal(t, expectedState, got.State)
+
+	// snapshots are not part of the collection, so they do not change its version
+	collection, err := collectionsStore.GetCollection(ctx, *user.ID, *expectedCollection.NodeID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), collection.Version)
+}
+
+func testCreateSnapshotNonExistent(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	_, err := collectionsStore.CreateSnapshot(ctx, 0, *user.ID, uuid.NewString())
+	require.ErrorIs(t, err, collections.ErrCollectionNotFound)
+}
+
+func testGetSnapshotNotFound(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection1 := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collection1ID := expectationDB.CreateCollection(ctx, t, collection1).ID
+	collection2 := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collection2ID := expectationDB.CreateCollection(ctx, t, collection2).ID
+
+	snapshot, err := collectionsStore.CreateSnapshot(ctx, collection1ID, *user.ID, uuid.NewString())
+	require.NoError(t, err)
+
+	_, err = collectionsStore.GetSnapshot(ctx, collection2ID, snapshot.ID)
+	require.ErrorIs(t, err, collections.ErrSnapshotNotFound)
+}
+
+func testGetSnapshots(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	otherCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	otherCollectionID := expectationDB.CreateCollection(ctx, t, otherCollection).ID
+	_, err := collectionsStore.CreateSnapshot(ctx, otherCollectionID, *user.ID, uuid.NewString())
+	require.NoError(t, err)
+
+	var created []collections.Snapshot
+	for i := 0; i < 3; i++ {
+		snapshot, err := collectionsStore.CreateSnapshot(ctx, collectionID, *user.ID, uuid.NewString())
+		require.NoError(t, err)
+		created = append(created, snapshot)
+	}
+
+	all, err := collectionsStore.GetSnapshots(ctx, collectionID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, all.TotalCount)
+	require.Len(t, all.Snapshots, 3)
+	for i, summary := range all.Snapshots {
+		expected := created[len(created)-1-i].SnapshotSummary
+		assert.Equal(t, expected.ID, summary.ID)
+		assert.Equal(t, expected.Name, summary.Name)
+		assert.Equal(t, expected.CreatedBy, summary.CreatedBy)
+	}
+
+	page, err := collectionsStore.GetSnapshots(ctx, collectionID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.TotalCount)
+	require.Len(t, page.Snapshots, 1)
+	assert.Equal(t, created[1].ID, page.Snapshots[0].ID)
+
+	pastEnd, err := collectionsStore.GetSnapshots(ctx, collectionID, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pastEnd.TotalCount)
+	assert.Empty(t, pastEnd.Snapshots)
+}
+
+func testCreatePublishJob(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	created, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, collectionID, created.CollectionID)
+	assert.Equal(t, *collection.NodeID, created.CollectionNodeID)
+	require.NotNil(t, created.UserID)
+	assert.Equal(t, *user.ID, *created.UserID)
+	require.NotNil(t, created.UserNodeID)
+	assert.Equal(t, user.NodeID, *created.UserNodeID)
+	assert.Equal(t, publishing.JobQueuedStatus, created.Status)
+	assert.Nil(t, created.Step)
+	assert.Zero(t, created.Attempts)
+	assert.Nil(t, created.Error)
+	assert.Nil(t, created.PublishedDatasetID)
+	assert.Nil(t, created.PublishedVersion)
+	assert.WithinDuration(t, time.Now(), created.CreatedAt, time.Minute)
+	assert.Equal(t, created.CreatedAt, created.UpdatedAt)
+
+	retrieved, err := collectionsStore.GetPublishJob(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, retrieved)
+}
+
+func testCreatePublishJobSupersedes(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	otherCollection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	otherCollectionID := expectationDB.CreateCollection(ctx, t, otherCollection).ID
+
+	oldJob, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+	otherJob, err := collectionsStore.CreatePublishJob(ctx, otherCollectionID, *user.ID)
+	require.NoError(t, err)
+
+	newJob, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldJob.ID, newJob.ID)
+
+	oldJob, err = collectionsStore.GetPublishJob(ctx, oldJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobFailedStatus, oldJob.Status)
+	require.NotNil(t, oldJob.Error)
+	assert.Contains(t, *oldJob.Error, "replaced")
+
+	// jobs of other collections are left alone
+	otherJob, err = collectionsStore.GetPublishJob(ctx, otherJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobQueuedStatus, otherJob.Status)
+}
+
+func testGetPublishJobNotFound(t *testing.T, collectionsStore *collections.PostgresStore, _ *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	_, err := collectionsStore.GetPublishJob(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, collections.ErrPublishJobNotFound)
+}
+
+func testGetLatestPublishJob(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	_, err := collectionsStore.GetLatestPublishJob(ctx, collectionID)
+	assert.ErrorIs(t, err, collections.ErrPublishJobNotFound)
+
+	_, err = collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+	latestJob, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+
+	actual, err := collectionsStore.GetLatestPublishJob(ctx, collectionID)
+	require.NoError(t, err)
+	assert.Equal(t, latestJob.ID, actual.ID)
+}
+
+func testStartPublishJobAttempt(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	job, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+
+	started, err := collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobRunningStatus, started.Status)
+	assert.Equal(t, 1, started.Attempts)
+	assert.False(t, started.UpdatedAt.Before(job.UpdatedAt))
+
+	require.NoError(t, collectionsStore.UpdatePublishJobStep(ctx, job.ID, publishing.SavingManifestStep))
+
+	// an attempt whose worker died leaves the job Running, so it can be started again
+	started, err = collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobRunningStatus, started.Status)
+	assert.Equal(t, 2, started.Attempts)
+	require.NotNil(t, started.Step)
+	assert.Equal(t, publishing.SavingManifestStep, *started.Step)
+}
+
+func testStartPublishJobAttemptFinished(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	job, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+	_, err = collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	require.NoError(t, err)
+	require.NoError(t, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobSucceededStatus, collections.PublishJobResult{}))
+
+	_, err = collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	assert.ErrorIs(t, err, collections.ErrPublishJobFinished)
+
+	_, err = collectionsStore.StartPublishJobAttempt(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, collections.ErrPublishJobNotFound)
+}
+
+func testFinishPublishJobAttempt(t *testing.T, collectionsStore *collections.PostgresStore, expectationDB *fixtures.ExpectationDB) {
+	ctx := context.Background()
+
+	user := userstest.NewTestUser()
+	expectationDB.CreateTestUser(ctx, t, user)
+
+	collection := apitest.NewExpectedCollection().WithNodeID().WithUser(*user.ID, pgdb.Owner)
+	collectionID := expectationDB.CreateCollection(ctx, t, collection).ID
+
+	job, err := collectionsStore.CreatePublishJob(ctx, collectionID, *user.ID)
+	require.NoError(t, err)
+
+	// a failed attempt to be retried
+	_, err = collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	require.NoError(t, err)
+	attemptError := "error publishing to Discover"
+	require.NoError(t, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobQueuedStatus, collections.PublishJobResult{Error: &attemptError}))
+
+	job, err = collectionsStore.GetPublishJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobQueuedStatus, job.Status)
+	require.NotNil(t, job.Error)
+	assert.Equal(t, attemptError, *job.Error)
+
+	// a successful retry clears the error
+	_, err = collectionsStore.StartPublishJobAttempt(ctx, job.ID)
+	require.NoError(t, err)
+	publishedDatasetID, publishedVersion := 17, 2
+	require.NoError(t, collectionsStore.FinishPublishJobAttempt(ctx, job.ID, publishing.JobSucceededStatus, collections.PublishJobResult{
+		PublishedDatasetID: &publishedDatasetID,
+		PublishedVersion:   &publishedVersion,
+	}))
+
+	job, err = collectionsStore.GetPublishJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, publishing.JobSucceededStatus, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Nil(t, job.Error)
+	require.NotNil(t, job.PublishedDatasetID)
+	assert.Equal(t, publishedDatasetID, *job.PublishedDatasetID)
+	require.NotNil(t, job.PublishedVersion)
+	assert.Equal(t, publishedVersion, *job.PublishedVersion)
+
+	assert.ErrorIs(t,
+		collectionsStore.FinishPublishJobAttempt(ctx, uuid.NewString(), publishing.JobFailedStatus, collections.PublishJobResult{}),
+		collections.ErrPublishJobNotFound)
 }
@@ -5,3 +5,29 @@ import "errors"
 var ErrCollectionNotFound = errors.New("collection not found")
 
 var ErrPublishInProgress = errors.New("publish already in progress")
+
+var ErrUserNotFound = errors.New("user not found")
+
+var ErrCollectionMemberNotFound = errors.New("user is not a member of collection")
+
+var ErrLastOwner = errors.New("collection must have at least one owner")
+
+var ErrNotOwner = errors.New("user is not an owner of collection")
+
+var ErrGranteeNotFound = errors.New("team or organization not found")
+
+var ErrCollectionGrantNotFound = errors.New("team or organization has no role on collection")
+
+var ErrDOIOrderMismatch = errors.New("DOIs to reorder do not match the DOIs in collection")
+
+var ErrSectionNotFound = errors.New("section not found in collection")
+
+var ErrSectionDOIsNotInCollection = errors.New("section DOIs are not all in collection")
+
+var ErrCollectionVersionMismatch = errors.New("collection has changed since it was read")
+
+var ErrSnapshotNotFound = errors.New("snapshot not found in collection")
+
+var ErrPublishJobNotFound = errors.New("publish job not found")
+
+var ErrPublishJobFinished = errors.New("publish job already finished")
@@ -1,9 +1,11 @@
 package collections
 
 import (
+	"encoding/json"
 	"github.com/pennsieve/collections-service/internal/api/datasource"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"time"
 )
 
 type CreateCollectionRequest struct {
@@ -22,8 +24,54 @@ type CreateCollectionResponse struct {
 }
 
 type Publication struct {
-	Status publishing.Status
-	Type   publishing.Type
+	Status    publishing.Status
+	Type      publishing.Type
+	StartedAt time.Time
+}
+
+// IsStale returns true if the publication is InProgress and started before publishTimeout ago. StartPublish
+// lets a new publish replace a stale one.
+func (p *Publication) IsStale(now time.Time, publishTimeout time.Duration) bool {
+	return p.Status == publishing.InProgressStatus && p.StartedAt.Before(now.Add(-publishTimeout))
+}
+
+// StalePublish is a publish that has been InProgress for longer than the publish timeout, most likely because the
+// process running it died before it could finish.
+type StalePublish struct {
+	CollectionID     int64
+	CollectionNodeID string
+	Type             publishing.Type
+	// UserID is the user that started the publish. Should only be nil if the user is deleted.
+	UserID    *int64
+	StartedAt time.Time
+}
+
+// PublishJob is a publish of a collection that runs in the publish worker rather than in the API request that started it.
+type PublishJob struct {
+	ID               string
+	CollectionID     int64
+	CollectionNodeID string
+	// UserID and UserNodeID identify the user who started the publish. They are nil if the user has since been deleted.
+	UserID     *int64
+	UserNodeID *string
+	Status     publishing.JobStatus
+	// Step is nil until the first attempt of the job starts.
+	Step     *publishing.JobStep
+	Attempts int
+	// Error is set if the most recent attempt failed.
+	Error              *string
+	PublishedDatasetID *int
+	PublishedVersion   *int
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// PublishJobResult is what an attempt of a publish job records besides its status. Nil published dataset ids and
+// versions leave any values recorded by earlier attempts alone.
+type PublishJobResult struct {
+	Error              *string
+	PublishedDatasetID *int
+	PublishedVersion   *int
 }
 
 type CollectionBase struct {
@@ -37,6 +85,9 @@ type CollectionBase struct {
 	UserRole    role.Role
 	// Publication is nil when this is part of GetCollectionsResponse
 	Publication *Publication
+	// Version changes whenever the collection's name, description, license, tags, DOIs, or sections change.
+	// It is zero when this is part of GetCollectionsResponse.
+	Version int64
 }
 
 type CollectionSummary struct {
@@ -44,16 +95,99 @@ type CollectionSummary struct {
 	BannerDOIs []string
 }
 
+type SortField string
+
+const (
+	// SortByID orders collections by creation order. It is the default.
+	SortByID        SortField = "id"
+	SortByName      SortField = "name"
+	SortByCreatedAt SortField = "createdAt"
+	SortByUpdatedAt SortField = "updatedAt"
+	SortBySize      SortField = "size"
+)
+
+var SortFields = []SortField{SortByName, SortByCreatedAt, SortByUpdatedAt, SortBySize}
+
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "asc"
+	Descending SortDirection = "desc"
+)
+
+// GetCollectionsQuery filters and orders the results of Store.GetCollections. The zero value
+// applies no filters and orders collections oldest first.
+type GetCollectionsQuery struct {
+	// Text matches collections whose name or description contains it, ignoring case.
+	Text string
+	// Tag matches collections with this tag.
+	Tag string
+	// License matches collections with this license.
+	License string
+	// PublicationStatus matches collections with this publish status. DraftStatus matches collections that have never been published.
+	PublicationStatus *publishing.Status
+	// Role matches collections on which the user's role is exactly this role.
+	Role *role.Role
+	// SortBy defaults to SortByID and SortDirection to Ascending.
+	SortBy        SortField
+	SortDirection SortDirection
+	// After, if set, limits the results to those ordered after the cursor. The cursor's sort must
+	// match the query's and the offset must be zero.
+	After *Cursor
+}
+
+// EffectiveSort returns the sort field and direction of q with defaults applied.
+func (q GetCollectionsQuery) EffectiveSort() (SortField, SortDirection) {
+	sortBy, sortDirection := q.SortBy, q.SortDirection
+	if len(sortBy) == 0 {
+		sortBy = SortByID
+	}
+	if len(sortDirection) == 0 {
+		sortDirection = Ascending
+	}
+	return sortBy, sortDirection
+}
+
+// Cursor marks the position of a collection in the ordered results of Store.GetCollections.
+type Cursor struct {
+	SortBy        SortField
+	SortDirection SortDirection
+	// Key is the collection's value of the sort field: a string for SortByName, a time.Time for
+	// SortByCreatedAt and SortByUpdatedAt, and an int64 for SortBySize and SortByID.
+	Key any
+	// ID breaks ties between collections with equal keys.
+	ID int64
+}
+
 type GetCollectionsResponse struct {
 	Limit       int
 	Offset      int
 	Collections []CollectionSummary
 	TotalCount  int
+	// NextCursor is set if there are more results after this page.
+	NextCursor *Cursor
+}
+
+// DeletedCollection is a collection in the trash.
+type DeletedCollection struct {
+	CollectionBase
+	DeletedAt time.Time
+}
+
+type GetDeletedCollectionsResponse struct {
+	Limit       int
+	Offset      int
+	Collections []DeletedCollection
+	TotalCount  int
 }
 
+// DOI is JSON encoded when it is part of a SnapshotState.
 type DOI struct {
-	Value      string
-	Datasource datasource.DOIDatasource
+	Value      string                   `json:"doi"`
+	Datasource datasource.DOIDatasource `json:"datasource"`
+	// Label and Note are optional annotations added by curators. Empty if not set.
+	Label string `json:"label,omitempty"`
+	Note  string `json:"note,omitempty"`
 }
 
 type DOIs []DOI
@@ -69,11 +203,54 @@ func (d DOIs) Strings() []string {
 type GetCollectionResponse struct {
 	CollectionBase
 	DOIs DOIs
+	// Sections are in section order. Nil if the collection has no sections.
+	Sections []Section
+}
+
+// Section is a titled sub-grouping of some of the DOIs in a collection. A DOI belongs to at most one section.
+type Section struct {
+	ID          int64
+	Title       string
+	Description string
+	// DOIs are in collection order.
+	DOIs []string
+}
+
+type CreateSectionRequest struct {
+	Title       string
+	Description string
+	// DOIs must already be in the collection. They are moved out of any other section.
+	DOIs []string
+}
+
+// UpdateSectionRequest changes a section. Nil fields are left unchanged.
+type UpdateSectionRequest struct {
+	Title       *string
+	Description *string
+	// Position is the zero-based index the section should be moved to among the collection's sections.
+	// Values past the end move the section to the end.
+	Position *int
+	// DOIs, if not nil, replace the DOIs in the section. They must already be in the collection and are
+	// moved out of any other section. An empty, non-nil value removes all DOIs from the section.
+	DOIs []string
 }
 
 type DOIUpdate struct {
 	Add    []DOI
 	Remove []string
+	// Annotate is applied after Add, so it may include DOIs being added.
+	Annotate []DOIAnnotation
+	// Order, if not nil, is the new order of the collection's DOIs after Add and Remove are applied.
+	// It must contain every DOI that will be in the collection, or the update fails with ErrDOIOrderMismatch.
+	Order []string
+}
+
+// DOIAnnotation changes the label and note of a DOI in a collection.
+// A nil Label or Note leaves the current value unchanged and an empty one removes it.
+type DOIAnnotation struct {
+	DOI   string
+	Label *string
+	Note  *string
 }
 
 type UpdateCollectionRequest struct {
@@ -82,4 +259,148 @@ type UpdateCollectionRequest struct {
 	License     *string
 	Tags        []string
 	DOIs        DOIUpdate
+	// ExpectedVersion, if not nil, makes the update conditional: it fails with ErrCollectionVersionMismatch
+	// unless the collection's current Version is *ExpectedVersion.
+	ExpectedVersion *int64
+}
+
+type CollectionMember struct {
+	UserID     int64
+	UserNodeID string
+	FirstName  *string
+	LastName   *string
+	Role       role.Role
+}
+
+type GranteeType string
+
+const (
+	TeamGrantee         GranteeType = "team"
+	OrganizationGrantee GranteeType = "organization"
+)
+
+// MaxGrantRole is the highest role that can be granted to a team or organization.
+// Owners are always individual users.
+const MaxGrantRole = role.Manager
+
+// CollectionGrant is a role on a collection given to every member of a team or organization.
+type CollectionGrant struct {
+	GranteeType   GranteeType
+	GranteeID     int64
+	GranteeNodeID string
+	Name          string
+	Role          role.Role
+}
+
+// EventType is the kind of change recorded by an Event.
+type EventType string
+
+const (
+	CreatedEvent              EventType = "created"
+	RenamedEvent              EventType = "renamed"
+	DescriptionChangedEvent   EventType = "description_changed"
+	LicenseChangedEvent       EventType = "license_changed"
+	TagsChangedEvent          EventType = "tags_changed"
+	DOIsAddedEvent            EventType = "dois_added"
+	DOIsRemovedEvent          EventType = "dois_removed"
+	DOIsAnnotatedEvent        EventType = "dois_annotated"
+	PublishStartedEvent       EventType = "publish_started"
+	PublishFinishedEvent      EventType = "publish_finished"
+	UnpublishStartedEvent     EventType = "unpublish_started"
+	UnpublishFinishedEvent    EventType = "unpublish_finished"
+	MemberAddedEvent          EventType = "member_added"
+	MemberRoleChangedEvent    EventType = "member_role_changed"
+	MemberRemovedEvent        EventType = "member_removed"
+	OwnershipTransferredEvent EventType = "ownership_transferred"
+	TrashedEvent              EventType = "trashed"
+	RestoredEvent             EventType = "restored"
+	DOIsReorderedEvent        EventType = "dois_reordered"
+	GrantAddedEvent           EventType = "grant_added"
+	GrantRoleChangedEvent     EventType = "grant_role_changed"
+	GrantRemovedEvent         EventType = "grant_removed"
+	SectionCreatedEvent       EventType = "section_created"
+	SectionUpdatedEvent       EventType = "section_updated"
+	SectionDeletedEvent       EventType = "section_deleted"
+)
+
+// EventDiff describes what an event changed. Keys are field names. A field whose value changed maps to a Change,
+// while other keys identify what was changed, for example the DOIs added to the collection or the member whose role changed.
+type EventDiff map[string]any
+
+// Change is the value of a field before and after an event. Old is nil if the field had no value before, and New is nil
+// if it has none after.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Event is an entry in a collection's activity log.
+type Event struct {
+	ID   int64
+	Type EventType
+	// User made the change. Nil if the user is unknown or has been deleted.
+	User *Actor
+	// Diff is the JSON encoding of an EventDiff
+	Diff      json.RawMessage
+	CreatedAt time.Time
+}
+
+// Actor is the user who changed a collection or took a snapshot of it.
+type Actor struct {
+	UserID     int64
+	UserNodeID string
+	FirstName  *string
+	LastName   *string
+}
+
+type GetCollectionEventsResponse struct {
+	Limit  int
+	Offset int
+	// Events are most recent first.
+	Events     []Event
+	TotalCount int
+}
+
+// SnapshotState is the draft state of a collection saved by a Snapshot. It is stored as JSON.
+type SnapshotState struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	License     *string  `json:"license"`
+	Tags        []string `json:"tags"`
+	// DOIs are in collection order.
+	DOIs DOIs `json:"dois"`
+}
+
+// SnapshotState returns the current state of the collection in the form saved by a Snapshot.
+func (r GetCollectionResponse) SnapshotState() SnapshotState {
+	return SnapshotState{
+		Name:        r.Name,
+		Description: r.Description,
+		License:     r.License,
+		Tags:        r.Tags,
+		DOIs:        r.DOIs,
+	}
+}
+
+// SnapshotSummary is a Snapshot without its state.
+type SnapshotSummary struct {
+	ID   int64
+	Name string
+	// CreatedBy is nil if the user is unknown or has been deleted.
+	CreatedBy *Actor
+	CreatedAt time.Time
+}
+
+// Snapshot is a named copy of the name, description, license, tags, and ordered DOIs of a collection.
+type Snapshot struct {
+	SnapshotSummary
+	State SnapshotState
+}
+
+type GetSnapshotsResponse struct {
+	Limit  int
+	Offset int
+	// Snapshots are most recent first.
+	Snapshots  []SnapshotSummary
+	TotalCount int
 }
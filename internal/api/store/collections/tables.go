@@ -1,6 +1,7 @@
 package collections
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/pennsieve/collections-service/internal/api/datasource"
@@ -19,6 +20,9 @@ type Collection struct {
 	NodeID      string    `db:"node_id"`
 	License     *string   `db:"license"`
 	Tags        []string  `db:"tags"`
+	// DeletedAt is set while the collection is in the trash.
+	DeletedAt *time.Time `db:"deleted_at"`
+	Version   int64      `db:"version"`
 }
 
 type CollectionUser struct {
@@ -30,6 +34,24 @@ type CollectionUser struct {
 	Role          PgxRole           `db:"role"`
 }
 
+type CollectionTeam struct {
+	CollectionID  int64             `db:"collection_id"`
+	TeamID        int64             `db:"team_id"`
+	PermissionBit pgdb.DbPermission `db:"permission_bit"`
+	CreatedAt     time.Time         `db:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"`
+	Role          PgxRole           `db:"role"`
+}
+
+type CollectionOrganization struct {
+	CollectionID   int64             `db:"collection_id"`
+	OrganizationID int64             `db:"organization_id"`
+	PermissionBit  pgdb.DbPermission `db:"permission_bit"`
+	CreatedAt      time.Time         `db:"created_at"`
+	UpdatedAt      time.Time         `db:"updated_at"`
+	Role           PgxRole           `db:"role"`
+}
+
 // PgxRole is a wrapper around role.Role that implements pgtype.TextScanner and pgtype.TextValuer
 // so that we can scan into them and use them as query parameters
 type PgxRole role.Role
@@ -61,8 +83,22 @@ type CollectionDOI struct {
 	CollectionID int64                    `db:"collection_id"`
 	DOI          string                   `db:"doi"`
 	Datasource   datasource.DOIDatasource `db:"datasource"`
+	Position     int                      `db:"position"`
+	Label        *string                  `db:"label"`
+	Note         *string                  `db:"note"`
 	UpdatedAt    time.Time                `db:"updated_at"`
 	CreatedAt    time.Time                `db:"created_at"`
+	SectionID    *int64                   `db:"section_id"`
+}
+
+type CollectionSection struct {
+	ID           int64     `db:"id"`
+	CollectionID int64     `db:"collection_id"`
+	Title        string    `db:"title"`
+	Description  string    `db:"description"`
+	Position     int       `db:"position"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	CreatedAt    time.Time `db:"created_at"`
 }
 
 type PublishStatus struct {
@@ -75,3 +111,35 @@ type PublishStatus struct {
 	// nil if the user is deleted.
 	UserID *int64 `db:"user_id"`
 }
+
+type CollectionEvent struct {
+	ID           int64           `db:"id"`
+	CollectionID int64           `db:"collection_id"`
+	UserID       *int64          `db:"user_id"`
+	Type         EventType       `db:"type"`
+	Diff         json.RawMessage `db:"diff"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+type CollectionSnapshot struct {
+	ID           int64           `db:"id"`
+	CollectionID int64           `db:"collection_id"`
+	Name         string          `db:"name"`
+	UserID       *int64          `db:"user_id"`
+	State        json.RawMessage `db:"state"`
+	CreatedAt    time.Time       `db:"created_at"`
+}
+
+type CollectionPublishJob struct {
+	ID                 string               `db:"id"`
+	CollectionID       int64                `db:"collection_id"`
+	UserID             *int64               `db:"user_id"`
+	Status             publishing.JobStatus `db:"status"`
+	Step               *publishing.JobStep  `db:"step"`
+	Attempts           int                  `db:"attempts"`
+	Error              *string              `db:"error"`
+	PublishedDatasetID *int                 `db:"published_dataset_id"`
+	PublishedVersion   *int                 `db:"published_version"`
+	CreatedAt          time.Time            `db:"created_at"`
+	UpdatedAt          time.Time            `db:"updated_at"`
+}
@@ -0,0 +1,142 @@
+package discovercache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"log/slog"
+	"time"
+)
+
+type Store interface {
+	// Get returns the cached entries for the given DOIs, keyed by DOI. DOIs that have never been cached are missing
+	// from the map. Entries are returned regardless of age; it is up to the caller to decide if they are too old.
+	Get(ctx context.Context, dois []string) (map[string]Entry, error)
+	// Put saves Discover's response for the given DOIs, replacing any existing entries and setting
+	// their FetchedAt to now.
+	Put(ctx context.Context, published map[string]dto.PublicDataset, unpublished map[string]dto.Tombstone) error
+}
+
+// Entry is what Discover returned for a DOI the last time it was asked.
+// Exactly one of Published or Tombstone is non-nil.
+type Entry struct {
+	DOI       string
+	Published *dto.PublicDataset
+	Tombstone *dto.Tombstone
+	FetchedAt time.Time
+}
+
+// PostgresStore always takes its own connection from db rather than using postgres.Connect.
+// Discover lookups are made from concurrent goroutines, which cannot share a connection, and
+// cache writes should not be rolled back with the caller's transaction.
+type PostgresStore struct {
+	db           postgres.DB
+	databaseName string
+	logger       *slog.Logger
+}
+
+func NewPostgresStore(db postgres.DB, collectionsDatabaseName string, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{
+		db:           db,
+		databaseName: collectionsDatabaseName,
+		logger:       logger.With(slog.String("type", "discovercache.PostgresStore")),
+	}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, dois []string) (map[string]Entry, error) {
+	entries := make(map[string]Entry, len(dois))
+	if len(dois) == 0 {
+		return entries, nil
+	}
+	conn, err := s.db.Connect(ctx, s.databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("Get error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `SELECT doi, published, tombstone, fetched_at FROM collections.discover_dataset_cache WHERE doi = ANY(@dois)`
+	args := pgx.NamedArgs{"dois": dois}
+
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	cached, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Entry, error) {
+		var entry Entry
+		var published, tombstone []byte
+		if err := row.Scan(&entry.DOI, &published, &tombstone, &entry.FetchedAt); err != nil {
+			return Entry{}, err
+		}
+		if published != nil {
+			entry.Published = &dto.PublicDataset{}
+			if err := json.Unmarshal(published, entry.Published); err != nil {
+				return Entry{}, fmt.Errorf("error unmarshalling cached dataset %s: %w", entry.DOI, err)
+			}
+		}
+		if tombstone != nil {
+			entry.Tombstone = &dto.Tombstone{}
+			if err := json.Unmarshal(tombstone, entry.Tombstone); err != nil {
+				return Entry{}, fmt.Errorf("error unmarshalling cached tombstone %s: %w", entry.DOI, err)
+			}
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached datasets: %w", err)
+	}
+	for _, entry := range cached {
+		entries[entry.DOI] = entry
+	}
+	return entries, nil
+}
+
+func (s *PostgresStore) Put(ctx context.Context, published map[string]dto.PublicDataset, unpublished map[string]dto.Tombstone) error {
+	// fields are omitted rather than null so that jsonb_to_recordset gives SQL NULLs
+	type putEntry struct {
+		DOI       string             `json:"doi"`
+		Published *dto.PublicDataset `json:"published,omitempty"`
+		Tombstone *dto.Tombstone     `json:"tombstone,omitempty"`
+	}
+	putEntries := make([]putEntry, 0, len(published)+len(unpublished))
+	for doi, dataset := range published {
+		putEntries = append(putEntries, putEntry{DOI: doi, Published: &dataset})
+	}
+	for doi, tombstone := range unpublished {
+		putEntries = append(putEntries, putEntry{DOI: doi, Tombstone: &tombstone})
+	}
+	if len(putEntries) == 0 {
+		return nil
+	}
+	entriesJSON, err := json.Marshal(putEntries)
+	if err != nil {
+		return fmt.Errorf("error marshalling datasets to cache: %w", err)
+	}
+
+	conn, err := s.db.Connect(ctx, s.databaseName)
+	if err != nil {
+		return fmt.Errorf("Put error connecting to database %s: %w", s.databaseName, err)
+	}
+	defer s.closeConn(ctx, conn)
+
+	query := `INSERT INTO collections.discover_dataset_cache (doi, published, tombstone, fetched_at)
+              SELECT e.doi, e.published, e.tombstone, @fetched_at
+              FROM jsonb_to_recordset(@entries::jsonb) AS e(doi TEXT, published JSONB, tombstone JSONB)
+              ON CONFLICT (doi) DO UPDATE SET published  = EXCLUDED.published,
+                                              tombstone  = EXCLUDED.tombstone,
+                                              fetched_at = EXCLUDED.fetched_at`
+	args := pgx.NamedArgs{
+		"entries":    string(entriesJSON),
+		"fetched_at": time.Now().UTC(),
+	}
+	if _, err := conn.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("error caching %d datasets: %w", len(putEntries), err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) closeConn(ctx context.Context, conn postgres.Conn) {
+	if err := conn.Close(ctx); err != nil {
+		s.logger.Warn("error closing discovercache.PostgresStore DB connection", slog.Any("error", err))
+	}
+}
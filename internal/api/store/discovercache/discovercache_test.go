@@ -0,0 +1,140 @@
+package discovercache_test
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/discovercache"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	config := test.PostgresDBConfig(t)
+
+	for _, tt := range []struct {
+		scenario string
+		tstFunc  func(t *testing.T, store *discovercache.PostgresStore, dois *testDOIs)
+	}{
+		{"Get should return Put datasets and tombstones", testPutAndGet},
+		{"Get should leave out DOIs that were never cached", testGetMissing},
+		{"Put should replace existing entries", testPutReplaces},
+		{"Put with nothing to cache should do nothing", testPutEmpty},
+	} {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, config)
+			dois := &testDOIs{}
+			t.Cleanup(func() {
+				dois.cleanUp(ctx, t, db, config.CollectionsDatabase)
+			})
+
+			store := discovercache.NewPostgresStore(db, config.CollectionsDatabase, logging.Default)
+
+			tt.tstFunc(t, store, dois)
+		})
+	}
+}
+
+// testDOIs creates DOIs for a test and deletes their cache entries afterward, since the cache is not tied to any
+// collection that the usual fixtures would clean up.
+type testDOIs struct {
+	dois []string
+}
+
+func (d *testDOIs) newDOI() string {
+	doi := apitest.NewPennsieveDOI().Value
+	d.dois = append(d.dois, doi)
+	return doi
+}
+
+func (d *testDOIs) cleanUp(ctx context.Context, t *testing.T, db postgres.DB, databaseName string) {
+	if len(d.dois) == 0 {
+		return
+	}
+	conn, err := db.Connect(ctx, databaseName)
+	require.NoError(t, err)
+	defer test.CloseConnection(ctx, t, conn)
+	_, err = conn.Exec(ctx, "DELETE FROM collections.discover_dataset_cache WHERE doi = ANY($1)", d.dois)
+	require.NoError(t, err)
+}
+
+func testPutAndGet(t *testing.T, store *discovercache.PostgresStore, dois *testDOIs) {
+	ctx := context.Background()
+
+	published := apitest.NewPublicDataset(dois.newDOI(), apitest.NewBanner())
+	tombstone := apitest.NewTombstone(dois.newDOI(), "Unpublished")
+
+	before := time.Now().UTC().Add(-time.Second)
+	require.NoError(t, store.Put(ctx,
+		map[string]dto.PublicDataset{published.DOI: published},
+		map[string]dto.Tombstone{tombstone.DOI: tombstone}))
+
+	entries, err := store.Get(ctx, []string{published.DOI, tombstone.DOI})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	publishedEntry := entries[published.DOI]
+	assert.Equal(t, published.DOI, publishedEntry.DOI)
+	require.NotNil(t, publishedEntry.Published)
+	assert.Equal(t, published, *publishedEntry.Published)
+	assert.Nil(t, publishedEntry.Tombstone)
+	assert.True(t, publishedEntry.FetchedAt.After(before))
+
+	tombstoneEntry := entries[tombstone.DOI]
+	assert.Nil(t, tombstoneEntry.Published)
+	require.NotNil(t, tombstoneEntry.Tombstone)
+	assert.Equal(t, tombstone.DOI, tombstoneEntry.Tombstone.DOI)
+	assert.Equal(t, tombstone.Status, tombstoneEntry.Tombstone.Status)
+	assert.True(t, tombstoneEntry.FetchedAt.After(before))
+}
+
+func testGetMissing(t *testing.T, store *discovercache.PostgresStore, dois *testDOIs) {
+	ctx := context.Background()
+
+	published := apitest.NewPublicDataset(dois.newDOI(), apitest.NewBanner())
+	require.NoError(t, store.Put(ctx, map[string]dto.PublicDataset{published.DOI: published}, nil))
+
+	entries, err := store.Get(ctx, []string{published.DOI, dois.newDOI()})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Contains(t, entries, published.DOI)
+
+	entries, err = store.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func testPutReplaces(t *testing.T, store *discovercache.PostgresStore, dois *testDOIs) {
+	ctx := context.Background()
+
+	doi := dois.newDOI()
+	published := apitest.NewPublicDataset(doi, apitest.NewBanner())
+	require.NoError(t, store.Put(ctx, map[string]dto.PublicDataset{doi: published}, nil))
+
+	first, err := store.Get(ctx, []string{doi})
+	require.NoError(t, err)
+
+	// the dataset has since been unpublished
+	tombstone := apitest.NewTombstone(doi, "Unpublished")
+	require.NoError(t, store.Put(ctx, nil, map[string]dto.Tombstone{doi: tombstone}))
+
+	second, err := store.Get(ctx, []string{doi})
+	require.NoError(t, err)
+	require.Contains(t, second, doi)
+	assert.Nil(t, second[doi].Published)
+	require.NotNil(t, second[doi].Tombstone)
+	assert.Equal(t, tombstone.Status, second[doi].Tombstone.Status)
+	assert.False(t, second[doi].FetchedAt.Before(first[doi].FetchedAt))
+}
+
+func testPutEmpty(t *testing.T, store *discovercache.PostgresStore, _ *testDOIs) {
+	ctx := context.Background()
+	assert.NoError(t, store.Put(ctx, nil, nil))
+	assert.NoError(t, store.Put(ctx, map[string]dto.PublicDataset{}, map[string]dto.Tombstone{}))
+}
@@ -3,15 +3,26 @@ package manifests
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"log/slog"
+	"slices"
 )
 
+var ErrManifestNotFound = errors.New("manifest not found")
+
 type Store interface {
 	SaveManifest(ctx context.Context, key string, manifest publishing.ManifestV5) (SaveManifestResponse, error)
+	// GetManifest returns the latest version of the manifest with the given key, or ErrManifestNotFound if there is none.
+	GetManifest(ctx context.Context, key string) (publishing.ManifestV5, error)
+	// GetManifestVersions returns the versions of the manifest with the given key, newest first.
+	// Returns an empty slice if there are none.
+	GetManifestVersions(ctx context.Context, key string) ([]ManifestVersion, error)
 	DeleteManifestVersion(ctx context.Context, key string, s3VersionID string) error
 }
 
@@ -56,6 +67,66 @@ func (s *S3Store) SaveManifest(ctx context.Context, key string, manifest publish
 	return SaveManifestResponse{S3VersionID: versionId}, nil
 }
 
+func (s *S3Store) GetManifest(ctx context.Context, key string) (publishing.ManifestV5, error) {
+	getIn := s3.GetObjectInput{
+		Bucket: aws.String(s.publishBucket),
+		Key:    aws.String(key),
+	}
+	getOut, err := s.s3.GetObject(ctx, &getIn)
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return publishing.ManifestV5{}, ErrManifestNotFound
+		}
+		return publishing.ManifestV5{}, fmt.Errorf("error reading manifest from %s/%s: %w", s.publishBucket, key, err)
+	}
+	defer func() {
+		if err := getOut.Body.Close(); err != nil {
+			s.logger.Warn("error closing manifest body",
+				slog.String("logSource", "S3Store"),
+				slog.String("key", key),
+				slog.Any("error", err))
+		}
+	}()
+	var manifest publishing.ManifestV5
+	if err := json.NewDecoder(getOut.Body).Decode(&manifest); err != nil {
+		return publishing.ManifestV5{}, fmt.Errorf("error decoding manifest from %s/%s: %w", s.publishBucket, key, err)
+	}
+	return manifest, nil
+}
+
+func (s *S3Store) GetManifestVersions(ctx context.Context, key string) ([]ManifestVersion, error) {
+	listIn := s3.ListObjectVersionsInput{
+		Bucket: aws.String(s.publishBucket),
+		Prefix: aws.String(key),
+	}
+	versions := make([]ManifestVersion, 0)
+	paginator := s3.NewListObjectVersionsPaginator(s.s3, &listIn)
+	for paginator.HasMorePages() {
+		listOut, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing manifest versions at %s/%s: %w", s.publishBucket, key, err)
+		}
+		// Delete markers are in listOut.DeleteMarkers, so these are all real versions
+		for _, version := range listOut.Versions {
+			// the prefix also matches any longer keys
+			if aws.ToString(version.Key) != key {
+				continue
+			}
+			versions = append(versions, ManifestVersion{
+				S3VersionID:  aws.ToString(version.VersionId),
+				LastModified: aws.ToTime(version.LastModified),
+				IsLatest:     aws.ToBool(version.IsLatest),
+			})
+		}
+	}
+	// S3 already returns the versions of a key newest first, but we don't want to depend on that
+	slices.SortStableFunc(versions, func(a, b ManifestVersion) int {
+		return b.LastModified.Compare(a.LastModified)
+	})
+	return versions, nil
+}
+
 func (s *S3Store) DeleteManifestVersion(ctx context.Context, key string, s3VersionID string) error {
 	deleteIn := s3.DeleteObjectInput{
 		Bucket:    aws.String(s.publishBucket),
@@ -9,6 +9,7 @@ import (
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/fixtures"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -21,6 +22,10 @@ func TestS3Store(t *testing.T) {
 		{"SaveManifest should save the manifest correctly", testSaveManifest},
 		{"SaveManifest should save manifest versions correctly", testSaveManifestVersions},
 		{"DeleteManifestVersion should delete the manifest version correctly", testDeleteManifestVersion},
+		{"GetManifest should return the latest manifest version", testGetManifest},
+		{"GetManifest should return ErrManifestNotFound if there is no manifest", testGetManifestNotFound},
+		{"GetManifestVersions should return the manifest versions newest first", testGetManifestVersions},
+		{"GetManifestVersions should return no versions if there is no manifest", testGetManifestVersionsNone},
 	}
 
 	for _, tt := range tests {
@@ -154,3 +159,86 @@ func testDeleteManifestVersion(t *testing.T, minio *fixtures.MinIO) {
 	minio.RequireNoObject(ctx, t, bucket, key)
 
 }
+
+func testGetManifest(t *testing.T, minio *fixtures.MinIO) {
+	ctx := context.Background()
+
+	bucket := minio.CreatePublishBucket(ctx, t)
+
+	manifestStore := manifests.NewS3Store(test.DefaultMinIOS3Client(ctx, t), bucket, logging.Default)
+
+	expectedDatasetID := 52
+	key := publishing.ManifestS3Key(expectedDatasetID)
+
+	for version := 1; version <= 2; version++ {
+		_, err := manifestStore.SaveManifest(ctx, key, apitest.NewExpectedManifest(t,
+			apitest.WithManifestPennsieveDatasetID(expectedDatasetID),
+			apitest.WithManifestVersion(version),
+		))
+		require.NoError(t, err)
+	}
+
+	actual, err := manifestStore.GetManifest(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, expectedDatasetID, actual.PennsieveDatasetID)
+	require.Equal(t, 2, actual.Version)
+}
+
+func testGetManifestNotFound(t *testing.T, minio *fixtures.MinIO) {
+	ctx := context.Background()
+
+	bucket := minio.CreatePublishBucket(ctx, t)
+
+	manifestStore := manifests.NewS3Store(test.DefaultMinIOS3Client(ctx, t), bucket, logging.Default)
+
+	_, err := manifestStore.GetManifest(ctx, publishing.ManifestS3Key(53))
+	require.ErrorIs(t, err, manifests.ErrManifestNotFound)
+}
+
+func testGetManifestVersions(t *testing.T, minio *fixtures.MinIO) {
+	ctx := context.Background()
+
+	bucket := minio.CreatePublishBucket(ctx, t)
+
+	manifestStore := manifests.NewS3Store(test.DefaultMinIOS3Client(ctx, t), bucket, logging.Default)
+
+	expectedDatasetID := 5
+	key := publishing.ManifestS3Key(expectedDatasetID)
+
+	var expectedS3VersionIDs []string
+	for version := 1; version <= 3; version++ {
+		response, err := manifestStore.SaveManifest(ctx, key, apitest.NewExpectedManifest(t,
+			apitest.WithManifestPennsieveDatasetID(expectedDatasetID),
+			apitest.WithManifestVersion(version),
+		))
+		require.NoError(t, err)
+		expectedS3VersionIDs = append([]string{response.S3VersionID}, expectedS3VersionIDs...)
+	}
+
+	// an object whose key has the manifest key as a prefix should not be included
+	_, err := manifestStore.SaveManifest(ctx, key+".bak", apitest.NewExpectedManifest(t,
+		apitest.WithManifestPennsieveDatasetID(expectedDatasetID),
+	))
+	require.NoError(t, err)
+
+	versions, err := manifestStore.GetManifestVersions(ctx, key)
+	require.NoError(t, err)
+	require.Len(t, versions, len(expectedS3VersionIDs))
+	for i, version := range versions {
+		assert.Equal(t, expectedS3VersionIDs[i], version.S3VersionID)
+		assert.Equal(t, i == 0, version.IsLatest)
+		assert.False(t, version.LastModified.IsZero())
+	}
+}
+
+func testGetManifestVersionsNone(t *testing.T, minio *fixtures.MinIO) {
+	ctx := context.Background()
+
+	bucket := minio.CreatePublishBucket(ctx, t)
+
+	manifestStore := manifests.NewS3Store(test.DefaultMinIOS3Client(ctx, t), bucket, logging.Default)
+
+	versions, err := manifestStore.GetManifestVersions(ctx, publishing.ManifestS3Key(5))
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
@@ -1,5 +1,14 @@
 package manifests
 
+import "time"
+
 type SaveManifestResponse struct {
 	S3VersionID string
 }
+
+// ManifestVersion is one S3 version of a manifest.
+type ManifestVersion struct {
+	S3VersionID  string
+	LastModified time.Time
+	IsLatest     bool
+}
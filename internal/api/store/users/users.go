@@ -29,9 +29,9 @@ func NewPostgresStore(db postgres.DB, usersDatabaseName string, logger *slog.Log
 }
 
 func (s *PostgresStore) GetUser(ctx context.Context, userID int64) (GetUserResponse, error) {
-	conn, err := s.db.Connect(ctx, s.databaseName)
+	conn, err := postgres.Connect(ctx, s.db, s.databaseName)
 	if err != nil {
-		return GetUserResponse{}, fmt.Errorf("CreateCollection error connecting to database %s: %w", s.databaseName, err)
+		return GetUserResponse{}, fmt.Errorf("GetUser error connecting to database %s: %w", s.databaseName, err)
 	}
 	defer s.closeConn(ctx, conn)
 
@@ -50,7 +50,7 @@ func (s *PostgresStore) GetUser(ctx context.Context, userID int64) (GetUserRespo
 
 }
 
-func (s *PostgresStore) closeConn(ctx context.Context, conn *pgx.Conn) {
+func (s *PostgresStore) closeConn(ctx context.Context, conn postgres.Conn) {
 	if err := conn.Close(ctx); err != nil {
 		s.logger.Warn("error closing users.PostgresStore DB connection", slog.Any("error", err))
 	}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"github.com/pennsieve/collections-service/internal/api/apierrors"
 	"github.com/pennsieve/collections-service/internal/api/dto"
+	"regexp"
 	"slices"
 	"strings"
 )
 
+// MaxCollectionNameLength is the maximum length in bytes of a collection name.
+const MaxCollectionNameLength = 255
+
 func CollectionName(value string) error {
 	if valueLen := len(value); valueLen == 0 {
 		return apierrors.NewBadRequestError("collection name cannot be empty")
-	} else if valueLen > 255 {
-		return apierrors.NewBadRequestError("collection name cannot have more than 255 characters")
+	} else if valueLen > MaxCollectionNameLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("collection name cannot have more than %d characters", MaxCollectionNameLength))
 	}
 	return nil
 }
@@ -25,6 +29,83 @@ func CollectionDescription(value string) error {
 	return nil
 }
 
+// MaxDOILabelLength is the maximum length in bytes of the label of a DOI in a collection.
+const MaxDOILabelLength = 64
+
+// MaxDOINoteLength is the maximum length in bytes of the note on a DOI in a collection.
+const MaxDOINoteLength = 1000
+
+func DOILabel(value string) error {
+	if len(value) > MaxDOILabelLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("DOI label cannot have more than %d characters", MaxDOILabelLength))
+	}
+	return nil
+}
+
+func DOINote(value string) error {
+	if len(value) > MaxDOINoteLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("DOI note cannot have more than %d characters", MaxDOINoteLength))
+	}
+	return nil
+}
+
+// MaxSectionTitleLength is the maximum length in bytes of the title of a section of a collection.
+const MaxSectionTitleLength = 255
+
+// MaxSectionDescriptionLength is the maximum length in bytes of the description of a section of a collection.
+const MaxSectionDescriptionLength = 1000
+
+func SectionTitle(value string) error {
+	if valueLen := len(value); valueLen == 0 {
+		return apierrors.NewBadRequestError("section title cannot be empty")
+	} else if valueLen > MaxSectionTitleLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("section title cannot have more than %d characters", MaxSectionTitleLength))
+	}
+	return nil
+}
+
+func SectionDescription(value string) error {
+	if len(value) > MaxSectionDescriptionLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("section description cannot have more than %d characters", MaxSectionDescriptionLength))
+	}
+	return nil
+}
+
+// MaxSnapshotNameLength is the maximum length in bytes of the name of a collection snapshot.
+const MaxSnapshotNameLength = 255
+
+func SnapshotName(value string) error {
+	if valueLen := len(value); valueLen == 0 {
+		return apierrors.NewBadRequestError("snapshot name cannot be empty")
+	} else if valueLen > MaxSnapshotNameLength {
+		return apierrors.NewBadRequestError(fmt.Sprintf("snapshot name cannot have more than %d characters", MaxSnapshotNameLength))
+	}
+	return nil
+}
+
+// doiPattern is a loose check that a string is a DOI: a "10." directory indicator and registrant code, a slash,
+// and a non-empty suffix without whitespace.
+var doiPattern = regexp.MustCompile(`^10\.\d{4,9}(\.\d+)*/\S+$`)
+
+// IsDOI reports whether value looks like a DOI.
+func IsDOI(value string) bool {
+	return doiPattern.MatchString(value)
+}
+
+// DOIs returns a Bad Request *apierrors.Error listing any values that do not look like DOIs.
+func DOIs(values []string) error {
+	var invalid []string
+	for _, value := range values {
+		if !IsDOI(value) {
+			invalid = append(invalid, value)
+		}
+	}
+	if len(invalid) > 0 {
+		return apierrors.NewBadRequestError(fmt.Sprintf("request contains invalid DOIs: %s", strings.Join(invalid, ", ")))
+	}
+	return nil
+}
+
 func IntQueryParamValue(key string, value int, requiredMin int) error {
 	if value < requiredMin {
 		return apierrors.NewBadRequestError(fmt.Sprintf("query param %s cannot be less than %d: %d", key, requiredMin, value))
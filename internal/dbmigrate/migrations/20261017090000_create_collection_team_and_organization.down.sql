@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS collection_organization CASCADE;
+
+DROP TABLE IF EXISTS collection_team CASCADE;
@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS collection_team
+(
+    collection_id  integer             not null
+        references collections
+            on delete cascade,
+    team_id        integer             not null
+        references pennsieve.teams
+            on delete cascade,
+    permission_bit integer   default 0 not null,
+    created_at     timestamp default CURRENT_TIMESTAMP,
+    updated_at     timestamp default CURRENT_TIMESTAMP,
+    role           varchar(50),
+    primary key (collection_id, team_id)
+);
+
+CREATE INDEX collection_team_team_perm_coll_idx
+    ON collection_team (team_id, permission_bit, collection_id);
+
+CREATE TRIGGER collection_team_update_updated_at
+    BEFORE UPDATE
+    ON collection_team
+    FOR EACH ROW
+EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS collection_organization
+(
+    collection_id   integer             not null
+        references collections
+            on delete cascade,
+    organization_id integer             not null
+        references pennsieve.organizations
+            on delete cascade,
+    permission_bit  integer   default 0 not null,
+    created_at      timestamp default CURRENT_TIMESTAMP,
+    updated_at      timestamp default CURRENT_TIMESTAMP,
+    role            varchar(50),
+    primary key (collection_id, organization_id)
+);
+
+CREATE INDEX collection_organization_org_perm_coll_idx
+    ON collection_organization (organization_id, permission_bit, collection_id);
+
+CREATE TRIGGER collection_organization_update_updated_at
+    BEFORE UPDATE
+    ON collection_organization
+    FOR EACH ROW
+EXECUTE PROCEDURE update_updated_at_column();
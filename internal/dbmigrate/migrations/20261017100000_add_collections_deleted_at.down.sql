@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS collections_deleted_at_idx;
+
+ALTER TABLE collections
+    DROP COLUMN IF EXISTS deleted_at;
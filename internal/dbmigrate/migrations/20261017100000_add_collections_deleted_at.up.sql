@@ -0,0 +1,7 @@
+ALTER TABLE collections
+    ADD COLUMN IF NOT EXISTS deleted_at timestamp;
+
+-- only the trash is looked up by deleted_at
+CREATE INDEX IF NOT EXISTS collections_deleted_at_idx
+    ON collections (deleted_at)
+    WHERE deleted_at IS NOT NULL;
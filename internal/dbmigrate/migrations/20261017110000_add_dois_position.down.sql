@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS dois_collection_id_position_idx;
+
+ALTER TABLE dois
+    DROP COLUMN IF EXISTS position;
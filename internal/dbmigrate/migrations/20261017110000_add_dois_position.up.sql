@@ -0,0 +1,15 @@
+ALTER TABLE dois
+    ADD COLUMN IF NOT EXISTS position integer;
+
+-- existing DOIs keep their insertion order
+UPDATE dois
+SET position = ordered.position
+FROM (SELECT id, row_number() OVER (PARTITION BY collection_id ORDER BY id) - 1 AS position
+      FROM dois) AS ordered
+WHERE dois.id = ordered.id;
+
+ALTER TABLE dois
+    ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS dois_collection_id_position_idx
+    ON dois (collection_id, position, id);
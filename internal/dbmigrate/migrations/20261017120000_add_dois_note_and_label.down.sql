@@ -0,0 +1,3 @@
+ALTER TABLE dois
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS label;
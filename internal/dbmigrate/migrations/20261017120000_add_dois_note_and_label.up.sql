@@ -0,0 +1,3 @@
+ALTER TABLE dois
+    ADD COLUMN IF NOT EXISTS label varchar(64),
+    ADD COLUMN IF NOT EXISTS note  varchar(1000);
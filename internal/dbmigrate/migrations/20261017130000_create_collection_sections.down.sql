@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS dois_section_id_idx;
+
+ALTER TABLE dois
+    DROP COLUMN IF EXISTS section_id;
+
+DROP TABLE IF EXISTS collection_sections CASCADE;
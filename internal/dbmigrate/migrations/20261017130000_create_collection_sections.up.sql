@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS collection_sections
+(
+    id            SERIAL PRIMARY KEY,
+    collection_id INTEGER       NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    title         VARCHAR(255)  NOT NULL CHECK (title <> ''),
+    description   VARCHAR(1000) NOT NULL DEFAULT '',
+    position      INTEGER       NOT NULL,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX collection_sections_collection_id_position_idx
+    ON collection_sections (collection_id, position, id);
+
+CREATE TRIGGER collection_sections_update_updated_at
+    BEFORE UPDATE
+    ON collection_sections
+    FOR EACH ROW
+EXECUTE PROCEDURE update_updated_at_column();
+
+-- A DOI belongs to at most one section. Deleting a section leaves its DOIs in the collection, but unsectioned.
+ALTER TABLE dois
+    ADD COLUMN IF NOT EXISTS section_id INTEGER REFERENCES collection_sections (id) ON DELETE SET NULL;
+
+CREATE INDEX dois_section_id_idx
+    ON dois (section_id);
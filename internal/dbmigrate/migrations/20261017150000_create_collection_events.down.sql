@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS collection_events_collection_id_id_idx;
+
+DROP TABLE IF EXISTS collection_events CASCADE;
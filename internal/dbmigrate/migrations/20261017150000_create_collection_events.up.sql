@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS collection_events
+(
+    id            BIGSERIAL PRIMARY KEY,
+    collection_id INTEGER     NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    -- the acting user. NULL if the user has since been deleted.
+    user_id       INTEGER     REFERENCES pennsieve.users (id) ON DELETE SET NULL,
+    type          VARCHAR(50) NOT NULL,
+    diff          JSONB       NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX collection_events_collection_id_id_idx
+    ON collection_events (collection_id, id DESC);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS collection_snapshots
+(
+    id            BIGSERIAL PRIMARY KEY,
+    collection_id INTEGER      NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    name          VARCHAR(255) NOT NULL,
+    -- the user who took the snapshot. NULL if the user has since been deleted.
+    user_id       INTEGER      REFERENCES pennsieve.users (id) ON DELETE SET NULL,
+    -- the collection's name, description, license, tags, and ordered DOIs when the snapshot was taken
+    state         JSONB        NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX collection_snapshots_collection_id_id_idx
+    ON collection_snapshots (collection_id, id DESC);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS collection_publish_jobs_collection_id_created_at_idx;
+
+DROP TABLE IF EXISTS collection_publish_jobs CASCADE;
+
+DROP INDEX IF EXISTS queue_messages_queue_visible_at_idx;
+
+DROP TABLE IF EXISTS queue_messages CASCADE;
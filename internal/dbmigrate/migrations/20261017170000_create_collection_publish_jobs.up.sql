@@ -0,0 +1,36 @@
+-- messages of the Postgres-backed queues. Several queues share the table.
+CREATE TABLE IF NOT EXISTS queue_messages
+(
+    id            BIGSERIAL PRIMARY KEY,
+    queue         VARCHAR(255) NOT NULL,
+    body          TEXT         NOT NULL,
+    -- the number of times the message has been received
+    receive_count INTEGER      NOT NULL DEFAULT 0,
+    -- a received message is hidden from other receivers until its visibility timeout passes
+    visible_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX queue_messages_queue_visible_at_idx
+    ON queue_messages (queue, visible_at);
+
+CREATE TABLE IF NOT EXISTS collection_publish_jobs
+(
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    collection_id        INTEGER      NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+    -- the user who started the publish. NULL if the user has since been deleted.
+    user_id              INTEGER      REFERENCES pennsieve.users (id) ON DELETE SET NULL,
+    status               VARCHAR(50)  NOT NULL,
+    -- the step the job is on, or was on when it last stopped
+    step                 VARCHAR(50),
+    attempts             INTEGER      NOT NULL DEFAULT 0,
+    -- the error of the most recent failed attempt
+    error                TEXT,
+    published_dataset_id INTEGER,
+    published_version    INTEGER,
+    created_at           TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX collection_publish_jobs_collection_id_created_at_idx
+    ON collection_publish_jobs (collection_id, created_at DESC);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS discover_dataset_cache CASCADE;
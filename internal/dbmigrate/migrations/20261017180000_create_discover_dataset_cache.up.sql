@@ -0,0 +1,12 @@
+-- Discover's response for a DOI, so that reading collections does not need a live call to Discover.
+-- Exactly one of published or tombstone is set, depending on whether Discover returned the DOI as published or unpublished.
+CREATE TABLE IF NOT EXISTS discover_dataset_cache
+(
+    doi        VARCHAR(255) PRIMARY KEY,
+    -- the dto.PublicDataset returned by Discover
+    published  JSONB,
+    -- the dto.Tombstone returned by Discover
+    tombstone  JSONB,
+    fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_published_or_tombstone CHECK ((published IS NULL) <> (tombstone IS NULL))
+);
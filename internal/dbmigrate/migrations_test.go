@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	collectionsconfig "github.com/pennsieve/collections-service/internal/dbmigrate"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
 	sharedconfig "github.com/pennsieve/collections-service/internal/shared/config"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/dbmigrate-go/pkg/config"
@@ -23,7 +24,7 @@ import (
 func TestCollectionsMigrator(t *testing.T) {
 	tests := []struct {
 		scenario string
-		tstFunc  func(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn)
+		tstFunc  func(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn)
 	}{
 		{"test up and collections created_at and updated_at", testUp},
 		{"Down runs without error", testDown},
@@ -31,6 +32,7 @@ func TestCollectionsMigrator(t *testing.T) {
 		{"prevent all white space name", testPreventWhiteSpaceName},
 		{"prevent empty DOI", testPreventEmptyDOI},
 		{"test populate datasource", testPopulateDatasource},
+		{"test populate DOI position", testPopulatePosition},
 	}
 
 	// Set up testcontainer that will be used by all tests.
@@ -75,7 +77,7 @@ func TestCollectionsMigrator(t *testing.T) {
 			migrator, err := dbmigrate.NewLocalMigrator(ctx, migrateConfig, migrationSource)
 			require.NoError(t, err)
 
-			// also pass in a plain pgx.Conn to let the test function run any verifications on the migrated schema
+			// also pass in a plain connection to let the test function run any verifications on the migrated schema
 			verificationConn, err := test.NewPostgresDBFromConfig(t,
 				toSharedPostgresDBConfig(migrateConfig.PostgresDB),
 			).Connect(ctx, migrateConfig.PostgresDB.Database)
@@ -92,7 +94,7 @@ func TestCollectionsMigrator(t *testing.T) {
 	}
 }
 
-func testUp(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn) {
+func testUp(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
 
 	require.NoError(t, migrator.Up())
 
@@ -128,14 +130,14 @@ func testUp(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn
 // We don't really use the Down() method for real. Test is here so that
 // if we do write 'down' files something checks that they at least run
 // without error.
-func testDown(t *testing.T, migrator *dbmigrate.DatabaseMigrator, _ *pgx.Conn) {
+func testDown(t *testing.T, migrator *dbmigrate.DatabaseMigrator, _ postgres.Conn) {
 
 	require.NoError(t, migrator.Up())
 
 	require.NoError(t, migrator.Down())
 }
 
-func testPreventEmptyName(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn) {
+func testPreventEmptyName(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
 	require.NoError(t, migrator.Up())
 
 	ctx := context.Background()
@@ -158,7 +160,7 @@ func testPreventEmptyName(t *testing.T, migrator *dbmigrate.DatabaseMigrator, ve
 
 }
 
-func testPreventWhiteSpaceName(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn) {
+func testPreventWhiteSpaceName(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
 	require.NoError(t, migrator.Up())
 
 	ctx := context.Background()
@@ -183,7 +185,7 @@ func testPreventWhiteSpaceName(t *testing.T, migrator *dbmigrate.DatabaseMigrato
 
 }
 
-func testPreventEmptyDOI(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn) {
+func testPreventEmptyDOI(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
 	require.NoError(t, migrator.Up())
 
 	ctx := context.Background()
@@ -199,7 +201,7 @@ func testPreventEmptyDOI(t *testing.T, migrator *dbmigrate.DatabaseMigrator, ver
 	require.NoError(t, err)
 
 	_, err = verificationConn.Exec(ctx,
-		"INSERT INTO collections.dois (collection_id, doi) VALUES (@collection_id, @doi)",
+		"INSERT INTO collections.dois (collection_id, doi, position) VALUES (@collection_id, @doi, 0)",
 		pgx.NamedArgs{
 			"collection_id": collectionID,
 			"doi":           ""},
@@ -215,7 +217,7 @@ func testPreventEmptyDOI(t *testing.T, migrator *dbmigrate.DatabaseMigrator, ver
 
 }
 
-func testPopulateDatasource(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn *pgx.Conn) {
+func testPopulateDatasource(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
 	// run migrations prior to add_datasource_column
 	require.NoError(t, migrator.Migrate(20250422101951))
 
@@ -262,6 +264,57 @@ func testPopulateDatasource(t *testing.T, migrator *dbmigrate.DatabaseMigrator,
 	assert.Equal(t, "External", datasource)
 }
 
+func testPopulatePosition(t *testing.T, migrator *dbmigrate.DatabaseMigrator, verificationConn postgres.Conn) {
+	// run migrations prior to add_dois_position
+	require.NoError(t, migrator.Migrate(20261017100000))
+
+	ctx := context.Background()
+
+	insertCollectionSQL := "INSERT INTO collections.collections (name, description, node_id) VALUES (@name, @description, @node_id) RETURNING id"
+	var collectionID1, collectionID2 int64
+	for _, collectionID := range []*int64{&collectionID1, &collectionID2} {
+		require.NoError(t, verificationConn.QueryRow(ctx,
+			insertCollectionSQL,
+			pgx.NamedArgs{
+				"name":        uuid.NewString(),
+				"description": uuid.NewString(),
+				"node_id":     uuid.NewString()},
+		).Scan(collectionID))
+	}
+
+	doi1 := fmt.Sprintf("10.26275/%s", uuid.NewString())
+	doi2 := fmt.Sprintf("10.26275/%s", uuid.NewString())
+	doi3 := fmt.Sprintf("10.26275/%s", uuid.NewString())
+
+	// interleave the collections' DOIs so that ids are not contiguous within a collection
+	_, err := verificationConn.Exec(ctx,
+		`INSERT INTO collections.dois (collection_id, doi) VALUES (@collection_id_1, @doi_1), (@collection_id_2, @doi_2), (@collection_id_1, @doi_3)`,
+		pgx.NamedArgs{
+			"collection_id_1": collectionID1,
+			"collection_id_2": collectionID2,
+			"doi_1":           doi1,
+			"doi_2":           doi2,
+			"doi_3":           doi3,
+		},
+	)
+	require.NoError(t, err)
+
+	// now run the remaining migrations
+	require.NoError(t, migrator.Up())
+
+	positionQuery := `SELECT position FROM collections.dois WHERE doi = @doi`
+
+	var position int
+	require.NoError(t, verificationConn.QueryRow(ctx, positionQuery, pgx.NamedArgs{"doi": doi1}).Scan(&position))
+	assert.Equal(t, 0, position)
+
+	require.NoError(t, verificationConn.QueryRow(ctx, positionQuery, pgx.NamedArgs{"doi": doi2}).Scan(&position))
+	assert.Equal(t, 0, position)
+
+	require.NoError(t, verificationConn.QueryRow(ctx, positionQuery, pgx.NamedArgs{"doi": doi3}).Scan(&position))
+	assert.Equal(t, 1, position)
+}
+
 func newConfig(t *testing.T, host string, port int) config.Config {
 	t.Helper()
 	defaults := collectionsconfig.ConfigDefaults()
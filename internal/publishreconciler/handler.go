@@ -0,0 +1,171 @@
+package publishreconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pennsieve/collections-service/internal/api/container"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"log"
+	"log/slog"
+	"time"
+)
+
+// LambdaHandler is triggered by a scheduled EventBridge rule.
+type LambdaHandler func(ctx context.Context, event events.EventBridgeEvent) error
+
+// discoverRole is the role on the collection claimed in requests to Discover. There is no user behind
+// a reconciliation, so we use the role that was required to start the publish.
+const discoverRole = role.Owner
+
+func Handler() LambdaHandler {
+	// The reconciler needs the same dependencies and config as the API
+	dependencies, err := container.NewContainer()
+	if err != nil {
+		log.Fatalf("Failed to create publish reconciler container: %v", err)
+	}
+	dependencies.SetLogger(logging.Default)
+
+	return ReconcileHandler(dependencies, dependencies.Config.PublishTimeout)
+}
+
+// ReconcileHandler finishes publishes that have been InProgress for longer than publishTimeout, using Discover's
+// status of the collection to decide whether they completed or failed.
+func ReconcileHandler(dependencies container.DependencyContainer, publishTimeout time.Duration) LambdaHandler {
+	return func(ctx context.Context, event events.EventBridgeEvent) error {
+		logger := dependencies.Logger().With(slog.String("eventId", event.ID))
+		startedBefore := time.Now().UTC().Add(-publishTimeout)
+		stalePublishes, err := dependencies.CollectionsStore().GetStalePublishes(ctx, startedBefore)
+		if err != nil {
+			logger.Error("error getting stale publishes", slog.Any("error", err))
+			return fmt.Errorf("error getting publishes started before %s: %w", startedBefore, err)
+		}
+		if len(stalePublishes) == 0 {
+			logger.Info("no stale publishes", slog.Time("startedBefore", startedBefore))
+			return nil
+		}
+
+		internalDiscover, err := dependencies.InternalDiscover(ctx)
+		if err != nil {
+			logger.Error("error getting internal Discover dependency", slog.Any("error", err))
+			return fmt.Errorf("error getting internal Discover dependency: %w", err)
+		}
+		r := reconciler{
+			dependencies:     dependencies,
+			internalDiscover: internalDiscover,
+			publishTimeout:   publishTimeout,
+		}
+
+		// A failure is left stale, so it will be retried on the next run
+		var errs []error
+		for _, stale := range stalePublishes {
+			staleLogger := logger.With(
+				slog.Int64("collectionId", stale.CollectionID),
+				slog.String("collectionNodeId", stale.CollectionNodeID),
+				slog.Any("type", stale.Type),
+				slog.Time("startedAt", stale.StartedAt))
+			if err := r.reconcile(ctx, staleLogger, stale); err != nil {
+				staleLogger.Error("error reconciling stale publish", slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("error reconciling stale publish of collection %d: %w", stale.CollectionID, err))
+			}
+		}
+		logger.Info("reconciled stale publishes",
+			slog.Int("count", len(stalePublishes)),
+			slog.Int("errorCount", len(errs)),
+			slog.Time("startedBefore", startedBefore))
+		return errors.Join(errs...)
+	}
+}
+
+// ReconciledStatus returns the status that the stale publish should finish with, given the status of the
+// collection on Discover. A publication or revision only completed if Discover published the collection after
+// it started, since Discover keeps reporting an earlier version as succeeded if the publish died before reaching it.
+func ReconciledStatus(stale collections.StalePublish, discoverStatus service.DatasetPublishStatusResponse) publishing.Status {
+	if stale.Type == publishing.RemovalType {
+		if discoverStatus.Status == dto.Unpublished {
+			return publishing.CompletedStatus
+		}
+		return publishing.FailedStatus
+	}
+	// Discover may only keep the publish date to the second
+	startedAt := stale.StartedAt.Truncate(time.Second)
+	if discoverStatus.Status == dto.PublishSucceeded &&
+		discoverStatus.LastPublishedDate != nil &&
+		!discoverStatus.LastPublishedDate.Before(startedAt) {
+		return publishing.CompletedStatus
+	}
+	return publishing.FailedStatus
+}
+
+type reconciler struct {
+	dependencies     container.DependencyContainer
+	internalDiscover service.InternalDiscover
+	publishTimeout   time.Duration
+}
+
+func (r reconciler) reconcile(ctx context.Context, logger *slog.Logger, stale collections.StalePublish) error {
+	discoverStatus, err := r.internalDiscover.GetCollectionPublishStatus(ctx, stale.CollectionID, stale.CollectionNodeID, discoverRole)
+	if err != nil {
+		return fmt.Errorf("error getting publish status from Discover: %w", err)
+	}
+	status := ReconciledStatus(stale, discoverStatus)
+	logger = logger.With(
+		slog.Any("discoverServiceStatus", discoverStatus.Status),
+		slog.Any("discoverLastPublishedDate", discoverStatus.LastPublishedDate),
+		slog.Any("collectionsServiceStatus", status))
+	if discoverStatus.Status == dto.PublishInProgress {
+		// Discover will not hear about this publish again, so someone will have to fail it there by hand
+		logger.Warn("Discover reports stale publish still in progress")
+	}
+
+	// Clean up before finishing, since a finished publish is no longer stale and would not be retried
+	if status == publishing.FailedStatus && stale.Type != publishing.RemovalType && discoverStatus.PublishedDatasetID != 0 {
+		if err := r.deleteOrphanedManifests(ctx, logger, stale, publishing.ManifestS3Key(discoverStatus.PublishedDatasetID)); err != nil {
+			return err
+		}
+	}
+
+	finished, err := r.dependencies.CollectionsStore().FinishStalePublish(ctx, stale, status)
+	if err != nil {
+		return err
+	}
+	if !finished {
+		logger.Info("stale publish was finished or replaced before it could be reconciled")
+		return nil
+	}
+	logger.Info("finished stale publish")
+	return nil
+}
+
+// deleteOrphanedManifests deletes the versions of the manifest written by a failed publish. The process running the publish
+// was stopped by the Lambda timeout at the latest, so these are the versions written less than publishTimeout after it started.
+// Any later versions belong to a newer publish, since a new one can only replace a publish after it has become stale.
+func (r reconciler) deleteOrphanedManifests(ctx context.Context, logger *slog.Logger, stale collections.StalePublish, key string) error {
+	manifestStore := r.dependencies.ManifestStore()
+	versions, err := manifestStore.GetManifestVersions(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error getting manifest versions: %w", err)
+	}
+	// S3 only keeps LastModified to the second
+	startedAt := stale.StartedAt.Truncate(time.Second)
+	staleAfter := stale.StartedAt.Add(r.publishTimeout)
+	for _, version := range versions {
+		if version.LastModified.Before(startedAt) || !version.LastModified.Before(staleAfter) {
+			continue
+		}
+		if err := manifestStore.DeleteManifestVersion(ctx, key, version.S3VersionID); err != nil {
+			return err
+		}
+		logger.Info("deleted orphaned manifest version",
+			slog.String("key", key),
+			slog.String("s3VersionId", version.S3VersionID),
+			slog.Time("lastModified", version.LastModified))
+	}
+	return nil
+}
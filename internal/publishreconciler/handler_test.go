@@ -0,0 +1,370 @@
+package publishreconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/api/store/manifests"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+const publishTimeout = 30 * time.Minute
+
+func TestReconciledStatus(t *testing.T) {
+	startedAt := time.Now().UTC().Add(-2 * publishTimeout)
+	publishedAfter := startedAt.Add(time.Minute)
+	publishedBefore := startedAt.Add(-24 * time.Hour)
+	tests := []struct {
+		pubType           publishing.Type
+		discoverStatus    dto.PublishStatus
+		lastPublishedDate *time.Time
+		expected          publishing.Status
+	}{
+		{publishing.PublicationType, dto.PublishSucceeded, &publishedAfter, publishing.CompletedStatus},
+		{publishing.PublicationType, dto.PublishSucceeded, &publishedBefore, publishing.FailedStatus},
+		{publishing.PublicationType, dto.PublishSucceeded, nil, publishing.FailedStatus},
+		{publishing.PublicationType, dto.PublishFailed, &publishedAfter, publishing.FailedStatus},
+		{publishing.PublicationType, dto.PublishInProgress, nil, publishing.FailedStatus},
+		{publishing.PublicationType, dto.NotPublished, nil, publishing.FailedStatus},
+		{publishing.RevisionType, dto.PublishSucceeded, &publishedAfter, publishing.CompletedStatus},
+		{publishing.RevisionType, dto.PublishSucceeded, &publishedBefore, publishing.FailedStatus},
+		{publishing.RevisionType, dto.PublishFailed, &publishedAfter, publishing.FailedStatus},
+		{publishing.RemovalType, dto.Unpublished, &publishedBefore, publishing.CompletedStatus},
+		{publishing.RemovalType, dto.PublishSucceeded, &publishedBefore, publishing.FailedStatus},
+	}
+	for _, tt := range tests {
+		published := "never"
+		switch tt.lastPublishedDate {
+		case &publishedAfter:
+			published = "after start"
+		case &publishedBefore:
+			published = "before start"
+		}
+		name := fmt.Sprintf("%s %s published %s", tt.pubType, tt.discoverStatus, published)
+		t.Run(name, func(t *testing.T) {
+			stale := collections.StalePublish{Type: tt.pubType, StartedAt: startedAt}
+			discoverStatus := service.DatasetPublishStatusResponse{Status: tt.discoverStatus, LastPublishedDate: tt.lastPublishedDate}
+			assert.Equal(t, tt.expected, ReconciledStatus(stale, discoverStatus))
+		})
+	}
+}
+
+func TestReconcileHandler(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"no stale publishes should not call Discover", testNoStalePublishes},
+		{"stale publish that succeeded on Discover should be completed", testStalePublishSucceeded},
+		{"stale publish that failed on Discover should be failed and its manifest versions deleted", testStalePublishFailed},
+		{"stale re-publish that never reached Discover should be failed and its manifest versions deleted", testStaleRepublish},
+		{"stale revision that never reached Discover should be failed and its manifest versions deleted", testStaleRevision},
+		{"stale removal should be completed only if Discover reports unpublished", testStaleRemoval},
+		{"stale publish finished since listing should be left alone", testStalePublishAlreadyFinished},
+		{"errors should not stop other stale publishes from being reconciled", testReconcileErrors},
+		{"error getting stale publishes should be returned", testGetStalePublishesError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testNoStalePublishes(t *testing.T) {
+	ctx := context.Background()
+
+	var actualStartedBefore time.Time
+	store := mocks.NewCollectionsStore().WithGetStalePublishesFunc(func(_ context.Context, startedBefore time.Time) ([]collections.StalePublish, error) {
+		actualStartedBefore = startedBefore
+		return nil, nil
+	})
+
+	expectedStartedBefore := time.Now().UTC().Add(-publishTimeout)
+	// no InternalDiscover or ManifestStore set, so the test container will panic if they are used
+	dependencies := apitest.NewTestContainer().WithCollectionsStore(store)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+
+	assert.WithinDuration(t, expectedStartedBefore, actualStartedBefore, time.Minute)
+}
+
+func testStalePublishSucceeded(t *testing.T) {
+	ctx := context.Background()
+
+	stale := newStalePublish(publishing.PublicationType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(stale))
+	finishedStatuses := recordFinishStalePublish(t, store)
+
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(getCollectionPublishStatusFunc(t, dto.PublishSucceeded, stale))
+
+	// no ManifestStore set, since there should be nothing to clean up
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+
+	assert.Equal(t, map[int64]publishing.Status{stale.CollectionID: publishing.CompletedStatus}, finishedStatuses)
+}
+
+func testStalePublishFailed(t *testing.T) {
+	ctx := context.Background()
+
+	stale := newStalePublish(publishing.PublicationType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(stale))
+	finishedStatuses := recordFinishStalePublish(t, store)
+
+	discoverResponse := service.DatasetPublishStatusResponse{
+		PublishedDatasetID: rand.Intn(5000) + 1,
+		Status:             dto.PublishInProgress,
+	}
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(func(_ context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (service.DatasetPublishStatusResponse, error) {
+			assert.Equal(t, stale.CollectionID, collectionID)
+			assert.Equal(t, stale.CollectionNodeID, collectionNodeID)
+			assert.Equal(t, role.Owner, userRole)
+			return discoverResponse, nil
+		})
+
+	expectedKey := publishing.ManifestS3Key(discoverResponse.PublishedDatasetID)
+	// the published manifest and one from a later publish should be kept
+	publishedVersion := manifests.ManifestVersion{S3VersionID: uuid.NewString(), LastModified: stale.StartedAt.Add(-time.Hour)}
+	orphanedVersion := manifests.ManifestVersion{S3VersionID: uuid.NewString(), LastModified: stale.StartedAt.Add(time.Minute)}
+	laterVersion := manifests.ManifestVersion{S3VersionID: uuid.NewString(), LastModified: stale.StartedAt.Add(publishTimeout + time.Minute), IsLatest: true}
+	var deletedVersionIDs []string
+	manifestStore := mocks.NewManifestStore().
+		WithGetManifestVersionsFunc(func(_ context.Context, key string) ([]manifests.ManifestVersion, error) {
+			assert.Equal(t, expectedKey, key)
+			return []manifests.ManifestVersion{laterVersion, orphanedVersion, publishedVersion}, nil
+		}).
+		WithDeleteManifestVersionFunc(func(_ context.Context, key string, s3VersionID string) error {
+			assert.Equal(t, expectedKey, key)
+			deletedVersionIDs = append(deletedVersionIDs, s3VersionID)
+			return nil
+		})
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover).
+		WithManifestStore(manifestStore)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+
+	assert.Equal(t, []string{orphanedVersion.S3VersionID}, deletedVersionIDs)
+	assert.Equal(t, map[int64]publishing.Status{stale.CollectionID: publishing.FailedStatus}, finishedStatuses)
+}
+
+func testStaleRepublish(t *testing.T) {
+	testStaleNotReachingDiscover(t, publishing.PublicationType)
+}
+
+func testStaleRevision(t *testing.T) {
+	testStaleNotReachingDiscover(t, publishing.RevisionType)
+}
+
+// testStaleNotReachingDiscover checks a stale publish of an already published collection that died before
+// reaching Discover, so Discover still reports the earlier version as succeeded.
+func testStaleNotReachingDiscover(t *testing.T, pubType publishing.Type) {
+	ctx := context.Background()
+
+	stale := newStalePublish(pubType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(stale))
+	finishedStatuses := recordFinishStalePublish(t, store)
+
+	lastPublishedDate := stale.StartedAt.Add(-24 * time.Hour)
+	discoverResponse := service.DatasetPublishStatusResponse{
+		PublishedDatasetID: rand.Intn(5000) + 1,
+		Status:             dto.PublishSucceeded,
+		LastPublishedDate:  &lastPublishedDate,
+	}
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(func(_ context.Context, collectionID int64, _ string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+			assert.Equal(t, stale.CollectionID, collectionID)
+			return discoverResponse, nil
+		})
+
+	expectedKey := publishing.ManifestS3Key(discoverResponse.PublishedDatasetID)
+	publishedVersion := manifests.ManifestVersion{S3VersionID: uuid.NewString(), LastModified: lastPublishedDate}
+	orphanedVersion := manifests.ManifestVersion{S3VersionID: uuid.NewString(), LastModified: stale.StartedAt.Add(time.Minute), IsLatest: true}
+	var deletedVersionIDs []string
+	manifestStore := mocks.NewManifestStore().
+		WithGetManifestVersionsFunc(func(_ context.Context, key string) ([]manifests.ManifestVersion, error) {
+			assert.Equal(t, expectedKey, key)
+			return []manifests.ManifestVersion{orphanedVersion, publishedVersion}, nil
+		}).
+		WithDeleteManifestVersionFunc(func(_ context.Context, key string, s3VersionID string) error {
+			assert.Equal(t, expectedKey, key)
+			deletedVersionIDs = append(deletedVersionIDs, s3VersionID)
+			return nil
+		})
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover).
+		WithManifestStore(manifestStore)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+
+	assert.Equal(t, []string{orphanedVersion.S3VersionID}, deletedVersionIDs)
+	assert.Equal(t, map[int64]publishing.Status{stale.CollectionID: publishing.FailedStatus}, finishedStatuses)
+}
+
+func testStaleRemoval(t *testing.T) {
+	ctx := context.Background()
+
+	unpublished := newStalePublish(publishing.RemovalType)
+	stillPublished := newStalePublish(publishing.RemovalType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(unpublished, stillPublished))
+	finishedStatuses := recordFinishStalePublish(t, store)
+
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(func(_ context.Context, collectionID int64, _ string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+			status := dto.PublishSucceeded
+			if collectionID == unpublished.CollectionID {
+				status = dto.Unpublished
+			}
+			return service.DatasetPublishStatusResponse{PublishedDatasetID: rand.Intn(5000) + 1, Status: status}, nil
+		})
+
+	// no ManifestStore set, since Discover takes care of removing the manifest
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+
+	assert.Equal(t, map[int64]publishing.Status{
+		unpublished.CollectionID:    publishing.CompletedStatus,
+		stillPublished.CollectionID: publishing.FailedStatus,
+	}, finishedStatuses)
+}
+
+func testStalePublishAlreadyFinished(t *testing.T) {
+	ctx := context.Background()
+
+	stale := newStalePublish(publishing.RevisionType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(stale)).
+		WithFinishStalePublishFunc(func(_ context.Context, _ collections.StalePublish, _ publishing.Status) (bool, error) {
+			return false, nil
+		})
+
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(getCollectionPublishStatusFunc(t, dto.PublishSucceeded, stale))
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover)
+	require.NoError(t, ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent()))
+}
+
+func testReconcileErrors(t *testing.T) {
+	ctx := context.Background()
+
+	discoverErrorStale := newStalePublish(publishing.PublicationType)
+	manifestErrorStale := newStalePublish(publishing.PublicationType)
+	okStale := newStalePublish(publishing.PublicationType)
+	store := mocks.NewCollectionsStore().
+		WithGetStalePublishesFunc(getStalePublishesFunc(discoverErrorStale, manifestErrorStale, okStale))
+	finishedStatuses := recordFinishStalePublish(t, store)
+
+	discoverErr := errors.New(uuid.NewString())
+	internalDiscover := mocks.NewInternalDiscover().
+		WithGetCollectionPublishStatusFunc(func(_ context.Context, collectionID int64, _ string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+			switch collectionID {
+			case discoverErrorStale.CollectionID:
+				return service.DatasetPublishStatusResponse{}, discoverErr
+			case manifestErrorStale.CollectionID:
+				return service.DatasetPublishStatusResponse{PublishedDatasetID: rand.Intn(5000) + 1, Status: dto.PublishFailed}, nil
+			default:
+				lastPublishedDate := okStale.StartedAt.Add(time.Minute)
+				return service.DatasetPublishStatusResponse{Status: dto.PublishSucceeded, LastPublishedDate: &lastPublishedDate}, nil
+			}
+		})
+
+	manifestErr := errors.New(uuid.NewString())
+	manifestStore := mocks.NewManifestStore().
+		WithGetManifestVersionsFunc(func(_ context.Context, _ string) ([]manifests.ManifestVersion, error) {
+			return nil, manifestErr
+		})
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithInternalDiscover(internalDiscover).
+		WithManifestStore(manifestStore)
+	err := ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent())
+	assert.ErrorIs(t, err, discoverErr)
+	assert.ErrorIs(t, err, manifestErr)
+
+	// publishes with errors are left stale to be retried
+	assert.Equal(t, map[int64]publishing.Status{okStale.CollectionID: publishing.CompletedStatus}, finishedStatuses)
+}
+
+func testGetStalePublishesError(t *testing.T) {
+	ctx := context.Background()
+
+	storeErr := errors.New(uuid.NewString())
+	store := mocks.NewCollectionsStore().WithGetStalePublishesFunc(func(_ context.Context, _ time.Time) ([]collections.StalePublish, error) {
+		return nil, storeErr
+	})
+
+	dependencies := apitest.NewTestContainer().WithCollectionsStore(store)
+	err := ReconcileHandler(dependencies, publishTimeout)(ctx, newEvent())
+	assert.ErrorIs(t, err, storeErr)
+}
+
+func newEvent() events.EventBridgeEvent {
+	return events.EventBridgeEvent{ID: uuid.NewString()}
+}
+
+func newStalePublish(pubType publishing.Type) collections.StalePublish {
+	userID := rand.Int63n(5000) + 1
+	return collections.StalePublish{
+		CollectionID:     rand.Int63n(5000) + 1,
+		CollectionNodeID: uuid.NewString(),
+		Type:             pubType,
+		UserID:           &userID,
+		StartedAt:        time.Now().UTC().Add(-2 * publishTimeout),
+	}
+}
+
+func getStalePublishesFunc(stalePublishes ...collections.StalePublish) mocks.GetStalePublishesFunc {
+	return func(_ context.Context, _ time.Time) ([]collections.StalePublish, error) {
+		return stalePublishes, nil
+	}
+}
+
+func getCollectionPublishStatusFunc(t *testing.T, status dto.PublishStatus, stale collections.StalePublish) mocks.GetCollectionPublishStatusFunc {
+	return func(_ context.Context, collectionID int64, collectionNodeID string, _ role.Role) (service.DatasetPublishStatusResponse, error) {
+		assert.Equal(t, stale.CollectionID, collectionID)
+		assert.Equal(t, stale.CollectionNodeID, collectionNodeID)
+		lastPublishedDate := stale.StartedAt.Add(time.Minute)
+		return service.DatasetPublishStatusResponse{PublishedDatasetID: rand.Intn(5000) + 1, Status: status, LastPublishedDate: &lastPublishedDate}, nil
+	}
+}
+
+// recordFinishStalePublish sets store's FinishStalePublishFunc and returns the map it records the finished statuses in.
+func recordFinishStalePublish(t *testing.T, store *mocks.CollectionsStore) map[int64]publishing.Status {
+	finishedStatuses := map[int64]publishing.Status{}
+	store.WithFinishStalePublishFunc(func(_ context.Context, stale collections.StalePublish, status publishing.Status) (bool, error) {
+		_, alreadyFinished := finishedStatuses[stale.CollectionID]
+		assert.False(t, alreadyFinished, "stale publish of collection %d finished twice", stale.CollectionID)
+		finishedStatuses[stale.CollectionID] = status
+		return true, nil
+	})
+	return finishedStatuses
+}
@@ -0,0 +1,117 @@
+package publishworker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/config"
+	"github.com/pennsieve/collections-service/internal/api/container"
+	"github.com/pennsieve/collections-service/internal/api/routes"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"log"
+	"log/slog"
+	"time"
+)
+
+// LambdaHandler is triggered by a scheduled EventBridge rule.
+type LambdaHandler func(ctx context.Context, event events.EventBridgeEvent) error
+
+const (
+	// VisibilityTimeout is how long a received publish job is hidden from other runs of the worker. It must be longer
+	// than the worker Lambda timeout, so that a job is only received again once the run that received it is over.
+	VisibilityTimeout = 6 * time.Minute
+	// MinRemainingTime is the time a run must have left before it starts another job. It is the longest a
+	// publish job attempt is expected to take.
+	MinRemainingTime = 2 * time.Minute
+	// MaxJobsPerRun bounds the number of jobs one run of the worker attempts.
+	MaxJobsPerRun = 10
+)
+
+func Handler() LambdaHandler {
+	// The worker runs the publish steps of the API, so it needs the same dependencies and config
+	dependencies, err := container.NewContainer()
+	if err != nil {
+		log.Fatalf("Failed to create publish worker container: %v", err)
+	}
+	dependencies.SetLogger(logging.Default)
+	// The worker is not behind API Gateway, so it can wait longer for Discover to start a publish
+	dependencies.Config.HTTPClients.InternalDiscover = dependencies.Config.HTTPClients.PublishWorkerInternalDiscover
+
+	return PublishJobHandler(dependencies, dependencies.Config)
+}
+
+// PublishJobHandler runs queued publish jobs one at a time until the queue is empty, MaxJobsPerRun jobs have been
+// attempted, or less than MinRemainingTime is left before ctx's deadline.
+func PublishJobHandler(dependencies container.DependencyContainer, config config.Config) LambdaHandler {
+	return func(ctx context.Context, event events.EventBridgeEvent) error {
+		logger := logging.Default.With(slog.String("eventId", event.ID))
+		publishQueue := dependencies.PublishQueue()
+
+		// A job whose outcome cannot be recorded is left in the queue, so it will be received again
+		// once its visibility timeout passes, until it has been received config.PublishJobMaxAttempts times
+		var errs []error
+		jobCount := 0
+		for ; jobCount < MaxJobsPerRun; jobCount++ {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < MinRemainingTime {
+				logger.Info("not enough time left to run another publish job", slog.Time("deadline", deadline))
+				break
+			}
+			messages, err := publishQueue.Receive(ctx, 1, VisibilityTimeout)
+			if err != nil {
+				logger.Error("error receiving publish jobs", slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("error receiving publish jobs: %w", err))
+				break
+			}
+			if len(messages) == 0 {
+				break
+			}
+			message := messages[0]
+			jobLogger := logger.With(slog.String("messageId", message.ID), slog.Int("receiveCount", message.ReceiveCount))
+			dependencies.SetLogger(jobLogger)
+
+			if _, err := uuid.Parse(message.Body); err != nil {
+				// Running it would fail the same way on every receive, so drop it
+				jobLogger.Error("deleting publish job message with invalid job id", slog.String("body", message.Body), slog.Any("error", err))
+				deleteMessage(ctx, publishQueue, message, jobLogger)
+				continue
+			}
+
+			params := routes.Params{Container: dependencies, Config: config}
+			finished, err := routes.RunPublishJob(ctx, params, message.Body)
+			if err != nil {
+				jobLogger.Error("error running publish job", slog.String("jobId", message.Body), slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("error running publish job %s: %w", message.Body, err))
+				if message.ReceiveCount >= config.PublishJobMaxAttempts {
+					// The publish is left InProgress, and is treated as stale once its publish timeout passes
+					jobLogger.Error("deleting publish job message after max receives",
+						slog.String("jobId", message.Body),
+						slog.Int("maxAttempts", config.PublishJobMaxAttempts))
+					deleteMessage(ctx, publishQueue, message, jobLogger)
+				}
+				continue
+			}
+			if !finished {
+				// Leave the message to be received again once its visibility timeout passes. The timeout
+				// doubles as the delay between attempts.
+				continue
+			}
+			// The job is finished, so receiving it again only logs a warning
+			deleteMessage(ctx, publishQueue, message, jobLogger)
+		}
+		logger.Info("ran publish jobs",
+			slog.Int("count", jobCount),
+			slog.Int("errorCount", len(errs)))
+		return errors.Join(errs...)
+	}
+}
+
+// deleteMessage deletes message from publishQueue. A failed delete is only logged, since the message will be
+// received and deleted again by a later run.
+func deleteMessage(ctx context.Context, publishQueue queue.Queue, message queue.Message, logger *slog.Logger) {
+	if err := publishQueue.Delete(ctx, message.ReceiptHandle); err != nil {
+		logger.Warn("error deleting publish job message from queue", slog.String("body", message.Body), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,190 @@
+package publishworker
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
+	"github.com/pennsieve/collections-service/internal/test/apitest"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPublishJobHandler(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"empty queue should not touch the store", testEmptyQueue},
+		{"finished jobs should be deleted from the queue", testFinishedJobsDeleted},
+		{"jobs whose attempt cannot be started should stay in the queue", testStartErrorKeepsJob},
+		{"jobs that still fail after max receives should be deleted", testStartErrorMaxReceives},
+		{"messages with an invalid job id should be deleted", testInvalidJobIDDeleted},
+		{"no jobs should be run without enough time left", testNotEnoughTime},
+		{"at most MaxJobsPerRun jobs should be run", testMaxJobsPerRun},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func newEvent() events.EventBridgeEvent {
+	return events.EventBridgeEvent{ID: uuid.NewString()}
+}
+
+func sendJobs(ctx context.Context, t *testing.T, publishQueue queue.Queue, count int) []string {
+	var jobIDs []string
+	for range count {
+		jobID := uuid.NewString()
+		_, err := publishQueue.Send(ctx, jobID)
+		require.NoError(t, err)
+		jobIDs = append(jobIDs, jobID)
+	}
+	return jobIDs
+}
+
+// recordStartPublishJobAttempt returns a pointer to the job ids that the worker tried to start, and has the store
+// return startErr for each of them.
+func recordStartPublishJobAttempt(store *mocks.CollectionsStore, startErr error) *[]string {
+	var startedJobIDs []string
+	store.WithStartPublishJobAttemptFunc(func(_ context.Context, jobID string) (collections.PublishJob, error) {
+		startedJobIDs = append(startedJobIDs, jobID)
+		return collections.PublishJob{}, startErr
+	})
+	return &startedJobIDs
+}
+
+func testEmptyQueue(t *testing.T) {
+	ctx := context.Background()
+
+	// no CollectionsStore set, so the test container will panic if it is used
+	dependencies := apitest.NewTestContainer().WithPublishQueue(queue.NewMemoryQueue())
+	require.NoError(t, PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent()))
+}
+
+func testFinishedJobsDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	publishQueue := queue.NewMemoryQueue()
+	jobIDs := sendJobs(ctx, t, publishQueue, 2)
+
+	store := mocks.NewCollectionsStore()
+	startedJobIDs := recordStartPublishJobAttempt(store, collections.ErrPublishJobFinished)
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithPublishQueue(publishQueue)
+	require.NoError(t, PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent()))
+
+	assert.Equal(t, jobIDs, *startedJobIDs)
+	assert.Zero(t, publishQueue.Len())
+}
+
+func testStartErrorKeepsJob(t *testing.T) {
+	ctx := context.Background()
+
+	publishQueue := queue.NewMemoryQueue()
+	jobIDs := sendJobs(ctx, t, publishQueue, 2)
+
+	store := mocks.NewCollectionsStore()
+	startedJobIDs := recordStartPublishJobAttempt(store, errors.New("database is down"))
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithPublishQueue(publishQueue)
+	err := PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database is down")
+	assert.Contains(t, err.Error(), jobIDs[0])
+	assert.Contains(t, err.Error(), jobIDs[1])
+
+	// an error should not stop other jobs from running
+	assert.Equal(t, jobIDs, *startedJobIDs)
+	assert.Equal(t, 2, publishQueue.Len())
+}
+
+func testStartErrorMaxReceives(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	publishQueue := queue.NewMemoryQueue().WithClock(func() time.Time { return now })
+	jobIDs := sendJobs(ctx, t, publishQueue, 1)
+
+	store := mocks.NewCollectionsStore()
+	startedJobIDs := recordStartPublishJobAttempt(store, errors.New("database is down"))
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithPublishQueue(publishQueue)
+	handler := PublishJobHandler(dependencies, apitest.NewConfigBuilder().WithPublishJobMaxAttempts(2).Build())
+
+	require.Error(t, handler(ctx, newEvent()))
+	assert.Equal(t, 1, publishQueue.Len())
+
+	now = now.Add(VisibilityTimeout)
+	require.Error(t, handler(ctx, newEvent()))
+	assert.Equal(t, []string{jobIDs[0], jobIDs[0]}, *startedJobIDs)
+	assert.Zero(t, publishQueue.Len())
+}
+
+func testInvalidJobIDDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	publishQueue := queue.NewMemoryQueue()
+	_, err := publishQueue.Send(ctx, "not-a-job-id")
+	require.NoError(t, err)
+	jobIDs := sendJobs(ctx, t, publishQueue, 1)
+
+	store := mocks.NewCollectionsStore()
+	startedJobIDs := recordStartPublishJobAttempt(store, collections.ErrPublishJobFinished)
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithPublishQueue(publishQueue)
+	require.NoError(t, PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent()))
+
+	assert.Equal(t, jobIDs, *startedJobIDs)
+	assert.Zero(t, publishQueue.Len())
+}
+
+func testNotEnoughTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), MinRemainingTime-time.Second)
+	defer cancel()
+
+	publishQueue := queue.NewMemoryQueue()
+	sendJobs(ctx, t, publishQueue, 1)
+
+	// no CollectionsStore set, so the test container will panic if it is used
+	dependencies := apitest.NewTestContainer().WithPublishQueue(publishQueue)
+	require.NoError(t, PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent()))
+
+	messages, err := publishQueue.Receive(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, 1, messages[0].ReceiveCount)
+}
+
+func testMaxJobsPerRun(t *testing.T) {
+	ctx := context.Background()
+
+	publishQueue := queue.NewMemoryQueue()
+	jobIDs := sendJobs(ctx, t, publishQueue, MaxJobsPerRun+2)
+
+	store := mocks.NewCollectionsStore()
+	startedJobIDs := recordStartPublishJobAttempt(store, collections.ErrPublishJobFinished)
+
+	dependencies := apitest.NewTestContainer().
+		WithCollectionsStore(store).
+		WithPublishQueue(publishQueue)
+	require.NoError(t, PublishJobHandler(dependencies, apitest.NewConfigBuilder().Build())(ctx, newEvent()))
+
+	assert.Equal(t, jobIDs[:MaxJobsPerRun], *startedJobIDs)
+	assert.Equal(t, 2, publishQueue.Len())
+}
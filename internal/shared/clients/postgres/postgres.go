@@ -3,47 +3,159 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DB interface {
-	Connect(ctx context.Context, databaseName string) (*pgx.Conn, error)
+	// Connect returns a connection to the given database. Callers must Close the returned Conn when they are done with it.
+	//
+	// Stores should call the package-level Connect instead of calling this method directly so that they
+	// pick up any connection shared by an enclosing WithConnection or WithTransaction.
+	Connect(ctx context.Context, databaseName string) (Conn, error)
 }
 
-type RDSProxy struct {
-	config aws.Config
-	host   string
-	port   int
-	user   string
+// Querier is implemented by *pgx.Conn, *pgxpool.Conn, and pgx.Tx
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
-func NewRDSProxy(config aws.Config, host string, port int, user string) *RDSProxy {
-	return &RDSProxy{
-		config,
-		host,
-		port,
-		user,
+// Conn is a connection returned by DB.Connect. Depending on where it came from, Close
+// may close the underlying connection, return it to a pool, or do nothing at all.
+type Conn interface {
+	Querier
+	Close(ctx context.Context) error
+}
+
+// Pool is a DB that keeps a pgxpool.Pool per database. Pools are created on first use and
+// live as long as the Pool, so a Pool held across warm Lambda invocations reuses its connections.
+type Pool struct {
+	connString    string
+	beforeConnect func(context.Context, *pgx.ConnConfig) error
+	limits        PoolLimits
+
+	mu    sync.Mutex
+	pools map[string]*pgxpool.Pool
+}
+
+// NewPool returns a Pool that connects using connString. connString should not include a dbname,
+// since that is supplied to Connect.
+func NewPool(connString string) *Pool {
+	return &Pool{
+		connString: connString,
+		pools:      map[string]*pgxpool.Pool{},
+	}
+}
+
+// NewRDSProxyPool returns a Pool that authenticates to RDS Proxy with an IAM authentication token.
+// A fresh token is built each time the pool opens a new connection, so the fifteen-minute token
+// lifetime is never an issue for long-lived pools.
+func NewRDSProxyPool(config aws.Config, host string, port int, user string) *Pool {
+	endpoint := fmt.Sprintf("%s:%d", host, port)
+	return NewPool(fmt.Sprintf("host=%s port=%d user=%s", host, port, user)).
+		WithBeforeConnect(func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			authenticationToken, err := auth.BuildAuthToken(
+				ctx,
+				endpoint,
+				config.Region,
+				user,
+				config.Credentials,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create authentication token: %w", err)
+			}
+			connConfig.Password = authenticationToken
+			return nil
+		})
+}
+
+// PoolLimits limit the connections of each per-database pool. Zero fields keep the pgxpool defaults.
+type PoolLimits struct {
+	MaxConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+}
+
+// WithLimits sets the limits of the per-database pools. Only pools created after the call are affected.
+func (p *Pool) WithLimits(limits PoolLimits) *Pool {
+	p.limits = limits
+	return p
+}
+
+// WithBeforeConnect sets a hook that is called with the config of each new connection before
+// the connection is opened.
+func (p *Pool) WithBeforeConnect(beforeConnect func(context.Context, *pgx.ConnConfig) error) *Pool {
+	p.beforeConnect = beforeConnect
+	return p
+}
+
+// Connect acquires a connection to databaseName from the pool. Closing the returned Conn releases it back to the pool.
+func (p *Pool) Connect(ctx context.Context, databaseName string) (Conn, error) {
+	pool, err := p.pool(ctx, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection to database %s: %w", databaseName, err)
 	}
+	return pooledConn{conn}, nil
 }
 
-func (db *RDSProxy) Connect(ctx context.Context, databaseName string) (*pgx.Conn, error) {
-	authenticationToken, err := auth.BuildAuthToken(
-		ctx,
-		fmt.Sprintf("%s:%d", db.host, db.port),
-		db.config.Region,
-		db.user,
-		db.config.Credentials,
-	)
+// Close closes all the pools. Connect should not be called after Close.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for databaseName, pool := range p.pools {
+		pool.Close()
+		delete(p.pools, databaseName)
+	}
+}
 
+func (p *Pool) pool(ctx context.Context, databaseName string) (*pgxpool.Pool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pool, found := p.pools[databaseName]; found {
+		return pool, nil
+	}
+	poolConfig, err := pgxpool.ParseConfig(fmt.Sprintf("%s dbname=%s", p.connString, databaseName))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create authentication token: %w", err)
+		return nil, fmt.Errorf("error parsing pool config for database %s: %w", databaseName, err)
+	}
+	poolConfig.BeforeConnect = p.beforeConnect
+	if p.limits.MaxConns > 0 {
+		poolConfig.MaxConns = p.limits.MaxConns
 	}
+	if p.limits.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = p.limits.MaxConnLifetime
+	}
+	if p.limits.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = p.limits.MaxConnIdleTime
+	}
+	// NewWithConfig does not open any connections, so this does not block on the database
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating pool for database %s: %w", databaseName, err)
+	}
+	p.pools[databaseName] = pool
+	return pool, nil
+}
+
+// pooledConn releases the connection back to its pool instead of closing it.
+type pooledConn struct {
+	*pgxpool.Conn
+}
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-		db.host, db.port, db.user, authenticationToken, databaseName,
-	)
-	return pgx.Connect(ctx, dsn)
+func (c pooledConn) Close(_ context.Context) error {
+	c.Release()
+	return nil
 }
@@ -0,0 +1,101 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+	config := test.PostgresDBConfig(t)
+	databaseName := config.CollectionsDatabase
+
+	backendPID := func(t *testing.T, conn postgres.Conn) int {
+		var pid int
+		require.NoError(t, conn.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&pid))
+		return pid
+	}
+
+	t.Run("closed connections should be reused", func(t *testing.T) {
+		pool := postgres.NewPool(test.NewPostgresDBFromConfig(t, config).ConnString())
+		t.Cleanup(pool.Close)
+
+		conn, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		firstPID := backendPID(t, conn)
+		test.CloseConnection(ctx, t, conn)
+
+		conn, err = pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		defer test.CloseConnection(ctx, t, conn)
+		assert.Equal(t, firstPID, backendPID(t, conn))
+	})
+
+	t.Run("open connections should not be shared", func(t *testing.T) {
+		pool := postgres.NewPool(test.NewPostgresDBFromConfig(t, config).ConnString())
+		t.Cleanup(pool.Close)
+
+		first, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		defer test.CloseConnection(ctx, t, first)
+
+		second, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		defer test.CloseConnection(ctx, t, second)
+
+		assert.NotEqual(t, backendPID(t, first), backendPID(t, second))
+	})
+
+	t.Run("MaxConns should limit open connections", func(t *testing.T) {
+		pool := postgres.NewPool(test.NewPostgresDBFromConfig(t, config).ConnString()).
+			WithLimits(postgres.PoolLimits{MaxConns: 1})
+		t.Cleanup(pool.Close)
+
+		conn, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = pool.Connect(timeoutCtx, databaseName)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		test.CloseConnection(ctx, t, conn)
+		conn, err = pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		test.CloseConnection(ctx, t, conn)
+	})
+
+	t.Run("BeforeConnect should be called for each new connection", func(t *testing.T) {
+		require.NotNil(t, config.Password)
+		password := *config.Password
+		// leave the password out of the conn string so that only the hook supplies it
+		var calls int
+		pool := postgres.NewPool(fmt.Sprintf("host=%s port=%d user=%s", config.Host, config.Port, config.User)).
+			WithBeforeConnect(func(_ context.Context, connConfig *pgx.ConnConfig) error {
+				calls++
+				connConfig.Password = password
+				return nil
+			})
+		t.Cleanup(pool.Close)
+
+		first, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		second, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		test.CloseConnection(ctx, t, first)
+		test.CloseConnection(ctx, t, second)
+		assert.Equal(t, 2, calls)
+
+		third, err := pool.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		test.CloseConnection(ctx, t, third)
+		assert.Equal(t, 2, calls)
+	})
+}
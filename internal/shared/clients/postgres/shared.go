@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type sharedScopeKey struct{}
+
+// sharedScope holds the connections handed out by Connect inside a WithConnection or WithTransaction call.
+// Connections are acquired lazily, one per database, the first time Connect asks for that database.
+type sharedScope struct {
+	// outer is the context WithConnection or WithTransaction was called with. Connections are
+	// acquired through it so that a transaction inside a WithConnection runs on the outer connection.
+	outer         context.Context
+	transactional bool
+
+	mu    sync.Mutex
+	conns map[string]*sharedEntry
+}
+
+type sharedEntry struct {
+	conn Conn
+	// tx is nil unless the scope is transactional
+	tx pgx.Tx
+}
+
+// Connect returns the connection to databaseName shared by an enclosing WithConnection or WithTransaction,
+// acquiring it from db if this is the first request for databaseName in the scope. Outside any such scope
+// it is the same as db.Connect. Either way, callers should Close the returned Conn when they are done;
+// closing a shared connection is a no-op and the connection is released when the scope ends.
+func Connect(ctx context.Context, db DB, databaseName string) (Conn, error) {
+	scope, ok := ctx.Value(sharedScopeKey{}).(*sharedScope)
+	if !ok {
+		return db.Connect(ctx, databaseName)
+	}
+	return scope.connect(ctx, db, databaseName)
+}
+
+// WithConnection calls f with a context in which Connect returns the same connection every time it is
+// asked for the same database, so that several store operations only use one connection between them.
+// The context passed to f must not be used by concurrent goroutines, since a connection
+// can only run one query at a time. If there is already an enclosing WithConnection or WithTransaction,
+// f is called with ctx as is.
+func WithConnection(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sharedScopeKey{}).(*sharedScope); ok {
+		return f(ctx)
+	}
+	return withScope(ctx, false, f)
+}
+
+// WithTransaction is like WithConnection, but the shared connections run inside a transaction that is
+// committed if f returns nil and rolled back otherwise. Store methods that start their own transactions
+// run them as nested transactions (savepoints). If there is already an enclosing WithTransaction,
+// f becomes part of its transaction. A WithTransaction inside a WithConnection runs its transaction
+// on the outer shared connection.
+func WithTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	if scope, ok := ctx.Value(sharedScopeKey{}).(*sharedScope); ok && scope.transactional {
+		return f(ctx)
+	}
+	return withScope(ctx, true, f)
+}
+
+func withScope(ctx context.Context, transactional bool, f func(ctx context.Context) error) (err error) {
+	scope := &sharedScope{
+		outer:         ctx,
+		transactional: transactional,
+		conns:         map[string]*sharedEntry{},
+	}
+	defer func() {
+		if endErr := scope.end(ctx, err); endErr != nil {
+			err = errors.Join(err, endErr)
+		}
+	}()
+	return f(context.WithValue(ctx, sharedScopeKey{}, scope))
+}
+
+func (s *sharedScope) connect(ctx context.Context, db DB, databaseName string) (Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, found := s.conns[databaseName]; found {
+		return entry.shared(), nil
+	}
+	conn, err := Connect(s.outer, db, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	entry := &sharedEntry{conn: conn}
+	if s.transactional {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			_ = conn.Close(ctx)
+			return nil, fmt.Errorf("error beginning shared transaction on database %s: %w", databaseName, err)
+		}
+		entry.tx = tx
+	}
+	s.conns[databaseName] = entry
+	return entry.shared(), nil
+}
+
+// end commits or rolls back any transactions, depending on whether fErr is nil, and then
+// closes the connections.
+func (s *sharedScope) end(ctx context.Context, fErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for databaseName, entry := range s.conns {
+		if entry.tx != nil {
+			if fErr == nil {
+				if err := entry.tx.Commit(ctx); err != nil {
+					errs = append(errs, fmt.Errorf("error committing shared transaction on database %s: %w", databaseName, err))
+				}
+			} else if err := entry.tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+				errs = append(errs, fmt.Errorf("error rolling back shared transaction on database %s: %w", databaseName, err))
+			}
+		}
+		if err := entry.conn.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("error closing shared connection to database %s: %w", databaseName, err))
+		}
+		delete(s.conns, databaseName)
+	}
+	return errors.Join(errs...)
+}
+
+func (e *sharedEntry) shared() Conn {
+	if e.tx != nil {
+		return sharedConn{e.tx}
+	}
+	return sharedConn{e.conn}
+}
+
+// sharedConn is handed out by Connect inside a scope. Its Close does nothing since the
+// scope owns the connection.
+type sharedConn struct {
+	Querier
+}
+
+func (sharedConn) Close(_ context.Context) error {
+	return nil
+}
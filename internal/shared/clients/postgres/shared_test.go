@@ -0,0 +1,259 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWithConnection(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"connections to the same database should be shared", testSharesConnection},
+		{"connections to different databases should not be shared", testSharesPerDatabase},
+		{"connections should be closed when the scope ends, even on error", testClosesAtEnd},
+		{"nested WithConnection should reuse the outer connection", testNestedWithConnection},
+		{"unused scope should not connect", testLazyConnect},
+		{"outside a scope, Connect should not share", testNoScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+func testSharesConnection(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+		for i := 0; i < 3; i++ {
+			conn, err := postgres.Connect(ctx, db, "collections")
+			require.NoError(t, err)
+			// closing a shared connection should not close the underlying one
+			require.NoError(t, conn.Close(ctx))
+		}
+		assert.Equal(t, 1, db.connects["collections"])
+		assert.Zero(t, db.closes["collections"])
+		return nil
+	}))
+	assert.Equal(t, 1, db.closes["collections"])
+}
+
+func testSharesPerDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+		for _, databaseName := range []string{"collections", "users", "collections", "users"} {
+			conn, err := postgres.Connect(ctx, db, databaseName)
+			require.NoError(t, err)
+			require.NoError(t, conn.Close(ctx))
+		}
+		return nil
+	}))
+	assert.Equal(t, map[string]int{"collections": 1, "users": 1}, db.connects)
+	assert.Equal(t, map[string]int{"collections": 1, "users": 1}, db.closes)
+}
+
+func testClosesAtEnd(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	expectedErr := errors.New("store error")
+	err := postgres.WithConnection(ctx, func(ctx context.Context) error {
+		_, err := postgres.Connect(ctx, db, "collections")
+		require.NoError(t, err)
+		return expectedErr
+	})
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Equal(t, 1, db.closes["collections"])
+}
+
+func testNestedWithConnection(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+		_, err := postgres.Connect(ctx, db, "collections")
+		require.NoError(t, err)
+		require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+			_, err := postgres.Connect(ctx, db, "collections")
+			return err
+		}))
+		// inner scope should not have closed the outer connection
+		assert.Zero(t, db.closes["collections"])
+		return nil
+	}))
+	assert.Equal(t, 1, db.connects["collections"])
+	assert.Equal(t, 1, db.closes["collections"])
+}
+
+func testLazyConnect(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+		return nil
+	}))
+	assert.Empty(t, db.connects)
+}
+
+func testNoScope(t *testing.T) {
+	ctx := context.Background()
+	db := newFakeDB()
+	for i := 0; i < 2; i++ {
+		conn, err := postgres.Connect(ctx, db, "collections")
+		require.NoError(t, err)
+		require.NoError(t, conn.Close(ctx))
+	}
+	assert.Equal(t, 2, db.connects["collections"])
+	assert.Equal(t, 2, db.closes["collections"])
+}
+
+func TestWithTransaction(t *testing.T) {
+	ctx := context.Background()
+	config := test.PostgresDBConfig(t)
+	db := test.NewPostgresDBFromConfig(t, config)
+	databaseName := config.CollectionsDatabase
+
+	table := fmt.Sprintf("collections.shared_test_%s", strings.ReplaceAll(uuid.NewString(), "-", ""))
+	conn, err := db.Connect(ctx, databaseName)
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, fmt.Sprintf("CREATE TABLE %s (value text NOT NULL)", table))
+	require.NoError(t, err)
+	test.CloseConnection(ctx, t, conn)
+	t.Cleanup(func() {
+		conn, err := db.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		defer test.CloseConnection(ctx, t, conn)
+		_, err = conn.Exec(ctx, fmt.Sprintf("DROP TABLE %s", table))
+		require.NoError(t, err)
+	})
+
+	// insert mimics a store method: it connects, runs its own transaction, and closes
+	insert := func(ctx context.Context, value string) error {
+		conn, err := postgres.Connect(ctx, db, databaseName)
+		if err != nil {
+			return err
+		}
+		defer test.CloseConnection(ctx, t, conn)
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (value) VALUES ($1)", table), value)
+			return err
+		})
+	}
+
+	values := func() []string {
+		conn, err := db.Connect(ctx, databaseName)
+		require.NoError(t, err)
+		defer test.CloseConnection(ctx, t, conn)
+		rows, _ := conn.Query(ctx, fmt.Sprintf("SELECT value FROM %s ORDER BY value", table))
+		values, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		require.NoError(t, err)
+		return values
+	}
+
+	t.Run("should commit when f succeeds", func(t *testing.T) {
+		require.NoError(t, postgres.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, "a"); err != nil {
+				return err
+			}
+			return insert(ctx, "b")
+		}))
+		assert.Equal(t, []string{"a", "b"}, values())
+	})
+
+	t.Run("should roll back everything when f fails", func(t *testing.T) {
+		expectedErr := errors.New("second step failed")
+		err := postgres.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, "c"); err != nil {
+				return err
+			}
+			return expectedErr
+		})
+		assert.ErrorIs(t, err, expectedErr)
+		assert.Equal(t, []string{"a", "b"}, values())
+	})
+
+	t.Run("should not be visible outside until committed", func(t *testing.T) {
+		require.NoError(t, postgres.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, "d"); err != nil {
+				return err
+			}
+			assert.Equal(t, []string{"a", "b"}, values())
+			return nil
+		}))
+		assert.Equal(t, []string{"a", "b", "d"}, values())
+	})
+
+	t.Run("inside WithConnection should run on the shared connection", func(t *testing.T) {
+		backendPID := func(ctx context.Context) int {
+			conn, err := postgres.Connect(ctx, db, databaseName)
+			require.NoError(t, err)
+			defer test.CloseConnection(ctx, t, conn)
+			var pid int
+			require.NoError(t, conn.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&pid))
+			return pid
+		}
+		require.NoError(t, postgres.WithConnection(ctx, func(ctx context.Context) error {
+			outerPID := backendPID(ctx)
+			err := postgres.WithTransaction(ctx, func(ctx context.Context) error {
+				assert.Equal(t, outerPID, backendPID(ctx))
+				return insert(ctx, "e")
+			})
+			assert.Equal(t, outerPID, backendPID(ctx))
+			return err
+		}))
+		assert.Equal(t, []string{"a", "b", "d", "e"}, values())
+	})
+}
+
+type fakeDB struct {
+	connects map[string]int
+	closes   map[string]int
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{connects: map[string]int{}, closes: map[string]int{}}
+}
+
+func (db *fakeDB) Connect(_ context.Context, databaseName string) (postgres.Conn, error) {
+	db.connects[databaseName]++
+	return &fakeConn{db: db, databaseName: databaseName}, nil
+}
+
+// fakeConn only counts Close calls. Tests using it should not run queries.
+type fakeConn struct {
+	db           *fakeDB
+	databaseName string
+}
+
+func (c *fakeConn) Exec(_ context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+	panic("not implemented")
+}
+
+func (c *fakeConn) Query(_ context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	panic("not implemented")
+}
+
+func (c *fakeConn) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	panic("not implemented")
+}
+
+func (c *fakeConn) Begin(_ context.Context) (pgx.Tx, error) {
+	panic("not implemented")
+}
+
+func (c *fakeConn) Close(_ context.Context) error {
+	c.db.closes[c.databaseName]++
+	return nil
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryQueue is a Queue for tests and local development. Messages are lost when the process exits.
+type MemoryQueue struct {
+	mu       sync.Mutex
+	nextID   int64
+	messages []*memoryMessage
+	now      func() time.Time
+}
+
+type memoryMessage struct {
+	id           int64
+	body         string
+	receiveCount int
+	visibleAt    time.Time
+}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{now: time.Now}
+}
+
+// WithClock replaces the clock used to decide which messages are visible.
+func (q *MemoryQueue) WithClock(now func() time.Time) *MemoryQueue {
+	q.now = now
+	return q
+}
+
+func (q *MemoryQueue) Send(_ context.Context, body string) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextID++
+	q.messages = append(q.messages, &memoryMessage{id: q.nextID, body: body, visibleAt: q.now()})
+	return strconv.FormatInt(q.nextID, 10), nil
+}
+
+func (q *MemoryQueue) Receive(_ context.Context, maxMessages int, visibilityTimeout time.Duration) ([]Message, error) {
+	if err := validateReceive(maxMessages, visibilityTimeout); err != nil {
+		return nil, err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var received []Message
+	for _, message := range q.messages {
+		if len(received) == maxMessages {
+			break
+		}
+		if message.visibleAt.After(now) {
+			continue
+		}
+		message.receiveCount++
+		message.visibleAt = now.Add(visibilityTimeout)
+		received = append(received, Message{
+			ID:            strconv.FormatInt(message.id, 10),
+			ReceiptHandle: receiptHandle(message.id, message.receiveCount),
+			Body:          message.body,
+			ReceiveCount:  message.receiveCount,
+		})
+	}
+	return received, nil
+}
+
+func (q *MemoryQueue) Delete(_ context.Context, handle string) error {
+	messageID, receiveCount, err := parseReceiptHandle(handle)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, message := range q.messages {
+		if message.id == messageID && message.receiveCount == receiveCount {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return nil
+		}
+	}
+	return ErrReceiptHandleNotFound
+}
+
+// Len returns the number of messages in the queue, visible or not.
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
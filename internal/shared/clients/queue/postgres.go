@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// PostgresQueue is a Queue stored in the collections.queue_messages table. Several queues can share the table,
+// each identified by its name.
+type PostgresQueue struct {
+	db           postgres.DB
+	databaseName string
+	name         string
+	logger       *slog.Logger
+}
+
+func NewPostgresQueue(db postgres.DB, databaseName string, name string, logger *slog.Logger) *PostgresQueue {
+	return &PostgresQueue{
+		db:           db,
+		databaseName: databaseName,
+		name:         name,
+		logger:       logger.With(slog.String("type", "queue.PostgresQueue"), slog.String("queue", name)),
+	}
+}
+
+func (q *PostgresQueue) Send(ctx context.Context, body string) (string, error) {
+	conn, err := postgres.Connect(ctx, q.db, q.databaseName)
+	if err != nil {
+		return "", fmt.Errorf("Send error connecting to database %s: %w", q.databaseName, err)
+	}
+	defer q.closeConn(ctx, conn)
+
+	query := `INSERT INTO collections.queue_messages (queue, body, visible_at, created_at)
+              VALUES (@queue, @body, @now, @now)
+              RETURNING id`
+	args := pgx.NamedArgs{
+		"queue": q.name,
+		"body":  body,
+		"now":   time.Now().UTC(),
+	}
+	var messageID int64
+	if err := conn.QueryRow(ctx, query, args).Scan(&messageID); err != nil {
+		return "", fmt.Errorf("error sending message to queue %s: %w", q.name, err)
+	}
+	return strconv.FormatInt(messageID, 10), nil
+}
+
+func (q *PostgresQueue) Receive(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]Message, error) {
+	if err := validateReceive(maxMessages, visibilityTimeout); err != nil {
+		return nil, err
+	}
+	conn, err := postgres.Connect(ctx, q.db, q.databaseName)
+	if err != nil {
+		return nil, fmt.Errorf("Receive error connecting to database %s: %w", q.databaseName, err)
+	}
+	defer q.closeConn(ctx, conn)
+
+	// SKIP LOCKED lets concurrent receivers each get different messages instead of waiting on each other
+	query := `UPDATE collections.queue_messages m
+              SET receive_count = m.receive_count + 1,
+                  visible_at = @visible_at
+              FROM (SELECT id FROM collections.queue_messages
+                    WHERE queue = @queue AND visible_at <= @now
+                    ORDER BY id
+                    LIMIT @max_messages
+                    FOR UPDATE SKIP LOCKED) next
+              WHERE m.id = next.id
+              RETURNING m.id, m.body, m.receive_count`
+	now := time.Now().UTC()
+	args := pgx.NamedArgs{
+		"queue":        q.name,
+		"now":          now,
+		"visible_at":   now.Add(visibilityTimeout),
+		"max_messages": maxMessages,
+	}
+
+	// any error here will be returned from pgx.CollectRows which also closes rows for us
+	rows, _ := conn.Query(ctx, query, args)
+	type received struct {
+		id           int64
+		body         string
+		receiveCount int
+	}
+	receivedRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (received, error) {
+		var r received
+		err := row.Scan(&r.id, &r.body, &r.receiveCount)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error receiving messages from queue %s: %w", q.name, err)
+	}
+	// RETURNING does not keep the order of the subquery
+	slices.SortFunc(receivedRows, func(a, b received) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	messages := make([]Message, 0, len(receivedRows))
+	for _, r := range receivedRows {
+		messages = append(messages, Message{
+			ID:            strconv.FormatInt(r.id, 10),
+			ReceiptHandle: receiptHandle(r.id, r.receiveCount),
+			Body:          r.body,
+			ReceiveCount:  r.receiveCount,
+		})
+	}
+	return messages, nil
+}
+
+func (q *PostgresQueue) Delete(ctx context.Context, handle string) error {
+	messageID, receiveCount, err := parseReceiptHandle(handle)
+	if err != nil {
+		return err
+	}
+	conn, err := postgres.Connect(ctx, q.db, q.databaseName)
+	if err != nil {
+		return fmt.Errorf("Delete error connecting to database %s: %w", q.databaseName, err)
+	}
+	defer q.closeConn(ctx, conn)
+
+	query := `DELETE FROM collections.queue_messages
+              WHERE queue = @queue AND id = @id AND receive_count = @receive_count`
+	args := pgx.NamedArgs{
+		"queue":         q.name,
+		"id":            messageID,
+		"receive_count": receiveCount,
+	}
+	tag, err := conn.Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("error deleting message %d from queue %s: %w", messageID, q.name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReceiptHandleNotFound
+	}
+	return nil
+}
+
+func (q *PostgresQueue) closeConn(ctx context.Context, conn postgres.Conn) {
+	if err := conn.Close(ctx); err != nil {
+		q.logger.Warn("error closing queue.PostgresQueue DB connection", slog.Any("error", err))
+	}
+}
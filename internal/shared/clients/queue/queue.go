@@ -0,0 +1,66 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrReceiptHandleNotFound is returned by Delete if the message has already been deleted or has been received again
+// since the receipt handle was issued.
+var ErrReceiptHandleNotFound = errors.New("receipt handle not found")
+
+// Queue is modelled on SQS: delivery is at least once, and a received message is only hidden from other receivers
+// until its visibility timeout passes. A receiver that finishes with a message must delete it, otherwise it will be
+// delivered again.
+type Queue interface {
+	// Send adds a message with the given body to the queue and returns the id of the message.
+	Send(ctx context.Context, body string) (string, error)
+	// Receive returns up to maxMessages visible messages, oldest first, and hides them for visibilityTimeout.
+	Receive(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]Message, error)
+	// Delete removes a received message from the queue. Returns ErrReceiptHandleNotFound if the receipt handle is not
+	// from the most recent receipt of a message still in the queue.
+	Delete(ctx context.Context, receiptHandle string) error
+}
+
+type Message struct {
+	ID string
+	// ReceiptHandle identifies this receipt of the message. It is needed to delete the message.
+	ReceiptHandle string
+	Body          string
+	// ReceiveCount is the number of times the message has been received, including this time.
+	ReceiveCount int
+}
+
+// receiptHandle identifies a receipt by the message id and receive count, so that a receiver whose visibility timeout
+// has passed cannot delete a message that has since been received by someone else.
+func receiptHandle(messageID int64, receiveCount int) string {
+	return fmt.Sprintf("%d:%d", messageID, receiveCount)
+}
+
+func parseReceiptHandle(handle string) (messageID int64, receiveCount int, err error) {
+	idPart, countPart, found := strings.Cut(handle, ":")
+	if !found {
+		return 0, 0, fmt.Errorf("malformed receipt handle %q", handle)
+	}
+	if messageID, err = strconv.ParseInt(idPart, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("malformed message id in receipt handle %q: %w", handle, err)
+	}
+	if receiveCount, err = strconv.Atoi(countPart); err != nil {
+		return 0, 0, fmt.Errorf("malformed receive count in receipt handle %q: %w", handle, err)
+	}
+	return messageID, receiveCount, nil
+}
+
+func validateReceive(maxMessages int, visibilityTimeout time.Duration) error {
+	if maxMessages <= 0 {
+		return fmt.Errorf("maxMessages must be positive: %d", maxMessages)
+	}
+	if visibilityTimeout < 0 {
+		return fmt.Errorf("visibilityTimeout cannot be negative: %s", visibilityTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package queue_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var queueTests = []struct {
+	scenario string
+	tstFunc  func(t *testing.T, q queue.Queue)
+}{
+	{"received messages should be oldest first and limited by maxMessages", testReceiveOrder},
+	{"received messages should be hidden until their visibility timeout passes", testReceiveHidden},
+	{"messages not deleted should be received again", testReceiveAgain},
+	{"deleted messages should not be received again", testDelete},
+	{"delete with a superseded receipt handle should return ErrReceiptHandleNotFound", testDeleteSuperseded},
+	{"delete with a malformed receipt handle should return an error", testDeleteMalformed},
+	{"receive with invalid arguments should return an error", testReceiveInvalid},
+}
+
+func TestMemoryQueue(t *testing.T) {
+	for _, tt := range queueTests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t, queue.NewMemoryQueue())
+		})
+	}
+	t.Run("messages should be visible again once the clock passes their visibility timeout", testMemoryQueueClock)
+}
+
+func TestPostgresQueue(t *testing.T) {
+	ctx := context.Background()
+	config := test.PostgresDBConfig(t)
+
+	for _, tt := range queueTests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := test.NewPostgresDBFromConfig(t, config)
+			// a queue of our own, so that tests do not see each other's messages
+			name := uuid.NewString()
+			t.Cleanup(func() {
+				conn, err := db.Connect(ctx, config.CollectionsDatabase)
+				require.NoError(t, err)
+				defer test.CloseConnection(ctx, t, conn)
+				_, err = conn.Exec(ctx, "DELETE FROM collections.queue_messages WHERE queue = $1", name)
+				require.NoError(t, err)
+			})
+
+			tt.tstFunc(t, queue.NewPostgresQueue(db, config.CollectionsDatabase, name, logging.Default))
+		})
+	}
+}
+
+func sendAll(ctx context.Context, t *testing.T, q queue.Queue, bodies ...string) {
+	for _, body := range bodies {
+		_, err := q.Send(ctx, body)
+		require.NoError(t, err)
+	}
+}
+
+func bodies(messages []queue.Message) []string {
+	var received []string
+	for _, message := range messages {
+		received = append(received, message.Body)
+	}
+	return received
+}
+
+func testReceiveOrder(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+	sendAll(ctx, t, q, "first", "second", "third")
+
+	messages, err := q.Receive(ctx, 2, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, bodies(messages))
+	for _, message := range messages {
+		assert.NotEmpty(t, message.ID)
+		assert.NotEmpty(t, message.ReceiptHandle)
+		assert.Equal(t, 1, message.ReceiveCount)
+	}
+}
+
+func testReceiveHidden(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+	sendAll(ctx, t, q, "first", "second")
+
+	messages, err := q.Receive(ctx, 1, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first"}, bodies(messages))
+
+	messages, err = q.Receive(ctx, 10, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second"}, bodies(messages))
+
+	messages, err = q.Receive(ctx, 10, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func testReceiveAgain(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+	sendAll(ctx, t, q, "body")
+
+	first, err := q.Receive(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	second, err := q.Receive(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+
+	assert.Equal(t, first[0].ID, second[0].ID)
+	assert.Equal(t, "body", second[0].Body)
+	assert.Equal(t, 2, second[0].ReceiveCount)
+	assert.NotEqual(t, first[0].ReceiptHandle, second[0].ReceiptHandle)
+}
+
+func testDelete(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+	sendAll(ctx, t, q, "first", "second")
+
+	messages, err := q.Receive(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.NoError(t, q.Delete(ctx, messages[0].ReceiptHandle))
+
+	messages, err = q.Receive(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second"}, bodies(messages))
+
+	// already deleted
+	assert.ErrorIs(t, q.Delete(ctx, "1000000:1"), queue.ErrReceiptHandleNotFound)
+}
+
+func testDeleteSuperseded(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+	sendAll(ctx, t, q, "body")
+
+	first, err := q.Receive(ctx, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	second, err := q.Receive(ctx, 1, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+
+	// the first receiver took too long, so the message belongs to the second
+	assert.ErrorIs(t, q.Delete(ctx, first[0].ReceiptHandle), queue.ErrReceiptHandleNotFound)
+	assert.NoError(t, q.Delete(ctx, second[0].ReceiptHandle))
+}
+
+func testDeleteMalformed(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+
+	for _, handle := range []string{"", "1", "a:1", "1:a"} {
+		err := q.Delete(ctx, handle)
+		assert.Error(t, err, "handle %q", handle)
+		assert.NotErrorIs(t, err, queue.ErrReceiptHandleNotFound, "handle %q", handle)
+	}
+}
+
+func testReceiveInvalid(t *testing.T, q queue.Queue) {
+	ctx := context.Background()
+
+	_, err := q.Receive(ctx, 0, time.Minute)
+	assert.ErrorContains(t, err, "maxMessages")
+
+	_, err = q.Receive(ctx, 1, -time.Minute)
+	assert.ErrorContains(t, err, "visibilityTimeout")
+}
+
+func testMemoryQueueClock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	q := queue.NewMemoryQueue().WithClock(func() time.Time {
+		return now
+	})
+	sendAll(ctx, t, q, "body")
+
+	messages, err := q.Receive(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	now = now.Add(59 * time.Second)
+	messages, err = q.Receive(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	now = now.Add(time.Second)
+	messages, err = q.Receive(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, 2, messages[0].ReceiveCount)
+	assert.Equal(t, 1, q.Len())
+}
@@ -1,11 +1,20 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
 type PostgresDBConfig struct {
 	Host                string
 	Port                int
 	User                string
 	Password            *string
 	CollectionsDatabase string
+	// MaxConns, MaxConnLifetime, and MaxConnIdleTime limit the connection pool of each database.
+	MaxConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
 }
 
 type PostgresDBOption func(postgresDBConfig *PostgresDBConfig)
@@ -64,9 +73,41 @@ func (c PostgresDBConfig) LoadWithEnvSettings(environmentSettings PostgresDBEnvi
 		}
 		c.CollectionsDatabase = databaseName
 	}
+	if c.MaxConns == 0 {
+		maxConns, err := getPositiveInt(environmentSettings.MaxConns)
+		if err != nil {
+			return PostgresDBConfig{}, err
+		}
+		c.MaxConns = int32(maxConns)
+	}
+	if c.MaxConnLifetime == 0 {
+		minutes, err := getPositiveInt(environmentSettings.MaxConnLifetime)
+		if err != nil {
+			return PostgresDBConfig{}, err
+		}
+		c.MaxConnLifetime = time.Duration(minutes) * time.Minute
+	}
+	if c.MaxConnIdleTime == 0 {
+		minutes, err := getPositiveInt(environmentSettings.MaxConnIdleTime)
+		if err != nil {
+			return PostgresDBConfig{}, err
+		}
+		c.MaxConnIdleTime = time.Duration(minutes) * time.Minute
+	}
 	return c, nil
 }
 
+func getPositiveInt(setting EnvironmentSetting) (int, error) {
+	value, err := setting.GetInt()
+	if err != nil {
+		return 0, err
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("'%s' must be positive: %d", setting.Key, value)
+	}
+	return value, nil
+}
+
 // Load returns a copy of this PostgresDBConfig where any missing fields are populated by the
 // given DeployedPostgresDBEnvironmentSettings.
 func (c PostgresDBConfig) Load() (PostgresDBConfig, error) {
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPostgresDBConfig_PoolLimits(t *testing.T) {
+	settings := PostgresDBEnvironmentSettings{
+		Host:                NewEnvironmentSettingWithDefault(PostgresHostKey, "localhost"),
+		Port:                NewEnvironmentSettingWithDefault(PostgresPortKey, DefaultPostgresPort),
+		User:                NewEnvironmentSettingWithDefault(PostgresUserKey, "postgres"),
+		Password:            NewEnvironmentSetting(PostgresPasswordKey),
+		CollectionsDatabase: NewEnvironmentSettingWithDefault(PostgresCollectionsDatabaseKey, "postgres"),
+		MaxConns:            DeployedPostgresDBEnvironmentSettings.MaxConns,
+		MaxConnLifetime:     DeployedPostgresDBEnvironmentSettings.MaxConnLifetime,
+		MaxConnIdleTime:     DeployedPostgresDBEnvironmentSettings.MaxConnIdleTime,
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		config, err := NewPostgresDBConfig().LoadWithEnvSettings(settings)
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), config.MaxConns)
+		assert.Equal(t, 30*time.Minute, config.MaxConnLifetime)
+		assert.Equal(t, 5*time.Minute, config.MaxConnIdleTime)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv(PostgresMaxConnsKey, "2")
+		t.Setenv(PostgresMaxConnLifetimeMinutesKey, "10")
+		t.Setenv(PostgresMaxConnIdleMinutesKey, "1")
+		config, err := NewPostgresDBConfig().LoadWithEnvSettings(settings)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), config.MaxConns)
+		assert.Equal(t, 10*time.Minute, config.MaxConnLifetime)
+		assert.Equal(t, time.Minute, config.MaxConnIdleTime)
+	})
+
+	for _, key := range []string{PostgresMaxConnsKey, PostgresMaxConnLifetimeMinutesKey, PostgresMaxConnIdleMinutesKey} {
+		t.Run(key+" must be positive", func(t *testing.T) {
+			t.Setenv(key, "0")
+			_, err := NewPostgresDBConfig().LoadWithEnvSettings(settings)
+			assert.ErrorContains(t, err, key)
+		})
+	}
+}
@@ -6,22 +6,40 @@ const PostgresUserKey = "POSTGRES_USER"
 const PostgresPasswordKey = "POSTGRES_PASSWORD"
 const PostgresCollectionsDatabaseKey = "POSTGRES_COLLECTIONS_DATABASE"
 
+// PostgresMaxConnsKey is the env var holding the maximum number of connections each Lambda instance keeps per database.
+// Every concurrent Lambda instance has its own pool, so this times the Lambda concurrency must fit within what RDS Proxy allows.
+const PostgresMaxConnsKey = "POSTGRES_MAX_CONNS"
+const PostgresMaxConnLifetimeMinutesKey = "POSTGRES_MAX_CONN_LIFETIME_MINUTES"
+const PostgresMaxConnIdleMinutesKey = "POSTGRES_MAX_CONN_IDLE_MINUTES"
+
 type PostgresDBEnvironmentSettings struct {
 	Host                EnvironmentSetting
 	Port                EnvironmentSetting
 	User                EnvironmentSetting
 	Password            EnvironmentSetting
 	CollectionsDatabase EnvironmentSetting
+	MaxConns            EnvironmentSetting
+	MaxConnLifetime     EnvironmentSetting
+	MaxConnIdleTime     EnvironmentSetting
 }
 
 var DefaultPostgresPort = "5432"
 
+// A Lambda instance only handles one request at a time, so it rarely needs more than the one connection
+// shared by the request, plus a few for concurrent Discover cache lookups.
+var DefaultPostgresMaxConns = "4"
+var DefaultPostgresMaxConnLifetimeMinutes = "30"
+var DefaultPostgresMaxConnIdleMinutes = "5"
+
 // DeployedPostgresDBEnvironmentSettings are the settings used for actual deployments (as opposed to tests).
-// The only default value the Postgres port 5432.
+// The only default values are the Postgres port 5432 and the connection pool limits.
 var DeployedPostgresDBEnvironmentSettings = PostgresDBEnvironmentSettings{
 	Host:                NewEnvironmentSetting(PostgresHostKey),
 	Port:                NewEnvironmentSettingWithDefault(PostgresPortKey, DefaultPostgresPort),
 	User:                NewEnvironmentSetting(PostgresUserKey),
 	Password:            NewEnvironmentSetting(PostgresPasswordKey),
 	CollectionsDatabase: NewEnvironmentSetting(PostgresCollectionsDatabaseKey),
+	MaxConns:            NewEnvironmentSettingWithDefault(PostgresMaxConnsKey, DefaultPostgresMaxConns),
+	MaxConnLifetime:     NewEnvironmentSettingWithDefault(PostgresMaxConnLifetimeMinutesKey, DefaultPostgresMaxConnLifetimeMinutes),
+	MaxConnIdleTime:     NewEnvironmentSettingWithDefault(PostgresMaxConnIdleMinutesKey, DefaultPostgresMaxConnIdleMinutes),
 }
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by HTTPClient.Invoke without making a request when too many recent requests to the
+// dependency have failed.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker stops requests to a failing dependency so that callers fail fast instead of each waiting out
+// timeouts and retries. It opens after threshold consecutive failures. Once cooldown has passed, it lets a single
+// request through: if that succeeds, it closes again, otherwise it stays open for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	openedAt            *time.Time
+	probing             bool
+}
+
+// newCircuitBreaker returns a circuitBreaker that never opens if threshold is zero or less.
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns false if a request should not be made. If it returns true, the caller must report the result
+// with success or failure.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt == nil {
+		return true
+	}
+	if b.probing || b.now().Sub(*b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFailures = 0
+	b.openedAt = nil
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFailures++
+	if b.probing || (b.threshold > 0 && b.consecutiveFailures >= b.threshold) {
+		openedAt := b.now()
+		b.openedAt = &openedAt
+		b.probing = false
+	}
+}
+
+// release is for requests that were allowed but ended without a result that says anything about the dependency,
+// for example because the caller gave up. It lets the next request through if this one was the probe.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
@@ -19,32 +19,10 @@ func CloseAndWarn(response *http.Response, logger *slog.Logger) {
 	}
 }
 
-// Invoke makes the given request with http.DefaultClient.
-// If an error is being returned, this method will consume response.Body so it should be
-// called before the caller has read the body.
-func Invoke(request *http.Request, logger *slog.Logger) (*http.Response, error) {
-
-	res, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, fmt.Errorf("error invoking %s %s: %w", request.Method, request.URL, err)
-	}
-	if err := checkHTTPStatus(res); err != nil {
-		// if there was an error, checkHTTPStatus read the body
-		if closeError := res.Body.Close(); closeError != nil {
-			logger.Warn("error closing response body from http status error",
-				slog.String("method", request.Method),
-				slog.String("url", request.URL.String()),
-				slog.Any("error", closeError))
-		}
-		return nil, err
-	}
-	return res, nil
-}
-
-// checkHTTPStatus returns an error if 400 <= response status code < 600. Otherwise, returns nil.
+// checkHTTPStatus returns an *HTTPError if 400 <= response status code < 600. Otherwise, returns nil.
 // If an error is being returned, this function will consume response.Body so it should be
 // called before the caller has read the body.
-func checkHTTPStatus(response *http.Response) error {
+func checkHTTPStatus(response *http.Response) *HTTPError {
 	readBody := func() []byte {
 		body, err := io.ReadAll(response.Body)
 		if err != nil {
@@ -67,6 +45,7 @@ func checkHTTPStatus(response *http.Response) error {
 			errorType:   errorType,
 			response:    response,
 			displayBody: displayBody,
+			attempts:    1,
 		}
 	}
 	return nil
@@ -76,14 +55,17 @@ type HTTPError struct {
 	errorType   string
 	response    *http.Response
 	displayBody string
+	// attempts is the number of times the request was sent, including the one that got response.
+	attempts int
 }
 
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("%s error %s calling %s %s; response body: %s",
+	return fmt.Sprintf("%s error %s calling %s %s after %d attempt(s); response body: %s",
 		e.errorType,
 		e.response.Status,
 		e.response.Request.Method,
 		e.response.Request.URL,
+		e.attempts,
 		e.displayBody)
 }
 
@@ -91,6 +73,11 @@ func (e *HTTPError) StatusCode() int {
 	return e.response.StatusCode
 }
 
+// Attempts returns the number of times the request was sent before giving up.
+func (e *HTTPError) Attempts() int {
+	return e.attempts
+}
+
 func UnmarshallResponse(response *http.Response, dtoPointer any) error {
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
@@ -0,0 +1,216 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// HTTPInvoker makes requests to a single dependency. Implemented by HTTPClient.
+type HTTPInvoker interface {
+	// Invoke makes the given request and returns the response if its status is less than 400. Otherwise,
+	// returns an *HTTPError, and response.Body will have already been consumed and closed.
+	Invoke(request *http.Request) (*http.Response, error)
+}
+
+type HTTPClientConfig struct {
+	// Timeout limits each attempt, including reading the response body.
+	Timeout time.Duration
+	// MaxAttempts is the most times a request will be sent. One means no retries.
+	MaxAttempts int
+	// Before retry n, HTTPClient waits a random duration up to min(MaxBackoff, BaseBackoff * 2^(n-1)).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold is the number of consecutive failed attempts that open the circuit breaker.
+	// Zero turns the circuit breaker off.
+	BreakerThreshold int
+	// BreakerCooldown is how long the circuit breaker stays open before letting a request through to test
+	// the dependency.
+	BreakerCooldown time.Duration
+}
+
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:          10 * time.Second,
+		MaxAttempts:      3,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// WithTimeout returns a copy of c with the given timeout.
+func (c HTTPClientConfig) WithTimeout(timeout time.Duration) HTTPClientConfig {
+	c.Timeout = timeout
+	return c
+}
+
+// WithMaxAttempts returns a copy of c with the given max attempts.
+func (c HTTPClientConfig) WithMaxAttempts(maxAttempts int) HTTPClientConfig {
+	c.MaxAttempts = maxAttempts
+	return c
+}
+
+// HTTPClient is an HTTPInvoker for one dependency, with its own timeout and circuit breaker.
+// Idempotent requests are retried with jittered exponential backoff if they fail to get a response or get a 502, 503,
+// or 504. Requests are idempotent if their method is, or if they have an Idempotency-Key header, following
+// http.Transport.
+type HTTPClient struct {
+	name    string
+	client  *http.Client
+	config  HTTPClientConfig
+	breaker *circuitBreaker
+	sleep   func(ctx context.Context, d time.Duration) error
+	logger  *slog.Logger
+}
+
+// NewHTTPClient returns an HTTPClient for the named dependency. The name is used in errors and logs.
+func NewHTTPClient(name string, config HTTPClientConfig, logger *slog.Logger) *HTTPClient {
+	return &HTTPClient{
+		name:    name,
+		client:  &http.Client{Timeout: config.Timeout},
+		config:  config,
+		breaker: newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		sleep:   sleepContext,
+		logger:  logger.With(slog.String("type", "util.HTTPClient"), slog.String("dependency", name)),
+	}
+}
+
+// WithSleep replaces the function used to wait between attempts. For tests.
+func (c *HTTPClient) WithSleep(sleep func(ctx context.Context, d time.Duration) error) *HTTPClient {
+	c.sleep = sleep
+	return c
+}
+
+// WithClock replaces time.Now in the circuit breaker. For tests.
+func (c *HTTPClient) WithClock(now func() time.Time) *HTTPClient {
+	c.breaker.now = now
+	return c
+}
+
+func (c *HTTPClient) Invoke(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	maxAttempts := c.config.MaxAttempts
+	if !isRetryableRequest(request) {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.allow() {
+			return nil, fmt.Errorf("%s %s not sent to %s: %w", request.Method, request.URL, c.name, ErrCircuitOpen)
+		}
+		response, retryable, err := c.attempt(request, attempt)
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+		if !retryable || attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, lastErr
+		}
+
+		delay := c.backoff(attempt)
+		c.logger.Warn("retrying request",
+			slog.String("method", request.Method),
+			slog.String("url", request.URL.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err))
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("%w; gave up retrying: %w", lastErr, err)
+		}
+	}
+}
+
+// attempt makes one attempt at request and reports the result to the circuit breaker. The returned bool is true if
+// the error is one that a retry might not get.
+func (c *HTTPClient) attempt(request *http.Request, attempt int) (*http.Response, bool, error) {
+	attemptRequest := request
+	if attempt > 1 && request.Body != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			c.breaker.release()
+			return nil, false, fmt.Errorf("error getting body to retry %s %s: %w", request.Method, request.URL, err)
+		}
+		attemptRequest = request.Clone(request.Context())
+		attemptRequest.Body = body
+	}
+
+	response, err := c.client.Do(attemptRequest)
+	if err != nil {
+		if request.Context().Err() != nil {
+			// the caller gave up, so this says nothing about the dependency
+			c.breaker.release()
+		} else {
+			c.breaker.failure()
+		}
+		return nil, true, fmt.Errorf("error invoking %s %s (attempt %d): %w", request.Method, request.URL, attempt, err)
+	}
+
+	if httpErr := checkHTTPStatus(response); httpErr != nil {
+		// if there was an error, checkHTTPStatus read the body
+		if closeError := response.Body.Close(); closeError != nil {
+			c.logger.Warn("error closing response body from http status error",
+				slog.String("method", request.Method),
+				slog.String("url", request.URL.String()),
+				slog.Any("error", closeError))
+		}
+		httpErr.attempts = attempt
+		if httpErr.StatusCode() >= http.StatusInternalServerError {
+			c.breaker.failure()
+		} else {
+			c.breaker.success()
+		}
+		return nil, isRetryableStatus(httpErr.StatusCode()), httpErr
+	}
+
+	c.breaker.success()
+	return response, false, nil
+}
+
+// backoff returns a random duration up to min(MaxBackoff, BaseBackoff * 2^(attempt-1)) ("full jitter"), so that
+// callers that failed together do not all retry together.
+func (c *HTTPClient) backoff(attempt int) time.Duration {
+	ceiling := c.config.BaseBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.config.MaxBackoff {
+		ceiling = c.config.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+func isRetryableRequest(request *http.Request) bool {
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, hasKey := request.Header["Idempotency-Key"]
+	_, hasXKey := request.Header["X-Idempotency-Key"]
+	return hasKey || hasXKey
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusBadGateway ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,250 @@
+package util_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/shared/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPClient(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tstFunc  func(t *testing.T)
+	}{
+		{"successful requests should be returned", testInvokeSuccess},
+		{"idempotent requests should be retried on 502, 503, and 504", testRetryStatus},
+		{"HTTPError should record attempts when retries run out", testRetriesExhausted},
+		{"non-idempotent requests should not be retried", testNoRetryNonIdempotent},
+		{"requests with an Idempotency-Key should be retried with their body", testRetryIdempotencyKey},
+		{"other error statuses should not be retried", testNoRetryOtherStatus},
+		{"requests that get no response should be retried", testRetryConnectionError},
+		{"attempts should time out", testTimeout},
+		{"backoff should be jittered and capped", testBackoff},
+		{"circuit breaker should open after consecutive failures and close after a successful probe", testCircuitBreaker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			tt.tstFunc(t)
+		})
+	}
+}
+
+// statusServer responds with each of statuses in turn, and then with 200 OK. It returns the server and the count
+// of requests it has received.
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		i := int(count.Add(1)) - 1
+		if i < len(statuses) {
+			writer.WriteHeader(statuses[i])
+			_, _ = fmt.Fprintf(writer, "status %d", statuses[i])
+			return
+		}
+		_, _ = writer.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+func newTestClient(config util.HTTPClientConfig) *util.HTTPClient {
+	return util.NewHTTPClient("test", config, logging.Default).
+		WithSleep(func(_ context.Context, _ time.Duration) error { return nil })
+}
+
+func newRequest(t *testing.T, method, url string, body string) *http.Request {
+	var bodyReader io.Reader
+	if len(body) > 0 {
+		bodyReader = strings.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(context.Background(), method, url, bodyReader)
+	require.NoError(t, err)
+	return request
+}
+
+func requireBody(t *testing.T, response *http.Response, expected string) {
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(body))
+}
+
+func testInvokeSuccess(t *testing.T) {
+	server, count := statusServer(t)
+	response, err := newTestClient(util.DefaultHTTPClientConfig()).Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+	require.NoError(t, err)
+	requireBody(t, response, "ok")
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func testRetryStatus(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			server, count := statusServer(t, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+			client := newTestClient(util.DefaultHTTPClientConfig().WithMaxAttempts(4))
+			response, err := client.Invoke(newRequest(t, method, server.URL, ""))
+			require.NoError(t, err)
+			requireBody(t, response, "ok")
+			assert.Equal(t, int32(4), count.Load())
+		})
+	}
+}
+
+func testRetriesExhausted(t *testing.T) {
+	server, count := statusServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, err := newTestClient(util.DefaultHTTPClientConfig().WithMaxAttempts(3)).Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+
+	var httpError *util.HTTPError
+	require.ErrorAs(t, err, &httpError)
+	assert.Equal(t, http.StatusServiceUnavailable, httpError.StatusCode())
+	assert.Equal(t, 3, httpError.Attempts())
+	assert.Contains(t, httpError.Error(), "after 3 attempt(s)")
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func testNoRetryNonIdempotent(t *testing.T) {
+	server, count := statusServer(t, http.StatusServiceUnavailable)
+	_, err := newTestClient(util.DefaultHTTPClientConfig()).Invoke(newRequest(t, http.MethodPost, server.URL, `{"name": "test"}`))
+
+	var httpError *util.HTTPError
+	require.ErrorAs(t, err, &httpError)
+	assert.Equal(t, 1, httpError.Attempts())
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func testRetryIdempotencyKey(t *testing.T) {
+	var bodies []string
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if count.Add(1) == 1 {
+			writer.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	request := newRequest(t, http.MethodPost, server.URL, `{"name": "test"}`)
+	request.Header.Set("Idempotency-Key", "key")
+	response, err := newTestClient(util.DefaultHTTPClientConfig()).Invoke(request)
+	require.NoError(t, err)
+	requireBody(t, response, "")
+	assert.Equal(t, []string{`{"name": "test"}`, `{"name": "test"}`}, bodies)
+}
+
+func testNoRetryOtherStatus(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server, count := statusServer(t, status)
+			_, err := newTestClient(util.DefaultHTTPClientConfig()).Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+
+			var httpError *util.HTTPError
+			require.ErrorAs(t, err, &httpError)
+			assert.Equal(t, status, httpError.StatusCode())
+			assert.Equal(t, 1, httpError.Attempts())
+			assert.Equal(t, int32(1), count.Load())
+		})
+	}
+}
+
+func testRetryConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	// nothing is listening anymore
+	server.Close()
+
+	_, err := newTestClient(util.DefaultHTTPClientConfig().WithMaxAttempts(3)).Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "attempt 3")
+}
+
+func testTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-release:
+		case <-request.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+
+	config := util.DefaultHTTPClientConfig().WithTimeout(50 * time.Millisecond).WithMaxAttempts(1)
+	start := time.Now()
+	_, err := newTestClient(config).Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func testBackoff(t *testing.T) {
+	server, _ := statusServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	config := util.DefaultHTTPClientConfig().WithMaxAttempts(5)
+	config.BaseBackoff = 100 * time.Millisecond
+	config.MaxBackoff = 300 * time.Millisecond
+
+	var delays []time.Duration
+	client := util.NewHTTPClient("test", config, logging.Default).
+		WithSleep(func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		})
+	_, err := client.Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+	require.NoError(t, err)
+
+	require.Len(t, delays, 4)
+	for i, ceiling := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		assert.GreaterOrEqual(t, delays[i], time.Duration(0))
+		assert.Less(t, delays[i], ceiling)
+	}
+}
+
+func testCircuitBreaker(t *testing.T) {
+	server, count := statusServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+
+	config := util.DefaultHTTPClientConfig()
+	config.BreakerThreshold = 2
+	config.BreakerCooldown = time.Minute
+	now := time.Now()
+	client := newTestClient(config).WithClock(func() time.Time { return now })
+
+	invoke := func() error {
+		response, err := client.Invoke(newRequest(t, http.MethodGet, server.URL, ""))
+		if err == nil {
+			requireBody(t, response, "ok")
+		}
+		return err
+	}
+
+	// two failures open the circuit
+	assert.Error(t, invoke())
+	assert.Error(t, invoke())
+	assert.ErrorIs(t, invoke(), util.ErrCircuitOpen)
+	assert.Equal(t, int32(2), count.Load())
+
+	// after the cooldown, a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	err := invoke()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, util.ErrCircuitOpen))
+	assert.ErrorIs(t, invoke(), util.ErrCircuitOpen)
+	assert.Equal(t, int32(3), count.Load())
+
+	// a successful probe closes it
+	now = now.Add(time.Minute)
+	assert.NoError(t, invoke())
+	assert.NoError(t, invoke())
+	assert.Equal(t, int32(5), count.Load())
+}
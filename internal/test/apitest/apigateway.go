@@ -66,6 +66,15 @@ func (b *APIGatewayRequestBuilder) WithPathParam(key string, value string) *APIG
 	return b
 }
 
+// WithHeader sets a request header. API Gateway lowercases header names, so key should be lowercase.
+func (b *APIGatewayRequestBuilder) WithHeader(key string, value string) *APIGatewayRequestBuilder {
+	if b.r.Headers == nil {
+		b.r.Headers = make(map[string]string)
+	}
+	b.r.Headers[key] = value
+	return b
+}
+
 func (b *APIGatewayRequestBuilder) Build() events.APIGatewayV2HTTPRequest {
 	return *b.r
 }
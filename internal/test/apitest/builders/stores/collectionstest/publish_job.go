@@ -0,0 +1,31 @@
+package collectionstest
+
+import (
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/publishing"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"time"
+)
+
+// NewQueuedPublishJob returns a collections.PublishJob that has been queued but not yet attempted.
+func NewQueuedPublishJob(collectionID int64, collectionNodeID string, userID int64, userNodeID string) collections.PublishJob {
+	createdAt := time.Now().UTC().Add(-time.Minute)
+	return collections.PublishJob{
+		ID:               uuid.NewString(),
+		CollectionID:     collectionID,
+		CollectionNodeID: collectionNodeID,
+		UserID:           &userID,
+		UserNodeID:       &userNodeID,
+		Status:           publishing.JobQueuedStatus,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	}
+}
+
+// NewRunningPublishJob returns job as StartPublishJobAttempt would: Running, with one more attempt.
+func NewRunningPublishJob(job collections.PublishJob) collections.PublishJob {
+	job.Status = publishing.JobRunningStatus
+	job.Attempts++
+	job.UpdatedAt = time.Now().UTC()
+	return job
+}
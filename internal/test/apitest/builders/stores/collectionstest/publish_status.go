@@ -50,7 +50,14 @@ func (b *PublishStatusBuilder) WithUserID(userID *int64) *PublishStatusBuilder {
 	return b
 }
 
+// WithInProgressStartedAt sets status to InProgress with a StartedAt value in the past, but recent enough that the publish is not stale.
 func (b *PublishStatusBuilder) WithInProgressStartedAt() *PublishStatusBuilder {
+	startedAt := time.Now().UTC().Add(-time.Minute)
+	return b.WithStatus(publishing.InProgressStatus).WithStartedAt(startedAt)
+}
+
+// WithStaleInProgressStartedAt sets status to InProgress with a StartedAt value far enough in the past that the publish is stale.
+func (b *PublishStatusBuilder) WithStaleInProgressStartedAt() *PublishStatusBuilder {
 	startedAt := time.Now().UTC().AddDate(0, -1, 2)
 	return b.WithStatus(publishing.InProgressStatus).WithStartedAt(startedAt)
 }
@@ -84,6 +91,15 @@ func NewInProgressPublishStatus(collectionID, userID int64) collections.PublishS
 	return NewInProgressPublishStatusBuilder(collectionID, publishing.PublicationType).WithUserID(&userID).Build()
 }
 
+// NewStaleInProgressPublishStatus returns an InProgress Publication collections.PublishStatus with a
+// StartedAt value old enough to be stale and a nil FinishedAt value
+func NewStaleInProgressPublishStatus(collectionID, userID int64) collections.PublishStatus {
+	return NewPublishStatusBuilder(collectionID, publishing.PublicationType, publishing.InProgressStatus).
+		WithStaleInProgressStartedAt().
+		WithUserID(&userID).
+		Build()
+}
+
 // NewCompletedPublishStatus returns a Completed Publication collections.PublishStatus with a
 // StartedAt value in the past and a non-nil FinishedAt value later than StartedAt
 func NewCompletedPublishStatus(collectionID, userID int64) collections.PublishStatus {
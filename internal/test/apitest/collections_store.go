@@ -38,6 +38,8 @@ type ExpectedCollection struct {
 	Tags    []string
 	Users   []ExpectedUser
 	DOIs    ExpectedDOIs
+	// Version is only used by mocks. Zero means the mocks return responses without a version.
+	Version int64
 }
 
 func NewExpectedCollection() *ExpectedCollection {
@@ -60,6 +62,11 @@ func (c *ExpectedCollection) WithNodeID() *ExpectedCollection {
 
 // WithMockID is meant for cases where this ExpectedCollection is not persisted to the test DB
 // but still needs an ID for the test.
+func (c *ExpectedCollection) WithVersion(version int64) *ExpectedCollection {
+	c.Version = version
+	return c
+}
+
 func (c *ExpectedCollection) WithMockID(mockID int64) *ExpectedCollection {
 	c.ID = &mockID
 	return c
@@ -120,15 +127,36 @@ func (c *ExpectedCollection) WithUser(userID int64, permission pgdb.DbPermission
 	return c
 }
 
+// SetUser replaces the permission of the given user if already present. Otherwise, adds the user with the given permission.
+func (c *ExpectedCollection) SetUser(userID int64, permission pgdb.DbPermission) *ExpectedCollection {
+	if idx := slices.IndexFunc(c.Users, func(user ExpectedUser) bool {
+		return user.UserID == userID
+	}); idx >= 0 {
+		c.Users[idx].PermissionBit = permission
+		return c
+	}
+	return c.WithUser(userID, permission)
+}
+
+// RemoveUser removes the given user from the expected users if present.
+func (c *ExpectedCollection) RemoveUser(userID int64) *ExpectedCollection {
+	c.Users = slices.DeleteFunc(c.Users, func(user ExpectedUser) bool {
+		return user.UserID == userID
+	})
+	return c
+}
+
 type ExpectedDOI struct {
 	DOI        string
 	Datasource datasource.DOIDatasource
+	Label      string
+	Note       string
 }
 
 // WithDOIs adds to the current ExpectedDOI slice
 func (c *ExpectedCollection) WithDOIs(dois ...collections.DOI) *ExpectedCollection {
 	for _, doi := range dois {
-		c.DOIs = append(c.DOIs, ExpectedDOI{DOI: doi.Value, Datasource: doi.Datasource})
+		c.DOIs = append(c.DOIs, ExpectedDOI{DOI: doi.Value, Datasource: doi.Datasource, Label: doi.Label, Note: doi.Note})
 	}
 	return c
 }
@@ -137,7 +165,7 @@ func (c *ExpectedCollection) WithDOIs(dois ...collections.DOI) *ExpectedCollecti
 func (c *ExpectedCollection) SetDOIs(dois ...collections.DOI) *ExpectedCollection {
 	var newDOIs []ExpectedDOI
 	for _, doi := range dois {
-		newDOIs = append(newDOIs, ExpectedDOI{DOI: doi.Value, Datasource: doi.Datasource})
+		newDOIs = append(newDOIs, ExpectedDOI{DOI: doi.Value, Datasource: doi.Datasource, Label: doi.Label, Note: doi.Note})
 	}
 	c.DOIs = newDOIs
 	return c
@@ -167,6 +195,14 @@ func (c *ExpectedCollection) WithTombstones(tombstones ...dto.Tombstone) *Expect
 	return c
 }
 
+// WithExternalDatasets appends the DOIs of the given externalDatasets to the ExpectedDOIs
+func (c *ExpectedCollection) WithExternalDatasets(externalDatasets ...dto.ExternalDataset) *ExpectedCollection {
+	for _, externalDataset := range externalDatasets {
+		c.DOIs = append(c.DOIs, ExpectedDOI{DOI: externalDataset.DOI, Datasource: datasource.External})
+	}
+	return c
+}
+
 // SetPublicDatasets replaces the current ExpectedDOI slice with the DOIs of the given publicDatasets
 func (c *ExpectedCollection) SetPublicDatasets(publicDatasets ...dto.PublicDataset) *ExpectedCollection {
 	var newDOIs []ExpectedDOI
@@ -199,6 +235,8 @@ func (d ExpectedDOIs) AsDOIs() collections.DOIs {
 		strs[i] = collections.DOI{
 			Value:      doi.DOI,
 			Datasource: doi.Datasource,
+			Label:      doi.Label,
+			Note:       doi.Note,
 		}
 	}
 	return strs
@@ -242,11 +280,13 @@ func (c *ExpectedCollection) ToGetCollectionResponse(t require.TestingT, expecte
 		Tags:        c.Tags,
 		Size:        len(c.DOIs),
 		UserRole:    user.PermissionBit.ToRole(),
+		Version:     c.Version,
 	}
 	if expectedPublishStatus != nil {
 		collectionBase.Publication = &collections.Publication{
-			Status: expectedPublishStatus.Status,
-			Type:   expectedPublishStatus.Type,
+			Status:    expectedPublishStatus.Status,
+			Type:      expectedPublishStatus.Type,
+			StartedAt: expectedPublishStatus.StartedAt,
 		}
 	}
 	return collections.GetCollectionResponse{
@@ -275,6 +315,10 @@ func (c *ExpectedCollection) UpdateCollectionFunc(t require.TestingT) mocks.Upda
 		require.NotEqual(t, -1, userIdx, "given user %d has no permission for collection %d", userID, collectionID)
 		user := c.Users[userIdx]
 
+		if update.ExpectedVersion != nil && *update.ExpectedVersion != c.Version {
+			return collections.GetCollectionResponse{}, collections.ErrCollectionVersionMismatch
+		}
+
 		updatedName := c.Name
 		if update.Name != nil {
 			updatedName = *update.Name
@@ -309,6 +353,36 @@ func (c *ExpectedCollection) UpdateCollectionFunc(t require.TestingT) mocks.Upda
 
 		updatedDOIs = append(updatedDOIs, update.DOIs.Add...)
 
+		if update.DOIs.Order != nil {
+			if len(update.DOIs.Order) != len(updatedDOIs) {
+				return collections.GetCollectionResponse{}, collections.ErrDOIOrderMismatch
+			}
+			orderedDOIs := make([]collections.DOI, 0, len(updatedDOIs))
+			for _, doi := range update.DOIs.Order {
+				idx := slices.IndexFunc(updatedDOIs, func(d collections.DOI) bool {
+					return d.Value == doi
+				})
+				if idx == -1 {
+					return collections.GetCollectionResponse{}, collections.ErrDOIOrderMismatch
+				}
+				orderedDOIs = append(orderedDOIs, updatedDOIs[idx])
+			}
+			updatedDOIs = orderedDOIs
+		}
+
+		for _, annotation := range update.DOIs.Annotate {
+			idx := slices.IndexFunc(updatedDOIs, func(doi collections.DOI) bool {
+				return doi.Value == annotation.DOI
+			})
+			require.NotEqual(t, -1, idx, "annotated DOI %s not in collection %d", annotation.DOI, collectionID)
+			if annotation.Label != nil {
+				updatedDOIs[idx].Label = *annotation.Label
+			}
+			if annotation.Note != nil {
+				updatedDOIs[idx].Note = *annotation.Note
+			}
+		}
+
 		collectionBase := collections.CollectionBase{
 			NodeID:      *c.NodeID,
 			ID:          *c.ID,
@@ -318,6 +392,10 @@ func (c *ExpectedCollection) UpdateCollectionFunc(t require.TestingT) mocks.Upda
 			Tags:        updatedTags,
 			Size:        len(updatedDOIs),
 			UserRole:    user.PermissionBit.ToRole(),
+			Version:     c.Version,
+		}
+		if c.Version > 0 {
+			collectionBase.Version++
 		}
 		return collections.GetCollectionResponse{
 			CollectionBase: collectionBase,
@@ -439,7 +517,7 @@ func (c *ExpectedCollection) DatasetServiceRoleForUser(t require.TestingT, user
 }
 
 func (c *ExpectedCollection) StartPublishFunc(t require.TestingT, expectedUserID int64, expectedType publishing.Type) mocks.StartPublishFunc {
-	return func(_ context.Context, collectionID int64, userID int64, publishingType publishing.Type) error {
+	return func(_ context.Context, collectionID int64, userID int64, publishingType publishing.Type, _ *int64) error {
 		require.NotNil(t, c.ID, "expected collection does not have ID set")
 		require.Equal(t, *c.ID, collectionID)
 		require.Equal(t, expectedUserID, userID)
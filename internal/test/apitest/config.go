@@ -7,6 +7,7 @@ import (
 	sharedconfig "github.com/pennsieve/collections-service/internal/shared/config"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
 	"strconv"
+	"time"
 )
 
 const CollectionsIDSpaceID = int64(2222)
@@ -24,7 +25,11 @@ type ConfigBuilder struct {
 
 func NewConfigBuilder() *ConfigBuilder {
 	return &ConfigBuilder{c: &config.Config{
-		Environment: "test",
+		Environment:           "test",
+		PublishTimeout:        config.DefaultPublishTimeout,
+		PublishJobMaxAttempts: 3,
+		DiscoverCacheMode:     config.DefaultDiscoverCacheMode,
+		HTTPClients:           config.DefaultHTTPClientsConfig(),
 	}}
 }
 
@@ -43,6 +48,16 @@ func (b *ConfigBuilder) WithEnvironment(env string) *ConfigBuilder {
 	return b
 }
 
+func (b *ConfigBuilder) WithPublishTimeout(publishTimeout time.Duration) *ConfigBuilder {
+	b.c.PublishTimeout = publishTimeout
+	return b
+}
+
+func (b *ConfigBuilder) WithPublishJobMaxAttempts(maxAttempts int) *ConfigBuilder {
+	b.c.PublishJobMaxAttempts = maxAttempts
+	return b
+}
+
 func (b *ConfigBuilder) Build() config.Config {
 	return *b.c
 }
@@ -51,6 +66,7 @@ func PennsieveConfigWithOptions(opts ...config.PennsieveOption) config.Pennsieve
 	pennsieveConfig := config.NewPennsieveConfig(
 		config.WithDiscoverServiceURL("http://example.com/discover"),
 		config.WithDOIServiceURL("http://example.com/doi-service"),
+		config.WithDOIResolverURL("http://example.com/doi-resolver"),
 		config.WithDOIPrefix(PennsieveDOIPrefix),
 		config.WithJWTSecretKey(uuid.NewString()),
 		config.WithCollectionsIDSpace(CollectionsIDSpaceID, CollectionsIDSpaceName),
@@ -8,7 +8,9 @@ import (
 	"github.com/pennsieve/collections-service/internal/api/store/manifests"
 	"github.com/pennsieve/collections-service/internal/api/store/users"
 	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/shared/clients/queue"
 	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/shared/util"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/stretchr/testify/require"
 	"log/slog"
@@ -19,9 +21,11 @@ type TestContainer struct {
 	TestDiscover         service.Discover
 	TestInternalDiscover service.InternalDiscover
 	TestDOI              service.DOI
+	TestExternalDOI      service.ExternalDOI
 	TestCollectionsStore collections.Store
 	TestUsersStore       users.Store
 	TestManifestStore    manifests.Store
+	TestPublishQueue     queue.Queue
 	logger               *slog.Logger
 }
 
@@ -39,6 +43,11 @@ func (c *TestContainer) Discover() service.Discover {
 	return c.TestDiscover
 }
 
+// UncachedDiscover returns the same service.Discover as Discover, since nothing in a TestContainer caches.
+func (c *TestContainer) UncachedDiscover() service.Discover {
+	return c.Discover()
+}
+
 func (c *TestContainer) CollectionsStore() collections.Store {
 	if c.TestCollectionsStore == nil {
 		panic("no collections.Store set for this TestContainer")
@@ -60,6 +69,13 @@ func (c *TestContainer) DOI(_ context.Context) (service.DOI, error) {
 	return c.TestDOI, nil
 }
 
+func (c *TestContainer) ExternalDOI() service.ExternalDOI {
+	if c.TestExternalDOI == nil {
+		panic("no service.ExternalDOI set for this TestContainer")
+	}
+	return c.TestExternalDOI
+}
+
 func (c *TestContainer) UsersStore() users.Store {
 	if c.TestUsersStore == nil {
 		panic("no users.Store set for this TestContainer")
@@ -74,6 +90,13 @@ func (c *TestContainer) ManifestStore() manifests.Store {
 	return c.TestManifestStore
 }
 
+func (c *TestContainer) PublishQueue() queue.Queue {
+	if c.TestPublishQueue == nil {
+		panic("no queue.Queue set for this TestContainer")
+	}
+	return c.TestPublishQueue
+}
+
 func (c *TestContainer) Logger() *slog.Logger {
 	if c.logger == nil {
 		c.logger = logging.Default
@@ -103,8 +126,14 @@ func (c *TestContainer) WithDiscover(discover service.Discover) *TestContainer {
 	return c
 }
 
+// NewHTTPClient returns a util.HTTPClient for tests that call an httptest server. It makes a single attempt,
+// so that tests of error responses do not wait on retries.
+func NewHTTPClient(logger *slog.Logger) *util.HTTPClient {
+	return util.NewHTTPClient("test", util.DefaultHTTPClientConfig().WithMaxAttempts(1), logger)
+}
+
 func (c *TestContainer) WithHTTPTestDiscover(mockServerURL string) *TestContainer {
-	c.TestDiscover = service.NewHTTPDiscover(mockServerURL, c.Logger())
+	c.TestDiscover = service.NewHTTPDiscover(mockServerURL, NewHTTPClient(c.Logger()), c.Logger())
 	return c
 }
 
@@ -131,6 +160,7 @@ func (c *TestContainer) WithHTTPTestInternalDiscover(pennsieveConfig config.Penn
 		pennsieveConfig.DiscoverServiceURL,
 		*pennsieveConfig.JWTSecretKey.Value,
 		pennsieveConfig.CollectionsIDSpace.ID,
+		NewHTTPClient(c.Logger()),
 		c.Logger())
 	return c
 }
@@ -145,11 +175,22 @@ func (c *TestContainer) WithHTTPTestDOI(pennsieveConfig config.PennsieveConfig)
 		pennsieveConfig.DOIServiceURL,
 		*pennsieveConfig.JWTSecretKey.Value,
 		pennsieveConfig.CollectionsIDSpace.ID,
+		NewHTTPClient(c.Logger()),
 		c.Logger(),
 	)
 	return c
 }
 
+func (c *TestContainer) WithExternalDOI(externalDOI service.ExternalDOI) *TestContainer {
+	c.TestExternalDOI = externalDOI
+	return c
+}
+
+func (c *TestContainer) WithHTTPTestExternalDOI(mockServerURL string) *TestContainer {
+	c.TestExternalDOI = service.NewHTTPExternalDOI(mockServerURL, NewHTTPClient(c.Logger()), c.Logger())
+	return c
+}
+
 func (c *TestContainer) WithUsersStore(usersStore users.Store) *TestContainer {
 	c.TestUsersStore = usersStore
 	return c
@@ -173,3 +214,8 @@ func (c *TestContainer) WithMinIOManifestStore(ctx context.Context, t require.Te
 	c.TestManifestStore = manifests.NewS3Store(s3Client, publishBucket, c.Logger())
 	return c
 }
+
+func (c *TestContainer) WithPublishQueue(publishQueue queue.Queue) *TestContainer {
+	c.TestPublishQueue = publishQueue
+	return c
+}
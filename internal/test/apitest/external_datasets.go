@@ -0,0 +1,79 @@
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pennsieve/collections-service/internal/api/datasource"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/stretchr/testify/require"
+	"math/rand/v2"
+)
+
+// ExpectedExternalDatasets are the non-Pennsieve DOIs expected to be known to the DOI resolver for a given test.
+// Add DOIs with NewResolved or NewUnresolved.
+// Turn into a mocks.ResolveDOIsFunc with ResolveDOIsFunc
+type ExpectedExternalDatasets struct {
+	DOIToExternalDataset map[string]dto.ExternalDataset
+	UnresolvedDOIs       map[string]bool
+}
+
+func NewExpectedExternalDatasets() *ExpectedExternalDatasets {
+	return &ExpectedExternalDatasets{
+		DOIToExternalDataset: make(map[string]dto.ExternalDataset),
+		UnresolvedDOIs:       make(map[string]bool),
+	}
+}
+
+func NewExternalDataset(doi string) dto.ExternalDataset {
+	return dto.ExternalDataset{
+		DOI:             doi,
+		Title:           uuid.NewString(),
+		URL:             "https://example.com/" + uuid.NewString(),
+		Publisher:       uuid.NewString(),
+		PublicationYear: 2000 + rand.N(25),
+		Type:            "dataset",
+		Creators:        []string{uuid.NewString(), uuid.NewString()},
+	}
+}
+
+func (e *ExpectedExternalDatasets) NewResolved() dto.ExternalDataset {
+	externalDataset := NewExternalDataset(NewExternalDOI().Value)
+	e.DOIToExternalDataset[externalDataset.DOI] = externalDataset
+	return externalDataset
+}
+
+// NewUnresolved returns a new external DOI that the resolver will not know about.
+func (e *ExpectedExternalDatasets) NewUnresolved() string {
+	doi := NewExternalDOI().Value
+	e.UnresolvedDOIs[doi] = true
+	return doi
+}
+
+func (e *ExpectedExternalDatasets) ResolveDOIsFunc(t require.TestingT) mocks.ResolveDOIsFunc {
+	return func(ctx context.Context, dois []string) (service.ResolveDOIsResponse, error) {
+		test.Helper(t)
+		response := service.ResolveDOIsResponse{Resolved: map[string]dto.ExternalDataset{}}
+		for _, doi := range dois {
+			if externalDataset, resolved := e.DOIToExternalDataset[doi]; resolved {
+				response.Resolved[doi] = externalDataset
+			} else if e.UnresolvedDOIs[doi] {
+				response.Unresolved = append(response.Unresolved, doi)
+			} else {
+				require.FailNow(t, "requested DOI not found", "DOI %s is not expected as Resolved or Unresolved", doi)
+			}
+		}
+		return response, nil
+	}
+}
+
+// RequireAsExternalDataset will unmarshall actualDataset.Data into externalDataset if it can. If it cannot, it
+// will fail the test.
+func RequireAsExternalDataset(t require.TestingT, actualDataset dto.Dataset, externalDataset *dto.ExternalDataset) {
+	test.Helper(t)
+	require.Equal(t, datasource.External, actualDataset.Source)
+	require.NoError(t, json.Unmarshal(actualDataset.Data, externalDataset))
+}
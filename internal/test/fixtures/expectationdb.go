@@ -3,9 +3,12 @@ package fixtures
 import (
 	"context"
 	"github.com/jackc/pgx/v5"
+	"github.com/pennsieve/collections-service/internal/api/config"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
 	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/shared/util"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/apitest"
 	"github.com/pennsieve/collections-service/internal/test/userstest"
@@ -22,6 +25,8 @@ type ExpectationDB struct {
 	dbName                 string
 	internalStore          *collections.PostgresStore
 	createdUsers           map[int64]bool
+	createdTeams           map[int64]bool
+	createdOrganizations   map[int64]bool
 	knownCollectionIDs     map[int64]bool
 	knownCollectionNodeIDs map[string]bool
 }
@@ -31,6 +36,8 @@ func NewExpectationDB(db *test.PostgresDB, dbName string) *ExpectationDB {
 		db:                     db,
 		dbName:                 dbName,
 		createdUsers:           map[int64]bool{},
+		createdTeams:           map[int64]bool{},
+		createdOrganizations:   map[int64]bool{},
 		knownCollectionIDs:     map[int64]bool{},
 		knownCollectionNodeIDs: map[string]bool{},
 	}
@@ -43,7 +50,7 @@ func (e *ExpectationDB) collectionsStore() collections.Store {
 	return e.internalStore
 }
 
-func (e *ExpectationDB) connect(ctx context.Context, t require.TestingT) *pgx.Conn {
+func (e *ExpectationDB) connect(ctx context.Context, t require.TestingT) postgres.Conn {
 	test.Helper(t)
 	conn, err := e.db.Connect(ctx, e.dbName)
 	require.NoError(t, err)
@@ -73,6 +80,32 @@ func (e *ExpectationDB) RequireNoCollection(ctx context.Context, t require.Testi
 	require.ErrorIs(t, err, pgx.ErrNoRows, "expected no row, got %v", unexpected)
 }
 
+// RequireDeletedCollection requires that the given collection is in the trash with its contents intact.
+func (e *ExpectationDB) RequireDeletedCollection(ctx context.Context, t require.TestingT, expected *apitest.ExpectedCollection, expectedCollectionID int64) {
+	test.Helper(t)
+	e.knownCollectionIDs[expectedCollectionID] = true
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+
+	actual := GetCollection(ctx, t, conn, expectedCollectionID)
+	require.NotNil(t, actual.DeletedAt, "expected collection %d to be deleted", expectedCollectionID)
+	actual.DeletedAt = nil
+	requireCollection(ctx, t, conn, expected, actual)
+}
+
+// SetCollectionDeletedAt moves the given collection to the trash as if it were deleted at deletedAt.
+func (e *ExpectationDB) SetCollectionDeletedAt(ctx context.Context, t require.TestingT, collectionID int64, deletedAt time.Time) {
+	test.Helper(t)
+	e.knownCollectionIDs[collectionID] = true
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+
+	_, err := conn.Exec(ctx,
+		"UPDATE collections.collections SET deleted_at = @deleted_at WHERE id = @id",
+		pgx.NamedArgs{"id": collectionID, "deleted_at": deletedAt.UTC()})
+	require.NoError(t, err)
+}
+
 func (e *ExpectationDB) RequireCollectionByNodeID(ctx context.Context, t require.TestingT, expected *apitest.ExpectedCollection, expectedNodeID string) {
 	test.Helper(t)
 	e.knownCollectionNodeIDs[expectedNodeID] = true
@@ -97,9 +130,9 @@ func (e *ExpectationDB) RequirePublishStatus(ctx context.Context, t require.Test
 	if expected.Status == publishing.InProgressStatus {
 		require.Nil(t, actual.FinishedAt)
 		if preCondition != nil {
-			switch preCondition.Status {
+			switch {
 			// If we expected InProgress with an InProgress pre-condition, then we should expect that there are no changes to the pre-condition
-			case publishing.InProgressStatus:
+			case preCondition.Status == publishing.InProgressStatus && !replacedStalePublish(*preCondition, actual):
 				require.Equal(t, preCondition.UserID, expected.UserID)
 				requireTimeWithinEpsilon(t, preCondition.StartedAt, actual.StartedAt, time.Second)
 			default:
@@ -112,7 +145,7 @@ func (e *ExpectationDB) RequirePublishStatus(ctx context.Context, t require.Test
 		require.False(t, (*actual.FinishedAt).Before(actual.StartedAt))
 		if preCondition != nil {
 			// If the actual status is the completion of an in-progress precondition, then the start times should be the same.
-			if preCondition.Status == publishing.InProgressStatus && preCondition.Type == actual.Type {
+			if preCondition.Status == publishing.InProgressStatus && preCondition.Type == actual.Type && !replacedStalePublish(*preCondition, actual) {
 				requireTimeWithinEpsilon(t, preCondition.StartedAt, actual.StartedAt, time.Second)
 				// otherwise, the start time should be updated to a new value
 			} else {
@@ -122,6 +155,13 @@ func (e *ExpectationDB) RequirePublishStatus(ctx context.Context, t require.Test
 	}
 }
 
+// replacedStalePublish returns true if preCondition is a stale InProgress publish and actual is a newer publish that replaced it.
+func replacedStalePublish(preCondition, actual collections.PublishStatus) bool {
+	stale := preCondition.Status == publishing.InProgressStatus &&
+		preCondition.StartedAt.Before(time.Now().UTC().Add(-config.DefaultPublishTimeout))
+	return stale && actual.StartedAt.After(preCondition.StartedAt.Add(time.Second))
+}
+
 func (e *ExpectationDB) RequireNoPublishStatus(ctx context.Context, t require.TestingT, expectedCollectionID int64) {
 	test.Helper(t)
 	e.knownCollectionIDs[expectedCollectionID] = true
@@ -170,6 +210,40 @@ func (e *ExpectationDB) CreateTestUser(ctx context.Context, t require.TestingT,
 	e.createdUsers[*testUser.ID] = true
 }
 
+// CreateTestTeam creates a new team with the given users as members. The team is removed by CleanUp.
+func (e *ExpectationDB) CreateTestTeam(ctx context.Context, t require.TestingT, memberIDs ...int64) (teamID int64, teamNodeID string) {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+	teamID, teamNodeID = CreateTestTeam(ctx, t, conn, memberIDs...)
+	e.createdTeams[teamID] = true
+	return
+}
+
+// CreateTestOrganization creates a new organization with the given users as members. The organization is removed by CleanUp.
+func (e *ExpectationDB) CreateTestOrganization(ctx context.Context, t require.TestingT, memberIDToPermission map[int64]pgdb.DbPermission) (organizationID int64, organizationNodeID string) {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+	organizationID, organizationNodeID = CreateTestOrganization(ctx, t, conn, memberIDToPermission)
+	e.createdOrganizations[organizationID] = true
+	return
+}
+
+func (e *ExpectationDB) AddCollectionTeam(ctx context.Context, t require.TestingT, collectionID int64, teamID int64, permission pgdb.DbPermission) {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+	AddCollectionTeam(ctx, t, conn, collectionID, teamID, permission)
+}
+
+func (e *ExpectationDB) AddCollectionOrganization(ctx context.Context, t require.TestingT, collectionID int64, organizationID int64, permission pgdb.DbPermission) {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+	AddCollectionOrganization(ctx, t, conn, collectionID, organizationID, permission)
+}
+
 func (e *ExpectationDB) CreatePublishStatus(ctx context.Context, t require.TestingT, publishStatus collections.PublishStatus) {
 	test.Helper(t)
 	require.NotZero(t, publishStatus.CollectionID, "collectionID not set on publishStatus")
@@ -179,6 +253,23 @@ func (e *ExpectationDB) CreatePublishStatus(ctx context.Context, t require.Testi
 	AddPublishStatus(ctx, t, conn, publishStatus)
 }
 
+func (e *ExpectationDB) GetPublishStatus(ctx context.Context, t require.TestingT, collectionID int64) collections.PublishStatus {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+
+	return GetPublishStatus(ctx, t, conn, collectionID)
+}
+
+// GetCollectionEvents returns the events of the given collection, oldest first.
+func (e *ExpectationDB) GetCollectionEvents(ctx context.Context, t require.TestingT, collectionID int64) []collections.CollectionEvent {
+	test.Helper(t)
+	conn := e.connect(ctx, t)
+	defer test.CloseConnection(ctx, t, conn)
+
+	return GetCollectionEvents(ctx, t, conn, collectionID)
+}
+
 func (e *ExpectationDB) CleanUp(ctx context.Context, t require.TestingT) {
 	test.Helper(t)
 	conn := e.connect(ctx, t)
@@ -202,6 +293,22 @@ func (e *ExpectationDB) CleanUp(ctx context.Context, t require.TestingT) {
 		require.NoError(t, err, "error deleting collections by node id in CleanUp")
 	}
 
+	if len(e.createdTeams) > 0 {
+		teamIDs := slices.AppendSeq([]int64{}, maps.Keys(e.createdTeams))
+		_, err := conn.Exec(ctx, "DELETE FROM pennsieve.team_user WHERE team_id = ANY(@team_ids)", pgx.NamedArgs{"team_ids": teamIDs})
+		require.NoError(t, err, "error deleting test team members in CleanUp")
+		_, err = conn.Exec(ctx, "DELETE FROM pennsieve.teams WHERE id = ANY(@team_ids)", pgx.NamedArgs{"team_ids": teamIDs})
+		require.NoError(t, err, "error deleting test teams in CleanUp")
+	}
+
+	if len(e.createdOrganizations) > 0 {
+		organizationIDs := slices.AppendSeq([]int64{}, maps.Keys(e.createdOrganizations))
+		_, err := conn.Exec(ctx, "DELETE FROM pennsieve.organization_user WHERE organization_id = ANY(@organization_ids)", pgx.NamedArgs{"organization_ids": organizationIDs})
+		require.NoError(t, err, "error deleting test organization members in CleanUp")
+		_, err = conn.Exec(ctx, "DELETE FROM pennsieve.organizations WHERE id = ANY(@organization_ids)", pgx.NamedArgs{"organization_ids": organizationIDs})
+		require.NoError(t, err, "error deleting test organizations in CleanUp")
+	}
+
 	if len(e.createdUsers) > 0 {
 		_, err := conn.Exec(
 			ctx,
@@ -212,7 +319,7 @@ func (e *ExpectationDB) CleanUp(ctx context.Context, t require.TestingT) {
 	}
 }
 
-func requireCollection(ctx context.Context, t require.TestingT, conn *pgx.Conn, expected *apitest.ExpectedCollection, actual collections.Collection) {
+func requireCollection(ctx context.Context, t require.TestingT, conn postgres.Conn, expected *apitest.ExpectedCollection, actual collections.Collection) {
 	require.Equal(t, expected.Name, actual.Name)
 	require.Equal(t, expected.Description, actual.Description)
 	if expected.NodeID != nil {
@@ -222,6 +329,7 @@ func requireCollection(ctx context.Context, t require.TestingT, conn *pgx.Conn,
 	require.NotZero(t, actual.UpdatedAt)
 	require.Equal(t, expected.License, actual.License)
 	require.Equal(t, expected.Tags, actual.Tags)
+	require.Nil(t, actual.DeletedAt, "expected collection %d not to be deleted", actual.ID)
 
 	actualUsers := GetCollectionUsers(ctx, t, conn, actual.ID)
 	require.Len(t, actualUsers, len(expected.Users))
@@ -241,6 +349,8 @@ func requireCollection(ctx context.Context, t require.TestingT, conn *pgx.Conn,
 		actualDOI := actualDOIs[expectedDOI.DOI]
 		require.Equal(t, expectedDOI.DOI, actualDOI.DOI)
 		require.Equal(t, expectedDOI.Datasource, actualDOI.Datasource)
+		require.Equal(t, expectedDOI.Label, util.SafeDeref(actualDOI.Label))
+		require.Equal(t, expectedDOI.Note, util.SafeDeref(actualDOI.Note))
 		require.NotZero(t, actualDOI.CreatedAt)
 		require.NotZero(t, actualDOI.UpdatedAt)
 	}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
 	"github.com/pennsieve/collections-service/internal/test"
 	"github.com/pennsieve/collections-service/internal/test/userstest"
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
@@ -13,7 +15,7 @@ import (
 	"strings"
 )
 
-func GetCollection(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64) collections.Collection {
+func GetCollection(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64) collections.Collection {
 	test.Helper(t)
 	rows, err := conn.Query(ctx, "SELECT * from collections.collections where id = @id", pgx.NamedArgs{"id": collectionID})
 	require.NoError(t, err)
@@ -29,7 +31,7 @@ func GetCollection(ctx context.Context, t require.TestingT, conn *pgx.Conn, coll
 	}
 	return collection
 }
-func GetCollectionByNodeID(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionNodeID string) collections.Collection {
+func GetCollectionByNodeID(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionNodeID string) collections.Collection {
 	test.Helper(t)
 	rows, err := conn.Query(ctx, "SELECT * from collections.collections where node_id = @nodeId", pgx.NamedArgs{"nodeId": collectionNodeID})
 	require.NoError(t, err)
@@ -47,7 +49,7 @@ func GetCollectionByNodeID(ctx context.Context, t require.TestingT, conn *pgx.Co
 
 }
 
-func GetCollectionUsers(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64) (userIDToCollectionUser map[int64]collections.CollectionUser) {
+func GetCollectionUsers(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64) (userIDToCollectionUser map[int64]collections.CollectionUser) {
 	test.Helper(t)
 	rows, err := conn.Query(ctx,
 		"SELECT * FROM collections.collection_user WHERE collection_id = @collection_id",
@@ -62,7 +64,7 @@ func GetCollectionUsers(ctx context.Context, t require.TestingT, conn *pgx.Conn,
 	return
 }
 
-func AddCollectionUser(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64, userID int64, permission pgdb.DbPermission) {
+func AddCollectionUser(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64, userID int64, permission pgdb.DbPermission) {
 	test.Helper(t)
 	args := pgx.NamedArgs{
 		"collection_id":  collectionID,
@@ -78,7 +80,7 @@ func AddCollectionUser(ctx context.Context, t require.TestingT, conn *pgx.Conn,
 	require.Equal(t, int64(1), tag.RowsAffected())
 }
 
-func AddCollectionUsers(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64, userIDToPermission map[int64]pgdb.DbPermission) {
+func AddCollectionUsers(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64, userIDToPermission map[int64]pgdb.DbPermission) {
 	test.Helper(t)
 	require.NotEmpty(t, userIDToPermission)
 	collectionKey := "collection_id"
@@ -102,7 +104,39 @@ func AddCollectionUsers(ctx context.Context, t require.TestingT, conn *pgx.Conn,
 	require.Equal(t, int64(len(userIDToPermission)), tag.RowsAffected(), "expected to add %d users, but added %d", len(userIDToPermission), tag.RowsAffected())
 }
 
-func GetDOIs(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64) (doiToDOI map[string]collections.CollectionDOI) {
+func AddCollectionTeam(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64, teamID int64, permission pgdb.DbPermission) {
+	test.Helper(t)
+	args := pgx.NamedArgs{
+		"collection_id":  collectionID,
+		"team_id":        teamID,
+		"permission_bit": permission,
+		"role":           collections.PgxRole(permission.ToRole()),
+	}
+	tag, err := conn.Exec(ctx,
+		`INSERT INTO collections.collection_team (collection_id, team_id, permission_bit, role)
+											  VALUES (@collection_id, @team_id, @permission_bit, @role)`,
+		args)
+	require.NoError(t, err, "error adding team %d to collection %d", teamID, collectionID)
+	require.Equal(t, int64(1), tag.RowsAffected())
+}
+
+func AddCollectionOrganization(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64, organizationID int64, permission pgdb.DbPermission) {
+	test.Helper(t)
+	args := pgx.NamedArgs{
+		"collection_id":   collectionID,
+		"organization_id": organizationID,
+		"permission_bit":  permission,
+		"role":            collections.PgxRole(permission.ToRole()),
+	}
+	tag, err := conn.Exec(ctx,
+		`INSERT INTO collections.collection_organization (collection_id, organization_id, permission_bit, role)
+											  VALUES (@collection_id, @organization_id, @permission_bit, @role)`,
+		args)
+	require.NoError(t, err, "error adding organization %d to collection %d", organizationID, collectionID)
+	require.Equal(t, int64(1), tag.RowsAffected())
+}
+
+func GetDOIs(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64) (doiToDOI map[string]collections.CollectionDOI) {
 	test.Helper(t)
 	rows, err := conn.Query(ctx,
 		"SELECT * FROM collections.dois WHERE collection_id = @collection_id",
@@ -117,7 +151,7 @@ func GetDOIs(ctx context.Context, t require.TestingT, conn *pgx.Conn, collection
 	return
 }
 
-func CreateTestUser(ctx context.Context, t require.TestingT, conn *pgx.Conn, testUser *userstest.TestUser) {
+func CreateTestUser(ctx context.Context, t require.TestingT, conn postgres.Conn, testUser *userstest.TestUser) {
 	test.Helper(t)
 	require.Nil(t, testUser.ID, "cannot create new user from TestUser: id already set")
 
@@ -160,7 +194,55 @@ func CreateTestUser(ctx context.Context, t require.TestingT, conn *pgx.Conn, tes
 	testUser.ID = &returnedID
 }
 
-func AddPublishStatus(ctx context.Context, t require.TestingT, conn *pgx.Conn, status collections.PublishStatus) {
+// CreateTestTeam inserts a new team with the given users as members and returns its id and node id.
+func CreateTestTeam(ctx context.Context, t require.TestingT, conn postgres.Conn, memberIDs ...int64) (teamID int64, teamNodeID string) {
+	test.Helper(t)
+	teamNodeID = fmt.Sprintf("N:team:%s", uuid.NewString())
+	require.NoError(t, pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO pennsieve.teams (name, node_id) VALUES (@name, @node_id) RETURNING id`,
+			pgx.NamedArgs{"name": uuid.NewString(), "node_id": teamNodeID},
+		).Scan(&teamID); err != nil {
+			return err
+		}
+		for _, memberID := range memberIDs {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO pennsieve.team_user (team_id, user_id, permission_bit) VALUES (@team_id, @user_id, @permission_bit)`,
+				pgx.NamedArgs{"team_id": teamID, "user_id": memberID, "permission_bit": pgdb.Delete},
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return
+}
+
+// CreateTestOrganization inserts a new organization with the given users as members and returns its id and node id.
+func CreateTestOrganization(ctx context.Context, t require.TestingT, conn postgres.Conn, memberIDToPermission map[int64]pgdb.DbPermission) (organizationID int64, organizationNodeID string) {
+	test.Helper(t)
+	organizationNodeID = fmt.Sprintf("N:organization:%s", uuid.NewString())
+	require.NoError(t, pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO pennsieve.organizations (name, slug, node_id, encryption_key_id) VALUES (@name, @slug, @node_id, @encryption_key_id) RETURNING id`,
+			pgx.NamedArgs{"name": uuid.NewString(), "slug": uuid.NewString(), "node_id": organizationNodeID, "encryption_key_id": "NO_ENCRYPTION_KEY"},
+		).Scan(&organizationID); err != nil {
+			return err
+		}
+		for memberID, permission := range memberIDToPermission {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO pennsieve.organization_user (organization_id, user_id, permission_bit) VALUES (@organization_id, @user_id, @permission_bit)`,
+				pgx.NamedArgs{"organization_id": organizationID, "user_id": memberID, "permission_bit": permission},
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return
+}
+
+func AddPublishStatus(ctx context.Context, t require.TestingT, conn postgres.Conn, status collections.PublishStatus) {
 	query := `INSERT INTO collections.publish_status (collection_id, status, type, started_at, finished_at, user_id) 
                                               VALUES (@collection_id, @status, @type, @started_at, @finished_at, @user_id)`
 	args := pgx.NamedArgs{
@@ -176,7 +258,7 @@ func AddPublishStatus(ctx context.Context, t require.TestingT, conn *pgx.Conn, s
 	require.Equal(t, int64(1), tag.RowsAffected())
 }
 
-func GetPublishStatus(ctx context.Context, t require.TestingT, conn *pgx.Conn, collectionID int64) collections.PublishStatus {
+func GetPublishStatus(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64) collections.PublishStatus {
 	query := `SELECT collection_id, status, type, started_at, finished_at, user_id
                 FROM collections.publish_status WHERE collection_id = @collection_id`
 	args := pgx.NamedArgs{"collection_id": collectionID}
@@ -194,3 +276,15 @@ func GetPublishStatus(ctx context.Context, t require.TestingT, conn *pgx.Conn, c
 	}
 	return publishStatus
 }
+
+// GetCollectionEvents returns the events of the given collection in the order they were recorded.
+func GetCollectionEvents(ctx context.Context, t require.TestingT, conn postgres.Conn, collectionID int64) []collections.CollectionEvent {
+	test.Helper(t)
+	rows, err := conn.Query(ctx,
+		"SELECT * FROM collections.collection_events WHERE collection_id = @collection_id ORDER BY id",
+		pgx.NamedArgs{"collection_id": collectionID})
+	require.NoError(t, err)
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[collections.CollectionEvent])
+	require.NoError(t, err)
+	return events
+}
@@ -4,22 +4,78 @@ import (
 	"context"
 	"github.com/pennsieve/collections-service/internal/api/publishing"
 	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
+	"time"
 )
 
 type CreateCollectionsFunc func(ctx context.Context, request collections.CreateCollectionRequest) (collections.CreateCollectionResponse, error)
 
-type GetCollectionsFunc func(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error)
+type GetCollectionsFunc func(ctx context.Context, userID int64, limit int, offset int, query collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error)
 
 type GetCollectionFunc func(ctx context.Context, userID int64, nodeID string) (collections.GetCollectionResponse, error)
 
-type DeleteCollectionFunc func(ctx context.Context, collectionID int64) error
+type DeleteCollectionFunc func(ctx context.Context, collectionID int64, actorID int64, expectedVersion *int64) error
 
 type UpdateCollectionFunc func(ctx context.Context, userID int64, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error)
 
-type StartPublishFunc func(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type) error
+type StartPublishFunc func(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type, expectedVersion *int64) error
 
 type FinishPublishFunc func(ctx context.Context, collectionID int64, publishingStatus publishing.Status, strict bool) error
 
+type GetCollectionMembersFunc func(ctx context.Context, collectionID int64) ([]collections.CollectionMember, error)
+
+type PutCollectionMemberFunc func(ctx context.Context, collectionID int64, actorID int64, userNodeID string, memberRole role.Role) (collections.CollectionMember, error)
+
+type DeleteCollectionMemberFunc func(ctx context.Context, collectionID int64, actorID int64, userNodeID string) error
+
+type TransferOwnershipFunc func(ctx context.Context, collectionID int64, currentOwnerID int64, newOwnerNodeID string) error
+
+type GetCollectionGrantsFunc func(ctx context.Context, collectionID int64) ([]collections.CollectionGrant, error)
+
+type PutCollectionGrantFunc func(ctx context.Context, collectionID int64, actorID int64, granteeType collections.GranteeType, granteeNodeID string, grantRole role.Role) (collections.CollectionGrant, error)
+
+type DeleteCollectionGrantFunc func(ctx context.Context, collectionID int64, actorID int64, granteeType collections.GranteeType, granteeNodeID string) error
+
+type GetDeletedCollectionsFunc func(ctx context.Context, userID int64, minRole role.Role, limit int, offset int) (collections.GetDeletedCollectionsResponse, error)
+
+type GetDeletedCollectionFunc func(ctx context.Context, userID int64, nodeID string) (collections.DeletedCollection, error)
+
+type RestoreCollectionFunc func(ctx context.Context, collectionID int64, actorID int64) error
+
+type PurgeDeletedCollectionsFunc func(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+type ReorderDOIsFunc func(ctx context.Context, userID int64, collectionID int64, dois []string) (collections.GetCollectionResponse, error)
+
+type CreateSectionFunc func(ctx context.Context, collectionID int64, actorID int64, request collections.CreateSectionRequest) (collections.Section, error)
+
+type UpdateSectionFunc func(ctx context.Context, collectionID int64, actorID int64, sectionID int64, update collections.UpdateSectionRequest) (collections.Section, error)
+
+type DeleteSectionFunc func(ctx context.Context, collectionID int64, actorID int64, sectionID int64) error
+
+type GetCollectionEventsFunc func(ctx context.Context, collectionID int64, limit int, offset int) (collections.GetCollectionEventsResponse, error)
+
+type CreateSnapshotFunc func(ctx context.Context, collectionID int64, userID int64, name string) (collections.Snapshot, error)
+
+type GetSnapshotsFunc func(ctx context.Context, collectionID int64, limit int, offset int) (collections.GetSnapshotsResponse, error)
+
+type GetSnapshotFunc func(ctx context.Context, collectionID, snapshotID int64) (collections.Snapshot, error)
+
+type GetStalePublishesFunc func(ctx context.Context, startedBefore time.Time) ([]collections.StalePublish, error)
+
+type FinishStalePublishFunc func(ctx context.Context, stale collections.StalePublish, publishingStatus publishing.Status) (bool, error)
+
+type CreatePublishJobFunc func(ctx context.Context, collectionID int64, userID int64) (collections.PublishJob, error)
+
+type GetPublishJobFunc func(ctx context.Context, jobID string) (collections.PublishJob, error)
+
+type GetLatestPublishJobFunc func(ctx context.Context, collectionID int64) (collections.PublishJob, error)
+
+type StartPublishJobAttemptFunc func(ctx context.Context, jobID string) (collections.PublishJob, error)
+
+type UpdatePublishJobStepFunc func(ctx context.Context, jobID string, step publishing.JobStep) error
+
+type FinishPublishJobAttemptFunc func(ctx context.Context, jobID string, status publishing.JobStatus, result collections.PublishJobResult) error
+
 type CollectionsStore struct {
 	CreateCollectionsFunc
 	GetCollectionsFunc
@@ -28,6 +84,33 @@ type CollectionsStore struct {
 	UpdateCollectionFunc
 	StartPublishFunc
 	FinishPublishFunc
+	GetCollectionMembersFunc
+	PutCollectionMemberFunc
+	DeleteCollectionMemberFunc
+	TransferOwnershipFunc
+	GetCollectionGrantsFunc
+	PutCollectionGrantFunc
+	DeleteCollectionGrantFunc
+	GetDeletedCollectionsFunc
+	GetDeletedCollectionFunc
+	RestoreCollectionFunc
+	PurgeDeletedCollectionsFunc
+	ReorderDOIsFunc
+	CreateSectionFunc
+	UpdateSectionFunc
+	DeleteSectionFunc
+	GetCollectionEventsFunc
+	CreateSnapshotFunc
+	GetSnapshotsFunc
+	GetSnapshotFunc
+	GetStalePublishesFunc
+	FinishStalePublishFunc
+	CreatePublishJobFunc
+	GetPublishJobFunc
+	GetLatestPublishJobFunc
+	StartPublishJobAttemptFunc
+	UpdatePublishJobStepFunc
+	FinishPublishJobAttemptFunc
 }
 
 func NewCollectionsStore() *CollectionsStore {
@@ -69,6 +152,141 @@ func (c *CollectionsStore) WithFinishPublishFunc(f FinishPublishFunc) *Collectio
 	return c
 }
 
+func (c *CollectionsStore) WithGetCollectionMembersFunc(f GetCollectionMembersFunc) *CollectionsStore {
+	c.GetCollectionMembersFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithPutCollectionMemberFunc(f PutCollectionMemberFunc) *CollectionsStore {
+	c.PutCollectionMemberFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithDeleteCollectionMemberFunc(f DeleteCollectionMemberFunc) *CollectionsStore {
+	c.DeleteCollectionMemberFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithTransferOwnershipFunc(f TransferOwnershipFunc) *CollectionsStore {
+	c.TransferOwnershipFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetCollectionGrantsFunc(f GetCollectionGrantsFunc) *CollectionsStore {
+	c.GetCollectionGrantsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithPutCollectionGrantFunc(f PutCollectionGrantFunc) *CollectionsStore {
+	c.PutCollectionGrantFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithDeleteCollectionGrantFunc(f DeleteCollectionGrantFunc) *CollectionsStore {
+	c.DeleteCollectionGrantFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetDeletedCollectionsFunc(f GetDeletedCollectionsFunc) *CollectionsStore {
+	c.GetDeletedCollectionsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetDeletedCollectionFunc(f GetDeletedCollectionFunc) *CollectionsStore {
+	c.GetDeletedCollectionFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithRestoreCollectionFunc(f RestoreCollectionFunc) *CollectionsStore {
+	c.RestoreCollectionFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithPurgeDeletedCollectionsFunc(f PurgeDeletedCollectionsFunc) *CollectionsStore {
+	c.PurgeDeletedCollectionsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithReorderDOIsFunc(f ReorderDOIsFunc) *CollectionsStore {
+	c.ReorderDOIsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithCreateSectionFunc(f CreateSectionFunc) *CollectionsStore {
+	c.CreateSectionFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithUpdateSectionFunc(f UpdateSectionFunc) *CollectionsStore {
+	c.UpdateSectionFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithDeleteSectionFunc(f DeleteSectionFunc) *CollectionsStore {
+	c.DeleteSectionFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetCollectionEventsFunc(f GetCollectionEventsFunc) *CollectionsStore {
+	c.GetCollectionEventsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithCreateSnapshotFunc(f CreateSnapshotFunc) *CollectionsStore {
+	c.CreateSnapshotFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetSnapshotsFunc(f GetSnapshotsFunc) *CollectionsStore {
+	c.GetSnapshotsFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetSnapshotFunc(f GetSnapshotFunc) *CollectionsStore {
+	c.GetSnapshotFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetStalePublishesFunc(f GetStalePublishesFunc) *CollectionsStore {
+	c.GetStalePublishesFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithFinishStalePublishFunc(f FinishStalePublishFunc) *CollectionsStore {
+	c.FinishStalePublishFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithCreatePublishJobFunc(f CreatePublishJobFunc) *CollectionsStore {
+	c.CreatePublishJobFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetPublishJobFunc(f GetPublishJobFunc) *CollectionsStore {
+	c.GetPublishJobFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithGetLatestPublishJobFunc(f GetLatestPublishJobFunc) *CollectionsStore {
+	c.GetLatestPublishJobFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithStartPublishJobAttemptFunc(f StartPublishJobAttemptFunc) *CollectionsStore {
+	c.StartPublishJobAttemptFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithUpdatePublishJobStepFunc(f UpdatePublishJobStepFunc) *CollectionsStore {
+	c.UpdatePublishJobStepFunc = f
+	return c
+}
+
+func (c *CollectionsStore) WithFinishPublishJobAttemptFunc(f FinishPublishJobAttemptFunc) *CollectionsStore {
+	c.FinishPublishJobAttemptFunc = f
+	return c
+}
+
 func (c *CollectionsStore) CreateCollection(ctx context.Context, request collections.CreateCollectionRequest) (collections.CreateCollectionResponse, error) {
 	if c.CreateCollectionsFunc == nil {
 		panic("mock CreateCollections function not set")
@@ -76,11 +294,11 @@ func (c *CollectionsStore) CreateCollection(ctx context.Context, request collect
 	return c.CreateCollectionsFunc(ctx, request)
 }
 
-func (c *CollectionsStore) GetCollections(ctx context.Context, userID int64, limit int, offset int) (collections.GetCollectionsResponse, error) {
+func (c *CollectionsStore) GetCollections(ctx context.Context, userID int64, limit int, offset int, query collections.GetCollectionsQuery) (collections.GetCollectionsResponse, error) {
 	if c.GetCollectionsFunc == nil {
 		panic("mock GetCollections function not set")
 	}
-	return c.GetCollectionsFunc(ctx, userID, limit, offset)
+	return c.GetCollectionsFunc(ctx, userID, limit, offset, query)
 }
 
 func (c *CollectionsStore) GetCollection(ctx context.Context, userID int64, nodeID string) (collections.GetCollectionResponse, error) {
@@ -90,11 +308,11 @@ func (c *CollectionsStore) GetCollection(ctx context.Context, userID int64, node
 	return c.GetCollectionFunc(ctx, userID, nodeID)
 }
 
-func (c *CollectionsStore) DeleteCollection(ctx context.Context, collectionID int64) error {
+func (c *CollectionsStore) DeleteCollection(ctx context.Context, collectionID int64, actorID int64, expectedVersion *int64) error {
 	if c.DeleteCollectionFunc == nil {
 		panic("mock DeleteCollection function not set")
 	}
-	return c.DeleteCollectionFunc(ctx, collectionID)
+	return c.DeleteCollectionFunc(ctx, collectionID, actorID, expectedVersion)
 }
 
 func (c *CollectionsStore) UpdateCollection(ctx context.Context, userID, collectionID int64, update collections.UpdateCollectionRequest) (collections.GetCollectionResponse, error) {
@@ -104,11 +322,11 @@ func (c *CollectionsStore) UpdateCollection(ctx context.Context, userID, collect
 	return c.UpdateCollectionFunc(ctx, userID, collectionID, update)
 }
 
-func (c *CollectionsStore) StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type) error {
+func (c *CollectionsStore) StartPublish(ctx context.Context, collectionID int64, userID int64, publishingType publishing.Type, expectedVersion *int64) error {
 	if c.StartPublishFunc == nil {
 		panic("mock StartPublish function not set")
 	}
-	return c.StartPublishFunc(ctx, collectionID, userID, publishingType)
+	return c.StartPublishFunc(ctx, collectionID, userID, publishingType, expectedVersion)
 }
 
 func (c *CollectionsStore) FinishPublish(ctx context.Context, collectionID int64, publishingStatus publishing.Status, strict bool) error {
@@ -117,3 +335,192 @@ func (c *CollectionsStore) FinishPublish(ctx context.Context, collectionID int64
 	}
 	return c.FinishPublishFunc(ctx, collectionID, publishingStatus, strict)
 }
+
+func (c *CollectionsStore) GetCollectionMembers(ctx context.Context, collectionID int64) ([]collections.CollectionMember, error) {
+	if c.GetCollectionMembersFunc == nil {
+		panic("mock GetCollectionMembers function not set")
+	}
+	return c.GetCollectionMembersFunc(ctx, collectionID)
+}
+
+func (c *CollectionsStore) PutCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string, memberRole role.Role) (collections.CollectionMember, error) {
+	if c.PutCollectionMemberFunc == nil {
+		panic("mock PutCollectionMember function not set")
+	}
+	return c.PutCollectionMemberFunc(ctx, collectionID, actorID, userNodeID, memberRole)
+}
+
+func (c *CollectionsStore) DeleteCollectionMember(ctx context.Context, collectionID int64, actorID int64, userNodeID string) error {
+	if c.DeleteCollectionMemberFunc == nil {
+		panic("mock DeleteCollectionMember function not set")
+	}
+	return c.DeleteCollectionMemberFunc(ctx, collectionID, actorID, userNodeID)
+}
+
+func (c *CollectionsStore) TransferOwnership(ctx context.Context, collectionID int64, currentOwnerID int64, newOwnerNodeID string) error {
+	if c.TransferOwnershipFunc == nil {
+		panic("mock TransferOwnership function not set")
+	}
+	return c.TransferOwnershipFunc(ctx, collectionID, currentOwnerID, newOwnerNodeID)
+}
+
+func (c *CollectionsStore) GetCollectionGrants(ctx context.Context, collectionID int64) ([]collections.CollectionGrant, error) {
+	if c.GetCollectionGrantsFunc == nil {
+		panic("mock GetCollectionGrants function not set")
+	}
+	return c.GetCollectionGrantsFunc(ctx, collectionID)
+}
+
+func (c *CollectionsStore) PutCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType collections.GranteeType, granteeNodeID string, grantRole role.Role) (collections.CollectionGrant, error) {
+	if c.PutCollectionGrantFunc == nil {
+		panic("mock PutCollectionGrant function not set")
+	}
+	return c.PutCollectionGrantFunc(ctx, collectionID, actorID, granteeType, granteeNodeID, grantRole)
+}
+
+func (c *CollectionsStore) DeleteCollectionGrant(ctx context.Context, collectionID int64, actorID int64, granteeType collections.GranteeType, granteeNodeID string) error {
+	if c.DeleteCollectionGrantFunc == nil {
+		panic("mock DeleteCollectionGrant function not set")
+	}
+	return c.DeleteCollectionGrantFunc(ctx, collectionID, actorID, granteeType, granteeNodeID)
+}
+
+func (c *CollectionsStore) GetDeletedCollections(ctx context.Context, userID int64, minRole role.Role, limit int, offset int) (collections.GetDeletedCollectionsResponse, error) {
+	if c.GetDeletedCollectionsFunc == nil {
+		panic("mock GetDeletedCollections function not set")
+	}
+	return c.GetDeletedCollectionsFunc(ctx, userID, minRole, limit, offset)
+}
+
+func (c *CollectionsStore) GetDeletedCollection(ctx context.Context, userID int64, nodeID string) (collections.DeletedCollection, error) {
+	if c.GetDeletedCollectionFunc == nil {
+		panic("mock GetDeletedCollection function not set")
+	}
+	return c.GetDeletedCollectionFunc(ctx, userID, nodeID)
+}
+
+func (c *CollectionsStore) RestoreCollection(ctx context.Context, collectionID int64, actorID int64) error {
+	if c.RestoreCollectionFunc == nil {
+		panic("mock RestoreCollection function not set")
+	}
+	return c.RestoreCollectionFunc(ctx, collectionID, actorID)
+}
+
+func (c *CollectionsStore) PurgeDeletedCollections(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if c.PurgeDeletedCollectionsFunc == nil {
+		panic("mock PurgeDeletedCollections function not set")
+	}
+	return c.PurgeDeletedCollectionsFunc(ctx, deletedBefore)
+}
+
+func (c *CollectionsStore) ReorderDOIs(ctx context.Context, userID int64, collectionID int64, dois []string) (collections.GetCollectionResponse, error) {
+	if c.ReorderDOIsFunc == nil {
+		panic("mock ReorderDOIs function not set")
+	}
+	return c.ReorderDOIsFunc(ctx, userID, collectionID, dois)
+}
+
+func (c *CollectionsStore) CreateSection(ctx context.Context, collectionID int64, actorID int64, request collections.CreateSectionRequest) (collections.Section, error) {
+	if c.CreateSectionFunc == nil {
+		panic("mock CreateSection function not set")
+	}
+	return c.CreateSectionFunc(ctx, collectionID, actorID, request)
+}
+
+func (c *CollectionsStore) UpdateSection(ctx context.Context, collectionID int64, actorID int64, sectionID int64, update collections.UpdateSectionRequest) (collections.Section, error) {
+	if c.UpdateSectionFunc == nil {
+		panic("mock UpdateSection function not set")
+	}
+	return c.UpdateSectionFunc(ctx, collectionID, actorID, sectionID, update)
+}
+
+func (c *CollectionsStore) DeleteSection(ctx context.Context, collectionID int64, actorID int64, sectionID int64) error {
+	if c.DeleteSectionFunc == nil {
+		panic("mock DeleteSection function not set")
+	}
+	return c.DeleteSectionFunc(ctx, collectionID, actorID, sectionID)
+}
+
+func (c *CollectionsStore) GetCollectionEvents(ctx context.Context, collectionID int64, limit int, offset int) (collections.GetCollectionEventsResponse, error) {
+	if c.GetCollectionEventsFunc == nil {
+		panic("mock GetCollectionEvents function not set")
+	}
+	return c.GetCollectionEventsFunc(ctx, collectionID, limit, offset)
+}
+
+func (c *CollectionsStore) CreateSnapshot(ctx context.Context, collectionID int64, userID int64, name string) (collections.Snapshot, error) {
+	if c.CreateSnapshotFunc == nil {
+		panic("mock CreateSnapshot function not set")
+	}
+	return c.CreateSnapshotFunc(ctx, collectionID, userID, name)
+}
+
+func (c *CollectionsStore) GetSnapshots(ctx context.Context, collectionID int64, limit int, offset int) (collections.GetSnapshotsResponse, error) {
+	if c.GetSnapshotsFunc == nil {
+		panic("mock GetSnapshots function not set")
+	}
+	return c.GetSnapshotsFunc(ctx, collectionID, limit, offset)
+}
+
+func (c *CollectionsStore) GetSnapshot(ctx context.Context, collectionID, snapshotID int64) (collections.Snapshot, error) {
+	if c.GetSnapshotFunc == nil {
+		panic("mock GetSnapshot function not set")
+	}
+	return c.GetSnapshotFunc(ctx, collectionID, snapshotID)
+}
+
+func (c *CollectionsStore) GetStalePublishes(ctx context.Context, startedBefore time.Time) ([]collections.StalePublish, error) {
+	if c.GetStalePublishesFunc == nil {
+		panic("mock GetStalePublishes function not set")
+	}
+	return c.GetStalePublishesFunc(ctx, startedBefore)
+}
+
+func (c *CollectionsStore) FinishStalePublish(ctx context.Context, stale collections.StalePublish, publishingStatus publishing.Status) (bool, error) {
+	if c.FinishStalePublishFunc == nil {
+		panic("mock FinishStalePublish function not set")
+	}
+	return c.FinishStalePublishFunc(ctx, stale, publishingStatus)
+}
+
+func (c *CollectionsStore) CreatePublishJob(ctx context.Context, collectionID int64, userID int64) (collections.PublishJob, error) {
+	if c.CreatePublishJobFunc == nil {
+		panic("mock CreatePublishJob function not set")
+	}
+	return c.CreatePublishJobFunc(ctx, collectionID, userID)
+}
+
+func (c *CollectionsStore) GetPublishJob(ctx context.Context, jobID string) (collections.PublishJob, error) {
+	if c.GetPublishJobFunc == nil {
+		panic("mock GetPublishJob function not set")
+	}
+	return c.GetPublishJobFunc(ctx, jobID)
+}
+
+func (c *CollectionsStore) GetLatestPublishJob(ctx context.Context, collectionID int64) (collections.PublishJob, error) {
+	if c.GetLatestPublishJobFunc == nil {
+		panic("mock GetLatestPublishJob function not set")
+	}
+	return c.GetLatestPublishJobFunc(ctx, collectionID)
+}
+
+func (c *CollectionsStore) StartPublishJobAttempt(ctx context.Context, jobID string) (collections.PublishJob, error) {
+	if c.StartPublishJobAttemptFunc == nil {
+		panic("mock StartPublishJobAttempt function not set")
+	}
+	return c.StartPublishJobAttemptFunc(ctx, jobID)
+}
+
+func (c *CollectionsStore) UpdatePublishJobStep(ctx context.Context, jobID string, step publishing.JobStep) error {
+	if c.UpdatePublishJobStepFunc == nil {
+		panic("mock UpdatePublishJobStep function not set")
+	}
+	return c.UpdatePublishJobStepFunc(ctx, jobID, step)
+}
+
+func (c *CollectionsStore) FinishPublishJobAttempt(ctx context.Context, jobID string, status publishing.JobStatus, result collections.PublishJobResult) error {
+	if c.FinishPublishJobAttemptFunc == nil {
+		panic("mock FinishPublishJobAttempt function not set")
+	}
+	return c.FinishPublishJobAttemptFunc(ctx, jobID, status, result)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/store/discovercache"
+)
+
+type GetCachedDatasetsFunc func(ctx context.Context, dois []string) (map[string]discovercache.Entry, error)
+
+type PutCachedDatasetsFunc func(ctx context.Context, published map[string]dto.PublicDataset, unpublished map[string]dto.Tombstone) error
+
+type DiscoverCache struct {
+	GetCachedDatasetsFunc
+	PutCachedDatasetsFunc
+}
+
+func NewDiscoverCache() *DiscoverCache {
+	return &DiscoverCache{}
+}
+
+func (c *DiscoverCache) WithGetFunc(f GetCachedDatasetsFunc) *DiscoverCache {
+	c.GetCachedDatasetsFunc = f
+	return c
+}
+
+func (c *DiscoverCache) WithPutFunc(f PutCachedDatasetsFunc) *DiscoverCache {
+	c.PutCachedDatasetsFunc = f
+	return c
+}
+
+func (c *DiscoverCache) Get(ctx context.Context, dois []string) (map[string]discovercache.Entry, error) {
+	if c.GetCachedDatasetsFunc == nil {
+		panic("mock Get function not set")
+	}
+	return c.GetCachedDatasetsFunc(ctx, dois)
+}
+
+func (c *DiscoverCache) Put(ctx context.Context, published map[string]dto.PublicDataset, unpublished map[string]dto.Tombstone) error {
+	if c.PutCachedDatasetsFunc == nil {
+		panic("mock Put function not set")
+	}
+	return c.PutCachedDatasetsFunc(ctx, published, unpublished)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"github.com/pennsieve/collections-service/internal/api/service"
+)
+
+type ResolveDOIsFunc func(ctx context.Context, dois []string) (service.ResolveDOIsResponse, error)
+
+type ExternalDOI struct {
+	ResolveDOIsFunc
+}
+
+func NewExternalDOI() *ExternalDOI {
+	return &ExternalDOI{}
+}
+
+func (e *ExternalDOI) WithResolveDOIsFunc(f ResolveDOIsFunc) *ExternalDOI {
+	e.ResolveDOIsFunc = f
+	return e
+}
+
+func (e *ExternalDOI) ResolveDOIs(ctx context.Context, dois []string) (service.ResolveDOIsResponse, error) {
+	if e.ResolveDOIsFunc == nil {
+		panic("mock ResolveDOIs function not set")
+	}
+	return e.ResolveDOIsFunc(ctx, dois)
+}
@@ -0,0 +1,66 @@
+package mocks
+
+import (
+	"github.com/pennsieve/collections-service/internal/api/dto"
+	"github.com/pennsieve/collections-service/internal/api/service"
+	"github.com/pennsieve/collections-service/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+)
+
+// DOIResolverMux mocks a doi.org-style DOI resolver. It responds with CSL JSON for any DOI
+// added with WithExternalDatasets or WithCSLJSON and with a 404 for any other DOI.
+type DOIResolverMux struct {
+	*http.ServeMux
+	cslItems map[string]any
+}
+
+func NewDOIResolverMux(t require.TestingT) *DOIResolverMux {
+	m := &DOIResolverMux{
+		ServeMux: http.NewServeMux(),
+		cslItems: map[string]any{},
+	}
+	m.HandleFunc("GET /{doi...}", func(writer http.ResponseWriter, request *http.Request) {
+		test.Helper(t)
+		assert.Equal(t, service.CSLJSONContentType, request.Header.Get("accept"))
+		doi := request.PathValue("doi")
+		cslItem, found := m.cslItems[doi]
+		if !found {
+			respond(t, writer, nil, HTTPError{StatusCode: http.StatusNotFound})
+			return
+		}
+		writer.Header().Set("Content-Type", service.CSLJSONContentType)
+		WriteJSONHTTPResponse(t, writer, cslItem)
+	})
+	return m
+}
+
+// WithExternalDatasets adds the given datasets as resolvable DOIs. Creators are sent as literal CSL names.
+func (m *DOIResolverMux) WithExternalDatasets(datasets ...dto.ExternalDataset) *DOIResolverMux {
+	for _, dataset := range datasets {
+		var authors []map[string]string
+		for _, creator := range dataset.Creators {
+			authors = append(authors, map[string]string{"literal": creator})
+		}
+		cslItem := map[string]any{
+			"DOI":       dataset.DOI,
+			"type":      dataset.Type,
+			"title":     dataset.Title,
+			"URL":       dataset.URL,
+			"publisher": dataset.Publisher,
+			"author":    authors,
+		}
+		if dataset.PublicationYear > 0 {
+			cslItem["issued"] = map[string]any{"date-parts": [][]int{{dataset.PublicationYear}}}
+		}
+		m.cslItems[dataset.DOI] = cslItem
+	}
+	return m
+}
+
+// WithCSLJSON adds doi as a resolvable DOI whose CSL JSON representation is cslItem.
+func (m *DOIResolverMux) WithCSLJSON(doi string, cslItem any) *DOIResolverMux {
+	m.cslItems[doi] = cslItem
+	return m
+}
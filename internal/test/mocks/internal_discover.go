@@ -9,6 +9,7 @@ import (
 type PublishCollectionFunc func(ctx context.Context, collectionID int64, userRole role.Role, request service.PublishDOICollectionRequest) (service.PublishDOICollectionResponse, error)
 type FinalizeCollectionPublishFunc func(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request service.FinalizeDOICollectionPublishRequest) (service.FinalizeDOICollectionPublishResponse, error)
 type GetCollectionPublishStatusFunc func(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (service.DatasetPublishStatusResponse, error)
+type ReviseCollectionFunc func(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request service.ReviseDOICollectionRequest) (service.ReviseDOICollectionResponse, error)
 type UnpublishCollectionFunc func(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role) (service.DatasetPublishStatusResponse, error)
 
 type InternalDiscover struct {
@@ -16,6 +17,7 @@ type InternalDiscover struct {
 	FinalizeCollectionPublishFunc
 	GetCollectionPublishStatusFunc
 	UnpublishCollectionFunc
+	ReviseCollectionFunc
 }
 
 func NewInternalDiscover() *InternalDiscover {
@@ -42,6 +44,11 @@ func (i *InternalDiscover) WithUnpublishCollectionFunc(f UnpublishCollectionFunc
 	return i
 }
 
+func (i *InternalDiscover) WithReviseCollectionFunc(f ReviseCollectionFunc) *InternalDiscover {
+	i.ReviseCollectionFunc = f
+	return i
+}
+
 func (i *InternalDiscover) PublishCollection(ctx context.Context, collectionID int64, userRole role.Role, request service.PublishDOICollectionRequest) (service.PublishDOICollectionResponse, error) {
 	if i.PublishCollectionFunc == nil {
 		panic("mock PublishCollection function not set")
@@ -69,3 +76,10 @@ func (i *InternalDiscover) UnpublishCollection(ctx context.Context, collectionID
 	}
 	return i.UnpublishCollectionFunc(ctx, collectionID, collectionNodeID, userRole)
 }
+
+func (i *InternalDiscover) ReviseCollection(ctx context.Context, collectionID int64, collectionNodeID string, userRole role.Role, request service.ReviseDOICollectionRequest) (service.ReviseDOICollectionResponse, error) {
+	if i.ReviseCollectionFunc == nil {
+		panic("mock ReviseCollection function not set")
+	}
+	return i.ReviseCollectionFunc(ctx, collectionID, collectionNodeID, userRole, request)
+}
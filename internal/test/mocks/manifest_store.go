@@ -8,9 +8,13 @@ import (
 
 type SaveManifestFunc func(ctx context.Context, key string, manifest publishing.ManifestV5) (manifests.SaveManifestResponse, error)
 type DeleteManifestVersionFunc func(ctx context.Context, key string, s3VersionID string) error
+type GetManifestFunc func(ctx context.Context, key string) (publishing.ManifestV5, error)
+type GetManifestVersionsFunc func(ctx context.Context, key string) ([]manifests.ManifestVersion, error)
 type ManifestStore struct {
 	SaveManifestFunc
 	DeleteManifestVersionFunc
+	GetManifestFunc
+	GetManifestVersionsFunc
 }
 
 func NewManifestStore() *ManifestStore {
@@ -28,7 +32,21 @@ func (m *ManifestStore) DeleteManifestVersion(ctx context.Context, key string, s
 	if m.DeleteManifestVersionFunc == nil {
 		panic("mock DeleteManifest function not set")
 	}
-	return m.DeleteManifestVersion(ctx, key, s3VersionID)
+	return m.DeleteManifestVersionFunc(ctx, key, s3VersionID)
+}
+
+func (m *ManifestStore) GetManifest(ctx context.Context, key string) (publishing.ManifestV5, error) {
+	if m.GetManifestFunc == nil {
+		panic("mock GetManifest function not set")
+	}
+	return m.GetManifestFunc(ctx, key)
+}
+
+func (m *ManifestStore) GetManifestVersions(ctx context.Context, key string) ([]manifests.ManifestVersion, error) {
+	if m.GetManifestVersionsFunc == nil {
+		panic("mock GetManifestVersions function not set")
+	}
+	return m.GetManifestVersionsFunc(ctx, key)
 }
 
 func (m *ManifestStore) WithSaveManifestFunc(saveManifestFunc SaveManifestFunc) *ManifestStore {
@@ -40,3 +58,13 @@ func (m *ManifestStore) WithDeleteManifestVersionFunc(deleteManifestFunc DeleteM
 	m.DeleteManifestVersionFunc = deleteManifestFunc
 	return m
 }
+
+func (m *ManifestStore) WithGetManifestFunc(getManifestFunc GetManifestFunc) *ManifestStore {
+	m.GetManifestFunc = getManifestFunc
+	return m
+}
+
+func (m *ManifestStore) WithGetManifestVersionsFunc(getManifestVersionsFunc GetManifestVersionsFunc) *ManifestStore {
+	m.GetManifestVersionsFunc = getManifestVersionsFunc
+	return m
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
 	"github.com/pennsieve/collections-service/internal/shared/config"
 	"github.com/stretchr/testify/require"
 )
@@ -30,7 +31,7 @@ func NewPostgresDB(host string, port int, user string, password string) *Postgre
 	}
 }
 
-func (db *PostgresDB) Connect(ctx context.Context, databaseName string) (*pgx.Conn, error) {
+func (db *PostgresDB) Connect(ctx context.Context, databaseName string) (postgres.Conn, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
 		db.host, db.port, db.user, db.password, databaseName,
 	)
@@ -38,7 +39,14 @@ func (db *PostgresDB) Connect(ctx context.Context, databaseName string) (*pgx.Co
 	return pgx.Connect(ctx, dsn)
 }
 
-func CloseConnection(ctx context.Context, t require.TestingT, conn *pgx.Conn) {
+// ConnString returns a connection string without a dbname suitable for [postgres.NewPool].
+func (db *PostgresDB) ConnString() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s",
+		db.host, db.port, db.user, db.password,
+	)
+}
+
+func CloseConnection(ctx context.Context, t require.TestingT, conn postgres.Conn) {
 	Helper(t)
 	require.NoError(t, conn.Close(ctx))
 }
@@ -64,4 +72,7 @@ var PostgresDBEnvironmentSettings = config.PostgresDBEnvironmentSettings{
 	User:                config.NewEnvironmentSettingWithDefault(config.PostgresUserKey, "postgres"),
 	Password:            config.NewEnvironmentSettingWithDefault(config.PostgresPasswordKey, "password"),
 	CollectionsDatabase: config.NewEnvironmentSettingWithDefault(config.PostgresCollectionsDatabaseKey, "postgres"),
+	MaxConns:            config.NewEnvironmentSettingWithDefault(config.PostgresMaxConnsKey, config.DefaultPostgresMaxConns),
+	MaxConnLifetime:     config.NewEnvironmentSettingWithDefault(config.PostgresMaxConnLifetimeMinutesKey, config.DefaultPostgresMaxConnLifetimeMinutes),
+	MaxConnIdleTime:     config.NewEnvironmentSettingWithDefault(config.PostgresMaxConnIdleMinutesKey, config.DefaultPostgresMaxConnIdleMinutes),
 }
@@ -0,0 +1,43 @@
+package trashpurge
+
+import (
+	"fmt"
+	sharedconfig "github.com/pennsieve/collections-service/internal/shared/config"
+	"time"
+)
+
+const EnvironmentKey = "ENV"
+
+// RetentionDaysKey is the env var holding the number of days a deleted collection stays in the trash before it is purged.
+const RetentionDaysKey = "TRASH_RETENTION_DAYS"
+const DefaultRetentionDays = "30"
+
+type Config struct {
+	Environment string
+	PostgresDB  sharedconfig.PostgresDBConfig
+	// Retention is how long a deleted collection stays in the trash before it is purged.
+	Retention time.Duration
+}
+
+func LoadConfig() (Config, error) {
+	environment, err := sharedconfig.NewEnvironmentSetting(EnvironmentKey).Get()
+	if err != nil {
+		return Config{}, err
+	}
+	postgresConfig, err := sharedconfig.NewPostgresDBConfig().Load()
+	if err != nil {
+		return Config{}, fmt.Errorf("error loading PostgresDB config: %w", err)
+	}
+	retentionDays, err := sharedconfig.NewEnvironmentSettingWithDefault(RetentionDaysKey, DefaultRetentionDays).GetInt()
+	if err != nil {
+		return Config{}, err
+	}
+	if retentionDays < 0 {
+		return Config{}, fmt.Errorf("'%s' cannot be negative: %d", RetentionDaysKey, retentionDays)
+	}
+	return Config{
+		Environment: environment,
+		PostgresDB:  postgresConfig,
+		Retention:   time.Duration(retentionDays) * 24 * time.Hour,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package trashpurge
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/pennsieve/collections-service/internal/api/store/collections"
+	"github.com/pennsieve/collections-service/internal/shared/clients/postgres"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"log"
+	"log/slog"
+	"time"
+)
+
+// LambdaHandler is triggered by a scheduled EventBridge rule.
+type LambdaHandler func(ctx context.Context, event events.EventBridgeEvent) error
+
+func Handler() LambdaHandler {
+	config, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load trash purge config: %v", err)
+	}
+	awsCfg, err := awsConfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to load AWS config: %v", err)
+	}
+	pgCfg := config.PostgresDB
+	db := postgres.NewRDSProxyPool(awsCfg, pgCfg.Host, pgCfg.Port, pgCfg.User).
+		WithLimits(postgres.PoolLimits{
+			MaxConns:        pgCfg.MaxConns,
+			MaxConnLifetime: pgCfg.MaxConnLifetime,
+			MaxConnIdleTime: pgCfg.MaxConnIdleTime,
+		})
+	store := collections.NewPostgresStore(db, pgCfg.CollectionsDatabase, logging.Default)
+
+	return PurgeHandler(store, config.Retention, logging.Default)
+}
+
+// PurgeHandler permanently deletes collections that have been in the trash for longer than retention.
+func PurgeHandler(store collections.Store, retention time.Duration, logger *slog.Logger) LambdaHandler {
+	return func(ctx context.Context, event events.EventBridgeEvent) error {
+		logger := logger.With(slog.String("eventId", event.ID))
+		deletedBefore := time.Now().UTC().Add(-retention)
+		purged, err := store.PurgeDeletedCollections(ctx, deletedBefore)
+		if err != nil {
+			logger.Error("error purging deleted collections", slog.Any("error", err))
+			return fmt.Errorf("error purging collections deleted before %s: %w", deletedBefore, err)
+		}
+		logger.Info("purged deleted collections",
+			slog.Int64("count", purged),
+			slog.Time("deletedBefore", deletedBefore))
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package trashpurge
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	sharedconfig "github.com/pennsieve/collections-service/internal/shared/config"
+	"github.com/pennsieve/collections-service/internal/shared/logging"
+	"github.com/pennsieve/collections-service/internal/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPurgeHandler(t *testing.T) {
+	ctx := context.Background()
+	retention := 7 * 24 * time.Hour
+
+	var actualDeletedBefore time.Time
+	store := mocks.NewCollectionsStore().WithPurgeDeletedCollectionsFunc(func(_ context.Context, deletedBefore time.Time) (int64, error) {
+		actualDeletedBefore = deletedBefore
+		return 3, nil
+	})
+
+	expectedDeletedBefore := time.Now().UTC().Add(-retention)
+	require.NoError(t, PurgeHandler(store, retention, logging.Default)(ctx, events.EventBridgeEvent{ID: uuid.NewString()}))
+
+	assert.WithinDuration(t, expectedDeletedBefore, actualDeletedBefore, time.Minute)
+}
+
+func TestPurgeHandlerError(t *testing.T) {
+	ctx := context.Background()
+
+	storeErr := errors.New(uuid.NewString())
+	store := mocks.NewCollectionsStore().WithPurgeDeletedCollectionsFunc(func(_ context.Context, _ time.Time) (int64, error) {
+		return 0, storeErr
+	})
+
+	err := PurgeHandler(store, time.Hour, logging.Default)(ctx, events.EventBridgeEvent{ID: uuid.NewString()})
+	assert.ErrorIs(t, err, storeErr)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv(EnvironmentKey, "test")
+	t.Setenv(sharedconfig.PostgresHostKey, "localhost")
+	t.Setenv(sharedconfig.PostgresUserKey, uuid.NewString())
+	t.Setenv(sharedconfig.PostgresCollectionsDatabaseKey, uuid.NewString())
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, config.Retention)
+
+	t.Setenv(RetentionDaysKey, "0")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Zero(t, config.Retention)
+
+	t.Setenv(RetentionDaysKey, "-1")
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, RetentionDaysKey)
+}
@@ -20,3 +20,51 @@ resource "aws_cloudwatch_log_group" "collections_service_gateway_log_group" {
 
   retention_in_days = 30
 }
+
+// Create log group for collections-service trash purge Lambda.
+resource "aws_cloudwatch_log_group" "collections_service_trash_purge_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.collections_service_trash_purge_lambda.function_name}"
+  retention_in_days = 30
+  tags              = local.common_tags
+}
+
+// Send logs from collections-service trash purge Lambda to Datadog
+resource "aws_cloudwatch_log_subscription_filter" "collections_service_trash_purge_lambda_datadog_subscription" {
+  name            = "${aws_cloudwatch_log_group.collections_service_trash_purge_lambda_log_group.name}-subscription"
+  log_group_name  = aws_cloudwatch_log_group.collections_service_trash_purge_lambda_log_group.name
+  filter_pattern  = ""
+  destination_arn = data.terraform_remote_state.region.outputs.datadog_delivery_stream_arn
+  role_arn        = data.terraform_remote_state.region.outputs.cw_logs_to_datadog_logs_firehose_role_arn
+}
+
+// Create log group for collections-service publish reconciler Lambda.
+resource "aws_cloudwatch_log_group" "collections_service_publish_reconciler_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.collections_service_publish_reconciler_lambda.function_name}"
+  retention_in_days = 30
+  tags              = local.common_tags
+}
+
+// Send logs from collections-service publish reconciler Lambda to Datadog
+resource "aws_cloudwatch_log_subscription_filter" "collections_service_publish_reconciler_lambda_datadog_subscription" {
+  name            = "${aws_cloudwatch_log_group.collections_service_publish_reconciler_lambda_log_group.name}-subscription"
+  log_group_name  = aws_cloudwatch_log_group.collections_service_publish_reconciler_lambda_log_group.name
+  filter_pattern  = ""
+  destination_arn = data.terraform_remote_state.region.outputs.datadog_delivery_stream_arn
+  role_arn        = data.terraform_remote_state.region.outputs.cw_logs_to_datadog_logs_firehose_role_arn
+}
+
+// Create log group for collections-service publish worker Lambda.
+resource "aws_cloudwatch_log_group" "collections_service_publish_worker_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.collections_service_publish_worker_lambda.function_name}"
+  retention_in_days = 30
+  tags              = local.common_tags
+}
+
+// Send logs from collections-service publish worker Lambda to Datadog
+resource "aws_cloudwatch_log_subscription_filter" "collections_service_publish_worker_lambda_datadog_subscription" {
+  name            = "${aws_cloudwatch_log_group.collections_service_publish_worker_lambda_log_group.name}-subscription"
+  log_group_name  = aws_cloudwatch_log_group.collections_service_publish_worker_lambda_log_group.name
+  filter_pattern  = ""
+  destination_arn = data.terraform_remote_state.region.outputs.datadog_delivery_stream_arn
+  role_arn        = data.terraform_remote_state.region.outputs.cw_logs_to_datadog_logs_firehose_role_arn
+}
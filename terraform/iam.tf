@@ -103,6 +103,315 @@ data "aws_iam_policy_document" "collections_service_api_iam_policy_document" {
     sid    = "S3BucketAccess"
     effect = "Allow"
     actions = [
+      "s3:GetObject",
+      "s3:PutObject",
+      "s3:DeleteObject",
+      "s3:DeleteObjectVersion",
+      "s3:ListBucket",
+    ]
+
+    resources = [
+      data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_arn,
+      "${data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_arn}/*",
+    ]
+  }
+}
+
+####################### COLLECTIONS SERVICE TRASH PURGE LAMBDA POLICY #######################
+
+resource "aws_iam_role" "collections_service_trash_purge_lambda_role" {
+  name = "${var.environment_name}-${var.service_name}-trash-purge-lambda-role-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+
+  assume_role_policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "lambda.amazonaws.com"
+      },
+      "Effect": "Allow",
+      "Sid": ""
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy_attachment" "collections_service_trash_purge_lambda_iam_policy_attachment" {
+  role       = aws_iam_role.collections_service_trash_purge_lambda_role.name
+  policy_arn = aws_iam_policy.collections_service_trash_purge_lambda_iam_policy.arn
+}
+
+resource "aws_iam_policy" "collections_service_trash_purge_lambda_iam_policy" {
+  name   = "${var.environment_name}-${var.service_name}-trash-purge-lambda-iam-policy-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  path   = "/"
+  policy = data.aws_iam_policy_document.collections_service_trash_purge_iam_policy_document.json
+}
+
+data "aws_iam_policy_document" "collections_service_trash_purge_iam_policy_document" {
+
+  statement {
+    sid    = "CollectionsServiceTrashPurgeLambdaLogsPermissions"
+    effect = "Allow"
+    actions = [
+      "logs:CreateLogGroup",
+      "logs:CreateLogStream",
+      "logs:PutDestination",
+      "logs:PutLogEvents",
+      "logs:DescribeLogStreams"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServiceTrashPurgeLambdaEC2Permissions"
+    effect = "Allow"
+    actions = [
+      "ec2:CreateNetworkInterface",
+      "ec2:DescribeNetworkInterfaces",
+      "ec2:DeleteNetworkInterface",
+      "ec2:AssignPrivateIpAddresses",
+      "ec2:UnassignPrivateIpAddresses"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServiceTrashPurgeRDSPermissions"
+    effect = "Allow"
+
+    actions = [
+      "rds-db:connect"
+    ]
+
+    resources = [local.rds_db_connect_arn]
+  }
+}
+
+####################### COLLECTIONS SERVICE PUBLISH RECONCILER LAMBDA POLICY #######################
+
+resource "aws_iam_role" "collections_service_publish_reconciler_lambda_role" {
+  name = "${var.environment_name}-${var.service_name}-publish-reconciler-lambda-role-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+
+  assume_role_policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "lambda.amazonaws.com"
+      },
+      "Effect": "Allow",
+      "Sid": ""
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy_attachment" "collections_service_publish_reconciler_lambda_iam_policy_attachment" {
+  role       = aws_iam_role.collections_service_publish_reconciler_lambda_role.name
+  policy_arn = aws_iam_policy.collections_service_publish_reconciler_lambda_iam_policy.arn
+}
+
+resource "aws_iam_policy" "collections_service_publish_reconciler_lambda_iam_policy" {
+  name   = "${var.environment_name}-${var.service_name}-publish-reconciler-lambda-iam-policy-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  path   = "/"
+  policy = data.aws_iam_policy_document.collections_service_publish_reconciler_iam_policy_document.json
+}
+
+data "aws_iam_policy_document" "collections_service_publish_reconciler_iam_policy_document" {
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerLambdaLogsPermissions"
+    effect = "Allow"
+    actions = [
+      "logs:CreateLogGroup",
+      "logs:CreateLogStream",
+      "logs:PutDestination",
+      "logs:PutLogEvents",
+      "logs:DescribeLogStreams"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerLambdaEC2Permissions"
+    effect = "Allow"
+    actions = [
+      "ec2:CreateNetworkInterface",
+      "ec2:DescribeNetworkInterfaces",
+      "ec2:DeleteNetworkInterface",
+      "ec2:AssignPrivateIpAddresses",
+      "ec2:UnassignPrivateIpAddresses"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerRDSPermissions"
+    effect = "Allow"
+
+    actions = [
+      "rds-db:connect"
+    ]
+
+    resources = [local.rds_db_connect_arn]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerSecretsManagerPermissions"
+    effect = "Allow"
+
+    actions = [
+      "kms:Decrypt",
+      "secretsmanager:GetSecretValue",
+    ]
+
+    resources = [
+      data.aws_kms_key.ssm_kms_key.arn,
+    ]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerSSMPermissions"
+    effect = "Allow"
+
+    actions = [
+      "ssm:GetParameter",
+      "ssm:GetParameters",
+      "ssm:GetParametersByPath",
+    ]
+
+    resources = [
+      "arn:aws:ssm:${data.aws_region.current_region.name}:${data.aws_caller_identity.current.account_id}:parameter/${var.environment_name}/${var.service_name}/*"
+    ]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishReconcilerS3BucketAccess"
+    effect = "Allow"
+    actions = [
+      "s3:ListBucketVersions",
+      "s3:DeleteObjectVersion",
+    ]
+
+    resources = [
+      data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_arn,
+      "${data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_arn}/*",
+    ]
+  }
+}
+
+####################### COLLECTIONS SERVICE PUBLISH WORKER LAMBDA POLICY #######################
+
+resource "aws_iam_role" "collections_service_publish_worker_lambda_role" {
+  name = "${var.environment_name}-${var.service_name}-publish-worker-lambda-role-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+
+  assume_role_policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Action": "sts:AssumeRole",
+      "Principal": {
+        "Service": "lambda.amazonaws.com"
+      },
+      "Effect": "Allow",
+      "Sid": ""
+    }
+  ]
+}
+EOF
+}
+
+resource "aws_iam_role_policy_attachment" "collections_service_publish_worker_lambda_iam_policy_attachment" {
+  role       = aws_iam_role.collections_service_publish_worker_lambda_role.name
+  policy_arn = aws_iam_policy.collections_service_publish_worker_lambda_iam_policy.arn
+}
+
+resource "aws_iam_policy" "collections_service_publish_worker_lambda_iam_policy" {
+  name   = "${var.environment_name}-${var.service_name}-publish-worker-lambda-iam-policy-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  path   = "/"
+  policy = data.aws_iam_policy_document.collections_service_publish_worker_iam_policy_document.json
+}
+
+data "aws_iam_policy_document" "collections_service_publish_worker_iam_policy_document" {
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerLambdaLogsPermissions"
+    effect = "Allow"
+    actions = [
+      "logs:CreateLogGroup",
+      "logs:CreateLogStream",
+      "logs:PutDestination",
+      "logs:PutLogEvents",
+      "logs:DescribeLogStreams"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerLambdaEC2Permissions"
+    effect = "Allow"
+    actions = [
+      "ec2:CreateNetworkInterface",
+      "ec2:DescribeNetworkInterfaces",
+      "ec2:DeleteNetworkInterface",
+      "ec2:AssignPrivateIpAddresses",
+      "ec2:UnassignPrivateIpAddresses"
+    ]
+    resources = ["*"]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerRDSPermissions"
+    effect = "Allow"
+
+    actions = [
+      "rds-db:connect"
+    ]
+
+    resources = [local.rds_db_connect_arn]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerSecretsManagerPermissions"
+    effect = "Allow"
+
+    actions = [
+      "kms:Decrypt",
+      "secretsmanager:GetSecretValue",
+    ]
+
+    resources = [
+      data.aws_kms_key.ssm_kms_key.arn,
+    ]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerSSMPermissions"
+    effect = "Allow"
+
+    actions = [
+      "ssm:GetParameter",
+      "ssm:GetParameters",
+      "ssm:GetParametersByPath",
+    ]
+
+    resources = [
+      "arn:aws:ssm:${data.aws_region.current_region.name}:${data.aws_caller_identity.current.account_id}:parameter/${var.environment_name}/${var.service_name}/*"
+    ]
+  }
+
+  statement {
+    sid    = "CollectionsServicePublishWorkerS3BucketAccess"
+    effect = "Allow"
+    actions = [
+      "s3:GetObject",
       "s3:PutObject",
       "s3:DeleteObject",
       "s3:DeleteObjectVersion",
@@ -22,16 +22,201 @@ resource "aws_lambda_function" "collections_service_api_lambda" {
       ENV    = var.environment_name
       REGION = var.aws_region
 
-      POSTGRES_HOST                 = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint,
-      POSTGRES_USER                 = var.api_postgres_user,
-      POSTGRES_COLLECTIONS_DATABASE = var.pennsieve_postgres_database,
-      DISCOVER_SERVICE_HOST         = local.discover_service_host,
-      DOI_SERVICE_HOST              = local.doi_service_host,
-      PENNSIEVE_DOI_PREFIX          = local.pennsieve_doi_prefix,
-      COLLECTIONS_ID_SPACE_ID       = local.collections_id_space_id,
-      COLLECTIONS_ID_SPACE_NAME     = local.collections_id_space_name,
-      PUBLISH_BUCKET                = data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_id,
-      LOG_LEVEL                     = local.log_level
+      POSTGRES_HOST                      = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint,
+      POSTGRES_USER                      = var.api_postgres_user,
+      POSTGRES_COLLECTIONS_DATABASE      = var.pennsieve_postgres_database,
+      POSTGRES_MAX_CONNS                 = var.postgres_max_conns,
+      POSTGRES_MAX_CONN_LIFETIME_MINUTES = var.postgres_max_conn_lifetime_minutes,
+      POSTGRES_MAX_CONN_IDLE_MINUTES     = var.postgres_max_conn_idle_minutes,
+      DISCOVER_SERVICE_HOST              = local.discover_service_host,
+      DOI_SERVICE_HOST                   = local.doi_service_host,
+      PENNSIEVE_DOI_PREFIX               = local.pennsieve_doi_prefix,
+      COLLECTIONS_ID_SPACE_ID            = local.collections_id_space_id,
+      COLLECTIONS_ID_SPACE_NAME          = local.collections_id_space_name,
+      PUBLISH_BUCKET                     = data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_id,
+      PUBLISH_TIMEOUT_MINUTES            = var.publish_timeout_minutes,
+      DISCOVER_CACHE_TTL_MINUTES         = var.discover_cache_ttl_minutes,
+      DISCOVER_CACHE_MODE                = var.discover_cache_mode,
+      LOG_LEVEL                          = local.log_level
     }
   }
 }
+
+###################### COLLECTIONS SERVICE TRASH PURGE LAMBDA #####################
+
+resource "aws_lambda_function" "collections_service_trash_purge_lambda" {
+  description   = "Lambda function for purging deleted dataset collections from the trash"
+  function_name = "${var.environment_name}-${var.service_name}-trash-purge-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler       = "bootstrap"
+  runtime       = "provided.al2023"
+  architectures = ["arm64"]
+  role          = aws_iam_role.collections_service_trash_purge_lambda_role.arn
+  timeout       = 300
+  memory_size   = 128
+  s3_bucket     = var.lambda_bucket
+  s3_key        = "${var.service_name}/${var.service_name}-trashpurge-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = {
+      ENV    = var.environment_name
+      REGION = var.aws_region
+
+      POSTGRES_HOST                      = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint,
+      POSTGRES_USER                      = var.api_postgres_user,
+      POSTGRES_COLLECTIONS_DATABASE      = var.pennsieve_postgres_database,
+      POSTGRES_MAX_CONNS                 = var.postgres_max_conns,
+      POSTGRES_MAX_CONN_LIFETIME_MINUTES = var.postgres_max_conn_lifetime_minutes,
+      POSTGRES_MAX_CONN_IDLE_MINUTES     = var.postgres_max_conn_idle_minutes,
+      TRASH_RETENTION_DAYS               = var.trash_retention_days,
+      LOG_LEVEL                          = local.log_level
+    }
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "collections_service_trash_purge_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-trash-purge-schedule-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the collections-service trash purge Lambda daily"
+  schedule_expression = "rate(1 day)"
+}
+
+resource "aws_cloudwatch_event_target" "collections_service_trash_purge_target" {
+  rule = aws_cloudwatch_event_rule.collections_service_trash_purge_schedule.name
+  arn  = aws_lambda_function.collections_service_trash_purge_lambda.arn
+}
+
+resource "aws_lambda_permission" "collections_service_trash_purge_eventbridge_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.collections_service_trash_purge_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.collections_service_trash_purge_schedule.arn
+}
+
+###################### COLLECTIONS SERVICE PUBLISH RECONCILER LAMBDA #####################
+
+resource "aws_lambda_function" "collections_service_publish_reconciler_lambda" {
+  description   = "Lambda function for finishing dataset collection publishes that were stopped before they finished"
+  function_name = "${var.environment_name}-${var.service_name}-publish-reconciler-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler       = "bootstrap"
+  runtime       = "provided.al2023"
+  architectures = ["arm64"]
+  role          = aws_iam_role.collections_service_publish_reconciler_lambda_role.arn
+  timeout       = 300
+  memory_size   = 128
+  s3_bucket     = var.lambda_bucket
+  s3_key        = "${var.service_name}/${var.service_name}-publishreconciler-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = {
+      ENV    = var.environment_name
+      REGION = var.aws_region
+
+      POSTGRES_HOST                      = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint,
+      POSTGRES_USER                      = var.api_postgres_user,
+      POSTGRES_COLLECTIONS_DATABASE      = var.pennsieve_postgres_database,
+      POSTGRES_MAX_CONNS                 = var.postgres_max_conns,
+      POSTGRES_MAX_CONN_LIFETIME_MINUTES = var.postgres_max_conn_lifetime_minutes,
+      POSTGRES_MAX_CONN_IDLE_MINUTES     = var.postgres_max_conn_idle_minutes,
+      DISCOVER_SERVICE_HOST              = local.discover_service_host,
+      DOI_SERVICE_HOST                   = local.doi_service_host,
+      PENNSIEVE_DOI_PREFIX               = local.pennsieve_doi_prefix,
+      COLLECTIONS_ID_SPACE_ID            = local.collections_id_space_id,
+      COLLECTIONS_ID_SPACE_NAME          = local.collections_id_space_name,
+      PUBLISH_BUCKET                     = data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_id,
+      PUBLISH_TIMEOUT_MINUTES            = var.publish_timeout_minutes,
+      LOG_LEVEL                          = local.log_level
+    }
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "collections_service_publish_reconciler_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-publish-reconciler-schedule-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the collections-service publish reconciler Lambda every 15 minutes"
+  schedule_expression = "rate(15 minutes)"
+}
+
+resource "aws_cloudwatch_event_target" "collections_service_publish_reconciler_target" {
+  rule = aws_cloudwatch_event_rule.collections_service_publish_reconciler_schedule.name
+  arn  = aws_lambda_function.collections_service_publish_reconciler_lambda.arn
+}
+
+resource "aws_lambda_permission" "collections_service_publish_reconciler_eventbridge_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.collections_service_publish_reconciler_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.collections_service_publish_reconciler_schedule.arn
+}
+
+###################### COLLECTIONS SERVICE PUBLISH WORKER LAMBDA #####################
+
+resource "aws_lambda_function" "collections_service_publish_worker_lambda" {
+  description   = "Lambda function for running the queued publish jobs of dataset collections"
+  function_name = "${var.environment_name}-${var.service_name}-publish-worker-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler       = "bootstrap"
+  runtime       = "provided.al2023"
+  architectures = ["arm64"]
+  role          = aws_iam_role.collections_service_publish_worker_lambda_role.arn
+  // must be shorter than publishworker.VisibilityTimeout
+  timeout       = 300
+  memory_size   = 128
+  s3_bucket     = var.lambda_bucket
+  s3_key        = "${var.service_name}/${var.service_name}-publishworker-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = {
+      ENV    = var.environment_name
+      REGION = var.aws_region
+
+      POSTGRES_HOST                      = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint,
+      POSTGRES_USER                      = var.api_postgres_user,
+      POSTGRES_COLLECTIONS_DATABASE      = var.pennsieve_postgres_database,
+      POSTGRES_MAX_CONNS                 = var.postgres_max_conns,
+      POSTGRES_MAX_CONN_LIFETIME_MINUTES = var.postgres_max_conn_lifetime_minutes,
+      POSTGRES_MAX_CONN_IDLE_MINUTES     = var.postgres_max_conn_idle_minutes,
+      DISCOVER_SERVICE_HOST              = local.discover_service_host,
+      DOI_SERVICE_HOST                   = local.doi_service_host,
+      PENNSIEVE_DOI_PREFIX               = local.pennsieve_doi_prefix,
+      COLLECTIONS_ID_SPACE_ID            = local.collections_id_space_id,
+      COLLECTIONS_ID_SPACE_NAME          = local.collections_id_space_name,
+      PUBLISH_BUCKET                     = data.terraform_remote_state.platform_infrastructure.outputs.discover_publish50_bucket_id,
+      PUBLISH_TIMEOUT_MINUTES            = var.publish_timeout_minutes,
+      PUBLISH_JOB_MAX_ATTEMPTS           = var.publish_job_max_attempts,
+      LOG_LEVEL                          = local.log_level
+    }
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "collections_service_publish_worker_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-publish-worker-schedule-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the collections-service publish worker Lambda every minute"
+  schedule_expression = "rate(1 minute)"
+}
+
+resource "aws_cloudwatch_event_target" "collections_service_publish_worker_target" {
+  rule = aws_cloudwatch_event_rule.collections_service_publish_worker_schedule.name
+  arn  = aws_lambda_function.collections_service_publish_worker_lambda.arn
+}
+
+resource "aws_lambda_permission" "collections_service_publish_worker_eventbridge_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.collections_service_publish_worker_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.collections_service_publish_worker_schedule.arn
+}
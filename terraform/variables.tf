@@ -28,6 +28,40 @@ variable "pennsieve_postgres_database" {
   default = "pennsieve_postgres"
 }
 
+// Per database, per Lambda instance. Times the Lambda concurrency, should stay under the RDS Proxy connection limit.
+variable "postgres_max_conns" {
+  default = "4"
+}
+
+variable "postgres_max_conn_lifetime_minutes" {
+  default = "30"
+}
+
+variable "postgres_max_conn_idle_minutes" {
+  default = "5"
+}
+
+variable "trash_retention_days" {
+  default = "30"
+}
+
+// Should be longer than the API Lambda timeout
+variable "publish_timeout_minutes" {
+  default = "30"
+}
+
+variable "discover_cache_ttl_minutes" {
+  default = "15"
+}
+
+variable "discover_cache_mode" {
+  default = "refresh"
+}
+
+variable "publish_job_max_attempts" {
+  default = "3"
+}
+
 locals {
   common_tags = {
     aws_account      = var.aws_account